@@ -0,0 +1,241 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+)
+
+// Blend is a blending way of the source color and the destination color.
+//
+// The final color is calculated like this:
+//
+//	c_out = BlendOperationRGB((c_src × BlendFactorSourceRGB), (c_dst × BlendFactorDestinationRGB))
+//	α_out = BlendOperationAlpha((α_src × BlendFactorSourceAlpha), (α_dst × BlendFactorDestinationAlpha))
+//
+// where c_src, c_dst and c_out represent alpha-premultiplied RGB values of source, destination and output respectively.
+// α_src and α_dst represent alpha values of source and destination respectively.
+//
+// The default (zero) value is regular alpha blending.
+type Blend struct {
+	// BlendFactorSourceRGB is a factor for the source RGB values.
+	BlendFactorSourceRGB BlendFactor
+
+	// BlendFactorSourceAlpha is a factor for the source alpha values.
+	BlendFactorSourceAlpha BlendFactor
+
+	// BlendFactorDestinationRGB is a factor for the destination RGB values.
+	BlendFactorDestinationRGB BlendFactor
+
+	// BlendFactorDestinationAlpha is a factor for the destination alpha values.
+	BlendFactorDestinationAlpha BlendFactor
+
+	// BlendOperationRGB is an operation for source RGB values and destination RGB values.
+	BlendOperationRGB BlendOperation
+
+	// BlendOperationAlpha is an operation for source alpha values and destination alpha values.
+	BlendOperationAlpha BlendOperation
+}
+
+func (b Blend) internalBlend() graphicsdriver.Blend {
+	return graphicsdriver.Blend{
+		BlendFactorSourceRGB:        b.BlendFactorSourceRGB.internalBlendFactor(true),
+		BlendFactorSourceAlpha:      b.BlendFactorSourceAlpha.internalBlendFactor(true),
+		BlendFactorDestinationRGB:   b.BlendFactorDestinationRGB.internalBlendFactor(false),
+		BlendFactorDestinationAlpha: b.BlendFactorDestinationAlpha.internalBlendFactor(false),
+		BlendOperationRGB:           b.BlendOperationRGB.internalBlendOperation(),
+		BlendOperationAlpha:         b.BlendOperationAlpha.internalBlendOperation(),
+	}
+}
+
+// BlendFactor is a factor for source and destination color values.
+type BlendFactor byte
+
+const (
+	// BlendFactorDefault is the default factor value.
+	// The actual value depends on which source or destination this value is used.
+	// For a source, BlendFactorDefault is BlendFactorOne.
+	// For a destination, BlendFactorDefault is BlendFactorOneMinusSourceAlpha.
+	BlendFactorDefault BlendFactor = iota
+
+	BlendFactorZero
+	BlendFactorOne
+	BlendFactorSourceColor
+	BlendFactorOneMinusSourceColor
+	BlendFactorSourceAlpha
+	BlendFactorOneMinusSourceAlpha
+	BlendFactorDestinationColor
+	BlendFactorOneMinusDestinationColor
+	BlendFactorDestinationAlpha
+	BlendFactorOneMinusDestinationAlpha
+)
+
+func (b BlendFactor) internalBlendFactor(source bool) graphicsdriver.BlendFactor {
+	switch b {
+	case BlendFactorDefault:
+		if source {
+			return graphicsdriver.BlendFactorOne
+		}
+		return graphicsdriver.BlendFactorOneMinusSourceAlpha
+	case BlendFactorZero:
+		return graphicsdriver.BlendFactorZero
+	case BlendFactorOne:
+		return graphicsdriver.BlendFactorOne
+	case BlendFactorSourceColor:
+		return graphicsdriver.BlendFactorSourceColor
+	case BlendFactorOneMinusSourceColor:
+		return graphicsdriver.BlendFactorOneMinusSourceColor
+	case BlendFactorSourceAlpha:
+		return graphicsdriver.BlendFactorSourceAlpha
+	case BlendFactorOneMinusSourceAlpha:
+		return graphicsdriver.BlendFactorOneMinusSourceAlpha
+	case BlendFactorDestinationColor:
+		return graphicsdriver.BlendFactorDestinationColor
+	case BlendFactorOneMinusDestinationColor:
+		return graphicsdriver.BlendFactorOneMinusDestinationColor
+	case BlendFactorDestinationAlpha:
+		return graphicsdriver.BlendFactorDestinationAlpha
+	case BlendFactorOneMinusDestinationAlpha:
+		return graphicsdriver.BlendFactorOneMinusDestinationAlpha
+	default:
+		panic(fmt.Sprintf("ebiten: invalid blend factor: %d", b))
+	}
+}
+
+// BlendOperation is an operation for source and destination color values.
+type BlendOperation byte
+
+const (
+	// BlendOperationAdd represents adding the source and destination color.
+	// c_out = (c_src × BlendFactorSourceRGB) + (c_dst × BlendFactorDestinationRGB)
+	// α_out = (α_src × BlendFactorSourceAlpha) + (α_dst × BlendFactorDestinationAlpha)
+	BlendOperationAdd BlendOperation = iota
+
+	// BlendOperationSubtract represents subtracting the source and destination color.
+	// c_out = (c_src × BlendFactorSourceRGB) - (c_dst × BlendFactorDestinationRGB)
+	// α_out = (α_src × BlendFactorSourceAlpha) - (α_dst × BlendFactorDestinationAlpha)
+	BlendOperationSubtract
+
+	// BlendOperationReverseSubtract represents subtracting the source and destination color in a reversed order.
+	// c_out = (c_dst × BlendFactorDestinationRGB) - (c_src × BlendFactorSourceRGB)
+	// α_out = (α_dst × BlendFactorDestinationAlpha) - (α_src × BlendFactorSourceAlpha)
+	BlendOperationReverseSubtract
+
+	// BlendOperationMin represents the minimum of the source and destination color.
+	// The factors are ignored.
+	// c_out = min(c_src, c_dst)
+	// α_out = min(α_src, α_dst)
+	BlendOperationMin
+
+	// BlendOperationMax represents the maximum of the source and destination color.
+	// The factors are ignored.
+	// c_out = max(c_src, c_dst)
+	// α_out = max(α_src, α_dst)
+	BlendOperationMax
+)
+
+func (b BlendOperation) internalBlendOperation() graphicsdriver.BlendOperation {
+	switch b {
+	case BlendOperationAdd:
+		return graphicsdriver.BlendOperationAdd
+	case BlendOperationSubtract:
+		return graphicsdriver.BlendOperationSubtract
+	case BlendOperationReverseSubtract:
+		return graphicsdriver.BlendOperationReverseSubtract
+	case BlendOperationMin:
+		return graphicsdriver.BlendOperationMin
+	case BlendOperationMax:
+		return graphicsdriver.BlendOperationMax
+	default:
+		panic(fmt.Sprintf("ebiten: invalid blend operation: %d", b))
+	}
+}
+
+func blendWithFactors(src, dst BlendFactor) Blend {
+	return Blend{
+		BlendFactorSourceRGB:        src,
+		BlendFactorSourceAlpha:      src,
+		BlendFactorDestinationRGB:   dst,
+		BlendFactorDestinationAlpha: dst,
+		BlendOperationRGB:           BlendOperationAdd,
+		BlendOperationAlpha:         BlendOperationAdd,
+	}
+}
+
+// The predefined blends corresponding to the composite modes.
+// See the CompositeMode constants for the details.
+var (
+	BlendSourceOver      = blendWithFactors(BlendFactorOne, BlendFactorOneMinusSourceAlpha)
+	BlendClear           = blendWithFactors(BlendFactorZero, BlendFactorZero)
+	BlendCopy            = blendWithFactors(BlendFactorOne, BlendFactorZero)
+	BlendDestination     = blendWithFactors(BlendFactorZero, BlendFactorOne)
+	BlendDestinationOver = blendWithFactors(BlendFactorOneMinusDestinationAlpha, BlendFactorOne)
+	BlendSourceIn        = blendWithFactors(BlendFactorDestinationAlpha, BlendFactorZero)
+	BlendDestinationIn   = blendWithFactors(BlendFactorZero, BlendFactorSourceAlpha)
+	BlendSourceOut       = blendWithFactors(BlendFactorOneMinusDestinationAlpha, BlendFactorZero)
+	BlendDestinationOut  = blendWithFactors(BlendFactorZero, BlendFactorOneMinusSourceAlpha)
+	BlendSourceAtop      = blendWithFactors(BlendFactorDestinationAlpha, BlendFactorOneMinusSourceAlpha)
+	BlendDestinationAtop = blendWithFactors(BlendFactorOneMinusDestinationAlpha, BlendFactorSourceAlpha)
+	BlendXor             = blendWithFactors(BlendFactorOneMinusDestinationAlpha, BlendFactorOneMinusSourceAlpha)
+	BlendLighter         = blendWithFactors(BlendFactorOne, BlendFactorOne)
+	BlendMultiply        = blendWithFactors(BlendFactorDestinationColor, BlendFactorZero)
+)
+
+// blend returns the Blend corresponding to the composite mode.
+func (c CompositeMode) blend() Blend {
+	switch c {
+	case CompositeModeSourceOver:
+		return BlendSourceOver
+	case CompositeModeClear:
+		return BlendClear
+	case CompositeModeCopy:
+		return BlendCopy
+	case CompositeModeDestination:
+		return BlendDestination
+	case CompositeModeDestinationOver:
+		return BlendDestinationOver
+	case CompositeModeSourceIn:
+		return BlendSourceIn
+	case CompositeModeDestinationIn:
+		return BlendDestinationIn
+	case CompositeModeSourceOut:
+		return BlendSourceOut
+	case CompositeModeDestinationOut:
+		return BlendDestinationOut
+	case CompositeModeSourceAtop:
+		return BlendSourceAtop
+	case CompositeModeDestinationAtop:
+		return BlendDestinationAtop
+	case CompositeModeXor:
+		return BlendXor
+	case CompositeModeLighter:
+		return BlendLighter
+	case CompositeModeMultiply:
+		return BlendMultiply
+	default:
+		panic(fmt.Sprintf("ebiten: invalid composite mode: %d", c))
+	}
+}
+
+// internalBlend returns the internal blend for the given composite mode and blend of drawing options.
+// If blend is not the zero value, blend is used and compositeMode is ignored.
+func internalBlend(compositeMode CompositeMode, blend Blend) graphicsdriver.Blend {
+	if blend != (Blend{}) {
+		return blend.internalBlend()
+	}
+	return compositeMode.blend().internalBlend()
+}
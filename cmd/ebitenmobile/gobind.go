@@ -358,6 +358,22 @@ const objcM = `// Code generated by ebitenmobile. DO NOT EDIT.
   }
 }
 
+- (void)setPreferredFrameRate:(long)fps {
+  @synchronized(self) {
+    // A frame rate higher than 60 Hz on ProMotion iPhones requires CADisableMinimumFrameDurationOnPhone in Info.plist.
+    if (@available(iOS 15.0, *)) {
+      if (fps > 0) {
+        displayLink_.preferredFrameRateRange = CAFrameRateRangeMake(fps, fps, fps);
+      } else {
+        displayLink_.preferredFrameRateRange = CAFrameRateRangeDefault;
+      }
+    } else {
+      // 0 means the maximum refresh rate of the display.
+      displayLink_.preferredFramesPerSecond = fps;
+    }
+  }
+}
+
 - (void)requestRenderIfNeeded {
   @synchronized(self) {
     if (explicitRendering_) {
@@ -850,6 +866,11 @@ class EbitenSurfaceView extends GLSurfaceView implements RenderRequester {
         }
     }
 
+    @Override
+    public synchronized void setPreferredFrameRate(long fps) {
+        // Do nothing. GLSurfaceView renders at the refresh rate of the display.
+    }
+
     @Override
     public synchronized void requestRenderIfNeeded() {
         if (getRenderMode() == RENDERMODE_WHEN_DIRTY) {
@@ -0,0 +1,204 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// kagec is a compiler and a validator for Kage, Ebiten's shading language.
+//
+// Usage:
+//
+//	kagec [-target glsl|glsles100|glsles300|msl] [-o output] [-json] file...
+//
+// kagec compiles the given files together as one shader in the same way as ebiten.NewShaderFromSources,
+// and reports the diagnostics to stderr.
+// To validate independent shaders, run kagec for each of them.
+//
+// With -target, kagec emits the shader in the target language to the output (stdout by default).
+// For GLSL, the vertex shader and the fragment shader are emitted to the files output.vert and output.frag.
+// Without -target, kagec only validates the files.
+//
+// With -json, the diagnostics are reported as JSON objects, one per line.
+//
+// kagec exits with 1 when the files are invalid, and with 2 when the usage is wrong.
+// kagec doesn't require a GPU or a window, so it is available in CI environments.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/shader"
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir/glsl"
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir/metal"
+)
+
+var (
+	flagTarget = flag.String("target", "", "target shading language: glsl, glsles100, glsles300 or msl")
+	flagOutput = flag.String("o", "", "output file, or output file prefix for GLSL (default: stdout)")
+	flagJSON   = flag.Bool("json", false, "report diagnostics as JSON")
+)
+
+func init() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: kagec [-target glsl|glsles100|glsles300|msl] [-o output] [-json] file...\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+}
+
+// diagnostic is a diagnostic in the JSON format.
+type diagnostic struct {
+	Filename string `json:"filename"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	Snippet  string `json:"snippet"`
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+	}
+
+	switch *flagTarget {
+	case "", "glsl", "glsles100", "glsles300", "msl":
+	case "hlsl":
+		fmt.Fprintf(os.Stderr, "kagec: -target hlsl is not supported yet\n")
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "kagec: invalid -target: %s\n", *flagTarget)
+		os.Exit(2)
+	}
+
+	if err := run(flag.Args()); err != nil {
+		perr, ok := err.(*shader.ParseError)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "kagec: %v\n", err)
+			os.Exit(1)
+		}
+		if *flagJSON {
+			reportJSON(perr)
+		} else {
+			report(perr)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(filenames []string) error {
+	srcs := make([][]byte, 0, len(filenames))
+	for _, filename := range filenames {
+		src, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		srcs = append(srcs, src)
+	}
+
+	p, err := shader.CompileSources(srcs, filenames)
+	if err != nil {
+		return err
+	}
+
+	if *flagTarget == "" {
+		return nil
+	}
+
+	return emit(p, *flagTarget, *flagOutput)
+}
+
+// emit writes the program in the target language to output.
+// For GLSL, the vertex shader and the fragment shader are written to output.vert and output.frag.
+// If output is empty, emit writes to stdout.
+func emit(p *shaderir.Program, target string, output string) error {
+	switch target {
+	case "glsl", "glsles100", "glsles300":
+		version := glsl.GLSLVersionDefault
+		switch target {
+		case "glsles100":
+			version = glsl.GLSLVersionES100
+		case "glsles300":
+			version = glsl.GLSLVersionES300
+		}
+		vs, fs := glsl.Compile(p, version)
+		if output == "" {
+			_, err := os.Stdout.WriteString("// Vertex shader\n\n" + vs + "\n\n// Fragment shader\n\n" + fs + "\n")
+			return err
+		}
+		if err := ioutil.WriteFile(output+".vert", []byte(vs+"\n"), 0644); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(output+".frag", []byte(fs+"\n"), 0644)
+	case "msl":
+		// The function names must match the names that the Metal graphics driver uses.
+		src := metal.Compile(p, "Vertex", "Fragment") + "\n"
+		if output == "" {
+			_, err := os.Stdout.WriteString(src)
+			return err
+		}
+		return ioutil.WriteFile(output, []byte(src), 0644)
+	default:
+		panic(fmt.Sprintf("kagec: unexpected target: %s", target))
+	}
+}
+
+// report reports the errors in the format of file:line:column: message [code], followed by the source line
+// and a caret at the column.
+func report(err *shader.ParseError) {
+	for _, e := range err.Errors {
+		fmt.Fprintf(os.Stderr, "%s [%s]\n", e.Error(), e.Code)
+		if e.Snippet == "" {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "\t%s\n", e.Snippet)
+		fmt.Fprintf(os.Stderr, "\t%s^\n", caretIndent(e.Snippet, e.Pos.Column))
+	}
+}
+
+// caretIndent returns the indent to put a caret at the column (in bytes, 1-based) of line.
+// Tabs are kept so that the caret is aligned regardless of the tab width.
+func caretIndent(line string, column int) string {
+	if column-1 < len(line) {
+		line = line[:column-1]
+	}
+	var b strings.Builder
+	for _, r := range line {
+		if r == '\t' {
+			b.WriteRune('\t')
+			continue
+		}
+		b.WriteRune(' ')
+	}
+	return b.String()
+}
+
+func reportJSON(err *shader.ParseError) {
+	enc := json.NewEncoder(os.Stderr)
+	for _, e := range err.Errors {
+		// Encoding a struct of strings and ints never fails.
+		_ = enc.Encode(&diagnostic{
+			Filename: e.Pos.Filename,
+			Line:     e.Pos.Line,
+			Column:   e.Pos.Column,
+			Code:     string(e.Code),
+			Message:  e.Msg,
+			Snippet:  e.Snippet,
+		})
+	}
+}
@@ -15,6 +15,9 @@
 package ebiten
 
 import (
+	"image"
+	"sync/atomic"
+
 	"github.com/hajimehoshi/ebiten/v2/internal/ui"
 )
 
@@ -40,3 +43,40 @@ const (
 	CursorShapeEWResize  CursorShapeType = CursorShapeType(ui.CursorShapeEWResize)
 	CursorShapeNSResize  CursorShapeType = CursorShapeType(ui.CursorShapeNSResize)
 )
+
+// SetCursorImage sets the mouse cursor to the given image with the native cursor of the OS.
+// hotspotX and hotspotY specify the position in the image that points the cursor position.
+// The hotspot is relative to the upper-left corner of the image's bounds.
+//
+// A native cursor follows the mouse without the latency of drawing a cursor in the game every frame.
+// The maximum size of a cursor image depends on the environment. 32x32 is a safe size.
+//
+// If img is nil, SetCursorImage reverts the cursor to the cursor shape.
+// SetCursorShape also reverts the cursor image.
+//
+// img can be *ebiten.Image. The pixels of *ebiten.Image are read from GPU asynchronously, and the cursor is
+// updated a few frames later. The cursor reflects the image at the time SetCursorImage is called.
+//
+// The cursor image is visible only when the cursor mode is CursorModeVisible.
+//
+// SetCursorImage does nothing on mobiles.
+//
+// SetCursorImage is concurrent-safe.
+func SetCursorImage(img image.Image, hotspotX, hotspotY int) {
+	gen := atomic.AddUint64(&cursorImageGeneration, 1)
+	if img == nil {
+		ui.Get().SetCursorImage(nil, 0, 0)
+		return
+	}
+
+	readImagesAsync([]image.Image{img}, func(imgs []image.Image) {
+		// Skip this if SetCursorImage or SetCursorShape is called again in the meantime.
+		if atomic.LoadUint64(&cursorImageGeneration) != gen {
+			return
+		}
+		ui.Get().SetCursorImage(imgs[0], hotspotX, hotspotY)
+	})
+}
+
+// cursorImageGeneration is incremented every time SetCursorImage or SetCursorShape is called.
+var cursorImageGeneration uint64
@@ -0,0 +1,184 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/assets"
+	"github.com/hajimehoshi/ebiten/v2/internal/atlas"
+	"github.com/hajimehoshi/ebiten/v2/internal/clock"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/ui"
+)
+
+var debugOverlayEnabled int32
+
+// SetDebugOverlayEnabled shows or hides the debug overlay.
+//
+// The debug overlay is rendered on the top-left corner of the window over the game screen, and shows
+// FPS, TPS, the statistics of draw calls (see ReadDrawStatistics), the usage of the texture atlases,
+// CPU and GPU frame times and memory statistics.
+// The debug overlay is not included in Screenshot or recordings.
+//
+// The debug overlay is disabled by default.
+//
+// SetDebugOverlayEnabled is concurrent-safe.
+func SetDebugOverlayEnabled(enabled bool) {
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&debugOverlayEnabled, v)
+}
+
+// IsDebugOverlayEnabled reports whether the debug overlay is shown.
+//
+// IsDebugOverlayEnabled is concurrent-safe.
+func IsDebugOverlayEnabled() bool {
+	return atomic.LoadInt32(&debugOverlayEnabled) != 0
+}
+
+// memStatsInterval is the interval to read the memory statistics.
+// runtime.ReadMemStats stops the world, then this should not be called every frame.
+const memStatsInterval = time.Second
+
+// debugOverlay renders the debug overlay. debugOverlay is accessed only from the game's goroutine.
+type debugOverlay struct {
+	textImage     *Image
+	textSubImages map[rune]*Image
+
+	memStats         runtime.MemStats
+	lastMemStatsTime time.Time
+}
+
+var theDebugOverlay debugOverlay
+
+func (d *debugOverlay) text(cpuTime time.Duration) string {
+	if now := time.Now(); now.Sub(d.lastMemStatsTime) >= memStatsInterval {
+		runtime.ReadMemStats(&d.memStats)
+		d.lastMemStatsTime = now
+	}
+
+	var stats DrawStatistics
+	ReadDrawStatistics(&stats)
+
+	atlases, used, total := atlas.Usage()
+	var usage float64
+	if total > 0 {
+		usage = float64(used) / float64(total) * 100
+	}
+
+	_, lastFrame, maxFrame := clock.FrameStatistics()
+
+	gpu := "N/A"
+	if t := ui.GPUFrameDuration(); t > 0 {
+		gpu = fmt.Sprintf("%0.2f ms", durationToMilliseconds(t))
+	}
+
+	const mib = 1024 * 1024
+	return fmt.Sprintf(`FPS: %0.2f
+TPS: %0.2f
+Draw calls: %d/%d (%d switches)
+Vertices: %d
+Atlases: %d (%0.1f%% used)
+Frame: %0.2f ms (max: %0.2f ms)
+CPU: %0.2f ms
+GPU: %s
+Heap: %0.1f MiB (sys: %0.1f MiB)
+GC: %d`,
+		CurrentFPS(),
+		CurrentTPS(),
+		stats.DrawCalls, stats.DrawRequests, stats.PipelineSwitches,
+		stats.Vertices,
+		atlases, usage,
+		durationToMilliseconds(lastFrame), durationToMilliseconds(maxFrame),
+		durationToMilliseconds(cpuTime),
+		gpu,
+		float64(d.memStats.HeapAlloc)/mib, float64(d.memStats.Sys)/mib,
+		d.memStats.NumGC)
+}
+
+func durationToMilliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// draw renders the debug overlay onto the screen framebuffer dst.
+func (d *debugOverlay) draw(dst *Image, deviceScaleFactor float64, framebufferYDirection graphicsdriver.YDirection, cpuTime time.Duration) {
+	if !IsDebugOverlayEnabled() {
+		return
+	}
+
+	if d.textImage == nil {
+		d.textImage = NewImageFromImage(assets.CreateTextImage())
+		d.textSubImages = map[rune]*Image{}
+	}
+
+	var geoM GeoM
+	s := math.Max(1, math.Ceil(deviceScaleFactor))
+	geoM.Scale(s, s)
+	switch framebufferYDirection {
+	case graphicsdriver.Upward:
+		_, h := dst.Size()
+		geoM.Scale(1, -1)
+		geoM.Translate(0, float64(h))
+	case graphicsdriver.Downward:
+	default:
+		panic(fmt.Sprintf("ebiten: invalid v-direction: %d", framebufferYDirection))
+	}
+
+	str := d.text(cpuTime)
+	d.drawText(dst, str, 1, 1, geoM, true)
+	d.drawText(dst, str, 0, 0, geoM, false)
+}
+
+func (d *debugOverlay) drawText(dst *Image, str string, ox, oy int, geoM GeoM, shadow bool) {
+	op := &DrawImageOptions{}
+	if shadow {
+		op.ColorM.Scale(0, 0, 0, 0.5)
+	}
+	x := 0
+	y := 0
+	w, _ := d.textImage.Size()
+	for _, c := range str {
+		const (
+			cw = assets.CharWidth
+			ch = assets.CharHeight
+		)
+		if c == '\n' {
+			x = 0
+			y += ch
+			continue
+		}
+		s, ok := d.textSubImages[c]
+		if !ok {
+			n := w / cw
+			sx := (int(c) % n) * cw
+			sy := (int(c) / n) * ch
+			s = d.textImage.SubImage(image.Rect(sx, sy, sx+cw, sy+ch)).(*Image)
+			d.textSubImages[c] = s
+		}
+		op.GeoM.Reset()
+		op.GeoM.Translate(float64(x+ox+1), float64(y+oy))
+		op.GeoM.Concat(geoM)
+		dst.DrawImage(s, op)
+		x += cw
+	}
+}
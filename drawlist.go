@@ -0,0 +1,262 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"github.com/hajimehoshi/ebiten/v2/internal/affine"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/mipmap"
+)
+
+// DrawList is a list of drawing operations recorded in advance.
+//
+// DrawList is useful to render a lot of static objects like a tilemap every frame.
+// The vertices are calculated at recording, and consecutive operations with the same source image and
+// the same options are merged into one batch. Image.DrawList replays the recorded operations with one call.
+//
+// The source images are referred to at replaying.
+// Modifying a source image after recording affects the result of replaying.
+//
+// The zero value for DrawList is an empty list ready to use.
+//
+// This API is experimental.
+type DrawList struct {
+	entries []drawListEntry
+}
+
+type drawListEntry struct {
+	src           *Image
+	vertices      []float32
+	indices       []uint16
+	colorm        affine.ColorM
+	mode          graphicsdriver.Blend
+	filter        graphicsdriver.Filter
+	quality       mipmap.FilterQuality
+	address       graphicsdriver.Address
+	srcRegion     graphicsdriver.Region
+	fillRule      graphicsdriver.FillRule
+	canSkipMipmap bool
+}
+
+// canMerge reports whether the other entry can be merged into e.
+func (e *drawListEntry) canMerge(other *drawListEntry) bool {
+	if e.src != other.src {
+		return false
+	}
+	if !e.colorm.Equals(other.colorm) {
+		return false
+	}
+	if e.mode != other.mode {
+		return false
+	}
+	if e.filter != other.filter {
+		return false
+	}
+	if e.quality != other.quality {
+		return false
+	}
+	if e.address != other.address {
+		return false
+	}
+	if e.srcRegion != other.srcRegion {
+		return false
+	}
+	// Merging triangles with a fill rule other than FillAll changes how overlapped regions are rendered.
+	if e.fillRule != graphicsdriver.FillAll || other.fillRule != graphicsdriver.FillAll {
+		return false
+	}
+	// The mipmap level is determined for a whole batch. Merging operations might change the level.
+	if e.filter == graphicsdriver.FilterLinear && (!e.canSkipMipmap || !other.canSkipMipmap) {
+		return false
+	}
+	if e.filter == graphicsdriver.FilterLinear && e.quality != mipmap.FilterQualityDefault {
+		return false
+	}
+	if len(e.indices)+len(other.indices) > graphics.IndicesNum {
+		return false
+	}
+	if (len(e.vertices)+len(other.vertices))/graphics.VertexFloatNum > graphics.VerticesNumPerBuffer {
+		return false
+	}
+	return true
+}
+
+func (l *DrawList) append(entry *drawListEntry) {
+	if len(l.entries) > 0 {
+		last := &l.entries[len(l.entries)-1]
+		if last.canMerge(entry) {
+			base := uint16(len(last.vertices) / graphics.VertexFloatNum)
+			for _, idx := range entry.indices {
+				last.indices = append(last.indices, idx+base)
+			}
+			last.vertices = append(last.vertices, entry.vertices...)
+			return
+		}
+	}
+	l.entries = append(l.entries, *entry)
+}
+
+// DrawImage records an operation to draw the given image.
+//
+// The arguments are the same as Image.DrawImage.
+//
+// When the given image is disposed, DrawImage panics.
+func (l *DrawList) DrawImage(img *Image, options *DrawImageOptions) {
+	if img.isDisposed() {
+		panic("ebiten: the given image to DrawList.DrawImage must not be disposed")
+	}
+
+	if options == nil {
+		options = &DrawImageOptions{}
+	}
+
+	bounds := img.Bounds()
+	filter := graphicsdriver.Filter(options.Filter)
+
+	a, b, c, d, tx, ty := options.GeoM.elements32()
+
+	sx0 := float32(bounds.Min.X)
+	sy0 := float32(bounds.Min.Y)
+	sx1 := float32(bounds.Max.X)
+	sy1 := float32(bounds.Max.Y)
+	vs := make([]float32, 4*graphics.VertexFloatNum)
+	copy(vs, graphics.QuadVertices(sx0, sy0, sx1, sy1, a, b, c, d, tx, ty, 1, 1, 1, 1))
+	is := make([]uint16, 6)
+	copy(is, graphics.QuadIndices())
+
+	l.append(&drawListEntry{
+		src:           img,
+		vertices:      vs,
+		indices:       is,
+		colorm:        options.ColorM.affineColorM(),
+		mode:          internalBlend(options.CompositeMode, options.Blend),
+		filter:        filter,
+		quality:       mipmap.FilterQuality(options.FilterQuality),
+		address:       graphicsdriver.AddressUnsafe,
+		fillRule:      graphicsdriver.FillAll,
+		canSkipMipmap: canSkipMipmap(options.GeoM, filter),
+	})
+}
+
+// DrawTriangles records an operation to draw triangles with the given image.
+//
+// The arguments are the same as Image.DrawTriangles.
+//
+// If len(indices) is not multiple of 3, DrawTriangles panics.
+//
+// If len(indices) is more than MaxIndicesNum, DrawTriangles panics.
+//
+// When the given image is disposed, DrawTriangles panics.
+func (l *DrawList) DrawTriangles(vertices []Vertex, indices []uint16, img *Image, options *DrawTrianglesOptions) {
+	if img != nil && img.isDisposed() {
+		panic("ebiten: the given image to DrawList.DrawTriangles must not be disposed")
+	}
+
+	if len(indices)%3 != 0 {
+		panic("ebiten: len(indices) % 3 must be 0")
+	}
+	if len(indices) > MaxIndicesNum {
+		panic("ebiten: len(indices) must be <= MaxIndicesNum")
+	}
+
+	if options == nil {
+		options = &DrawTrianglesOptions{}
+	}
+
+	address := graphicsdriver.Address(options.Address)
+	var sr graphicsdriver.Region
+	if address != graphicsdriver.AddressUnsafe {
+		b := img.Bounds()
+		sr = graphicsdriver.Region{
+			X:      float32(b.Min.X),
+			Y:      float32(b.Min.Y),
+			Width:  float32(b.Dx()),
+			Height: float32(b.Dy()),
+		}
+	}
+
+	vs := make([]float32, len(vertices)*graphics.VertexFloatNum)
+	for i, v := range vertices {
+		vs[i*graphics.VertexFloatNum] = v.DstX
+		vs[i*graphics.VertexFloatNum+1] = v.DstY
+		vs[i*graphics.VertexFloatNum+2] = v.SrcX
+		vs[i*graphics.VertexFloatNum+3] = v.SrcY
+		vs[i*graphics.VertexFloatNum+4] = v.ColorR
+		vs[i*graphics.VertexFloatNum+5] = v.ColorG
+		vs[i*graphics.VertexFloatNum+6] = v.ColorB
+		vs[i*graphics.VertexFloatNum+7] = v.ColorA
+	}
+	is := make([]uint16, len(indices))
+	copy(is, indices)
+
+	l.append(&drawListEntry{
+		src:       img,
+		vertices:  vs,
+		indices:   is,
+		colorm:    options.ColorM.affineColorM(),
+		mode:      internalBlend(options.CompositeMode, options.Blend),
+		filter:    graphicsdriver.Filter(options.Filter),
+		quality:   mipmap.FilterQuality(options.FilterQuality),
+		address:   address,
+		srcRegion: sr,
+		fillRule:  graphicsdriver.FillRule(options.FillRule),
+	})
+}
+
+// Clear removes all the recorded operations.
+func (l *DrawList) Clear() {
+	l.entries = l.entries[:0]
+}
+
+// DrawList draws the operations recorded in the given list.
+//
+// When one of the source images in the list is disposed, DrawList panics.
+//
+// When the image i is disposed, DrawList does nothing.
+//
+// This API is experimental.
+func (i *Image) DrawList(list *DrawList) {
+	i.copyCheck()
+
+	for _, e := range list.entries {
+		if e.src != nil && e.src.isDisposed() {
+			panic("ebiten: the source images in the given DrawList must not be disposed")
+		}
+	}
+	if i.isDisposed() {
+		return
+	}
+
+	dstBounds := i.Bounds()
+	dstRegion := graphicsdriver.Region{
+		X:      float32(dstBounds.Min.X),
+		Y:      float32(dstBounds.Min.Y),
+		Width:  float32(dstBounds.Dx()),
+		Height: float32(dstBounds.Dy()),
+	}
+
+	for _, e := range list.entries {
+		// The vertices are modified in the internal packages. Pass a copy.
+		vs := graphics.Vertices(len(e.vertices) / graphics.VertexFloatNum)
+		copy(vs, e.vertices)
+
+		var srcs [graphics.ShaderImageNum]*mipmap.Mipmap
+		if e.src != nil {
+			srcs[0] = e.src.mipmap
+		}
+		i.mipmap.DrawTriangles(srcs, vs, e.indices, e.colorm, e.mode, e.filter, e.quality, e.address, dstRegion, e.srcRegion, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, e.fillRule, e.canSkipMipmap)
+	}
+}
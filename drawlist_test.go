@@ -0,0 +1,81 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestDrawListTilemap(t *testing.T) {
+	const (
+		tileSize = 4
+		tilesX   = 8
+		tilesY   = 6
+	)
+
+	tileset := ebiten.NewImage(tileSize*2, tileSize)
+	tileset.SubImage(image.Rect(0, 0, tileSize, tileSize)).(*ebiten.Image).Fill(color.RGBA{0xff, 0, 0, 0xff})
+	tileset.SubImage(image.Rect(tileSize, 0, tileSize*2, tileSize)).(*ebiten.Image).Fill(color.RGBA{0, 0xff, 0, 0xff})
+
+	var list ebiten.DrawList
+	ref := ebiten.NewImage(tileSize*tilesX, tileSize*tilesY)
+	for j := 0; j < tilesY; j++ {
+		for i := 0; i < tilesX; i++ {
+			k := (i + j) % 2
+			tile := tileset.SubImage(image.Rect(k*tileSize, 0, (k+1)*tileSize, tileSize)).(*ebiten.Image)
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(float64(i*tileSize), float64(j*tileSize))
+			list.DrawImage(tile, op)
+			ref.DrawImage(tile, op)
+		}
+	}
+
+	dst := ebiten.NewImage(tileSize*tilesX, tileSize*tilesY)
+	// Replay the list twice to confirm that the list is reusable.
+	for n := 0; n < 2; n++ {
+		dst.Clear()
+		dst.DrawList(&list)
+
+		w, h := dst.Size()
+		for j := 0; j < h; j++ {
+			for i := 0; i < w; i++ {
+				got := dst.At(i, j)
+				want := ref.At(i, j)
+				if got != want {
+					t.Errorf("replay %d: dst.At(%d, %d): got: %v, want: %v", n, i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestDrawListClear(t *testing.T) {
+	src := ebiten.NewImage(4, 4)
+	src.Fill(color.White)
+
+	var list ebiten.DrawList
+	list.DrawImage(src, nil)
+	list.Clear()
+
+	dst := ebiten.NewImage(4, 4)
+	dst.DrawList(&list)
+	if got, want := dst.At(0, 0), (color.RGBA{}); got != want {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
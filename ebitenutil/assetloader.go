@@ -0,0 +1,266 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// AssetOpener is a function to open an asset by its name.
+type AssetOpener func(name string) (io.ReadCloser, error)
+
+// HTTPAssetOpener returns an AssetOpener that fetches an asset from baseURL + name via HTTP GET.
+func HTTPAssetOpener(baseURL string) AssetOpener {
+	return func(name string) (io.ReadCloser, error) {
+		url := baseURL + name
+		if baseURL != "" && !strings.HasSuffix(baseURL, "/") {
+			url = baseURL + "/" + name
+		}
+		res, err := http.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode < 200 || res.StatusCode >= 300 {
+			_ = res.Body.Close()
+			return nil, fmt.Errorf("ebitenutil: HTTP GET %s failed: %s", url, res.Status)
+		}
+		return res.Body, nil
+	}
+}
+
+// AssetDecodeFunc is a function to decode an asset.
+//
+// AssetDecodeFunc is called on a goroutine other than the game's goroutine.
+// r is closed right after AssetDecodeFunc returns, so the decoded value must not read r lazily.
+type AssetDecodeFunc func(r io.Reader) (interface{}, error)
+
+// AssetFinalizeFunc is a function to convert a decoded asset into the final value.
+//
+// AssetFinalizeFunc is called on the game's goroutine at (*AssetLoader).Update.
+type AssetFinalizeFunc func(decoded interface{}) (interface{}, error)
+
+// Asset represents an asset loaded by AssetLoader.
+type Asset struct {
+	name     string
+	finalize AssetFinalizeFunc
+
+	value  interface{}
+	err    error
+	loaded bool
+}
+
+// Name returns the asset's name.
+func (a *Asset) Name() string {
+	return a.name
+}
+
+// IsLoaded reports whether the asset's loading has finished, regardless of its success.
+func (a *Asset) IsLoaded() bool {
+	return a.loaded
+}
+
+// Err returns the error in loading the asset if exists.
+func (a *Asset) Err() error {
+	return a.err
+}
+
+// Value returns the loaded value.
+//
+// Value returns nil if the asset is not loaded yet or the loading failed.
+func (a *Asset) Value() interface{} {
+	return a.value
+}
+
+// Image returns the loaded image.
+//
+// Image returns nil if the asset is not loaded yet, the loading failed, or the asset is not an image.
+func (a *Asset) Image() *ebiten.Image {
+	img, _ := a.value.(*ebiten.Image)
+	return img
+}
+
+// Face returns the loaded font face.
+//
+// Face returns nil if the asset is not loaded yet, the loading failed, or the asset is not a font.
+func (a *Asset) Face() font.Face {
+	f, _ := a.value.(font.Face)
+	return f
+}
+
+// AssetLoader loads assets asynchronously.
+//
+// Opening and decoding assets are done on goroutines other than the game's goroutine,
+// and the decoded assets are converted into the final values like ebiten.Image on the game's goroutine
+// when Update is called.
+// Then, the game can show a loading screen without being blocked.
+//
+// AssetLoader's functions must be called on the game's goroutine.
+type AssetLoader struct {
+	open AssetOpener
+
+	assets  []*Asset
+	loading int
+	results chan assetResult
+	sem     chan struct{}
+	err     error
+}
+
+type assetResult struct {
+	asset   *Asset
+	decoded interface{}
+	err     error
+}
+
+// maxConcurrentAssetLoads is the maximum number of assets that are decoded at the same time.
+const maxConcurrentAssetLoads = 4
+
+// NewAssetLoader returns a new AssetLoader that opens assets with open.
+func NewAssetLoader(open AssetOpener) *AssetLoader {
+	return &AssetLoader{
+		open:    open,
+		results: make(chan assetResult, 64),
+		sem:     make(chan struct{}, maxConcurrentAssetLoads),
+	}
+}
+
+// Load queues a load of an asset and returns the asset.
+//
+// decode is called with the opened asset on another goroutine.
+// finalize is called with the decoded value on the game's goroutine at Update.
+// If finalize is nil, the decoded value is used as it is.
+//
+// Load is useful for assets other than images and fonts.
+// As the opened asset is closed right after decode returns, decode must read all the data it needs.
+//
+// AssetLoader doesn't have a function to load audio, since the audio package is not a dependency of ebitenutil.
+// To load audio, read the whole decoded stream in decode (e.g. by mp3.DecodeWithSampleRate and ioutil.ReadAll),
+// and create a player from the bytes with (*audio.Context).NewPlayerFromBytes in finalize.
+func (l *AssetLoader) Load(name string, decode AssetDecodeFunc, finalize AssetFinalizeFunc) *Asset {
+	a := &Asset{
+		name:     name,
+		finalize: finalize,
+	}
+	l.assets = append(l.assets, a)
+	l.loading++
+
+	go func() {
+		l.sem <- struct{}{}
+		defer func() {
+			<-l.sem
+		}()
+
+		decoded, err := l.decode(name, decode)
+		l.results <- assetResult{
+			asset:   a,
+			decoded: decoded,
+			err:     err,
+		}
+	}()
+	return a
+}
+
+func (l *AssetLoader) decode(name string, decode AssetDecodeFunc) (interface{}, error) {
+	r, err := l.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	return decode(r)
+}
+
+// LoadImage queues a load of an image and returns the asset.
+// The asset's value is an *ebiten.Image.
+//
+// Image decoders must be imported when using LoadImage. For example,
+// if you want to load a PNG image, you'd need to add `_ "image/png"` to the import section.
+func (l *AssetLoader) LoadImage(name string) *Asset {
+	return l.Load(name, func(r io.Reader) (interface{}, error) {
+		img, _, err := image.Decode(r)
+		if err != nil {
+			return nil, err
+		}
+		return img, nil
+	}, func(decoded interface{}) (interface{}, error) {
+		return ebiten.NewImageFromImage(decoded.(image.Image)), nil
+	})
+}
+
+// LoadFace queues a load of an OpenType or TrueType font and returns the asset.
+// The asset's value is a font.Face.
+func (l *AssetLoader) LoadFace(name string, options *opentype.FaceOptions) *Asset {
+	return l.Load(name, func(r io.Reader) (interface{}, error) {
+		bs, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		f, err := opentype.Parse(bs)
+		if err != nil {
+			return nil, err
+		}
+		return opentype.NewFace(f, options)
+	}, nil)
+}
+
+// Update finalizes the assets whose decoding has finished.
+//
+// Update must be called on the game's goroutine, typically from the game's Update.
+//
+// Update returns the first error that occurred in loading assets.
+// Even after an error, the other assets continue being loaded.
+func (l *AssetLoader) Update() error {
+	for {
+		select {
+		case r := <-l.results:
+			l.loading--
+			a := r.asset
+			a.value, a.err = r.decoded, r.err
+			if a.err == nil && a.finalize != nil {
+				a.value, a.err = a.finalize(r.decoded)
+			}
+			a.loaded = true
+			if a.err != nil {
+				a.value = nil
+				a.err = fmt.Errorf("ebitenutil: loading %s failed: %w", a.name, a.err)
+				if l.err == nil {
+					l.err = a.err
+				}
+			}
+		default:
+			return l.err
+		}
+	}
+}
+
+// Progress returns the number of loaded assets and the number of all the queued assets.
+func (l *AssetLoader) Progress() (loaded, total int) {
+	return len(l.assets) - l.loading, len(l.assets)
+}
+
+// IsDone reports whether all the queued assets are loaded.
+func (l *AssetLoader) IsDone() bool {
+	return l.loading == 0
+}
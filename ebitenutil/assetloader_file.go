@@ -0,0 +1,32 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !android && !ios
+// +build !android,!ios
+
+package ebitenutil
+
+import (
+	"io"
+	"path"
+)
+
+// FileAssetOpener returns an AssetOpener that opens an asset at the path dir + "/" + name by OpenFile.
+//
+// How to solve path depends on your environment. See OpenFile.
+func FileAssetOpener(dir string) AssetOpener {
+	return func(name string) (io.ReadCloser, error) {
+		return OpenFile(path.Join(dir, name))
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package ebitenutil_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+type memoryAssets struct {
+	data  map[string]string
+	gates map[string]chan struct{}
+}
+
+func (m *memoryAssets) open(name string) (io.ReadCloser, error) {
+	if g, ok := m.gates[name]; ok {
+		<-g
+	}
+	d, ok := m.data[name]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", name)
+	}
+	return ioutil.NopCloser(strings.NewReader(d)), nil
+}
+
+func readString(r io.Reader) (interface{}, error) {
+	bs, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return string(bs), nil
+}
+
+// waitForAssets calls Update until all the assets are loaded.
+func waitForAssets(t *testing.T, l *ebitenutil.AssetLoader) error {
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		err := l.Update()
+		if l.IsDone() {
+			return err
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("loading assets timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAssetLoaderProgress(t *testing.T) {
+	m := &memoryAssets{
+		data: map[string]string{
+			"a.txt": "foo",
+			"b.txt": "bar",
+		},
+		gates: map[string]chan struct{}{
+			"b.txt": make(chan struct{}),
+		},
+	}
+	l := ebitenutil.NewAssetLoader(m.open)
+
+	if !l.IsDone() {
+		t.Errorf("IsDone: got: false, want: true")
+	}
+	if loaded, total := l.Progress(); loaded != 0 || total != 0 {
+		t.Errorf("Progress: got: (%d, %d), want: (0, 0)", loaded, total)
+	}
+
+	a := l.Load("a.txt", readString, nil)
+	b := l.Load("b.txt", readString, func(decoded interface{}) (interface{}, error) {
+		return strings.ToUpper(decoded.(string)), nil
+	})
+
+	// Wait until a.txt is loaded. b.txt is blocked by the gate.
+	deadline := time.Now().Add(10 * time.Second)
+	for !a.IsLoaded() {
+		if err := l.Update(); err != nil {
+			t.Fatal(err)
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("loading a.txt timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if l.IsDone() {
+		t.Errorf("IsDone: got: true, want: false")
+	}
+	if loaded, total := l.Progress(); loaded != 1 || total != 2 {
+		t.Errorf("Progress: got: (%d, %d), want: (1, 2)", loaded, total)
+	}
+	if b.IsLoaded() {
+		t.Errorf("b.IsLoaded: got: true, want: false")
+	}
+	if got := b.Value(); got != nil {
+		t.Errorf("b.Value: got: %v, want: nil", got)
+	}
+
+	close(m.gates["b.txt"])
+	if err := waitForAssets(t, l); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, total := l.Progress(); loaded != 2 || total != 2 {
+		t.Errorf("Progress: got: (%d, %d), want: (2, 2)", loaded, total)
+	}
+
+	// With a nil finalize, the decoded value is used as it is.
+	if got, want := a.Value(), "foo"; got != want {
+		t.Errorf("a.Value: got: %v, want: %v", got, want)
+	}
+	if got, want := b.Value(), "BAR"; got != want {
+		t.Errorf("b.Value: got: %v, want: %v", got, want)
+	}
+	if a.Err() != nil || b.Err() != nil {
+		t.Errorf("Err: got: %v, %v, want: nil, nil", a.Err(), b.Err())
+	}
+	if got := a.Image(); got != nil {
+		t.Errorf("a.Image: got: %v, want: nil", got)
+	}
+}
+
+func TestAssetLoaderError(t *testing.T) {
+	errFinalize := errors.New("finalize failed")
+
+	m := &memoryAssets{
+		data: map[string]string{
+			"ok.txt":       "ok",
+			"finalize.txt": "finalize",
+		},
+	}
+	l := ebitenutil.NewAssetLoader(m.open)
+	ok := l.Load("ok.txt", readString, nil)
+	missing := l.Load("missing.txt", readString, nil)
+	finalize := l.Load("finalize.txt", readString, func(decoded interface{}) (interface{}, error) {
+		return decoded, errFinalize
+	})
+
+	if err := waitForAssets(t, l); err == nil {
+		t.Errorf("Update must return an error")
+	}
+
+	// The other assets continue being loaded after an error.
+	if got, want := ok.Value(), "ok"; got != want {
+		t.Errorf("ok.Value: got: %v, want: %v", got, want)
+	}
+	if ok.Err() != nil {
+		t.Errorf("ok.Err: got: %v, want: nil", ok.Err())
+	}
+
+	for _, a := range []*ebitenutil.Asset{missing, finalize} {
+		if !a.IsLoaded() {
+			t.Errorf("%s: IsLoaded: got: false, want: true", a.Name())
+		}
+		if a.Err() == nil {
+			t.Errorf("%s: Err: got: nil, want: non-nil", a.Name())
+		}
+		if a.Value() != nil {
+			t.Errorf("%s: Value: got: %v, want: nil", a.Name(), a.Value())
+		}
+	}
+	if !errors.Is(finalize.Err(), errFinalize) {
+		t.Errorf("finalize.Err: got: %v, want: wrapping %v", finalize.Err(), errFinalize)
+	}
+
+	// Update keeps returning the first error.
+	err := l.Update()
+	if err != missing.Err() && err != finalize.Err() {
+		t.Errorf("Update: got: %v, want: the first error", err)
+	}
+}
@@ -18,7 +18,7 @@ import (
 	"image"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil/internal/assets"
+	"github.com/hajimehoshi/ebiten/v2/internal/assets"
 )
 
 var (
@@ -0,0 +1,207 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/internal/apng"
+)
+
+const (
+	// gifMinDelay is the minimum delay in 100ths of a second for GIF.
+	// Many viewers treat a delay less than 2 as 10 (100 ms).
+	gifMinDelay = 2
+
+	// apngMinDelay is the minimum delay in milliseconds for APNG.
+	// Some viewers treat a delay of 10 ms or less as 100 ms.
+	apngMinDelay = 11
+)
+
+// frameDelays returns the indices of the frames to be encoded and their delays in the given unit.
+// times are the timestamps of the frames.
+//
+// The delays are the differences of the frames' timestamps rounded in the unit,
+// so that rounding errors are not accumulated.
+// A frame that would be shown shorter than minDelay is dropped.
+func frameDelays(times []time.Duration, unit time.Duration, minDelay int) (indices []int, delays []int) {
+	if len(times) == 0 {
+		return nil, nil
+	}
+
+	indices = append(indices, 0)
+	var last int
+	for i := 1; i < len(times); i++ {
+		t := int((times[i] - times[0] + unit/2) / unit)
+		if t-last < minDelay {
+			continue
+		}
+		indices = append(indices, i)
+		delays = append(delays, t-last)
+		last = t
+	}
+
+	// The last frame doesn't have the next frame. Use the average delay.
+	d := minDelay
+	if n := len(delays); n > 0 {
+		d = (last + n/2) / n
+	}
+	if d < minDelay {
+		d = minDelay
+	}
+	delays = append(delays, d)
+	return indices, delays
+}
+
+// toPaletted converts img to a paletted image for GIF.
+func toPaletted(img *image.RGBA) *image.Paletted {
+	p := image.NewPaletted(img.Bounds(), palette.Plan9)
+	draw.FloydSteinberg.Draw(p, p.Bounds(), img, image.Point{})
+	return p
+}
+
+type gifFrameEncoder struct {
+	w      io.Writer
+	times  []time.Duration
+	images []*image.Paletted
+}
+
+// NewGIFFrameEncoder returns a new ebiten.FrameEncoder that writes the frames to w as an animated GIF.
+//
+// The frames are converted to paletted images as they come, and the GIF is written to w when the encoder is closed.
+func NewGIFFrameEncoder(w io.Writer) ebiten.FrameEncoder {
+	return &gifFrameEncoder{
+		w: w,
+	}
+}
+
+// EncodeFrame implements ebiten.FrameEncoder.
+func (g *gifFrameEncoder) EncodeFrame(img *image.RGBA, t time.Duration) error {
+	if len(g.images) > 0 && img.Bounds() != g.images[0].Bounds() {
+		return fmt.Errorf("ebitenutil: all the recorded frames must have the same size")
+	}
+	g.times = append(g.times, t)
+	g.images = append(g.images, toPaletted(img))
+	return nil
+}
+
+// Close implements ebiten.FrameEncoder.
+func (g *gifFrameEncoder) Close() error {
+	if len(g.images) == 0 {
+		return fmt.Errorf("ebitenutil: no frames are recorded")
+	}
+
+	indices, delays := frameDelays(g.times, 10*time.Millisecond, gifMinDelay)
+	a := &gif.GIF{
+		Image: make([]*image.Paletted, len(indices)),
+		Delay: delays,
+	}
+	for i, idx := range indices {
+		a.Image[i] = g.images[idx]
+	}
+	g.times = nil
+	g.images = nil
+	return gif.EncodeAll(g.w, a)
+}
+
+type apngFrameEncoder struct {
+	w      io.Writer
+	times  []time.Duration
+	images []*image.RGBA
+}
+
+// NewAPNGFrameEncoder returns a new ebiten.FrameEncoder that writes the frames to w as an animated PNG.
+//
+// The frames are kept as they come, and the APNG is written to w when the encoder is closed.
+func NewAPNGFrameEncoder(w io.Writer) ebiten.FrameEncoder {
+	return &apngFrameEncoder{
+		w: w,
+	}
+}
+
+// EncodeFrame implements ebiten.FrameEncoder.
+func (a *apngFrameEncoder) EncodeFrame(img *image.RGBA, t time.Duration) error {
+	if len(a.images) > 0 && img.Bounds() != a.images[0].Bounds() {
+		return fmt.Errorf("ebitenutil: all the recorded frames must have the same size")
+	}
+	a.times = append(a.times, t)
+	a.images = append(a.images, img)
+	return nil
+}
+
+// Close implements ebiten.FrameEncoder.
+func (a *apngFrameEncoder) Close() error {
+	if len(a.images) == 0 {
+		return fmt.Errorf("ebitenutil: no frames are recorded")
+	}
+
+	indices, delays := frameDelays(a.times, time.Millisecond, apngMinDelay)
+	p := &apng.APNG{
+		Image: make([]image.Image, len(indices)),
+		Delay: make([]time.Duration, len(indices)),
+	}
+	for i, idx := range indices {
+		p.Image[i] = a.images[idx]
+		p.Delay[i] = time.Duration(delays[i]) * time.Millisecond
+	}
+	a.times = nil
+	a.images = nil
+	return apng.EncodeAll(a.w, p)
+}
+
+type pngSequenceFrameEncoder struct {
+	dir   string
+	index int
+}
+
+// NewPNGSequenceFrameEncoder returns a new ebiten.FrameEncoder that writes each frame to a PNG file in dir.
+//
+// The file names are frame_00000.png, frame_00001.png and so on. dir must exist.
+// PNG sequences don't have timestamps. Drop frames by the timestamps when making a video from the files, if needed.
+func NewPNGSequenceFrameEncoder(dir string) ebiten.FrameEncoder {
+	return &pngSequenceFrameEncoder{
+		dir: dir,
+	}
+}
+
+// EncodeFrame implements ebiten.FrameEncoder.
+func (p *pngSequenceFrameEncoder) EncodeFrame(img *image.RGBA, t time.Duration) error {
+	f, err := os.Create(filepath.Join(p.dir, fmt.Sprintf("frame_%05d.png", p.index)))
+	if err != nil {
+		return err
+	}
+	p.index++
+
+	if err := png.Encode(f, img); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Close implements ebiten.FrameEncoder.
+func (p *pngSequenceFrameEncoder) Close() error {
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+func newFilledRGBA(w, h int, clr color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i] = clr.R
+		img.Pix[i+1] = clr.G
+		img.Pix[i+2] = clr.B
+		img.Pix[i+3] = clr.A
+	}
+	return img
+}
+
+func TestGIFFrameEncoder(t *testing.T) {
+	clr := color.RGBA{0xff, 0, 0, 0xff}
+
+	var buf bytes.Buffer
+	e := ebitenutil.NewGIFFrameEncoder(&buf)
+	for i := 0; i < 3; i++ {
+		if err := e.EncodeFrame(newFilledRGBA(16, 8, clr), time.Duration(i)*100*time.Millisecond); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(g.Image), 3; got != want {
+		t.Fatalf("GIF frames: got: %d, want: %d", got, want)
+	}
+	for i, d := range g.Delay {
+		if got, want := d, 10; got != want {
+			t.Errorf("GIF delay %d: got: %d, want: %d", i, got, want)
+		}
+	}
+	if got, want := color.RGBAModel.Convert(g.Image[0].At(1, 1)), clr; got != want {
+		t.Errorf("GIF color: got: %v, want: %v", got, want)
+	}
+
+	if err := e.EncodeFrame(newFilledRGBA(16, 8, clr), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.EncodeFrame(newFilledRGBA(8, 8, clr), 0); err == nil {
+		t.Errorf("EncodeFrame must return an error when the frame size is changed")
+	}
+}
+
+func TestPNGSequenceFrameEncoder(t *testing.T) {
+	clr := color.RGBA{0, 0xff, 0, 0xff}
+
+	dir, err := ioutil.TempDir("", "ebitenutil")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	e := ebitenutil.NewPNGSequenceFrameEncoder(dir)
+	for i := 0; i < 2; i++ {
+		if err := e.EncodeFrame(newFilledRGBA(16, 8, clr), 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"frame_00000.png", "frame_00001.png"} {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := color.RGBAModel.Convert(img.At(1, 1)), clr; got != want {
+			t.Errorf("%s: color: got: %v, want: %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// NinePatch is an image split into nine parts by insets, which is useful to render UI panels and buttons in any size.
+//
+// When a NinePatch is drawn, the four corners keep their sizes, the top and bottom edges are stretched horizontally,
+// the left and right edges are stretched vertically, and the center is stretched or tiled to fill the rest.
+// If the destination is smaller than the sum of the insets, the corners are shrunk proportionally.
+//
+// All the nine parts are rendered with one DrawTriangles call as long as the triangles are not too many.
+//
+// The parts are adjacent on the source image. With FilterLinear, the pixels around the borders of the parts might
+// be blended with the neighbor parts.
+type NinePatch struct {
+	// Image is the source image.
+	Image *ebiten.Image
+
+	// Left, Top, Right and Bottom are the insets in pixels from the edges of Image.
+	Left   int
+	Top    int
+	Right  int
+	Bottom int
+
+	// TileCenter indicates whether the center part is repeated at its original size instead of being stretched.
+	// The tiles are aligned to the upper-left corner of the center, and the last tiles are clipped.
+	TileCenter bool
+
+	vertices []ebiten.Vertex
+	indices  []uint16
+}
+
+// Draw renders the nine-patch on dst so that it fills the rectangle at (x, y) with the given size.
+//
+// options.Address and options.FillRule are ignored.
+func (n *NinePatch) Draw(dst *ebiten.Image, x, y, width, height float64, options *ebiten.DrawTrianglesOptions) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	var op ebiten.DrawTrianglesOptions
+	if options != nil {
+		op.ColorM = options.ColorM
+		op.CompositeMode = options.CompositeMode
+		op.Blend = options.Blend
+		op.Filter = options.Filter
+	}
+
+	b := n.Image.Bounds()
+	sxs := [4]float64{
+		float64(b.Min.X),
+		float64(b.Min.X + n.Left),
+		float64(b.Max.X - n.Right),
+		float64(b.Max.X),
+	}
+	sys := [4]float64{
+		float64(b.Min.Y),
+		float64(b.Min.Y + n.Top),
+		float64(b.Max.Y - n.Bottom),
+		float64(b.Max.Y),
+	}
+	l, r := ninePatchInsets(float64(n.Left), float64(n.Right), width)
+	t, bt := ninePatchInsets(float64(n.Top), float64(n.Bottom), height)
+	dxs := [4]float64{x, x + l, x + width - r, x + width}
+	dys := [4]float64{y, y + t, y + height - bt, y + height}
+
+	n.vertices = n.vertices[:0]
+	n.indices = n.indices[:0]
+	for j := 0; j < 3; j++ {
+		for i := 0; i < 3; i++ {
+			if i == 1 && j == 1 && n.TileCenter {
+				n.appendTiles(dst, &op, dxs[1], dys[1], dxs[2], dys[2], sxs[1], sys[1], sxs[2], sys[2])
+				continue
+			}
+			n.appendQuad(dst, &op, dxs[i], dys[j], dxs[i+1], dys[j+1], sxs[i], sys[j], sxs[i+1], sys[j+1])
+		}
+	}
+	n.flush(dst, &op)
+}
+
+// ninePatchInsets returns the insets a and b adjusted so that they fit with the given size.
+func ninePatchInsets(a, b, size float64) (float64, float64) {
+	if a+b <= size {
+		return a, b
+	}
+	s := size / (a + b)
+	return a * s, b * s
+}
+
+// appendTiles appends quads that repeat the source region (sx0, sy0)-(sx1, sy1) over the destination region
+// (dx0, dy0)-(dx1, dy1).
+func (n *NinePatch) appendTiles(dst *ebiten.Image, op *ebiten.DrawTrianglesOptions, dx0, dy0, dx1, dy1, sx0, sy0, sx1, sy1 float64) {
+	sw := sx1 - sx0
+	sh := sy1 - sy0
+	if sw <= 0 || sh <= 0 {
+		return
+	}
+	for ty := dy0; ty < dy1; ty += sh {
+		h := math.Min(sh, dy1-ty)
+		for tx := dx0; tx < dx1; tx += sw {
+			w := math.Min(sw, dx1-tx)
+			n.appendQuad(dst, op, tx, ty, tx+w, ty+h, sx0, sy0, sx0+w, sy0+h)
+		}
+	}
+}
+
+// appendQuad appends a quad that maps the source region (sx0, sy0)-(sx1, sy1) to the destination region
+// (dx0, dy0)-(dx1, dy1).
+func (n *NinePatch) appendQuad(dst *ebiten.Image, op *ebiten.DrawTrianglesOptions, dx0, dy0, dx1, dy1, sx0, sy0, sx1, sy1 float64) {
+	if dx0 >= dx1 || dy0 >= dy1 || sx0 >= sx1 || sy0 >= sy1 {
+		return
+	}
+	if len(n.vertices)+4 > math.MaxUint16+1 || len(n.indices)+6 > ebiten.MaxIndicesNum {
+		n.flush(dst, op)
+	}
+
+	idx := uint16(len(n.vertices))
+	n.vertices = append(n.vertices,
+		ebiten.Vertex{DstX: float32(dx0), DstY: float32(dy0), SrcX: float32(sx0), SrcY: float32(sy0), ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		ebiten.Vertex{DstX: float32(dx1), DstY: float32(dy0), SrcX: float32(sx1), SrcY: float32(sy0), ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		ebiten.Vertex{DstX: float32(dx0), DstY: float32(dy1), SrcX: float32(sx0), SrcY: float32(sy1), ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		ebiten.Vertex{DstX: float32(dx1), DstY: float32(dy1), SrcX: float32(sx1), SrcY: float32(sy1), ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	)
+	n.indices = append(n.indices, idx, idx+1, idx+2, idx+1, idx+2, idx+3)
+}
+
+func (n *NinePatch) flush(dst *ebiten.Image, op *ebiten.DrawTrianglesOptions) {
+	if len(n.indices) == 0 {
+		return
+	}
+	dst.DrawTriangles(n.vertices, n.indices, n.Image, op)
+	n.vertices = n.vertices[:0]
+	n.indices = n.indices[:0]
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+var (
+	ninePatchCorner = color.RGBA{0xff, 0, 0, 0xff}
+	ninePatchEdge   = color.RGBA{0, 0xff, 0, 0xff}
+	ninePatchCenter = color.RGBA{0, 0, 0xff, 0xff}
+	ninePatchStripe = color.RGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+// newNinePatchImage returns a 6x6 image with 2-pixel insets.
+// The center part has a white stripe on its right column.
+func newNinePatchImage() *ebiten.Image {
+	const size = 6
+	pix := make([]byte, 4*size*size)
+	for j := 0; j < size; j++ {
+		for i := 0; i < size; i++ {
+			hi := i < 2 || 4 <= i
+			vi := j < 2 || 4 <= j
+			var clr color.RGBA
+			switch {
+			case hi && vi:
+				clr = ninePatchCorner
+			case hi || vi:
+				clr = ninePatchEdge
+			case i == 3:
+				clr = ninePatchStripe
+			default:
+				clr = ninePatchCenter
+			}
+			idx := 4 * (j*size + i)
+			pix[idx] = clr.R
+			pix[idx+1] = clr.G
+			pix[idx+2] = clr.B
+			pix[idx+3] = clr.A
+		}
+	}
+	img := ebiten.NewImage(size, size)
+	img.ReplacePixels(pix)
+	return img
+}
+
+func TestNinePatch(t *testing.T) {
+	for _, tile := range []bool{false, true} {
+		const w, h = 16, 12
+		dst := ebiten.NewImage(w, h)
+		n := &ebitenutil.NinePatch{
+			Image:      newNinePatchImage(),
+			Left:       2,
+			Top:        2,
+			Right:      2,
+			Bottom:     2,
+			TileCenter: tile,
+		}
+		n.Draw(dst, 0, 0, w, h, nil)
+
+		for j := 0; j < h; j++ {
+			for i := 0; i < w; i++ {
+				hi := i < 2 || w-2 <= i
+				vi := j < 2 || h-2 <= j
+				var want color.RGBA
+				switch {
+				case hi && vi:
+					want = ninePatchCorner
+				case hi || vi:
+					want = ninePatchEdge
+				case !tile:
+					// The 2-pixel center is stretched to 12 pixels. The stripe occupies the right half.
+					if i >= 8 {
+						want = ninePatchStripe
+					} else {
+						want = ninePatchCenter
+					}
+				default:
+					// The 2-pixel center is repeated. The stripe is on every odd column.
+					if (i-2)%2 == 1 {
+						want = ninePatchStripe
+					} else {
+						want = ninePatchCenter
+					}
+				}
+				if got := dst.At(i, j); got != want {
+					t.Errorf("tile: %v, dst.At(%d, %d): got: %v, want: %v", tile, i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestNinePatchSmallerThanInsets(t *testing.T) {
+	const size = 2
+	dst := ebiten.NewImage(size, size)
+	n := &ebitenutil.NinePatch{
+		Image:  newNinePatchImage(),
+		Left:   2,
+		Top:    2,
+		Right:  2,
+		Bottom: 2,
+	}
+	n.Draw(dst, 0, 0, size, size, nil)
+
+	// Only the shrunk corners are rendered.
+	for j := 0; j < size; j++ {
+		for i := 0; i < size; i++ {
+			if got, want := dst.At(i, j), ninePatchCorner; got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
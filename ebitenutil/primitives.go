@@ -0,0 +1,289 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// primitiveBatch is a set of vertices and indices rendered by one DrawTriangles call.
+type primitiveBatch struct {
+	vertices []ebiten.Vertex
+	indices  []uint16
+}
+
+// Primitives is a batch of antialiased primitive shapes: lines with thickness, rectangles, circles and arcs.
+//
+// The shapes are converted into triangles when they are added, and all the shapes are rendered with one
+// DrawTriangles call by Draw as long as the triangles are not too many.
+// The edges of the shapes are antialiased with 1-pixel-wide translucent fringes, so the shapes look smooth even
+// at non-integer positions.
+//
+// A typical usage is to add shapes every frame, call Draw, and then call Reset to reuse the buffers.
+//
+// The zero value for Primitives is an empty batch ready to use.
+type Primitives struct {
+	batches []primitiveBatch
+	n       int
+}
+
+// Reset removes all the added shapes.
+func (p *Primitives) Reset() {
+	for i := range p.batches {
+		p.batches[i].vertices = p.batches[i].vertices[:0]
+		p.batches[i].indices = p.batches[i].indices[:0]
+	}
+	p.n = 0
+}
+
+// Draw renders all the added shapes on dst.
+//
+// Draw doesn't remove the shapes. Call Reset to remove them.
+func (p *Primitives) Draw(dst *ebiten.Image) {
+	op := &ebiten.DrawTrianglesOptions{}
+	for _, b := range p.batches[:p.n] {
+		if len(b.indices) == 0 {
+			continue
+		}
+		dst.DrawTriangles(b.vertices, b.indices, emptySubImage, op)
+	}
+}
+
+// batch returns a batch that has room for the given numbers of vertices and indices.
+func (p *Primitives) batch(vertexNum, indexNum int) *primitiveBatch {
+	if p.n > 0 {
+		b := &p.batches[p.n-1]
+		if len(b.vertices)+vertexNum <= math.MaxUint16+1 && len(b.indices)+indexNum <= ebiten.MaxIndicesNum {
+			return b
+		}
+	}
+	if p.n == len(p.batches) {
+		p.batches = append(p.batches, primitiveBatch{})
+	}
+	p.n++
+	return &p.batches[p.n-1]
+}
+
+func (b *primitiveBatch) appendVertex(x, y float64, r, g, bl, a float32) uint16 {
+	b.vertices = append(b.vertices, ebiten.Vertex{
+		DstX:   float32(x),
+		DstY:   float32(y),
+		SrcX:   1.5,
+		SrcY:   1.5,
+		ColorR: r,
+		ColorG: g,
+		ColorB: bl,
+		ColorA: a,
+	})
+	return uint16(len(b.vertices) - 1)
+}
+
+// colorScale returns the non-premultiplied color scale values of clr.
+func colorScale(clr color.Color) (r, g, b, a float32) {
+	cr, cg, cb, ca := clr.RGBA()
+	if ca == 0 {
+		return 0, 0, 0, 0
+	}
+	return float32(cr) / float32(ca), float32(cg) / float32(ca), float32(cb) / float32(ca), float32(ca) / 0xffff
+}
+
+// circleSegmentNum returns the number of segments to approximate a circle with the radius r
+// so that the error is at most 0.25 pixels.
+func circleSegmentNum(r float64) int {
+	if r <= 0.25 {
+		return 4
+	}
+	n := int(math.Ceil(2 * math.Pi / (2 * math.Acos(1-0.25/r))))
+	if n < 8 {
+		n = 8
+	}
+	return n
+}
+
+// addConvexPolygon adds an antialiased convex polygon.
+func (p *Primitives) addConvexPolygon(xs, ys []float64, clr color.Color) {
+	n := len(xs)
+	if n < 3 {
+		return
+	}
+	r, g, bl, a := colorScale(clr)
+	if a == 0 {
+		return
+	}
+
+	// The polygon's orientation decides the outward direction of the edge normals.
+	var area float64
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		area += xs[i]*ys[j] - xs[j]*ys[i]
+	}
+	if area == 0 {
+		return
+	}
+	sign := 1.0
+	if area < 0 {
+		sign = -1
+	}
+
+	nxs := make([]float64, n)
+	nys := make([]float64, n)
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		dx, dy := xs[j]-xs[i], ys[j]-ys[i]
+		l := math.Hypot(dx, dy)
+		if l == 0 {
+			continue
+		}
+		nxs[i], nys[i] = sign*dy/l, -sign*dx/l
+	}
+
+	b := p.batch(2*n, 3*(n-2)+6*n)
+	base := uint16(len(b.vertices))
+	for i := 0; i < n; i++ {
+		// The offset of a vertex is the average of the adjacent edges' normals, scaled so that the offset edges are
+		// 0.5 pixels away from the original edges.
+		prev := (i + n - 1) % n
+		mx, my := nxs[prev]+nxs[i], nys[prev]+nys[i]
+		if d := mx*nxs[i] + my*nys[i]; d > 0.01 {
+			mx /= d
+			my /= d
+		}
+		mx, my = mx*0.5, my*0.5
+		b.appendVertex(xs[i]-mx, ys[i]-my, r, g, bl, a)
+		b.appendVertex(xs[i]+mx, ys[i]+my, r, g, bl, 0)
+	}
+	for i := 2; i < n; i++ {
+		b.indices = append(b.indices, base, base+uint16(2*(i-1)), base+uint16(2*i))
+	}
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		in0, out0 := base+uint16(2*i), base+uint16(2*i+1)
+		in1, out1 := base+uint16(2*j), base+uint16(2*j+1)
+		b.indices = append(b.indices, in0, out0, in1, out0, in1, out1)
+	}
+}
+
+// AddLine adds a line segment from (x1, y1) to (x2, y2) with the given thickness.
+//
+// A line thinner than 1 pixel is rendered as a 1-pixel line with a lower alpha.
+func (p *Primitives) AddLine(x1, y1, x2, y2, thickness float64, clr color.Color) {
+	l := math.Hypot(x2-x1, y2-y1)
+	if l == 0 {
+		return
+	}
+	clr, thickness = thinColor(clr, thickness)
+	nx, ny := -(y2-y1)/l*thickness/2, (x2-x1)/l*thickness/2
+	p.addConvexPolygon(
+		[]float64{x1 + nx, x2 + nx, x2 - nx, x1 - nx},
+		[]float64{y1 + ny, y2 + ny, y2 - ny, y1 - ny},
+		clr)
+}
+
+// AddRect adds a filled rectangle.
+func (p *Primitives) AddRect(x, y, width, height float64, clr color.Color) {
+	p.addConvexPolygon(
+		[]float64{x, x + width, x + width, x},
+		[]float64{y, y, y + height, y + height},
+		clr)
+}
+
+// AddCircle adds a filled circle whose center is (cx, cy).
+func (p *Primitives) AddCircle(cx, cy, radius float64, clr color.Color) {
+	if radius <= 0 {
+		return
+	}
+	n := circleSegmentNum(radius)
+	xs := make([]float64, n)
+	ys := make([]float64, n)
+	for i := 0; i < n; i++ {
+		sin, cos := math.Sincos(2 * math.Pi * float64(i) / float64(n))
+		xs[i] = cx + radius*cos
+		ys[i] = cy + radius*sin
+	}
+	p.addConvexPolygon(xs, ys, clr)
+}
+
+// AddArc adds an arc of the circle whose center is (cx, cy) with the given thickness.
+// The arc goes clockwise from startAngle to endAngle in radians, where 0 is the right direction.
+// Use 0 and 2π as the angles to add an outline of a circle.
+//
+// An arc thinner than 1 pixel is rendered as a 1-pixel arc with a lower alpha.
+func (p *Primitives) AddArc(cx, cy, radius, startAngle, endAngle, thickness float64, clr color.Color) {
+	if radius <= 0 || startAngle == endAngle {
+		return
+	}
+	clr, thickness = thinColor(clr, thickness)
+	r, g, bl, a := colorScale(clr)
+	if a == 0 {
+		return
+	}
+
+	da := endAngle - startAngle
+	if math.Abs(da) > 2*math.Pi {
+		da = math.Copysign(2*math.Pi, da)
+	}
+	n := int(math.Ceil(float64(circleSegmentNum(radius+thickness/2)) * math.Abs(da) / (2 * math.Pi)))
+	if n < 1 {
+		n = 1
+	}
+
+	// Each sample has 4 vertices from the outside to the inside: an outer fringe, an outer edge, an inner edge
+	// and an inner fringe.
+	rs := [4]float64{
+		radius + thickness/2 + 0.5,
+		radius + thickness/2 - 0.5,
+		math.Max(radius-thickness/2+0.5, 0),
+		math.Max(radius-thickness/2-0.5, 0),
+	}
+	as := [4]float32{0, a, a, 0}
+
+	b := p.batch(4*(n+1), 18*n)
+	base := uint16(len(b.vertices))
+	for i := 0; i <= n; i++ {
+		sin, cos := math.Sincos(startAngle + da*float64(i)/float64(n))
+		for k := 0; k < 4; k++ {
+			b.appendVertex(cx+rs[k]*cos, cy+rs[k]*sin, r, g, bl, as[k])
+		}
+		if i == 0 {
+			continue
+		}
+		i0 := base + uint16(4*(i-1))
+		i1 := base + uint16(4*i)
+		for k := uint16(0); k < 3; k++ {
+			b.indices = append(b.indices, i0+k, i0+k+1, i1+k, i0+k+1, i1+k, i1+k+1)
+		}
+	}
+}
+
+// thinColor returns the color and the thickness to render a line with the given thickness.
+// A line thinner than 1 pixel is rendered as a 1-pixel line with a lower alpha.
+func thinColor(clr color.Color, thickness float64) (color.Color, float64) {
+	if thickness >= 1 {
+		return clr, thickness
+	}
+	if thickness <= 0 {
+		return color.Transparent, 1
+	}
+	r, g, b, a := clr.RGBA()
+	return color.RGBA64{
+		R: uint16(float64(r) * thickness),
+		G: uint16(float64(g) * thickness),
+		B: uint16(float64(b) * thickness),
+		A: uint16(float64(a) * thickness),
+	}, 1
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil_test
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+func TestPrimitivesRect(t *testing.T) {
+	const size = 16
+	dst := ebiten.NewImage(size, size)
+
+	var p ebitenutil.Primitives
+	p.AddRect(4, 4, 8, 8, color.RGBA{0xff, 0, 0, 0xff})
+	p.Draw(dst)
+
+	// The edges are antialiased, so only the pixels away from the edges are checked.
+	for j := 0; j < size; j++ {
+		for i := 0; i < size; i++ {
+			got := dst.At(i, j)
+			var want color.RGBA
+			switch {
+			case 5 <= i && i < 11 && 5 <= j && j < 11:
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			case i < 3 || 13 <= i || j < 3 || 13 <= j:
+				want = color.RGBA{}
+			default:
+				continue
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestPrimitivesCircle(t *testing.T) {
+	const size = 16
+	dst := ebiten.NewImage(size, size)
+
+	var p ebitenutil.Primitives
+	p.AddCircle(8, 8, 5, color.White)
+	p.Draw(dst)
+
+	if got, want := dst.At(8, 8), (color.RGBA{0xff, 0xff, 0xff, 0xff}); got != want {
+		t.Errorf("dst.At(8, 8): got: %v, want: %v", got, want)
+	}
+	if got, want := dst.At(0, 0), (color.RGBA{}); got != want {
+		t.Errorf("dst.At(0, 0): got: %v, want: %v", got, want)
+	}
+
+	// The edge is antialiased: a pixel on the circle is partially covered.
+	var partial bool
+	for j := 0; j < size; j++ {
+		for i := 0; i < size; i++ {
+			d := math.Hypot(float64(i)+0.5-8, float64(j)+0.5-8)
+			if math.Abs(d-5) > 0.3 {
+				continue
+			}
+			if a := dst.At(i, j).(color.RGBA).A; 0 < a && a < 0xff {
+				partial = true
+			}
+		}
+	}
+	if !partial {
+		t.Errorf("the circle's edge must be antialiased")
+	}
+}
+
+func TestPrimitivesBatches(t *testing.T) {
+	const size = 16
+	dst := ebiten.NewImage(size, size)
+
+	// Add so many shapes that the vertices don't fit into one batch.
+	var p ebitenutil.Primitives
+	for i := 0; i < 20000; i++ {
+		p.AddRect(1, 1, 2, 2, color.White)
+	}
+	p.AddLine(8, 12, 14, 12, 4, color.RGBA{0, 0xff, 0, 0xff})
+	p.Draw(dst)
+
+	if got, want := dst.At(2, 2), (color.RGBA{0xff, 0xff, 0xff, 0xff}); got != want {
+		t.Errorf("dst.At(2, 2): got: %v, want: %v", got, want)
+	}
+	if got, want := dst.At(10, 11), (color.RGBA{0, 0xff, 0, 0xff}); got != want {
+		t.Errorf("dst.At(10, 11): got: %v, want: %v", got, want)
+	}
+
+	p.Reset()
+	dst.Clear()
+	p.Draw(dst)
+	if got, want := dst.At(2, 2), (color.RGBA{}); got != want {
+		t.Errorf("dst.At(2, 2) after Reset: got: %v, want: %v", got, want)
+	}
+}
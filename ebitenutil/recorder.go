@@ -0,0 +1,222 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	xdraw "golang.org/x/image/draw"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// RecordingFormat represents a file format of a recorded animation.
+type RecordingFormat int
+
+const (
+	// RecordingFormatGIF represents the animated GIF format.
+	RecordingFormatGIF RecordingFormat = iota
+
+	// RecordingFormatAPNG represents the animated PNG format.
+	RecordingFormatAPNG
+)
+
+// RecorderOptions represents options for NewRecorder.
+//
+// Recorder keeps all the frames in the last Duration as uncompressed RGBA images until Encode is called.
+// The memory usage is about 4 * (frame width * Scale) * (frame height * Scale) * (recorded frames per second) * Duration in bytes.
+// For example, recording a 1280x720 screen at 60 FPS for 5 seconds with the default options takes about 1.1 GB.
+// Adjust Scale, FrameSkip and Duration to keep the memory usage reasonable.
+type RecorderOptions struct {
+	// Duration is the length of the recording.
+	// Only the frames captured in the last Duration are kept.
+	//
+	// The default (zero) value is 5 seconds.
+	Duration time.Duration
+
+	// Scale is the scale of the recorded frames against the captured images.
+	// A value less than 1 makes the recorded animation smaller.
+	//
+	// The default (zero) value is 1.
+	Scale float64
+
+	// FrameSkip is the number of frames skipped between two recorded frames.
+	// For example, if FrameSkip is 1, every other frame is recorded.
+	//
+	// The default (zero) value is 0, which means all the frames are recorded.
+	FrameSkip int
+
+	// Format is the file format of the recorded animation.
+	//
+	// The default (zero) value is RecordingFormatGIF.
+	Format RecordingFormat
+}
+
+type recordedFrame struct {
+	image *image.RGBA
+	time  time.Duration
+}
+
+// Recorder records the recent frames and encodes them as an animation.
+//
+// Recorder is an ebiten.FrameEncoder that keeps the frames instead of encoding them immediately.
+// Pass a Recorder to ebiten.StartRecording to record the screen, and call Encode e.g. when the player presses a key.
+//
+//	r := ebitenutil.NewRecorder(nil)
+//	if err := ebiten.StartRecording(r); err != nil {
+//		return err
+//	}
+//
+//	// Later, e.g. when the player presses a key:
+//	if err := <-r.Encode(w); err != nil {
+//		return err
+//	}
+//
+// Recorder is intended to be used to make short gameplay clips.
+type Recorder struct {
+	options RecorderOptions
+
+	frames  []recordedFrame
+	counter int
+
+	m sync.Mutex
+}
+
+// NewRecorder returns a new Recorder.
+//
+// If options is nil, the default options are used.
+func NewRecorder(options *RecorderOptions) *Recorder {
+	r := &Recorder{}
+	if options != nil {
+		r.options = *options
+	}
+	if r.options.Duration <= 0 {
+		r.options.Duration = 5 * time.Second
+	}
+	if r.options.Scale <= 0 {
+		r.options.Scale = 1
+	}
+	if r.options.FrameSkip < 0 {
+		panic(fmt.Sprintf("ebitenutil: FrameSkip must be non-negative but %d", r.options.FrameSkip))
+	}
+	return r
+}
+
+// EncodeFrame implements ebiten.FrameEncoder.
+//
+// EncodeFrame keeps the frame, scaled by RecorderOptions's Scale, until Encode is called.
+// If t is earlier than the last kept frame, e.g. when the Recorder is passed to ebiten.StartRecording again,
+// the frames kept so far are discarded.
+func (r *Recorder) EncodeFrame(img *image.RGBA, t time.Duration) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	c := r.counter
+	r.counter++
+	if c%(r.options.FrameSkip+1) != 0 {
+		return nil
+	}
+
+	if r.options.Scale != 1 {
+		sw, sh := img.Bounds().Dx(), img.Bounds().Dy()
+		w := int(math.Ceil(float64(sw) * r.options.Scale))
+		h := int(math.Ceil(float64(sh) * r.options.Scale))
+		if w <= 0 || h <= 0 {
+			return nil
+		}
+		dst := image.NewRGBA(image.Rect(0, 0, w, h))
+		// Both image.RGBA images have alpha-premultiplied colors, so they can be scaled as they are.
+		xdraw.BiLinear.Scale(dst, dst.Bounds(), img, img.Bounds(), xdraw.Src, nil)
+		img = dst
+	}
+
+	if n := len(r.frames); n > 0 && t < r.frames[n-1].time {
+		r.frames = nil
+	}
+	r.frames = append(r.frames, recordedFrame{
+		image: img,
+		time:  t,
+	})
+
+	// Drop the frames older than the duration.
+	var n int
+	for n < len(r.frames) && t-r.frames[n].time > r.options.Duration {
+		n++
+	}
+	if n > 0 {
+		r.frames = append(r.frames[:0], r.frames[n:]...)
+	}
+	return nil
+}
+
+// Close implements ebiten.FrameEncoder.
+//
+// Close does nothing. The frames are kept so that Encode can be called after ebiten.StopRecording.
+func (r *Recorder) Close() error {
+	return nil
+}
+
+// Reset discards all the recorded frames.
+func (r *Recorder) Reset() {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.frames = nil
+	r.counter = 0
+}
+
+// Encode encodes the recorded frames and writes the result to w.
+//
+// Encoding is done on another goroutine, and the returned channel receives the result when the encoding finishes.
+// The game can continue recording frames during the encoding.
+// The frames recorded so far are discarded from the recorder.
+func (r *Recorder) Encode(w io.Writer) <-chan error {
+	r.m.Lock()
+	frames := r.frames
+	r.frames = nil
+	r.counter = 0
+	format := r.options.Format
+	r.m.Unlock()
+
+	ch := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		ch <- encodeFrames(w, frames, format)
+	}()
+	return ch
+}
+
+func encodeFrames(w io.Writer, frames []recordedFrame, format RecordingFormat) error {
+	var e ebiten.FrameEncoder
+	switch format {
+	case RecordingFormatGIF:
+		e = NewGIFFrameEncoder(w)
+	case RecordingFormatAPNG:
+		e = NewAPNGFrameEncoder(w)
+	default:
+		return fmt.Errorf("ebitenutil: invalid recording format: %d", format)
+	}
+
+	for _, f := range frames {
+		if err := e.EncodeFrame(f.image, f.time); err != nil {
+			return err
+		}
+	}
+	return e.Close()
+}
@@ -0,0 +1,209 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package ebitenutil_test
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	t "github.com/hajimehoshi/ebiten/v2/internal/testing"
+)
+
+func TestMain(m *testing.M) {
+	t.MainWithRunLoop(m)
+}
+
+func TestFrameDelays(t *testing.T) {
+	const fps = 60
+	start := time.Now()
+	times := make([]time.Time, fps)
+	for i := range times {
+		times[i] = start.Add(time.Duration(i) * time.Second / fps)
+	}
+
+	cases := []struct {
+		name     string
+		unit     time.Duration
+		minDelay int
+		total    int
+	}{
+		{
+			name:     "centiseconds",
+			unit:     10 * time.Millisecond,
+			minDelay: 2,
+			total:    100,
+		},
+		{
+			name:     "milliseconds",
+			unit:     time.Millisecond,
+			minDelay: 11,
+			total:    1000,
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			indices, delays := ebitenutil.FrameDelays(times, c.unit, c.minDelay)
+			if len(indices) != len(delays) {
+				t.Fatalf("len(indices) (%d) and len(delays) (%d) must match", len(indices), len(delays))
+			}
+			if indices[0] != 0 {
+				t.Errorf("indices[0]: got: %d, want: 0", indices[0])
+			}
+
+			var sum int
+			for i, d := range delays {
+				if d < c.minDelay {
+					t.Errorf("delays[%d]: got: %d, want: >= %d", i, d, c.minDelay)
+				}
+				if i < len(delays)-1 {
+					sum += d
+				}
+			}
+
+			// The delays must not accumulate rounding errors.
+			last := times[indices[len(indices)-1]]
+			if got, want := sum, int((last.Sub(start)+c.unit/2)/c.unit); got != want {
+				t.Errorf("sum of the delays: got: %d, want: %d", got, want)
+			}
+			if got, want := sum+delays[len(delays)-1], c.total; got < want-c.minDelay || got > want+c.minDelay {
+				t.Errorf("total delay: got: %d, want: %d", got, want)
+			}
+		})
+	}
+}
+
+func TestFrameDelaysDropShortFrames(t *testing.T) {
+	start := time.Now()
+	times := []time.Time{
+		start,
+		start.Add(5 * time.Millisecond),
+		start.Add(10 * time.Millisecond),
+		start.Add(40 * time.Millisecond),
+	}
+	indices, delays := ebitenutil.FrameDelays(times, 10*time.Millisecond, 2)
+	wantIndices := []int{0, 3}
+	wantDelays := []int{4, 4}
+	if len(indices) != len(wantIndices) {
+		t.Fatalf("indices: got: %v, want: %v", indices, wantIndices)
+	}
+	for i := range indices {
+		if indices[i] != wantIndices[i] || delays[i] != wantDelays[i] {
+			t.Fatalf("indices, delays: got: %v, %v, want: %v, %v", indices, delays, wantIndices, wantDelays)
+		}
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	clr := color.RGBA{0xff, 0, 0, 0xff}
+	src := newFilledRGBA(16, 8, clr)
+
+	for _, format := range []ebitenutil.RecordingFormat{ebitenutil.RecordingFormatGIF, ebitenutil.RecordingFormatAPNG} {
+		r := ebitenutil.NewRecorder(&ebitenutil.RecorderOptions{
+			Scale:  0.5,
+			Format: format,
+		})
+		if err := r.EncodeFrame(src, 0); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := <-r.Encode(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		switch format {
+		case ebitenutil.RecordingFormatGIF:
+			g, err := gif.DecodeAll(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(g.Image), 1; got != want {
+				t.Fatalf("GIF frames: got: %d, want: %d", got, want)
+			}
+			if got, want := g.Image[0].Bounds().Size(), src.Bounds().Size().Div(2); got != want {
+				t.Errorf("GIF size: got: %v, want: %v", got, want)
+			}
+			if got, want := color.RGBAModel.Convert(g.Image[0].At(1, 1)), clr; got != want {
+				t.Errorf("GIF color: got: %v, want: %v", got, want)
+			}
+		case ebitenutil.RecordingFormatAPNG:
+			img, err := png.Decode(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := img.Bounds().Size(), src.Bounds().Size().Div(2); got != want {
+				t.Errorf("APNG size: got: %v, want: %v", got, want)
+			}
+			if got, want := color.RGBAModel.Convert(img.At(1, 1)), clr; got != want {
+				t.Errorf("APNG color: got: %v, want: %v", got, want)
+			}
+		}
+
+		if err := <-r.Encode(&buf); err == nil {
+			t.Errorf("Encode must return an error when no frames are recorded")
+		}
+	}
+}
+
+func TestRecorderDuration(t *testing.T) {
+	r := ebitenutil.NewRecorder(&ebitenutil.RecorderOptions{
+		Duration: time.Second,
+	})
+
+	// firstColor encodes the recorded frames and returns the number of the frames and the color of the first frame.
+	firstColor := func() (int, color.Color) {
+		var buf bytes.Buffer
+		if err := <-r.Encode(&buf); err != nil {
+			t.Fatal(err)
+		}
+		g, err := gif.DecodeAll(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(g.Image), color.RGBAModel.Convert(g.Image[0].At(1, 1))
+	}
+
+	clrs := []color.RGBA{
+		{0xff, 0, 0, 0xff},
+		{0, 0xff, 0, 0xff},
+		{0, 0, 0xff, 0xff},
+		{0xff, 0xff, 0xff, 0xff},
+	}
+	for i, clr := range clrs {
+		if err := r.EncodeFrame(newFilledRGBA(16, 8, clr), time.Duration(i)*time.Second); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Only the frames in the last second are kept.
+	if n, clr := firstColor(); n != 2 || clr != clrs[2] {
+		t.Errorf("got: %d frames starting with %v, want: 2 frames starting with %v", n, clr, clrs[2])
+	}
+
+	// A new recording with the same recorder discards the frames of the previous recording.
+	if err := r.EncodeFrame(newFilledRGBA(16, 8, clrs[0]), 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.EncodeFrame(newFilledRGBA(16, 8, clrs[1]), 0); err != nil {
+		t.Fatal(err)
+	}
+	if n, clr := firstColor(); n != 1 || clr != clrs[1] {
+		t.Errorf("got: %d frames starting with %v, want: 1 frame starting with %v", n, clr, clrs[1])
+	}
+}
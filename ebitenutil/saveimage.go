@@ -0,0 +1,72 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image"
+	"image/png"
+	"io"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// SaveImage encodes the pixels of img as a PNG image and writes it to w.
+//
+// SaveImage is useful to inspect offscreens and render targets for debugging.
+//
+// SaveImage reads the pixels with ReadPixels. All the draw commands to img before SaveImage are reflected, and
+// SaveImage might be slow as it waits for GPU. The alpha-premultiplied pixels are converted to non-premultiplied
+// ones for PNG.
+//
+// SaveImage works on a sub-image. The result is always at (0, 0) regardless of the sub-image's bounds.
+//
+// SaveImage can't be called outside the main loop (ebiten.Run's updating function) starts.
+func SaveImage(img *ebiten.Image, w io.Writer) error {
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	img.ReadPixels(rgba.Pix)
+	return png.Encode(w, toNRGBA(rgba))
+}
+
+// toNRGBA converts the alpha-premultiplied image to a non-premultiplied image.
+func toNRGBA(img *image.RGBA) *image.NRGBA {
+	dst := image.NewNRGBA(img.Rect)
+	for i := 0; i < len(img.Pix); i += 4 {
+		r, g, b, a := img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3]
+		switch a {
+		case 0:
+			continue
+		case 0xff:
+			dst.Pix[i] = r
+			dst.Pix[i+1] = g
+			dst.Pix[i+2] = b
+		default:
+			dst.Pix[i] = unpremultiply(r, a)
+			dst.Pix[i+1] = unpremultiply(g, a)
+			dst.Pix[i+2] = unpremultiply(b, a)
+		}
+		dst.Pix[i+3] = a
+	}
+	return dst
+}
+
+// unpremultiply returns the non-premultiplied value of the color value c with the alpha a, rounded to the nearest.
+func unpremultiply(c, a uint8) uint8 {
+	// A premultiplied color value must not exceed the alpha, but clamp it just in case.
+	if c >= a {
+		return 0xff
+	}
+	return uint8((uint32(c)*0xff + uint32(a)/2) / uint32(a))
+}
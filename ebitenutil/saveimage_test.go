@@ -0,0 +1,59 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+func TestSaveImage(t *testing.T) {
+	const w, h = 16, 8
+	img := ebiten.NewImage(w, h)
+	// An opaque color and a translucent color in alpha-premultiplied form.
+	img.Fill(color.RGBA{0xff, 0, 0, 0xff})
+	img.SubImage(image.Rect(8, 0, 16, 8)).(*ebiten.Image).Fill(color.RGBA{0x40, 0x20, 0, 0x80})
+
+	var buf bytes.Buffer
+	if err := ebitenutil.SaveImage(img.SubImage(image.Rect(4, 2, 16, 8)).(*ebiten.Image), &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := got.Bounds(), image.Rect(0, 0, 12, 6); got != want {
+		t.Fatalf("bounds: got: %v, want: %v", got, want)
+	}
+	for j := 0; j < 6; j++ {
+		for i := 0; i < 12; i++ {
+			got := color.NRGBAModel.Convert(got.At(i, j)).(color.NRGBA)
+			want := color.NRGBA{0xff, 0, 0, 0xff}
+			if i >= 4 {
+				want = color.NRGBA{0x80, 0x40, 0, 0x80}
+			}
+			if got != want {
+				t.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
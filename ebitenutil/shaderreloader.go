@@ -0,0 +1,140 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !android && !ios && !js
+// +build !android,!ios,!js
+
+package ebitenutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ReloadableShader is a shader that is reloaded when its source files are modified.
+//
+// ReloadableShader is intended for development: edit the Kage source files while the game is running,
+// and the changes are reflected without restarting the game.
+type ReloadableShader struct {
+	paths  []string
+	stamps []fileStamp
+	shader *ebiten.Shader
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewReloadableShader creates a new ReloadableShader from the Kage source files at the given paths.
+//
+// The sources are compiled in the same way as ebiten.NewShaderFromSources.
+func NewReloadableShader(paths ...string) (*ReloadableShader, error) {
+	r := &ReloadableShader{
+		paths: append([]string(nil), paths...),
+	}
+	stamps, err := r.fileStamps()
+	if err != nil {
+		return nil, err
+	}
+	srcs, err := r.readSources()
+	if err != nil {
+		return nil, err
+	}
+	s, err := ebiten.NewShaderFromSources(srcs...)
+	if err != nil {
+		return nil, err
+	}
+	r.stamps = stamps
+	r.shader = s
+	return r, nil
+}
+
+// Shader returns the shader.
+//
+// Shader returns the same object even after the shader is reloaded.
+func (r *ReloadableShader) Shader() *ebiten.Shader {
+	return r.shader
+}
+
+// Update checks the source files and reloads the shader if any of them is modified.
+// Update is intended to be called at the game's Update.
+//
+// Update reports whether the shader is reloaded.
+// If reloading fails, Update returns the error and the shader keeps the current program.
+// A failed reload is not retried until the source files are modified again.
+func (r *ReloadableShader) Update() (bool, error) {
+	stamps, err := r.fileStamps()
+	if err != nil {
+		return false, err
+	}
+	if !r.isModified(stamps) {
+		return false, nil
+	}
+	r.stamps = stamps
+
+	srcs, err := r.readSources()
+	if err != nil {
+		return false, err
+	}
+	if err := r.shader.Reload(srcs...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Dispose disposes the shader.
+func (r *ReloadableShader) Dispose() {
+	r.shader.Dispose()
+}
+
+func (r *ReloadableShader) isModified(stamps []fileStamp) bool {
+	for i, s := range stamps {
+		if !s.modTime.Equal(r.stamps[i].modTime) || s.size != r.stamps[i].size {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *ReloadableShader) fileStamps() ([]fileStamp, error) {
+	stamps := make([]fileStamp, len(r.paths))
+	for i, path := range r.paths {
+		fi, err := os.Stat(filepath.FromSlash(path))
+		if err != nil {
+			return nil, err
+		}
+		stamps[i] = fileStamp{
+			modTime: fi.ModTime(),
+			size:    fi.Size(),
+		}
+	}
+	return stamps, nil
+}
+
+func (r *ReloadableShader) readSources() ([][]byte, error) {
+	srcs := make([][]byte, len(r.paths))
+	for i, path := range r.paths {
+		src, err := ioutil.ReadFile(filepath.FromSlash(path))
+		if err != nil {
+			return nil, err
+		}
+		srcs[i] = src
+	}
+	return srcs, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !android && !ios && !js
+// +build !android,!ios,!js
+
+package ebitenutil_test
+
+import (
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+func TestReloadableShader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ebitenutil")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "shader.go")
+	writeSrc := func(src string, modTime time.Time) {
+		if err := ioutil.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		// Set the modification time explicitly as the file system's time resolution might be coarse.
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	writeSrc(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return vec4(1, 0, 0, 1)
+}
+`, now)
+
+	r, err := ebitenutil.NewReloadableShader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Dispose()
+	s := r.Shader()
+
+	draw := func() color.RGBA {
+		dst := ebiten.NewImage(1, 1)
+		defer dst.Dispose()
+		dst.DrawRectShader(1, 1, r.Shader(), nil)
+		return dst.At(0, 0).(color.RGBA)
+	}
+
+	if reloaded, err := r.Update(); err != nil {
+		t.Fatal(err)
+	} else if reloaded {
+		t.Errorf("reloaded: got: true, want: false")
+	}
+	if got, want := draw(), (color.RGBA{0xff, 0, 0, 0xff}); got != want {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+
+	writeSrc(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return vec4(0, 1, 0, 1)
+}
+`, now.Add(time.Second))
+	if reloaded, err := r.Update(); err != nil {
+		t.Fatal(err)
+	} else if !reloaded {
+		t.Errorf("reloaded: got: false, want: true")
+	}
+	if r.Shader() != s {
+		t.Errorf("Shader must return the same object after reloading")
+	}
+	if got, want := draw(), (color.RGBA{0, 0xff, 0, 0xff}); got != want {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+
+	writeSrc(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return foo
+}
+`, now.Add(2*time.Second))
+	if _, err := r.Update(); err == nil {
+		t.Errorf("error must be non-nil but was nil")
+	}
+	// The failed reload is not retried until the file is modified again.
+	if _, err := r.Update(); err != nil {
+		t.Error(err)
+	}
+	if got, want := draw(), (color.RGBA{0, 0xff, 0, 0xff}); got != want {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
@@ -34,6 +34,7 @@ func init() {
 // DrawLine draws a line segment on the given destination dst.
 //
 // DrawLine is intended to be used mainly for debugging or prototyping purpose.
+// DrawLine renders a whole-pixel line without antialiasing. Use Primitives for antialiased lines.
 func DrawLine(dst *ebiten.Image, x1, y1, x2, y2 float64, clr color.Color) {
 	length := math.Hypot(x2-x1, y2-y1)
 
@@ -50,6 +51,7 @@ func DrawLine(dst *ebiten.Image, x1, y1, x2, y2 float64, clr color.Color) {
 // DrawRect draws a rectangle on the given destination dst.
 //
 // DrawRect is intended to be used mainly for debugging or prototyping purpose.
+// DrawRect renders a rectangle without antialiasing. Use Primitives for antialiased shapes.
 func DrawRect(dst *ebiten.Image, x, y, width, height float64, clr color.Color) {
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Scale(width, height)
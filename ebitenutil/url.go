@@ -25,6 +25,10 @@ import (
 //
 // Image decoders must be imported when using NewImageFromURL. For example,
 // if you want to load a PNG image, you'd need to add `_ "image/png"` to the import section.
+//
+// NewImageFromURL blocks until the image is downloaded and decoded.
+//
+// Deprecated: as of v2.3. Use AssetLoader with HTTPAssetOpener instead.
 func NewImageFromURL(url string) (*ebiten.Image, error) {
 	res, err := http.Get(url)
 	if err != nil {
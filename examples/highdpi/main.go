@@ -27,30 +27,21 @@ import (
 )
 
 type Game struct {
-	highDPIImageCh chan *ebiten.Image
-	highDPIImage   *ebiten.Image
+	loader       *ebitenutil.AssetLoader
+	highDPIImage *ebitenutil.Asset
 }
 
 func NewGame() *Game {
-	g := &Game{
-		highDPIImageCh: make(chan *ebiten.Image),
-	}
-
 	// Licensed under Public Domain
 	// https://commons.wikimedia.org/wiki/File:As08-16-2593.jpg
-	const url = "https://upload.wikimedia.org/wikipedia/commons/1/1f/As08-16-2593.jpg"
+	const baseURL = "https://upload.wikimedia.org/wikipedia/commons/1/1f/"
 
 	// Load the image asynchronously.
-	go func() {
-		img, err := ebitenutil.NewImageFromURL(url)
-		if err != nil {
-			log.Fatal(err)
-		}
-		g.highDPIImageCh <- img
-		close(g.highDPIImageCh)
-	}()
-
-	return g
+	loader := ebitenutil.NewAssetLoader(ebitenutil.HTTPAssetOpener(baseURL))
+	return &Game{
+		loader:       loader,
+		highDPIImage: loader.LoadImage("As08-16-2593.jpg"),
+	}
 }
 
 func (g *Game) Update() error {
@@ -58,29 +49,20 @@ func (g *Game) Update() error {
 	// Add a mode to adjust the screen size along with the current device scale (#705).
 	// Now this example uses the device scale initialized at the beginning of this application.
 
-	if g.highDPIImage != nil {
-		return nil
-	}
-
-	// Use select and 'default' clause for non-blocking receiving.
-	select {
-	case img := <-g.highDPIImageCh:
-		g.highDPIImage = img
-	default:
-	}
-
-	return nil
+	// Update finalizes the image on the game's goroutine when the download finishes.
+	return g.loader.Update()
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
-	if g.highDPIImage == nil {
+	img := g.highDPIImage.Image()
+	if img == nil {
 		ebitenutil.DebugPrint(screen, "Loading the image...")
 		return
 	}
 
 	sw, sh := screen.Size()
 
-	w, h := g.highDPIImage.Size()
+	w, h := img.Size()
 	op := &ebiten.DrawImageOptions{}
 
 	// Move the images's center to the upper left corner.
@@ -98,7 +80,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	op.GeoM.Translate(float64(sw)/2, float64(sh)/2)
 
 	op.Filter = ebiten.FilterLinear
-	screen.DrawImage(g.highDPIImage, op)
+	screen.DrawImage(img, op)
 
 	ebitenutil.DebugPrint(screen, fmt.Sprintf("(Init) Device Scale Ratio: %0.2f", scale))
 }
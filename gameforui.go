@@ -15,21 +15,45 @@
 package ebiten
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"image"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/ui"
 )
 
 type gameForUI struct {
 	game      Game
+	ctx       context.Context
 	offscreen *Image
 	screen    *Image
+
+	// The parameters to render the offscreen onto the screen at the latest frame.
+	screenScaleX float64
+	screenScaleY float64
+	offsetX      float64
+	offsetY      float64
+
+	// recordingBuffer is the image to render the final screen for recording.
+	recordingBuffer *Image
+
+	deviceScaleFactor float64
+
+	// cpuTime is the time spent by the game's Update and Draw in the current frame.
+	cpuTime time.Duration
 }
 
+// theGameForUI is the current game. theGameForUI is accessed only from the game's goroutine.
+var theGameForUI *gameForUI
+
 func newGameForUI(game Game) *gameForUI {
-	return &gameForUI{
+	theGameForUI = &gameForUI{
 		game: game,
 	}
+	return theGameForUI
 }
 
 func (c *gameForUI) Layout(outsideWidth, outsideHeight float64, deviceScaleFactor float64) (int, int) {
@@ -38,6 +62,7 @@ func (c *gameForUI) Layout(outsideWidth, outsideHeight float64, deviceScaleFacto
 		panic("ebiten: Layout must return positive numbers")
 	}
 
+	c.deviceScaleFactor = deviceScaleFactor
 	sw, sh := int(outsideWidth*deviceScaleFactor), int(outsideHeight*deviceScaleFactor)
 	if c.screen != nil {
 		if w, h := c.screen.Size(); w != sw || h != sh {
@@ -69,10 +94,47 @@ func (c *gameForUI) Layout(outsideWidth, outsideHeight float64, deviceScaleFacto
 }
 
 func (c *gameForUI) Update() error {
+	if c.ctx != nil && c.ctx.Err() != nil {
+		return ui.RegularTermination
+	}
+	t := time.Now()
+	defer func() {
+		c.cpuTime += time.Since(t)
+	}()
 	return c.game.Update()
 }
 
-func (c *gameForUI) Draw(screenScale float64, offsetX, offsetY float64, needsClearingScreen bool, framebufferYDirection graphicsdriver.YDirection, clearScreenEveryFrame bool) error {
+func (c *gameForUI) OnSuspend() {
+	if h, ok := c.game.(SuspendHandler); ok {
+		h.OnSuspend()
+	}
+}
+
+func (c *gameForUI) OnResume() {
+	if h, ok := c.game.(SuspendHandler); ok {
+		h.OnResume()
+	}
+}
+
+func (c *gameForUI) OnFocusChanged(focused bool) {
+	if h, ok := c.game.(FocusHandler); ok {
+		h.OnFocusChanged(focused)
+	}
+}
+
+func (c *gameForUI) OnWindowResized(width, height int) {
+	if h, ok := c.game.(WindowResizeHandler); ok {
+		h.OnWindowResized(width, height)
+	}
+}
+
+func (c *gameForUI) OnWindowMaximizedChanged(maximized bool) {
+	if h, ok := c.game.(WindowMaximizeHandler); ok {
+		h.OnWindowMaximizedChanged(maximized)
+	}
+}
+
+func (c *gameForUI) Draw(screenScaleX, screenScaleY float64, offsetX, offsetY float64, needsClearingScreen bool, framebufferYDirection graphicsdriver.YDirection, clearScreenEveryFrame bool) error {
 	c.offscreen.mipmap.SetVolatile(clearScreenEveryFrame)
 
 	// Even though updateCount == 0, the offscreen is cleared and Draw is called.
@@ -81,37 +143,86 @@ func (c *gameForUI) Draw(screenScale float64, offsetX, offsetY float64, needsCle
 	if clearScreenEveryFrame {
 		c.offscreen.Clear()
 	}
+	t := time.Now()
 	c.game.Draw(c.offscreen)
+	cpuTime := c.cpuTime + time.Since(t)
+	c.cpuTime = 0
 
 	if needsClearingScreen {
 		// This clear is needed for fullscreen mode or some mobile platforms (#622).
 		c.screen.Clear()
 	}
 
+	c.screenScaleX, c.screenScaleY = screenScaleX, screenScaleY
+	c.offsetX, c.offsetY = offsetX, offsetY
+	c.drawFinalScreen(c.screen, framebufferYDirection)
+	theDebugOverlay.draw(c.screen, c.deviceScaleFactor, framebufferYDirection, cpuTime)
+	c.recordFrame()
+	return nil
+}
+
+// drawFinalScreen renders the offscreen onto dst with the screen shader or the screen filter.
+func (c *gameForUI) drawFinalScreen(dst *Image, framebufferYDirection graphicsdriver.YDirection) {
 	op := &DrawImageOptions{}
 
-	s := screenScale
+	sx, sy := c.screenScaleX, c.screenScaleY
 	switch framebufferYDirection {
 	case graphicsdriver.Upward:
-		op.GeoM.Scale(s, -s)
+		op.GeoM.Scale(sx, -sy)
 		_, h := c.offscreen.Size()
-		op.GeoM.Translate(0, float64(h)*s)
+		op.GeoM.Translate(0, float64(h)*sy)
 	case graphicsdriver.Downward:
-		op.GeoM.Scale(s, s)
+		op.GeoM.Scale(sx, sy)
 	default:
 		panic(fmt.Sprintf("ebiten: invalid v-direction: %d", framebufferYDirection))
 	}
 
-	op.GeoM.Translate(offsetX, offsetY)
+	op.GeoM.Translate(c.offsetX, c.offsetY)
 	op.CompositeMode = CompositeModeCopy
 
+	if shader, uniforms := currentScreenShader(); shader != nil {
+		w, h := c.offscreen.Size()
+		sop := &DrawRectShaderOptions{}
+		sop.GeoM = op.GeoM
+		sop.CompositeMode = CompositeModeCopy
+		sop.Uniforms = uniforms
+		sop.Images[0] = c.offscreen
+		dst.DrawRectShader(w, h, shader, sop)
+		return
+	}
+
 	// filterScreen works with >=1 scale, but does not well with <1 scale.
 	// Use regular FilterLinear instead so far (#669).
-	if s >= 1 {
+	// filterScreen assumes the same scales for X and Y, which is not true with ScreenScaleModeStretch.
+	if sx >= 1 && sx == sy {
 		op.Filter = filterScreen
 	} else {
 		op.Filter = FilterLinear
 	}
-	c.screen.DrawImage(c.offscreen, op)
-	return nil
+	dst.DrawImage(c.offscreen, op)
+}
+
+// screenshot returns the final screen image at the latest frame.
+func (c *gameForUI) screenshot() (*image.RGBA, error) {
+	if c.screen == nil || c.offscreen == nil {
+		return nil, errors.New("ebiten: the screen is not ready yet")
+	}
+
+	// Render the final screen onto a separate image instead of reading the screen framebuffer,
+	// as the screen framebuffer's content is not reliable after being presented.
+	w, h := c.screen.Size()
+	img := NewImage(w, h)
+	defer img.Dispose()
+	c.drawFinalScreen(img, graphicsdriver.Downward)
+
+	rgba := image.NewRGBA(image.Rect(0, 0, w, h))
+	if err := img.mipmap.ReadPixels(rgba.Pix, 0, 0, w, h); err != nil {
+		return nil, err
+	}
+
+	// The window is opaque unless the screen is transparent.
+	if !IsScreenTransparent() {
+		fillAlpha(rgba.Pix)
+	}
+	return rgba, nil
 }
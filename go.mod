@@ -15,5 +15,6 @@ require (
 	golang.org/x/mobile v0.0.0-20220104184238-4a8be17bd2e3
 	golang.org/x/sync v0.0.0-20210220032951-036812b2e83c
 	golang.org/x/sys v0.0.0-20220128215802-99c3d69c2c27
+	golang.org/x/text v0.3.7
 	golang.org/x/tools v0.1.8-0.20211022200916-316ba0b74098
 )
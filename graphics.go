@@ -16,6 +16,8 @@ package ebiten
 
 import (
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/mipmap"
+	"github.com/hajimehoshi/ebiten/v2/internal/ui"
 )
 
 // Filter represents the type of texture filter to be used when an image is maginified or minified.
@@ -34,6 +36,28 @@ const (
 	filterScreen Filter = Filter(graphicsdriver.FilterScreen)
 )
 
+// FilterQuality represents the quality of FilterLinear when an image is minified.
+//
+// A minified image is sampled from its mipmap images, which are generated automatically.
+// FilterQuality is ignored unless the filter is FilterLinear.
+type FilterQuality int
+
+const (
+	// FilterQualityDefault uses one mipmap level chosen for the whole draw call.
+	// This is the fastest, but the switch between levels can be visible when the scale changes gradually.
+	FilterQualityDefault FilterQuality = FilterQuality(mipmap.FilterQualityDefault)
+
+	// FilterQualityTrilinear blends the two mipmap levels around the ideal level (trilinear filtering).
+	// The scale changes smoothly without the visible switch between levels.
+	FilterQualityTrilinear FilterQuality = FilterQuality(mipmap.FilterQualityTrilinear)
+
+	// FilterQualityAnisotropic takes up to 8 trilinear samples along the direction in which the image is minified
+	// the most (anisotropic filtering).
+	// An image minified in one direction, like a map seen at a shallow angle, is rendered less blurry than with
+	// FilterQualityTrilinear.
+	FilterQualityAnisotropic FilterQuality = FilterQuality(mipmap.FilterQualityAnisotropic)
+)
+
 // CompositeMode represents Porter-Duff composition mode.
 type CompositeMode int
 
@@ -44,46 +68,99 @@ type CompositeMode int
 const (
 	// Regular alpha blending
 	// c_out = c_src + c_dst × (1 - α_src)
-	CompositeModeSourceOver CompositeMode = CompositeMode(graphicsdriver.CompositeModeSourceOver)
+	CompositeModeSourceOver CompositeMode = iota
 
 	// c_out = 0
-	CompositeModeClear CompositeMode = CompositeMode(graphicsdriver.CompositeModeClear)
+	CompositeModeClear
 
 	// c_out = c_src
-	CompositeModeCopy CompositeMode = CompositeMode(graphicsdriver.CompositeModeCopy)
+	CompositeModeCopy
 
 	// c_out = c_dst
-	CompositeModeDestination CompositeMode = CompositeMode(graphicsdriver.CompositeModeDestination)
+	CompositeModeDestination
 
 	// c_out = c_src × (1 - α_dst) + c_dst
-	CompositeModeDestinationOver CompositeMode = CompositeMode(graphicsdriver.CompositeModeDestinationOver)
+	CompositeModeDestinationOver
 
 	// c_out = c_src × α_dst
-	CompositeModeSourceIn CompositeMode = CompositeMode(graphicsdriver.CompositeModeSourceIn)
+	CompositeModeSourceIn
 
 	// c_out = c_dst × α_src
-	CompositeModeDestinationIn CompositeMode = CompositeMode(graphicsdriver.CompositeModeDestinationIn)
+	CompositeModeDestinationIn
 
 	// c_out = c_src × (1 - α_dst)
-	CompositeModeSourceOut CompositeMode = CompositeMode(graphicsdriver.CompositeModeSourceOut)
+	CompositeModeSourceOut
 
 	// c_out = c_dst × (1 - α_src)
-	CompositeModeDestinationOut CompositeMode = CompositeMode(graphicsdriver.CompositeModeDestinationOut)
+	CompositeModeDestinationOut
 
 	// c_out = c_src × α_dst + c_dst × (1 - α_src)
-	CompositeModeSourceAtop CompositeMode = CompositeMode(graphicsdriver.CompositeModeSourceAtop)
+	CompositeModeSourceAtop
 
 	// c_out = c_src × (1 - α_dst) + c_dst × α_src
-	CompositeModeDestinationAtop CompositeMode = CompositeMode(graphicsdriver.CompositeModeDestinationAtop)
+	CompositeModeDestinationAtop
 
 	// c_out = c_src × (1 - α_dst) + c_dst × (1 - α_src)
-	CompositeModeXor CompositeMode = CompositeMode(graphicsdriver.CompositeModeXor)
+	CompositeModeXor
 
 	// Sum of source and destination (a.k.a. 'plus' or 'additive')
 	// c_out = c_src + c_dst
-	CompositeModeLighter CompositeMode = CompositeMode(graphicsdriver.CompositeModeLighter)
+	CompositeModeLighter
 
 	// The product of source and destination (a.k.a 'multiply blend mode')
 	// c_out = c_src * c_dst
-	CompositeModeMultiply CompositeMode = CompositeMode(graphicsdriver.CompositeModeMultiply)
+	CompositeModeMultiply
 )
+
+// GraphicsCapabilitiesInfo represents the capabilities of the graphics device.
+type GraphicsCapabilitiesInfo struct {
+	// MaxImageSize is the maximum width and height of a texture on the graphics device.
+	MaxImageSize int
+
+	// MaxSampleCount is the maximum number of samples per pixel for multisample antialiasing.
+	// MaxSampleCount is 1 if multisampling is not available.
+	MaxSampleCount int
+
+	// FloatTexture reports whether the graphics device supports floating-point textures.
+	FloatTexture bool
+
+	// ComputeShader reports whether the graphics device supports compute shaders.
+	ComputeShader bool
+
+	// Instancing reports whether the graphics device supports instanced drawing.
+	// Image.DrawTrianglesInstanced works even without instanced drawing, but is slower.
+	Instancing bool
+
+	// MaxRenderTargets is the maximum number of destination images for DrawTrianglesShaderMRT.
+	// MaxRenderTargets is 1 if multiple render targets are not available.
+	MaxRenderTargets int
+
+	// Renderer is the name of the graphics device or the renderer.
+	Renderer string
+
+	// DriverVersion is the version string of the graphics library and the driver.
+	// DriverVersion might be empty when the graphics library doesn't provide it, e.g., Metal.
+	DriverVersion string
+}
+
+// GraphicsCapabilities returns the capabilities of the graphics device.
+//
+// The capabilities are available after the game starts, e.g., in Update.
+// Before that, GraphicsCapabilities returns the zero value.
+//
+// GraphicsCapabilities is concurrent-safe.
+//
+// This API is experimental.
+func GraphicsCapabilities() GraphicsCapabilitiesInfo {
+	c := ui.GraphicsCapabilities()
+	return GraphicsCapabilitiesInfo{
+		MaxImageSize:     c.MaxImageSize,
+		MaxSampleCount:   c.MaxSampleCount,
+		FloatTexture:     c.FloatTexture,
+		ComputeShader:    c.ComputeShader,
+		Instancing:       c.Instancing,
+		MaxRenderTargets: c.MaxRenderTargets,
+		Renderer:         c.Renderer,
+		DriverVersion:    c.Version,
+	}
+}
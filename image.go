@@ -128,9 +128,19 @@ type DrawImageOptions struct {
 	// The default (zero) value is regular alpha blending.
 	CompositeMode CompositeMode
 
+	// Blend is a blending way of the source color and the destination color.
+	// If Blend is not the zero value, Blend is used and CompositeMode is ignored.
+	// The default (zero) value is to use CompositeMode.
+	Blend Blend
+
 	// Filter is a type of texture filter.
 	// The default (zero) value is FilterNearest.
 	Filter Filter
+
+	// FilterQuality is the quality of the filter when the image is minified.
+	// FilterQuality is used only when Filter is FilterLinear.
+	// The default (zero) value is FilterQualityDefault.
+	FilterQuality FilterQuality
 }
 
 // DrawImage draws the given image on the image i.
@@ -156,7 +166,7 @@ type DrawImageOptions struct {
 //     * If only (*ColorM).Scale is applied to a ColorM, the ColorM has only
 //       diagonal elements. The other ColorM functions might modify the other
 //       elements.
-//   * All CompositeMode values are same
+//   * All CompositeMode and Blend values are same
 //   * All Filter values are same
 //
 // Even when all the above conditions are satisfied, multiple draw commands can
@@ -193,7 +203,7 @@ func (i *Image) DrawImage(img *Image, options *DrawImageOptions) {
 	}
 
 	bounds := img.Bounds()
-	mode := graphicsdriver.CompositeMode(options.CompositeMode)
+	mode := internalBlend(options.CompositeMode, options.Blend)
 	filter := graphicsdriver.Filter(options.Filter)
 
 	a, b, c, d, tx, ty := options.GeoM.elements32()
@@ -207,7 +217,7 @@ func (i *Image) DrawImage(img *Image, options *DrawImageOptions) {
 
 	srcs := [graphics.ShaderImageNum]*mipmap.Mipmap{img.mipmap}
 
-	i.mipmap.DrawTriangles(srcs, vs, is, options.ColorM.affineColorM(), mode, filter, graphicsdriver.AddressUnsafe, dstRegion, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false, canSkipMipmap(options.GeoM, filter))
+	i.mipmap.DrawTriangles(srcs, vs, is, options.ColorM.affineColorM(), mode, filter, mipmap.FilterQuality(options.FilterQuality), graphicsdriver.AddressUnsafe, dstRegion, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll, canSkipMipmap(options.GeoM, filter))
 }
 
 // Vertex represents a vertex passed to DrawTriangles.
@@ -255,6 +265,11 @@ const (
 	// EvenOdd means that triangles are rendered based on the even-odd rule.
 	// If and only if the number of overlappings is odd, the region is rendered.
 	EvenOdd
+
+	// NonZero means that triangles are rendered based on the non-zero rule.
+	// A triangle whose vertices are in the clockwise order counts +1 and one in the counterclockwise order counts -1.
+	// If and only if the sum of the counts of overlappings is not zero, the region is rendered.
+	NonZero
 )
 
 // DrawTrianglesOptions represents options for DrawTriangles.
@@ -270,17 +285,27 @@ type DrawTrianglesOptions struct {
 	// The default (zero) value is regular alpha blending.
 	CompositeMode CompositeMode
 
+	// Blend is a blending way of the source color and the destination color.
+	// If Blend is not the zero value, Blend is used and CompositeMode is ignored.
+	// The default (zero) value is to use CompositeMode.
+	Blend Blend
+
 	// Filter is a type of texture filter.
 	// The default (zero) value is FilterNearest.
 	Filter Filter
 
+	// FilterQuality is the quality of the filter when the image is minified.
+	// FilterQuality is used only when Filter is FilterLinear and Address is AddressUnsafe.
+	// The default (zero) value is FilterQualityDefault.
+	FilterQuality FilterQuality
+
 	// Address is a sampler address mode.
 	// The default (zero) value is AddressUnsafe.
 	Address Address
 
 	// FillRule indicates the rule how an overlapped region is rendered.
 	//
-	// The rule EvenOdd is useful when you want to render a complex polygon.
+	// The rules EvenOdd and NonZero are useful when you want to render a complex polygon.
 	// A complex polygon is a non-convex polygon like a concave polygon, a polygon with holes, or a self-intersecting polygon.
 	// See examples/vector for actual usages.
 	//
@@ -332,7 +357,7 @@ func (i *Image) DrawTriangles(vertices []Vertex, indices []uint16, img *Image, o
 		options = &DrawTrianglesOptions{}
 	}
 
-	mode := graphicsdriver.CompositeMode(options.CompositeMode)
+	mode := internalBlend(options.CompositeMode, options.Blend)
 
 	address := graphicsdriver.Address(options.Address)
 	var sr graphicsdriver.Region
@@ -364,7 +389,128 @@ func (i *Image) DrawTriangles(vertices []Vertex, indices []uint16, img *Image, o
 
 	srcs := [graphics.ShaderImageNum]*mipmap.Mipmap{img.mipmap}
 
-	i.mipmap.DrawTriangles(srcs, vs, is, options.ColorM.affineColorM(), mode, filter, address, dstRegion, sr, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, options.FillRule == EvenOdd, false)
+	i.mipmap.DrawTriangles(srcs, vs, is, options.ColorM.affineColorM(), mode, filter, mipmap.FilterQuality(options.FilterQuality), address, dstRegion, sr, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillRule(options.FillRule), false)
+}
+
+// Instance represents the attributes of one instance for DrawTrianglesInstanced.
+//
+// This API is experimental.
+type Instance struct {
+	// DstX and DstY represents an offset added to the destination points of the vertices.
+	DstX float32
+	DstY float32
+
+	// SrcX and SrcY represents an offset added to the source points of the vertices.
+	SrcX float32
+	SrcY float32
+
+	// ColorR/ColorG/ColorB/ColorA represents color scaling values multiplied with the vertices' ones.
+	// Be careful that the zero value makes the instance transparent.
+	ColorR float32
+	ColorG float32
+	ColorB float32
+	ColorA float32
+}
+
+// DrawTrianglesInstanced draws triangles with the specified vertices and their indices repeatedly for each instance.
+//
+// DrawTrianglesInstanced is useful to draw a lot of same shapes like particles and tiles with one call.
+// For each instance, the instance's offsets are added to the vertices' points, and the instance's color scale is
+// multiplied with the vertices' color scale.
+// If the graphics device supports instanced drawing (see GraphicsCapabilities), the vertices are sent to GPU only once.
+// Otherwise, the triangles are expanded on CPU.
+//
+// Mipmaps are not used for DrawTrianglesInstanced.
+//
+// If len(indices) is not multiple of 3, DrawTrianglesInstanced panics.
+//
+// If len(indices) is more than MaxIndicesNum, DrawTrianglesInstanced panics.
+//
+// If options.FillRule is not FillAll, DrawTrianglesInstanced panics.
+//
+// When the given image is nil or disposed, DrawTrianglesInstanced panics.
+//
+// When the image i is disposed, DrawTrianglesInstanced does nothing.
+//
+// This API is experimental.
+func (i *Image) DrawTrianglesInstanced(vertices []Vertex, indices []uint16, img *Image, instances []Instance, options *DrawTrianglesOptions) {
+	i.copyCheck()
+
+	if img == nil {
+		panic("ebiten: the given image to DrawTrianglesInstanced must not be nil")
+	}
+	if img.isDisposed() {
+		panic("ebiten: the given image to DrawTrianglesInstanced must not be disposed")
+	}
+	if i.isDisposed() {
+		return
+	}
+
+	if len(indices)%3 != 0 {
+		panic("ebiten: len(indices) % 3 must be 0")
+	}
+	if len(indices) > MaxIndicesNum {
+		panic("ebiten: len(indices) must be <= MaxIndicesNum")
+	}
+
+	if options == nil {
+		options = &DrawTrianglesOptions{}
+	}
+	if options.FillRule != FillAll {
+		panic("ebiten: options.FillRule must be FillAll at DrawTrianglesInstanced")
+	}
+
+	if len(vertices) == 0 || len(indices) == 0 || len(instances) == 0 {
+		return
+	}
+
+	dstBounds := i.Bounds()
+	dstRegion := graphicsdriver.Region{
+		X:      float32(dstBounds.Min.X),
+		Y:      float32(dstBounds.Min.Y),
+		Width:  float32(dstBounds.Dx()),
+		Height: float32(dstBounds.Dy()),
+	}
+
+	address := graphicsdriver.Address(options.Address)
+	var sr graphicsdriver.Region
+	if address != graphicsdriver.AddressUnsafe {
+		b := img.Bounds()
+		sr = graphicsdriver.Region{
+			X:      float32(b.Min.X),
+			Y:      float32(b.Min.Y),
+			Width:  float32(b.Dx()),
+			Height: float32(b.Dy()),
+		}
+	}
+
+	vs := graphics.Vertices(len(vertices))
+	for i, v := range vertices {
+		vs[i*graphics.VertexFloatNum] = v.DstX
+		vs[i*graphics.VertexFloatNum+1] = v.DstY
+		vs[i*graphics.VertexFloatNum+2] = v.SrcX
+		vs[i*graphics.VertexFloatNum+3] = v.SrcY
+		vs[i*graphics.VertexFloatNum+4] = v.ColorR
+		vs[i*graphics.VertexFloatNum+5] = v.ColorG
+		vs[i*graphics.VertexFloatNum+6] = v.ColorB
+		vs[i*graphics.VertexFloatNum+7] = v.ColorA
+	}
+	is := make([]uint16, len(indices))
+	copy(is, indices)
+
+	insts := make([]float32, len(instances)*graphics.InstanceFloatNum)
+	for i, inst := range instances {
+		insts[i*graphics.InstanceFloatNum] = inst.DstX
+		insts[i*graphics.InstanceFloatNum+1] = inst.DstY
+		insts[i*graphics.InstanceFloatNum+2] = inst.SrcX
+		insts[i*graphics.InstanceFloatNum+3] = inst.SrcY
+		insts[i*graphics.InstanceFloatNum+4] = inst.ColorR
+		insts[i*graphics.InstanceFloatNum+5] = inst.ColorG
+		insts[i*graphics.InstanceFloatNum+6] = inst.ColorB
+		insts[i*graphics.InstanceFloatNum+7] = inst.ColorA
+	}
+
+	i.mipmap.DrawTrianglesInstanced(img.mipmap, vs, is, insts, options.ColorM.affineColorM(), internalBlend(options.CompositeMode, options.Blend), graphicsdriver.Filter(options.Filter), address, dstRegion, sr)
 }
 
 // DrawTrianglesShaderOptions represents options for DrawTrianglesShader.
@@ -375,6 +521,11 @@ type DrawTrianglesShaderOptions struct {
 	// The default (zero) value is regular alpha blending.
 	CompositeMode CompositeMode
 
+	// Blend is a blending way of the source color and the destination color.
+	// If Blend is not the zero value, Blend is used and CompositeMode is ignored.
+	// The default (zero) value is to use CompositeMode.
+	Blend Blend
+
 	// Uniforms is a set of uniform variables for the shader.
 	// The keys are the names of the uniform variables.
 	// The values must be float or []float.
@@ -389,7 +540,7 @@ type DrawTrianglesShaderOptions struct {
 
 	// FillRule indicates the rule how an overlapped region is rendered.
 	//
-	// The rule EvenOdd is useful when you want to render a complex polygon.
+	// The rules EvenOdd and NonZero are useful when you want to render a complex polygon.
 	// A complex polygon is a non-convex polygon like a concave polygon, a polygon with holes, or a self-intersecting polygon.
 	// See examples/vector for actual usages.
 	//
@@ -406,6 +557,8 @@ func init() {
 
 // DrawTrianglesShader draws triangles with the specified vertices and their indices with the specified shader.
 //
+// If the shader has the vertex entry point Vertex, each vertex is processed by Vertex. See NewShader.
+//
 // For the details about the shader, see https://ebiten.org/documents/shader.html.
 //
 // If len(indices) is not multiple of 3, DrawTrianglesShader panics.
@@ -444,7 +597,7 @@ func (i *Image) DrawTrianglesShader(vertices []Vertex, indices []uint16, shader
 		options = &DrawTrianglesShaderOptions{}
 	}
 
-	mode := graphicsdriver.CompositeMode(options.CompositeMode)
+	mode := internalBlend(options.CompositeMode, options.Blend)
 
 	vs := graphics.Vertices(len(vertices))
 	for i, v := range vertices {
@@ -510,7 +663,168 @@ func (i *Image) DrawTrianglesShader(vertices []Vertex, indices []uint16, shader
 
 	us := shader.convertUniforms(options.Uniforms)
 
-	i.mipmap.DrawTriangles(imgs, vs, is, affine.ColorMIdentity{}, mode, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, sr, offsets, shader.shader, us, options.FillRule == EvenOdd, false)
+	i.mipmap.DrawTriangles(imgs, vs, is, affine.ColorMIdentity{}, mode, graphicsdriver.FilterNearest, mipmap.FilterQualityDefault, graphicsdriver.AddressUnsafe, dstRegion, sr, offsets, shader.shader, us, graphicsdriver.FillRule(options.FillRule), false)
+}
+
+// DrawTrianglesShaderMRT draws triangles with the specified vertices and their indices with the specified shader
+// to multiple destination images at once (multiple render targets).
+//
+// The fragment entry point of the shader must return as many vec4 values as len(dsts).
+// The k-th returned color is written to dsts[k].
+//
+// len(dsts) must be between 1 and GraphicsCapabilities().MaxRenderTargets.
+// All the destination images must have the same bounds and must be different from each other.
+// A destination image must not be the screen image, and must not be disposed.
+// If a destination image is a sub-image, its original image must be the same size as the other destinations' ones.
+//
+// The options are the same as DrawTrianglesShader's options, except that FillRule must be FillAll.
+//
+// If len(indices) is not multiple of 3, DrawTrianglesShaderMRT panics.
+//
+// If len(indices) is more than MaxIndicesNum, DrawTrianglesShaderMRT panics.
+//
+// When a specified source image is non-nil and is disposed, DrawTrianglesShaderMRT panics.
+//
+// This API is experimental.
+func DrawTrianglesShaderMRT(dsts []*Image, vertices []Vertex, indices []uint16, shader *Shader, options *DrawTrianglesShaderOptions) {
+	if len(dsts) == 0 {
+		panic("ebiten: len(dsts) must be > 0")
+	}
+	if len(dsts) > graphics.ShaderDstImageNum {
+		panic(fmt.Sprintf("ebiten: len(dsts) must be <= %d", graphics.ShaderDstImageNum))
+	}
+	if n := ui.GraphicsCapabilities().MaxRenderTargets; n > 0 && len(dsts) > n {
+		panic(fmt.Sprintf("ebiten: len(dsts) must be <= GraphicsCapabilities().MaxRenderTargets (%d)", n))
+	}
+	if len(dsts) > shader.colorsCount {
+		panic(fmt.Sprintf("ebiten: len(dsts) must be <= the number of the colors the shader returns (%d)", shader.colorsCount))
+	}
+
+	var dstMipmaps [graphics.ShaderDstImageNum]*mipmap.Mipmap
+	for i, dst := range dsts {
+		if dst == nil {
+			panic("ebiten: the destination images must not be nil")
+		}
+		dst.copyCheck()
+		if dst.isDisposed() {
+			panic("ebiten: the destination images must not be disposed")
+		}
+		if dst.screen || (dst.isSubImage() && dst.original.screen) {
+			panic("ebiten: the destination images must not be the screen image")
+		}
+		if i > 0 {
+			if dst.Bounds() != dsts[0].Bounds() {
+				panic("ebiten: all the destination images must have the same bounds")
+			}
+			if dst.originalBounds() != dsts[0].originalBounds() {
+				panic("ebiten: all the destination images' original images must be the same size")
+			}
+		}
+		for _, m := range dstMipmaps[:i] {
+			if m == dst.mipmap {
+				panic("ebiten: the destination images must be different from each other")
+			}
+		}
+		dstMipmaps[i] = dst.mipmap
+	}
+
+	if len(indices)%3 != 0 {
+		panic("ebiten: len(indices) % 3 must be 0")
+	}
+	if len(indices) > MaxIndicesNum {
+		panic("ebiten: len(indices) must be <= MaxIndicesNum")
+	}
+
+	dstBounds := dsts[0].Bounds()
+	dstRegion := graphicsdriver.Region{
+		X:      float32(dstBounds.Min.X),
+		Y:      float32(dstBounds.Min.Y),
+		Width:  float32(dstBounds.Dx()),
+		Height: float32(dstBounds.Dy()),
+	}
+
+	if options == nil {
+		options = &DrawTrianglesShaderOptions{}
+	}
+	if options.FillRule != FillAll {
+		panic("ebiten: FillRule must be FillAll for DrawTrianglesShaderMRT")
+	}
+
+	mode := internalBlend(options.CompositeMode, options.Blend)
+
+	vs := graphics.Vertices(len(vertices))
+	for i, v := range vertices {
+		vs[i*graphics.VertexFloatNum] = v.DstX
+		vs[i*graphics.VertexFloatNum+1] = v.DstY
+		vs[i*graphics.VertexFloatNum+2] = v.SrcX
+		vs[i*graphics.VertexFloatNum+3] = v.SrcY
+		vs[i*graphics.VertexFloatNum+4] = v.ColorR
+		vs[i*graphics.VertexFloatNum+5] = v.ColorG
+		vs[i*graphics.VertexFloatNum+6] = v.ColorB
+		vs[i*graphics.VertexFloatNum+7] = v.ColorA
+	}
+	is := make([]uint16, len(indices))
+	copy(is, indices)
+
+	var imgs [graphics.ShaderImageNum]*mipmap.Mipmap
+	var imgw, imgh int
+	for i, img := range options.Images {
+		if img == nil {
+			continue
+		}
+		if img.isDisposed() {
+			panic("ebiten: the given image to DrawTrianglesShaderMRT must not be disposed")
+		}
+		for _, m := range dstMipmaps {
+			if m != nil && m == img.mipmap {
+				panic("ebiten: a source image must not be a destination image")
+			}
+		}
+		if i == 0 {
+			imgw, imgh = img.Size()
+		} else {
+			if w, h := img.Size(); imgw != w || imgh != h {
+				panic("ebiten: all the source images must be the same size with the rectangle")
+			}
+		}
+		imgs[i] = img.mipmap
+	}
+
+	var sx, sy float32
+	var sr graphicsdriver.Region
+	if img := options.Images[0]; img != nil {
+		b := img.Bounds()
+		sx = float32(b.Min.X)
+		sy = float32(b.Min.Y)
+		sr = graphicsdriver.Region{
+			X:      float32(b.Min.X),
+			Y:      float32(b.Min.Y),
+			Width:  float32(b.Dx()),
+			Height: float32(b.Dy()),
+		}
+	}
+
+	var offsets [graphics.ShaderImageNum - 1][2]float32
+	for i, img := range options.Images[1:] {
+		if img == nil {
+			continue
+		}
+		b := img.Bounds()
+		offsets[i][0] = -sx + float32(b.Min.X)
+		offsets[i][1] = -sy + float32(b.Min.Y)
+	}
+
+	us := shader.convertUniforms(options.Uniforms)
+
+	mipmap.DrawTrianglesMRT(dstMipmaps, imgs, vs, is, mode, dstRegion, sr, offsets, shader.shader, us)
+}
+
+// originalBounds returns the bounds of the original image if i is a sub-image, or i's bounds otherwise.
+func (i *Image) originalBounds() image.Rectangle {
+	if i.isSubImage() {
+		return i.original.Bounds()
+	}
+	return i.Bounds()
 }
 
 // DrawRectShaderOptions represents options for DrawRectShader.
@@ -525,6 +839,11 @@ type DrawRectShaderOptions struct {
 	// The default (zero) value is regular alpha blending.
 	CompositeMode CompositeMode
 
+	// Blend is a blending way of the source color and the destination color.
+	// If Blend is not the zero value, Blend is used and CompositeMode is ignored.
+	// The default (zero) value is to use CompositeMode.
+	Blend Blend
+
 	// Uniforms is a set of uniform variables for the shader.
 	// The keys are the names of the uniform variables.
 	// The values must be float or []float.
@@ -573,7 +892,7 @@ func (i *Image) DrawRectShader(width, height int, shader *Shader, options *DrawR
 		options = &DrawRectShaderOptions{}
 	}
 
-	mode := graphicsdriver.CompositeMode(options.CompositeMode)
+	mode := internalBlend(options.CompositeMode, options.Blend)
 
 	var imgs [graphics.ShaderImageNum]*mipmap.Mipmap
 	for i, img := range options.Images {
@@ -622,7 +941,7 @@ func (i *Image) DrawRectShader(width, height int, shader *Shader, options *DrawR
 	}
 
 	us := shader.convertUniforms(options.Uniforms)
-	i.mipmap.DrawTriangles(imgs, vs, is, affine.ColorMIdentity{}, mode, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, sr, offsets, shader.shader, us, false, canSkipMipmap(options.GeoM, graphicsdriver.FilterNearest))
+	i.mipmap.DrawTriangles(imgs, vs, is, affine.ColorMIdentity{}, mode, graphicsdriver.FilterNearest, mipmap.FilterQualityDefault, graphicsdriver.AddressUnsafe, dstRegion, sr, offsets, shader.shader, us, graphicsdriver.FillAll, canSkipMipmap(options.GeoM, graphicsdriver.FilterNearest))
 }
 
 // SubImage returns an image representing the portion of the image p visible through r.
@@ -714,8 +1033,8 @@ func (i *Image) at(x, y int) (r, g, b, a uint8) {
 	if !image.Pt(x, y).In(i.Bounds()) {
 		return 0, 0, 0, 0
 	}
-	pix, err := i.mipmap.Pixels(x, y, 1, 1)
-	if err != nil {
+	var pix [4]byte
+	if err := i.mipmap.ReadPixels(pix[:], x, y, 1, 1); err != nil {
 		if panicOnErrorAtImageAt {
 			panic(err)
 		}
@@ -725,6 +1044,161 @@ func (i *Image) at(x, y int) (r, g, b, a uint8) {
 	return pix[0], pix[1], pix[2], pix[3]
 }
 
+// ReadPixels reads the image's pixels from the image into pixels.
+//
+// The given pixels represent RGBA pre-multiplied alpha values.
+// len(pixels) must equal to 4 * (bounds width) * (bounds height).
+//
+// ReadPixels loads pixels from GPU to system memory if necessary, which means that ReadPixels can be slow.
+// Unlike calling At for each pixel, ReadPixels loads all the pixels at once and fills pixels without allocating
+// another buffer.
+//
+// ReadPixels is synchronous: ReadPixels flushes the queued draw commands, waits for GPU to finish them, and
+// the result reflects all the operations on the image before ReadPixels is called.
+// Ebiten doesn't retain pixels after ReadPixels returns, then the caller can reuse pixels.
+// If you don't want to wait for GPU, use ReadPixelsAsync instead.
+//
+// ReadPixels works on a sub-image.
+//
+// When len(pixels) is not appropriate, ReadPixels panics.
+//
+// When the image is disposed, ReadPixels does nothing.
+//
+// ReadPixels can't be called outside the main loop (ebiten.Run's updating function) starts.
+func (i *Image) ReadPixels(pixels []byte) {
+	b := i.Bounds()
+	if got, want := len(pixels), 4*b.Dx()*b.Dy(); got != want {
+		panic(fmt.Sprintf("ebiten: len(pixels) must be %d but %d at ReadPixels", want, got))
+	}
+	i.readPixels(pixels, b)
+}
+
+// ReadPixelsRect reads the image's pixels in the given rectangle into pixels.
+//
+// The given pixels represent RGBA pre-multiplied alpha values.
+// len(pixels) must equal to 4 * (rect width) * (rect height).
+//
+// ReadPixelsRect works in the same way as ReadPixels on the sub-image of rect, without creating the sub-image.
+// See ReadPixels for the synchronization semantics.
+//
+// When rect is not in the image's bounds or len(pixels) is not appropriate, ReadPixelsRect panics.
+//
+// When the image is disposed, ReadPixelsRect does nothing.
+//
+// ReadPixelsRect can't be called outside the main loop (ebiten.Run's updating function) starts.
+func (i *Image) ReadPixelsRect(pixels []byte, rect image.Rectangle) {
+	if !rect.In(i.Bounds()) {
+		panic(fmt.Sprintf("ebiten: rect %v must be in the image's bounds %v at ReadPixelsRect", rect, i.Bounds()))
+	}
+	if got, want := len(pixels), 4*rect.Dx()*rect.Dy(); got != want {
+		panic(fmt.Sprintf("ebiten: len(pixels) must be %d but %d at ReadPixelsRect", want, got))
+	}
+	i.readPixels(pixels, rect)
+}
+
+func (i *Image) readPixels(pixels []byte, rect image.Rectangle) {
+	if i.isDisposed() {
+		return
+	}
+	if rect.Empty() {
+		return
+	}
+
+	if err := i.mipmap.ReadPixels(pixels, rect.Min.X, rect.Min.Y, rect.Dx(), rect.Dy()); err != nil {
+		if panicOnErrorAtImageAt {
+			panic(err)
+		}
+		ui.SetError(err)
+	}
+}
+
+// ToImage returns a copy of the image's pixels as *image.RGBA.
+// The bounds of the returned image are the same as the image's bounds.
+//
+// ToImage loads pixels from GPU to system memory, which means that ToImage can be slow.
+//
+// ToImage returns a transparent image if the image is disposed.
+//
+// ToImage can't be called outside the main loop (ebiten.Run's updating function) starts.
+func (i *Image) ToImage() *image.RGBA {
+	img := image.NewRGBA(i.Bounds())
+	i.ReadPixels(img.Pix)
+	return img
+}
+
+// ReadPixelsAsync reads the image's pixels in the given rectangle without waiting for GPU, and calls f with the
+// pixels later.
+//
+// The pixels given to f represent RGBA pre-multiplied alpha values.
+// The length of the pixels is 4 * (rect width) * (rect height).
+// The pixels reflect the image at the time ReadPixelsAsync is called.
+//
+// f is called before Update in a later frame, usually one or two frames later.
+// Unlike ReadPixels, ReadPixelsAsync doesn't wait for GPU to finish the rendering, and is suitable to read pixels
+// every frame e.g. for recording.
+//
+// ReadPixelsAsync works on a sub-image.
+//
+// When rect is not in the image's bounds, ReadPixelsAsync panics.
+//
+// When rect is empty or the image is disposed, ReadPixelsAsync does nothing and f is never called.
+func (i *Image) ReadPixelsAsync(rect image.Rectangle, f func(pixels []byte)) {
+	if !rect.In(i.Bounds()) {
+		panic(fmt.Sprintf("ebiten: rect %v must be in the image's bounds %v at ReadPixelsAsync", rect, i.Bounds()))
+	}
+
+	if i.isDisposed() {
+		return
+	}
+	if rect.Empty() {
+		return
+	}
+
+	i.mipmap.ReadPixelsAsync(rect.Min.X, rect.Min.Y, rect.Dx(), rect.Dy(), f)
+}
+
+// readImagesAsync replaces *Image in imgs with *image.RGBA by reading the pixels from GPU asynchronously,
+// and calls f with the result.
+//
+// If imgs doesn't include *Image, f is called immediately. Otherwise, f is called before Update in a later frame.
+// readImagesAsync doesn't modify imgs.
+func readImagesAsync(imgs []image.Image, f func(imgs []image.Image)) {
+	result := make([]image.Image, len(imgs))
+	copy(result, imgs)
+
+	// Collect the indices of *Image to read its pixels from GPU.
+	var indices []int
+	for i, img := range result {
+		if img, ok := img.(*Image); ok && !img.isDisposed() && !img.Bounds().Empty() {
+			indices = append(indices, i)
+		}
+	}
+	if len(indices) == 0 {
+		f(result)
+		return
+	}
+
+	// The callbacks are called on the same goroutine, then pending doesn't need a lock.
+	pending := len(indices)
+	for _, i := range indices {
+		i := i
+		img := result[i].(*Image)
+		b := img.Bounds()
+		img.ReadPixelsAsync(b, func(pix []byte) {
+			result[i] = &image.RGBA{
+				Pix:    pix,
+				Stride: 4 * b.Dx(),
+				Rect:   b,
+			}
+			pending--
+			if pending > 0 {
+				return
+			}
+			f(result)
+		})
+	}
+}
+
 // Set sets the color at (x, y).
 //
 // Set loads pixels from GPU to system memory if necessary, which means that Set can be slow.
@@ -799,6 +1273,75 @@ func (i *Image) ReplacePixels(pixels []byte) {
 	}
 }
 
+// WritePixels replaces the pixels of the image in the given region with pix.
+//
+// The given pix must represent RGBA pre-multiplied alpha values, and the rows are packed without gaps.
+// len(pix) must equal to 4 * (region width) * (region height).
+//
+// WritePixels is useful to update a part of a big image, e.g., to put a tile generated by CPU onto an atlas image,
+// without creating a sub-image.
+//
+// When region is not in the image's bounds or len(pix) is not appropriate, WritePixels panics.
+//
+// When the image is disposed, WritePixels does nothing.
+func (i *Image) WritePixels(pix []byte, region image.Rectangle) {
+	if got, want := len(pix), 4*region.Dx()*region.Dy(); got != want {
+		panic(fmt.Sprintf("ebiten: len(pix) must be %d but %d at WritePixels", want, got))
+	}
+	i.WritePixelsWithStride(pix, 4*region.Dx(), region)
+}
+
+// WritePixelsWithStride replaces the pixels of the image in the given region with pix, whose rows are stride bytes
+// apart.
+//
+// The given pix must represent RGBA pre-multiplied alpha values.
+// The pixel at (x, y) in region is read from pix[(y-region.Min.Y)*stride+(x-region.Min.X)*4:].
+// This is the same layout as image.RGBA's Pix and Stride, then a part of a bigger buffer like a video frame can be
+// passed without copying it in advance.
+//
+// stride must be equal to or greater than 4 * (region width), and len(pix) must be at least
+// stride * (region height - 1) + 4 * (region width).
+//
+// When region is not in the image's bounds, or stride or len(pix) is not appropriate, WritePixelsWithStride panics.
+//
+// When the image is disposed, WritePixelsWithStride does nothing.
+func (i *Image) WritePixelsWithStride(pix []byte, stride int, region image.Rectangle) {
+	i.copyCheck()
+
+	if !region.In(i.Bounds()) {
+		panic(fmt.Sprintf("ebiten: region %v must be in the image's bounds %v at WritePixels", region, i.Bounds()))
+	}
+	w, h := region.Dx(), region.Dy()
+	if stride < 4*w {
+		panic(fmt.Sprintf("ebiten: stride must be >= %d but %d at WritePixels", 4*w, stride))
+	}
+	if region.Empty() {
+		return
+	}
+	if got, want := len(pix), stride*(h-1)+4*w; got < want {
+		panic(fmt.Sprintf("ebiten: len(pix) must be >= %d but %d at WritePixels", want, got))
+	}
+
+	if i.isDisposed() {
+		return
+	}
+
+	// Pack the rows as the internal packages require contiguous pixels.
+	if stride == 4*w {
+		pix = pix[:4*w*h]
+	} else {
+		packed := make([]byte, 4*w*h)
+		for j := 0; j < h; j++ {
+			copy(packed[4*w*j:4*w*(j+1)], pix[stride*j:])
+		}
+		pix = packed
+	}
+
+	if err := i.mipmap.ReplacePixels(pix, region.Min.X, region.Min.Y, w, h); err != nil {
+		ui.SetError(err)
+	}
+}
+
 // NewImage returns an empty image.
 //
 // If width or height is less than 1 or more than device-dependent maximum size, NewImage panics.
@@ -809,6 +1352,86 @@ func (i *Image) ReplacePixels(pixels []byte) {
 //
 // NewImage panics if RunGame already finishes.
 func NewImage(width, height int) *Image {
+	return NewImageWithOptions(width, height, nil)
+}
+
+// NewImageOptions represents options for NewImageWithOptions.
+type NewImageOptions struct {
+	// SampleCount is the number of samples per pixel for multisample antialiasing (MSAA).
+	// The edges of triangles rendered onto a multisampled image are antialiased.
+	// This is useful to render smooth paths with the vector package.
+	//
+	// SampleCount must be 0, 1, 2, 4 or 8. 0 and 1 mean no multisampling.
+	// The default (zero) value is 0.
+	//
+	// If the graphics driver doesn't support the specified sample count, fewer samples are used.
+	// Multisampling is available only with OpenGL on desktops so far. Otherwise, SampleCount is ignored.
+	SampleCount int
+
+	// Format is the pixel format of the image on GPU.
+	// An image with fewer channels uses less GPU memory.
+	// The default (zero) value is ImageFormatRGBA8.
+	//
+	// If the graphics driver doesn't support the specified format, e.g., OpenGL 2.1, OpenGL ES 2.0 or WebGL 1,
+	// ImageFormatRGBA8 is used on GPU instead. Even in this case, the pixels read from the image are the same,
+	// but only the channels the format has are meaningful when the image is used as a rendering source.
+	Format ImageFormat
+
+	// Unmanaged indicates whether the image has its own texture and is never put on an internal texture atlas.
+	//
+	// By default, Ebiten puts small images on texture atlases so that drawing them can be batched, and moves an
+	// image between an atlas and its own texture depending on how the image is used.
+	// An unmanaged image is never moved, which is useful for an image that is frequently updated and used as a
+	// rendering source, e.g., a video frame, to avoid re-allocations.
+	// On the other hand, drawing an unmanaged image is never batched with drawing other images.
+	//
+	// The default (zero) value is false.
+	Unmanaged bool
+}
+
+// ImageFormat represents a pixel format of an image on GPU.
+//
+// Regardless of the format, pixels are passed to and returned from an image as RGBA, e.g., at ReplacePixels and At.
+// The color channels that the format doesn't have are treated as 0, and the alpha channel is treated as 1.
+// For example, a pixel of an ImageFormatR8 image is read as color.RGBA{r, 0, 0, 0xff},
+// and a shader reading the image gets vec4(r, 0, 0, 1).
+// When an image with such a format is a render target, only the channels the format has are written.
+type ImageFormat int
+
+const (
+	// ImageFormatRGBA8 represents 8-bit red, green, blue and alpha channels.
+	ImageFormatRGBA8 ImageFormat = iota
+
+	// ImageFormatR8 represents an 8-bit red channel.
+	// This is useful for masks, heightmaps and single-channel distance fields, and uses 1/4 GPU memory of RGBA8.
+	ImageFormatR8
+
+	// ImageFormatRG8 represents 8-bit red and green channels.
+	// This uses 1/2 GPU memory of RGBA8.
+	ImageFormatRG8
+)
+
+func (f ImageFormat) pixelFormat() graphicsdriver.PixelFormat {
+	switch f {
+	case ImageFormatRGBA8:
+		return graphicsdriver.PixelFormatRGBA8
+	case ImageFormatR8:
+		return graphicsdriver.PixelFormatR8
+	case ImageFormatRG8:
+		return graphicsdriver.PixelFormatRG8
+	default:
+		panic(fmt.Sprintf("ebiten: invalid image format: %d", f))
+	}
+}
+
+// NewImageWithOptions returns an empty image with the specified options.
+//
+// options can be nil. In this case, NewImageWithOptions works in the same way as NewImage.
+//
+// If width or height is less than 1 or more than device-dependent maximum size, NewImageWithOptions panics.
+//
+// NewImageWithOptions panics if RunGame already finishes.
+func NewImageWithOptions(width, height int, options *NewImageOptions) *Image {
 	if isRunGameEnded() {
 		panic(fmt.Sprintf("ebiten: NewImage cannot be called after RunGame finishes"))
 	}
@@ -823,9 +1446,42 @@ func NewImage(width, height int) *Image {
 		bounds: image.Rect(0, 0, width, height),
 	}
 	i.addr = i
+	if options != nil {
+		switch options.SampleCount {
+		case 0, 1:
+		case 2, 4, 8:
+			i.mipmap.SetSampleCount(options.SampleCount)
+		default:
+			panic(fmt.Sprintf("ebiten: SampleCount at NewImageWithOptions must be 0, 1, 2, 4 or 8 but %d", options.SampleCount))
+		}
+		if options.Format != ImageFormatRGBA8 {
+			i.mipmap.SetFormat(options.Format.pixelFormat())
+		}
+		if options.Unmanaged {
+			i.mipmap.SetIndependent(true)
+		}
+	}
 	return i
 }
 
+// IsOnAtlas reports whether the image is currently put on an internal texture atlas.
+//
+// An image is put on an atlas or moved out of an atlas automatically depending on how the image is used.
+// The result is just a snapshot, and can change after the following operations on the image.
+// An image created with NewImageOptions.Unmanaged is never on an atlas.
+//
+// For a sub-image, IsOnAtlas reports the state of the original image.
+//
+// IsOnAtlas always returns false if the image is disposed.
+//
+// This value is for measurement and/or debug, and your game logic should not rely on this value.
+func (i *Image) IsOnAtlas() bool {
+	if i.isDisposed() {
+		return false
+	}
+	return i.mipmap.IsOnAtlas()
+}
+
 // NewImageFromImage creates a new image with the given image (source).
 //
 // If source's width or height is less than 1 or more than device-dependent maximum size, NewImageFromImage panics.
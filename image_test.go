@@ -111,6 +111,98 @@ func TestImagePixels(t *testing.T) {
 	}
 }
 
+func TestImageReadPixels(t *testing.T) {
+	img0, img, err := openEbitenImage()
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+
+	for _, r := range []image.Rectangle{
+		img0.Bounds(),
+		image.Rect(3, 4, 20, 30),
+	} {
+		sub := img0.SubImage(r).(*ebiten.Image)
+		r = sub.Bounds()
+		pix := make([]byte, 4*r.Dx()*r.Dy())
+		sub.ReadPixels(pix)
+		for j := r.Min.Y; j < r.Max.Y; j++ {
+			for i := r.Min.X; i < r.Max.X; i++ {
+				idx := 4 * ((j-r.Min.Y)*r.Dx() + (i - r.Min.X))
+				got := color.RGBA{pix[idx], pix[idx+1], pix[idx+2], pix[idx+3]}
+				want := color.RGBAModel.Convert(img.At(i, j))
+				if got != want {
+					t.Errorf("%v: pixel at (%d, %d): got %#v; want %#v", r, i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestImageReadPixelsRect(t *testing.T) {
+	img0, img, err := openEbitenImage()
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+
+	sub := img0.SubImage(image.Rect(2, 3, 30, 20)).(*ebiten.Image)
+	for _, r := range []image.Rectangle{
+		sub.Bounds(),
+		image.Rect(3, 4, 20, 15),
+		image.Rect(29, 19, 30, 20),
+	} {
+		pix := make([]byte, 4*r.Dx()*r.Dy())
+		sub.ReadPixelsRect(pix, r)
+		for j := r.Min.Y; j < r.Max.Y; j++ {
+			for i := r.Min.X; i < r.Max.X; i++ {
+				idx := 4 * ((j-r.Min.Y)*r.Dx() + (i - r.Min.X))
+				got := color.RGBA{pix[idx], pix[idx+1], pix[idx+2], pix[idx+3]}
+				want := color.RGBAModel.Convert(img.At(i, j))
+				if got != want {
+					t.Errorf("%v: pixel at (%d, %d): got %#v; want %#v", r, i, j, got, want)
+				}
+			}
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("ReadPixelsRect with a rectangle out of the bounds must panic")
+		}
+	}()
+	sub.ReadPixelsRect(make([]byte, 4), image.Rect(1, 1, 2, 2))
+}
+
+func TestImageToImage(t *testing.T) {
+	img0, img, err := openEbitenImage()
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+
+	for _, r := range []image.Rectangle{
+		img0.Bounds(),
+		image.Rect(3, 4, 20, 30),
+	} {
+		sub := img0.SubImage(r).(*ebiten.Image)
+		r = sub.Bounds()
+		got := sub.ToImage()
+		if got.Bounds() != r {
+			t.Errorf("bounds: got %v; want %v", got.Bounds(), r)
+		}
+		for j := r.Min.Y; j < r.Max.Y; j++ {
+			for i := r.Min.X; i < r.Max.X; i++ {
+				got := got.RGBAAt(i, j)
+				want := color.RGBAModel.Convert(img.At(i, j))
+				if got != want {
+					t.Errorf("%v: pixel at (%d, %d): got %#v; want %#v", r, i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
 func TestImageComposition(t *testing.T) {
 	img2Color := color.NRGBA{0x24, 0x3f, 0x6a, 0x88}
 	img3Color := color.NRGBA{0x85, 0xa3, 0x08, 0xd3}
@@ -295,6 +387,166 @@ func TestImageReplacePixels(t *testing.T) {
 	}
 }
 
+func TestImageWritePixels(t *testing.T) {
+	const w, h = 16, 16
+	img := ebiten.NewImage(w, h)
+	img.Fill(color.White)
+
+	// src is a bigger buffer than the region. Its rows are not contiguous for the region.
+	src := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	for j := 0; j < 32; j++ {
+		for i := 0; i < 32; i++ {
+			src.SetRGBA(i, j, color.RGBA{uint8(i * 8), uint8(j * 8), 0, 0xff})
+		}
+	}
+
+	r := image.Rect(4, 5, 10, 9)
+	img.WritePixelsWithStride(src.Pix[src.PixOffset(1, 2):], src.Stride, r)
+
+	// A region without a stride.
+	r2 := image.Rect(12, 12, 14, 14)
+	pix := make([]byte, 4*r2.Dx()*r2.Dy())
+	for i := range pix {
+		pix[i] = 0x80
+	}
+	img.WritePixels(pix, r2)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := img.At(i, j)
+			var want color.RGBA
+			switch {
+			case image.Pt(i, j).In(r):
+				want = src.RGBAAt(i-r.Min.X+1, j-r.Min.Y+2)
+			case image.Pt(i, j).In(r2):
+				want = color.RGBA{0x80, 0x80, 0x80, 0x80}
+			default:
+				want = color.RGBA{0xff, 0xff, 0xff, 0xff}
+			}
+			if got != want {
+				t.Errorf("img At(%d, %d): got %#v; want %#v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawImageQuad(t *testing.T) {
+	const w, h = 16, 16
+	src := ebiten.NewImage(w, h)
+	pix := make([]byte, 4*w*h)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			idx := 4 * (j*w + i)
+			pix[idx] = uint8(i * 16)
+			pix[idx+1] = uint8(j * 16)
+			pix[idx+3] = 0xff
+		}
+	}
+	src.ReplacePixels(pix)
+
+	want := ebiten.NewImage(2*w, 2*h)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(2, 2)
+	want.DrawImage(src, op)
+
+	// An axis-aligned rectangle must be rendered as DrawImage does, regardless of Perspective.
+	for _, perspective := range []bool{false, true} {
+		dst := ebiten.NewImage(2*w, 2*h)
+		dst.DrawImageQuad(src, ebiten.Quad{
+			X0: 0, Y0: 0,
+			X1: 2 * w, Y1: 0,
+			X2: 2 * w, Y2: 2 * h,
+			X3: 0, Y3: 2 * h,
+		}, &ebiten.DrawImageQuadOptions{
+			Perspective: perspective,
+		})
+		for j := 0; j < 2*h; j++ {
+			for i := 0; i < 2*w; i++ {
+				got := dst.At(i, j)
+				want := want.At(i, j)
+				if got != want {
+					t.Errorf("perspective: %v, dst.At(%d, %d): got: %v, want: %v", perspective, i, j, got, want)
+				}
+			}
+		}
+	}
+
+	// A trapezoid narrowing to the top looks like a floor receding into the distance.
+	// With the perspective mapping, the upper half of the source is compressed to the upper side.
+	q := ebiten.Quad{
+		X0: 12, Y0: 0,
+		X1: 20, Y1: 0,
+		X2: 32, Y2: 32,
+		X3: 0, Y3: 32,
+	}
+	for _, perspective := range []bool{false, true} {
+		dst := ebiten.NewImage(2*w, 2*h)
+		dst.DrawImageQuad(src, q, &ebiten.DrawImageQuadOptions{
+			Perspective: perspective,
+		})
+		got := dst.At(16, 8).(color.RGBA)
+		if perspective && got.G < 8*16 {
+			t.Errorf("perspective: %v, dst.At(16, 8).G: got: %d, want: >= %d", perspective, got.G, 8*16)
+		}
+		if !perspective && got.G >= 8*16 {
+			t.Errorf("perspective: %v, dst.At(16, 8).G: got: %d, want: < %d", perspective, got.G, 8*16)
+		}
+	}
+}
+
+func TestImageFilterQuality(t *testing.T) {
+	const w, h = 64, 64
+
+	for _, tc := range []struct {
+		quality ebiten.FilterQuality
+		period  int
+		scaleX  float64
+		scaleY  float64
+	}{
+		{quality: ebiten.FilterQualityTrilinear, period: 2, scaleX: 0.3, scaleY: 0.3},
+		{quality: ebiten.FilterQualityAnisotropic, period: 2, scaleX: 0.3, scaleY: 0.3},
+		// The image is minified only horizontally.
+		// FilterQualityDefault doesn't use mipmaps as the image is not minified vertically, and the result is
+		// not uniform.
+		{quality: ebiten.FilterQualityAnisotropic, period: 4, scaleX: 0.125, scaleY: 1},
+	} {
+		// src has vertical white stripes of 1 pixel width at every period pixels.
+		src := ebiten.NewImage(w, h)
+		pix := make([]byte, 4*w*h)
+		for j := 0; j < h; j++ {
+			for i := 0; i < w; i += tc.period {
+				idx := 4 * (j*w + i)
+				pix[idx] = 0xff
+				pix[idx+1] = 0xff
+				pix[idx+2] = 0xff
+				pix[idx+3] = 0xff
+			}
+		}
+		src.ReplacePixels(pix)
+
+		dst := ebiten.NewImage(w, h)
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(tc.scaleX, tc.scaleY)
+		op.Filter = ebiten.FilterLinear
+		op.FilterQuality = tc.quality
+		dst.DrawImage(src, op)
+
+		// The stripes should be averaged into uniform gray, except for the edges.
+		v := uint8(0x100/tc.period - 1)
+		want := color.RGBA{v, v, v, v}
+		dw := int(w * tc.scaleX)
+		dh := int(h * tc.scaleY)
+		for j := 1; j < dh-1; j++ {
+			for i := 1; i < dw-1; i++ {
+				got := dst.At(i, j).(color.RGBA)
+				if !sameColors(got, want, 0x10) {
+					t.Errorf("quality: %d, scale: (%f, %f), dst.At(%d, %d): got: %v, want: %v", tc.quality, tc.scaleX, tc.scaleY, i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
 func TestImageReplacePixelsNil(t *testing.T) {
 	defer func() {
 		if r := recover(); r == nil {
@@ -2426,6 +2678,91 @@ func TestImageEvenOdd(t *testing.T) {
 	}
 }
 
+func TestImageNonZero(t *testing.T) {
+	emptyImage := ebiten.NewImage(3, 3)
+	emptyImage.Fill(color.White)
+	emptySubImage := emptyImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+
+	square := func(x0, y0, x1, y1 float32, r, g, b float32) []ebiten.Vertex {
+		return []ebiten.Vertex{
+			{
+				DstX: x0, DstY: y0, SrcX: 1, SrcY: 1,
+				ColorR: r, ColorG: g, ColorB: b, ColorA: 1,
+			},
+			{
+				DstX: x1, DstY: y0, SrcX: 1, SrcY: 1,
+				ColorR: r, ColorG: g, ColorB: b, ColorA: 1,
+			},
+			{
+				DstX: x0, DstY: y1, SrcX: 1, SrcY: 1,
+				ColorR: r, ColorG: g, ColorB: b, ColorA: 1,
+			},
+			{
+				DstX: x1, DstY: y1, SrcX: 1, SrcY: 1,
+				ColorR: r, ColorG: g, ColorB: b, ColorA: 1,
+			},
+		}
+	}
+
+	vs := square(1, 1, 15, 15, 1, 0, 0)
+	vs = append(vs, square(2, 2, 14, 14, 0, 1, 0)...)
+	vs = append(vs, square(3, 3, 13, 13, 0, 0, 1)...)
+	// The first and the second squares are clockwise, and the third square is counterclockwise.
+	is := []uint16{0, 1, 2, 2, 1, 3, 4, 5, 6, 6, 5, 7, 8, 10, 9, 9, 10, 11}
+
+	dst := ebiten.NewImage(16, 16)
+	op := &ebiten.DrawTrianglesOptions{
+		FillRule: ebiten.NonZero,
+	}
+	dst.DrawTriangles(vs, is, emptySubImage, op)
+	for j := 0; j < 16; j++ {
+		for i := 0; i < 16; i++ {
+			got := dst.At(i, j)
+			var want color.RGBA
+			switch {
+			case 3 <= i && i < 13 && 3 <= j && j < 13:
+				// The winding number is 1.
+				want = color.RGBA{0, 0, 0xff, 0xff}
+			case 2 <= i && i < 14 && 2 <= j && j < 14:
+				// The winding number is 2.
+				want = color.RGBA{0, 0xff, 0, 0xff}
+			case 1 <= i && i < 15 && 1 <= j && j < 15:
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			default:
+				want = color.RGBA{0, 0, 0, 0}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+
+	// Make the second square counterclockwise. The winding numbers are 0 at the ring of the second square and -1 at
+	// the center.
+	is = []uint16{0, 1, 2, 2, 1, 3, 4, 6, 5, 5, 6, 7, 8, 10, 9, 9, 10, 11}
+	dst.Clear()
+	dst.DrawTriangles(vs, is, emptySubImage, op)
+	for j := 0; j < 16; j++ {
+		for i := 0; i < 16; i++ {
+			got := dst.At(i, j)
+			var want color.RGBA
+			switch {
+			case 3 <= i && i < 13 && 3 <= j && j < 13:
+				want = color.RGBA{0, 0, 0xff, 0xff}
+			case 2 <= i && i < 14 && 2 <= j && j < 14:
+				want = color.RGBA{0, 0, 0, 0}
+			case 1 <= i && i < 15 && 1 <= j && j < 15:
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			default:
+				want = color.RGBA{0, 0, 0, 0}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
 // #1658
 func BenchmarkColorMScale(b *testing.B) {
 	r := rand.Float64
@@ -2601,3 +2938,346 @@ func TestTooManyVertices(t *testing.T) {
 	// Confirm this doesn't freeze.
 	dst.At(0, 0)
 }
+
+func TestImageDrawTrianglesManyIndices(t *testing.T) {
+	const (
+		w = 16
+		h = 16
+	)
+
+	dst := ebiten.NewImage(w, h)
+	src := ebiten.NewImage(w, h)
+	src.Fill(color.White)
+
+	vs := []ebiten.Vertex{
+		{DstX: 0, DstY: 0, SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: w, DstY: 0, SrcX: w, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 0, DstY: h, SrcX: 0, SrcY: h, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: w, DstY: h, SrcX: w, SrcY: h, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+	is := make([]uint16, ebiten.MaxIndicesNum/6*6)
+	for i := 0; i < len(is); i += 6 {
+		copy(is[i:], []uint16{0, 1, 2, 1, 2, 3})
+	}
+
+	// The total number of indices in a frame exceeds MaxIndicesNum.
+	// The draw calls are merged into one command, and the indices must fit the driver's buffer.
+	for i := 0; i < 4; i++ {
+		dst.DrawTriangles(vs, is, src, nil)
+	}
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j)
+			want := color.RGBA{0xff, 0xff, 0xff, 0xff}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageMipmapPartialUpdate(t *testing.T) {
+	const size = 64
+
+	src := ebiten.NewImage(size, size)
+	src.Fill(color.White)
+
+	draw := func(dst, src *ebiten.Image) {
+		dst.Clear()
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(0.125, 0.125)
+		op.Filter = ebiten.FilterLinear
+		dst.DrawImage(src, op)
+	}
+
+	// Create the mipmaps of src.
+	dst0 := ebiten.NewImage(size/8, size/8)
+	draw(dst0, src)
+
+	// Modify parts of src. Only the modified regions of the mipmaps should be regenerated.
+	red := ebiten.NewImage(16, 16)
+	red.Fill(color.RGBA{0xff, 0, 0, 0xff})
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(40, 8)
+	src.DrawImage(red, op)
+
+	pix := make([]byte, 4*8*8)
+	for i := 0; i < len(pix)/4; i++ {
+		pix[4*i+2] = 0xff
+		pix[4*i+3] = 0xff
+	}
+	src.SubImage(image.Rect(4, 36, 12, 44)).(*ebiten.Image).ReplacePixels(pix)
+	draw(dst0, src)
+
+	// Render the same contents with a new image, whose mipmaps are generated from scratch.
+	src1 := ebiten.NewImage(size, size)
+	src1.DrawImage(src, nil)
+	dst1 := ebiten.NewImage(size/8, size/8)
+	draw(dst1, src1)
+
+	for j := 0; j < size/8; j++ {
+		for i := 0; i < size/8; i++ {
+			got := dst0.At(i, j)
+			want := dst1.At(i, j)
+			if got != want {
+				t.Errorf("dst0.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageMultisampled(t *testing.T) {
+	const w, h = 16, 16
+	dst := ebiten.NewImageWithOptions(w, h, &ebiten.NewImageOptions{
+		SampleCount: 4,
+	})
+
+	// The pixels given by ReplacePixels must be kept after rendering.
+	pix := make([]byte, 4*w*h)
+	for i := 0; i < len(pix)/4; i++ {
+		pix[4*i] = 0xff
+		pix[4*i+3] = 0xff
+	}
+	dst.ReplacePixels(pix)
+
+	src := ebiten.NewImage(w, h)
+	src.Fill(color.White)
+	vs := []ebiten.Vertex{
+		{DstX: 0, DstY: 0, SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: w, DstY: 0, SrcX: w, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 0, DstY: h, SrcX: 0, SrcY: h, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+	dst.DrawTriangles(vs, []uint16{0, 1, 2}, src, nil)
+
+	// Use dst as a rendering source to resolve its pixels.
+	dst2 := ebiten.NewImage(w, h)
+	dst2.DrawImage(dst, nil)
+
+	for _, img := range []*ebiten.Image{dst, dst2} {
+		for j := 0; j < h; j++ {
+			for i := 0; i < w; i++ {
+				var want color.RGBA
+				switch {
+				case i+j < w-2:
+					want = color.RGBA{0xff, 0xff, 0xff, 0xff}
+				case i+j > w:
+					want = color.RGBA{0xff, 0, 0, 0xff}
+				default:
+					// The pixels on the edge might be antialiased.
+					continue
+				}
+				if got := img.At(i, j); got != want {
+					t.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestImageFormat(t *testing.T) {
+	// Use an odd width to check the rows that are not aligned to 4 bytes.
+	const w, h = 7, 5
+
+	for _, format := range []ebiten.ImageFormat{ebiten.ImageFormatR8, ebiten.ImageFormatRG8} {
+		format := format
+		t.Run(fmt.Sprintf("format %d", format), func(t *testing.T) {
+			filter := func(c color.RGBA) color.RGBA {
+				c.B = 0
+				c.A = 0xff
+				if format == ebiten.ImageFormatR8 {
+					c.G = 0
+				}
+				return c
+			}
+
+			img := ebiten.NewImageWithOptions(w, h, &ebiten.NewImageOptions{
+				Format: format,
+			})
+			pix := make([]byte, 4*w*h)
+			for i := 0; i < len(pix)/4; i++ {
+				pix[4*i] = byte(i)
+				pix[4*i+1] = byte(2 * i)
+				pix[4*i+2] = byte(3 * i)
+				pix[4*i+3] = 0xff
+			}
+			img.ReplacePixels(pix)
+			img.SubImage(image.Rect(0, 0, 2, 2)).(*ebiten.Image).Fill(color.RGBA{0x80, 0x40, 0x20, 0xff})
+
+			dst := ebiten.NewImage(w, h)
+			dst.DrawImage(img, nil)
+
+			for j := 0; j < h; j++ {
+				for i := 0; i < w; i++ {
+					idx := j*w + i
+					want := filter(color.RGBA{byte(idx), byte(2 * idx), byte(3 * idx), 0xff})
+					if i < 2 && j < 2 {
+						want = filter(color.RGBA{0x80, 0x40, 0x20, 0xff})
+					}
+					if got := img.At(i, j); got != want {
+						t.Errorf("img.At(%d, %d): got: %v, want: %v", i, j, got, want)
+					}
+					if got := dst.At(i, j); got != want {
+						t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestImageUnmanaged(t *testing.T) {
+	const w, h = 16, 16
+
+	pix := make([]byte, 4*w*h)
+	for i := 0; i < w*h; i++ {
+		pix[4*i+1] = 0xff
+		pix[4*i+3] = 0xff
+	}
+
+	// An image whose pixels are given by ReplacePixels is put on an atlas by default.
+	managed := ebiten.NewImage(w, h)
+	managed.ReplacePixels(pix)
+	if got, want := managed.IsOnAtlas(), true; got != want {
+		t.Errorf("managed.IsOnAtlas(): got: %t, want: %t", got, want)
+	}
+
+	unmanaged := ebiten.NewImageWithOptions(w, h, &ebiten.NewImageOptions{
+		Unmanaged: true,
+	})
+	unmanaged.ReplacePixels(pix)
+	if got, want := unmanaged.IsOnAtlas(), false; got != want {
+		t.Errorf("unmanaged.IsOnAtlas(): got: %t, want: %t", got, want)
+	}
+
+	dst := ebiten.NewImage(w, h)
+	dst.DrawImage(unmanaged, nil)
+	if got, want := dst.At(0, 0), (color.RGBA{0, 0xff, 0, 0xff}); got != want {
+		t.Errorf("dst.At(0, 0): got: %v, want: %v", got, want)
+	}
+
+	unmanaged.Dispose()
+	if got, want := unmanaged.IsOnAtlas(), false; got != want {
+		t.Errorf("unmanaged.IsOnAtlas() after Dispose: got: %t, want: %t", got, want)
+	}
+}
+
+func TestImageDebugMarker(t *testing.T) {
+	dst := ebiten.NewImage(16, 16)
+	ebiten.DebugMarker("outer", func() {
+		dst.Fill(color.RGBA{0xff, 0, 0, 0xff})
+		ebiten.DebugMarker("inner", func() {
+			dst.SubImage(image.Rect(8, 8, 16, 16)).(*ebiten.Image).Fill(color.RGBA{0, 0xff, 0, 0xff})
+		})
+	})
+
+	if got, want := dst.At(0, 0), (color.RGBA{0xff, 0, 0, 0xff}); got != want {
+		t.Errorf("dst.At(0, 0): got: %v, want: %v", got, want)
+	}
+	if got, want := dst.At(12, 12), (color.RGBA{0, 0xff, 0, 0xff}); got != want {
+		t.Errorf("dst.At(12, 12): got: %v, want: %v", got, want)
+	}
+}
+
+func TestImageDrawTrianglesInstanced(t *testing.T) {
+	src := ebiten.NewImage(2, 1)
+	src.Set(0, 0, color.RGBA{0xff, 0, 0, 0xff})
+	src.Set(1, 0, color.RGBA{0, 0, 0xff, 0xff})
+
+	vs := []ebiten.Vertex{
+		{DstX: 0, DstY: 0, SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 1, DstY: 0, SrcX: 1, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 0, DstY: 1, SrcX: 0, SrcY: 1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 1, DstY: 1, SrcX: 1, SrcY: 1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+	is := []uint16{0, 1, 2, 1, 2, 3}
+	insts := []ebiten.Instance{
+		{DstX: 0, DstY: 0, SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 2, DstY: 0, SrcX: 1, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 0, DstY: 2, SrcX: 0, SrcY: 0, ColorR: 0, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+
+	const w, h = 4, 4
+	dst := ebiten.NewImage(w, h)
+	dst.DrawTrianglesInstanced(vs, is, src, insts, nil)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			var want color.RGBA
+			switch {
+			case i == 0 && j == 0:
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			case i == 2 && j == 0:
+				want = color.RGBA{0, 0, 0xff, 0xff}
+			case i == 0 && j == 2:
+				want = color.RGBA{0, 0, 0, 0xff}
+			}
+			if !sameColors(got, want, 1) {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageBlend(t *testing.T) {
+	const w, h = 16, 16
+	src := ebiten.NewImage(w, h)
+	src.Fill(color.RGBA{0x80, 0x80, 0x80, 0xff})
+
+	for _, tc := range []struct {
+		name  string
+		blend ebiten.Blend
+		want  color.RGBA
+	}{
+		{
+			name:  "copy",
+			blend: ebiten.BlendCopy,
+			want:  color.RGBA{0x80, 0x80, 0x80, 0xff},
+		},
+		{
+			name: "max",
+			blend: ebiten.Blend{
+				BlendFactorSourceRGB:        ebiten.BlendFactorOne,
+				BlendFactorSourceAlpha:      ebiten.BlendFactorOne,
+				BlendFactorDestinationRGB:   ebiten.BlendFactorOne,
+				BlendFactorDestinationAlpha: ebiten.BlendFactorOne,
+				BlendOperationRGB:           ebiten.BlendOperationMax,
+				BlendOperationAlpha:         ebiten.BlendOperationMax,
+			},
+			want: color.RGBA{0x80, 0x80, 0xc0, 0xff},
+		},
+		{
+			name: "reverse-subtract",
+			blend: ebiten.Blend{
+				BlendFactorSourceRGB:        ebiten.BlendFactorOne,
+				BlendFactorSourceAlpha:      ebiten.BlendFactorZero,
+				BlendFactorDestinationRGB:   ebiten.BlendFactorOne,
+				BlendFactorDestinationAlpha: ebiten.BlendFactorOne,
+				BlendOperationRGB:           ebiten.BlendOperationReverseSubtract,
+				BlendOperationAlpha:         ebiten.BlendOperationAdd,
+			},
+			want: color.RGBA{0, 0, 0x40, 0xff},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := ebiten.NewImage(w, h)
+			dst.Fill(color.RGBA{0x40, 0x80, 0xc0, 0xff})
+
+			op := &ebiten.DrawImageOptions{}
+			// CompositeMode is ignored when Blend is specified.
+			op.CompositeMode = ebiten.CompositeModeClear
+			op.Blend = tc.blend
+			dst.DrawImage(src, op)
+
+			for j := 0; j < h; j++ {
+				for i := 0; i < w; i++ {
+					got := dst.At(i, j).(color.RGBA)
+					if !sameColors(got, tc.want, 1) {
+						t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, tc.want)
+					}
+				}
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package ebiten
 
 import (
 	"fmt"
+	"image/png"
 	"os"
 	"time"
 
@@ -45,14 +46,24 @@ func availableFilename(prefix, postfix string) (string, error) {
 	return name, nil
 }
 
-func takeScreenshot(screen *Image) error {
+func takeScreenshot() error {
 	newname, err := availableFilename("screenshot_", ".png")
 	if err != nil {
 		return err
 	}
 
-	blackbg := !IsScreenTransparent()
-	if err := screen.mipmap.DumpScreenshot(newname, blackbg); err != nil {
+	img, err := Screenshot()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(newname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := png.Encode(f, img); err != nil {
 		return err
 	}
 
@@ -164,7 +175,7 @@ func (i *imageDumper) update() error {
 func (i *imageDumper) dump(screen *Image) error {
 	if i.toTakeScreenshot {
 		i.toTakeScreenshot = false
-		if err := takeScreenshot(screen); err != nil {
+		if err := takeScreenshot(); err != nil {
 			return err
 		}
 	}
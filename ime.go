@@ -0,0 +1,69 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/ui"
+)
+
+// IMEComposition returns the text that is being composed with an input method editor (IME) at the time update is called,
+// and the caret position in runes in the text.
+//
+// The composition text is not committed yet. When the user commits the text, the text is reported by AppendInputChars.
+// IMEComposition returns an empty string when there is no composition.
+//
+// IMEComposition works only on Windows and browsers so far.
+// On browsers, IMEComposition works only while a caret rectangle is set by SetIMECaretRect.
+// On the other environments, IMEComposition always returns an empty string and 0.
+//
+// IMEComposition is concurrent-safe.
+func IMEComposition() (text string, caret int) {
+	return ui.Get().Input().IMEComposition()
+}
+
+// AppendIMECandidates appends the conversion candidates of the current IME composition to candidates,
+// and returns the extended buffer and the index of the selected candidate.
+// The index is -1 when there are no candidates.
+// Giving a slice that already has enough capacity works efficiently.
+//
+// The candidates are useful to draw a candidate window by the game itself.
+// Otherwise, the OS draws its own candidate window.
+//
+// AppendIMECandidates works only on Windows so far, as browsers don't expose the candidates.
+// On the other environments, AppendIMECandidates appends nothing and returns -1 as the index.
+//
+// AppendIMECandidates is concurrent-safe.
+func AppendIMECandidates(candidates []string) ([]string, int) {
+	return ui.Get().Input().AppendIMECandidates(candidates)
+}
+
+// SetIMECaretRect sets the rectangle of the text caret in the game screen coordinates.
+// The OS shows the IME composition window and the candidate window next to the rectangle.
+//
+// An empty rectangle lets the OS decide the position.
+//
+// On browsers, a non-empty rectangle also enables the IME, as the IME works only while the game accepts text.
+// The keyboard inputs go to a hidden text field at the rectangle instead of the canvas, while the key states and
+// AppendInputChars work as usual.
+// An empty rectangle disables the IME.
+//
+// SetIMECaretRect works only on Windows and browsers so far.
+//
+// SetIMECaretRect is concurrent-safe.
+func SetIMECaretRect(rect image.Rectangle) {
+	ui.Get().Input().SetIMECaretRect(rect)
+}
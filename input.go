@@ -15,6 +15,8 @@
 package ebiten
 
 import (
+	"io"
+
 	"github.com/hajimehoshi/ebiten/v2/internal/gamepad"
 	"github.com/hajimehoshi/ebiten/v2/internal/gamepaddb"
 	"github.com/hajimehoshi/ebiten/v2/internal/ui"
@@ -45,6 +47,45 @@ func InputChars() []rune {
 	return AppendInputChars(nil)
 }
 
+// DroppedFile represents a file dropped onto the window.
+type DroppedFile struct {
+	// Name is the base name of the file.
+	Name string
+
+	// Path is the full path of the file.
+	// Path is empty on browsers, where the file system is not accessible.
+	Path string
+
+	open func() (io.ReadCloser, error)
+}
+
+// Open opens the dropped file for reading.
+//
+// On desktops, Open opens the file at Path. On browsers, the file contents are already loaded on memory.
+func (d DroppedFile) Open() (io.ReadCloser, error) {
+	return d.open()
+}
+
+// AppendDroppedFiles appends files dropped onto the window at the time update is called, to files,
+// and returns the extended buffer.
+// Giving a slice that already has enough capacity works efficiently.
+//
+// AppendDroppedFiles works on desktops and browsers. On browsers, files are reported after their contents are loaded,
+// which might be a few ticks after they are dropped.
+// On the other environments, AppendDroppedFiles appends nothing.
+//
+// AppendDroppedFiles is concurrent-safe.
+func AppendDroppedFiles(files []DroppedFile) []DroppedFile {
+	for _, f := range ui.Get().Input().AppendDroppedFiles(nil) {
+		files = append(files, DroppedFile{
+			Name: f.Name,
+			Path: f.Path,
+			open: f.Open,
+		})
+	}
+	return files
+}
+
 // IsKeyPressed returns a boolean indicating whether key is pressed.
 //
 // If you want to know whether the key started being pressed in the current frame,
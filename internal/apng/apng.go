@@ -0,0 +1,231 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apng implements an encoder for animated PNG (APNG) images.
+//
+// All the frames are encoded as 8-bit non-premultiplied RGBA images without filtering,
+// so that all the frames can share the same IHDR chunk regardless of their opacity.
+package apng
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"time"
+)
+
+// APNG represents an animated PNG image.
+//
+// APNG is similar to image/gif's GIF.
+type APNG struct {
+	// Image is the successive images.
+	// All the images must have the same size.
+	Image []image.Image
+
+	// Delay is the successive delay times, one per frame.
+	//
+	// A delay is stored in an fcTL chunk as a fraction of a second.
+	// A delay is rounded to milliseconds, or to a coarser unit if it is too long to be represented in milliseconds.
+	Delay []time.Duration
+
+	// LoopCount is the number of times the animation is played.
+	// 0 means an infinite loop.
+	LoopCount int
+}
+
+const pngHeader = "\x89PNG\r\n\x1a\n"
+
+type encoder struct {
+	w   io.Writer
+	seq uint32
+	buf [8]byte
+	err error
+}
+
+func (e *encoder) writeChunk(name string, data []byte) {
+	if e.err != nil {
+		return
+	}
+	binary.BigEndian.PutUint32(e.buf[:4], uint32(len(data)))
+	copy(e.buf[4:8], name)
+	crc := crc32.NewIEEE()
+	_, _ = crc.Write(e.buf[4:8])
+	_, _ = crc.Write(data)
+
+	if _, err := e.w.Write(e.buf[:8]); err != nil {
+		e.err = err
+		return
+	}
+	if _, err := e.w.Write(data); err != nil {
+		e.err = err
+		return
+	}
+	binary.BigEndian.PutUint32(e.buf[:4], crc.Sum32())
+	if _, err := e.w.Write(e.buf[:4]); err != nil {
+		e.err = err
+		return
+	}
+}
+
+func (e *encoder) nextSeq() uint32 {
+	s := e.seq
+	e.seq++
+	return s
+}
+
+func (e *encoder) writeIHDR(width, height int) {
+	var b [13]byte
+	binary.BigEndian.PutUint32(b[0:4], uint32(width))
+	binary.BigEndian.PutUint32(b[4:8], uint32(height))
+	// Bit depth 8, color type 6 (truecolor with alpha).
+	b[8] = 8
+	b[9] = 6
+	// Compression method, filter method and interlace method are all 0.
+	e.writeChunk("IHDR", b[:])
+}
+
+func (e *encoder) writeacTL(numFrames, numPlays int) {
+	var b [8]byte
+	binary.BigEndian.PutUint32(b[0:4], uint32(numFrames))
+	binary.BigEndian.PutUint32(b[4:8], uint32(numPlays))
+	e.writeChunk("acTL", b[:])
+}
+
+func (e *encoder) writefcTL(width, height int, delayNum, delayDen uint16) {
+	var b [26]byte
+	binary.BigEndian.PutUint32(b[0:4], e.nextSeq())
+	binary.BigEndian.PutUint32(b[4:8], uint32(width))
+	binary.BigEndian.PutUint32(b[8:12], uint32(height))
+	// x_offset and y_offset are 0.
+	binary.BigEndian.PutUint16(b[20:22], delayNum)
+	binary.BigEndian.PutUint16(b[22:24], delayDen)
+	// dispose_op is APNG_DISPOSE_OP_NONE and blend_op is APNG_BLEND_OP_SOURCE.
+	e.writeChunk("fcTL", b[:])
+}
+
+// delayFraction returns the numerator and the denominator of the delay d in seconds.
+func delayFraction(d time.Duration) (num, den uint16) {
+	for _, unit := range []struct {
+		duration time.Duration
+		den      uint16
+	}{
+		{time.Millisecond, 1000},
+		{10 * time.Millisecond, 100},
+		{time.Second, 1},
+	} {
+		n := (d + unit.duration/2) / unit.duration
+		if n <= math.MaxUint16 {
+			return uint16(n), unit.den
+		}
+	}
+	return math.MaxUint16, 1
+}
+
+func compress(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+
+	b := img.Bounds()
+	row := make([]byte, 1+4*b.Dx())
+	for j := b.Min.Y; j < b.Max.Y; j++ {
+		// The first byte is the filter type 0 (none).
+		row[0] = 0
+		for i := b.Min.X; i < b.Max.X; i++ {
+			c := color.NRGBAModel.Convert(img.At(i, j)).(color.NRGBA)
+			idx := 1 + 4*(i-b.Min.X)
+			row[idx] = c.R
+			row[idx+1] = c.G
+			row[idx+2] = c.B
+			row[idx+3] = c.A
+		}
+		if _, err := zw.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeAll writes the images in a to w in APNG format.
+func EncodeAll(w io.Writer, a *APNG) error {
+	if len(a.Image) == 0 {
+		return errors.New("apng: must provide at least one image")
+	}
+	if len(a.Image) != len(a.Delay) {
+		return errors.New("apng: mismatched image and delay lengths")
+	}
+	for _, d := range a.Delay {
+		if d < 0 {
+			return fmt.Errorf("apng: Delay must be non-negative but %v", d)
+		}
+	}
+	if a.LoopCount < 0 {
+		return fmt.Errorf("apng: LoopCount must be non-negative but %d", a.LoopCount)
+	}
+
+	size := a.Image[0].Bounds().Size()
+	if size.X <= 0 || size.Y <= 0 {
+		return fmt.Errorf("apng: invalid image size: %d, %d", size.X, size.Y)
+	}
+	for _, img := range a.Image[1:] {
+		if img.Bounds().Size() != size {
+			return errors.New("apng: all the images must have the same size")
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	e := &encoder{
+		w: bw,
+	}
+	if _, err := io.WriteString(bw, pngHeader); err != nil {
+		return err
+	}
+	e.writeIHDR(size.X, size.Y)
+	e.writeacTL(len(a.Image), a.LoopCount)
+
+	for i, img := range a.Image {
+		data, err := compress(img)
+		if err != nil {
+			return err
+		}
+
+		num, den := delayFraction(a.Delay[i])
+		e.writefcTL(size.X, size.Y, num, den)
+		if i == 0 {
+			// The first frame is also the default image for decoders that don't support APNG.
+			e.writeChunk("IDAT", data)
+			continue
+		}
+		fdat := make([]byte, 4+len(data))
+		binary.BigEndian.PutUint32(fdat[0:4], e.nextSeq())
+		copy(fdat[4:], data)
+		e.writeChunk("fdAT", fdat)
+	}
+	e.writeChunk("IEND", nil)
+
+	if e.err != nil {
+		return e.err
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,187 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apng_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/apng"
+)
+
+func TestEncodeAll(t *testing.T) {
+	const (
+		w = 4
+		h = 3
+	)
+	img0 := image.NewRGBA(image.Rect(0, 0, w, h))
+	img1 := image.NewRGBA(image.Rect(0, 0, w, h))
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			img0.Set(i, j, color.RGBA{0xff, 0, 0, 0xff})
+			img1.Set(i, j, color.RGBA{0, 0x80, 0, 0x80})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := apng.EncodeAll(&buf, &apng.APNG{
+		Image: []image.Image{img0, img1},
+		Delay: []time.Duration{100 * time.Millisecond, 100 * time.Millisecond},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A regular PNG decoder must be able to decode the first frame.
+	got, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Bounds() != img0.Bounds() {
+		t.Fatalf("bounds: got: %v, want: %v", got.Bounds(), img0.Bounds())
+	}
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			r, g, b, a := got.At(i, j).RGBA()
+			if r != 0xffff || g != 0 || b != 0 || a != 0xffff {
+				t.Errorf("At(%d, %d): got: (%d, %d, %d, %d), want: (0xffff, 0, 0, 0xffff)", i, j, r, g, b, a)
+			}
+		}
+	}
+}
+
+func TestEncodeAllDifferentSizes(t *testing.T) {
+	img0 := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img1 := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	var buf bytes.Buffer
+	if err := apng.EncodeAll(&buf, &apng.APNG{
+		Image: []image.Image{img0, img1},
+		Delay: []time.Duration{10 * time.Millisecond, 10 * time.Millisecond},
+	}); err == nil {
+		t.Errorf("EncodeAll must return an error for images with different sizes")
+	}
+}
+
+type chunk struct {
+	name string
+	data []byte
+}
+
+func readChunks(t *testing.T, b []byte) []chunk {
+	const header = "\x89PNG\r\n\x1a\n"
+	if string(b[:len(header)]) != header {
+		t.Fatalf("invalid PNG header: %q", b[:len(header)])
+	}
+	b = b[len(header):]
+
+	var chunks []chunk
+	for len(b) > 0 {
+		if len(b) < 12 {
+			t.Fatalf("truncated chunk: %d bytes", len(b))
+		}
+		n := int(binary.BigEndian.Uint32(b[0:4]))
+		name := string(b[4:8])
+		data := b[8 : 8+n]
+		if got, want := binary.BigEndian.Uint32(b[8+n:12+n]), crc32.ChecksumIEEE(b[4:8+n]); got != want {
+			t.Errorf("%s: CRC: got: %08x, want: %08x", name, got, want)
+		}
+		chunks = append(chunks, chunk{name: name, data: data})
+		b = b[12+n:]
+	}
+	return chunks
+}
+
+func TestEncodeAllChunks(t *testing.T) {
+	delays := []time.Duration{
+		16 * time.Millisecond,
+		17 * time.Millisecond,
+		2 * time.Second,
+		70 * time.Second,
+	}
+	imgs := make([]image.Image, len(delays))
+	for i := range imgs {
+		imgs[i] = image.NewRGBA(image.Rect(0, 0, 2, 2))
+	}
+
+	var buf bytes.Buffer
+	if err := apng.EncodeAll(&buf, &apng.APNG{
+		Image:     imgs,
+		Delay:     delays,
+		LoopCount: 3,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	type fraction struct {
+		num uint16
+		den uint16
+	}
+	wantDelays := []fraction{
+		{16, 1000},
+		{17, 1000},
+		{2000, 1000},
+		{7000, 100},
+	}
+
+	var names []string
+	var seqs []uint32
+	var gotDelays []fraction
+	for _, c := range readChunks(t, buf.Bytes()) {
+		names = append(names, c.name)
+		switch c.name {
+		case "acTL":
+			if got, want := binary.BigEndian.Uint32(c.data[0:4]), uint32(len(delays)); got != want {
+				t.Errorf("acTL num_frames: got: %d, want: %d", got, want)
+			}
+			if got, want := binary.BigEndian.Uint32(c.data[4:8]), uint32(3); got != want {
+				t.Errorf("acTL num_plays: got: %d, want: %d", got, want)
+			}
+		case "fcTL":
+			seqs = append(seqs, binary.BigEndian.Uint32(c.data[0:4]))
+			gotDelays = append(gotDelays, fraction{
+				num: binary.BigEndian.Uint16(c.data[20:22]),
+				den: binary.BigEndian.Uint16(c.data[22:24]),
+			})
+		case "fdAT":
+			seqs = append(seqs, binary.BigEndian.Uint32(c.data[0:4]))
+		}
+	}
+
+	wantNames := []string{"IHDR", "acTL", "fcTL", "IDAT", "fcTL", "fdAT", "fcTL", "fdAT", "fcTL", "fdAT", "IEND"}
+	if len(names) != len(wantNames) {
+		t.Fatalf("chunks: got: %v, want: %v", names, wantNames)
+	}
+	for i := range names {
+		if names[i] != wantNames[i] {
+			t.Fatalf("chunks: got: %v, want: %v", names, wantNames)
+		}
+	}
+	for i, s := range seqs {
+		if s != uint32(i) {
+			t.Errorf("sequence numbers: got: %v, want: successive numbers from 0", seqs)
+			break
+		}
+	}
+	for i := range wantDelays {
+		if gotDelays[i] != wantDelays[i] {
+			t.Errorf("delay #%d: got: %v, want: %v", i, gotDelays[i], wantDelays[i])
+		}
+	}
+}
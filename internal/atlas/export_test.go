@@ -40,12 +40,6 @@ func ResetImageSizeForTesting() {
 	maxSize = oldMaxSize
 }
 
-func (i *Image) IsOnAtlasForTesting() bool {
-	backendsM.Lock()
-	defer backendsM.Unlock()
-	return i.isOnAtlas()
-}
-
 func (i *Image) EnsureIsolatedForTesting() {
 	backendsM.Lock()
 	defer backendsM.Unlock()
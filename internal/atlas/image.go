@@ -16,9 +16,9 @@ package atlas
 
 import (
 	"fmt"
-	"image"
 	"runtime"
 	"sync"
+	"sync/atomic"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/affine"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
@@ -169,6 +169,7 @@ func (b *backend) tryAlloc(width, height int) (*packing.Node, bool) {
 
 	s := b.page.Size()
 	b.restorable = b.restorable.Extend(s, s)
+	reallocations++
 
 	if n == nil {
 		panic("atlas: Alloc result must not be nil at TryAlloc")
@@ -191,6 +192,13 @@ var (
 
 	// deferredM is a mutext for the slice operations. This must not be used for other usages.
 	deferredM sync.Mutex
+
+	// reallocations is the number of the times when an atlas is created or extended in the current frame.
+	// reallocations is protected by backendsM.
+	reallocations int
+
+	// lastReallocations is the number of the reallocations in the last frame.
+	lastReallocations int64
 )
 
 func init() {
@@ -214,6 +222,12 @@ type Image struct {
 	volatile    bool
 	screen      bool
 
+	// sampleCount is the number of samples per pixel for multisample antialiasing.
+	sampleCount int
+
+	// format is the pixel format of the image's texture.
+	format graphicsdriver.PixelFormat
+
 	backend *backend
 
 	node *packing.Node
@@ -294,7 +308,7 @@ func (i *Image) ensureIsolated() {
 		Width:  float32(w - 2*paddingSize),
 		Height: float32(h - 2*paddingSize),
 	}
-	newImg.DrawTriangles(srcs, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, graphicsdriver.Region{}, nil, nil, false)
+	newImg.DrawTriangles(srcs, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, graphicsdriver.Region{}, nil, nil, graphicsdriver.FillAll)
 
 	i.dispose(false)
 	i.backend = &backend{
@@ -350,7 +364,7 @@ func (i *Image) putOnAtlas() error {
 			Width:  w,
 			Height: h,
 		}
-		newI.drawTriangles([graphics.ShaderImageNum]*Image{i}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false, true)
+		newI.drawTriangles([graphics.ShaderImageNum]*Image{i}, vs, is, nil, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll, [graphics.ShaderDstImageNum - 1]*Image{}, true)
 	}
 
 	newI.moveTo(i)
@@ -398,13 +412,44 @@ func (i *Image) processSrc(src *Image) {
 //   5: Color G
 //   6: Color B
 //   7: Color Y
-func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool) {
+func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform, fillRule graphicsdriver.FillRule) {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+	i.drawTriangles(srcs, vertices, indices, nil, colorm, mode, filter, address, dstRegion, srcRegion, subimageOffsets, shader, uniforms, fillRule, [graphics.ShaderDstImageNum - 1]*Image{}, false)
+}
+
+// DrawTrianglesInstanced draws triangles with the given image repeatedly for each instance.
+//
+// Each instance has graphics.InstanceFloatNum values:
+//
+//   0: Destination X offset in pixels
+//   1: Destination Y offset in pixels
+//   2: Source X offset in pixels
+//   3: Source Y offset in pixels
+//   4: Color R scale
+//   5: Color G scale
+//   6: Color B scale
+//   7: Color A scale
+func (i *Image) DrawTrianglesInstanced(src *Image, vertices []float32, indices []uint16, instances []float32, colorm affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region) {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+	i.drawTriangles([graphics.ShaderImageNum]*Image{src}, vertices, indices, instances, colorm, mode, filter, address, dstRegion, srcRegion, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll, [graphics.ShaderDstImageNum - 1]*Image{}, false)
+}
+
+// DrawTrianglesMRT draws triangles with the given shader onto the multiple destinations at the same time.
+//
+// The destinations are packed from the front in dsts, and the rest are nil.
+// All the destinations must have the same size.
+func DrawTrianglesMRT(dsts [graphics.ShaderDstImageNum]*Image, srcs [graphics.ShaderImageNum]*Image, vertices []float32, indices []uint16, mode graphicsdriver.Blend, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform) {
 	backendsM.Lock()
 	defer backendsM.Unlock()
-	i.drawTriangles(srcs, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, subimageOffsets, shader, uniforms, evenOdd, false)
+
+	var extraDsts [graphics.ShaderDstImageNum - 1]*Image
+	copy(extraDsts[:], dsts[1:])
+	dsts[0].drawTriangles(srcs, vertices, indices, nil, affine.ColorMIdentity{}, mode, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, srcRegion, subimageOffsets, shader, uniforms, graphicsdriver.FillAll, extraDsts, false)
 }
 
-func (i *Image) drawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool, keepOnAtlas bool) {
+func (i *Image) drawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []float32, indices []uint16, instances []float32, colorm affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform, fillRule graphicsdriver.FillRule, extraDsts [graphics.ShaderDstImageNum - 1]*Image, keepOnAtlas bool) {
 	if i.disposed {
 		panic("atlas: the drawing target image must not be disposed (DrawTriangles)")
 	}
@@ -416,8 +461,25 @@ func (i *Image) drawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []f
 		i.ensureIsolated()
 	}
 
+	// The extra destinations for multiple render targets are never on an atlas.
+	for _, dst := range extraDsts {
+		if dst == nil {
+			continue
+		}
+		if dst.disposed {
+			panic("atlas: the drawing target image must not be disposed (DrawTriangles)")
+		}
+		dst.ensureIsolated()
+	}
+
 	for _, src := range srcs {
 		i.processSrc(src)
+		for _, dst := range extraDsts {
+			if dst == nil {
+				continue
+			}
+			dst.processSrc(src)
+		}
 	}
 
 	cr := float32(1)
@@ -503,7 +565,21 @@ func (i *Image) drawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []f
 		}
 	}
 
-	i.backend.restorable.DrawTriangles(imgs, offsets, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, s, uniforms, evenOdd)
+	// The offsets in the instances are relative to the vertices, and don't have to be adjusted.
+	if extraDsts[0] != nil {
+		dsts := [graphics.ShaderDstImageNum]*restorable.Image{i.backend.restorable}
+		for j, dst := range extraDsts {
+			if dst == nil {
+				continue
+			}
+			dsts[j+1] = dst.backend.restorable
+		}
+		restorable.DrawTrianglesMRT(dsts, imgs, offsets, vertices, indices, mode, dstRegion, srcRegion, s, uniforms)
+	} else if instances != nil {
+		i.backend.restorable.DrawTrianglesInstanced(imgs[0], vertices, indices, instances, colorm, mode, filter, address, dstRegion, srcRegion)
+	} else {
+		i.backend.restorable.DrawTriangles(imgs, offsets, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, s, uniforms, fillRule)
+	}
 
 	for _, src := range srcs {
 		if src == nil {
@@ -601,6 +677,29 @@ func (img *Image) Pixels(x, y, width, height int) ([]byte, error) {
 	return bs, nil
 }
 
+// ReadPixelsAsync reads the pixels in the given region without waiting for GPU.
+// f is called with the pixels a few frames later.
+func (i *Image) ReadPixelsAsync(x, y, width, height int, f func([]byte)) {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+
+	if i.disposed {
+		panic("atlas: the image must not be disposed at ReadPixelsAsync")
+	}
+
+	if i.backend == nil {
+		i.allocate(true)
+	}
+
+	// A screen image doesn't have its padding.
+	if !i.screen {
+		ox, oy, _, _ := i.regionWithPadding()
+		x += ox + paddingSize
+		y += oy + paddingSize
+	}
+	i.backend.restorable.ReadPixelsAsync(x, y, width, height, f)
+}
+
 func (i *Image) at(x, y int) (byte, byte, byte, byte, error) {
 	if i.backend == nil {
 		return 0, 0, 0, 0, nil
@@ -684,10 +783,39 @@ func NewImage(width, height int) *Image {
 	}
 }
 
+// IsOnAtlas reports whether the image is currently on an atlas.
+func (i *Image) IsOnAtlas() bool {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+	return i.isOnAtlas()
+}
+
 func (i *Image) SetIndependent(independent bool) {
 	i.independent = independent
 }
 
+// SetSampleCount sets the number of samples per pixel for multisample antialiasing.
+// A multisampled image is never put on an atlas.
+//
+// SetSampleCount must be called before the image is used.
+func (i *Image) SetSampleCount(sampleCount int) {
+	if i.backend != nil {
+		panic("atlas: SetSampleCount must be called before the image is used")
+	}
+	i.sampleCount = sampleCount
+}
+
+// SetFormat sets the pixel format of the image's texture.
+// An image whose format is not PixelFormatRGBA8 is never put on an atlas.
+//
+// SetFormat must be called before the image is used.
+func (i *Image) SetFormat(format graphicsdriver.PixelFormat) {
+	if i.backend != nil {
+		panic("atlas: SetFormat must be called before the image is used")
+	}
+	i.format = format
+}
+
 func (i *Image) SetVolatile(volatile bool) {
 	i.volatile = volatile
 	if i.backend == nil {
@@ -712,6 +840,12 @@ func (i *Image) canBePutOnAtlas() bool {
 	if i.screen {
 		return false
 	}
+	if i.sampleCount > 1 {
+		return false
+	}
+	if i.format != graphicsdriver.PixelFormatRGBA8 {
+		return false
+	}
 	return i.width+2*paddingSize <= maxSize && i.height+2*paddingSize <= maxSize
 }
 
@@ -732,7 +866,7 @@ func (i *Image) allocate(putOnAtlas bool) {
 
 	if !putOnAtlas || !i.canBePutOnAtlas() {
 		i.backend = &backend{
-			restorable: restorable.NewImage(i.width+2*paddingSize, i.height+2*paddingSize),
+			restorable: restorable.NewImageWithAttributes(i.width+2*paddingSize, i.height+2*paddingSize, i.sampleCount, i.format),
 		}
 		i.backend.restorable.SetVolatile(i.volatile)
 		return
@@ -759,6 +893,7 @@ func (i *Image) allocate(putOnAtlas bool) {
 	}
 	b.restorable.SetVolatile(i.volatile)
 	theBackends = append(theBackends, b)
+	reallocations++
 
 	n := b.page.Alloc(i.width+2*paddingSize, i.height+2*paddingSize)
 	if n == nil {
@@ -768,13 +903,6 @@ func (i *Image) allocate(putOnAtlas bool) {
 	i.node = n
 }
 
-func (i *Image) DumpScreenshot(path string, blackbg bool) error {
-	backendsM.Lock()
-	defer backendsM.Unlock()
-
-	return i.backend.restorable.Dump(path, blackbg, image.Rect(paddingSize, paddingSize, paddingSize+i.width, paddingSize+i.height))
-}
-
 func NewScreenFramebufferImage(width, height int) *Image {
 	// Actual allocation is done lazily.
 	i := &Image{
@@ -788,6 +916,9 @@ func NewScreenFramebufferImage(width, height int) *Image {
 func EndFrame() error {
 	backendsM.Lock()
 
+	atomic.StoreInt64(&lastReallocations, int64(reallocations))
+	reallocations = 0
+
 	theTemporaryPixels.resetAtFrameEnd()
 
 	return restorable.ResolveStaleImages()
@@ -820,6 +951,44 @@ func BeginFrame() error {
 	return restorable.RestoreIfNeeded()
 }
 
+// PushDebugGroup starts a group of the following image operations with the given name for graphics debugging
+// tools.
+func PushDebugGroup(name string) {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+	restorable.PushDebugGroup(name)
+}
+
+// PopDebugGroup ends the group started by the last PushDebugGroup.
+func PopDebugGroup() {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+	restorable.PopDebugGroup()
+}
+
+// Usage returns the number of the atlases, and the used and the total areas of them in pixels.
+func Usage() (atlases int, usedArea int, totalArea int) {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+
+	for _, b := range theBackends {
+		if b.page == nil {
+			continue
+		}
+		atlases++
+		usedArea += b.page.UsedArea()
+		totalArea += b.page.Size() * b.page.Size()
+	}
+	return
+}
+
+// LastFrameReallocations returns the number of the times when an atlas was created or extended in the last frame.
+//
+// LastFrameReallocations is concurrent-safe.
+func LastFrameReallocations() int {
+	return int(atomic.LoadInt64(&lastReallocations))
+}
+
 func DumpImages(dir string) error {
 	backendsM.Lock()
 	defer backendsM.Unlock()
@@ -22,6 +22,7 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/internal/affine"
 	"github.com/hajimehoshi/ebiten/v2/internal/atlas"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicscommand"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	t "github.com/hajimehoshi/ebiten/v2/internal/testing"
 )
@@ -103,9 +104,9 @@ func TestEnsureIsolated(t *testing.T) {
 		Width:  size,
 		Height: size,
 	}
-	img4.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img3}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
+	img4.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img3}, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll)
 	want := false
-	if got := img4.IsOnAtlasForTesting(); got != want {
+	if got := img4.IsOnAtlas(); got != want {
 		t.Errorf("got: %v, want: %v", got, want)
 	}
 
@@ -133,7 +134,7 @@ func TestEnsureIsolated(t *testing.T) {
 
 	// Check further drawing doesn't cause panic.
 	// This bug was fixed by 03dcd948.
-	img4.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img3}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
+	img4.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img3}, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll)
 }
 
 func TestReputOnAtlas(t *testing.T) {
@@ -146,7 +147,7 @@ func TestReputOnAtlas(t *testing.T) {
 	img1 := atlas.NewImage(size, size)
 	defer img1.MarkDisposed()
 	img1.ReplacePixels(make([]byte, 4*size*size))
-	if got, want := img1.IsOnAtlasForTesting(), true; got != want {
+	if got, want := img1.IsOnAtlas(), true; got != want {
 		t.Errorf("got: %v, want: %v", got, want)
 	}
 
@@ -167,7 +168,7 @@ func TestReputOnAtlas(t *testing.T) {
 	img3.SetVolatile(true)
 	defer img3.MarkDisposed()
 	img1.ReplacePixels(make([]byte, 4*size*size))
-	if got, want := img3.IsOnAtlasForTesting(), false; got != want {
+	if got, want := img3.IsOnAtlas(), false; got != want {
 		t.Errorf("got: %v, want: %v", got, want)
 	}
 
@@ -180,8 +181,8 @@ func TestReputOnAtlas(t *testing.T) {
 		Width:  size,
 		Height: size,
 	}
-	img1.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img2}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
-	if got, want := img1.IsOnAtlasForTesting(), false; got != want {
+	img1.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img2}, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll)
+	if got, want := img1.IsOnAtlas(), false; got != want {
 		t.Errorf("got: %v, want: %v", got, want)
 	}
 
@@ -192,8 +193,8 @@ func TestReputOnAtlas(t *testing.T) {
 		if err := atlas.PutImagesOnAtlasForTesting(); err != nil {
 			t.Fatal(err)
 		}
-		img0.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img1}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
-		if got, want := img1.IsOnAtlasForTesting(), false; got != want {
+		img0.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img1}, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll)
+		if got, want := img1.IsOnAtlas(), false; got != want {
 			t.Errorf("got: %v, want: %v", got, want)
 		}
 	}
@@ -220,8 +221,8 @@ func TestReputOnAtlas(t *testing.T) {
 	}
 
 	// img1 is on an atlas again.
-	img0.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img1}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
-	if got, want := img1.IsOnAtlasForTesting(), true; got != want {
+	img0.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img1}, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll)
+	if got, want := img1.IsOnAtlas(), true; got != want {
 		t.Errorf("got: %v, want: %v", got, want)
 	}
 
@@ -244,8 +245,8 @@ func TestReputOnAtlas(t *testing.T) {
 	}
 
 	// Use img1 as a render target again.
-	img1.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img2}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
-	if got, want := img1.IsOnAtlasForTesting(), false; got != want {
+	img1.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img2}, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll)
+	if got, want := img1.IsOnAtlas(), false; got != want {
 		t.Errorf("got: %v, want: %v", got, want)
 	}
 
@@ -256,8 +257,8 @@ func TestReputOnAtlas(t *testing.T) {
 			t.Fatal(err)
 		}
 		img1.ReplacePixels(make([]byte, 4*size*size))
-		img0.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img1}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
-		if got, want := img1.IsOnAtlasForTesting(), false; got != want {
+		img0.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img1}, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll)
+		if got, want := img1.IsOnAtlas(), false; got != want {
 			t.Errorf("got: %v, want: %v", got, want)
 		}
 	}
@@ -266,8 +267,8 @@ func TestReputOnAtlas(t *testing.T) {
 	}
 
 	// img1 is not on an atlas due to ReplacePixels.
-	img0.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img1}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
-	if got, want := img1.IsOnAtlasForTesting(), false; got != want {
+	img0.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img1}, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll)
+	if got, want := img1.IsOnAtlas(), false; got != want {
 		t.Errorf("got: %v, want: %v", got, want)
 	}
 
@@ -276,8 +277,8 @@ func TestReputOnAtlas(t *testing.T) {
 		if err := atlas.PutImagesOnAtlasForTesting(); err != nil {
 			t.Fatal(err)
 		}
-		img0.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img3}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
-		if got, want := img3.IsOnAtlasForTesting(), false; got != want {
+		img0.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{img3}, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll)
+		if got, want := img3.IsOnAtlas(), false; got != want {
 			t.Errorf("got: %v, want: %v", got, want)
 		}
 	}
@@ -376,7 +377,7 @@ func TestReplacePixelsAfterDrawTriangles(t *testing.T) {
 		Width:  w,
 		Height: h,
 	}
-	dst.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{src}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
+	dst.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{src}, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll)
 	dst.ReplacePixels(pix)
 
 	pix, err := dst.Pixels(0, 0, w, h)
@@ -424,7 +425,7 @@ func TestSmallImages(t *testing.T) {
 		Width:  w,
 		Height: h,
 	}
-	dst.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{src}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
+	dst.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{src}, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll)
 
 	pix, err := dst.Pixels(0, 0, w, h)
 	if err != nil {
@@ -472,7 +473,7 @@ func TestLongImages(t *testing.T) {
 		Width:  dstW,
 		Height: dstH,
 	}
-	dst.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{src}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
+	dst.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{src}, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll)
 
 	pix, err := dst.Pixels(0, 0, dstW, dstH)
 	if err != nil {
@@ -561,8 +562,8 @@ func TestDisposedAndReputOnAtlas(t *testing.T) {
 		Width:  size,
 		Height: size,
 	}
-	src.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{src2}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
-	if got, want := src.IsOnAtlasForTesting(), false; got != want {
+	src.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{src2}, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll)
+	if got, want := src.IsOnAtlas(), false; got != want {
 		t.Errorf("got: %v, want: %v", got, want)
 	}
 
@@ -571,8 +572,8 @@ func TestDisposedAndReputOnAtlas(t *testing.T) {
 		if err := atlas.PutImagesOnAtlasForTesting(); err != nil {
 			t.Fatal(err)
 		}
-		dst.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{src}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
-		if got, want := src.IsOnAtlasForTesting(), false; got != want {
+		dst.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{src}, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll)
+		if got, want := src.IsOnAtlas(), false; got != want {
 			t.Errorf("got: %v, want: %v", got, want)
 		}
 	}
@@ -611,8 +612,8 @@ func TestImageIsNotReputOnAtlasWithoutUsingAsSource(t *testing.T) {
 	}
 
 	// Use src2 as a rendering target, and make src2 an independent image.
-	src2.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{src}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
-	if got, want := src2.IsOnAtlasForTesting(), false; got != want {
+	src2.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{src}, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll)
+	if got, want := src2.IsOnAtlas(), false; got != want {
 		t.Errorf("got: %v, want: %v", got, want)
 	}
 
@@ -622,7 +623,7 @@ func TestImageIsNotReputOnAtlasWithoutUsingAsSource(t *testing.T) {
 		if err := atlas.PutImagesOnAtlasForTesting(); err != nil {
 			t.Fatal(err)
 		}
-		if got, want := src2.IsOnAtlasForTesting(), false; got != want {
+		if got, want := src2.IsOnAtlas(), false; got != want {
 			t.Errorf("got: %v, want: %v", got, want)
 		}
 	}
@@ -632,8 +633,8 @@ func TestImageIsNotReputOnAtlasWithoutUsingAsSource(t *testing.T) {
 		if err := atlas.PutImagesOnAtlasForTesting(); err != nil {
 			t.Fatal(err)
 		}
-		dst.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{src2}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
-		if got, want := src2.IsOnAtlasForTesting(), false; got != want {
+		dst.DrawTriangles([graphics.ShaderImageNum]*atlas.Image{src2}, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll)
+		if got, want := src2.IsOnAtlas(), false; got != want {
 			t.Errorf("got: %v, want: %v", got, want)
 		}
 	}
@@ -641,9 +642,62 @@ func TestImageIsNotReputOnAtlasWithoutUsingAsSource(t *testing.T) {
 	if err := atlas.PutImagesOnAtlasForTesting(); err != nil {
 		t.Fatal(err)
 	}
-	if got, want := src2.IsOnAtlasForTesting(), true; got != want {
+	if got, want := src2.IsOnAtlas(), true; got != want {
 		t.Errorf("got: %v, want: %v", got, want)
 	}
 }
 
 // TODO: Add tests to extend image on an atlas out of the main loop
+
+func TestReadPixelsAsync(t *testing.T) {
+	const w, h = 16, 16
+
+	// Create another image first so that img is not at the origin of the atlas.
+	img0 := atlas.NewImage(w, h)
+	defer img0.MarkDisposed()
+	img0.ReplacePixels(make([]byte, 4*w*h))
+
+	img := atlas.NewImage(w, h)
+	defer img.MarkDisposed()
+	pix := make([]byte, 4*w*h)
+	for i := 0; i < w*h; i++ {
+		pix[4*i] = byte(i)
+		pix[4*i+1] = byte(i)
+		pix[4*i+2] = byte(i)
+		pix[4*i+3] = 0xff
+	}
+	img.ReplacePixels(pix)
+
+	const x, y, sw, sh = 2, 3, 5, 4
+	var result []byte
+	img.ReadPixelsAsync(x, y, sw, sh, func(pix []byte) {
+		result = pix
+	})
+
+	for i := 0; i < 100 && result == nil; i++ {
+		if err := atlas.EndFrame(); err != nil {
+			t.Fatal(err)
+		}
+		if err := atlas.BeginFrame(); err != nil {
+			t.Fatal(err)
+		}
+		graphicscommand.RunAsyncCallbacks()
+	}
+	if result == nil {
+		t.Fatal("the callback was not called")
+	}
+	if len(result) != 4*sw*sh {
+		t.Fatalf("len(pixels): got: %d, want: %d", len(result), 4*sw*sh)
+	}
+	for j := 0; j < sh; j++ {
+		for i := 0; i < sw; i++ {
+			idx := 4 * (j*sw + i)
+			c := byte((x + i) + w*(y+j))
+			got := color.RGBA{result[idx], result[idx+1], result[idx+2], result[idx+3]}
+			want := color.RGBA{c, c, c, 0xff}
+			if got != want {
+				t.Errorf("at(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
@@ -17,6 +17,7 @@ package atlas
 import (
 	"runtime"
 
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/restorable"
 	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
 )
@@ -33,6 +34,23 @@ func NewShader(program *shaderir.Program) *Shader {
 	return s
 }
 
+// NewShaderAsync creates a shader without waiting for the compilation.
+// f is called after the shader is compiled.
+func NewShaderAsync(program *shaderir.Program, f func()) *Shader {
+	s := &Shader{
+		shader: restorable.NewShaderAsync(program, f),
+	}
+	runtime.SetFinalizer(s, (*Shader).MarkDisposed)
+	return s
+}
+
+// Precompile creates the pipeline states of the shader for the given destination format and blends in advance.
+func (s *Shader) Precompile(format graphicsdriver.PixelFormat, blends []graphicsdriver.Blend) {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+	s.shader.Precompile(format, blends)
+}
+
 // MarkDisposed marks the shader as disposed. The actual operation is deferred.
 // MarkDisposed can be called from finalizers.
 //
@@ -0,0 +1,262 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffered
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/affine"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+)
+
+// frameHasher calculates a hash of the image operations in a frame.
+//
+// The hash is calculated from the operations at this layer, which are independent from texture atlases and
+// graphics drivers. Then, the hash is the same across platforms as long as the operations are the same.
+// Images and shaders are identified with serial numbers in the order of their creation.
+type frameHasher struct {
+	current  hash.Hash64
+	last     uint64
+	buf      []byte
+	imageID  int
+	shaderID int
+
+	m sync.Mutex
+}
+
+var theFrameHasher = &frameHasher{
+	current: fnv.New64a(),
+}
+
+// FrameHash returns the hash of the image operations in the last frame.
+// The operations on the screen framebuffer are not included.
+func FrameHash() uint64 {
+	theFrameHasher.m.Lock()
+	defer theFrameHasher.m.Unlock()
+	return theFrameHasher.last
+}
+
+func (f *frameHasher) endFrame() {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.last = f.current.Sum64()
+	f.current.Reset()
+}
+
+func (f *frameHasher) newImageID() int {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.imageID++
+	return f.imageID
+}
+
+func (f *frameHasher) newShaderID() int {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.shaderID++
+	return f.shaderID
+}
+
+func (f *frameHasher) writeInts(vs ...int) {
+	var b [8]byte
+	for _, v := range vs {
+		binary.LittleEndian.PutUint64(b[:], uint64(v))
+		f.buf = append(f.buf, b[:]...)
+	}
+}
+
+func (f *frameHasher) writeFloat32s(vs []float32) {
+	var b [4]byte
+	for _, v := range vs {
+		binary.LittleEndian.PutUint32(b[:], math.Float32bits(v))
+		f.buf = append(f.buf, b[:]...)
+	}
+}
+
+func (f *frameHasher) writeRegion(r graphicsdriver.Region) {
+	f.writeFloat32s([]float32{r.X, r.Y, r.Width, r.Height})
+}
+
+func (f *frameHasher) writeBlend(blend graphicsdriver.Blend) {
+	f.buf = append(f.buf,
+		byte(blend.BlendFactorSourceRGB), byte(blend.BlendFactorSourceAlpha),
+		byte(blend.BlendFactorDestinationRGB), byte(blend.BlendFactorDestinationAlpha),
+		byte(blend.BlendOperationRGB), byte(blend.BlendOperationAlpha))
+}
+
+func (f *frameHasher) flush() {
+	f.current.Write(f.buf)
+	f.buf = f.buf[:0]
+}
+
+// Operation kinds written at the head of each operation.
+const (
+	hashOpNewImage = iota
+	hashOpDispose
+	hashOpReplacePixels
+	hashOpDrawTriangles
+	hashOpDrawTrianglesInstanced
+	hashOpDrawTrianglesMRT
+)
+
+func (f *frameHasher) hashNewImage(img *Image) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.writeInts(hashOpNewImage, img.id, img.width, img.height)
+	f.flush()
+}
+
+func (f *frameHasher) hashDispose(img *Image) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.writeInts(hashOpDispose, img.id)
+	f.flush()
+}
+
+func (f *frameHasher) hashReplacePixels(img *Image, pix []byte, x, y, width, height int) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.writeInts(hashOpReplacePixels, img.id, x, y, width, height)
+	f.buf = append(f.buf, pix...)
+	f.flush()
+}
+
+func (f *frameHasher) hashDrawTriangles(dst *Image, srcs []*Image, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform, fillRule graphicsdriver.FillRule) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	f.writeInts(hashOpDrawTriangles, dst.id)
+	for _, src := range srcs {
+		id := 0
+		if src != nil {
+			id = src.id
+		}
+		f.writeInts(id)
+	}
+	shaderID := 0
+	if shader != nil {
+		shaderID = shader.id
+	}
+	f.writeInts(shaderID, int(filter), int(address), int(fillRule))
+	f.writeBlend(mode)
+
+	if colorm != nil {
+		var body [16]float32
+		var translate [4]float32
+		colorm.Elements(&body, &translate)
+		f.writeFloat32s(body[:])
+		f.writeFloat32s(translate[:])
+	}
+
+	f.writeRegion(dstRegion)
+	f.writeRegion(srcRegion)
+	for _, o := range subimageOffsets {
+		f.writeFloat32s(o[:])
+	}
+	for _, u := range uniforms {
+		f.writeFloat32s([]float32{u.Float32})
+		f.writeInts(len(u.Float32s))
+		f.writeFloat32s(u.Float32s)
+	}
+
+	f.writeInts(len(vertices))
+	f.writeFloat32s(vertices)
+	f.writeInts(len(indices))
+	var b [2]byte
+	for _, idx := range indices {
+		binary.LittleEndian.PutUint16(b[:], idx)
+		f.buf = append(f.buf, b[:]...)
+	}
+	f.flush()
+}
+
+func (f *frameHasher) hashDrawTrianglesInstanced(dst *Image, src *Image, vertices []float32, indices []uint16, instances []float32, colorm affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	f.writeInts(hashOpDrawTrianglesInstanced, dst.id, src.id, int(filter), int(address))
+	f.writeBlend(mode)
+
+	if colorm != nil {
+		var body [16]float32
+		var translate [4]float32
+		colorm.Elements(&body, &translate)
+		f.writeFloat32s(body[:])
+		f.writeFloat32s(translate[:])
+	}
+
+	f.writeRegion(dstRegion)
+	f.writeRegion(srcRegion)
+
+	f.writeInts(len(vertices))
+	f.writeFloat32s(vertices)
+	f.writeInts(len(indices))
+	var b [2]byte
+	for _, idx := range indices {
+		binary.LittleEndian.PutUint16(b[:], idx)
+		f.buf = append(f.buf, b[:]...)
+	}
+	f.writeInts(len(instances))
+	f.writeFloat32s(instances)
+	f.flush()
+}
+
+func (f *frameHasher) hashDrawTrianglesMRT(dsts []*Image, srcs []*Image, vertices []float32, indices []uint16, mode graphicsdriver.Blend, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	f.writeInts(hashOpDrawTrianglesMRT)
+	for _, dst := range dsts {
+		id := 0
+		if dst != nil {
+			id = dst.id
+		}
+		f.writeInts(id)
+	}
+	for _, src := range srcs {
+		id := 0
+		if src != nil {
+			id = src.id
+		}
+		f.writeInts(id)
+	}
+	f.writeInts(shader.id)
+	f.writeBlend(mode)
+
+	f.writeRegion(dstRegion)
+	f.writeRegion(srcRegion)
+	for _, o := range subimageOffsets {
+		f.writeFloat32s(o[:])
+	}
+	for _, u := range uniforms {
+		f.writeFloat32s([]float32{u.Float32})
+		f.writeInts(len(u.Float32s))
+		f.writeFloat32s(u.Float32s)
+	}
+
+	f.writeInts(len(vertices))
+	f.writeFloat32s(vertices)
+	f.writeInts(len(indices))
+	var b [2]byte
+	for _, idx := range indices {
+		binary.LittleEndian.PutUint16(b[:], idx)
+		f.buf = append(f.buf, b[:]...)
+	}
+	f.flush()
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffered
+
+import (
+	"hash/fnv"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/affine"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+)
+
+func TestFrameHash(t *testing.T) {
+	frame := func(x float32, colorm affine.ColorM) uint64 {
+		f := &frameHasher{
+			current: fnv.New64a(),
+		}
+		dst := &Image{id: 1, width: 16, height: 16}
+		src := &Image{id: 2, width: 16, height: 16}
+		f.hashNewImage(dst)
+		f.hashNewImage(src)
+		f.hashReplacePixels(src, make([]byte, 4*16*16), 0, 0, 16, 16)
+		vs := []float32{
+			x, 0, 0, 0, 1, 1, 1, 1,
+			16, 0, 16, 0, 1, 1, 1, 1,
+			0, 16, 0, 16, 1, 1, 1, 1,
+		}
+		f.hashDrawTriangles(dst, []*Image{src, nil, nil, nil}, vs, []uint16{0, 1, 2}, colorm, graphicsdriver.BlendSourceOver, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, graphicsdriver.Region{Width: 16, Height: 16}, graphicsdriver.Region{}, nil, nil, nil, graphicsdriver.FillAll)
+		f.endFrame()
+		return f.last
+	}
+
+	h0 := frame(0, affine.ColorMIdentity{})
+	if h1 := frame(0, affine.ColorMIdentity{}); h0 != h1 {
+		t.Errorf("the same operations must have the same hash: %x vs %x", h0, h1)
+	}
+	if h1 := frame(1, affine.ColorMIdentity{}); h0 == h1 {
+		t.Errorf("different vertices must have a different hash: %x", h0)
+	}
+	if h1 := frame(0, affine.ColorMIdentity{}.Scale(1, 0, 0, 1)); h0 == h1 {
+		t.Errorf("a different color matrix must have a different hash: %x", h0)
+	}
+}
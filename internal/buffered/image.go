@@ -29,6 +29,9 @@ type Image struct {
 	img    *atlas.Image
 	width  int
 	height int
+	id     int
+	screen bool
+	format graphicsdriver.PixelFormat
 
 	pixels               []byte
 	needsToResolvePixels bool
@@ -42,11 +45,41 @@ func BeginFrame() error {
 }
 
 func EndFrame() error {
+	theFrameHasher.endFrame()
 	return atlas.EndFrame()
 }
 
+// PushDebugGroup starts a group of the following image operations with the given name for graphics debugging
+// tools.
+func PushDebugGroup(name string) {
+	if maybeCanAddDelayedCommand() {
+		if tryAddDelayedCommand(func() error {
+			PushDebugGroup(name)
+			return nil
+		}) {
+			return
+		}
+	}
+	atlas.PushDebugGroup(name)
+}
+
+// PopDebugGroup ends the group started by the last PushDebugGroup.
+func PopDebugGroup() {
+	if maybeCanAddDelayedCommand() {
+		if tryAddDelayedCommand(func() error {
+			PopDebugGroup()
+			return nil
+		}) {
+			return
+		}
+	}
+	atlas.PopDebugGroup()
+}
+
 func NewImage(width, height int) *Image {
-	i := &Image{}
+	i := &Image{
+		id: theFrameHasher.newImageID(),
+	}
 	i.initialize(width, height)
 	return i
 }
@@ -63,6 +96,7 @@ func (i *Image) initialize(width, height int) {
 	i.img = atlas.NewImage(width, height)
 	i.width = width
 	i.height = height
+	theFrameHasher.hashNewImage(i)
 }
 
 func (i *Image) SetIndependent(independent bool) {
@@ -77,6 +111,40 @@ func (i *Image) SetIndependent(independent bool) {
 	i.img.SetIndependent(independent)
 }
 
+// IsOnAtlas reports whether the image is currently on an atlas.
+// IsOnAtlas returns false before the image is actually created.
+func (i *Image) IsOnAtlas() bool {
+	if maybeCanAddDelayedCommand() {
+		return false
+	}
+	return i.img.IsOnAtlas()
+}
+
+func (i *Image) SetSampleCount(sampleCount int) {
+	if maybeCanAddDelayedCommand() {
+		if tryAddDelayedCommand(func() error {
+			i.SetSampleCount(sampleCount)
+			return nil
+		}) {
+			return
+		}
+	}
+	i.img.SetSampleCount(sampleCount)
+}
+
+func (i *Image) SetFormat(format graphicsdriver.PixelFormat) {
+	if maybeCanAddDelayedCommand() {
+		if tryAddDelayedCommand(func() error {
+			i.SetFormat(format)
+			return nil
+		}) {
+			return
+		}
+	}
+	i.format = format
+	i.img.SetFormat(format)
+}
+
 func (i *Image) SetVolatile(volatile bool) {
 	if maybeCanAddDelayedCommand() {
 		if tryAddDelayedCommand(func() error {
@@ -90,7 +158,10 @@ func (i *Image) SetVolatile(volatile bool) {
 }
 
 func NewScreenFramebufferImage(width, height int) *Image {
-	i := &Image{}
+	i := &Image{
+		id:     theFrameHasher.newImageID(),
+		screen: true,
+	}
 	i.initializeAsScreenFramebuffer(width, height)
 	return i
 }
@@ -136,34 +207,51 @@ func (i *Image) MarkDisposed() {
 	}
 	i.invalidatePendingPixels()
 	i.img.MarkDisposed()
+	if !i.screen {
+		theFrameHasher.hashDispose(i)
+	}
 }
 
-func (img *Image) Pixels(x, y, width, height int) (pix []byte, err error) {
-	checkDelayedCommandsFlushed("Pixels")
+// ReadPixels reads the pixels in the given region into pixels.
+// len(pixels) must be 4 * width * height.
+func (img *Image) ReadPixels(pixels []byte, x, y, width, height int) error {
+	checkDelayedCommandsFlushed("ReadPixels")
 
 	if !image.Rect(x, y, x+width, y+height).In(image.Rect(0, 0, img.width, img.height)) {
-		return nil, fmt.Errorf("buffered: out of range")
+		return fmt.Errorf("buffered: out of range")
+	}
+	if len(pixels) != 4*width*height {
+		return fmt.Errorf("buffered: len(pixels) must be %d but %d", 4*width*height, len(pixels))
 	}
-
-	pix = make([]byte, 4*width*height)
 
 	if img.pixels == nil {
 		pix, err := img.img.Pixels(0, 0, img.width, img.height)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		img.pixels = pix
 	}
 
 	for j := 0; j < height; j++ {
-		copy(pix[4*j*width:4*(j+1)*width], img.pixels[4*((j+y)*img.width+x):])
+		copy(pixels[4*j*width:4*(j+1)*width], img.pixels[4*((j+y)*img.width+x):])
 	}
-	return pix, nil
+	return nil
 }
 
-func (i *Image) DumpScreenshot(name string, blackbg bool) error {
-	checkDelayedCommandsFlushed("Dump")
-	return i.img.DumpScreenshot(name, blackbg)
+// ReadPixelsAsync reads the pixels in the given region without waiting for GPU.
+// f is called with the pixels a few frames later.
+func (i *Image) ReadPixelsAsync(x, y, width, height int, f func([]byte)) {
+	if maybeCanAddDelayedCommand() {
+		if tryAddDelayedCommand(func() error {
+			i.ReadPixelsAsync(x, y, width, height, f)
+			return nil
+		}) {
+			return
+		}
+	}
+
+	i.resolvePendingPixels(true)
+	i.img.ReadPixelsAsync(x, y, width, height, f)
 }
 
 func (i *Image) ReplacePixels(pix []byte, x, y, width, height int) error {
@@ -182,6 +270,13 @@ func (i *Image) ReplacePixels(pix []byte, x, y, width, height int) error {
 		}
 	}
 
+	// Drop the channels the format doesn't have so that the pending pixels match the pixels on GPU.
+	pix = i.format.UnpackPixels(i.format.PackPixels(pix))
+
+	if !i.screen {
+		theFrameHasher.hashReplacePixels(i, pix, x, y, width, height)
+	}
+
 	if x == 0 && y == 0 && width == i.width && height == i.height {
 		i.invalidatePendingPixels()
 
@@ -214,7 +309,7 @@ func (i *Image) replacePendingPixels(pix []byte, x, y, width, height int) {
 // DrawTriangles draws the src image with the given vertices.
 //
 // Copying vertices and indices is the caller's responsibility.
-func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool) {
+func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform, fillRule graphicsdriver.FillRule) {
 	for _, src := range srcs {
 		if i == src {
 			panic("buffered: Image.DrawTriangles: source images must be different from the receiver")
@@ -224,13 +319,17 @@ func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []f
 	if maybeCanAddDelayedCommand() {
 		if tryAddDelayedCommand(func() error {
 			// Arguments are not copied. Copying is the caller's responsibility.
-			i.DrawTriangles(srcs, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, subimageOffsets, shader, uniforms, evenOdd)
+			i.DrawTriangles(srcs, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, subimageOffsets, shader, uniforms, fillRule)
 			return nil
 		}) {
 			return
 		}
 	}
 
+	if !i.screen {
+		theFrameHasher.hashDrawTriangles(i, srcs[:], vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, subimageOffsets[:], shader, uniforms, fillRule)
+	}
+
 	var s *atlas.Shader
 	var imgs [graphics.ShaderImageNum]*atlas.Image
 	if shader == nil {
@@ -250,20 +349,121 @@ func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, vertices []f
 	}
 	i.resolvePendingPixels(false)
 
-	i.img.DrawTriangles(imgs, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, subimageOffsets, s, uniforms, evenOdd)
+	i.img.DrawTriangles(imgs, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, subimageOffsets, s, uniforms, fillRule)
 	i.invalidatePendingPixels()
 }
 
+// DrawTrianglesInstanced draws the src image with the given vertices repeatedly for each instance.
+//
+// Copying vertices, indices and instances is the caller's responsibility.
+func (i *Image) DrawTrianglesInstanced(src *Image, vertices []float32, indices []uint16, instances []float32, colorm affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region) {
+	if i == src {
+		panic("buffered: Image.DrawTrianglesInstanced: the source image must be different from the receiver")
+	}
+
+	if maybeCanAddDelayedCommand() {
+		if tryAddDelayedCommand(func() error {
+			// Arguments are not copied. Copying is the caller's responsibility.
+			i.DrawTrianglesInstanced(src, vertices, indices, instances, colorm, mode, filter, address, dstRegion, srcRegion)
+			return nil
+		}) {
+			return
+		}
+	}
+
+	if !i.screen {
+		theFrameHasher.hashDrawTrianglesInstanced(i, src, vertices, indices, instances, colorm, mode, filter, address, dstRegion, srcRegion)
+	}
+
+	src.resolvePendingPixels(true)
+	i.resolvePendingPixels(false)
+
+	i.img.DrawTrianglesInstanced(src.img, vertices, indices, instances, colorm, mode, filter, address, dstRegion, srcRegion)
+	i.invalidatePendingPixels()
+}
+
+// DrawTrianglesMRT draws triangles with the given shader onto the multiple destinations at the same time.
+//
+// The destinations are packed from the front in dsts, and the rest are nil.
+//
+// Copying vertices and indices is the caller's responsibility.
+func DrawTrianglesMRT(dsts [graphics.ShaderDstImageNum]*Image, srcs [graphics.ShaderImageNum]*Image, vertices []float32, indices []uint16, mode graphicsdriver.Blend, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform) {
+	for _, dst := range dsts {
+		if dst == nil {
+			continue
+		}
+		for _, src := range srcs {
+			if dst == src {
+				panic("buffered: DrawTrianglesMRT: source images must be different from the destinations")
+			}
+		}
+	}
+
+	if maybeCanAddDelayedCommand() {
+		if tryAddDelayedCommand(func() error {
+			// Arguments are not copied. Copying is the caller's responsibility.
+			DrawTrianglesMRT(dsts, srcs, vertices, indices, mode, dstRegion, srcRegion, subimageOffsets, shader, uniforms)
+			return nil
+		}) {
+			return
+		}
+	}
+
+	theFrameHasher.hashDrawTrianglesMRT(dsts[:], srcs[:], vertices, indices, mode, dstRegion, srcRegion, subimageOffsets[:], shader, uniforms)
+
+	var imgs [graphics.ShaderImageNum]*atlas.Image
+	for i, img := range srcs {
+		if img == nil {
+			continue
+		}
+		img.resolvePendingPixels(true)
+		imgs[i] = img.img
+	}
+	var dstImgs [graphics.ShaderDstImageNum]*atlas.Image
+	for i, dst := range dsts {
+		if dst == nil {
+			continue
+		}
+		dst.resolvePendingPixels(false)
+		dstImgs[i] = dst.img
+	}
+
+	atlas.DrawTrianglesMRT(dstImgs, imgs, vertices, indices, mode, dstRegion, srcRegion, subimageOffsets, shader.shader, uniforms)
+
+	for _, dst := range dsts {
+		if dst == nil {
+			continue
+		}
+		dst.invalidatePendingPixels()
+	}
+}
+
 type Shader struct {
 	shader *atlas.Shader
+	id     int
 }
 
 func NewShader(program *shaderir.Program) *Shader {
 	return &Shader{
 		shader: atlas.NewShader(program),
+		id:     theFrameHasher.newShaderID(),
 	}
 }
 
+// NewShaderAsync creates a shader without waiting for the compilation.
+// f is called after the shader is compiled.
+func NewShaderAsync(program *shaderir.Program, f func()) *Shader {
+	return &Shader{
+		shader: atlas.NewShaderAsync(program, f),
+		id:     theFrameHasher.newShaderID(),
+	}
+}
+
+// Precompile creates the pipeline states of the shader for the given destination format and blends in advance.
+func (s *Shader) Precompile(format graphicsdriver.PixelFormat, blends []graphicsdriver.Blend) {
+	s.shader.Precompile(format, blends)
+}
+
 func (s *Shader) MarkDisposed() {
 	s.shader.MarkDisposed()
 	s.shader = nil
@@ -33,6 +33,24 @@ var (
 	fpsCount    = 0
 	tpsCount    = 0
 
+	// frameCount is the number of frames so far.
+	frameCount uint64
+
+	// lastFrameDuration is the duration between the last two frames.
+	lastFrameDuration int64
+
+	// maxFrameDuration is the maximum frame duration in the current one-second period.
+	maxFrameDuration int64
+
+	// lastMaxFrameDuration is the maximum frame duration in the last one-second period.
+	lastMaxFrameDuration int64
+
+	// lastTickTime is the time of the last frame that updates the game.
+	lastTickTime int64
+
+	// deltaTime is the duration of the current tick.
+	deltaTime int64
+
 	m sync.Mutex
 )
 
@@ -57,6 +75,21 @@ func CurrentTPS() float64 {
 	return v
 }
 
+// FrameStatistics returns the number of frames so far, the duration between the last two frames, and the
+// maximum duration between two frames in the last one-second period.
+func FrameStatistics() (count uint64, last time.Duration, max time.Duration) {
+	m.Lock()
+	defer m.Unlock()
+	return frameCount, time.Duration(lastFrameDuration), time.Duration(lastMaxFrameDuration)
+}
+
+// DeltaTime returns the duration of the current tick.
+func DeltaTime() time.Duration {
+	m.Lock()
+	defer m.Unlock()
+	return time.Duration(deltaTime)
+}
+
 func max(a, b int64) int64 {
 	if a < b {
 		return b
@@ -120,6 +153,8 @@ func updateFPSAndTPS(now int64, count int) {
 	}
 	currentFPS = float64(fpsCount) * float64(time.Second) / float64(now-lastUpdated)
 	currentTPS = float64(tpsCount) * float64(time.Second) / float64(now-lastUpdated)
+	lastMaxFrameDuration = maxFrameDuration
+	maxFrameDuration = 0
 	lastUpdated = now
 	fpsCount = 0
 	tpsCount = 0
@@ -143,13 +178,29 @@ func Update(tps int) int {
 		// This ensures that now() must be monotonic (#875).
 		panic("clock: lastNow must be older than n")
 	}
+	// The duration before the first frame is not a frame duration.
+	if frameCount > 0 {
+		lastFrameDuration = n - lastNow
+		maxFrameDuration = max(maxFrameDuration, lastFrameDuration)
+	}
+	frameCount++
 	lastNow = n
 
 	c := 0
 	if tps == SyncWithFPS {
 		c = 1
+		// The first tick doesn't have the previous tick.
+		if lastTickTime != 0 {
+			deltaTime = n - lastTickTime
+		} else {
+			deltaTime = 0
+		}
 	} else if tps > 0 {
 		c = calcCountFromTPS(int64(tps), n)
+		deltaTime = int64(time.Second) / int64(tps)
+	}
+	if c > 0 {
+		lastTickTime = n
 	}
 	updateFPSAndTPS(n, c)
 
@@ -41,10 +41,9 @@ func GetAt(x, y int) float64 {
 }
 
 // CelarCache clears the cache.
-// This should be called when monitors are changed by connecting or disconnecting.
+// This should be called when monitors are changed by connecting or disconnecting, or when a monitor's scale is changed.
 func ClearCache() {
-	// TODO: This should be called not only when monitors are changed but also a monitor's scales are changed.
-	// The device scale can vary even for the same monitor.
+	// TODO: The device scale can vary even for the same monitor without notifications.
 	// The only known case is when the application works on macOS, with OpenGL, with a wider screen mode,
 	// and in the fullscreen mode (#1573).
 
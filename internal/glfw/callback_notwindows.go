@@ -24,6 +24,8 @@ import (
 var (
 	charModsCallbacks        = map[CharModsCallback]glfw.CharModsCallback{}
 	closeCallbacks           = map[CloseCallback]glfw.CloseCallback{}
+	contentScaleCallbacks    = map[ContentScaleCallback]glfw.ContentScaleCallback{}
+	dropCallbacks            = map[DropCallback]glfw.DropCallback{}
 	framebufferSizeCallbacks = map[FramebufferSizeCallback]glfw.FramebufferSizeCallback{}
 	scrollCallbacks          = map[ScrollCallback]glfw.ScrollCallback{}
 	sizeCallbacks            = map[SizeCallback]glfw.SizeCallback{}
@@ -53,6 +55,30 @@ func ToCloseCallback(cb func(window *Window)) CloseCallback {
 	return id
 }
 
+func ToContentScaleCallback(cb func(window *Window, xscale float32, yscale float32)) ContentScaleCallback {
+	if cb == nil {
+		return 0
+	}
+	id := ContentScaleCallback(len(contentScaleCallbacks) + 1)
+	var gcb glfw.ContentScaleCallback = func(window *glfw.Window, xscale float32, yscale float32) {
+		cb(theWindows.get(window), xscale, yscale)
+	}
+	contentScaleCallbacks[id] = gcb
+	return id
+}
+
+func ToDropCallback(cb func(window *Window, names []string)) DropCallback {
+	if cb == nil {
+		return 0
+	}
+	id := DropCallback(len(dropCallbacks) + 1)
+	var gcb glfw.DropCallback = func(window *glfw.Window, names []string) {
+		cb(theWindows.get(window), names)
+	}
+	dropCallbacks[id] = gcb
+	return id
+}
+
 func ToFramebufferSizeCallback(cb func(window *Window, width int, height int)) FramebufferSizeCallback {
 	if cb == nil {
 		return 0
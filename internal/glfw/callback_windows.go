@@ -15,6 +15,8 @@
 package glfw
 
 import (
+	"unsafe"
+
 	"golang.org/x/sys/windows"
 )
 
@@ -38,6 +40,35 @@ func ToCloseCallback(cb func(window *Window)) CloseCallback {
 	}))
 }
 
+func ToContentScaleCallback(cb func(window *Window, xscale float32, yscale float32)) ContentScaleCallback {
+	if cb == nil {
+		return 0
+	}
+	return ContentScaleCallback(windows.NewCallbackCDecl(func(window uintptr, xscale uintptr, yscale uintptr) uintptr {
+		// xscale and yscale are float32 values, but there is no way to receive float values via NewCallback.
+		// Get the current scale from the window instead.
+		w := theGLFWWindows.get(window)
+		x, y := w.GetContentScale()
+		cb(w, x, y)
+		return 0
+	}))
+}
+
+func ToDropCallback(cb func(window *Window, names []string)) DropCallback {
+	if cb == nil {
+		return 0
+	}
+	return DropCallback(windows.NewCallbackCDecl(func(window uintptr, count int, names **byte) uintptr {
+		ns := make([]string, 0, count)
+		for i := 0; i < count; i++ {
+			p := *(**byte)(unsafe.Pointer(uintptr(unsafe.Pointer(names)) + uintptr(i)*unsafe.Sizeof(names)))
+			ns = append(ns, bytePtrToString(p))
+		}
+		cb(theGLFWWindows.get(window), ns)
+		return 0
+	}))
+}
+
 func ToFramebufferSizeCallback(cb func(window *Window, width int, height int)) FramebufferSizeCallback {
 	if cb == nil {
 		return 0
@@ -67,6 +67,15 @@ func CreateStandardCursor(shape StandardCursor) *Cursor {
 	return &Cursor{c: c}
 }
 
+func CreateCursor(img image.Image, xhot, yhot int) *Cursor {
+	c := glfw.CreateCursor(img, xhot, yhot)
+	return &Cursor{c: c}
+}
+
+func (c *Cursor) Destroy() {
+	c.c.Destroy()
+}
+
 type Monitor struct {
 	m *glfw.Monitor
 }
@@ -75,6 +84,10 @@ func (m *Monitor) GetContentScale() (float32, float32) {
 	return m.m.GetContentScale()
 }
 
+func (m *Monitor) GetName() string {
+	return m.m.GetName()
+}
+
 func (m *Monitor) GetPos() (x, y int) {
 	return m.m.GetPos()
 }
@@ -109,6 +122,10 @@ func (w *Window) GetAttrib(attrib Hint) int {
 	return w.w.GetAttrib(glfw.Hint(attrib))
 }
 
+func (w *Window) GetContentScale() (float32, float32) {
+	return w.w.GetContentScale()
+}
+
 func (w *Window) GetCursorPos() (x, y float64) {
 	return w.w.GetCursorPos()
 }
@@ -145,6 +162,10 @@ func (w *Window) Hide() {
 	w.w.Hide()
 }
 
+func (w *Window) RequestAttention() {
+	w.w.RequestAttention()
+}
+
 func (w *Window) Iconify() {
 	w.w.Iconify()
 }
@@ -183,6 +204,16 @@ func (w *Window) SetCloseCallback(cbfun CloseCallback) (previous CloseCallback)
 	return ToCloseCallback(nil) // TODO
 }
 
+func (w *Window) SetContentScaleCallback(cbfun ContentScaleCallback) (previous ContentScaleCallback) {
+	w.w.SetContentScaleCallback(contentScaleCallbacks[cbfun])
+	return ToContentScaleCallback(nil) // TODO
+}
+
+func (w *Window) SetDropCallback(cbfun DropCallback) (previous DropCallback) {
+	w.w.SetDropCallback(dropCallbacks[cbfun])
+	return ToDropCallback(nil) // TODO
+}
+
 func (w *Window) SetFramebufferSizeCallback(cbfun FramebufferSizeCallback) (previous FramebufferSizeCallback) {
 	w.w.SetFramebufferSizeCallback(framebufferSizeCallbacks[cbfun])
 	return ToFramebufferSizeCallback(nil) // TODO
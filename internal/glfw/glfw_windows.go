@@ -76,6 +76,27 @@ func CreateStandardCursor(shape StandardCursor) *Cursor {
 	return &Cursor{c: c}
 }
 
+func CreateCursor(img image.Image, xhot, yhot int) *Cursor {
+	b := img.Bounds()
+	m := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(m, m.Bounds(), img, b.Min, draw.Src)
+	gimg := glfwImage{
+		width:  int32(b.Dx()),
+		height: int32(b.Dy()),
+		pixels: uintptr(unsafe.Pointer(&m.Pix[0])),
+	}
+	defer runtime.KeepAlive(m)
+
+	c := glfwDLL.call("glfwCreateCursor", uintptr(unsafe.Pointer(&gimg)), uintptr(xhot), uintptr(yhot))
+	panicError()
+	return &Cursor{c: c}
+}
+
+func (c *Cursor) Destroy() {
+	glfwDLL.call("glfwDestroyCursor", c.c)
+	panicError()
+}
+
 type Monitor struct {
 	m uintptr
 }
@@ -87,6 +108,15 @@ func (m *Monitor) GetContentScale() (float32, float32) {
 	return sx, sy
 }
 
+func (m *Monitor) GetName() string {
+	n := glfwDLL.call("glfwGetMonitorName", m.m)
+	panicError()
+	if n == 0 {
+		return ""
+	}
+	return bytePtrToString((*byte)(unsafe.Pointer(n)))
+}
+
 func (m *Monitor) GetPos() (int, int) {
 	var x, y int32
 	glfwDLL.call("glfwGetMonitorPos", m.m, uintptr(unsafe.Pointer(&x)), uintptr(unsafe.Pointer(&y)))
@@ -135,6 +165,13 @@ func (w *Window) SetAttrib(attrib Hint, value int) {
 	panicError()
 }
 
+func (w *Window) GetContentScale() (float32, float32) {
+	var sx, sy float32
+	glfwDLL.call("glfwGetWindowContentScale", w.w, uintptr(unsafe.Pointer(&sx)), uintptr(unsafe.Pointer(&sy)))
+	panicError()
+	return sx, sy
+}
+
 func (w *Window) GetCursorPos() (x, y float64) {
 	glfwDLL.call("glfwGetCursorPos", w.w, uintptr(unsafe.Pointer(&x)), uintptr(unsafe.Pointer(&y)))
 	panicError()
@@ -187,6 +224,11 @@ func (w *Window) Hide() {
 	panicError()
 }
 
+func (w *Window) RequestAttention() {
+	glfwDLL.call("glfwRequestWindowAttention", w.w)
+	panicError()
+}
+
 func (w *Window) Iconify() {
 	glfwDLL.call("glfwIconifyWindow", w.w)
 	panicError()
@@ -219,6 +261,12 @@ func (w *Window) SetCloseCallback(cbfun CloseCallback) (previous CloseCallback)
 	return ToCloseCallback(nil) // TODO
 }
 
+func (w *Window) SetContentScaleCallback(cbfun ContentScaleCallback) (previous ContentScaleCallback) {
+	glfwDLL.call("glfwSetWindowContentScaleCallback", w.w, uintptr(cbfun))
+	panicError()
+	return ToContentScaleCallback(nil) // TODO
+}
+
 func (w *Window) SetCursor(cursor *Cursor) {
 	var c uintptr
 	if cursor != nil {
@@ -227,6 +275,12 @@ func (w *Window) SetCursor(cursor *Cursor) {
 	glfwDLL.call("glfwSetCursor", w.w, c)
 }
 
+func (w *Window) SetDropCallback(cbfun DropCallback) (previous DropCallback) {
+	glfwDLL.call("glfwSetDropCallback", w.w, uintptr(cbfun))
+	panicError()
+	return ToDropCallback(nil) // TODO
+}
+
 func (w *Window) SetFramebufferSizeCallback(cbfun FramebufferSizeCallback) (previous FramebufferSizeCallback) {
 	glfwDLL.call("glfwSetFramebufferSizeCallback", w.w, uintptr(cbfun))
 	panicError()
@@ -279,7 +333,11 @@ func (w *Window) SetIcon(images []image.Image) {
 		gimgs[i].pixels = uintptr(unsafe.Pointer(&m.Pix[0]))
 	}
 
-	glfwDLL.call("glfwSetWindowIcon", w.w, uintptr(len(gimgs)), uintptr(unsafe.Pointer(&gimgs[0])))
+	var p uintptr
+	if len(gimgs) > 0 {
+		p = uintptr(unsafe.Pointer(&gimgs[0]))
+	}
+	glfwDLL.call("glfwSetWindowIcon", w.w, uintptr(len(gimgs)), p)
 	panicError()
 }
 
@@ -20,6 +20,8 @@ package glfw
 type (
 	CharModsCallback        uintptr
 	CloseCallback           uintptr
+	ContentScaleCallback    uintptr
+	DropCallback            uintptr
 	FramebufferSizeCallback uintptr
 	ScrollCallback          uintptr
 	SizeCallback            uintptr
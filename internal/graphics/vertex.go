@@ -21,6 +21,9 @@ import (
 const (
 	ShaderImageNum = 4
 
+	// ShaderDstImageNum is the maximum number of destination images (render targets) for one shader.
+	ShaderDstImageNum = 4
+
 	// PreservedUniformVariablesNum represents the number of preserved uniform variables.
 	// Any shaders in Ebiten must have these uniform variables.
 	PreservedUniformVariablesNum = 1 + // the destination texture size
@@ -41,8 +44,27 @@ const (
 )
 
 const (
-	IndicesNum     = (1 << 16) / 3 * 3 // Adjust num for triangles.
+	// IndicesNum is the maximum number of indices for one drawing-triangles call.
+	IndicesNum = (1 << 16) / 3 * 3 // Adjust num for triangles.
+
+	// VerticesNumPerBuffer is the maximum number of vertices in one vertex buffer.
+	// The vertices in one buffer must be addressable with uint16 indices.
+	// The number of indices in one buffer is not limited, and the buffers in a frame are not limited either.
+	VerticesNumPerBuffer = 1 << 16
+
 	VertexFloatNum = 8
+
+	// InstanceFloatNum is the number of float32 values for one instance of instanced drawing.
+	//
+	//   0: Destination X offset in pixels
+	//   1: Destination Y offset in pixels
+	//   2: Source X offset in pixels
+	//   3: Source Y offset in pixels
+	//   4: Color R scale
+	//   5: Color G scale
+	//   6: Color B scale
+	//   7: Color A scale
+	InstanceFloatNum = 8
 )
 
 var (
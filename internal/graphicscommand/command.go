@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/affine"
 	"github.com/hajimehoshi/ebiten/v2/internal/debug"
@@ -95,7 +97,6 @@ type commandQueue struct {
 	nindices int
 
 	tmpNumVertexFloats int
-	tmpNumIndices      int
 
 	drawTrianglesCommandPool drawTrianglesCommandPool
 
@@ -145,20 +146,22 @@ func (q *commandQueue) appendIndices(indices []uint16, offset uint16) {
 }
 
 // mustUseDifferentVertexBuffer reports whether a differnt vertex buffer must be used.
-func mustUseDifferentVertexBuffer(nextNumVertexFloats, nextNumIndices int) bool {
-	return nextNumVertexFloats > graphics.IndicesNum*graphics.VertexFloatNum || nextNumIndices > graphics.IndicesNum
+//
+// Only the number of vertices matters since the vertices must be addressable with uint16 indices.
+// The driver's buffers grow as needed, so the number of indices is not limited.
+func mustUseDifferentVertexBuffer(nextNumVertexFloats int) bool {
+	return nextNumVertexFloats > graphics.VerticesNumPerBuffer*graphics.VertexFloatNum
 }
 
 // EnqueueDrawTrianglesCommand enqueues a drawing-image command.
-func (q *commandQueue) EnqueueDrawTrianglesCommand(dst *Image, srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, color affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool) {
+func (q *commandQueue) EnqueueDrawTrianglesCommand(dst *Image, srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, color affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, fillRule graphicsdriver.FillRule) {
 	if len(indices) > graphics.IndicesNum {
 		panic(fmt.Sprintf("graphicscommand: len(indices) must be <= graphics.IndicesNum but not at EnqueueDrawTrianglesCommand: len(indices): %d, graphics.IndicesNum: %d", len(indices), graphics.IndicesNum))
 	}
 
 	split := false
-	if mustUseDifferentVertexBuffer(q.tmpNumVertexFloats+len(vertices), q.tmpNumIndices+len(indices)) {
+	if mustUseDifferentVertexBuffer(q.tmpNumVertexFloats + len(vertices)) {
 		q.tmpNumVertexFloats = 0
-		q.tmpNumIndices = 0
 		split = true
 	}
 
@@ -167,7 +170,6 @@ func (q *commandQueue) EnqueueDrawTrianglesCommand(dst *Image, srcs [graphics.Sh
 	q.appendVertices(vertices, srcs[0])
 	q.appendIndices(indices, uint16(q.tmpNumVertexFloats/graphics.VertexFloatNum))
 	q.tmpNumVertexFloats += len(vertices)
-	q.tmpNumIndices += len(indices)
 
 	if srcs[0] != nil {
 		w, h := srcs[0].InternalSize()
@@ -185,7 +187,7 @@ func (q *commandQueue) EnqueueDrawTrianglesCommand(dst *Image, srcs [graphics.Sh
 	if !split && 0 < len(q.commands) {
 		// TODO: Pass offsets and uniforms when merging considers the shader.
 		if last, ok := q.commands[len(q.commands)-1].(*drawTrianglesCommand); ok {
-			if last.CanMergeWithDrawTrianglesCommand(dst, srcs, vertices, color, mode, filter, address, dstRegion, srcRegion, shader, evenOdd) {
+			if last.CanMergeWithDrawTrianglesCommand(dst, srcs, vertices, color, mode, filter, address, dstRegion, srcRegion, shader, fillRule) {
 				last.setVertices(q.lastVertices(len(vertices) + last.numVertices()))
 				last.addNumIndices(len(indices))
 				return
@@ -195,10 +197,12 @@ func (q *commandQueue) EnqueueDrawTrianglesCommand(dst *Image, srcs [graphics.Sh
 
 	c := q.drawTrianglesCommandPool.get()
 	c.dst = dst
+	c.extraDsts = [graphics.ShaderDstImageNum - 1]*Image{}
 	c.srcs = srcs
 	c.offsets = offsets
 	c.vertices = q.lastVertices(len(vertices))
 	c.nindices = len(indices)
+	c.instances = nil
 	c.color = color
 	c.mode = mode
 	c.filter = filter
@@ -207,10 +211,129 @@ func (q *commandQueue) EnqueueDrawTrianglesCommand(dst *Image, srcs [graphics.Sh
 	c.srcRegion = srcRegion
 	c.shader = shader
 	c.uniforms = uniforms
-	c.evenOdd = evenOdd
+	c.fillRule = fillRule
 	q.commands = append(q.commands, c)
 }
 
+// EnqueueDrawTrianglesInstancedCommand enqueues a command to draw the triangles repeatedly for each instance.
+//
+// If the graphics driver doesn't support instanced drawing, the instances are expanded into regular triangles.
+func (q *commandQueue) EnqueueDrawTrianglesInstancedCommand(dst *Image, src *Image, vertices []float32, indices []uint16, instances []float32, color affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region) {
+	if len(indices) > graphics.IndicesNum {
+		panic(fmt.Sprintf("graphicscommand: len(indices) must be <= graphics.IndicesNum but not at EnqueueDrawTrianglesInstancedCommand: len(indices): %d, graphics.IndicesNum: %d", len(indices), graphics.IndicesNum))
+	}
+	if len(instances) == 0 {
+		return
+	}
+
+	if !Capabilities().Instancing {
+		q.enqueueExpandedInstances(dst, src, vertices, indices, instances, color, mode, filter, address, dstRegion, srcRegion)
+		return
+	}
+
+	if mustUseDifferentVertexBuffer(q.tmpNumVertexFloats + len(vertices)) {
+		q.tmpNumVertexFloats = 0
+	}
+
+	q.appendVertices(vertices, src)
+	q.appendIndices(indices, uint16(q.tmpNumVertexFloats/graphics.VertexFloatNum))
+	q.tmpNumVertexFloats += len(vertices)
+
+	// Convert the source offsets from pixels to texels.
+	// Copy the instances as the given slice might be reused by the caller.
+	w, h := src.InternalSize()
+	is := make([]float32, len(instances))
+	copy(is, instances)
+	for i := 0; i < len(is); i += graphics.InstanceFloatNum {
+		is[i+2] /= float32(w)
+		is[i+3] /= float32(h)
+	}
+	srcRegion.X /= float32(w)
+	srcRegion.Y /= float32(h)
+	srcRegion.Width /= float32(w)
+	srcRegion.Height /= float32(h)
+
+	// A command for instanced drawing is never merged with other commands.
+	c := q.drawTrianglesCommandPool.get()
+	c.dst = dst
+	c.extraDsts = [graphics.ShaderDstImageNum - 1]*Image{}
+	c.srcs = [graphics.ShaderImageNum]*Image{src}
+	c.offsets = [graphics.ShaderImageNum - 1][2]float32{}
+	c.vertices = q.lastVertices(len(vertices))
+	c.nindices = len(indices)
+	c.instances = is
+	c.color = color
+	c.mode = mode
+	c.filter = filter
+	c.address = address
+	c.dstRegion = dstRegion
+	c.srcRegion = srcRegion
+	c.shader = nil
+	c.uniforms = nil
+	c.fillRule = graphicsdriver.FillAll
+	q.commands = append(q.commands, c)
+}
+
+// EnqueueDrawTrianglesMRTCommand enqueues a command to draw triangles with the shader onto multiple destinations.
+//
+// The destinations are packed from the front in dsts.
+func (q *commandQueue) EnqueueDrawTrianglesMRTCommand(dsts [graphics.ShaderDstImageNum]*Image, srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, mode graphicsdriver.Blend, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform) {
+	if shader == nil {
+		panic("graphicscommand: shader must not be nil at EnqueueDrawTrianglesMRTCommand")
+	}
+	q.EnqueueDrawTrianglesCommand(dsts[0], srcs, offsets, vertices, indices, affine.ColorMIdentity{}, mode, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, srcRegion, shader, uniforms, graphicsdriver.FillAll)
+
+	// A command with a shader is never merged, then the last command is the one just enqueued.
+	c := q.commands[len(q.commands)-1].(*drawTrianglesCommand)
+	copy(c.extraDsts[:], dsts[1:])
+}
+
+// enqueueExpandedInstances enqueues regular draw-triangles commands that are equivalent to instanced drawing.
+func (q *commandQueue) enqueueExpandedInstances(dst *Image, src *Image, vertices []float32, indices []uint16, instances []float32, color affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region) {
+	nv := len(vertices) / graphics.VertexFloatNum
+	if nv == 0 || len(indices) == 0 {
+		return
+	}
+	if nv > graphics.VerticesNumPerBuffer {
+		panic(fmt.Sprintf("graphicscommand: the number of vertices must be <= graphics.VerticesNumPerBuffer but not at enqueueExpandedInstances: %d", nv))
+	}
+
+	// The number of instances in one command is limited so that the vertices are addressable with uint16 indices.
+	n := graphics.VerticesNumPerBuffer / nv
+	if m := graphics.IndicesNum / len(indices); n > m {
+		n = m
+	}
+
+	for len(instances) > 0 {
+		num := len(instances) / graphics.InstanceFloatNum
+		if num > n {
+			num = n
+		}
+		vs := make([]float32, 0, num*len(vertices))
+		is := make([]uint16, 0, num*len(indices))
+		for i := 0; i < num; i++ {
+			inst := instances[i*graphics.InstanceFloatNum : (i+1)*graphics.InstanceFloatNum]
+			base := uint16(len(vs) / graphics.VertexFloatNum)
+			for j := 0; j < len(vertices); j += graphics.VertexFloatNum {
+				vs = append(vs,
+					vertices[j]+inst[0],
+					vertices[j+1]+inst[1],
+					vertices[j+2]+inst[2],
+					vertices[j+3]+inst[3],
+					vertices[j+4]*inst[4],
+					vertices[j+5]*inst[5],
+					vertices[j+6]*inst[6],
+					vertices[j+7]*inst[7])
+			}
+			for _, idx := range indices {
+				is = append(is, idx+base)
+			}
+		}
+		q.EnqueueDrawTrianglesCommand(dst, [graphics.ShaderImageNum]*Image{src}, [graphics.ShaderImageNum - 1][2]float32{}, vs, is, color, mode, filter, address, dstRegion, srcRegion, nil, nil, graphicsdriver.FillAll)
+		instances = instances[num*graphics.InstanceFloatNum:]
+	}
+}
+
 func (q *commandQueue) lastVertices(n int) []float32 {
 	return q.vertices[q.nvertices-n : q.nvertices]
 }
@@ -300,10 +423,7 @@ func (q *commandQueue) flush() error {
 		nc := 0
 		for _, c := range cs {
 			if dtc, ok := c.(*drawTrianglesCommand); ok {
-				if dtc.numIndices() > graphics.IndicesNum {
-					panic(fmt.Sprintf("graphicscommand: dtc.NumIndices() must be <= graphics.IndicesNum but not at Flush: dtc.NumIndices(): %d, graphics.IndicesNum: %d", dtc.numIndices(), graphics.IndicesNum))
-				}
-				if nc > 0 && mustUseDifferentVertexBuffer(nv+dtc.numVertices(), ne+dtc.numIndices()) {
+				if nc > 0 && mustUseDifferentVertexBuffer(nv+dtc.numVertices()) {
 					break
 				}
 				nv += dtc.numVertices()
@@ -327,6 +447,7 @@ func (q *commandQueue) flush() error {
 			// introduced than drawTrianglesCommand.
 			if dtc, ok := c.(*drawTrianglesCommand); ok {
 				indexOffset += dtc.numIndices()
+				countDrawCall(dtc)
 			}
 		}
 		cs = cs[nc:]
@@ -346,7 +467,6 @@ func (q *commandQueue) flush() error {
 	q.nvertices = 0
 	q.nindices = 0
 	q.tmpNumVertexFloats = 0
-	q.tmpNumIndices = 0
 	return nil
 }
 
@@ -355,64 +475,109 @@ func FlushCommands() error {
 	return theCommandQueue.Flush()
 }
 
+// gpuTimer is an optional interface for a graphics driver to measure the time spent by GPU.
+type gpuTimer interface {
+	EndGPUFrame() time.Duration
+}
+
+var gpuFrameDuration int64
+
+// EndGPUFrame notifies the graphics driver of the end of a frame, and rolls the frame statistics over.
+// EndGPUFrame should be called after the last FlushCommands in a frame.
+func EndGPUFrame() {
+	runOnRenderingThread(func() {
+		endFrameStats()
+		if t, ok := theGraphicsDriver.(gpuTimer); ok {
+			atomic.StoreInt64(&gpuFrameDuration, int64(t.EndGPUFrame()))
+		}
+	})
+}
+
+// GPUFrameDuration returns the time spent by GPU for the last measured frame.
+// GPUFrameDuration returns 0 if the graphics driver cannot measure the time.
+//
+// GPUFrameDuration is concurrent-safe.
+func GPUFrameDuration() time.Duration {
+	return time.Duration(atomic.LoadInt64(&gpuFrameDuration))
+}
+
 // drawTrianglesCommand represents a drawing command to draw an image on another image.
 type drawTrianglesCommand struct {
-	dst       *Image
+	dst *Image
+
+	// extraDsts is the second and the following destinations for multiple render targets.
+	// The destinations are packed from the front, and the rest are nil.
+	extraDsts [graphics.ShaderDstImageNum - 1]*Image
+
 	srcs      [graphics.ShaderImageNum]*Image
 	offsets   [graphics.ShaderImageNum - 1][2]float32
 	vertices  []float32
 	nindices  int
 	color     affine.ColorM
-	mode      graphicsdriver.CompositeMode
+	mode      graphicsdriver.Blend
 	filter    graphicsdriver.Filter
 	address   graphicsdriver.Address
 	dstRegion graphicsdriver.Region
 	srcRegion graphicsdriver.Region
 	shader    *Shader
 	uniforms  []graphicsdriver.Uniform
-	evenOdd   bool
+	fillRule  graphicsdriver.FillRule
+
+	// instances is the per-instance attributes for instanced drawing.
+	// instances is nil if the command is not for instanced drawing.
+	instances []float32
 }
 
-func (c *drawTrianglesCommand) String() string {
-	mode := ""
-	switch c.mode {
-	case graphicsdriver.CompositeModeSourceOver:
-		mode = "source-over"
-	case graphicsdriver.CompositeModeClear:
-		mode = "clear"
-	case graphicsdriver.CompositeModeCopy:
-		mode = "copy"
-	case graphicsdriver.CompositeModeDestination:
-		mode = "destination"
-	case graphicsdriver.CompositeModeDestinationOver:
-		mode = "destination-over"
-	case graphicsdriver.CompositeModeSourceIn:
-		mode = "source-in"
-	case graphicsdriver.CompositeModeDestinationIn:
-		mode = "destination-in"
-	case graphicsdriver.CompositeModeSourceOut:
-		mode = "source-out"
-	case graphicsdriver.CompositeModeDestinationOut:
-		mode = "destination-out"
-	case graphicsdriver.CompositeModeSourceAtop:
-		mode = "source-atop"
-	case graphicsdriver.CompositeModeDestinationAtop:
-		mode = "destination-atop"
-	case graphicsdriver.CompositeModeXor:
-		mode = "xor"
-	case graphicsdriver.CompositeModeLighter:
-		mode = "lighter"
-	case graphicsdriver.CompositeModeMultiply:
-		mode = "multiply"
+func blendString(blend graphicsdriver.Blend) string {
+	switch blend {
+	case graphicsdriver.BlendSourceOver:
+		return "source-over"
+	case graphicsdriver.BlendClear:
+		return "clear"
+	case graphicsdriver.BlendCopy:
+		return "copy"
+	case graphicsdriver.BlendDestination:
+		return "destination"
+	case graphicsdriver.BlendDestinationOver:
+		return "destination-over"
+	case graphicsdriver.BlendSourceIn:
+		return "source-in"
+	case graphicsdriver.BlendDestinationIn:
+		return "destination-in"
+	case graphicsdriver.BlendSourceOut:
+		return "source-out"
+	case graphicsdriver.BlendDestinationOut:
+		return "destination-out"
+	case graphicsdriver.BlendSourceAtop:
+		return "source-atop"
+	case graphicsdriver.BlendDestinationAtop:
+		return "destination-atop"
+	case graphicsdriver.BlendXor:
+		return "xor"
+	case graphicsdriver.BlendLighter:
+		return "lighter"
+	case graphicsdriver.BlendMultiply:
+		return "multiply"
 	default:
-		panic(fmt.Sprintf("graphicscommand: invalid composite mode: %d", c.mode))
+		return fmt.Sprintf("custom (factors: %d, %d, %d, %d, operations: %d, %d)", blend.BlendFactorSourceRGB, blend.BlendFactorSourceAlpha, blend.BlendFactorDestinationRGB, blend.BlendFactorDestinationAlpha, blend.BlendOperationRGB, blend.BlendOperationAlpha)
 	}
+}
+
+func (c *drawTrianglesCommand) String() string {
+	mode := blendString(c.mode)
 
 	dst := fmt.Sprintf("%d", c.dst.id)
 	if c.dst.screen {
 		dst += " (screen)"
 	}
 
+	for _, d := range c.extraDsts {
+		if d == nil {
+			continue
+		}
+		dst += fmt.Sprintf(", %d", d.id)
+	}
+
 	if c.shader != nil {
 		return fmt.Sprintf("draw-triangles: dst: %s, shader, num of indices: %d, mode %s", dst, c.nindices, mode)
 	}
@@ -441,6 +606,18 @@ func (c *drawTrianglesCommand) String() string {
 		panic(fmt.Sprintf("graphicscommand: invalid address: %d", c.address))
 	}
 
+	fillRule := ""
+	switch c.fillRule {
+	case graphicsdriver.FillAll:
+		fillRule = "fill_all"
+	case graphicsdriver.EvenOdd:
+		fillRule = "even_odd"
+	case graphicsdriver.NonZero:
+		fillRule = "non_zero"
+	default:
+		panic(fmt.Sprintf("graphicscommand: invalid fill rule: %d", c.fillRule))
+	}
+
 	var srcstrs [graphics.ShaderImageNum]string
 	for i, src := range c.srcs {
 		if src == nil {
@@ -455,7 +632,10 @@ func (c *drawTrianglesCommand) String() string {
 
 	r := fmt.Sprintf("(x:%d, y:%d, width:%d, height:%d)",
 		int(c.dstRegion.X), int(c.dstRegion.Y), int(c.dstRegion.Width), int(c.dstRegion.Height))
-	return fmt.Sprintf("draw-triangles: dst: %s <- src: [%s], dst region: %s, num of indices: %d, colorm: %v, mode: %s, filter: %s, address: %s, even-odd: %t", dst, strings.Join(srcstrs[:], ", "), r, c.nindices, c.color, mode, filter, address, c.evenOdd)
+	if c.instances != nil {
+		return fmt.Sprintf("draw-triangles-instanced: dst: %s <- src: [%s], dst region: %s, num of indices: %d, num of instances: %d, colorm: %v, mode: %s, filter: %s, address: %s", dst, strings.Join(srcstrs[:], ", "), r, c.nindices, len(c.instances)/graphics.InstanceFloatNum, c.color, mode, filter, address)
+	}
+	return fmt.Sprintf("draw-triangles: dst: %s <- src: [%s], dst region: %s, num of indices: %d, colorm: %v, mode: %s, filter: %s, address: %s, fill rule: %s", dst, strings.Join(srcstrs[:], ", "), r, c.nindices, c.color, mode, filter, address, fillRule)
 }
 
 // Exec executes the drawTrianglesCommand.
@@ -465,6 +645,10 @@ func (c *drawTrianglesCommand) Exec(indexOffset int) error {
 		return nil
 	}
 
+	if c.instances != nil {
+		return theGraphicsDriver.DrawTrianglesInstanced(c.dst.image.ID(), c.srcs[0].image.ID(), c.instances, c.nindices, indexOffset, c.mode, c.color, c.filter, c.address, c.dstRegion, c.srcRegion)
+	}
+
 	var shaderID graphicsdriver.ShaderID = graphicsdriver.InvalidShaderID
 	var imgs [graphics.ShaderImageNum]graphicsdriver.ImageID
 	if c.shader != nil {
@@ -480,7 +664,19 @@ func (c *drawTrianglesCommand) Exec(indexOffset int) error {
 		imgs[0] = c.srcs[0].image.ID()
 	}
 
-	return theGraphicsDriver.DrawTriangles(c.dst.image.ID(), imgs, c.offsets, shaderID, c.nindices, indexOffset, c.mode, c.color, c.filter, c.address, c.dstRegion, c.srcRegion, c.uniforms, c.evenOdd)
+	if c.extraDsts[0] != nil {
+		var dsts [graphics.ShaderDstImageNum]graphicsdriver.ImageID
+		dsts[0] = c.dst.image.ID()
+		for i, d := range c.extraDsts {
+			if d == nil {
+				continue
+			}
+			dsts[i+1] = d.image.ID()
+		}
+		return theGraphicsDriver.DrawTrianglesMRT(dsts, imgs, c.offsets, shaderID, c.nindices, indexOffset, c.mode, c.dstRegion, c.srcRegion, c.uniforms)
+	}
+
+	return theGraphicsDriver.DrawTriangles(c.dst.image.ID(), imgs, c.offsets, shaderID, c.nindices, indexOffset, c.mode, c.color, c.filter, c.address, c.dstRegion, c.srcRegion, c.uniforms, c.fillRule)
 }
 
 func (c *drawTrianglesCommand) numVertices() int {
@@ -501,13 +697,17 @@ func (c *drawTrianglesCommand) addNumIndices(n int) {
 
 // CanMergeWithDrawTrianglesCommand returns a boolean value indicating whether the other drawTrianglesCommand can be merged
 // with the drawTrianglesCommand c.
-func (c *drawTrianglesCommand) CanMergeWithDrawTrianglesCommand(dst *Image, srcs [graphics.ShaderImageNum]*Image, vertices []float32, color affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, evenOdd bool) bool {
+func (c *drawTrianglesCommand) CanMergeWithDrawTrianglesCommand(dst *Image, srcs [graphics.ShaderImageNum]*Image, vertices []float32, color affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, fillRule graphicsdriver.FillRule) bool {
 	// If a shader is used, commands are not merged.
 	//
 	// TODO: Merge shader commands considering uniform variables.
 	if c.shader != nil || shader != nil {
 		return false
 	}
+	// Commands for instanced drawing are not merged.
+	if c.instances != nil {
+		return false
+	}
 	if c.dst != dst {
 		return false
 	}
@@ -532,12 +732,12 @@ func (c *drawTrianglesCommand) CanMergeWithDrawTrianglesCommand(dst *Image, srcs
 	if c.srcRegion != srcRegion {
 		return false
 	}
-	if c.evenOdd || evenOdd {
-		if c.evenOdd && evenOdd {
-			return !mightOverlapDstRegions(c.vertices, vertices)
-		}
+	if c.fillRule != fillRule {
 		return false
 	}
+	if c.fillRule != graphicsdriver.FillAll {
+		return !mightOverlapDstRegions(c.vertices, vertices)
+	}
 	return true
 }
 
@@ -613,6 +813,121 @@ func (c *pixelsCommand) String() string {
 	return fmt.Sprintf("pixels: image: %d", c.img.id)
 }
 
+// readPixelsAsyncCommand represents a command to start reading pixels without waiting for GPU.
+type readPixelsAsyncCommand struct {
+	img    *Image
+	x      int
+	y      int
+	width  int
+	height int
+	f      func([]byte)
+}
+
+// Exec executes a readPixelsAsyncCommand.
+func (c *readPixelsAsyncCommand) Exec(indexOffset int) error {
+	p, err := c.img.image.ReadPixelsAsync(c.x, c.y, c.width, c.height)
+	if err != nil {
+		return err
+	}
+	thePendingPixels = append(thePendingPixels, pendingPixels{
+		pixels: p,
+		f:      c.f,
+	})
+	return nil
+}
+
+func (c *readPixelsAsyncCommand) String() string {
+	return fmt.Sprintf("read-pixels-async: image: %d, x: %d, y: %d, width: %d, height: %d", c.img.id, c.x, c.y, c.width, c.height)
+}
+
+// pendingPixels represents pixels being read asynchronously and the callback for them.
+type pendingPixels struct {
+	pixels graphicsdriver.PendingPixels
+	f      func([]byte)
+}
+
+// thePendingPixels is the pixels being read asynchronously.
+// thePendingPixels must be accessed on the rendering thread.
+var thePendingPixels []pendingPixels
+
+// theAsyncCallbacks is the callbacks for the asynchronous operations that are already done.
+var theAsyncCallbacks []func()
+
+// ResolvePendingPixels checks whether the pixels being read asynchronously are ready.
+// ResolvePendingPixels should be called after the last FlushCommands in a frame.
+func ResolvePendingPixels() {
+	runOnRenderingThread(func() {
+		var n int
+		for _, p := range thePendingPixels {
+			pix, ok := p.pixels.TryPixels()
+			if !ok {
+				thePendingPixels[n] = p
+				n++
+				continue
+			}
+			f := p.f
+			theAsyncCallbacks = append(theAsyncCallbacks, func() {
+				f(pix)
+			})
+		}
+		for i := n; i < len(thePendingPixels); i++ {
+			thePendingPixels[i] = pendingPixels{}
+		}
+		thePendingPixels = thePendingPixels[:n]
+	})
+}
+
+// pendingShader represents a shader being compiled asynchronously and the callback for it.
+type pendingShader struct {
+	result *Shader
+	shader graphicsdriver.PendingShader
+	f      func()
+}
+
+// thePendingShaders is the shaders being compiled asynchronously.
+// thePendingShaders must be accessed on the rendering thread.
+var thePendingShaders []pendingShader
+
+// ResolvePendingShaders checks whether the shaders being compiled asynchronously are ready.
+// ResolvePendingShaders should be called after the last FlushCommands in a frame.
+func ResolvePendingShaders() error {
+	var err error
+	runOnRenderingThread(func() {
+		var n int
+		for _, p := range thePendingShaders {
+			s, ok, e := p.shader.TryShader()
+			if e != nil {
+				err = e
+				return
+			}
+			if !ok {
+				thePendingShaders[n] = p
+				n++
+				continue
+			}
+			p.result.shader = s
+			theAsyncCallbacks = append(theAsyncCallbacks, p.f)
+		}
+		for i := n; i < len(thePendingShaders); i++ {
+			thePendingShaders[i] = pendingShader{}
+		}
+		thePendingShaders = thePendingShaders[:n]
+	})
+	return err
+}
+
+// RunAsyncCallbacks calls the callbacks for the pixels and the shaders resolved by ResolvePendingPixels and
+// ResolvePendingShaders.
+//
+// RunAsyncCallbacks must not be called while any lock for images is held, as the callbacks might use images.
+func RunAsyncCallbacks() {
+	cs := theAsyncCallbacks
+	theAsyncCallbacks = nil
+	for _, c := range cs {
+		c()
+	}
+}
+
 // disposeImageCommand represents a command to dispose an image.
 type disposeImageCommand struct {
 	target *Image
@@ -645,18 +960,20 @@ func (c *disposeShaderCommand) Exec(indexOffset int) error {
 
 // newImageCommand represents a command to create an empty image with given width and height.
 type newImageCommand struct {
-	result *Image
-	width  int
-	height int
+	result      *Image
+	width       int
+	height      int
+	sampleCount int
+	format      graphicsdriver.PixelFormat
 }
 
 func (c *newImageCommand) String() string {
-	return fmt.Sprintf("new-image: result: %d, width: %d, height: %d", c.result.id, c.width, c.height)
+	return fmt.Sprintf("new-image: result: %d, width: %d, height: %d, sample count: %d, format: %d", c.result.id, c.width, c.height, c.sampleCount, c.format)
 }
 
 // Exec executes a newImageCommand.
 func (c *newImageCommand) Exec(indexOffset int) error {
-	i, err := theGraphicsDriver.NewImage(c.width, c.height)
+	i, err := theGraphicsDriver.NewImage(c.width, c.height, c.sampleCount, c.format)
 	if err != nil {
 		return err
 	}
@@ -686,27 +1003,136 @@ func (c *newScreenFramebufferImageCommand) Exec(indexOffset int) error {
 type newShaderCommand struct {
 	result *Shader
 	ir     *shaderir.Program
+	async  bool
+	f      func()
 }
 
 func (c *newShaderCommand) String() string {
-	return fmt.Sprintf("new-shader")
+	return fmt.Sprintf("new-shader: async: %t", c.async)
 }
 
 // Exec executes a newShaderCommand.
 func (c *newShaderCommand) Exec(indexOffset int) error {
+	if c.async {
+		s, err := theGraphicsDriver.NewShaderAsync(c.ir)
+		if err != nil {
+			return err
+		}
+		thePendingShaders = append(thePendingShaders, pendingShader{
+			result: c.result,
+			shader: s,
+			f:      c.f,
+		})
+		return nil
+	}
+
 	var err error
 	c.result.shader, err = theGraphicsDriver.NewShader(c.ir)
 	return err
 }
 
+// shaderPrecompiler is an optional interface for a graphics driver to create pipeline states of a shader in advance.
+type shaderPrecompiler interface {
+	PrecompileShader(shader graphicsdriver.ShaderID, format graphicsdriver.PixelFormat, blends []graphicsdriver.Blend) error
+}
+
+// precompileShaderCommand represents a command to create pipeline states of a shader in advance.
+type precompileShaderCommand struct {
+	target *Shader
+	format graphicsdriver.PixelFormat
+	blends []graphicsdriver.Blend
+}
+
+func (c *precompileShaderCommand) String() string {
+	return fmt.Sprintf("precompile-shader: format: %d, len(blends): %d", c.format, len(c.blends))
+}
+
+// Exec executes a precompileShaderCommand.
+func (c *precompileShaderCommand) Exec(indexOffset int) error {
+	p, ok := theGraphicsDriver.(shaderPrecompiler)
+	if !ok {
+		return nil
+	}
+	return p.PrecompileShader(c.target.shader.ID(), c.format, c.blends)
+}
+
+// debugGrouper is an optional interface for a graphics driver to group commands for debugging tools.
+type debugGrouper interface {
+	PushDebugGroup(name string)
+	PopDebugGroup()
+}
+
+// pushDebugGroupCommand represents a command to start a group of commands for debugging tools.
+type pushDebugGroupCommand struct {
+	name string
+}
+
+func (c *pushDebugGroupCommand) String() string {
+	return fmt.Sprintf("push-debug-group: name: %q", c.name)
+}
+
+// Exec executes a pushDebugGroupCommand.
+func (c *pushDebugGroupCommand) Exec(indexOffset int) error {
+	if d, ok := theGraphicsDriver.(debugGrouper); ok {
+		d.PushDebugGroup(c.name)
+	}
+	return nil
+}
+
+// popDebugGroupCommand represents a command to end a group of commands for debugging tools.
+type popDebugGroupCommand struct {
+}
+
+func (c *popDebugGroupCommand) String() string {
+	return "pop-debug-group"
+}
+
+// Exec executes a popDebugGroupCommand.
+func (c *popDebugGroupCommand) Exec(indexOffset int) error {
+	if d, ok := theGraphicsDriver.(debugGrouper); ok {
+		d.PopDebugGroup()
+	}
+	return nil
+}
+
+// PushDebugGroup starts a group of the following commands with the given name.
+// The group is shown in graphics debugging tools like RenderDoc if the graphics driver supports it.
+func PushDebugGroup(name string) {
+	theCommandQueue.Enqueue(&pushDebugGroupCommand{
+		name: name,
+	})
+}
+
+// PopDebugGroup ends the group started by the last PushDebugGroup.
+func PopDebugGroup() {
+	theCommandQueue.Enqueue(&popDebugGroupCommand{})
+}
+
 // InitializeGraphicsDriverState initialize the current graphics driver state.
 func InitializeGraphicsDriverState() (err error) {
 	runOnRenderingThread(func() {
 		err = theGraphicsDriver.Initialize()
+		if err != nil {
+			return
+		}
+		theCapabilities.Store(theGraphicsDriver.Capabilities())
 	})
 	return
 }
 
+// theCapabilities is the capabilities of the graphics device, which is stored at the initialization.
+var theCapabilities atomic.Value
+
+// Capabilities returns the capabilities of the graphics device.
+// Capabilities returns the zero value before the graphics driver is initialized.
+func Capabilities() graphicsdriver.Capabilities {
+	c, ok := theCapabilities.Load().(graphicsdriver.Capabilities)
+	if !ok {
+		return graphicsdriver.Capabilities{}
+	}
+	return c
+}
+
 // ResetGraphicsDriverState resets the current graphics driver state.
 // If the graphics driver doesn't have an API to reset, ResetGraphicsDriverState does nothing.
 func ResetGraphicsDriverState() (err error) {
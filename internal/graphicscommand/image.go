@@ -58,15 +58,26 @@ func genNextID() int {
 //
 // Note that the image is not initialized yet.
 func NewImage(width, height int) *Image {
+	return NewImageWithAttributes(width, height, 1, graphicsdriver.PixelFormatRGBA8)
+}
+
+// NewImageWithAttributes returns a new image with the given attributes.
+// sampleCount is the number of samples per pixel for multisample antialiasing.
+// format is the pixel format of the image's texture.
+//
+// Note that the image is not initialized yet.
+func NewImageWithAttributes(width, height int, sampleCount int, format graphicsdriver.PixelFormat) *Image {
 	i := &Image{
 		width:  width,
 		height: height,
 		id:     genNextID(),
 	}
 	c := &newImageCommand{
-		result: i,
-		width:  width,
-		height: height,
+		result:      i,
+		width:       width,
+		height:      height,
+		sampleCount: sampleCount,
+		format:      format,
 	}
 	theCommandQueue.Enqueue(c)
 	return i
@@ -141,7 +152,7 @@ func (i *Image) InternalSize() (int, int) {
 //
 // If the source image is not specified, i.e., src is nil and there is no image in the uniform variables, the
 // elements for the source image are not used.
-func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, clr affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool) {
+func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, clr affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, fillRule graphicsdriver.FillRule) {
 	if shader == nil {
 		// Fast path for rendering without a shader (#1355).
 		img := srcs[0]
@@ -162,7 +173,54 @@ func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, offsets [gra
 	}
 	i.resolveBufferedReplacePixels()
 
-	theCommandQueue.EnqueueDrawTrianglesCommand(i, srcs, offsets, vertices, indices, clr, mode, filter, address, dstRegion, srcRegion, shader, uniforms, evenOdd)
+	currentFrameStats.DrawRequests++
+	theCommandQueue.EnqueueDrawTrianglesCommand(i, srcs, offsets, vertices, indices, clr, mode, filter, address, dstRegion, srcRegion, shader, uniforms, fillRule)
+}
+
+// DrawTrianglesInstanced draws triangles with the given image repeatedly for each instance.
+//
+// Each instance has graphics.InstanceFloatNum values: the destination offset, the source offset in pixels, and the
+// color scale. The offsets are added to the positions of the vertices, and the color scale is multiplied with the
+// colors of the vertices.
+//
+// If the graphics driver doesn't support instanced drawing, the triangles are expanded on CPU.
+func (i *Image) DrawTrianglesInstanced(src *Image, vertices []float32, indices []uint16, instances []float32, clr affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region) {
+	if src.screen {
+		panic("graphicscommand: the screen image cannot be the rendering source")
+	}
+	src.resolveBufferedReplacePixels()
+	i.resolveBufferedReplacePixels()
+
+	currentFrameStats.DrawRequests++
+	theCommandQueue.EnqueueDrawTrianglesInstancedCommand(i, src, vertices, indices, instances, clr, mode, filter, address, dstRegion, srcRegion)
+}
+
+// DrawTrianglesMRT draws triangles with the given shader onto the multiple destinations at the same time.
+//
+// The destinations are packed from the front in dsts, and the rest are nil.
+// The i-th destination is rendered with the i-th output color of the shader.
+func DrawTrianglesMRT(dsts [graphics.ShaderDstImageNum]*Image, srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, mode graphicsdriver.Blend, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform) {
+	for _, src := range srcs {
+		if src == nil {
+			continue
+		}
+		if src.screen {
+			panic("graphicscommand: the screen image cannot be the rendering source")
+		}
+		src.resolveBufferedReplacePixels()
+	}
+	for _, dst := range dsts {
+		if dst == nil {
+			continue
+		}
+		if dst.screen {
+			panic("graphicscommand: the screen image cannot be a destination of multiple render targets")
+		}
+		dst.resolveBufferedReplacePixels()
+	}
+
+	currentFrameStats.DrawRequests++
+	theCommandQueue.EnqueueDrawTrianglesMRTCommand(dsts, srcs, offsets, vertices, indices, mode, dstRegion, srcRegion, shader, uniforms)
 }
 
 // Pixels returns the image's pixels.
@@ -179,6 +237,20 @@ func (i *Image) Pixels() ([]byte, error) {
 	return c.result, nil
 }
 
+// ReadPixelsAsync starts reading the pixels in the given region without waiting for GPU.
+// f is called by RunAsyncCallbacks after the pixels are read.
+func (i *Image) ReadPixelsAsync(x, y, width, height int, f func([]byte)) {
+	i.resolveBufferedReplacePixels()
+	theCommandQueue.Enqueue(&readPixelsAsyncCommand{
+		img:    i,
+		x:      x,
+		y:      y,
+		width:  width,
+		height: height,
+		f:      f,
+	})
+}
+
 func (i *Image) ReplacePixels(pixels []byte, x, y, width, height int) {
 	i.bufferedRP = append(i.bufferedRP, &graphicsdriver.ReplacePixelsArgs{
 		Pixels: pixels,
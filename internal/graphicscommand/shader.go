@@ -33,6 +33,32 @@ func NewShader(ir *shaderir.Program) *Shader {
 	return s
 }
 
+// NewShaderAsync creates a shader without waiting for the compilation.
+// f is called by RunAsyncCallbacks after the shader is compiled.
+//
+// The shader must not be used until f is called.
+func NewShaderAsync(ir *shaderir.Program, f func()) *Shader {
+	s := &Shader{}
+	c := &newShaderCommand{
+		result: s,
+		ir:     ir,
+		async:  true,
+		f:      f,
+	}
+	theCommandQueue.Enqueue(c)
+	return s
+}
+
+// Precompile creates the pipeline states of the shader for the given destination format and blends in advance.
+func (s *Shader) Precompile(format graphicsdriver.PixelFormat, blends []graphicsdriver.Blend) {
+	c := &precompileShaderCommand{
+		target: s,
+		format: format,
+		blends: blends,
+	}
+	theCommandQueue.Enqueue(c)
+}
+
 func (s *Shader) Dispose() {
 	c := &disposeShaderCommand{
 		target: s,
@@ -0,0 +1,106 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicscommand
+
+import (
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+)
+
+// FrameStats represents statistics of the graphics commands executed in a frame.
+type FrameStats struct {
+	// DrawRequests is the number of the requests to draw triangles before the commands are merged.
+	DrawRequests int
+
+	// DrawCalls is the number of draw calls issued to the graphics driver.
+	DrawCalls int
+
+	// PipelineSwitches is the number of the times when the shader or the blend state changed between two
+	// consecutive draw calls.
+	PipelineSwitches int
+
+	// Vertices is the number of vertices sent to the graphics driver.
+	Vertices int
+}
+
+// pipeline represents the states to switch a pipeline of the graphics driver.
+type pipeline struct {
+	shader    *Shader
+	blend     graphicsdriver.Blend
+	filter    graphicsdriver.Filter
+	address   graphicsdriver.Address
+	instanced bool
+	mrt       bool
+}
+
+var (
+	// currentFrameStats is updated only while the command queue is used.
+	// The rendering thread uses the command queue only synchronously with the game's goroutine.
+	currentFrameStats FrameStats
+
+	// lastPipeline is the pipeline used at the last draw call.
+	lastPipeline    pipeline
+	hasLastPipeline bool
+
+	lastFrameStats  FrameStats
+	lastFrameStatsM sync.Mutex
+)
+
+// endFrameStats rolls the statistics of the current frame over.
+// endFrameStats must be called on the rendering thread.
+func endFrameStats() {
+	lastFrameStatsM.Lock()
+	defer lastFrameStatsM.Unlock()
+	lastFrameStats = currentFrameStats
+	currentFrameStats = FrameStats{}
+	hasLastPipeline = false
+}
+
+// countDrawCall updates the statistics of the current frame for the executed command c.
+func countDrawCall(c *drawTrianglesCommand) {
+	if c.nindices == 0 {
+		return
+	}
+	currentFrameStats.DrawCalls++
+	currentFrameStats.Vertices += c.numVertices() / graphics.VertexFloatNum
+
+	p := pipeline{
+		shader:    c.shader,
+		blend:     c.mode,
+		instanced: c.instances != nil,
+		mrt:       c.extraDsts[0] != nil,
+	}
+	// Without a custom shader, the default shader program depends on the filter and the address.
+	if c.shader == nil {
+		p.filter = c.filter
+		p.address = c.address
+	}
+	if hasLastPipeline && p != lastPipeline {
+		currentFrameStats.PipelineSwitches++
+	}
+	lastPipeline = p
+	hasLastPipeline = true
+}
+
+// LastFrameStats returns the statistics of the graphics commands executed in the last frame.
+//
+// LastFrameStats is concurrent-safe.
+func LastFrameStats() FrameStats {
+	lastFrameStatsM.Lock()
+	defer lastFrameStatsM.Unlock()
+	return lastFrameStats
+}
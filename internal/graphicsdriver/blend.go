@@ -0,0 +1,85 @@
+// Copyright 2018 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicsdriver
+
+// Blend represents the blend state of the source color and the destination color.
+//
+// The blended color is
+//
+//	c_out = c_src × factor_src <op> c_dst × factor_dst
+//
+// where the factors and the operation are specified separately for RGB and alpha.
+type Blend struct {
+	BlendFactorSourceRGB        BlendFactor
+	BlendFactorSourceAlpha      BlendFactor
+	BlendFactorDestinationRGB   BlendFactor
+	BlendFactorDestinationAlpha BlendFactor
+	BlendOperationRGB           BlendOperation
+	BlendOperationAlpha         BlendOperation
+}
+
+type BlendFactor byte
+
+const (
+	BlendFactorZero BlendFactor = iota
+	BlendFactorOne
+	BlendFactorSourceColor
+	BlendFactorOneMinusSourceColor
+	BlendFactorSourceAlpha
+	BlendFactorOneMinusSourceAlpha
+	BlendFactorDestinationColor
+	BlendFactorOneMinusDestinationColor
+	BlendFactorDestinationAlpha
+	BlendFactorOneMinusDestinationAlpha
+)
+
+type BlendOperation byte
+
+const (
+	BlendOperationAdd BlendOperation = iota
+	BlendOperationSubtract
+	BlendOperationReverseSubtract
+	BlendOperationMin
+	BlendOperationMax
+)
+
+func blendWithFactors(src, dst BlendFactor) Blend {
+	return Blend{
+		BlendFactorSourceRGB:        src,
+		BlendFactorSourceAlpha:      src,
+		BlendFactorDestinationRGB:   dst,
+		BlendFactorDestinationAlpha: dst,
+		BlendOperationRGB:           BlendOperationAdd,
+		BlendOperationAlpha:         BlendOperationAdd,
+	}
+}
+
+// The predefined blend states corresponding to the composite modes.
+var (
+	BlendSourceOver      = blendWithFactors(BlendFactorOne, BlendFactorOneMinusSourceAlpha)
+	BlendClear           = blendWithFactors(BlendFactorZero, BlendFactorZero)
+	BlendCopy            = blendWithFactors(BlendFactorOne, BlendFactorZero)
+	BlendDestination     = blendWithFactors(BlendFactorZero, BlendFactorOne)
+	BlendDestinationOver = blendWithFactors(BlendFactorOneMinusDestinationAlpha, BlendFactorOne)
+	BlendSourceIn        = blendWithFactors(BlendFactorDestinationAlpha, BlendFactorZero)
+	BlendDestinationIn   = blendWithFactors(BlendFactorZero, BlendFactorSourceAlpha)
+	BlendSourceOut       = blendWithFactors(BlendFactorOneMinusDestinationAlpha, BlendFactorZero)
+	BlendDestinationOut  = blendWithFactors(BlendFactorZero, BlendFactorOneMinusSourceAlpha)
+	BlendSourceAtop      = blendWithFactors(BlendFactorDestinationAlpha, BlendFactorOneMinusSourceAlpha)
+	BlendDestinationAtop = blendWithFactors(BlendFactorOneMinusDestinationAlpha, BlendFactorSourceAlpha)
+	BlendXor             = blendWithFactors(BlendFactorOneMinusDestinationAlpha, BlendFactorOneMinusSourceAlpha)
+	BlendLighter         = blendWithFactors(BlendFactorOne, BlendFactorOne)
+	BlendMultiply        = blendWithFactors(BlendFactorDestinationColor, BlendFactorZero)
+)
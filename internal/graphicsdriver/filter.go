@@ -29,3 +29,11 @@ const (
 	AddressClampToZero
 	AddressRepeat
 )
+
+type FillRule int
+
+const (
+	FillAll FillRule = iota
+	EvenOdd
+	NonZero
+)
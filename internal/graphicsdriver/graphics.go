@@ -49,7 +49,18 @@ type Graphics interface {
 	End()
 	SetTransparent(transparent bool)
 	SetVertices(vertices []float32, indices []uint16)
-	NewImage(width, height int) (Image, error)
+
+	// NewImage creates a new image.
+	//
+	// sampleCount is the number of samples per pixel for multisample antialiasing. 0 or 1 means no multisampling.
+	// A driver might use fewer samples than sampleCount, e.g., when the device doesn't support multisampling.
+	//
+	// format is the pixel format of the texture. Regardless of format, pixels are passed to and returned from
+	// Image in RGBA. The channels format doesn't have are dropped at ReplacePixels, and are filled with 0
+	// (0xff for alpha) at Pixels. A driver might use PixelFormatRGBA8 for the texture when the device doesn't
+	// support format.
+	NewImage(width, height int, sampleCount int, format PixelFormat) (Image, error)
+
 	NewScreenFramebufferImage(width, height int) (Image, error)
 	Initialize() error
 	SetVsyncEnabled(enabled bool)
@@ -60,16 +71,40 @@ type Graphics interface {
 	IsGL() bool
 	HasHighPrecisionFloat() bool
 	MaxImageSize() int
+	Capabilities() Capabilities
 
 	NewShader(program *shaderir.Program) (Shader, error)
 
+	// NewShaderAsync starts compiling a shader without waiting for the compilation.
+	//
+	// The returned shader is not registered to the driver until PendingShader's TryShader returns the shader.
+	// A driver might compile the shader synchronously when the device doesn't support asynchronous compilation.
+	NewShaderAsync(program *shaderir.Program) (PendingShader, error)
+
 	// DrawTriangles draws an image onto another image with the given parameters.
 	//
 	// uniforms represents a colletion of uniform variables. The values must be one of these types:
 	//
 	//   * float32
 	//   * []float32
-	DrawTriangles(dst ImageID, srcs [graphics.ShaderImageNum]ImageID, offsets [graphics.ShaderImageNum - 1][2]float32, shader ShaderID, indexLen int, indexOffset int, mode CompositeMode, colorM ColorM, filter Filter, address Address, dstRegion, srcRegion Region, uniforms []Uniform, evenOdd bool) error
+	DrawTriangles(dst ImageID, srcs [graphics.ShaderImageNum]ImageID, offsets [graphics.ShaderImageNum - 1][2]float32, shader ShaderID, indexLen int, indexOffset int, blend Blend, colorM ColorM, filter Filter, address Address, dstRegion, srcRegion Region, uniforms []Uniform, fillRule FillRule) error
+
+	// DrawTrianglesInstanced draws the triangles of the given index range repeatedly with the default shader.
+	//
+	// instances is the per-instance attributes. Each instance has graphics.InstanceFloatNum values.
+	// The source offsets in instances are in texels.
+	//
+	// DrawTrianglesInstanced is available only when Capabilities().Instancing is true.
+	DrawTrianglesInstanced(dst ImageID, src ImageID, instances []float32, indexLen int, indexOffset int, blend Blend, colorM ColorM, filter Filter, address Address, dstRegion, srcRegion Region) error
+
+	// DrawTrianglesMRT draws triangles with the given shader onto multiple destinations at the same time.
+	//
+	// The destinations in dsts are filled from the head, and the rest are InvalidImageID.
+	// The i-th destination is rendered with the i-th output color of the shader.
+	// All the destinations must have the same size, and must not be the screen or multisampled images.
+	//
+	// The number of the destinations must not exceed Capabilities().MaxRenderTargets.
+	DrawTrianglesMRT(dsts [graphics.ShaderDstImageNum]ImageID, srcs [graphics.ShaderImageNum]ImageID, offsets [graphics.ShaderImageNum - 1][2]float32, shader ShaderID, indexLen int, indexOffset int, blend Blend, dstRegion, srcRegion Region, uniforms []Uniform) error
 }
 
 // GraphicsNotReady represents that the graphics driver is not ready for recovering from the context lost.
@@ -80,9 +115,41 @@ type Image interface {
 	Dispose()
 	IsInvalidated() bool
 	Pixels() ([]byte, error)
+
+	// ReadPixelsAsync starts reading the pixels in the given region without waiting for GPU.
+	ReadPixelsAsync(x, y, width, height int) (PendingPixels, error)
+
 	ReplacePixels(args []*ReplacePixelsArgs)
 }
 
+// PendingPixels represents pixels being read from GPU asynchronously.
+type PendingPixels interface {
+	// TryPixels returns the pixels and true if reading the pixels is done.
+	// Otherwise, TryPixels returns nil and false.
+	TryPixels() ([]byte, bool)
+}
+
+// PendingShader represents a shader being compiled asynchronously.
+type PendingShader interface {
+	// TryShader returns the shader and true if compiling the shader is done.
+	// Otherwise, TryShader returns nil and false.
+	//
+	// TryShader returns an error if compiling the shader failed.
+	TryShader() (Shader, bool, error)
+}
+
+// Capabilities represents the capabilities of a graphics device.
+type Capabilities struct {
+	MaxImageSize     int
+	MaxSampleCount   int
+	FloatTexture     bool
+	ComputeShader    bool
+	Instancing       bool
+	MaxRenderTargets int
+	Renderer         string
+	Version          string
+}
+
 type ImageID int
 
 type ReplacePixelsArgs struct {
@@ -19,6 +19,7 @@ import (
 	"math"
 	"sort"
 	"strings"
+	"time"
 	"unsafe"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
@@ -89,6 +90,38 @@ vertex VertexOut VertexShader(
   return out;
 }
 
+struct InstanceIn {
+  packed_float4 offset;
+  packed_float4 color;
+};
+
+vertex VertexOut VertexShaderInstanced(
+  uint vid [[vertex_id]],
+  uint iid [[instance_id]],
+  const device VertexIn* vertices [[buffer(0)]],
+  constant float2& viewport_size [[buffer(1)]],
+  const device InstanceIn* instances [[buffer({{.InstanceBufferIndex}})]]
+) {
+  float4x4 projectionMatrix = float4x4(
+    float4(2.0 / viewport_size.x, 0, 0, 0),
+    float4(0, 2.0 / viewport_size.y, 0, 0),
+    float4(0, 0, 1, 0),
+    float4(-1, -1, 0, 1)
+  );
+
+  VertexIn in = vertices[vid];
+  InstanceIn instance = instances[iid];
+  float4 offset = instance.offset;
+  float4 color = instance.color;
+  VertexOut out = {
+    .position = projectionMatrix * float4(in.position + offset.xy, 0, 1),
+    .tex = in.tex + offset.zw,
+    .color = in.color * color,
+  };
+
+  return out;
+}
+
 float FloorMod(float x, float y) {
   if (x < 0.0) {
     return y - (-x - y * floor(-x/y));
@@ -292,13 +325,19 @@ FragmentShaderFunc(0, FILTER_SCREEN, ADDRESS_UNSAFE)
 #undef FragmentShaderFuncName
 `
 
+// instanceBufferIndex is the index of the vertex buffer for the per-instance attributes.
+// The indices from 1 are used for the uniform variables of the default shader.
+const instanceBufferIndex = 7
+
 type rpsKey struct {
-	useColorM     bool
-	filter        graphicsdriver.Filter
-	address       graphicsdriver.Address
-	compositeMode graphicsdriver.CompositeMode
-	stencilMode   stencilMode
-	screen        bool
+	useColorM   bool
+	filter      graphicsdriver.Filter
+	address     graphicsdriver.Address
+	blend       graphicsdriver.Blend
+	stencilMode stencilMode
+	screen      bool
+	instanced   bool
+	format      graphicsdriver.PixelFormat
 }
 
 type Graphics struct {
@@ -306,17 +345,23 @@ type Graphics struct {
 
 	screenRPS mtl.RenderPipelineState
 	rpss      map[rpsKey]mtl.RenderPipelineState
+	lib       mtl.Library
 	cq        mtl.CommandQueue
 	cb        mtl.CommandBuffer
 	rce       mtl.RenderCommandEncoder
 	dsss      map[stencilMode]mtl.DepthStencilState
 
+	// nonZeroDSS is a depth stencil state to prepare a stencil buffer with the non-zero rule.
+	// dsss[prepareStencil] is for the even-odd rule.
+	nonZeroDSS mtl.DepthStencilState
+
 	screenDrawable ca.MetalDrawable
 
 	buffers       map[mtl.CommandBuffer][]mtl.Buffer
 	unusedBuffers map[mtl.Buffer]struct{}
 
 	lastDst         *Image
+	lastExtraDsts   [graphics.ShaderDstImageNum - 1]*Image
 	lastStencilMode stencilMode
 
 	vb mtl.Buffer
@@ -335,6 +380,18 @@ type Graphics struct {
 	maxImageSize int
 	tmpTextures  []mtl.Texture
 
+	// frameCommandBuffers is the command buffers committed in the current frame.
+	frameCommandBuffers []mtl.CommandBuffer
+
+	// pendingFrameCommandBuffers is the command buffers of the past frames whose GPU time is not read yet.
+	pendingFrameCommandBuffers [][]mtl.CommandBuffer
+
+	// gpuFrameDuration is the time spent by GPU for the last frame whose command buffers are completed.
+	gpuFrameDuration time.Duration
+
+	// minimumPresentDuration is the minimum duration for which the previous drawable is displayed.
+	minimumPresentDuration time.Duration
+
 	pool unsafe.Pointer
 }
 
@@ -376,6 +433,42 @@ func (g *Graphics) End() {
 	g.pool = nil
 }
 
+// EndGPUFrame notifies the end of a frame, and returns the time spent by GPU for the last frame
+// whose command buffers are completed.
+//
+// The GPU time is read without waiting for the command buffers so as not to stall the pipeline.
+// Then, the returned duration is usually of a few frames before.
+func (g *Graphics) EndGPUFrame() time.Duration {
+	if len(g.frameCommandBuffers) > 0 {
+		g.pendingFrameCommandBuffers = append(g.pendingFrameCommandBuffers, g.frameCommandBuffers)
+		g.frameCommandBuffers = nil
+	}
+
+	for len(g.pendingFrameCommandBuffers) > 0 {
+		cbs := g.pendingFrameCommandBuffers[0]
+		completed := true
+		for _, cb := range cbs {
+			if s := cb.Status(); s != mtl.CommandBufferStatusCompleted && s != mtl.CommandBufferStatusError {
+				completed = false
+				break
+			}
+		}
+		if !completed {
+			break
+		}
+
+		var d float64
+		for _, cb := range cbs {
+			d += cb.GPUEndTime() - cb.GPUStartTime()
+			cb.Release()
+		}
+		g.gpuFrameDuration = time.Duration(d * float64(time.Second))
+		g.pendingFrameCommandBuffers[0] = nil
+		g.pendingFrameCommandBuffers = g.pendingFrameCommandBuffers[1:]
+	}
+	return g.gpuFrameDuration
+}
+
 func (g *Graphics) SetWindow(window uintptr) {
 	// Note that [NSApp mainWindow] returns nil when the window is borderless.
 	// Then the window is needed to be given explicitly.
@@ -475,7 +568,11 @@ func (g *Graphics) flushIfNeeded(present bool) {
 	g.flushRenderCommandEncoderIfNeeded()
 
 	if !g.view.presentsWithTransaction() && present && g.screenDrawable != (ca.MetalDrawable{}) {
-		g.cb.PresentDrawable(g.screenDrawable)
+		if g.minimumPresentDuration > 0 {
+			g.cb.PresentDrawableAfterMinimumDuration(g.screenDrawable, g.minimumPresentDuration.Seconds())
+		} else {
+			g.cb.PresentDrawable(g.screenDrawable)
+		}
 	}
 	g.cb.Commit()
 	if g.view.presentsWithTransaction() && present && g.screenDrawable != (ca.MetalDrawable{}) {
@@ -483,6 +580,10 @@ func (g *Graphics) flushIfNeeded(present bool) {
 		g.screenDrawable.Present()
 	}
 
+	// Keep the command buffer to read its GPU time later.
+	g.cb.Retain()
+	g.frameCommandBuffers = append(g.frameCommandBuffers, g.cb)
+
 	for _, t := range g.tmpTextures {
 		t.Release()
 	}
@@ -517,11 +618,12 @@ func (g *Graphics) genNextShaderID() graphicsdriver.ShaderID {
 	return g.nextShaderID
 }
 
-func (g *Graphics) NewImage(width, height int) (graphicsdriver.Image, error) {
+func (g *Graphics) NewImage(width, height int, sampleCount int, format graphicsdriver.PixelFormat) (graphicsdriver.Image, error) {
+	// Multisampling is not implemented on Metal yet, and sampleCount is ignored.
 	g.checkSize(width, height)
 	td := mtl.TextureDescriptor{
 		TextureType: mtl.TextureType2D,
-		PixelFormat: mtl.PixelFormatRGBA8UNorm,
+		PixelFormat: mtlPixelFormat(format),
 		Width:       graphics.InternalImageSize(width),
 		Height:      graphics.InternalImageSize(height),
 		StorageMode: storageMode,
@@ -534,6 +636,7 @@ func (g *Graphics) NewImage(width, height int) (graphicsdriver.Image, error) {
 		width:    width,
 		height:   height,
 		texture:  t,
+		format:   format,
 	}
 	g.addImage(i)
 	return i, nil
@@ -570,27 +673,61 @@ func (g *Graphics) SetTransparent(transparent bool) {
 	g.transparent = transparent
 }
 
-func operationToBlendFactor(c graphicsdriver.Operation) mtl.BlendFactor {
+func blendFactorToMetalBlendFactor(c graphicsdriver.BlendFactor) mtl.BlendFactor {
 	switch c {
-	case graphicsdriver.Zero:
+	case graphicsdriver.BlendFactorZero:
 		return mtl.BlendFactorZero
-	case graphicsdriver.One:
+	case graphicsdriver.BlendFactorOne:
 		return mtl.BlendFactorOne
-	case graphicsdriver.SrcAlpha:
+	case graphicsdriver.BlendFactorSourceColor:
+		return mtl.BlendFactorSourceColor
+	case graphicsdriver.BlendFactorOneMinusSourceColor:
+		return mtl.BlendFactorOneMinusSourceColor
+	case graphicsdriver.BlendFactorSourceAlpha:
 		return mtl.BlendFactorSourceAlpha
-	case graphicsdriver.DstAlpha:
-		return mtl.BlendFactorDestinationAlpha
-	case graphicsdriver.OneMinusSrcAlpha:
+	case graphicsdriver.BlendFactorOneMinusSourceAlpha:
 		return mtl.BlendFactorOneMinusSourceAlpha
-	case graphicsdriver.OneMinusDstAlpha:
-		return mtl.BlendFactorOneMinusDestinationAlpha
-	case graphicsdriver.DstColor:
+	case graphicsdriver.BlendFactorDestinationColor:
 		return mtl.BlendFactorDestinationColor
+	case graphicsdriver.BlendFactorOneMinusDestinationColor:
+		return mtl.BlendFactorOneMinusDestinationColor
+	case graphicsdriver.BlendFactorDestinationAlpha:
+		return mtl.BlendFactorDestinationAlpha
+	case graphicsdriver.BlendFactorOneMinusDestinationAlpha:
+		return mtl.BlendFactorOneMinusDestinationAlpha
 	default:
-		panic(fmt.Sprintf("metal: invalid operation: %d", c))
+		panic(fmt.Sprintf("metal: invalid blend factor: %d", c))
 	}
 }
 
+func blendOperationToMetalBlendOperation(o graphicsdriver.BlendOperation) mtl.BlendOperation {
+	switch o {
+	case graphicsdriver.BlendOperationAdd:
+		return mtl.BlendOperationAdd
+	case graphicsdriver.BlendOperationSubtract:
+		return mtl.BlendOperationSubtract
+	case graphicsdriver.BlendOperationReverseSubtract:
+		return mtl.BlendOperationReverseSubtract
+	case graphicsdriver.BlendOperationMin:
+		return mtl.BlendOperationMin
+	case graphicsdriver.BlendOperationMax:
+		return mtl.BlendOperationMax
+	default:
+		panic(fmt.Sprintf("metal: invalid blend operation: %d", o))
+	}
+}
+
+// setBlend sets the blend state to the color attachment descriptor.
+func setBlend(c *mtl.RenderPipelineColorAttachmentDescriptor, blend graphicsdriver.Blend) {
+	c.BlendingEnabled = true
+	c.SourceRGBBlendFactor = blendFactorToMetalBlendFactor(blend.BlendFactorSourceRGB)
+	c.SourceAlphaBlendFactor = blendFactorToMetalBlendFactor(blend.BlendFactorSourceAlpha)
+	c.DestinationRGBBlendFactor = blendFactorToMetalBlendFactor(blend.BlendFactorDestinationRGB)
+	c.DestinationAlphaBlendFactor = blendFactorToMetalBlendFactor(blend.BlendFactorDestinationAlpha)
+	c.RGBBlendOperation = blendOperationToMetalBlendOperation(blend.BlendOperationRGB)
+	c.AlphaBlendOperation = blendOperationToMetalBlendOperation(blend.BlendOperationAlpha)
+}
+
 func (g *Graphics) initializeView() error {
 	return g.view.initialize()
 }
@@ -616,12 +753,13 @@ func (g *Graphics) Initialize() error {
 	}
 
 	replaces := map[string]string{
-		"{{.FilterNearest}}":      fmt.Sprintf("%d", graphicsdriver.FilterNearest),
-		"{{.FilterLinear}}":       fmt.Sprintf("%d", graphicsdriver.FilterLinear),
-		"{{.FilterScreen}}":       fmt.Sprintf("%d", graphicsdriver.FilterScreen),
-		"{{.AddressClampToZero}}": fmt.Sprintf("%d", graphicsdriver.AddressClampToZero),
-		"{{.AddressRepeat}}":      fmt.Sprintf("%d", graphicsdriver.AddressRepeat),
-		"{{.AddressUnsafe}}":      fmt.Sprintf("%d", graphicsdriver.AddressUnsafe),
+		"{{.FilterNearest}}":       fmt.Sprintf("%d", graphicsdriver.FilterNearest),
+		"{{.FilterLinear}}":        fmt.Sprintf("%d", graphicsdriver.FilterLinear),
+		"{{.FilterScreen}}":        fmt.Sprintf("%d", graphicsdriver.FilterScreen),
+		"{{.AddressClampToZero}}":  fmt.Sprintf("%d", graphicsdriver.AddressClampToZero),
+		"{{.AddressRepeat}}":       fmt.Sprintf("%d", graphicsdriver.AddressRepeat),
+		"{{.AddressUnsafe}}":       fmt.Sprintf("%d", graphicsdriver.AddressUnsafe),
+		"{{.InstanceBufferIndex}}": fmt.Sprintf("%d", instanceBufferIndex),
 	}
 	src := source
 	for k, v := range replaces {
@@ -632,6 +770,7 @@ func (g *Graphics) Initialize() error {
 	if err != nil {
 		return err
 	}
+	g.lib = lib
 	vs, err := lib.MakeFunction("VertexShader")
 	if err != nil {
 		return err
@@ -669,57 +808,41 @@ func (g *Graphics) Initialize() error {
 					graphicsdriver.FilterNearest,
 					graphicsdriver.FilterLinear,
 				} {
-					for c := graphicsdriver.CompositeModeSourceOver; c <= graphicsdriver.CompositeModeMax; c++ {
+					// The render pipeline states for the other blend states are created lazily.
+					for _, b := range []graphicsdriver.Blend{
+						graphicsdriver.BlendSourceOver,
+						graphicsdriver.BlendClear,
+						graphicsdriver.BlendCopy,
+						graphicsdriver.BlendDestination,
+						graphicsdriver.BlendDestinationOver,
+						graphicsdriver.BlendSourceIn,
+						graphicsdriver.BlendDestinationIn,
+						graphicsdriver.BlendSourceOut,
+						graphicsdriver.BlendDestinationOut,
+						graphicsdriver.BlendSourceAtop,
+						graphicsdriver.BlendDestinationAtop,
+						graphicsdriver.BlendXor,
+						graphicsdriver.BlendLighter,
+						graphicsdriver.BlendMultiply,
+					} {
 						for _, stencil := range []stencilMode{
 							prepareStencil,
 							drawWithStencil,
 							noStencil,
 						} {
-							cmi := 0
-							if cm {
-								cmi = 1
+							key := rpsKey{
+								screen:      screen,
+								useColorM:   cm,
+								filter:      f,
+								address:     a,
+								blend:       b,
+								stencilMode: stencil,
 							}
-							fs, err := lib.MakeFunction(fmt.Sprintf("FragmentShader_%d_%d_%d", cmi, f, a))
+							rps, err := g.makeRenderPipelineState(vs, key)
 							if err != nil {
 								return err
 							}
-							rpld := mtl.RenderPipelineDescriptor{
-								VertexFunction:   vs,
-								FragmentFunction: fs,
-							}
-							if stencil != noStencil {
-								rpld.StencilAttachmentPixelFormat = mtl.PixelFormatStencil8
-							}
-
-							pix := mtl.PixelFormatRGBA8UNorm
-							if screen {
-								pix = g.view.colorPixelFormat()
-							}
-							rpld.ColorAttachments[0].PixelFormat = pix
-							rpld.ColorAttachments[0].BlendingEnabled = true
-
-							src, dst := c.Operations()
-							rpld.ColorAttachments[0].DestinationAlphaBlendFactor = operationToBlendFactor(dst)
-							rpld.ColorAttachments[0].DestinationRGBBlendFactor = operationToBlendFactor(dst)
-							rpld.ColorAttachments[0].SourceAlphaBlendFactor = operationToBlendFactor(src)
-							rpld.ColorAttachments[0].SourceRGBBlendFactor = operationToBlendFactor(src)
-							if stencil == prepareStencil {
-								rpld.ColorAttachments[0].WriteMask = mtl.ColorWriteMaskNone
-							} else {
-								rpld.ColorAttachments[0].WriteMask = mtl.ColorWriteMaskAll
-							}
-							rps, err := g.view.getMTLDevice().MakeRenderPipelineState(rpld)
-							if err != nil {
-								return err
-							}
-							g.rpss[rpsKey{
-								screen:        screen,
-								useColorM:     cm,
-								filter:        f,
-								address:       a,
-								compositeMode: c,
-								stencilMode:   stencil,
-							}] = rps
+							g.rpss[key] = rps
 						}
 					}
 				}
@@ -742,6 +865,20 @@ func (g *Graphics) Initialize() error {
 			StencilCompareFunction:    mtl.CompareFunctionAlways,
 		},
 	})
+	g.nonZeroDSS = g.view.getMTLDevice().MakeDepthStencilState(mtl.DepthStencilDescriptor{
+		BackFaceStencil: mtl.StencilDescriptor{
+			StencilFailureOperation:   mtl.StencilOperationKeep,
+			DepthFailureOperation:     mtl.StencilOperationKeep,
+			DepthStencilPassOperation: mtl.StencilOperationDecrementWrap,
+			StencilCompareFunction:    mtl.CompareFunctionAlways,
+		},
+		FrontFaceStencil: mtl.StencilDescriptor{
+			StencilFailureOperation:   mtl.StencilOperationKeep,
+			DepthFailureOperation:     mtl.StencilOperationKeep,
+			DepthStencilPassOperation: mtl.StencilOperationIncrementWrap,
+			StencilCompareFunction:    mtl.CompareFunctionAlways,
+		},
+	})
 	g.dsss[drawWithStencil] = g.view.getMTLDevice().MakeDepthStencilState(mtl.DepthStencilDescriptor{
 		BackFaceStencil: mtl.StencilDescriptor{
 			StencilFailureOperation:   mtl.StencilOperationKeep,
@@ -775,6 +912,60 @@ func (g *Graphics) Initialize() error {
 	return nil
 }
 
+// makeRenderPipelineState creates a render pipeline state for the default shader with the given vertex function.
+func (g *Graphics) makeRenderPipelineState(vs mtl.Function, key rpsKey) (mtl.RenderPipelineState, error) {
+	cmi := 0
+	if key.useColorM {
+		cmi = 1
+	}
+	fs, err := g.lib.MakeFunction(fmt.Sprintf("FragmentShader_%d_%d_%d", cmi, key.filter, key.address))
+	if err != nil {
+		return mtl.RenderPipelineState{}, err
+	}
+	rpld := mtl.RenderPipelineDescriptor{
+		VertexFunction:   vs,
+		FragmentFunction: fs,
+	}
+	if key.stencilMode != noStencil {
+		rpld.StencilAttachmentPixelFormat = mtl.PixelFormatStencil8
+	}
+
+	pix := mtlPixelFormat(key.format)
+	if key.screen {
+		pix = g.view.colorPixelFormat()
+	}
+	rpld.ColorAttachments[0].PixelFormat = pix
+	setBlend(&rpld.ColorAttachments[0], key.blend)
+	if key.stencilMode == prepareStencil {
+		rpld.ColorAttachments[0].WriteMask = mtl.ColorWriteMaskNone
+	} else {
+		rpld.ColorAttachments[0].WriteMask = mtl.ColorWriteMaskAll
+	}
+	return g.view.getMTLDevice().MakeRenderPipelineState(rpld)
+}
+
+// renderPipelineState returns a render pipeline state for the default shader.
+// The render pipeline states for instanced drawing and non-predefined blend states are created lazily.
+func (g *Graphics) renderPipelineState(key rpsKey) (mtl.RenderPipelineState, error) {
+	if rps, ok := g.rpss[key]; ok {
+		return rps, nil
+	}
+	name := "VertexShader"
+	if key.instanced {
+		name = "VertexShaderInstanced"
+	}
+	vs, err := g.lib.MakeFunction(name)
+	if err != nil {
+		return mtl.RenderPipelineState{}, err
+	}
+	rps, err := g.makeRenderPipelineState(vs, key)
+	if err != nil {
+		return mtl.RenderPipelineState{}, err
+	}
+	g.rpss[key] = rps
+	return rps, nil
+}
+
 func (g *Graphics) flushRenderCommandEncoderIfNeeded() {
 	if g.rce == (mtl.RenderCommandEncoder{}) {
 		return
@@ -782,16 +973,18 @@ func (g *Graphics) flushRenderCommandEncoderIfNeeded() {
 	g.rce.EndEncoding()
 	g.rce = mtl.RenderCommandEncoder{}
 	g.lastDst = nil
+	g.lastExtraDsts = [graphics.ShaderDstImageNum - 1]*Image{}
 }
 
-func (g *Graphics) draw(rps mtl.RenderPipelineState, dst *Image, dstRegion graphicsdriver.Region, srcs [graphics.ShaderImageNum]*Image, indexLen int, indexOffset int, uniforms []graphicsdriver.Uniform, stencilMode stencilMode) error {
+func (g *Graphics) draw(rps mtl.RenderPipelineState, dst *Image, extraDsts [graphics.ShaderDstImageNum - 1]*Image, dstRegion graphicsdriver.Region, srcs [graphics.ShaderImageNum]*Image, indexLen int, indexOffset int, uniforms []graphicsdriver.Uniform, stencilMode stencilMode, fillRule graphicsdriver.FillRule, instances []float32) error {
 	// When prepareing a stencil buffer, flush the current render command encoder
 	// to make sure the stencil buffer is cleared when loading.
 	// TODO: What about clearing the stencil buffer by vertices?
-	if g.lastDst != dst || (g.lastStencilMode == noStencil) != (stencilMode == noStencil) || stencilMode == prepareStencil {
+	if g.lastDst != dst || g.lastExtraDsts != extraDsts || (g.lastStencilMode == noStencil) != (stencilMode == noStencil) || stencilMode == prepareStencil {
 		g.flushRenderCommandEncoderIfNeeded()
 	}
 	g.lastDst = dst
+	g.lastExtraDsts = extraDsts
 	g.lastStencilMode = stencilMode
 
 	if g.rce == (mtl.RenderCommandEncoder{}) {
@@ -814,6 +1007,15 @@ func (g *Graphics) draw(rps mtl.RenderPipelineState, dst *Image, dstRegion graph
 		rpd.ColorAttachments[0].Texture = t
 		rpd.ColorAttachments[0].ClearColor = mtl.ClearColor{}
 
+		for i, e := range extraDsts {
+			if e == nil {
+				continue
+			}
+			rpd.ColorAttachments[i+1].LoadAction = mtl.LoadActionLoad
+			rpd.ColorAttachments[i+1].StoreAction = mtl.StoreActionStore
+			rpd.ColorAttachments[i+1].Texture = e.texture
+		}
+
 		if stencilMode == prepareStencil {
 			dst.ensureStencil()
 			rpd.StencilAttachment.LoadAction = mtl.LoadActionClear
@@ -868,14 +1070,62 @@ func (g *Graphics) draw(rps mtl.RenderPipelineState, dst *Image, dstRegion graph
 		}
 	}
 
-	g.rce.SetDepthStencilState(g.dsss[stencilMode])
-
-	g.rce.DrawIndexedPrimitives(mtl.PrimitiveTypeTriangle, indexLen, mtl.IndexTypeUInt16, g.ib, indexOffset*2)
+	dss := g.dsss[stencilMode]
+	if stencilMode == prepareStencil && fillRule == graphicsdriver.NonZero {
+		dss = g.nonZeroDSS
+	}
+	g.rce.SetDepthStencilState(dss)
+
+	if instances != nil {
+		size := unsafe.Sizeof(instances[0]) * uintptr(len(instances))
+		b := g.availableBuffer(size)
+		b.CopyToContents(unsafe.Pointer(&instances[0]), size)
+		g.rce.SetVertexBuffer(b, 0, instanceBufferIndex)
+		g.rce.DrawIndexedPrimitivesInstanced(mtl.PrimitiveTypeTriangle, indexLen, mtl.IndexTypeUInt16, g.ib, indexOffset*2, len(instances)/graphics.InstanceFloatNum)
+	} else {
+		g.rce.DrawIndexedPrimitives(mtl.PrimitiveTypeTriangle, indexLen, mtl.IndexTypeUInt16, g.ib, indexOffset*2)
+	}
 
 	return nil
 }
 
-func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.ShaderImageNum]graphicsdriver.ImageID, offsets [graphics.ShaderImageNum - 1][2]float32, shaderID graphicsdriver.ShaderID, indexLen int, indexOffset int, mode graphicsdriver.CompositeMode, colorM graphicsdriver.ColorM, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, uniforms []graphicsdriver.Uniform, evenOdd bool) error {
+func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.ShaderImageNum]graphicsdriver.ImageID, offsets [graphics.ShaderImageNum - 1][2]float32, shaderID graphicsdriver.ShaderID, indexLen int, indexOffset int, mode graphicsdriver.Blend, colorM graphicsdriver.ColorM, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, uniforms []graphicsdriver.Uniform, fillRule graphicsdriver.FillRule) error {
+	return g.drawTriangles(dstID, srcIDs, offsets, shaderID, indexLen, indexOffset, mode, colorM, filter, address, dstRegion, srcRegion, uniforms, fillRule, nil, [graphics.ShaderDstImageNum - 1]*Image{})
+}
+
+func (g *Graphics) DrawTrianglesInstanced(dstID graphicsdriver.ImageID, srcID graphicsdriver.ImageID, instances []float32, indexLen int, indexOffset int, mode graphicsdriver.Blend, colorM graphicsdriver.ColorM, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region) error {
+	if len(instances) == 0 {
+		return nil
+	}
+	return g.drawTriangles(dstID, [graphics.ShaderImageNum]graphicsdriver.ImageID{srcID}, [graphics.ShaderImageNum - 1][2]float32{}, graphicsdriver.InvalidShaderID, indexLen, indexOffset, mode, colorM, filter, address, dstRegion, srcRegion, nil, graphicsdriver.FillAll, instances, [graphics.ShaderDstImageNum - 1]*Image{})
+}
+
+func (g *Graphics) DrawTrianglesMRT(dstIDs [graphics.ShaderDstImageNum]graphicsdriver.ImageID, srcIDs [graphics.ShaderImageNum]graphicsdriver.ImageID, offsets [graphics.ShaderImageNum - 1][2]float32, shaderID graphicsdriver.ShaderID, indexLen int, indexOffset int, mode graphicsdriver.Blend, dstRegion, srcRegion graphicsdriver.Region, uniforms []graphicsdriver.Uniform) error {
+	if shaderID == graphicsdriver.InvalidShaderID {
+		panic("metal: a shader must be specified for multiple render targets")
+	}
+
+	dst := g.images[dstIDs[0]]
+	var extraDsts [graphics.ShaderDstImageNum - 1]*Image
+	for i, id := range dstIDs {
+		if id == graphicsdriver.InvalidImageID {
+			continue
+		}
+		img := g.images[id]
+		if img.screen {
+			panic("metal: the screen image cannot be a destination of multiple render targets")
+		}
+		if img.width != dst.width || img.height != dst.height {
+			panic("metal: all the destinations of multiple render targets must have the same size")
+		}
+		if i > 0 {
+			extraDsts[i-1] = img
+		}
+	}
+	return g.drawTriangles(dstIDs[0], srcIDs, offsets, shaderID, indexLen, indexOffset, mode, nil, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, srcRegion, uniforms, graphicsdriver.FillAll, nil, extraDsts)
+}
+
+func (g *Graphics) drawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.ShaderImageNum]graphicsdriver.ImageID, offsets [graphics.ShaderImageNum - 1][2]float32, shaderID graphicsdriver.ShaderID, indexLen int, indexOffset int, mode graphicsdriver.Blend, colorM graphicsdriver.ColorM, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, uniforms []graphicsdriver.Uniform, fillRule graphicsdriver.FillRule, instances []float32, extraDsts [graphics.ShaderDstImageNum - 1]*Image) error {
 	dst := g.images[dstID]
 
 	if dst.screen {
@@ -890,7 +1140,23 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 	rpss := map[stencilMode]mtl.RenderPipelineState{}
 	var uniformVars []graphicsdriver.Uniform
 	if shaderID == graphicsdriver.InvalidShaderID {
-		if dst.screen && filter == graphicsdriver.FilterScreen {
+		if instances != nil {
+			// Instanced drawing is always done without a stencil buffer.
+			rps, err := g.renderPipelineState(rpsKey{
+				screen:      dst.screen,
+				useColorM:   !colorM.IsIdentity(),
+				filter:      filter,
+				address:     address,
+				blend:       mode,
+				stencilMode: noStencil,
+				instanced:   true,
+				format:      dst.format,
+			})
+			if err != nil {
+				return err
+			}
+			rpss[noStencil] = rps
+		} else if dst.screen && filter == graphicsdriver.FilterScreen {
 			rpss[noStencil] = g.screenRPS
 		} else {
 			for _, stencil := range []stencilMode{
@@ -898,14 +1164,19 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 				drawWithStencil,
 				noStencil,
 			} {
-				rpss[stencil] = g.rpss[rpsKey{
-					screen:        dst.screen,
-					useColorM:     !colorM.IsIdentity(),
-					filter:        filter,
-					address:       address,
-					compositeMode: mode,
-					stencilMode:   stencil,
-				}]
+				rps, err := g.renderPipelineState(rpsKey{
+					screen:      dst.screen,
+					useColorM:   !colorM.IsIdentity(),
+					filter:      filter,
+					address:     address,
+					blend:       mode,
+					stencilMode: stencil,
+					format:      dst.format,
+				})
+				if err != nil {
+					return err
+				}
+				rpss[stencil] = rps
 			}
 		}
 
@@ -949,13 +1220,20 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 			},
 		}
 	} else {
+		var colorAttachmentFormats [graphics.ShaderDstImageNum]mtl.PixelFormat
+		colorAttachmentFormats[0] = mtlPixelFormat(dst.format)
+		for i, e := range extraDsts {
+			if e != nil {
+				colorAttachmentFormats[i+1] = mtlPixelFormat(e.format)
+			}
+		}
 		for _, stencil := range []stencilMode{
 			prepareStencil,
 			drawWithStencil,
 			noStencil,
 		} {
 			var err error
-			rpss[stencil], err = g.shaders[shaderID].RenderPipelineState(g.view.getMTLDevice(), mode, stencil)
+			rpss[stencil], err = g.shaders[shaderID].RenderPipelineState(g.view.getMTLDevice(), mode, stencil, colorAttachmentFormats)
 			if err != nil {
 				return err
 			}
@@ -1017,21 +1295,22 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 		}
 
 		// Set the additional uniform variables.
+		ir := g.shaders[shaderID].ir
 		for i, v := range uniforms {
 			const offset = graphics.PreservedUniformVariablesNum
-			uniformVars[offset+i] = v
+			uniformVars[offset+i] = adjustUniformForMetal(v, &ir.Uniforms[offset+i])
 		}
 	}
 
-	if evenOdd {
-		if err := g.draw(rpss[prepareStencil], dst, dstRegion, srcs, indexLen, indexOffset, uniformVars, prepareStencil); err != nil {
+	if fillRule != graphicsdriver.FillAll {
+		if err := g.draw(rpss[prepareStencil], dst, extraDsts, dstRegion, srcs, indexLen, indexOffset, uniformVars, prepareStencil, fillRule, nil); err != nil {
 			return err
 		}
-		if err := g.draw(rpss[drawWithStencil], dst, dstRegion, srcs, indexLen, indexOffset, uniformVars, drawWithStencil); err != nil {
+		if err := g.draw(rpss[drawWithStencil], dst, extraDsts, dstRegion, srcs, indexLen, indexOffset, uniformVars, drawWithStencil, fillRule, nil); err != nil {
 			return err
 		}
 	} else {
-		if err := g.draw(rpss[noStencil], dst, dstRegion, srcs, indexLen, indexOffset, uniformVars, noStencil); err != nil {
+		if err := g.draw(rpss[noStencil], dst, extraDsts, dstRegion, srcs, indexLen, indexOffset, uniformVars, noStencil, fillRule, instances); err != nil {
 			return err
 		}
 	}
@@ -1043,6 +1322,12 @@ func (g *Graphics) SetVsyncEnabled(enabled bool) {
 	g.view.setDisplaySyncEnabled(enabled)
 }
 
+// SetMinimumPresentDuration sets the minimum duration for which the previous frame is displayed before the next
+// frame is presented. 0 means that the next frame is presented as soon as possible.
+func (g *Graphics) SetMinimumPresentDuration(duration time.Duration) {
+	g.minimumPresentDuration = duration
+}
+
 func (g *Graphics) SetFullscreen(fullscreen bool) {
 	g.view.setFullscreen(fullscreen)
 }
@@ -1101,6 +1386,22 @@ func (g *Graphics) MaxImageSize() int {
 	return g.maxImageSize
 }
 
+func (g *Graphics) Capabilities() graphicsdriver.Capabilities {
+	return graphicsdriver.Capabilities{
+		MaxImageSize: g.MaxImageSize(),
+		// Multisampling is not implemented on Metal yet.
+		MaxSampleCount: 1,
+		FloatTexture:   true,
+		ComputeShader:  true,
+		Instancing:     true,
+		// All the Metal devices support at least 4 color attachments.
+		MaxRenderTargets: graphics.ShaderDstImageNum,
+		Renderer:         g.view.getMTLDevice().Name,
+		// Metal doesn't have an API to get the driver version.
+		Version: "",
+	}
+}
+
 func (g *Graphics) NewShader(program *shaderir.Program) (graphicsdriver.Shader, error) {
 	s, err := newShader(g.view.getMTLDevice(), g.genNextShaderID(), program)
 	if err != nil {
@@ -1110,6 +1411,29 @@ func (g *Graphics) NewShader(program *shaderir.Program) (graphicsdriver.Shader,
 	return s, nil
 }
 
+func (g *Graphics) NewShaderAsync(program *shaderir.Program) (graphicsdriver.PendingShader, error) {
+	return newPendingShader(g, g.view.getMTLDevice(), g.genNextShaderID(), program), nil
+}
+
+// PrecompileShader creates the render pipeline states of the shader for the given destination format and blends,
+// which are otherwise created lazily at the first draw call.
+func (g *Graphics) PrecompileShader(shaderID graphicsdriver.ShaderID, format graphicsdriver.PixelFormat, blends []graphicsdriver.Blend) error {
+	var colorAttachmentFormats [graphics.ShaderDstImageNum]mtl.PixelFormat
+	colorAttachmentFormats[0] = mtlPixelFormat(format)
+	for _, b := range blends {
+		for _, stencil := range []stencilMode{
+			prepareStencil,
+			drawWithStencil,
+			noStencil,
+		} {
+			if _, err := g.shaders[shaderID].RenderPipelineState(g.view.getMTLDevice(), b, stencil, colorAttachmentFormats); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (g *Graphics) addShader(shader *Shader) {
 	if g.shaders == nil {
 		g.shaders = map[graphicsdriver.ShaderID]*Shader{}
@@ -1132,6 +1456,23 @@ type Image struct {
 	screen   bool
 	texture  mtl.Texture
 	stencil  mtl.Texture
+
+	// format is the pixel format of the texture.
+	format graphicsdriver.PixelFormat
+}
+
+// mtlPixelFormat returns the Metal pixel format for the given pixel format.
+func mtlPixelFormat(format graphicsdriver.PixelFormat) mtl.PixelFormat {
+	switch format {
+	case graphicsdriver.PixelFormatRGBA8:
+		return mtl.PixelFormatRGBA8UNorm
+	case graphicsdriver.PixelFormatR8:
+		return mtl.PixelFormatR8UNorm
+	case graphicsdriver.PixelFormatRG8:
+		return mtl.PixelFormatRG8UNorm
+	default:
+		panic(fmt.Sprintf("metal: invalid pixel format: %d", format))
+	}
 }
 
 func (i *Image) ID() graphicsdriver.ImageID {
@@ -1186,11 +1527,60 @@ func (i *Image) Pixels() ([]byte, error) {
 	i.graphics.flushIfNeeded(false)
 	i.syncTexture()
 
-	b := make([]byte, 4*i.width*i.height)
-	i.texture.GetBytes(&b[0], uintptr(4*i.width), mtl.Region{
+	n := i.format.BytesPerPixel()
+	b := make([]byte, n*i.width*i.height)
+	i.texture.GetBytes(&b[0], uintptr(n*i.width), mtl.Region{
 		Size: mtl.Size{Width: i.width, Height: i.height, Depth: 1},
 	}, 0)
-	return b, nil
+	return i.format.UnpackPixels(b), nil
+}
+
+func (i *Image) ReadPixelsAsync(x, y, width, height int) (graphicsdriver.PendingPixels, error) {
+	g := i.graphics
+
+	g.flushRenderCommandEncoderIfNeeded()
+
+	n := i.format.BytesPerPixel()
+	size := n * width * height
+	buf := g.view.getMTLDevice().MakeBufferWithLength(uintptr(size), resourceStorageMode)
+
+	if g.cb == (mtl.CommandBuffer{}) {
+		g.cb = g.cq.MakeCommandBuffer()
+	}
+	bce := g.cb.MakeBlitCommandEncoder()
+	bce.CopyFromTextureToBuffer(i.texture, 0, 0, mtl.Origin{X: x, Y: y, Z: 0}, mtl.Size{Width: width, Height: height, Depth: 1}, buf, 0, n*width, size)
+	// Calling Synchronize is ignored on iOS (see mtl.m).
+	bce.Synchronize(buf)
+	bce.EndEncoding()
+
+	g.cb.Retain()
+	return &pendingPixels{
+		cb:     g.cb,
+		buffer: buf,
+		size:   size,
+		format: i.format,
+	}, nil
+}
+
+// pendingPixels represents pixels being copied into a buffer.
+type pendingPixels struct {
+	cb     mtl.CommandBuffer
+	buffer mtl.Buffer
+	size   int
+	format graphicsdriver.PixelFormat
+}
+
+func (p *pendingPixels) TryPixels() ([]byte, bool) {
+	// TODO: Handle an error?
+	if s := p.cb.Status(); s != mtl.CommandBufferStatusCompleted && s != mtl.CommandBufferStatusError {
+		return nil, false
+	}
+
+	pix := make([]byte, p.size)
+	p.buffer.CopyFromContents(unsafe.Pointer(&pix[0]), uintptr(p.size))
+	p.buffer.Release()
+	p.cb.Release()
+	return p.format.UnpackPixels(pix), true
 }
 
 func (i *Image) ReplacePixels(args []*graphicsdriver.ReplacePixelsArgs) {
@@ -1226,7 +1616,7 @@ func (i *Image) ReplacePixels(args []*graphicsdriver.ReplacePixelsArgs) {
 	// The texture cannot be reused until sending the pixels finishes, then create new ones for each call.
 	td := mtl.TextureDescriptor{
 		TextureType: mtl.TextureType2D,
-		PixelFormat: mtl.PixelFormatRGBA8UNorm,
+		PixelFormat: mtlPixelFormat(i.format),
 		Width:       w,
 		Height:      h,
 		StorageMode: storageMode,
@@ -1235,11 +1625,13 @@ func (i *Image) ReplacePixels(args []*graphicsdriver.ReplacePixelsArgs) {
 	t := g.view.getMTLDevice().MakeTexture(td)
 	g.tmpTextures = append(g.tmpTextures, t)
 
+	n := i.format.BytesPerPixel()
 	for _, a := range args {
+		pix := i.format.PackPixels(a.Pixels)
 		t.ReplaceRegion(mtl.Region{
 			Origin: mtl.Origin{X: a.X - minX, Y: a.Y - minY, Z: 0},
 			Size:   mtl.Size{Width: a.Width, Height: a.Height, Depth: 1},
-		}, 0, unsafe.Pointer(&a.Pixels[0]), 4*a.Width)
+		}, 0, unsafe.Pointer(&pix[0]), n*a.Width)
 	}
 
 	if g.cb == (mtl.CommandBuffer{}) {
@@ -28,6 +28,7 @@ import (
 	"unsafe"
 )
 
+// #cgo CFLAGS: -Wno-unguarded-availability-new
 // #cgo !ios CFLAGS: -mmacosx-version-min=10.12
 // #cgo LDFLAGS: -framework Metal -framework CoreGraphics -framework Foundation
 //
@@ -101,6 +102,9 @@ type PixelFormat uint16
 // The data formats that describe the organization and characteristics
 // of individual pixels in a texture.
 const (
+	PixelFormatInvalid        PixelFormat = 0   // The default value of the pixel format for the RenderPipelineState.
+	PixelFormatR8UNorm        PixelFormat = 10  // Ordinary format with one 8-bit normalized unsigned integer component.
+	PixelFormatRG8UNorm       PixelFormat = 30  // Ordinary format with two 8-bit normalized unsigned integer components.
 	PixelFormatRGBA8UNorm     PixelFormat = 70  // Ordinary format with four 8-bit normalized unsigned integer components in RGBA order.
 	PixelFormatRGBA8UNormSRGB PixelFormat = 71  // Ordinary format with four 8-bit normalized unsigned integer components in RGBA order with conversion between sRGB and linear space.
 	PixelFormatBGRA8UNorm     PixelFormat = 80  // Ordinary format with four 8-bit normalized unsigned integer components in BGRA order.
@@ -290,6 +294,16 @@ const (
 	BlendFactorOneMinusSource1Alpha     BlendFactor = 18
 )
 
+type BlendOperation uint8
+
+const (
+	BlendOperationAdd             BlendOperation = 0
+	BlendOperationSubtract        BlendOperation = 1
+	BlendOperationReverseSubtract BlendOperation = 2
+	BlendOperationMin             BlendOperation = 3
+	BlendOperationMax             BlendOperation = 4
+)
+
 type ColorWriteMask uint8
 
 const (
@@ -358,7 +372,8 @@ type RenderPipelineDescriptor struct {
 	FragmentFunction Function
 
 	// ColorAttachments is an array of attachments that store color data.
-	ColorAttachments [1]RenderPipelineColorAttachmentDescriptor
+	// An attachment with PixelFormatInvalid is not used.
+	ColorAttachments [4]RenderPipelineColorAttachmentDescriptor
 
 	// StencilAttachmentPixelFormat is the pixel format of the attachment that stores stencil data.
 	StencilAttachmentPixelFormat PixelFormat
@@ -379,6 +394,9 @@ type RenderPipelineColorAttachmentDescriptor struct {
 	SourceAlphaBlendFactor      BlendFactor
 	SourceRGBBlendFactor        BlendFactor
 
+	AlphaBlendOperation BlendOperation
+	RGBBlendOperation   BlendOperation
+
 	WriteMask ColorWriteMask
 }
 
@@ -388,7 +406,8 @@ type RenderPipelineColorAttachmentDescriptor struct {
 // Reference: https://developer.apple.com/documentation/metal/mtlrenderpassdescriptor.
 type RenderPassDescriptor struct {
 	// ColorAttachments is array of state information for attachments that store color data.
-	ColorAttachments [1]RenderPassColorAttachmentDescriptor
+	// An attachment without a texture is not used.
+	ColorAttachments [4]RenderPassColorAttachmentDescriptor
 
 	// StencilAttachment is state information for an attachment that stores stencil data.
 	StencilAttachment RenderPassStencilAttachment
@@ -511,22 +530,28 @@ func (d Device) MakeLibrary(source string, opt CompileOptions) (Library, error)
 //
 // Reference: https://developer.apple.com/documentation/metal/mtldevice/1433369-makerenderpipelinestate.
 func (d Device) MakeRenderPipelineState(rpd RenderPipelineDescriptor) (RenderPipelineState, error) {
-	blendingEnabled := 0
-	if rpd.ColorAttachments[0].BlendingEnabled {
-		blendingEnabled = 1
-	}
-	c := &rpd.ColorAttachments[0]
 	descriptor := C.struct_RenderPipelineDescriptor{
-		VertexFunction:                              rpd.VertexFunction.function,
-		FragmentFunction:                            rpd.FragmentFunction.function,
-		ColorAttachment0PixelFormat:                 C.uint16_t(c.PixelFormat),
-		ColorAttachment0BlendingEnabled:             C.uint8_t(blendingEnabled),
-		ColorAttachment0DestinationAlphaBlendFactor: C.uint8_t(c.DestinationAlphaBlendFactor),
-		ColorAttachment0DestinationRGBBlendFactor:   C.uint8_t(c.DestinationRGBBlendFactor),
-		ColorAttachment0SourceAlphaBlendFactor:      C.uint8_t(c.SourceAlphaBlendFactor),
-		ColorAttachment0SourceRGBBlendFactor:        C.uint8_t(c.SourceRGBBlendFactor),
-		ColorAttachment0WriteMask:                   C.uint8_t(c.WriteMask),
-		StencilAttachmentPixelFormat:                C.uint8_t(rpd.StencilAttachmentPixelFormat),
+		VertexFunction:               rpd.VertexFunction.function,
+		FragmentFunction:             rpd.FragmentFunction.function,
+		StencilAttachmentPixelFormat: C.uint8_t(rpd.StencilAttachmentPixelFormat),
+	}
+	for i := range rpd.ColorAttachments {
+		c := &rpd.ColorAttachments[i]
+		blendingEnabled := 0
+		if c.BlendingEnabled {
+			blendingEnabled = 1
+		}
+		descriptor.ColorAttachments[i] = C.struct_RenderPipelineColorAttachmentDescriptor{
+			PixelFormat:                 C.uint16_t(c.PixelFormat),
+			BlendingEnabled:             C.uint8_t(blendingEnabled),
+			DestinationAlphaBlendFactor: C.uint8_t(c.DestinationAlphaBlendFactor),
+			DestinationRGBBlendFactor:   C.uint8_t(c.DestinationRGBBlendFactor),
+			SourceAlphaBlendFactor:      C.uint8_t(c.SourceAlphaBlendFactor),
+			SourceRGBBlendFactor:        C.uint8_t(c.SourceRGBBlendFactor),
+			AlphaBlendOperation:         C.uint8_t(c.AlphaBlendOperation),
+			RGBBlendOperation:           C.uint8_t(c.RGBBlendOperation),
+			WriteMask:                   C.uint8_t(c.WriteMask),
+		}
 	}
 	rps := C.Device_MakeRenderPipelineState(d.device, descriptor)
 	if rps.RenderPipelineState == nil {
@@ -653,6 +678,15 @@ func (cb CommandBuffer) PresentDrawable(d Drawable) {
 	C.CommandBuffer_PresentDrawable(cb.commandBuffer, d.Drawable())
 }
 
+// PresentDrawableAfterMinimumDuration registers a drawable presentation to occur after the previous drawable
+// has been displayed for at least the given duration in seconds.
+// If the API is not available, PresentDrawableAfterMinimumDuration works as same as PresentDrawable.
+//
+// Reference: https://developer.apple.com/documentation/metal/mtlcommandbuffer/2795831-presentdrawable.
+func (cb CommandBuffer) PresentDrawableAfterMinimumDuration(d Drawable, duration float64) {
+	C.CommandBuffer_PresentDrawableAfterMinimumDuration(cb.commandBuffer, d.Drawable(), C.double(duration))
+}
+
 // Commit commits this command buffer for execution as soon as possible.
 //
 // Reference: https://developer.apple.com/documentation/metal/mtlcommandbuffer/1443003-commit.
@@ -674,25 +708,46 @@ func (cb CommandBuffer) WaitUntilScheduled() {
 	C.CommandBuffer_WaitUntilScheduled(cb.commandBuffer)
 }
 
+// GPUStartTime returns the host time in seconds when the GPU started executing the command buffer.
+// GPUStartTime returns 0 when the API is not available.
+//
+// Reference: https://developer.apple.com/documentation/metal/mtlcommandbuffer/gpustarttime.
+func (cb CommandBuffer) GPUStartTime() float64 {
+	return float64(C.CommandBuffer_GPUStartTime(cb.commandBuffer))
+}
+
+// GPUEndTime returns the host time in seconds when the GPU finished executing the command buffer.
+// GPUEndTime returns 0 when the API is not available.
+//
+// Reference: https://developer.apple.com/documentation/metal/mtlcommandbuffer/gpuendtime.
+func (cb CommandBuffer) GPUEndTime() float64 {
+	return float64(C.CommandBuffer_GPUEndTime(cb.commandBuffer))
+}
+
 // MakeRenderCommandEncoder creates an encoder object that can
 // encode graphics rendering commands into this command buffer.
 //
 // Reference: https://developer.apple.com/documentation/metal/mtlcommandbuffer/1442999-makerendercommandencoder.
 func (cb CommandBuffer) MakeRenderCommandEncoder(rpd RenderPassDescriptor) RenderCommandEncoder {
 	descriptor := C.struct_RenderPassDescriptor{
-		ColorAttachment0LoadAction:  C.uint8_t(rpd.ColorAttachments[0].LoadAction),
-		ColorAttachment0StoreAction: C.uint8_t(rpd.ColorAttachments[0].StoreAction),
-		ColorAttachment0ClearColor: C.struct_ClearColor{
-			Red:   C.double(rpd.ColorAttachments[0].ClearColor.Red),
-			Green: C.double(rpd.ColorAttachments[0].ClearColor.Green),
-			Blue:  C.double(rpd.ColorAttachments[0].ClearColor.Blue),
-			Alpha: C.double(rpd.ColorAttachments[0].ClearColor.Alpha),
-		},
-		ColorAttachment0Texture:      rpd.ColorAttachments[0].Texture.texture,
 		StencilAttachmentLoadAction:  C.uint8_t(rpd.StencilAttachment.LoadAction),
 		StencilAttachmentStoreAction: C.uint8_t(rpd.StencilAttachment.StoreAction),
 		StencilAttachmentTexture:     rpd.StencilAttachment.Texture.texture,
 	}
+	for i := range rpd.ColorAttachments {
+		c := &rpd.ColorAttachments[i]
+		descriptor.ColorAttachments[i] = C.struct_RenderPassColorAttachmentDescriptor{
+			LoadAction:  C.uint8_t(c.LoadAction),
+			StoreAction: C.uint8_t(c.StoreAction),
+			ClearColor: C.struct_ClearColor{
+				Red:   C.double(c.ClearColor.Red),
+				Green: C.double(c.ClearColor.Green),
+				Blue:  C.double(c.ClearColor.Blue),
+				Alpha: C.double(c.ClearColor.Alpha),
+			},
+			Texture: c.Texture.texture,
+		}
+	}
 	return RenderCommandEncoder{CommandEncoder{C.CommandBuffer_MakeRenderCommandEncoder(cb.commandBuffer, descriptor)}}
 }
 
@@ -801,6 +856,14 @@ func (rce RenderCommandEncoder) DrawIndexedPrimitives(typ PrimitiveType, indexCo
 	C.RenderCommandEncoder_DrawIndexedPrimitives(rce.commandEncoder, C.uint8_t(typ), C.uint_t(indexCount), C.uint8_t(indexType), indexBuffer.buffer, C.uint_t(indexBufferOffset))
 }
 
+// DrawIndexedPrimitivesInstanced encodes a command to render a number of instances of primitives using an index list
+// specified in a buffer.
+//
+// Reference: https://developer.apple.com/documentation/metal/mtlrendercommandencoder/1515699-drawindexedprimitives
+func (rce RenderCommandEncoder) DrawIndexedPrimitivesInstanced(typ PrimitiveType, indexCount int, indexType IndexType, indexBuffer Buffer, indexBufferOffset int, instanceCount int) {
+	C.RenderCommandEncoder_DrawIndexedPrimitivesInstanced(rce.commandEncoder, C.uint8_t(typ), C.uint_t(indexCount), C.uint8_t(indexType), indexBuffer.buffer, C.uint_t(indexBufferOffset), C.uint_t(instanceCount))
+}
+
 // BlitCommandEncoder is an encoder that specifies resource copy
 // and resource synchronization commands.
 //
@@ -825,6 +888,11 @@ func (bce BlitCommandEncoder) CopyFromTexture(sourceTexture Texture, sourceSlice
 	C.BlitCommandEncoder_CopyFromTexture(bce.commandEncoder, sourceTexture.texture, C.uint_t(sourceSlice), C.uint_t(sourceLevel), sourceOrigin.c(), sourceSize.c(), destinationTexture.texture, C.uint_t(destinationSlice), C.uint_t(destinationLevel), destinationOrigin.c())
 }
 
+// CopyFromTextureToBuffer encodes a command to copy image data from a texture slice into a buffer.
+func (bce BlitCommandEncoder) CopyFromTextureToBuffer(sourceTexture Texture, sourceSlice int, sourceLevel int, sourceOrigin Origin, sourceSize Size, destinationBuffer Buffer, destinationOffset int, destinationBytesPerRow int, destinationBytesPerImage int) {
+	C.BlitCommandEncoder_CopyFromTextureToBuffer(bce.commandEncoder, sourceTexture.texture, C.uint_t(sourceSlice), C.uint_t(sourceLevel), sourceOrigin.c(), sourceSize.c(), destinationBuffer.buffer, C.uint_t(destinationOffset), C.uint_t(destinationBytesPerRow), C.uint_t(destinationBytesPerImage))
+}
+
 // Library is a collection of compiled graphics or compute functions.
 //
 // Reference: https://developer.apple.com/documentation/metal/mtllibrary.
@@ -913,6 +981,10 @@ func (b Buffer) CopyToContents(data unsafe.Pointer, lengthInBytes uintptr) {
 	C.Buffer_CopyToContents(b.buffer, data, C.size_t(lengthInBytes))
 }
 
+func (b Buffer) CopyFromContents(data unsafe.Pointer, lengthInBytes uintptr) {
+	C.Buffer_CopyFromContents(b.buffer, data, C.size_t(lengthInBytes))
+}
+
 func (b Buffer) Retain() {
 	C.Buffer_Retain(b.buffer)
 }
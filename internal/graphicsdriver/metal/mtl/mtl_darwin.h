@@ -36,16 +36,22 @@ struct Library {
   const char *Error;
 };
 
+struct RenderPipelineColorAttachmentDescriptor {
+  uint16_t PixelFormat;
+  uint8_t BlendingEnabled;
+  uint8_t DestinationAlphaBlendFactor;
+  uint8_t DestinationRGBBlendFactor;
+  uint8_t SourceAlphaBlendFactor;
+  uint8_t SourceRGBBlendFactor;
+  uint8_t AlphaBlendOperation;
+  uint8_t RGBBlendOperation;
+  uint8_t WriteMask;
+};
+
 struct RenderPipelineDescriptor {
   void *VertexFunction;
   void *FragmentFunction;
-  uint16_t ColorAttachment0PixelFormat;
-  uint8_t ColorAttachment0BlendingEnabled;
-  uint8_t ColorAttachment0DestinationAlphaBlendFactor;
-  uint8_t ColorAttachment0DestinationRGBBlendFactor;
-  uint8_t ColorAttachment0SourceAlphaBlendFactor;
-  uint8_t ColorAttachment0SourceRGBBlendFactor;
-  uint8_t ColorAttachment0WriteMask;
+  struct RenderPipelineColorAttachmentDescriptor ColorAttachments[4];
   uint8_t StencilAttachmentPixelFormat;
 };
 
@@ -61,11 +67,15 @@ struct ClearColor {
   double Alpha;
 };
 
+struct RenderPassColorAttachmentDescriptor {
+  uint8_t LoadAction;
+  uint8_t StoreAction;
+  struct ClearColor ClearColor;
+  void *Texture;
+};
+
 struct RenderPassDescriptor {
-  uint8_t ColorAttachment0LoadAction;
-  uint8_t ColorAttachment0StoreAction;
-  struct ClearColor ColorAttachment0ClearColor;
-  void *ColorAttachment0Texture;
+  struct RenderPassColorAttachmentDescriptor ColorAttachments[4];
   uint8_t StencilAttachmentLoadAction;
   uint8_t StencilAttachmentStoreAction;
   void *StencilAttachmentTexture;
@@ -149,9 +159,14 @@ void CommandBuffer_Retain(void *commandBuffer);
 void CommandBuffer_Release(void *commandBuffer);
 uint8_t CommandBuffer_Status(void *commandBuffer);
 void CommandBuffer_PresentDrawable(void *commandBuffer, void *drawable);
+void CommandBuffer_PresentDrawableAfterMinimumDuration(void *commandBuffer,
+                                                       void *drawable,
+                                                       double duration);
 void CommandBuffer_Commit(void *commandBuffer);
 void CommandBuffer_WaitUntilCompleted(void *commandBuffer);
 void CommandBuffer_WaitUntilScheduled(void *commandBuffer);
+double CommandBuffer_GPUStartTime(void *commandBuffer);
+double CommandBuffer_GPUEndTime(void *commandBuffer);
 void *
 CommandBuffer_MakeRenderCommandEncoder(void *commandBuffer,
                                        struct RenderPassDescriptor descriptor);
@@ -188,6 +203,10 @@ void RenderCommandEncoder_DrawPrimitives(void *renderCommandEncoder,
 void RenderCommandEncoder_DrawIndexedPrimitives(
     void *renderCommandEncoder, uint8_t primitiveType, uint_t indexCount,
     uint8_t indexType, void *indexBuffer, uint_t indexBufferOffset);
+void RenderCommandEncoder_DrawIndexedPrimitivesInstanced(
+    void *renderCommandEncoder, uint8_t primitiveType, uint_t indexCount,
+    uint8_t indexType, void *indexBuffer, uint_t indexBufferOffset,
+    uint_t instanceCount);
 
 void BlitCommandEncoder_Synchronize(void *blitCommandEncoder, void *resource);
 void BlitCommandEncoder_SynchronizeTexture(void *blitCommandEncoder,
@@ -198,6 +217,11 @@ void BlitCommandEncoder_CopyFromTexture(
     uint_t sourceLevel, struct Origin sourceOrigin, struct Size sourceSize,
     void *destinationTexture, uint_t destinationSlice, uint_t destinationLevel,
     struct Origin destinationOrigin);
+void BlitCommandEncoder_CopyFromTextureToBuffer(
+    void *blitCommandEncoder, void *sourceTexture, uint_t sourceSlice,
+    uint_t sourceLevel, struct Origin sourceOrigin, struct Size sourceSize,
+    void *destinationBuffer, uint_t destinationOffset,
+    uint_t destinationBytesPerRow, uint_t destinationBytesPerImage);
 
 void *Library_MakeFunction(void *library, const char *name);
 
@@ -211,6 +235,7 @@ int Texture_Height(void *texture);
 
 size_t Buffer_Length(void *buffer);
 void Buffer_CopyToContents(void *buffer, void *data, size_t lengthInBytes);
+void Buffer_CopyFromContents(void *buffer, void *data, size_t lengthInBytes);
 void Buffer_Retain(void *buffer);
 void Buffer_Release(void *buffer);
 void Function_Release(void *function);
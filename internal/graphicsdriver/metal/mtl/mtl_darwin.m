@@ -70,20 +70,29 @@ Device_MakeRenderPipelineState(void *device,
       [[MTLRenderPipelineDescriptor alloc] init];
   renderPipelineDescriptor.vertexFunction = descriptor.VertexFunction;
   renderPipelineDescriptor.fragmentFunction = descriptor.FragmentFunction;
-  renderPipelineDescriptor.colorAttachments[0].pixelFormat =
-      descriptor.ColorAttachment0PixelFormat;
-  renderPipelineDescriptor.colorAttachments[0].blendingEnabled =
-      descriptor.ColorAttachment0BlendingEnabled;
-  renderPipelineDescriptor.colorAttachments[0].destinationAlphaBlendFactor =
-      descriptor.ColorAttachment0DestinationAlphaBlendFactor;
-  renderPipelineDescriptor.colorAttachments[0].destinationRGBBlendFactor =
-      descriptor.ColorAttachment0DestinationRGBBlendFactor;
-  renderPipelineDescriptor.colorAttachments[0].sourceAlphaBlendFactor =
-      descriptor.ColorAttachment0SourceAlphaBlendFactor;
-  renderPipelineDescriptor.colorAttachments[0].sourceRGBBlendFactor =
-      descriptor.ColorAttachment0SourceRGBBlendFactor;
-  renderPipelineDescriptor.colorAttachments[0].writeMask =
-      descriptor.ColorAttachment0WriteMask;
+  for (int i = 0; i < 4; i++) {
+    struct RenderPipelineColorAttachmentDescriptor c =
+        descriptor.ColorAttachments[i];
+    if (c.PixelFormat == MTLPixelFormatInvalid) {
+      continue;
+    }
+    renderPipelineDescriptor.colorAttachments[i].pixelFormat = c.PixelFormat;
+    renderPipelineDescriptor.colorAttachments[i].blendingEnabled =
+        c.BlendingEnabled;
+    renderPipelineDescriptor.colorAttachments[i].destinationAlphaBlendFactor =
+        c.DestinationAlphaBlendFactor;
+    renderPipelineDescriptor.colorAttachments[i].destinationRGBBlendFactor =
+        c.DestinationRGBBlendFactor;
+    renderPipelineDescriptor.colorAttachments[i].sourceAlphaBlendFactor =
+        c.SourceAlphaBlendFactor;
+    renderPipelineDescriptor.colorAttachments[i].sourceRGBBlendFactor =
+        c.SourceRGBBlendFactor;
+    renderPipelineDescriptor.colorAttachments[i].alphaBlendOperation =
+        c.AlphaBlendOperation;
+    renderPipelineDescriptor.colorAttachments[i].rgbBlendOperation =
+        c.RGBBlendOperation;
+    renderPipelineDescriptor.colorAttachments[i].writeMask = c.WriteMask;
+  }
   renderPipelineDescriptor.stencilAttachmentPixelFormat =
       descriptor.StencilAttachmentPixelFormat;
   NSError *error;
@@ -178,6 +187,21 @@ void CommandBuffer_PresentDrawable(void *commandBuffer, void *drawable) {
       presentDrawable:(id<MTLDrawable>)drawable];
 }
 
+void CommandBuffer_PresentDrawableAfterMinimumDuration(void *commandBuffer,
+                                                       void *drawable,
+                                                       double duration) {
+  // presentDrawable:afterMinimumDuration: is available on macOS 10.15.4 or
+  // later and iOS 10.3 or later.
+  if (![(id<MTLCommandBuffer>)commandBuffer
+          respondsToSelector:@selector(presentDrawable:afterMinimumDuration:)]) {
+    [(id<MTLCommandBuffer>)commandBuffer
+        presentDrawable:(id<MTLDrawable>)drawable];
+    return;
+  }
+  [(id<MTLCommandBuffer>)commandBuffer presentDrawable:(id<MTLDrawable>)drawable
+                                  afterMinimumDuration:duration];
+}
+
 void CommandBuffer_Commit(void *commandBuffer) {
   [(id<MTLCommandBuffer>)commandBuffer commit];
 }
@@ -190,22 +214,42 @@ void CommandBuffer_WaitUntilScheduled(void *commandBuffer) {
   [(id<MTLCommandBuffer>)commandBuffer waitUntilScheduled];
 }
 
+double CommandBuffer_GPUStartTime(void *commandBuffer) {
+  // GPUStartTime is available on macOS 10.15 or later and iOS 10.3 or later.
+  if (![(id<MTLCommandBuffer>)commandBuffer
+          respondsToSelector:@selector(GPUStartTime)]) {
+    return 0;
+  }
+  return [(id<MTLCommandBuffer>)commandBuffer GPUStartTime];
+}
+
+double CommandBuffer_GPUEndTime(void *commandBuffer) {
+  // GPUEndTime is available on macOS 10.15 or later and iOS 10.3 or later.
+  if (![(id<MTLCommandBuffer>)commandBuffer
+          respondsToSelector:@selector(GPUEndTime)]) {
+    return 0;
+  }
+  return [(id<MTLCommandBuffer>)commandBuffer GPUEndTime];
+}
+
 void *
 CommandBuffer_MakeRenderCommandEncoder(void *commandBuffer,
                                        struct RenderPassDescriptor descriptor) {
   MTLRenderPassDescriptor *renderPassDescriptor =
       [[MTLRenderPassDescriptor alloc] init];
-  renderPassDescriptor.colorAttachments[0].loadAction =
-      descriptor.ColorAttachment0LoadAction;
-  renderPassDescriptor.colorAttachments[0].storeAction =
-      descriptor.ColorAttachment0StoreAction;
-  renderPassDescriptor.colorAttachments[0].clearColor =
-      MTLClearColorMake(descriptor.ColorAttachment0ClearColor.Red,
-                        descriptor.ColorAttachment0ClearColor.Green,
-                        descriptor.ColorAttachment0ClearColor.Blue,
-                        descriptor.ColorAttachment0ClearColor.Alpha);
-  renderPassDescriptor.colorAttachments[0].texture =
-      (id<MTLTexture>)descriptor.ColorAttachment0Texture;
+  for (int i = 0; i < 4; i++) {
+    struct RenderPassColorAttachmentDescriptor c =
+        descriptor.ColorAttachments[i];
+    if (!c.Texture) {
+      continue;
+    }
+    renderPassDescriptor.colorAttachments[i].loadAction = c.LoadAction;
+    renderPassDescriptor.colorAttachments[i].storeAction = c.StoreAction;
+    renderPassDescriptor.colorAttachments[i].clearColor =
+        MTLClearColorMake(c.ClearColor.Red, c.ClearColor.Green,
+                          c.ClearColor.Blue, c.ClearColor.Alpha);
+    renderPassDescriptor.colorAttachments[i].texture = (id<MTLTexture>)c.Texture;
+  }
   renderPassDescriptor.stencilAttachment.loadAction =
       descriptor.StencilAttachmentLoadAction;
   renderPassDescriptor.stencilAttachment.storeAction =
@@ -329,6 +373,19 @@ void RenderCommandEncoder_DrawIndexedPrimitives(
           indexBufferOffset:(NSUInteger)indexBufferOffset];
 }
 
+void RenderCommandEncoder_DrawIndexedPrimitivesInstanced(
+    void *renderCommandEncoder, uint8_t primitiveType, uint_t indexCount,
+    uint8_t indexType, void *indexBuffer, uint_t indexBufferOffset,
+    uint_t instanceCount) {
+  [(id<MTLRenderCommandEncoder>)renderCommandEncoder
+      drawIndexedPrimitives:(MTLPrimitiveType)primitiveType
+                 indexCount:(NSUInteger)indexCount
+                  indexType:(MTLIndexType)indexType
+                indexBuffer:(id<MTLBuffer>)indexBuffer
+          indexBufferOffset:(NSUInteger)indexBufferOffset
+              instanceCount:(NSUInteger)instanceCount];
+}
+
 void BlitCommandEncoder_Synchronize(void *blitCommandEncoder, void *resource) {
 #if !TARGET_OS_IPHONE
   [(id<MTLBlitCommandEncoder>)blitCommandEncoder
@@ -370,6 +427,27 @@ void BlitCommandEncoder_CopyFromTexture(
                                     .z = destinationOrigin.Z}];
 }
 
+void BlitCommandEncoder_CopyFromTextureToBuffer(
+    void *blitCommandEncoder, void *sourceTexture, uint_t sourceSlice,
+    uint_t sourceLevel, struct Origin sourceOrigin, struct Size sourceSize,
+    void *destinationBuffer, uint_t destinationOffset,
+    uint_t destinationBytesPerRow, uint_t destinationBytesPerImage) {
+  [(id<MTLBlitCommandEncoder>)blitCommandEncoder
+               copyFromTexture:(id<MTLTexture>)sourceTexture
+                   sourceSlice:(NSUInteger)sourceSlice
+                   sourceLevel:(NSUInteger)sourceLevel
+                  sourceOrigin:(MTLOrigin){.x = sourceOrigin.X,
+                                           .y = sourceOrigin.Y,
+                                           .z = sourceOrigin.Z}
+                    sourceSize:(MTLSize){.width = sourceSize.Width,
+                                         .height = sourceSize.Height,
+                                         .depth = sourceSize.Depth}
+                      toBuffer:(id<MTLBuffer>)destinationBuffer
+             destinationOffset:(NSUInteger)destinationOffset
+        destinationBytesPerRow:(NSUInteger)destinationBytesPerRow
+      destinationBytesPerImage:(NSUInteger)destinationBytesPerImage];
+}
+
 void *Library_MakeFunction(void *library, const char *name) {
   return [(id<MTLLibrary>)library
       newFunctionWithName:[NSString stringWithUTF8String:name]];
@@ -424,6 +502,10 @@ void Buffer_CopyToContents(void *buffer, void *data, size_t lengthInBytes) {
 #endif
 }
 
+void Buffer_CopyFromContents(void *buffer, void *data, size_t lengthInBytes) {
+  memcpy(data, ((id<MTLBuffer>)buffer).contents, lengthInBytes);
+}
+
 void Buffer_Retain(void *buffer) { [(id<MTLBuffer>)buffer retain]; }
 
 void Buffer_Release(void *buffer) { [(id<MTLBuffer>)buffer release]; }
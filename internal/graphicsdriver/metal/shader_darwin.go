@@ -17,6 +17,7 @@ package metal
 import (
 	"fmt"
 
+	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/metal/mtl"
 	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
@@ -24,8 +25,12 @@ import (
 )
 
 type shaderRpsKey struct {
-	compositeMode graphicsdriver.CompositeMode
-	stencilMode   stencilMode
+	blend       graphicsdriver.Blend
+	stencilMode stencilMode
+
+	// colorAttachmentFormats is the pixel formats of the color attachments.
+	// PixelFormatInvalid means that the attachment is not used.
+	colorAttachmentFormats [graphics.ShaderDstImageNum]mtl.PixelFormat
 }
 
 type Shader struct {
@@ -49,6 +54,45 @@ func newShader(device mtl.Device, id graphicsdriver.ShaderID, program *shaderir.
 	return s, nil
 }
 
+// pendingShader represents a shader being compiled in a background goroutine.
+type pendingShader struct {
+	graphics *Graphics
+	shader   *Shader
+	done     chan struct{}
+	err      error
+}
+
+func newPendingShader(graphics *Graphics, device mtl.Device, id graphicsdriver.ShaderID, program *shaderir.Program) *pendingShader {
+	p := &pendingShader{
+		graphics: graphics,
+		shader: &Shader{
+			id:   id,
+			ir:   program,
+			rpss: map[shaderRpsKey]mtl.RenderPipelineState{},
+		},
+		done: make(chan struct{}),
+	}
+	// MTLDevice is thread-safe, and creating libraries and functions can be done on any thread.
+	go func() {
+		defer close(p.done)
+		p.err = p.shader.init(device)
+	}()
+	return p
+}
+
+func (p *pendingShader) TryShader() (graphicsdriver.Shader, bool, error) {
+	select {
+	case <-p.done:
+	default:
+		return nil, false, nil
+	}
+	if p.err != nil {
+		return nil, true, p.err
+	}
+	p.graphics.addShader(p.shader)
+	return p.shader, true, nil
+}
+
 func (s *Shader) ID() graphicsdriver.ShaderID {
 	return s.id
 }
@@ -85,11 +129,13 @@ func (s *Shader) init(device mtl.Device) error {
 	return nil
 }
 
-func (s *Shader) RenderPipelineState(device mtl.Device, compositeMode graphicsdriver.CompositeMode, stencilMode stencilMode) (mtl.RenderPipelineState, error) {
-	if rps, ok := s.rpss[shaderRpsKey{
-		compositeMode: compositeMode,
-		stencilMode:   stencilMode,
-	}]; ok {
+func (s *Shader) RenderPipelineState(device mtl.Device, blend graphicsdriver.Blend, stencilMode stencilMode, colorAttachmentFormats [graphics.ShaderDstImageNum]mtl.PixelFormat) (mtl.RenderPipelineState, error) {
+	key := shaderRpsKey{
+		blend:                  blend,
+		stencilMode:            stencilMode,
+		colorAttachmentFormats: colorAttachmentFormats,
+	}
+	if rps, ok := s.rpss[key]; ok {
 		return rps, nil
 	}
 
@@ -102,18 +148,17 @@ func (s *Shader) RenderPipelineState(device mtl.Device, compositeMode graphicsdr
 	}
 
 	// TODO: For the precise pixel format, whether the render target is the screen or not must be considered.
-	rpld.ColorAttachments[0].PixelFormat = mtl.PixelFormatRGBA8UNorm
-	rpld.ColorAttachments[0].BlendingEnabled = true
-
-	src, dst := compositeMode.Operations()
-	rpld.ColorAttachments[0].DestinationAlphaBlendFactor = operationToBlendFactor(dst)
-	rpld.ColorAttachments[0].DestinationRGBBlendFactor = operationToBlendFactor(dst)
-	rpld.ColorAttachments[0].SourceAlphaBlendFactor = operationToBlendFactor(src)
-	rpld.ColorAttachments[0].SourceRGBBlendFactor = operationToBlendFactor(src)
-	if stencilMode == prepareStencil {
-		rpld.ColorAttachments[0].WriteMask = mtl.ColorWriteMaskNone
-	} else {
-		rpld.ColorAttachments[0].WriteMask = mtl.ColorWriteMaskAll
+	for i, f := range colorAttachmentFormats {
+		if f == mtl.PixelFormatInvalid {
+			continue
+		}
+		rpld.ColorAttachments[i].PixelFormat = f
+		setBlend(&rpld.ColorAttachments[i], blend)
+		if stencilMode == prepareStencil {
+			rpld.ColorAttachments[i].WriteMask = mtl.ColorWriteMaskNone
+		} else {
+			rpld.ColorAttachments[i].WriteMask = mtl.ColorWriteMaskAll
+		}
 	}
 
 	rps, err := device.MakeRenderPipelineState(rpld)
@@ -121,9 +166,33 @@ func (s *Shader) RenderPipelineState(device mtl.Device, compositeMode graphicsdr
 		return mtl.RenderPipelineState{}, err
 	}
 
-	s.rpss[shaderRpsKey{
-		compositeMode: compositeMode,
-		stencilMode:   stencilMode,
-	}] = rps
+	s.rpss[key] = rps
 	return rps, nil
 }
+
+// adjustUniformForMetal returns the uniform value u of the type t in the memory layout of Metal.
+//
+// In Metal, a float3 occupies 16 bytes even in an array, and a float3x3 consists of three float3 columns.
+// A vec3 and a mat3 value, or an array of them, are given as a tightly packed slice, so a padding is inserted
+// after every three values.
+func adjustUniformForMetal(u graphicsdriver.Uniform, t *shaderir.Type) graphicsdriver.Uniform {
+	if len(u.Float32s) == 0 {
+		return u
+	}
+
+	base := t.Main
+	if base == shaderir.Array {
+		base = t.Sub[0].Main
+	}
+	if base != shaderir.Vec3 && base != shaderir.Mat3 {
+		return u
+	}
+
+	vs := make([]float32, 0, len(u.Float32s)/3*4)
+	for i := 0; i+3 <= len(u.Float32s); i += 3 {
+		vs = append(vs, u.Float32s[i], u.Float32s[i+1], u.Float32s[i+2], 0)
+	}
+	return graphicsdriver.Uniform{
+		Float32s: vs,
+	}
+}
@@ -16,31 +16,57 @@ package opengl
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 )
 
-type operation int
+type blendFactor int
 
-func convertOperation(op graphicsdriver.Operation) operation {
-	switch op {
-	case graphicsdriver.Zero:
+type blendOperation int
+
+func convertBlendFactor(f graphicsdriver.BlendFactor) blendFactor {
+	switch f {
+	case graphicsdriver.BlendFactorZero:
 		return zero
-	case graphicsdriver.One:
+	case graphicsdriver.BlendFactorOne:
 		return one
-	case graphicsdriver.SrcAlpha:
+	case graphicsdriver.BlendFactorSourceColor:
+		return srcColor
+	case graphicsdriver.BlendFactorOneMinusSourceColor:
+		return oneMinusSrcColor
+	case graphicsdriver.BlendFactorSourceAlpha:
 		return srcAlpha
-	case graphicsdriver.DstAlpha:
-		return dstAlpha
-	case graphicsdriver.OneMinusSrcAlpha:
+	case graphicsdriver.BlendFactorOneMinusSourceAlpha:
 		return oneMinusSrcAlpha
-	case graphicsdriver.OneMinusDstAlpha:
-		return oneMinusDstAlpha
-	case graphicsdriver.DstColor:
+	case graphicsdriver.BlendFactorDestinationColor:
 		return dstColor
+	case graphicsdriver.BlendFactorOneMinusDestinationColor:
+		return oneMinusDstColor
+	case graphicsdriver.BlendFactorDestinationAlpha:
+		return dstAlpha
+	case graphicsdriver.BlendFactorOneMinusDestinationAlpha:
+		return oneMinusDstAlpha
 	default:
-		panic(fmt.Sprintf("opengl: invalid operation %d at convertOperation", op))
+		panic(fmt.Sprintf("opengl: invalid blend factor %d at convertBlendFactor", f))
+	}
+}
+
+func convertBlendOperation(o graphicsdriver.BlendOperation) blendOperation {
+	switch o {
+	case graphicsdriver.BlendOperationAdd:
+		return funcAdd
+	case graphicsdriver.BlendOperationSubtract:
+		return funcSubtract
+	case graphicsdriver.BlendOperationReverseSubtract:
+		return funcReverseSubtract
+	case graphicsdriver.BlendOperationMin:
+		return funcMin
+	case graphicsdriver.BlendOperationMax:
+		return funcMax
+	default:
+		panic(fmt.Sprintf("opengl: invalid blend operation %d at convertBlendOperation", o))
 	}
 }
 
@@ -52,9 +78,25 @@ type context struct {
 	lastRenderbuffer   renderbufferNative
 	lastViewportWidth  int
 	lastViewportHeight int
-	lastCompositeMode  graphicsdriver.CompositeMode
+	lastBlend          graphicsdriver.Blend
 	maxTextureSize     int
 	maxTextureSizeOnce sync.Once
+	maxSampleCount     int
+	maxSampleCountOnce sync.Once
+	maxDrawBuffers     int
+	maxDrawBuffersOnce sync.Once
+	maxDebugGroups     int
+	maxDebugGroupsOnce sync.Once
+	timerQuery         bool
+	timerQueryOnce     sync.Once
+	fence              bool
+	fenceOnce          sync.Once
+	instancing         bool
+	instancingOnce     sync.Once
+	textureRG          bool
+	textureRGOnce      sync.Once
+	parallelShader     bool
+	parallelShaderOnce sync.Once
 	highp              bool
 	highpOnce          sync.Once
 
@@ -117,6 +159,79 @@ func (c *context) getMaxTextureSize() int {
 	return c.maxTextureSize
 }
 
+func (c *context) getMaxSampleCount() int {
+	c.maxSampleCountOnce.Do(func() {
+		c.maxSampleCount = c.maxSampleCountImpl()
+	})
+	return c.maxSampleCount
+}
+
+// getMaxDrawBuffers returns the maximum number of color attachments that can be drawn at the same time.
+func (c *context) getMaxDrawBuffers() int {
+	c.maxDrawBuffersOnce.Do(func() {
+		c.maxDrawBuffers = c.maxDrawBuffersImpl()
+	})
+	return c.maxDrawBuffers
+}
+
+// getMaxDebugGroups returns the maximum depth of the debug group stack.
+func (c *context) getMaxDebugGroups() int {
+	c.maxDebugGroupsOnce.Do(func() {
+		c.maxDebugGroups = c.maxDebugGroupsImpl()
+	})
+	return c.maxDebugGroups
+}
+
+// isTimerQueryAvailable reports whether the time spent by GPU can be measured.
+func (c *context) isTimerQueryAvailable() bool {
+	c.timerQueryOnce.Do(func() {
+		c.timerQuery = c.isTimerQueryAvailableImpl()
+	})
+	return c.timerQuery
+}
+
+// isFenceAvailable reports whether fences can be used to check the progress of GPU.
+func (c *context) isFenceAvailable() bool {
+	c.fenceOnce.Do(func() {
+		c.fence = c.isFenceAvailableImpl()
+	})
+	return c.fence
+}
+
+// isInstancingAvailable reports whether instanced drawing is available.
+func (c *context) isInstancingAvailable() bool {
+	c.instancingOnce.Do(func() {
+		c.instancing = c.isInstancingAvailableImpl()
+	})
+	return c.instancing
+}
+
+// isTextureRGAvailable reports whether textures with one or two channels (R8 and RG8) are available.
+func (c *context) isTextureRGAvailable() bool {
+	c.textureRGOnce.Do(func() {
+		c.textureRG = c.isTextureRGAvailableImpl()
+	})
+	return c.textureRG
+}
+
+// isParallelShaderCompileAvailable reports whether the completion of compiling shaders can be queried without
+// blocking (KHR_parallel_shader_compile).
+func (c *context) isParallelShaderCompileAvailable() bool {
+	c.parallelShaderOnce.Do(func() {
+		c.parallelShader = c.isParallelShaderCompileAvailableImpl()
+	})
+	return c.parallelShader
+}
+
+// texturePixelFormat returns the pixel format used for a texture of the given format.
+// If the format is not available, PixelFormatRGBA8 is used instead.
+func (c *context) texturePixelFormat(format graphicsdriver.PixelFormat) graphicsdriver.PixelFormat {
+	if format != graphicsdriver.PixelFormatRGBA8 && !c.isTextureRGAvailable() {
+		return graphicsdriver.PixelFormatRGBA8
+	}
+	return format
+}
+
 // highpPrecision represents an enough mantissa of float values in a shader.
 const highpPrecision = 23
 
@@ -126,3 +241,14 @@ func (c *context) hasHighPrecisionFloat() bool {
 	})
 	return c.highp
 }
+
+// parseGLVersion returns the major and minor version numbers from the string of GL_VERSION.
+// The string starts with "OpenGL ES " for OpenGL ES, and with the version numbers for OpenGL.
+// parseGLVersion returns (0, 0) if the string cannot be parsed.
+func parseGLVersion(str string) (major, minor int) {
+	str = strings.TrimPrefix(str, "OpenGL ES ")
+	if _, err := fmt.Sscanf(str, "%d.%d", &major, &minor); err != nil {
+		return 0, 0
+	}
+	return major, minor
+}
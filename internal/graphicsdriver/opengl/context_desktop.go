@@ -20,6 +20,7 @@ package opengl
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/opengl/gl"
@@ -33,6 +34,8 @@ type (
 	shader             uint32
 	program            uint32
 	buffer             uint32
+	queryNative        uint32
+	fenceNative        uintptr
 )
 
 func (t textureNative) equal(rhs textureNative) bool {
@@ -83,13 +86,21 @@ func getProgramID(p program) programID {
 }
 
 const (
-	zero             = operation(gl.ZERO)
-	one              = operation(gl.ONE)
-	srcAlpha         = operation(gl.SRC_ALPHA)
-	dstAlpha         = operation(gl.DST_ALPHA)
-	oneMinusSrcAlpha = operation(gl.ONE_MINUS_SRC_ALPHA)
-	oneMinusDstAlpha = operation(gl.ONE_MINUS_DST_ALPHA)
-	dstColor         = operation(gl.DST_COLOR)
+	zero                = blendFactor(gl.ZERO)
+	one                 = blendFactor(gl.ONE)
+	srcColor            = blendFactor(gl.SRC_COLOR)
+	oneMinusSrcColor    = blendFactor(gl.ONE_MINUS_SRC_COLOR)
+	srcAlpha            = blendFactor(gl.SRC_ALPHA)
+	oneMinusSrcAlpha    = blendFactor(gl.ONE_MINUS_SRC_ALPHA)
+	dstColor            = blendFactor(gl.DST_COLOR)
+	oneMinusDstColor    = blendFactor(gl.ONE_MINUS_DST_COLOR)
+	dstAlpha            = blendFactor(gl.DST_ALPHA)
+	oneMinusDstAlpha    = blendFactor(gl.ONE_MINUS_DST_ALPHA)
+	funcAdd             = blendOperation(gl.FUNC_ADD)
+	funcSubtract        = blendOperation(gl.FUNC_SUBTRACT)
+	funcReverseSubtract = blendOperation(gl.FUNC_REVERSE_SUBTRACT)
+	funcMin             = blendOperation(gl.MIN)
+	funcMax             = blendOperation(gl.MAX)
 )
 
 type contextImpl struct {
@@ -110,11 +121,11 @@ func (c *context) reset() error {
 	c.lastFramebuffer = invalidFramebuffer
 	c.lastViewportWidth = 0
 	c.lastViewportHeight = 0
-	c.lastCompositeMode = graphicsdriver.CompositeModeUnknown
+	c.lastBlend = graphicsdriver.Blend{}
 	gl.Enable(gl.BLEND)
 	gl.Enable(gl.SCISSOR_TEST)
 
-	c.blendFunc(graphicsdriver.CompositeModeSourceOver)
+	c.blend(graphicsdriver.BlendSourceOver)
 
 	f := int32(0)
 	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &f)
@@ -122,21 +133,40 @@ func (c *context) reset() error {
 	return nil
 }
 
-func (c *context) blendFunc(mode graphicsdriver.CompositeMode) {
-	if c.lastCompositeMode == mode {
+func (c *context) blend(blend graphicsdriver.Blend) {
+	if c.lastBlend == blend {
 		return
 	}
-	c.lastCompositeMode = mode
-	s, d := mode.Operations()
-	s2, d2 := convertOperation(s), convertOperation(d)
-	gl.BlendFunc(uint32(s2), uint32(d2))
+	c.lastBlend = blend
+	sRGB := convertBlendFactor(blend.BlendFactorSourceRGB)
+	sA := convertBlendFactor(blend.BlendFactorSourceAlpha)
+	dRGB := convertBlendFactor(blend.BlendFactorDestinationRGB)
+	dA := convertBlendFactor(blend.BlendFactorDestinationAlpha)
+	opRGB := convertBlendOperation(blend.BlendOperationRGB)
+	opA := convertBlendOperation(blend.BlendOperationAlpha)
+	gl.BlendFuncSeparate(uint32(sRGB), uint32(dRGB), uint32(sA), uint32(dA))
+	gl.BlendEquationSeparate(uint32(opRGB), uint32(opA))
 }
 
 func (c *context) scissor(x, y, width, height int) {
 	gl.Scissor(int32(x), int32(y), int32(width), int32(height))
 }
 
-func (c *context) newTexture(width, height int) (textureNative, error) {
+// textureFormats returns the internal format and the format of a texture for the given pixel format.
+func textureFormats(format graphicsdriver.PixelFormat) (internalFormat int32, pixelFormat uint32) {
+	switch format {
+	case graphicsdriver.PixelFormatRGBA8:
+		return gl.RGBA, gl.RGBA
+	case graphicsdriver.PixelFormatR8:
+		return gl.R8, gl.RED
+	case graphicsdriver.PixelFormatRG8:
+		return gl.RG8, gl.RG
+	default:
+		panic(fmt.Sprintf("opengl: invalid pixel format: %d", format))
+	}
+}
+
+func (c *context) newTexture(width, height int, format graphicsdriver.PixelFormat) (textureNative, error) {
 	var t uint32
 	gl.GenTextures(1, &t)
 	// TODO: Use gl.IsTexture
@@ -154,7 +184,8 @@ func (c *context) newTexture(width, height int) (textureNative, error) {
 	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
 	// If data is nil, this just allocates memory and the content is undefined.
 	// https://www.khronos.org/registry/OpenGL-Refpages/gl4/html/glTexImage2D.xhtml
-	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(width), int32(height), 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	internalFormat, pixelFormat := textureFormats(format)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, internalFormat, int32(width), int32(height), 0, pixelFormat, gl.UNSIGNED_BYTE, nil)
 	return texture, nil
 }
 
@@ -170,12 +201,33 @@ func (c *context) framebufferPixels(f *framebuffer, width, height int) []byte {
 	return pixels
 }
 
-func (c *context) framebufferPixelsToBuffer(f *framebuffer, buffer buffer, width, height int) {
+func (c *context) framebufferPixelsToBuffer(f *framebuffer, buffer buffer, x, y, width, height int) {
 	gl.Flush()
 	c.bindFramebuffer(f.native)
 	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, uint32(buffer))
-	gl.ReadPixels(0, 0, int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.ReadPixels(int32(x), int32(y), int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
+}
+
+func (c *context) canReadPixelsAsync() bool {
+	return true
+}
+
+func (c *context) newPixelPackBuffer(size int) buffer {
+	var b uint32
+	gl.GenBuffers(1, &b)
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, b)
+	gl.BufferData(gl.PIXEL_PACK_BUFFER, size, nil, gl.STREAM_READ)
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
+	return buffer(b)
+}
+
+func (c *context) pixelPackBufferData(b buffer, size int) []byte {
+	pixels := make([]byte, size)
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, uint32(b))
+	gl.GetBufferSubData(gl.PIXEL_PACK_BUFFER, 0, size, gl.Ptr(pixels))
 	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
+	return pixels
 }
 
 func (c *context) activeTexture(idx int) {
@@ -255,6 +307,26 @@ func (c *context) newFramebuffer(texture textureNative) (framebufferNative, erro
 	return framebufferNative(f), nil
 }
 
+// newFramebufferFromTextures creates a framebuffer that has the given textures as its color attachments.
+func (c *context) newFramebufferFromTextures(textures []textureNative) (framebufferNative, error) {
+	var f uint32
+	gl.GenFramebuffersEXT(1, &f)
+	if f <= 0 {
+		return 0, errors.New("opengl: creating framebuffer failed: gl.IsFramebuffer returns false")
+	}
+	c.bindFramebuffer(framebufferNative(f))
+	bufs := make([]uint32, len(textures))
+	for i, t := range textures {
+		bufs[i] = gl.COLOR_ATTACHMENT0 + uint32(i)
+		gl.FramebufferTexture2DEXT(gl.FRAMEBUFFER, bufs[i], gl.TEXTURE_2D, uint32(t), 0)
+	}
+	gl.DrawBuffers(int32(len(bufs)), &bufs[0])
+	if s := gl.CheckFramebufferStatusEXT(gl.FRAMEBUFFER); s != gl.FRAMEBUFFER_COMPLETE {
+		return 0, fmt.Errorf("opengl: creating framebuffer failed: %v", s)
+	}
+	return framebufferNative(f), nil
+}
+
 func (c *context) bindStencilBuffer(f framebufferNative, r renderbufferNative) error {
 	c.bindFramebuffer(f)
 
@@ -265,6 +337,53 @@ func (c *context) bindStencilBuffer(f framebufferNative, r renderbufferNative) e
 	return nil
 }
 
+func (c *context) newMultisampledRenderbuffer(width, height, sampleCount int, internalFormat uint32) (renderbufferNative, error) {
+	var r uint32
+	gl.GenRenderbuffersEXT(1, &r)
+	if r <= 0 {
+		return 0, errors.New("opengl: creating multisampled renderbuffer failed")
+	}
+
+	renderbuffer := renderbufferNative(r)
+	c.bindRenderbuffer(renderbuffer)
+	gl.RenderbufferStorageMultisampleEXT(gl.RENDERBUFFER, int32(sampleCount), internalFormat, int32(width), int32(height))
+	return renderbuffer, nil
+}
+
+func (c *context) newMultisampledColorRenderbuffer(width, height, sampleCount int) (renderbufferNative, error) {
+	return c.newMultisampledRenderbuffer(width, height, sampleCount, gl.RGBA8)
+}
+
+func (c *context) newMultisampledStencilRenderbuffer(width, height, sampleCount int) (renderbufferNative, error) {
+	return c.newMultisampledRenderbuffer(width, height, sampleCount, gl.DEPTH24_STENCIL8)
+}
+
+func (c *context) newFramebufferFromRenderbuffer(renderbuffer renderbufferNative) (framebufferNative, error) {
+	var f uint32
+	gl.GenFramebuffersEXT(1, &f)
+	if f <= 0 {
+		return 0, errors.New("opengl: creating framebuffer failed: gl.IsFramebuffer returns false")
+	}
+	c.bindFramebuffer(framebufferNative(f))
+	gl.FramebufferRenderbufferEXT(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.RENDERBUFFER, uint32(renderbuffer))
+	if s := gl.CheckFramebufferStatusEXT(gl.FRAMEBUFFER); s != gl.FRAMEBUFFER_COMPLETE {
+		return 0, fmt.Errorf("opengl: creating framebuffer failed: %v", s)
+	}
+	return framebufferNative(f), nil
+}
+
+// blitFramebuffer copies the pixels in the region (0, 0)-(width, height) from src to dst.
+// Multisampled pixels are resolved if src is multisampled and dst is not, and vice versa.
+func (c *context) blitFramebuffer(src, dst framebufferNative, width, height int) {
+	gl.BindFramebufferEXT(gl.READ_FRAMEBUFFER, uint32(src))
+	gl.BindFramebufferEXT(gl.DRAW_FRAMEBUFFER, uint32(dst))
+	// glBlitFramebuffer is affected by the scissor test.
+	gl.Disable(gl.SCISSOR_TEST)
+	gl.BlitFramebufferEXT(0, 0, int32(width), int32(height), 0, 0, int32(width), int32(height), gl.COLOR_BUFFER_BIT, gl.NEAREST)
+	gl.Enable(gl.SCISSOR_TEST)
+	c.lastFramebuffer = invalidFramebuffer
+}
+
 func (c *context) setViewportImpl(width, height int) {
 	gl.Viewport(0, 0, int32(width), int32(height))
 }
@@ -291,6 +410,26 @@ func (c *context) newFragmentShader(source string) (shader, error) {
 }
 
 func (c *context) newShader(shaderType uint32, source string) (shader, error) {
+	s, err := c.compileShader(shaderType, source)
+	if err != nil {
+		return 0, err
+	}
+	if err := c.checkShader(s); err != nil {
+		return 0, err
+	}
+	return s, nil
+}
+
+func (c *context) compileVertexShader(source string) (shader, error) {
+	return c.compileShader(gl.VERTEX_SHADER, source)
+}
+
+func (c *context) compileFragmentShader(source string) (shader, error) {
+	return c.compileShader(gl.FRAGMENT_SHADER, source)
+}
+
+// compileShader starts compiling a shader without checking the result.
+func (c *context) compileShader(shaderType uint32, source string) (shader, error) {
 	s := gl.CreateShader(shaderType)
 	if s == 0 {
 		return 0, fmt.Errorf("opengl: glCreateShader failed: shader type: %d", shaderType)
@@ -299,20 +438,25 @@ func (c *context) newShader(shaderType uint32, source string) (shader, error) {
 	gl.ShaderSource(uint32(s), 1, cSources, nil)
 	free()
 	gl.CompileShader(s)
+	return shader(s), nil
+}
 
+// checkShader returns an error if compiling the shader failed.
+// checkShader waits for the compilation to finish.
+func (c *context) checkShader(s shader) error {
 	var v int32
-	gl.GetShaderiv(s, gl.COMPILE_STATUS, &v)
+	gl.GetShaderiv(uint32(s), gl.COMPILE_STATUS, &v)
 	if v == gl.FALSE {
 		var l int32
 		var log []byte
 		gl.GetShaderiv(uint32(s), gl.INFO_LOG_LENGTH, &l)
 		if l != 0 {
 			log = make([]byte, l)
-			gl.GetShaderInfoLog(s, l, nil, (*uint8)(gl.Ptr(log)))
+			gl.GetShaderInfoLog(uint32(s), l, nil, (*uint8)(gl.Ptr(log)))
 		}
-		return 0, fmt.Errorf("opengl: shader compile failed: %s", log)
+		return fmt.Errorf("opengl: shader compile failed: %s", log)
 	}
-	return shader(s), nil
+	return nil
 }
 
 func (c *context) deleteShader(s shader) {
@@ -320,6 +464,18 @@ func (c *context) deleteShader(s shader) {
 }
 
 func (c *context) newProgram(shaders []shader, attributes []string) (program, error) {
+	p, err := c.linkProgram(shaders, attributes)
+	if err != nil {
+		return 0, err
+	}
+	if err := c.checkProgram(p); err != nil {
+		return 0, err
+	}
+	return p, nil
+}
+
+// linkProgram starts linking a program without checking the result.
+func (c *context) linkProgram(shaders []shader, attributes []string) (program, error) {
 	p := gl.CreateProgram()
 	if p == 0 {
 		return 0, errors.New("opengl: glCreateProgram failed")
@@ -336,19 +492,37 @@ func (c *context) newProgram(shaders []shader, attributes []string) (program, er
 	}
 
 	gl.LinkProgram(p)
+	return program(p), nil
+}
+
+// checkProgram returns an error if linking the program failed.
+// checkProgram waits for the linking to finish.
+func (c *context) checkProgram(p program) error {
 	var v int32
-	gl.GetProgramiv(p, gl.LINK_STATUS, &v)
+	gl.GetProgramiv(uint32(p), gl.LINK_STATUS, &v)
 	if v == gl.FALSE {
 		var l int32
 		var log []byte
-		gl.GetProgramiv(p, gl.INFO_LOG_LENGTH, &l)
+		gl.GetProgramiv(uint32(p), gl.INFO_LOG_LENGTH, &l)
 		if l != 0 {
 			log = make([]byte, l)
-			gl.GetProgramInfoLog(p, l, nil, (*uint8)(gl.Ptr(log)))
+			gl.GetProgramInfoLog(uint32(p), l, nil, (*uint8)(gl.Ptr(log)))
 		}
-		return 0, fmt.Errorf("opengl: program error: %s", log)
+		return fmt.Errorf("opengl: program error: %s", log)
 	}
-	return program(p), nil
+	return nil
+}
+
+// isProgramCompleted reports whether compiling and linking the program finished.
+// isProgramCompleted doesn't wait for the completion.
+func (c *context) isProgramCompleted(p program) bool {
+	if !c.isParallelShaderCompileAvailable() {
+		// Without the extension, the completion cannot be queried without blocking.
+		return true
+	}
+	var v int32
+	gl.GetProgramiv(uint32(p), gl.COMPLETION_STATUS, &v)
+	return v != gl.FALSE
 }
 
 func (c *context) useProgram(p program) {
@@ -476,12 +650,185 @@ func (c *context) drawElements(len int, offsetInBytes int) {
 	gl.DrawElements(gl.TRIANGLES, int32(len), gl.UNSIGNED_SHORT, uintptr(offsetInBytes))
 }
 
+func (c *context) drawElementsInstanced(len int, offsetInBytes int, instanceCount int) {
+	gl.DrawElementsInstanced(gl.TRIANGLES, int32(len), gl.UNSIGNED_SHORT, uintptr(offsetInBytes), int32(instanceCount))
+}
+
+func (c *context) vertexAttribDivisor(index int, divisor int) {
+	gl.VertexAttribDivisor(uint32(index), uint32(divisor))
+}
+
 func (c *context) maxTextureSizeImpl() int {
 	s := int32(0)
 	gl.GetIntegerv(gl.MAX_TEXTURE_SIZE, &s)
 	return int(s)
 }
 
+func (c *context) maxSampleCountImpl() int {
+	// GL_MAX_SAMPLES is available only with GL_EXT_framebuffer_multisample.
+	// Without the extension, glGetIntegerv causes an error and doesn't update the value.
+	s := int32(0)
+	gl.GetIntegerv(gl.MAX_SAMPLES, &s)
+	if e := gl.GetError(); e != gl.NO_ERROR || s < 1 {
+		return 1
+	}
+	return int(s)
+}
+
+func (c *context) maxDrawBuffersImpl() int {
+	// glDrawBuffers is available with OpenGL 2.0 or later.
+	n := int32(0)
+	gl.GetIntegerv(gl.MAX_DRAW_BUFFERS, &n)
+	if e := gl.GetError(); e != gl.NO_ERROR || n < 1 {
+		return 1
+	}
+	return int(n)
+}
+
+func (c *context) maxDebugGroupsImpl() int {
+	// GL_MAX_DEBUG_GROUP_STACK_DEPTH is available only with GL_KHR_debug or OpenGL 4.3.
+	// Without them, glGetIntegerv causes an error and doesn't update the value.
+	d := int32(0)
+	gl.GetIntegerv(gl.MAX_DEBUG_GROUP_STACK_DEPTH, &d)
+	if e := gl.GetError(); e != gl.NO_ERROR {
+		return 0
+	}
+	return int(d)
+}
+
+func (c *context) pushDebugGroup(name string) {
+	gl.PushDebugGroup(gl.DEBUG_SOURCE_APPLICATION, 0, -1, gl.Str(name+"\x00"))
+}
+
+func (c *context) popDebugGroup() {
+	gl.PopDebugGroup()
+}
+
+func (c *context) isTimerQueryAvailableImpl() bool {
+	// GL_TIME_ELAPSED is available only with GL_ARB_timer_query, GL_EXT_timer_query or OpenGL 3.3.
+	// Without them, glBeginQuery causes an error.
+	q := c.newQuery()
+	defer c.deleteQuery(q)
+	gl.BeginQuery(gl.TIME_ELAPSED, uint32(q))
+	if e := gl.GetError(); e != gl.NO_ERROR {
+		return false
+	}
+	gl.EndQuery(gl.TIME_ELAPSED)
+	return true
+}
+
+func (c *context) newQuery() queryNative {
+	var q uint32
+	gl.GenQueries(1, &q)
+	return queryNative(q)
+}
+
+func (c *context) deleteQuery(q queryNative) {
+	qq := uint32(q)
+	gl.DeleteQueries(1, &qq)
+}
+
+func (c *context) beginTimerQuery(q queryNative) {
+	gl.BeginQuery(gl.TIME_ELAPSED, uint32(q))
+}
+
+func (c *context) endTimerQuery() {
+	gl.EndQuery(gl.TIME_ELAPSED)
+}
+
+func (c *context) isQueryResultAvailable(q queryNative) bool {
+	var a uint32
+	gl.GetQueryObjectuiv(uint32(q), gl.QUERY_RESULT_AVAILABLE, &a)
+	return a == gl.TRUE
+}
+
+func (c *context) getString(name uint32) string {
+	s := gl.GetString(name)
+	if s == nil {
+		return ""
+	}
+	return gl.GoStr(s)
+}
+
+func (c *context) renderer() string {
+	return c.getString(gl.RENDERER)
+}
+
+func (c *context) version() string {
+	return c.getString(gl.VERSION)
+}
+
+func (c *context) isFloatTextureAvailable() bool {
+	// Floating-point textures are available with OpenGL 3.0 or later.
+	major, _ := parseGLVersion(c.version())
+	return major >= 3
+}
+
+func (c *context) isComputeShaderAvailable() bool {
+	// GL_MAX_COMPUTE_WORK_GROUP_INVOCATIONS is available only with GL_ARB_compute_shader or OpenGL 4.3.
+	// Without them, glGetIntegerv causes an error.
+	n := int32(0)
+	gl.GetIntegerv(gl.MAX_COMPUTE_WORK_GROUP_INVOCATIONS, &n)
+	if e := gl.GetError(); e != gl.NO_ERROR {
+		return false
+	}
+	return n > 0
+}
+
+func (c *context) isInstancingAvailableImpl() bool {
+	// glVertexAttribDivisor is available with OpenGL 3.3 or later.
+	major, minor := parseGLVersion(c.version())
+	return major > 3 || (major == 3 && minor >= 3)
+}
+
+func (c *context) isParallelShaderCompileAvailableImpl() bool {
+	// GL_ARB_parallel_shader_compile has the same token as GL_KHR_parallel_shader_compile.
+	for _, e := range strings.Fields(c.getString(gl.EXTENSIONS)) {
+		if e == "GL_KHR_parallel_shader_compile" || e == "GL_ARB_parallel_shader_compile" {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *context) isTextureRGAvailableImpl() bool {
+	// R8 and RG8 textures are available with OpenGL 3.0 or later.
+	major, _ := parseGLVersion(c.version())
+	return major >= 3
+}
+
+func (c *context) isFenceAvailableImpl() bool {
+	// GL_MAX_SERVER_WAIT_TIMEOUT is available only with GL_ARB_sync or OpenGL 3.2.
+	// Without them, glGetIntegerv causes an error.
+	t := int32(0)
+	gl.GetIntegerv(gl.MAX_SERVER_WAIT_TIMEOUT, &t)
+	if e := gl.GetError(); e != gl.NO_ERROR {
+		return false
+	}
+	return true
+}
+
+func (c *context) newFence() fenceNative {
+	return fenceNative(gl.FenceSync(gl.SYNC_GPU_COMMANDS_COMPLETE, 0))
+}
+
+func (c *context) isFenceSignaled(f fenceNative) bool {
+	var s int32
+	gl.GetSynciv(uintptr(f), gl.SYNC_STATUS, 1, nil, &s)
+	return s == gl.SIGNALED
+}
+
+func (c *context) deleteFence(f fenceNative) {
+	gl.DeleteSync(uintptr(f))
+}
+
+func (c *context) queryResult(q queryNative) uint64 {
+	// Use glGetQueryObjectui64v since the result in nanoseconds might not fit into 32 bits.
+	var r uint64
+	gl.GetQueryObjectui64v(uint32(q), gl.QUERY_RESULT, &r)
+	return r
+}
+
 func (c *context) getShaderPrecisionFormatPrecision() int {
 	// glGetShaderPrecisionFormat is not defined at OpenGL 2.0. Assume that desktop environments always have
 	// enough highp precision.
@@ -496,10 +843,16 @@ func (c *context) needsRestoring() bool {
 	return false
 }
 
-func (c *context) texSubImage2D(t textureNative, args []*graphicsdriver.ReplacePixelsArgs) {
+func (c *context) texSubImage2D(t textureNative, format graphicsdriver.PixelFormat, args []*graphicsdriver.ReplacePixelsArgs) {
 	c.bindTexture(t)
+	_, pixelFormat := textureFormats(format)
+	if format != graphicsdriver.PixelFormatRGBA8 {
+		// Rows of one- or two-byte pixels are not always aligned to 4 bytes.
+		gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+		defer gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	}
 	for _, a := range args {
-		gl.TexSubImage2D(gl.TEXTURE_2D, 0, int32(a.X), int32(a.Y), int32(a.Width), int32(a.Height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(a.Pixels))
+		gl.TexSubImage2D(gl.TEXTURE_2D, 0, int32(a.X), int32(a.Y), int32(a.Width), int32(a.Height), pixelFormat, gl.UNSIGNED_BYTE, gl.Ptr(a.Pixels))
 	}
 }
 
@@ -518,7 +871,15 @@ func (c *context) beginStencilWithEvenOddRule() {
 	gl.ColorMask(false, false, false, false)
 }
 
-func (c *context) endStencilWithEvenOddRule() {
+func (c *context) beginStencilWithNonZeroRule() {
+	gl.Clear(gl.STENCIL_BUFFER_BIT)
+	gl.StencilFunc(gl.ALWAYS, 0x00, 0xff)
+	gl.StencilOpSeparate(gl.FRONT, gl.KEEP, gl.KEEP, gl.INCR_WRAP)
+	gl.StencilOpSeparate(gl.BACK, gl.KEEP, gl.KEEP, gl.DECR_WRAP)
+	gl.ColorMask(false, false, false, false)
+}
+
+func (c *context) endStencil() {
 	gl.StencilFunc(gl.NOTEQUAL, 0x00, 0xff)
 	gl.StencilOp(gl.KEEP, gl.KEEP, gl.KEEP)
 	gl.ColorMask(true, true, true, true)
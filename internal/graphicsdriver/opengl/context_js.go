@@ -32,6 +32,8 @@ type (
 	shader             js.Value
 	buffer             js.Value
 	uniformLocation    js.Value
+	queryNative        js.Value
+	fenceNative        js.Value
 
 	attribLocation int
 	programID      int
@@ -78,13 +80,21 @@ func getProgramID(p program) programID {
 }
 
 const (
-	zero             = operation(gles.ZERO)
-	one              = operation(gles.ONE)
-	srcAlpha         = operation(gles.SRC_ALPHA)
-	dstAlpha         = operation(gles.DST_ALPHA)
-	oneMinusSrcAlpha = operation(gles.ONE_MINUS_SRC_ALPHA)
-	oneMinusDstAlpha = operation(gles.ONE_MINUS_DST_ALPHA)
-	dstColor         = operation(gles.DST_COLOR)
+	zero                = blendFactor(gles.ZERO)
+	one                 = blendFactor(gles.ONE)
+	srcColor            = blendFactor(gles.SRC_COLOR)
+	oneMinusSrcColor    = blendFactor(gles.ONE_MINUS_SRC_COLOR)
+	srcAlpha            = blendFactor(gles.SRC_ALPHA)
+	oneMinusSrcAlpha    = blendFactor(gles.ONE_MINUS_SRC_ALPHA)
+	dstColor            = blendFactor(gles.DST_COLOR)
+	oneMinusDstColor    = blendFactor(gles.ONE_MINUS_DST_COLOR)
+	dstAlpha            = blendFactor(gles.DST_ALPHA)
+	oneMinusDstAlpha    = blendFactor(gles.ONE_MINUS_DST_ALPHA)
+	funcAdd             = blendOperation(gles.FUNC_ADD)
+	funcSubtract        = blendOperation(gles.FUNC_SUBTRACT)
+	funcReverseSubtract = blendOperation(gles.FUNC_REVERSE_SUBTRACT)
+	funcMin             = blendOperation(gles.MIN)
+	funcMax             = blendOperation(gles.MAX)
 )
 
 type webGLVersion int
@@ -167,7 +177,7 @@ func (c *context) reset() error {
 	c.lastFramebuffer = framebufferNative(js.Null())
 	c.lastViewportWidth = 0
 	c.lastViewportHeight = 0
-	c.lastCompositeMode = graphicsdriver.CompositeModeUnknown
+	c.lastBlend = graphicsdriver.Blend{}
 
 	if err := c.initGL(); err != nil {
 		return err
@@ -179,25 +189,38 @@ func (c *context) reset() error {
 	gl := c.gl
 	gl.enable.Invoke(gles.BLEND)
 	gl.enable.Invoke(gles.SCISSOR_TEST)
-	c.blendFunc(graphicsdriver.CompositeModeSourceOver)
+	c.blend(graphicsdriver.BlendSourceOver)
 	f := gl.getParameter.Invoke(gles.FRAMEBUFFER_BINDING)
 	c.screenFramebuffer = framebufferNative(f)
 
 	if !c.usesWebGL2() {
 		gl.getExtension.Invoke("OES_standard_derivatives")
+		// EXT_blend_minmax is required for the min and max blend operations.
+		gl.getExtension.Invoke("EXT_blend_minmax")
+	}
+	if gl.createVertexArray.Truthy() {
+		// Bind one vertex array object for the whole context.
+		// The vertex attributes are still specified for each draw call, but some browsers handle a bound
+		// vertex array object more efficiently than the default one.
+		gl.bindVertexArray.Invoke(gl.createVertexArray.Invoke())
 	}
 	return nil
 }
 
-func (c *context) blendFunc(mode graphicsdriver.CompositeMode) {
-	if c.lastCompositeMode == mode {
+func (c *context) blend(blend graphicsdriver.Blend) {
+	if c.lastBlend == blend {
 		return
 	}
-	c.lastCompositeMode = mode
-	s, d := mode.Operations()
-	s2, d2 := convertOperation(s), convertOperation(d)
+	c.lastBlend = blend
+	sRGB := convertBlendFactor(blend.BlendFactorSourceRGB)
+	sA := convertBlendFactor(blend.BlendFactorSourceAlpha)
+	dRGB := convertBlendFactor(blend.BlendFactorDestinationRGB)
+	dA := convertBlendFactor(blend.BlendFactorDestinationAlpha)
+	opRGB := convertBlendOperation(blend.BlendOperationRGB)
+	opA := convertBlendOperation(blend.BlendOperationAlpha)
 	gl := c.gl
-	gl.blendFunc.Invoke(int(s2), int(d2))
+	gl.blendFuncSeparate.Invoke(int(sRGB), int(dRGB), int(sA), int(dA))
+	gl.blendEquationSeparate.Invoke(int(opRGB), int(opA))
 }
 
 func (c *context) scissor(x, y, width, height int) {
@@ -205,7 +228,7 @@ func (c *context) scissor(x, y, width, height int) {
 	gl.scissor.Invoke(x, y, width, height)
 }
 
-func (c *context) newTexture(width, height int) (textureNative, error) {
+func (c *context) newTexture(width, height int, format graphicsdriver.PixelFormat) (textureNative, error) {
 	gl := c.gl
 	t := gl.createTexture.Invoke()
 	if !t.Truthy() {
@@ -226,7 +249,19 @@ func (c *context) newTexture(width, height int) (textureNative, error) {
 	// In Ebiten, textures are filled with pixels laster by the filter that ignores destination, so it is fine
 	// to leave textures as uninitialized here. Rather, extra memory allocating for initialization should be
 	// avoided.
-	gl.texImage2D.Invoke(gles.TEXTURE_2D, 0, gles.RGBA, width, height, 0, gles.RGBA, gles.UNSIGNED_BYTE, nil)
+	internalFormat := gles.RGBA
+	if c.usesWebGL2() {
+		// Use the sized internal format to ensure 8 bits per channel.
+		internalFormat = gles.RGBA8
+	}
+	pixelFormat := textureFormat(format)
+	switch format {
+	case graphicsdriver.PixelFormatR8:
+		internalFormat = gles.R8
+	case graphicsdriver.PixelFormatRG8:
+		internalFormat = gles.RG8
+	}
+	gl.texImage2D.Invoke(gles.TEXTURE_2D, 0, internalFormat, width, height, 0, pixelFormat, gles.UNSIGNED_BYTE, nil)
 
 	return textureNative(t), nil
 }
@@ -248,16 +283,29 @@ func (c *context) framebufferPixels(f *framebuffer, width, height int) []byte {
 	return uint8ArrayToSlice(p, l)
 }
 
-func (c *context) framebufferPixelsToBuffer(f *framebuffer, buffer buffer, width, height int) {
+func (c *context) framebufferPixelsToBuffer(f *framebuffer, buffer buffer, x, y, width, height int) {
 	gl := c.gl
 
 	c.bindFramebuffer(f.native)
 	gl.bindBuffer.Invoke(gles.PIXEL_PACK_BUFFER, js.Value(buffer))
 	// void gl.readPixels(x, y, width, height, format, type, GLintptr offset);
-	gl.readPixels.Invoke(0, 0, width, height, gles.RGBA, gles.UNSIGNED_BYTE, 0)
+	gl.readPixels.Invoke(x, y, width, height, gles.RGBA, gles.UNSIGNED_BYTE, 0)
 	gl.bindBuffer.Invoke(gles.PIXEL_PACK_BUFFER, nil)
 }
 
+func (c *context) canReadPixelsAsync() bool {
+	// Reading pixels asynchronously is not implemented for WebGL yet.
+	return false
+}
+
+func (c *context) newPixelPackBuffer(size int) buffer {
+	panic("opengl: newPixelPackBuffer is not implemented")
+}
+
+func (c *context) pixelPackBufferData(b buffer, size int) []byte {
+	panic("opengl: pixelPackBufferData is not implemented")
+}
+
 func (c *context) activeTexture(idx int) {
 	gl := c.gl
 	gl.activeTexture.Invoke(gles.TEXTURE0 + idx)
@@ -328,6 +376,26 @@ func (c *context) newFramebuffer(t textureNative) (framebufferNative, error) {
 	return framebufferNative(f), nil
 }
 
+// newFramebufferFromTextures creates a framebuffer that has the given textures as its color attachments.
+func (c *context) newFramebufferFromTextures(textures []textureNative) (framebufferNative, error) {
+	gl := c.gl
+	f := gl.createFramebuffer.Invoke()
+	c.bindFramebuffer(framebufferNative(f))
+
+	bufs := make([]interface{}, len(textures))
+	for i, t := range textures {
+		a := gles.COLOR_ATTACHMENT0 + i
+		gl.framebufferTexture2D.Invoke(gles.FRAMEBUFFER, a, gles.TEXTURE_2D, js.Value(t), 0)
+		bufs[i] = a
+	}
+	gl.drawBuffers.Invoke(bufs)
+	if s := gl.checkFramebufferStatus.Invoke(gles.FRAMEBUFFER); s.Int() != gles.FRAMEBUFFER_COMPLETE {
+		return framebufferNative(js.Null()), errors.New(fmt.Sprintf("opengl: creating framebuffer failed: %d", s.Int()))
+	}
+
+	return framebufferNative(f), nil
+}
+
 func (c *context) bindStencilBuffer(f framebufferNative, r renderbufferNative) error {
 	gl := c.gl
 	c.bindFramebuffer(f)
@@ -369,6 +437,26 @@ func (c *context) newFragmentShader(source string) (shader, error) {
 }
 
 func (c *context) newShader(shaderType int, source string) (shader, error) {
+	s, err := c.compileShader(shaderType, source)
+	if err != nil {
+		return shader(js.Null()), err
+	}
+	if err := c.checkShader(s); err != nil {
+		return shader(js.Null()), err
+	}
+	return s, nil
+}
+
+func (c *context) compileVertexShader(source string) (shader, error) {
+	return c.compileShader(gles.VERTEX_SHADER, source)
+}
+
+func (c *context) compileFragmentShader(source string) (shader, error) {
+	return c.compileShader(gles.FRAGMENT_SHADER, source)
+}
+
+// compileShader starts compiling a shader without checking the result.
+func (c *context) compileShader(shaderType int, source string) (shader, error) {
 	gl := c.gl
 	s := gl.createShader.Invoke(int(shaderType))
 	if !s.Truthy() {
@@ -377,12 +465,18 @@ func (c *context) newShader(shaderType int, source string) (shader, error) {
 
 	gl.shaderSource.Invoke(js.Value(s), source)
 	gl.compileShader.Invoke(js.Value(s))
+	return shader(s), nil
+}
 
+// checkShader returns an error if compiling the shader failed.
+// checkShader waits for the compilation to finish.
+func (c *context) checkShader(s shader) error {
+	gl := c.gl
 	if !gl.getShaderParameter.Invoke(js.Value(s), gles.COMPILE_STATUS).Bool() {
 		log := gl.getShaderInfoLog.Invoke(js.Value(s))
-		return shader(js.Null()), fmt.Errorf("opengl: shader compile failed: %s", log)
+		return fmt.Errorf("opengl: shader compile failed: %s", log)
 	}
-	return shader(s), nil
+	return nil
 }
 
 func (c *context) deleteShader(s shader) {
@@ -391,6 +485,18 @@ func (c *context) deleteShader(s shader) {
 }
 
 func (c *context) newProgram(shaders []shader, attributes []string) (program, error) {
+	p, err := c.linkProgram(shaders, attributes)
+	if err != nil {
+		return program{}, err
+	}
+	if err := c.checkProgram(p); err != nil {
+		return program{}, err
+	}
+	return p, nil
+}
+
+// linkProgram starts linking a program without checking the result.
+func (c *context) linkProgram(shaders []shader, attributes []string) (program, error) {
 	gl := c.gl
 	v := gl.createProgram.Invoke()
 	if !v.Truthy() {
@@ -406,10 +512,6 @@ func (c *context) newProgram(shaders []shader, attributes []string) (program, er
 	}
 
 	gl.linkProgram.Invoke(v)
-	if !gl.getProgramParameter.Invoke(v, gles.LINK_STATUS).Bool() {
-		info := gl.getProgramInfoLog.Invoke(v).String()
-		return program{}, fmt.Errorf("opengl: program error: %s", info)
-	}
 
 	id := c.lastProgramID
 	c.lastProgramID++
@@ -419,6 +521,27 @@ func (c *context) newProgram(shaders []shader, attributes []string) (program, er
 	}, nil
 }
 
+// checkProgram returns an error if linking the program failed.
+// checkProgram waits for the linking to finish.
+func (c *context) checkProgram(p program) error {
+	gl := c.gl
+	if !gl.getProgramParameter.Invoke(p.value, gles.LINK_STATUS).Bool() {
+		info := gl.getProgramInfoLog.Invoke(p.value).String()
+		return fmt.Errorf("opengl: program error: %s", info)
+	}
+	return nil
+}
+
+// isProgramCompleted reports whether compiling and linking the program finished.
+// isProgramCompleted doesn't wait for the completion.
+func (c *context) isProgramCompleted(p program) bool {
+	if !c.isParallelShaderCompileAvailable() {
+		// Without the extension, the completion cannot be queried without blocking.
+		return true
+	}
+	return c.gl.getProgramParameter.Invoke(p.value, gles.COMPLETION_STATUS).Bool()
+}
+
 func (c *context) useProgram(p program) {
 	gl := c.gl
 	gl.useProgram.Invoke(p.value)
@@ -596,11 +719,145 @@ func (c *context) drawElements(len int, offsetInBytes int) {
 	gl.drawElements.Invoke(gles.TRIANGLES, len, gles.UNSIGNED_SHORT, offsetInBytes)
 }
 
+func (c *context) drawElementsInstanced(len int, offsetInBytes int, instanceCount int) {
+	gl := c.gl
+	gl.drawElementsInstanced.Invoke(gles.TRIANGLES, len, gles.UNSIGNED_SHORT, offsetInBytes, instanceCount)
+}
+
+func (c *context) vertexAttribDivisor(index int, divisor int) {
+	gl := c.gl
+	gl.vertexAttribDivisor.Invoke(index, divisor)
+}
+
 func (c *context) maxTextureSizeImpl() int {
 	gl := c.gl
 	return gl.getParameter.Invoke(gles.MAX_TEXTURE_SIZE).Int()
 }
 
+func (c *context) maxSampleCountImpl() int {
+	// Multisampling is not implemented for WebGL yet.
+	// Loading pixels into a multisampled renderbuffer by glBlitFramebuffer is not allowed there.
+	return 1
+}
+
+func (c *context) maxDrawBuffersImpl() int {
+	// drawBuffers is available with WebGL 2.
+	// WEBGL_draw_buffers for WebGL 1 is not used.
+	if !c.gl.drawBuffers.Truthy() {
+		return 1
+	}
+	return c.gl.getParameter.Invoke(gles.MAX_DRAW_BUFFERS).Int()
+}
+
+func (c *context) newMultisampledColorRenderbuffer(width, height, sampleCount int) (renderbufferNative, error) {
+	panic("opengl: newMultisampledColorRenderbuffer is not implemented")
+}
+
+func (c *context) newMultisampledStencilRenderbuffer(width, height, sampleCount int) (renderbufferNative, error) {
+	panic("opengl: newMultisampledStencilRenderbuffer is not implemented")
+}
+
+func (c *context) newFramebufferFromRenderbuffer(renderbuffer renderbufferNative) (framebufferNative, error) {
+	panic("opengl: newFramebufferFromRenderbuffer is not implemented")
+}
+
+func (c *context) blitFramebuffer(src, dst framebufferNative, width, height int) {
+	panic("opengl: blitFramebuffer is not implemented")
+}
+
+func (c *context) maxDebugGroupsImpl() int {
+	// Debug groups are not available for WebGL.
+	return 0
+}
+
+func (c *context) pushDebugGroup(name string) {
+	panic("opengl: pushDebugGroup is not implemented")
+}
+
+func (c *context) popDebugGroup() {
+	panic("opengl: popDebugGroup is not implemented")
+}
+
+func (c *context) isTimerQueryAvailableImpl() bool {
+	// Timer queries are not available for WebGL.
+	return false
+}
+
+func (c *context) newQuery() queryNative {
+	panic("opengl: newQuery is not implemented")
+}
+
+func (c *context) deleteQuery(q queryNative) {
+	panic("opengl: deleteQuery is not implemented")
+}
+
+func (c *context) beginTimerQuery(q queryNative) {
+	panic("opengl: beginTimerQuery is not implemented")
+}
+
+func (c *context) endTimerQuery() {
+	panic("opengl: endTimerQuery is not implemented")
+}
+
+func (c *context) isQueryResultAvailable(q queryNative) bool {
+	panic("opengl: isQueryResultAvailable is not implemented")
+}
+
+func (c *context) queryResult(q queryNative) uint64 {
+	panic("opengl: queryResult is not implemented")
+}
+
+func (c *context) renderer() string {
+	return c.gl.getParameter.Invoke(gles.RENDERER).String()
+}
+
+func (c *context) version() string {
+	return c.gl.getParameter.Invoke(gles.VERSION).String()
+}
+
+func (c *context) isFloatTextureAvailable() bool {
+	if c.usesWebGL2() {
+		return true
+	}
+	return c.gl.getExtension.Invoke("OES_texture_float").Truthy()
+}
+
+func (c *context) isComputeShaderAvailable() bool {
+	// WebGL doesn't have compute shaders.
+	return false
+}
+
+func (c *context) isParallelShaderCompileAvailableImpl() bool {
+	return c.gl.getExtension.Invoke("KHR_parallel_shader_compile").Truthy()
+}
+
+func (c *context) isTextureRGAvailableImpl() bool {
+	// R8 and RG8 textures are available with WebGL 2.
+	return c.usesWebGL2()
+}
+
+func (c *context) isInstancingAvailableImpl() bool {
+	// Instanced drawing is available with WebGL 2.
+	return c.gl.drawElementsInstanced.Truthy()
+}
+
+func (c *context) isFenceAvailableImpl() bool {
+	// Fences are not used for WebGL.
+	return false
+}
+
+func (c *context) newFence() fenceNative {
+	panic("opengl: newFence is not implemented")
+}
+
+func (c *context) isFenceSignaled(f fenceNative) bool {
+	panic("opengl: isFenceSignaled is not implemented")
+}
+
+func (c *context) deleteFence(f fenceNative) {
+	panic("opengl: deleteFence is not implemented")
+}
+
 func (c *context) getShaderPrecisionFormatPrecision() int {
 	gl := c.gl
 	return gl.getShaderPrecisionFormat.Invoke(gles.FRAGMENT_SHADER, gles.HIGH_FLOAT).Get("precision").Int()
@@ -620,25 +877,45 @@ func (c *context) canUsePBO() bool {
 	return false
 }
 
-func (c *context) texSubImage2D(t textureNative, args []*graphicsdriver.ReplacePixelsArgs) {
+func (c *context) texSubImage2D(t textureNative, format graphicsdriver.PixelFormat, args []*graphicsdriver.ReplacePixelsArgs) {
 	c.bindTexture(t)
 	gl := c.gl
+	pixelFormat := textureFormat(format)
+	if format != graphicsdriver.PixelFormatRGBA8 {
+		// Rows of one- or two-byte pixels are not always aligned to 4 bytes.
+		gl.pixelStorei.Invoke(gles.UNPACK_ALIGNMENT, 1)
+		defer gl.pixelStorei.Invoke(gles.UNPACK_ALIGNMENT, 4)
+	}
 	for _, a := range args {
 		arr := jsutil.TemporaryUint8ArrayFromUint8Slice(len(a.Pixels), a.Pixels)
 		if c.usesWebGL2() {
 			// void texSubImage2D(GLenum target, GLint level, GLint xoffset, GLint yoffset,
 			//                    GLsizei width, GLsizei height,
 			//                    GLenum format, GLenum type, ArrayBufferView pixels, srcOffset);
-			gl.texSubImage2D.Invoke(gles.TEXTURE_2D, 0, a.X, a.Y, a.Width, a.Height, gles.RGBA, gles.UNSIGNED_BYTE, arr, 0)
+			gl.texSubImage2D.Invoke(gles.TEXTURE_2D, 0, a.X, a.Y, a.Width, a.Height, pixelFormat, gles.UNSIGNED_BYTE, arr, 0)
 		} else {
 			// void texSubImage2D(GLenum target, GLint level, GLint xoffset, GLint yoffset,
 			//                    GLsizei width, GLsizei height,
 			//                    GLenum format, GLenum type, ArrayBufferView? pixels);
-			gl.texSubImage2D.Invoke(gles.TEXTURE_2D, 0, a.X, a.Y, a.Width, a.Height, gles.RGBA, gles.UNSIGNED_BYTE, arr)
+			gl.texSubImage2D.Invoke(gles.TEXTURE_2D, 0, a.X, a.Y, a.Width, a.Height, pixelFormat, gles.UNSIGNED_BYTE, arr)
 		}
 	}
 }
 
+// textureFormat returns the format of pixels for the given pixel format.
+func textureFormat(format graphicsdriver.PixelFormat) int {
+	switch format {
+	case graphicsdriver.PixelFormatRGBA8:
+		return gles.RGBA
+	case graphicsdriver.PixelFormatR8:
+		return gles.RED
+	case graphicsdriver.PixelFormatRG8:
+		return gles.RG
+	default:
+		panic(fmt.Sprintf("opengl: invalid pixel format: %d", format))
+	}
+}
+
 func (c *context) enableStencilTest() {
 	gl := c.gl
 	gl.enable.Invoke(gles.STENCIL_TEST)
@@ -657,7 +934,16 @@ func (c *context) beginStencilWithEvenOddRule() {
 	gl.colorMask.Invoke(false, false, false, false)
 }
 
-func (c *context) endStencilWithEvenOddRule() {
+func (c *context) beginStencilWithNonZeroRule() {
+	gl := c.gl
+	gl.clear.Invoke(gles.STENCIL_BUFFER_BIT)
+	gl.stencilFunc.Invoke(gles.ALWAYS, 0x00, 0xff)
+	gl.stencilOpSeparate.Invoke(gles.FRONT, gles.KEEP, gles.KEEP, gles.INCR_WRAP)
+	gl.stencilOpSeparate.Invoke(gles.BACK, gles.KEEP, gles.KEEP, gles.DECR_WRAP)
+	gl.colorMask.Invoke(false, false, false, false)
+}
+
+func (c *context) endStencil() {
 	gl := c.gl
 	gl.stencilFunc.Invoke(gles.NOTEQUAL, 0x00, 0xff)
 	gl.stencilOp.Invoke(gles.KEEP, gles.KEEP, gles.KEEP)
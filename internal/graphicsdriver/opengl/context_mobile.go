@@ -20,6 +20,7 @@ package opengl
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/opengl/gles"
@@ -33,6 +34,8 @@ type (
 	shader             uint32
 	program            uint32
 	buffer             uint32
+	queryNative        uint32
+	fenceNative        uintptr
 )
 
 func (t textureNative) equal(rhs textureNative) bool {
@@ -83,17 +86,28 @@ func getProgramID(p program) programID {
 }
 
 const (
-	zero             = operation(gles.ZERO)
-	one              = operation(gles.ONE)
-	srcAlpha         = operation(gles.SRC_ALPHA)
-	dstAlpha         = operation(gles.DST_ALPHA)
-	oneMinusSrcAlpha = operation(gles.ONE_MINUS_SRC_ALPHA)
-	oneMinusDstAlpha = operation(gles.ONE_MINUS_DST_ALPHA)
-	dstColor         = operation(gles.DST_COLOR)
+	zero                = blendFactor(gles.ZERO)
+	one                 = blendFactor(gles.ONE)
+	srcColor            = blendFactor(gles.SRC_COLOR)
+	oneMinusSrcColor    = blendFactor(gles.ONE_MINUS_SRC_COLOR)
+	srcAlpha            = blendFactor(gles.SRC_ALPHA)
+	oneMinusSrcAlpha    = blendFactor(gles.ONE_MINUS_SRC_ALPHA)
+	dstColor            = blendFactor(gles.DST_COLOR)
+	oneMinusDstColor    = blendFactor(gles.ONE_MINUS_DST_COLOR)
+	dstAlpha            = blendFactor(gles.DST_ALPHA)
+	oneMinusDstAlpha    = blendFactor(gles.ONE_MINUS_DST_ALPHA)
+	funcAdd             = blendOperation(gles.FUNC_ADD)
+	funcSubtract        = blendOperation(gles.FUNC_SUBTRACT)
+	funcReverseSubtract = blendOperation(gles.FUNC_REVERSE_SUBTRACT)
+	funcMin             = blendOperation(gles.MIN)
+	funcMax             = blendOperation(gles.MAX)
 )
 
 type contextImpl struct {
 	ctx gles.Context
+
+	// isES3 reports whether the context is OpenGL ES 3.0 or later.
+	isES3 bool
 }
 
 func (c *context) reset() error {
@@ -102,32 +116,46 @@ func (c *context) reset() error {
 	c.lastFramebuffer = invalidFramebuffer
 	c.lastViewportWidth = 0
 	c.lastViewportHeight = 0
-	c.lastCompositeMode = graphicsdriver.CompositeModeUnknown
+	c.lastBlend = graphicsdriver.Blend{}
 	c.ctx.Enable(gles.BLEND)
 	c.ctx.Enable(gles.SCISSOR_TEST)
-	c.blendFunc(graphicsdriver.CompositeModeSourceOver)
+	c.blend(graphicsdriver.BlendSourceOver)
 	f := make([]int32, 1)
 	c.ctx.GetIntegerv(f, gles.FRAMEBUFFER_BINDING)
 	c.screenFramebuffer = framebufferNative(f[0])
 	// TODO: Need to update screenFramebufferWidth/Height?
+
+	major, _ := parseGLVersion(c.version())
+	c.isES3 = major >= 3
+	if c.isES3 {
+		// Bind one vertex array object for the whole context.
+		// The vertex attributes are still specified for each draw call, but some drivers handle a bound
+		// vertex array object more efficiently than the default one.
+		c.ctx.BindVertexArray(c.ctx.GenVertexArrays(1)[0])
+	}
 	return nil
 }
 
-func (c *context) blendFunc(mode graphicsdriver.CompositeMode) {
-	if c.lastCompositeMode == mode {
+func (c *context) blend(blend graphicsdriver.Blend) {
+	if c.lastBlend == blend {
 		return
 	}
-	c.lastCompositeMode = mode
-	s, d := mode.Operations()
-	s2, d2 := convertOperation(s), convertOperation(d)
-	c.ctx.BlendFunc(uint32(s2), uint32(d2))
+	c.lastBlend = blend
+	sRGB := convertBlendFactor(blend.BlendFactorSourceRGB)
+	sA := convertBlendFactor(blend.BlendFactorSourceAlpha)
+	dRGB := convertBlendFactor(blend.BlendFactorDestinationRGB)
+	dA := convertBlendFactor(blend.BlendFactorDestinationAlpha)
+	opRGB := convertBlendOperation(blend.BlendOperationRGB)
+	opA := convertBlendOperation(blend.BlendOperationAlpha)
+	c.ctx.BlendFuncSeparate(uint32(sRGB), uint32(dRGB), uint32(sA), uint32(dA))
+	c.ctx.BlendEquationSeparate(uint32(opRGB), uint32(opA))
 }
 
 func (c *context) scissor(x, y, width, height int) {
 	c.ctx.Scissor(int32(x), int32(y), int32(width), int32(height))
 }
 
-func (c *context) newTexture(width, height int) (textureNative, error) {
+func (c *context) newTexture(width, height int, format graphicsdriver.PixelFormat) (textureNative, error) {
 	t := c.ctx.GenTextures(1)[0]
 	if t <= 0 {
 		return 0, errors.New("opengl: creating texture failed")
@@ -139,7 +167,19 @@ func (c *context) newTexture(width, height int) (textureNative, error) {
 	c.ctx.TexParameteri(gles.TEXTURE_2D, gles.TEXTURE_WRAP_S, gles.CLAMP_TO_EDGE)
 	c.ctx.TexParameteri(gles.TEXTURE_2D, gles.TEXTURE_WRAP_T, gles.CLAMP_TO_EDGE)
 	c.ctx.PixelStorei(gles.UNPACK_ALIGNMENT, 4)
-	c.ctx.TexImage2D(gles.TEXTURE_2D, 0, gles.RGBA, int32(width), int32(height), gles.RGBA, gles.UNSIGNED_BYTE, nil)
+	internalFormat := int32(gles.RGBA)
+	if c.isES3 {
+		// Use the sized internal format to ensure 8 bits per channel.
+		internalFormat = gles.RGBA8
+	}
+	pixelFormat := textureFormat(format)
+	switch format {
+	case graphicsdriver.PixelFormatR8:
+		internalFormat = gles.R8
+	case graphicsdriver.PixelFormatRG8:
+		internalFormat = gles.RG8
+	}
+	c.ctx.TexImage2D(gles.TEXTURE_2D, 0, internalFormat, int32(width), int32(height), pixelFormat, gles.UNSIGNED_BYTE, nil)
 
 	return textureNative(t), nil
 }
@@ -158,16 +198,29 @@ func (c *context) framebufferPixels(f *framebuffer, width, height int) []byte {
 	return pixels
 }
 
-func (c *context) framebufferPixelsToBuffer(f *framebuffer, buffer buffer, width, height int) {
+func (c *context) framebufferPixelsToBuffer(f *framebuffer, buffer buffer, x, y, width, height int) {
 	c.ctx.Flush()
 
 	c.bindFramebuffer(f.native)
 
 	c.ctx.BindBuffer(gles.PIXEL_PACK_BUFFER, uint32(buffer))
-	c.ctx.ReadPixels(nil, 0, 0, int32(width), int32(height), gles.RGBA, gles.UNSIGNED_BYTE)
+	c.ctx.ReadPixels(nil, int32(x), int32(y), int32(width), int32(height), gles.RGBA, gles.UNSIGNED_BYTE)
 	c.ctx.BindBuffer(gles.PIXEL_PACK_BUFFER, 0)
 }
 
+func (c *context) canReadPixelsAsync() bool {
+	// As well as canUsePBO, do not use PBO for reading pixels on mobiles.
+	return false
+}
+
+func (c *context) newPixelPackBuffer(size int) buffer {
+	panic("opengl: newPixelPackBuffer is not implemented")
+}
+
+func (c *context) pixelPackBufferData(b buffer, size int) []byte {
+	panic("opengl: pixelPackBufferData is not implemented")
+}
+
 func (c *context) activeTexture(idx int) {
 	c.ctx.ActiveTexture(uint32(gles.TEXTURE0 + idx))
 }
@@ -239,6 +292,26 @@ func (c *context) newFramebuffer(texture textureNative) (framebufferNative, erro
 	return framebufferNative(f), nil
 }
 
+// newFramebufferFromTextures creates a framebuffer that has the given textures as its color attachments.
+func (c *context) newFramebufferFromTextures(textures []textureNative) (framebufferNative, error) {
+	f := c.ctx.GenFramebuffers(1)[0]
+	if f <= 0 {
+		return 0, fmt.Errorf("opengl: creating framebuffer failed: the returned value is not positive but %d", f)
+	}
+	c.bindFramebuffer(framebufferNative(f))
+
+	bufs := make([]uint32, len(textures))
+	for i, t := range textures {
+		bufs[i] = gles.COLOR_ATTACHMENT0 + uint32(i)
+		c.ctx.FramebufferTexture2D(gles.FRAMEBUFFER, bufs[i], gles.TEXTURE_2D, uint32(t), 0)
+	}
+	c.ctx.DrawBuffers(bufs)
+	if s := c.ctx.CheckFramebufferStatus(gles.FRAMEBUFFER); s != gles.FRAMEBUFFER_COMPLETE {
+		return 0, fmt.Errorf("opengl: creating framebuffer failed: %v", s)
+	}
+	return framebufferNative(f), nil
+}
+
 func (c *context) bindStencilBuffer(f framebufferNative, r renderbufferNative) error {
 	c.bindFramebuffer(f)
 
@@ -277,20 +350,45 @@ func (c *context) newFragmentShader(source string) (shader, error) {
 }
 
 func (c *context) newShader(shaderType uint32, source string) (shader, error) {
+	s, err := c.compileShader(shaderType, source)
+	if err != nil {
+		return 0, err
+	}
+	if err := c.checkShader(s); err != nil {
+		return 0, err
+	}
+	return s, nil
+}
+
+func (c *context) compileVertexShader(source string) (shader, error) {
+	return c.compileShader(gles.VERTEX_SHADER, source)
+}
+
+func (c *context) compileFragmentShader(source string) (shader, error) {
+	return c.compileShader(gles.FRAGMENT_SHADER, source)
+}
+
+// compileShader starts compiling a shader without checking the result.
+func (c *context) compileShader(shaderType uint32, source string) (shader, error) {
 	s := c.ctx.CreateShader(shaderType)
 	if s == 0 {
 		return 0, fmt.Errorf("opengl: glCreateShader failed: shader type: %d", shaderType)
 	}
 	c.ctx.ShaderSource(s, source)
 	c.ctx.CompileShader(s)
+	return shader(s), nil
+}
 
+// checkShader returns an error if compiling the shader failed.
+// checkShader waits for the compilation to finish.
+func (c *context) checkShader(s shader) error {
 	v := make([]int32, 1)
-	c.ctx.GetShaderiv(v, s, gles.COMPILE_STATUS)
+	c.ctx.GetShaderiv(v, uint32(s), gles.COMPILE_STATUS)
 	if v[0] == gles.FALSE {
-		log := c.ctx.GetShaderInfoLog(s)
-		return 0, fmt.Errorf("opengl: shader compile failed: %s", log)
+		log := c.ctx.GetShaderInfoLog(uint32(s))
+		return fmt.Errorf("opengl: shader compile failed: %s", log)
 	}
-	return shader(s), nil
+	return nil
 }
 
 func (c *context) deleteShader(s shader) {
@@ -298,6 +396,18 @@ func (c *context) deleteShader(s shader) {
 }
 
 func (c *context) newProgram(shaders []shader, attributes []string) (program, error) {
+	p, err := c.linkProgram(shaders, attributes)
+	if err != nil {
+		return 0, err
+	}
+	if err := c.checkProgram(p); err != nil {
+		return 0, err
+	}
+	return p, nil
+}
+
+// linkProgram starts linking a program without checking the result.
+func (c *context) linkProgram(shaders []shader, attributes []string) (program, error) {
 	p := c.ctx.CreateProgram()
 	if p == 0 {
 		return 0, errors.New("opengl: glCreateProgram failed")
@@ -312,13 +422,31 @@ func (c *context) newProgram(shaders []shader, attributes []string) (program, er
 	}
 
 	c.ctx.LinkProgram(p)
+	return program(p), nil
+}
+
+// checkProgram returns an error if linking the program failed.
+// checkProgram waits for the linking to finish.
+func (c *context) checkProgram(p program) error {
 	v := make([]int32, 1)
-	c.ctx.GetProgramiv(v, p, gles.LINK_STATUS)
+	c.ctx.GetProgramiv(v, uint32(p), gles.LINK_STATUS)
 	if v[0] == gles.FALSE {
-		info := c.ctx.GetProgramInfoLog(p)
-		return 0, fmt.Errorf("opengl: program error: %s", info)
+		info := c.ctx.GetProgramInfoLog(uint32(p))
+		return fmt.Errorf("opengl: program error: %s", info)
 	}
-	return program(p), nil
+	return nil
+}
+
+// isProgramCompleted reports whether compiling and linking the program finished.
+// isProgramCompleted doesn't wait for the completion.
+func (c *context) isProgramCompleted(p program) bool {
+	if !c.isParallelShaderCompileAvailable() {
+		// Without the extension, the completion cannot be queried without blocking.
+		return true
+	}
+	v := make([]int32, 1)
+	c.ctx.GetProgramiv(v, uint32(p), gles.COMPLETION_STATUS)
+	return v[0] != gles.FALSE
 }
 
 func (c *context) useProgram(p program) {
@@ -439,12 +567,160 @@ func (c *context) drawElements(len int, offsetInBytes int) {
 	c.ctx.DrawElements(gles.TRIANGLES, int32(len), gles.UNSIGNED_SHORT, offsetInBytes)
 }
 
+func (c *context) drawElementsInstanced(len int, offsetInBytes int, instanceCount int) {
+	c.ctx.DrawElementsInstanced(gles.TRIANGLES, int32(len), gles.UNSIGNED_SHORT, offsetInBytes, int32(instanceCount))
+}
+
+func (c *context) vertexAttribDivisor(index int, divisor int) {
+	c.ctx.VertexAttribDivisor(uint32(index), uint32(divisor))
+}
+
 func (c *context) maxTextureSizeImpl() int {
 	v := make([]int32, 1)
 	c.ctx.GetIntegerv(v, gles.MAX_TEXTURE_SIZE)
 	return int(v[0])
 }
 
+func (c *context) maxSampleCountImpl() int {
+	// Multisampling is not implemented for OpenGL ES yet.
+	// Loading pixels into a multisampled renderbuffer by glBlitFramebuffer is not allowed there.
+	return 1
+}
+
+func (c *context) maxDrawBuffersImpl() int {
+	// glDrawBuffers is available with OpenGL ES 3.0 or later.
+	// The bindings by gomobile don't have glDrawBuffers.
+	if !c.isES3 {
+		return 1
+	}
+	if _, ok := c.ctx.(*gles.GomobileContext); ok {
+		return 1
+	}
+	v := make([]int32, 1)
+	c.ctx.GetIntegerv(v, gles.MAX_DRAW_BUFFERS)
+	if v[0] < 1 {
+		return 1
+	}
+	return int(v[0])
+}
+
+func (c *context) newMultisampledColorRenderbuffer(width, height, sampleCount int) (renderbufferNative, error) {
+	panic("opengl: newMultisampledColorRenderbuffer is not implemented")
+}
+
+func (c *context) newMultisampledStencilRenderbuffer(width, height, sampleCount int) (renderbufferNative, error) {
+	panic("opengl: newMultisampledStencilRenderbuffer is not implemented")
+}
+
+func (c *context) newFramebufferFromRenderbuffer(renderbuffer renderbufferNative) (framebufferNative, error) {
+	panic("opengl: newFramebufferFromRenderbuffer is not implemented")
+}
+
+func (c *context) blitFramebuffer(src, dst framebufferNative, width, height int) {
+	panic("opengl: blitFramebuffer is not implemented")
+}
+
+func (c *context) maxDebugGroupsImpl() int {
+	// Debug groups are not available for OpenGL ES.
+	return 0
+}
+
+func (c *context) pushDebugGroup(name string) {
+	panic("opengl: pushDebugGroup is not implemented")
+}
+
+func (c *context) popDebugGroup() {
+	panic("opengl: popDebugGroup is not implemented")
+}
+
+func (c *context) isTimerQueryAvailableImpl() bool {
+	// Timer queries are not available for OpenGL ES.
+	return false
+}
+
+func (c *context) newQuery() queryNative {
+	panic("opengl: newQuery is not implemented")
+}
+
+func (c *context) deleteQuery(q queryNative) {
+	panic("opengl: deleteQuery is not implemented")
+}
+
+func (c *context) beginTimerQuery(q queryNative) {
+	panic("opengl: beginTimerQuery is not implemented")
+}
+
+func (c *context) endTimerQuery() {
+	panic("opengl: endTimerQuery is not implemented")
+}
+
+func (c *context) isQueryResultAvailable(q queryNative) bool {
+	panic("opengl: isQueryResultAvailable is not implemented")
+}
+
+func (c *context) queryResult(q queryNative) uint64 {
+	panic("opengl: queryResult is not implemented")
+}
+
+func (c *context) renderer() string {
+	return c.ctx.GetString(gles.RENDERER)
+}
+
+func (c *context) version() string {
+	return c.ctx.GetString(gles.VERSION)
+}
+
+func (c *context) isFloatTextureAvailable() bool {
+	// Floating-point textures are available with OpenGL ES 3.0 or later.
+	return c.isES3
+}
+
+func (c *context) isComputeShaderAvailable() bool {
+	// Compute shaders are available with OpenGL ES 3.1 or later.
+	major, minor := parseGLVersion(c.version())
+	return major > 3 || (major == 3 && minor >= 1)
+}
+
+func (c *context) isParallelShaderCompileAvailableImpl() bool {
+	for _, e := range strings.Fields(c.ctx.GetString(gles.EXTENSIONS)) {
+		if e == "GL_KHR_parallel_shader_compile" {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *context) isTextureRGAvailableImpl() bool {
+	// R8 and RG8 textures are available with OpenGL ES 3.0 or later.
+	return c.isES3
+}
+
+func (c *context) isInstancingAvailableImpl() bool {
+	// Instanced drawing is available with OpenGL ES 3.0 or later.
+	// The bindings by gomobile don't have the functions for instanced drawing.
+	if _, ok := c.ctx.(*gles.GomobileContext); ok {
+		return false
+	}
+	return c.isES3
+}
+
+func (c *context) isFenceAvailableImpl() bool {
+	// Fences are not used for OpenGL ES.
+	return false
+}
+
+func (c *context) newFence() fenceNative {
+	panic("opengl: newFence is not implemented")
+}
+
+func (c *context) isFenceSignaled(f fenceNative) bool {
+	panic("opengl: isFenceSignaled is not implemented")
+}
+
+func (c *context) deleteFence(f fenceNative) {
+	panic("opengl: deleteFence is not implemented")
+}
+
 func (c *context) getShaderPrecisionFormatPrecision() int {
 	_, _, p := c.ctx.GetShaderPrecisionFormat(gles.FRAGMENT_SHADER, gles.HIGH_FLOAT)
 	return p
@@ -464,10 +740,30 @@ func (c *context) canUsePBO() bool {
 	return false
 }
 
-func (c *context) texSubImage2D(t textureNative, args []*graphicsdriver.ReplacePixelsArgs) {
+func (c *context) texSubImage2D(t textureNative, format graphicsdriver.PixelFormat, args []*graphicsdriver.ReplacePixelsArgs) {
 	c.bindTexture(t)
+	pixelFormat := textureFormat(format)
+	if format != graphicsdriver.PixelFormatRGBA8 {
+		// Rows of one- or two-byte pixels are not always aligned to 4 bytes.
+		c.ctx.PixelStorei(gles.UNPACK_ALIGNMENT, 1)
+		defer c.ctx.PixelStorei(gles.UNPACK_ALIGNMENT, 4)
+	}
 	for _, a := range args {
-		c.ctx.TexSubImage2D(gles.TEXTURE_2D, 0, int32(a.X), int32(a.Y), int32(a.Width), int32(a.Height), gles.RGBA, gles.UNSIGNED_BYTE, a.Pixels)
+		c.ctx.TexSubImage2D(gles.TEXTURE_2D, 0, int32(a.X), int32(a.Y), int32(a.Width), int32(a.Height), pixelFormat, gles.UNSIGNED_BYTE, a.Pixels)
+	}
+}
+
+// textureFormat returns the format of pixels for the given pixel format.
+func textureFormat(format graphicsdriver.PixelFormat) uint32 {
+	switch format {
+	case graphicsdriver.PixelFormatRGBA8:
+		return gles.RGBA
+	case graphicsdriver.PixelFormatR8:
+		return gles.RED
+	case graphicsdriver.PixelFormatRG8:
+		return gles.RG
+	default:
+		panic(fmt.Sprintf("opengl: invalid pixel format: %d", format))
 	}
 }
 
@@ -486,7 +782,15 @@ func (c *context) beginStencilWithEvenOddRule() {
 	c.ctx.ColorMask(false, false, false, false)
 }
 
-func (c *context) endStencilWithEvenOddRule() {
+func (c *context) beginStencilWithNonZeroRule() {
+	c.ctx.Clear(gles.STENCIL_BUFFER_BIT)
+	c.ctx.StencilFunc(gles.ALWAYS, 0x00, 0xff)
+	c.ctx.StencilOpSeparate(gles.FRONT, gles.KEEP, gles.KEEP, gles.INCR_WRAP)
+	c.ctx.StencilOpSeparate(gles.BACK, gles.KEEP, gles.KEEP, gles.DECR_WRAP)
+	c.ctx.ColorMask(false, false, false, false)
+}
+
+func (c *context) endStencil() {
 	c.ctx.StencilFunc(gles.NOTEQUAL, 0x00, 0xff)
 	c.ctx.StencilOp(gles.KEEP, gles.KEEP, gles.KEEP)
 	c.ctx.ColorMask(true, true, true, true)
@@ -55,8 +55,11 @@ func checkGLSL(src string) {
 	}
 }
 
-func vertexShaderStr() string {
+func vertexShaderStr(instanced bool) string {
 	src := shaderStrVertex
+	if instanced {
+		src = "#define INSTANCED\n" + src
+	}
 	checkGLSL(src)
 	return src
 }
@@ -112,12 +115,25 @@ uniform vec2 viewport_size;
 attribute vec2 A0;
 attribute vec2 A1;
 attribute vec4 A2;
+#if defined(INSTANCED)
+// The destination offset (xy) and the source offset (zw) for each instance.
+attribute vec4 A3;
+// The color scale for each instance.
+attribute vec4 A4;
+#endif
 varying vec2 varying_tex;
 varying vec4 varying_color_scale;
 
 void main(void) {
+#if defined(INSTANCED)
+  vec2 position = A0 + A3.xy;
+  varying_tex = A1 + A3.zw;
+  varying_color_scale = A2 * A4;
+#else
+  vec2 position = A0;
   varying_tex = A1;
   varying_color_scale = A2;
+#endif
 
   mat4 projection_matrix = mat4(
     vec4(2.0 / viewport_size.x, 0, 0, 0),
@@ -125,7 +141,7 @@ void main(void) {
     vec4(0, 0, 1, 0),
     vec4(-1, -1, 0, 1)
   );
-  gl_Position = projection_matrix * vec4(A0, 0, 1);
+  gl_Position = projection_matrix * vec4(position, 0, 1);
 }
 `
 	shaderStrFragment = `
@@ -35,6 +35,32 @@ func newFramebufferFromTexture(context *context, texture textureNative, width, h
 	}, nil
 }
 
+// newFramebufferFromTextures creates a framebuffer that renders onto the given textures at the same time.
+func newFramebufferFromTextures(context *context, textures []textureNative, width, height int) (*framebuffer, error) {
+	native, err := context.newFramebufferFromTextures(textures)
+	if err != nil {
+		return nil, err
+	}
+	return &framebuffer{
+		native: native,
+		width:  width,
+		height: height,
+	}, nil
+}
+
+// newFramebufferFromRenderbuffer creates a framebuffer from the given color renderbuffer.
+func newFramebufferFromRenderbuffer(context *context, renderbuffer renderbufferNative, width, height int) (*framebuffer, error) {
+	native, err := context.newFramebufferFromRenderbuffer(renderbuffer)
+	if err != nil {
+		return nil, err
+	}
+	return &framebuffer{
+		native: native,
+		width:  width,
+		height: height,
+	}, nil
+}
+
 // newScreenFramebuffer creates a framebuffer for the screen.
 func newScreenFramebuffer(context *context, width, height int) *framebuffer {
 	return &framebuffer{
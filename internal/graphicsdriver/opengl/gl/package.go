@@ -16,47 +16,72 @@
 package gl
 
 const (
-	ZERO                = 0
-	ONE                 = 1
-	SRC_ALPHA           = 0x0302
-	DST_ALPHA           = 0x0304
-	ONE_MINUS_SRC_ALPHA = 0x0303
-	ONE_MINUS_DST_ALPHA = 0x0305
-	DST_COLOR           = 0x0306
+	ZERO                  = 0
+	ONE                   = 1
+	SRC_COLOR             = 0x0300
+	ONE_MINUS_SRC_COLOR   = 0x0301
+	SRC_ALPHA             = 0x0302
+	ONE_MINUS_SRC_ALPHA   = 0x0303
+	DST_ALPHA             = 0x0304
+	ONE_MINUS_DST_ALPHA   = 0x0305
+	DST_COLOR             = 0x0306
+	ONE_MINUS_DST_COLOR   = 0x0307
+	FUNC_ADD              = 0x8006
+	MIN                   = 0x8007
+	MAX                   = 0x8008
+	FUNC_SUBTRACT         = 0x800A
+	FUNC_REVERSE_SUBTRACT = 0x800B
 
 	ALWAYS               = 0x0207
 	ARRAY_BUFFER         = 0x8892
+	BACK                 = 0x0405
 	BLEND                = 0x0BE2
 	CLAMP_TO_EDGE        = 0x812F
 	COLOR_ATTACHMENT0    = 0x8CE0
+	COLOR_BUFFER_BIT     = 0x4000
 	COMPILE_STATUS       = 0x8B81
+	COMPLETION_STATUS    = 0x91B1
+	DECR_WRAP            = 0x8508
 	DEPTH24_STENCIL8     = 0x88F0
+	DRAW_FRAMEBUFFER     = 0x8CA9
 	DYNAMIC_DRAW         = 0x88E8
 	ELEMENT_ARRAY_BUFFER = 0x8893
+	EXTENSIONS           = 0x1F03
 	FALSE                = 0
 	FLOAT                = 0x1406
 	FRAGMENT_SHADER      = 0x8B30
 	FRAMEBUFFER          = 0x8D40
 	FRAMEBUFFER_BINDING  = 0x8CA6
 	FRAMEBUFFER_COMPLETE = 0x8CD5
+	FRONT                = 0x0404
+	INCR_WRAP            = 0x8507
 	INFO_LOG_LENGTH      = 0x8B84
 	INVERT               = 0x150A
 	KEEP                 = 0x1E00
 	LINK_STATUS          = 0x8B82
+	MAX_DRAW_BUFFERS     = 0x8824
+	MAX_SAMPLES          = 0x8D57
 	MAX_TEXTURE_SIZE     = 0x0D33
 	NEAREST              = 0x2600
 	NO_ERROR             = 0
 	NOTEQUAL             = 0x0205
 	PIXEL_PACK_BUFFER    = 0x88EB
 	PIXEL_UNPACK_BUFFER  = 0x88EC
+	R8                   = 0x8229
+	READ_FRAMEBUFFER     = 0x8CA8
 	READ_WRITE           = 0x88BA
+	RED                  = 0x1903
 	RENDERBUFFER         = 0x8D41
+	RG                   = 0x8227
+	RG8                  = 0x822B
 	RGBA                 = 0x1908
+	RGBA8                = 0x8058
 	SHORT                = 0x1402
 	STENCIL_ATTACHMENT   = 0x8D20
 	STENCIL_BUFFER_BIT   = 0x0400
 	STENCIL_TEST         = 0x0B90
 	STREAM_DRAW          = 0x88E0
+	STREAM_READ          = 0x88E1
 	TEXTURE0             = 0x84C0
 	TEXTURE_2D           = 0x0DE1
 	TEXTURE_MAG_FILTER   = 0x2800
@@ -73,6 +98,27 @@ const (
 	WRITE_ONLY           = 0x88B9
 )
 
+// Constants for timer queries (OpenGL 3.3 or GL_EXT_timer_query).
+const (
+	QUERY_RESULT           = 0x8866
+	QUERY_RESULT_AVAILABLE = 0x8867
+	TIME_ELAPSED           = 0x88BF
+)
+
+// Constants for KHR_debug (OpenGL 4.3).
+const (
+	DEBUG_SOURCE_APPLICATION    = 0x824A
+	MAX_DEBUG_GROUP_STACK_DEPTH = 0x826C
+)
+
+// Constants for sync objects (OpenGL 3.2 or GL_ARB_sync).
+const (
+	MAX_SERVER_WAIT_TIMEOUT    = 0x9111
+	SIGNALED                   = 0x9119
+	SYNC_GPU_COMMANDS_COMPLETE = 0x9117
+	SYNC_STATUS                = 0x9114
+)
+
 // Init initializes the OpenGL bindings by loading the function pointers (for
 // each OpenGL function) from the active OpenGL context.
 //
@@ -95,3 +141,14 @@ const (
 func Init() error {
 	return InitWithProcAddrFunc(getProcAddress)
 }
+
+// Constants for querying the implementation.
+const (
+	RENDERER = 0x1F01
+	VERSION  = 0x1F02
+)
+
+// Constants for compute shaders (OpenGL 4.3 or GL_ARB_compute_shader).
+const (
+	MAX_COMPUTE_WORK_GROUP_INVOCATIONS = 0x90EB
+)
@@ -98,12 +98,15 @@ package gl
 //
 // typedef void  (APIENTRYP GPACTIVETEXTURE)(GLenum  texture);
 // typedef void  (APIENTRYP GPATTACHSHADER)(GLuint  program, GLuint  shader);
+// typedef void  (APIENTRYP GPBEGINQUERY)(GLenum  target, GLuint  id);
 // typedef void  (APIENTRYP GPBINDATTRIBLOCATION)(GLuint  program, GLuint  index, const GLchar * name);
 // typedef void  (APIENTRYP GPBINDBUFFER)(GLenum  target, GLuint  buffer);
 // typedef void  (APIENTRYP GPBINDFRAMEBUFFEREXT)(GLenum  target, GLuint  framebuffer);
 // typedef void  (APIENTRYP GPBINDRENDERBUFFEREXT)(GLenum  target, GLuint  renderbuffer);
 // typedef void  (APIENTRYP GPBINDTEXTURE)(GLenum  target, GLuint  texture);
-// typedef void  (APIENTRYP GPBLENDFUNC)(GLenum  sfactor, GLenum  dfactor);
+// typedef void  (APIENTRYP GPBLENDEQUATIONSEPARATE)(GLenum  modeRGB, GLenum  modeAlpha);
+// typedef void  (APIENTRYP GPBLENDFUNCSEPARATE)(GLenum  sfactorRGB, GLenum  dfactorRGB, GLenum  sfactorAlpha, GLenum  dfactorAlpha);
+// typedef void  (APIENTRYP GPBLITFRAMEBUFFEREXT)(GLint  srcX0, GLint  srcY0, GLint  srcX1, GLint  srcY1, GLint  dstX0, GLint  dstY0, GLint  dstX1, GLint  dstY1, GLbitfield  mask, GLenum  filter);
 // typedef void  (APIENTRYP GPBUFFERDATA)(GLenum  target, GLsizeiptr  size, const void * data, GLenum  usage);
 // typedef void  (APIENTRYP GPBUFFERSUBDATA)(GLenum  target, GLintptr  offset, GLsizeiptr  size, const void * data);
 // typedef GLenum  (APIENTRYP GPCHECKFRAMEBUFFERSTATUSEXT)(GLenum  target);
@@ -115,19 +118,26 @@ package gl
 // typedef void  (APIENTRYP GPDELETEBUFFERS)(GLsizei  n, const GLuint * buffers);
 // typedef void  (APIENTRYP GPDELETEFRAMEBUFFERSEXT)(GLsizei  n, const GLuint * framebuffers);
 // typedef void  (APIENTRYP GPDELETEPROGRAM)(GLuint  program);
+// typedef void  (APIENTRYP GPDELETEQUERIES)(GLsizei  n, const GLuint * ids);
 // typedef void  (APIENTRYP GPDELETERENDERBUFFERSEXT)(GLsizei  n, const GLuint * renderbuffers);
 // typedef void  (APIENTRYP GPDELETESHADER)(GLuint  shader);
+// typedef void  (APIENTRYP GPDELETESYNC)(GLsync  sync);
 // typedef void  (APIENTRYP GPDELETETEXTURES)(GLsizei  n, const GLuint * textures);
 // typedef void  (APIENTRYP GPDISABLE)(GLenum  cap);
 // typedef void  (APIENTRYP GPDISABLEVERTEXATTRIBARRAY)(GLuint  index);
+// typedef void  (APIENTRYP GPDRAWBUFFERS)(GLsizei  n, const GLenum * bufs);
 // typedef void  (APIENTRYP GPDRAWELEMENTS)(GLenum  mode, GLsizei  count, GLenum  type, const uintptr_t indices);
+// typedef void  (APIENTRYP GPDRAWELEMENTSINSTANCED)(GLenum  mode, GLsizei  count, GLenum  type, const uintptr_t indices, GLsizei  instancecount);
 // typedef void  (APIENTRYP GPENABLE)(GLenum  cap);
 // typedef void  (APIENTRYP GPENABLEVERTEXATTRIBARRAY)(GLuint  index);
+// typedef void  (APIENTRYP GPENDQUERY)(GLenum  target);
+// typedef GLsync  (APIENTRYP GPFENCESYNC)(GLenum  condition, GLbitfield  flags);
 // typedef void  (APIENTRYP GPFLUSH)();
 // typedef void  (APIENTRYP GPFRAMEBUFFERRENDERBUFFEREXT)(GLenum  target, GLenum  attachment, GLenum  renderbuffertarget, GLuint  renderbuffer);
 // typedef void  (APIENTRYP GPFRAMEBUFFERTEXTURE2DEXT)(GLenum  target, GLenum  attachment, GLenum  textarget, GLuint  texture, GLint  level);
 // typedef void  (APIENTRYP GPGENBUFFERS)(GLsizei  n, GLuint * buffers);
 // typedef void  (APIENTRYP GPGENFRAMEBUFFERSEXT)(GLsizei  n, GLuint * framebuffers);
+// typedef void  (APIENTRYP GPGENQUERIES)(GLsizei  n, GLuint * ids);
 // typedef void  (APIENTRYP GPGENRENDERBUFFERSEXT)(GLsizei  n, GLuint * renderbuffers);
 // typedef void  (APIENTRYP GPGENTEXTURES)(GLsizei  n, GLuint * textures);
 // typedef void  (APIENTRYP GPGETBUFFERSUBDATA)(GLenum  target, GLintptr  offset, GLsizeiptr  size, void * data);
@@ -142,8 +152,12 @@ package gl
 // typedef void  (APIENTRYP GPGETPOINTERI_VEXT)(GLenum  pname, GLuint  index, void ** params);
 // typedef void  (APIENTRYP GPGETPROGRAMINFOLOG)(GLuint  program, GLsizei  bufSize, GLsizei * length, GLchar * infoLog);
 // typedef void  (APIENTRYP GPGETPROGRAMIV)(GLuint  program, GLenum  pname, GLint * params);
+// typedef void  (APIENTRYP GPGETQUERYOBJECTUI64V)(GLuint  id, GLenum  pname, GLuint64 * params);
+// typedef void  (APIENTRYP GPGETQUERYOBJECTUIV)(GLuint  id, GLenum  pname, GLuint * params);
 // typedef void  (APIENTRYP GPGETSHADERINFOLOG)(GLuint  shader, GLsizei  bufSize, GLsizei * length, GLchar * infoLog);
 // typedef void  (APIENTRYP GPGETSHADERIV)(GLuint  shader, GLenum  pname, GLint * params);
+// typedef const GLubyte *  (APIENTRYP GPGETSTRING)(GLenum  name);
+// typedef void  (APIENTRYP GPGETSYNCIV)(GLsync  sync, GLenum  pname, GLsizei  bufSize, GLsizei * length, GLint * values);
 // typedef void  (APIENTRYP GPGETTRANSFORMFEEDBACKI64_V)(GLuint  xfb, GLenum  pname, GLuint  index, GLint64 * param);
 // typedef void  (APIENTRYP GPGETTRANSFORMFEEDBACKI_V)(GLuint  xfb, GLenum  pname, GLuint  index, GLint * param);
 // typedef GLint  (APIENTRYP GPGETUNIFORMLOCATION)(GLuint  program, const GLchar * name);
@@ -156,12 +170,16 @@ package gl
 // typedef GLboolean  (APIENTRYP GPISTEXTURE)(GLuint  texture);
 // typedef void  (APIENTRYP GPLINKPROGRAM)(GLuint  program);
 // typedef void  (APIENTRYP GPPIXELSTOREI)(GLenum  pname, GLint  param);
+// typedef void  (APIENTRYP GPPOPDEBUGGROUP)();
+// typedef void  (APIENTRYP GPPUSHDEBUGGROUP)(GLenum  source, GLuint  id, GLsizei  length, const GLchar * message);
 // typedef void  (APIENTRYP GPREADPIXELS)(GLint  x, GLint  y, GLsizei  width, GLsizei  height, GLenum  format, GLenum  type, void * pixels);
 // typedef void  (APIENTRYP GPRENDERBUFFERSTORAGEEXT)(GLenum  target, GLenum  internalformat, GLsizei  width, GLsizei  height);
+// typedef void  (APIENTRYP GPRENDERBUFFERSTORAGEMULTISAMPLEEXT)(GLenum  target, GLsizei  samples, GLenum  internalformat, GLsizei  width, GLsizei  height);
 // typedef void  (APIENTRYP GPSCISSOR)(GLint  x, GLint  y, GLsizei  width, GLsizei  height);
 // typedef void  (APIENTRYP GPSHADERSOURCE)(GLuint  shader, GLsizei  count, const GLchar *const* string, const GLint * length);
 // typedef void  (APIENTRYP GPSTENCILFUNC)(GLenum  func, GLint  ref, GLuint  mask);
 // typedef void  (APIENTRYP GPSTENCILOP)(GLenum  fail, GLenum  zfail, GLenum  zpass);
+// typedef void  (APIENTRYP GPSTENCILOPSEPARATE)(GLenum  face, GLenum  sfail, GLenum  dpfail, GLenum  dppass);
 // typedef void  (APIENTRYP GPTEXIMAGE2D)(GLenum  target, GLint  level, GLint  internalformat, GLsizei  width, GLsizei  height, GLint  border, GLenum  format, GLenum  type, const void * pixels);
 // typedef void  (APIENTRYP GPTEXPARAMETERI)(GLenum  target, GLenum  pname, GLint  param);
 // typedef void  (APIENTRYP GPTEXSUBIMAGE2D)(GLenum  target, GLint  level, GLint  xoffset, GLint  yoffset, GLsizei  width, GLsizei  height, GLenum  format, GLenum  type, const void * pixels);
@@ -175,6 +193,7 @@ package gl
 // typedef void  (APIENTRYP GPUNIFORMMATRIX3FV)(GLint  location, GLsizei  count, GLboolean  transpose, const GLfloat * value);
 // typedef void  (APIENTRYP GPUNIFORMMATRIX4FV)(GLint  location, GLsizei  count, GLboolean  transpose, const GLfloat * value);
 // typedef void  (APIENTRYP GPUSEPROGRAM)(GLuint  program);
+// typedef void  (APIENTRYP GPVERTEXATTRIBDIVISOR)(GLuint  index, GLuint  divisor);
 // typedef void  (APIENTRYP GPVERTEXATTRIBPOINTER)(GLuint  index, GLint  size, GLenum  type, GLboolean  normalized, GLsizei  stride, const uintptr_t pointer);
 // typedef void  (APIENTRYP GPVIEWPORT)(GLint  x, GLint  y, GLsizei  width, GLsizei  height);
 //
@@ -184,6 +203,9 @@ package gl
 // static void  glowAttachShader(GPATTACHSHADER fnptr, GLuint  program, GLuint  shader) {
 //   (*fnptr)(program, shader);
 // }
+// static void  glowBeginQuery(GPBEGINQUERY fnptr, GLenum  target, GLuint  id) {
+//   (*fnptr)(target, id);
+// }
 // static void  glowBindAttribLocation(GPBINDATTRIBLOCATION fnptr, GLuint  program, GLuint  index, const GLchar * name) {
 //   (*fnptr)(program, index, name);
 // }
@@ -199,8 +221,14 @@ package gl
 // static void  glowBindTexture(GPBINDTEXTURE fnptr, GLenum  target, GLuint  texture) {
 //   (*fnptr)(target, texture);
 // }
-// static void  glowBlendFunc(GPBLENDFUNC fnptr, GLenum  sfactor, GLenum  dfactor) {
-//   (*fnptr)(sfactor, dfactor);
+// static void  glowBlendEquationSeparate(GPBLENDEQUATIONSEPARATE fnptr, GLenum  modeRGB, GLenum  modeAlpha) {
+//   (*fnptr)(modeRGB, modeAlpha);
+// }
+// static void  glowBlendFuncSeparate(GPBLENDFUNCSEPARATE fnptr, GLenum  sfactorRGB, GLenum  dfactorRGB, GLenum  sfactorAlpha, GLenum  dfactorAlpha) {
+//   (*fnptr)(sfactorRGB, dfactorRGB, sfactorAlpha, dfactorAlpha);
+// }
+// static void  glowBlitFramebufferEXT(GPBLITFRAMEBUFFEREXT fnptr, GLint  srcX0, GLint  srcY0, GLint  srcX1, GLint  srcY1, GLint  dstX0, GLint  dstY0, GLint  dstX1, GLint  dstY1, GLbitfield  mask, GLenum  filter) {
+//   (*fnptr)(srcX0, srcY0, srcX1, srcY1, dstX0, dstY0, dstX1, dstY1, mask, filter);
 // }
 // static void  glowBufferData(GPBUFFERDATA fnptr, GLenum  target, GLsizeiptr  size, const void * data, GLenum  usage) {
 //   (*fnptr)(target, size, data, usage);
@@ -235,12 +263,18 @@ package gl
 // static void  glowDeleteProgram(GPDELETEPROGRAM fnptr, GLuint  program) {
 //   (*fnptr)(program);
 // }
+// static void  glowDeleteQueries(GPDELETEQUERIES fnptr, GLsizei  n, const GLuint * ids) {
+//   (*fnptr)(n, ids);
+// }
 // static void  glowDeleteRenderbuffersEXT(GPDELETERENDERBUFFERSEXT fnptr, GLsizei  n, const GLuint * renderbuffers) {
 //   (*fnptr)(n, renderbuffers);
 // }
 // static void  glowDeleteShader(GPDELETESHADER fnptr, GLuint  shader) {
 //   (*fnptr)(shader);
 // }
+// static void  glowDeleteSync(GPDELETESYNC fnptr, GLsync  sync) {
+//   (*fnptr)(sync);
+// }
 // static void  glowDeleteTextures(GPDELETETEXTURES fnptr, GLsizei  n, const GLuint * textures) {
 //   (*fnptr)(n, textures);
 // }
@@ -250,15 +284,27 @@ package gl
 // static void  glowDisableVertexAttribArray(GPDISABLEVERTEXATTRIBARRAY fnptr, GLuint  index) {
 //   (*fnptr)(index);
 // }
+// static void  glowDrawBuffers(GPDRAWBUFFERS fnptr, GLsizei  n, const GLenum * bufs) {
+//   (*fnptr)(n, bufs);
+// }
 // static void  glowDrawElements(GPDRAWELEMENTS fnptr, GLenum  mode, GLsizei  count, GLenum  type, const uintptr_t indices) {
 //   (*fnptr)(mode, count, type, indices);
 // }
+// static void  glowDrawElementsInstanced(GPDRAWELEMENTSINSTANCED fnptr, GLenum  mode, GLsizei  count, GLenum  type, const uintptr_t indices, GLsizei  instancecount) {
+//   (*fnptr)(mode, count, type, indices, instancecount);
+// }
 // static void  glowEnable(GPENABLE fnptr, GLenum  cap) {
 //   (*fnptr)(cap);
 // }
 // static void  glowEnableVertexAttribArray(GPENABLEVERTEXATTRIBARRAY fnptr, GLuint  index) {
 //   (*fnptr)(index);
 // }
+// static void  glowEndQuery(GPENDQUERY fnptr, GLenum  target) {
+//   (*fnptr)(target);
+// }
+// static GLsync  glowFenceSync(GPFENCESYNC fnptr, GLenum  condition, GLbitfield  flags) {
+//   return (*fnptr)(condition, flags);
+// }
 // static void  glowFlush(GPFLUSH fnptr) {
 //   (*fnptr)();
 // }
@@ -274,6 +320,9 @@ package gl
 // static void  glowGenFramebuffersEXT(GPGENFRAMEBUFFERSEXT fnptr, GLsizei  n, GLuint * framebuffers) {
 //   (*fnptr)(n, framebuffers);
 // }
+// static void  glowGenQueries(GPGENQUERIES fnptr, GLsizei  n, GLuint * ids) {
+//   (*fnptr)(n, ids);
+// }
 // static void  glowGenRenderbuffersEXT(GPGENRENDERBUFFERSEXT fnptr, GLsizei  n, GLuint * renderbuffers) {
 //   (*fnptr)(n, renderbuffers);
 // }
@@ -316,12 +365,24 @@ package gl
 // static void  glowGetProgramiv(GPGETPROGRAMIV fnptr, GLuint  program, GLenum  pname, GLint * params) {
 //   (*fnptr)(program, pname, params);
 // }
+// static void  glowGetQueryObjectui64v(GPGETQUERYOBJECTUI64V fnptr, GLuint  id, GLenum  pname, GLuint64 * params) {
+//   (*fnptr)(id, pname, params);
+// }
+// static void  glowGetQueryObjectuiv(GPGETQUERYOBJECTUIV fnptr, GLuint  id, GLenum  pname, GLuint * params) {
+//   (*fnptr)(id, pname, params);
+// }
 // static void  glowGetShaderInfoLog(GPGETSHADERINFOLOG fnptr, GLuint  shader, GLsizei  bufSize, GLsizei * length, GLchar * infoLog) {
 //   (*fnptr)(shader, bufSize, length, infoLog);
 // }
 // static void  glowGetShaderiv(GPGETSHADERIV fnptr, GLuint  shader, GLenum  pname, GLint * params) {
 //   (*fnptr)(shader, pname, params);
 // }
+// static const GLubyte *  glowGetString(GPGETSTRING fnptr, GLenum  name) {
+//   return (*fnptr)(name);
+// }
+// static void  glowGetSynciv(GPGETSYNCIV fnptr, GLsync  sync, GLenum  pname, GLsizei  bufSize, GLsizei * length, GLint * values) {
+//   (*fnptr)(sync, pname, bufSize, length, values);
+// }
 // static void  glowGetTransformFeedbacki64_v(GPGETTRANSFORMFEEDBACKI64_V fnptr, GLuint  xfb, GLenum  pname, GLuint  index, GLint64 * param) {
 //   (*fnptr)(xfb, pname, index, param);
 // }
@@ -358,12 +419,21 @@ package gl
 // static void  glowPixelStorei(GPPIXELSTOREI fnptr, GLenum  pname, GLint  param) {
 //   (*fnptr)(pname, param);
 // }
+// static void  glowPopDebugGroup(GPPOPDEBUGGROUP fnptr) {
+//   (*fnptr)();
+// }
+// static void  glowPushDebugGroup(GPPUSHDEBUGGROUP fnptr, GLenum  source, GLuint  id, GLsizei  length, const GLchar * message) {
+//   (*fnptr)(source, id, length, message);
+// }
 // static void  glowReadPixels(GPREADPIXELS fnptr, GLint  x, GLint  y, GLsizei  width, GLsizei  height, GLenum  format, GLenum  type, void * pixels) {
 //   (*fnptr)(x, y, width, height, format, type, pixels);
 // }
 // static void  glowRenderbufferStorageEXT(GPRENDERBUFFERSTORAGEEXT fnptr, GLenum  target, GLenum  internalformat, GLsizei  width, GLsizei  height) {
 //   (*fnptr)(target, internalformat, width, height);
 // }
+// static void  glowRenderbufferStorageMultisampleEXT(GPRENDERBUFFERSTORAGEMULTISAMPLEEXT fnptr, GLenum  target, GLsizei  samples, GLenum  internalformat, GLsizei  width, GLsizei  height) {
+//   (*fnptr)(target, samples, internalformat, width, height);
+// }
 // static void  glowScissor(GPSCISSOR fnptr, GLint  x, GLint  y, GLsizei  width, GLsizei  height) {
 //   (*fnptr)(x, y, width, height);
 // }
@@ -376,6 +446,9 @@ package gl
 // static void  glowStencilOp(GPSTENCILOP fnptr, GLenum  fail, GLenum  zfail, GLenum  zpass) {
 //   (*fnptr)(fail, zfail, zpass);
 // }
+// static void  glowStencilOpSeparate(GPSTENCILOPSEPARATE fnptr, GLenum  face, GLenum  sfail, GLenum  dpfail, GLenum  dppass) {
+//   (*fnptr)(face, sfail, dpfail, dppass);
+// }
 // static void  glowTexImage2D(GPTEXIMAGE2D fnptr, GLenum  target, GLint  level, GLint  internalformat, GLsizei  width, GLsizei  height, GLint  border, GLenum  format, GLenum  type, const void * pixels) {
 //   (*fnptr)(target, level, internalformat, width, height, border, format, type, pixels);
 // }
@@ -415,6 +488,9 @@ package gl
 // static void  glowUseProgram(GPUSEPROGRAM fnptr, GLuint  program) {
 //   (*fnptr)(program);
 // }
+// static void  glowVertexAttribDivisor(GPVERTEXATTRIBDIVISOR fnptr, GLuint  index, GLuint  divisor) {
+//   (*fnptr)(index, divisor);
+// }
 // static void  glowVertexAttribPointer(GPVERTEXATTRIBPOINTER fnptr, GLuint  index, GLint  size, GLenum  type, GLboolean  normalized, GLsizei  stride, const uintptr_t pointer) {
 //   (*fnptr)(index, size, type, normalized, stride, pointer);
 // }
@@ -429,87 +505,106 @@ import (
 )
 
 var (
-	gpActiveTexture               C.GPACTIVETEXTURE
-	gpAttachShader                C.GPATTACHSHADER
-	gpBindAttribLocation          C.GPBINDATTRIBLOCATION
-	gpBindBuffer                  C.GPBINDBUFFER
-	gpBindFramebufferEXT          C.GPBINDFRAMEBUFFEREXT
-	gpBindRenderbufferEXT         C.GPBINDRENDERBUFFEREXT
-	gpBindTexture                 C.GPBINDTEXTURE
-	gpBlendFunc                   C.GPBLENDFUNC
-	gpBufferData                  C.GPBUFFERDATA
-	gpBufferSubData               C.GPBUFFERSUBDATA
-	gpCheckFramebufferStatusEXT   C.GPCHECKFRAMEBUFFERSTATUSEXT
-	gpClear                       C.GPCLEAR
-	gpColorMask                   C.GPCOLORMASK
-	gpCompileShader               C.GPCOMPILESHADER
-	gpCreateProgram               C.GPCREATEPROGRAM
-	gpCreateShader                C.GPCREATESHADER
-	gpDeleteBuffers               C.GPDELETEBUFFERS
-	gpDeleteFramebuffersEXT       C.GPDELETEFRAMEBUFFERSEXT
-	gpDeleteProgram               C.GPDELETEPROGRAM
-	gpDeleteRenderbuffersEXT      C.GPDELETERENDERBUFFERSEXT
-	gpDeleteShader                C.GPDELETESHADER
-	gpDeleteTextures              C.GPDELETETEXTURES
-	gpDisable                     C.GPDISABLE
-	gpDisableVertexAttribArray    C.GPDISABLEVERTEXATTRIBARRAY
-	gpDrawElements                C.GPDRAWELEMENTS
-	gpEnable                      C.GPENABLE
-	gpEnableVertexAttribArray     C.GPENABLEVERTEXATTRIBARRAY
-	gpFlush                       C.GPFLUSH
-	gpFramebufferRenderbufferEXT  C.GPFRAMEBUFFERRENDERBUFFEREXT
-	gpFramebufferTexture2DEXT     C.GPFRAMEBUFFERTEXTURE2DEXT
-	gpGenBuffers                  C.GPGENBUFFERS
-	gpGenFramebuffersEXT          C.GPGENFRAMEBUFFERSEXT
-	gpGenRenderbuffersEXT         C.GPGENRENDERBUFFERSEXT
-	gpGenTextures                 C.GPGENTEXTURES
-	gpGetBufferSubData            C.GPGETBUFFERSUBDATA
-	gpGetDoublei_v                C.GPGETDOUBLEI_V
-	gpGetDoublei_vEXT             C.GPGETDOUBLEI_VEXT
-	gpGetError                    C.GPGETERROR
-	gpGetFloati_v                 C.GPGETFLOATI_V
-	gpGetFloati_vEXT              C.GPGETFLOATI_VEXT
-	gpGetIntegeri_v               C.GPGETINTEGERI_V
-	gpGetIntegerui64i_vNV         C.GPGETINTEGERUI64I_VNV
-	gpGetIntegerv                 C.GPGETINTEGERV
-	gpGetPointeri_vEXT            C.GPGETPOINTERI_VEXT
-	gpGetProgramInfoLog           C.GPGETPROGRAMINFOLOG
-	gpGetProgramiv                C.GPGETPROGRAMIV
-	gpGetShaderInfoLog            C.GPGETSHADERINFOLOG
-	gpGetShaderiv                 C.GPGETSHADERIV
-	gpGetTransformFeedbacki64_v   C.GPGETTRANSFORMFEEDBACKI64_V
-	gpGetTransformFeedbacki_v     C.GPGETTRANSFORMFEEDBACKI_V
-	gpGetUniformLocation          C.GPGETUNIFORMLOCATION
-	gpGetUnsignedBytei_vEXT       C.GPGETUNSIGNEDBYTEI_VEXT
-	gpGetVertexArrayIntegeri_vEXT C.GPGETVERTEXARRAYINTEGERI_VEXT
-	gpGetVertexArrayPointeri_vEXT C.GPGETVERTEXARRAYPOINTERI_VEXT
-	gpIsFramebufferEXT            C.GPISFRAMEBUFFEREXT
-	gpIsProgram                   C.GPISPROGRAM
-	gpIsRenderbufferEXT           C.GPISRENDERBUFFEREXT
-	gpIsTexture                   C.GPISTEXTURE
-	gpLinkProgram                 C.GPLINKPROGRAM
-	gpPixelStorei                 C.GPPIXELSTOREI
-	gpReadPixels                  C.GPREADPIXELS
-	gpRenderbufferStorageEXT      C.GPRENDERBUFFERSTORAGEEXT
-	gpScissor                     C.GPSCISSOR
-	gpShaderSource                C.GPSHADERSOURCE
-	gpStencilFunc                 C.GPSTENCILFUNC
-	gpStencilOp                   C.GPSTENCILOP
-	gpTexImage2D                  C.GPTEXIMAGE2D
-	gpTexParameteri               C.GPTEXPARAMETERI
-	gpTexSubImage2D               C.GPTEXSUBIMAGE2D
-	gpUniform1f                   C.GPUNIFORM1F
-	gpUniform1i                   C.GPUNIFORM1I
-	gpUniform1fv                  C.GPUNIFORM1FV
-	gpUniform2fv                  C.GPUNIFORM2FV
-	gpUniform3fv                  C.GPUNIFORM3FV
-	gpUniform4fv                  C.GPUNIFORM4FV
-	gpUniformMatrix2fv            C.GPUNIFORMMATRIX2FV
-	gpUniformMatrix3fv            C.GPUNIFORMMATRIX3FV
-	gpUniformMatrix4fv            C.GPUNIFORMMATRIX4FV
-	gpUseProgram                  C.GPUSEPROGRAM
-	gpVertexAttribPointer         C.GPVERTEXATTRIBPOINTER
-	gpViewport                    C.GPVIEWPORT
+	gpActiveTexture                     C.GPACTIVETEXTURE
+	gpAttachShader                      C.GPATTACHSHADER
+	gpBeginQuery                        C.GPBEGINQUERY
+	gpBindAttribLocation                C.GPBINDATTRIBLOCATION
+	gpBindBuffer                        C.GPBINDBUFFER
+	gpBindFramebufferEXT                C.GPBINDFRAMEBUFFEREXT
+	gpBindRenderbufferEXT               C.GPBINDRENDERBUFFEREXT
+	gpBindTexture                       C.GPBINDTEXTURE
+	gpBlendEquationSeparate             C.GPBLENDEQUATIONSEPARATE
+	gpBlendFuncSeparate                 C.GPBLENDFUNCSEPARATE
+	gpBlitFramebufferEXT                C.GPBLITFRAMEBUFFEREXT
+	gpBufferData                        C.GPBUFFERDATA
+	gpBufferSubData                     C.GPBUFFERSUBDATA
+	gpCheckFramebufferStatusEXT         C.GPCHECKFRAMEBUFFERSTATUSEXT
+	gpClear                             C.GPCLEAR
+	gpColorMask                         C.GPCOLORMASK
+	gpCompileShader                     C.GPCOMPILESHADER
+	gpCreateProgram                     C.GPCREATEPROGRAM
+	gpCreateShader                      C.GPCREATESHADER
+	gpDeleteBuffers                     C.GPDELETEBUFFERS
+	gpDeleteFramebuffersEXT             C.GPDELETEFRAMEBUFFERSEXT
+	gpDeleteProgram                     C.GPDELETEPROGRAM
+	gpDeleteQueries                     C.GPDELETEQUERIES
+	gpDeleteRenderbuffersEXT            C.GPDELETERENDERBUFFERSEXT
+	gpDeleteShader                      C.GPDELETESHADER
+	gpDeleteSync                        C.GPDELETESYNC
+	gpDeleteTextures                    C.GPDELETETEXTURES
+	gpDisable                           C.GPDISABLE
+	gpDisableVertexAttribArray          C.GPDISABLEVERTEXATTRIBARRAY
+	gpDrawBuffers                       C.GPDRAWBUFFERS
+	gpDrawElements                      C.GPDRAWELEMENTS
+	gpDrawElementsInstanced             C.GPDRAWELEMENTSINSTANCED
+	gpEnable                            C.GPENABLE
+	gpEnableVertexAttribArray           C.GPENABLEVERTEXATTRIBARRAY
+	gpEndQuery                          C.GPENDQUERY
+	gpFenceSync                         C.GPFENCESYNC
+	gpFlush                             C.GPFLUSH
+	gpFramebufferRenderbufferEXT        C.GPFRAMEBUFFERRENDERBUFFEREXT
+	gpFramebufferTexture2DEXT           C.GPFRAMEBUFFERTEXTURE2DEXT
+	gpGenBuffers                        C.GPGENBUFFERS
+	gpGenFramebuffersEXT                C.GPGENFRAMEBUFFERSEXT
+	gpGenQueries                        C.GPGENQUERIES
+	gpGenRenderbuffersEXT               C.GPGENRENDERBUFFERSEXT
+	gpGenTextures                       C.GPGENTEXTURES
+	gpGetBufferSubData                  C.GPGETBUFFERSUBDATA
+	gpGetDoublei_v                      C.GPGETDOUBLEI_V
+	gpGetDoublei_vEXT                   C.GPGETDOUBLEI_VEXT
+	gpGetError                          C.GPGETERROR
+	gpGetFloati_v                       C.GPGETFLOATI_V
+	gpGetFloati_vEXT                    C.GPGETFLOATI_VEXT
+	gpGetIntegeri_v                     C.GPGETINTEGERI_V
+	gpGetIntegerui64i_vNV               C.GPGETINTEGERUI64I_VNV
+	gpGetIntegerv                       C.GPGETINTEGERV
+	gpGetPointeri_vEXT                  C.GPGETPOINTERI_VEXT
+	gpGetProgramInfoLog                 C.GPGETPROGRAMINFOLOG
+	gpGetProgramiv                      C.GPGETPROGRAMIV
+	gpGetQueryObjectui64v               C.GPGETQUERYOBJECTUI64V
+	gpGetQueryObjectuiv                 C.GPGETQUERYOBJECTUIV
+	gpGetShaderInfoLog                  C.GPGETSHADERINFOLOG
+	gpGetShaderiv                       C.GPGETSHADERIV
+	gpGetString                         C.GPGETSTRING
+	gpGetSynciv                         C.GPGETSYNCIV
+	gpGetTransformFeedbacki64_v         C.GPGETTRANSFORMFEEDBACKI64_V
+	gpGetTransformFeedbacki_v           C.GPGETTRANSFORMFEEDBACKI_V
+	gpGetUniformLocation                C.GPGETUNIFORMLOCATION
+	gpGetUnsignedBytei_vEXT             C.GPGETUNSIGNEDBYTEI_VEXT
+	gpGetVertexArrayIntegeri_vEXT       C.GPGETVERTEXARRAYINTEGERI_VEXT
+	gpGetVertexArrayPointeri_vEXT       C.GPGETVERTEXARRAYPOINTERI_VEXT
+	gpIsFramebufferEXT                  C.GPISFRAMEBUFFEREXT
+	gpIsProgram                         C.GPISPROGRAM
+	gpIsRenderbufferEXT                 C.GPISRENDERBUFFEREXT
+	gpIsTexture                         C.GPISTEXTURE
+	gpLinkProgram                       C.GPLINKPROGRAM
+	gpPixelStorei                       C.GPPIXELSTOREI
+	gpPopDebugGroup                     C.GPPOPDEBUGGROUP
+	gpPushDebugGroup                    C.GPPUSHDEBUGGROUP
+	gpReadPixels                        C.GPREADPIXELS
+	gpRenderbufferStorageEXT            C.GPRENDERBUFFERSTORAGEEXT
+	gpRenderbufferStorageMultisampleEXT C.GPRENDERBUFFERSTORAGEMULTISAMPLEEXT
+	gpScissor                           C.GPSCISSOR
+	gpShaderSource                      C.GPSHADERSOURCE
+	gpStencilFunc                       C.GPSTENCILFUNC
+	gpStencilOp                         C.GPSTENCILOP
+	gpStencilOpSeparate                 C.GPSTENCILOPSEPARATE
+	gpTexImage2D                        C.GPTEXIMAGE2D
+	gpTexParameteri                     C.GPTEXPARAMETERI
+	gpTexSubImage2D                     C.GPTEXSUBIMAGE2D
+	gpUniform1f                         C.GPUNIFORM1F
+	gpUniform1i                         C.GPUNIFORM1I
+	gpUniform1fv                        C.GPUNIFORM1FV
+	gpUniform2fv                        C.GPUNIFORM2FV
+	gpUniform3fv                        C.GPUNIFORM3FV
+	gpUniform4fv                        C.GPUNIFORM4FV
+	gpUniformMatrix2fv                  C.GPUNIFORMMATRIX2FV
+	gpUniformMatrix3fv                  C.GPUNIFORMMATRIX3FV
+	gpUniformMatrix4fv                  C.GPUNIFORMMATRIX4FV
+	gpUseProgram                        C.GPUSEPROGRAM
+	gpVertexAttribDivisor               C.GPVERTEXATTRIBDIVISOR
+	gpVertexAttribPointer               C.GPVERTEXATTRIBPOINTER
+	gpViewport                          C.GPVIEWPORT
 )
 
 func boolToInt(b bool) int {
@@ -527,6 +622,10 @@ func AttachShader(program uint32, shader uint32) {
 	C.glowAttachShader(gpAttachShader, (C.GLuint)(program), (C.GLuint)(shader))
 }
 
+func BeginQuery(target uint32, id uint32) {
+	C.glowBeginQuery(gpBeginQuery, (C.GLenum)(target), (C.GLuint)(id))
+}
+
 func BindAttribLocation(program uint32, index uint32, name *uint8) {
 	C.glowBindAttribLocation(gpBindAttribLocation, (C.GLuint)(program), (C.GLuint)(index), (*C.GLchar)(unsafe.Pointer(name)))
 }
@@ -547,8 +646,16 @@ func BindTexture(target uint32, texture uint32) {
 	C.glowBindTexture(gpBindTexture, (C.GLenum)(target), (C.GLuint)(texture))
 }
 
-func BlendFunc(sfactor uint32, dfactor uint32) {
-	C.glowBlendFunc(gpBlendFunc, (C.GLenum)(sfactor), (C.GLenum)(dfactor))
+func BlendEquationSeparate(modeRGB uint32, modeAlpha uint32) {
+	C.glowBlendEquationSeparate(gpBlendEquationSeparate, (C.GLenum)(modeRGB), (C.GLenum)(modeAlpha))
+}
+
+func BlendFuncSeparate(sfactorRGB uint32, dfactorRGB uint32, sfactorAlpha uint32, dfactorAlpha uint32) {
+	C.glowBlendFuncSeparate(gpBlendFuncSeparate, (C.GLenum)(sfactorRGB), (C.GLenum)(dfactorRGB), (C.GLenum)(sfactorAlpha), (C.GLenum)(dfactorAlpha))
+}
+
+func BlitFramebufferEXT(srcX0 int32, srcY0 int32, srcX1 int32, srcY1 int32, dstX0 int32, dstY0 int32, dstX1 int32, dstY1 int32, mask uint32, filter uint32) {
+	C.glowBlitFramebufferEXT(gpBlitFramebufferEXT, (C.GLint)(srcX0), (C.GLint)(srcY0), (C.GLint)(srcX1), (C.GLint)(srcY1), (C.GLint)(dstX0), (C.GLint)(dstY0), (C.GLint)(dstX1), (C.GLint)(dstY1), (C.GLbitfield)(mask), (C.GLenum)(filter))
 }
 
 func BufferData(target uint32, size int, data unsafe.Pointer, usage uint32) {
@@ -598,6 +705,10 @@ func DeleteProgram(program uint32) {
 	C.glowDeleteProgram(gpDeleteProgram, (C.GLuint)(program))
 }
 
+func DeleteQueries(n int32, ids *uint32) {
+	C.glowDeleteQueries(gpDeleteQueries, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(ids)))
+}
+
 func DeleteRenderbuffersEXT(n int32, renderbuffers *uint32) {
 	C.glowDeleteRenderbuffersEXT(gpDeleteRenderbuffersEXT, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(renderbuffers)))
 }
@@ -606,6 +717,10 @@ func DeleteShader(shader uint32) {
 	C.glowDeleteShader(gpDeleteShader, (C.GLuint)(shader))
 }
 
+func DeleteSync(sync uintptr) {
+	C.glowDeleteSync(gpDeleteSync, (C.GLsync)(sync))
+}
+
 func DeleteTextures(n int32, textures *uint32) {
 	C.glowDeleteTextures(gpDeleteTextures, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(textures)))
 }
@@ -618,10 +733,18 @@ func DisableVertexAttribArray(index uint32) {
 	C.glowDisableVertexAttribArray(gpDisableVertexAttribArray, (C.GLuint)(index))
 }
 
+func DrawBuffers(n int32, bufs *uint32) {
+	C.glowDrawBuffers(gpDrawBuffers, (C.GLsizei)(n), (*C.GLenum)(unsafe.Pointer(bufs)))
+}
+
 func DrawElements(mode uint32, count int32, xtype uint32, indices uintptr) {
 	C.glowDrawElements(gpDrawElements, (C.GLenum)(mode), (C.GLsizei)(count), (C.GLenum)(xtype), C.uintptr_t(indices))
 }
 
+func DrawElementsInstanced(mode uint32, count int32, xtype uint32, indices uintptr, instancecount int32) {
+	C.glowDrawElementsInstanced(gpDrawElementsInstanced, (C.GLenum)(mode), (C.GLsizei)(count), (C.GLenum)(xtype), C.uintptr_t(indices), (C.GLsizei)(instancecount))
+}
+
 func Enable(cap uint32) {
 	C.glowEnable(gpEnable, (C.GLenum)(cap))
 }
@@ -630,6 +753,15 @@ func EnableVertexAttribArray(index uint32) {
 	C.glowEnableVertexAttribArray(gpEnableVertexAttribArray, (C.GLuint)(index))
 }
 
+func EndQuery(target uint32) {
+	C.glowEndQuery(gpEndQuery, (C.GLenum)(target))
+}
+
+func FenceSync(condition uint32, flags uint32) uintptr {
+	ret := C.glowFenceSync(gpFenceSync, (C.GLenum)(condition), (C.GLbitfield)(flags))
+	return (uintptr)(ret)
+}
+
 func Flush() {
 	C.glowFlush(gpFlush)
 }
@@ -650,6 +782,10 @@ func GenFramebuffersEXT(n int32, framebuffers *uint32) {
 	C.glowGenFramebuffersEXT(gpGenFramebuffersEXT, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(framebuffers)))
 }
 
+func GenQueries(n int32, ids *uint32) {
+	C.glowGenQueries(gpGenQueries, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(ids)))
+}
+
 func GenRenderbuffersEXT(n int32, renderbuffers *uint32) {
 	C.glowGenRenderbuffersEXT(gpGenRenderbuffersEXT, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(renderbuffers)))
 }
@@ -702,6 +838,14 @@ func GetProgramiv(program uint32, pname uint32, params *int32) {
 	C.glowGetProgramiv(gpGetProgramiv, (C.GLuint)(program), (C.GLenum)(pname), (*C.GLint)(unsafe.Pointer(params)))
 }
 
+func GetQueryObjectui64v(id uint32, pname uint32, params *uint64) {
+	C.glowGetQueryObjectui64v(gpGetQueryObjectui64v, (C.GLuint)(id), (C.GLenum)(pname), (*C.GLuint64)(unsafe.Pointer(params)))
+}
+
+func GetQueryObjectuiv(id uint32, pname uint32, params *uint32) {
+	C.glowGetQueryObjectuiv(gpGetQueryObjectuiv, (C.GLuint)(id), (C.GLenum)(pname), (*C.GLuint)(unsafe.Pointer(params)))
+}
+
 func GetShaderInfoLog(shader uint32, bufSize int32, length *int32, infoLog *uint8) {
 	C.glowGetShaderInfoLog(gpGetShaderInfoLog, (C.GLuint)(shader), (C.GLsizei)(bufSize), (*C.GLsizei)(unsafe.Pointer(length)), (*C.GLchar)(unsafe.Pointer(infoLog)))
 }
@@ -710,6 +854,15 @@ func GetShaderiv(shader uint32, pname uint32, params *int32) {
 	C.glowGetShaderiv(gpGetShaderiv, (C.GLuint)(shader), (C.GLenum)(pname), (*C.GLint)(unsafe.Pointer(params)))
 }
 
+func GetString(name uint32) *uint8 {
+	ret := C.glowGetString(gpGetString, (C.GLenum)(name))
+	return (*uint8)(ret)
+}
+
+func GetSynciv(sync uintptr, pname uint32, bufSize int32, length *int32, values *int32) {
+	C.glowGetSynciv(gpGetSynciv, (C.GLsync)(sync), (C.GLenum)(pname), (C.GLsizei)(bufSize), (*C.GLsizei)(unsafe.Pointer(length)), (*C.GLint)(unsafe.Pointer(values)))
+}
+
 func GetTransformFeedbacki64_v(xfb uint32, pname uint32, index uint32, param *int64) {
 	C.glowGetTransformFeedbacki64_v(gpGetTransformFeedbacki64_v, (C.GLuint)(xfb), (C.GLenum)(pname), (C.GLuint)(index), (*C.GLint64)(unsafe.Pointer(param)))
 }
@@ -760,6 +913,14 @@ func PixelStorei(pname uint32, param int32) {
 	C.glowPixelStorei(gpPixelStorei, (C.GLenum)(pname), (C.GLint)(param))
 }
 
+func PopDebugGroup() {
+	C.glowPopDebugGroup(gpPopDebugGroup)
+}
+
+func PushDebugGroup(source uint32, id uint32, length int32, message *uint8) {
+	C.glowPushDebugGroup(gpPushDebugGroup, (C.GLenum)(source), (C.GLuint)(id), (C.GLsizei)(length), (*C.GLchar)(unsafe.Pointer(message)))
+}
+
 func ReadPixels(x int32, y int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
 	C.glowReadPixels(gpReadPixels, (C.GLint)(x), (C.GLint)(y), (C.GLsizei)(width), (C.GLsizei)(height), (C.GLenum)(format), (C.GLenum)(xtype), pixels)
 }
@@ -768,6 +929,10 @@ func RenderbufferStorageEXT(target uint32, internalformat uint32, width int32, h
 	C.glowRenderbufferStorageEXT(gpRenderbufferStorageEXT, (C.GLenum)(target), (C.GLenum)(internalformat), (C.GLsizei)(width), (C.GLsizei)(height))
 }
 
+func RenderbufferStorageMultisampleEXT(target uint32, samples int32, internalformat uint32, width int32, height int32) {
+	C.glowRenderbufferStorageMultisampleEXT(gpRenderbufferStorageMultisampleEXT, (C.GLenum)(target), (C.GLsizei)(samples), (C.GLenum)(internalformat), (C.GLsizei)(width), (C.GLsizei)(height))
+}
+
 func Scissor(x int32, y int32, width int32, height int32) {
 	C.glowScissor(gpScissor, (C.GLint)(x), (C.GLint)(y), (C.GLsizei)(width), (C.GLsizei)(height))
 }
@@ -784,6 +949,10 @@ func StencilOp(fail uint32, zfail uint32, zpass uint32) {
 	C.glowStencilOp(gpStencilOp, (C.GLenum)(fail), (C.GLenum)(zfail), (C.GLenum)(zpass))
 }
 
+func StencilOpSeparate(face uint32, sfail uint32, dpfail uint32, dppass uint32) {
+	C.glowStencilOpSeparate(gpStencilOpSeparate, (C.GLenum)(face), (C.GLenum)(sfail), (C.GLenum)(dpfail), (C.GLenum)(dppass))
+}
+
 func TexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
 	C.glowTexImage2D(gpTexImage2D, (C.GLenum)(target), (C.GLint)(level), (C.GLint)(internalformat), (C.GLsizei)(width), (C.GLsizei)(height), (C.GLint)(border), (C.GLenum)(format), (C.GLenum)(xtype), pixels)
 }
@@ -836,6 +1005,10 @@ func UseProgram(program uint32) {
 	C.glowUseProgram(gpUseProgram, (C.GLuint)(program))
 }
 
+func VertexAttribDivisor(index uint32, divisor uint32) {
+	C.glowVertexAttribDivisor(gpVertexAttribDivisor, (C.GLuint)(index), (C.GLuint)(divisor))
+}
+
 func VertexAttribPointer(index uint32, size int32, xtype uint32, normalized bool, stride int32, pointer uintptr) {
 	C.glowVertexAttribPointer(gpVertexAttribPointer, (C.GLuint)(index), (C.GLint)(size), (C.GLenum)(xtype), (C.GLboolean)(boolToInt(normalized)), (C.GLsizei)(stride), C.uintptr_t(pointer))
 }
@@ -857,6 +1030,7 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpAttachShader == nil {
 		return errors.New("glAttachShader")
 	}
+	gpBeginQuery = (C.GPBEGINQUERY)(getProcAddr("glBeginQuery"))
 	gpBindAttribLocation = (C.GPBINDATTRIBLOCATION)(getProcAddr("glBindAttribLocation"))
 	if gpBindAttribLocation == nil {
 		return errors.New("glBindAttribLocation")
@@ -871,10 +1045,15 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpBindTexture == nil {
 		return errors.New("glBindTexture")
 	}
-	gpBlendFunc = (C.GPBLENDFUNC)(getProcAddr("glBlendFunc"))
-	if gpBlendFunc == nil {
-		return errors.New("glBlendFunc")
+	gpBlendEquationSeparate = (C.GPBLENDEQUATIONSEPARATE)(getProcAddr("glBlendEquationSeparate"))
+	if gpBlendEquationSeparate == nil {
+		return errors.New("glBlendEquationSeparate")
 	}
+	gpBlendFuncSeparate = (C.GPBLENDFUNCSEPARATE)(getProcAddr("glBlendFuncSeparate"))
+	if gpBlendFuncSeparate == nil {
+		return errors.New("glBlendFuncSeparate")
+	}
+	gpBlitFramebufferEXT = (C.GPBLITFRAMEBUFFEREXT)(getProcAddr("glBlitFramebufferEXT"))
 	gpBufferData = (C.GPBUFFERDATA)(getProcAddr("glBufferData"))
 	if gpBufferData == nil {
 		return errors.New("glBufferData")
@@ -913,11 +1092,13 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpDeleteProgram == nil {
 		return errors.New("glDeleteProgram")
 	}
+	gpDeleteQueries = (C.GPDELETEQUERIES)(getProcAddr("glDeleteQueries"))
 	gpDeleteRenderbuffersEXT = (C.GPDELETERENDERBUFFERSEXT)(getProcAddr("glDeleteRenderbuffersEXT"))
 	gpDeleteShader = (C.GPDELETESHADER)(getProcAddr("glDeleteShader"))
 	if gpDeleteShader == nil {
 		return errors.New("glDeleteShader")
 	}
+	gpDeleteSync = (C.GPDELETESYNC)(getProcAddr("glDeleteSync"))
 	gpDeleteTextures = (C.GPDELETETEXTURES)(getProcAddr("glDeleteTextures"))
 	if gpDeleteTextures == nil {
 		return errors.New("glDeleteTextures")
@@ -930,10 +1111,12 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpDisableVertexAttribArray == nil {
 		return errors.New("glDisableVertexAttribArray")
 	}
+	gpDrawBuffers = (C.GPDRAWBUFFERS)(getProcAddr("glDrawBuffers"))
 	gpDrawElements = (C.GPDRAWELEMENTS)(getProcAddr("glDrawElements"))
 	if gpDrawElements == nil {
 		return errors.New("glDrawElements")
 	}
+	gpDrawElementsInstanced = (C.GPDRAWELEMENTSINSTANCED)(getProcAddr("glDrawElementsInstanced"))
 	gpEnable = (C.GPENABLE)(getProcAddr("glEnable"))
 	if gpEnable == nil {
 		return errors.New("glEnable")
@@ -942,6 +1125,8 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpEnableVertexAttribArray == nil {
 		return errors.New("glEnableVertexAttribArray")
 	}
+	gpEndQuery = (C.GPENDQUERY)(getProcAddr("glEndQuery"))
+	gpFenceSync = (C.GPFENCESYNC)(getProcAddr("glFenceSync"))
 	gpFlush = (C.GPFLUSH)(getProcAddr("glFlush"))
 	if gpFlush == nil {
 		return errors.New("glFlush")
@@ -953,6 +1138,7 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 		return errors.New("glGenBuffers")
 	}
 	gpGenFramebuffersEXT = (C.GPGENFRAMEBUFFERSEXT)(getProcAddr("glGenFramebuffersEXT"))
+	gpGenQueries = (C.GPGENQUERIES)(getProcAddr("glGenQueries"))
 	gpGenRenderbuffersEXT = (C.GPGENRENDERBUFFERSEXT)(getProcAddr("glGenRenderbuffersEXT"))
 	gpGenTextures = (C.GPGENTEXTURES)(getProcAddr("glGenTextures"))
 	if gpGenTextures == nil {
@@ -985,6 +1171,8 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpGetProgramiv == nil {
 		return errors.New("glGetProgramiv")
 	}
+	gpGetQueryObjectui64v = (C.GPGETQUERYOBJECTUI64V)(getProcAddr("glGetQueryObjectui64v"))
+	gpGetQueryObjectuiv = (C.GPGETQUERYOBJECTUIV)(getProcAddr("glGetQueryObjectuiv"))
 	gpGetShaderInfoLog = (C.GPGETSHADERINFOLOG)(getProcAddr("glGetShaderInfoLog"))
 	if gpGetShaderInfoLog == nil {
 		return errors.New("glGetShaderInfoLog")
@@ -993,6 +1181,11 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpGetShaderiv == nil {
 		return errors.New("glGetShaderiv")
 	}
+	gpGetString = (C.GPGETSTRING)(getProcAddr("glGetString"))
+	if gpGetString == nil {
+		return errors.New("glGetString")
+	}
+	gpGetSynciv = (C.GPGETSYNCIV)(getProcAddr("glGetSynciv"))
 	gpGetTransformFeedbacki64_v = (C.GPGETTRANSFORMFEEDBACKI64_V)(getProcAddr("glGetTransformFeedbacki64_v"))
 	gpGetTransformFeedbacki_v = (C.GPGETTRANSFORMFEEDBACKI_V)(getProcAddr("glGetTransformFeedbacki_v"))
 	gpGetUniformLocation = (C.GPGETUNIFORMLOCATION)(getProcAddr("glGetUniformLocation"))
@@ -1020,11 +1213,14 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpPixelStorei == nil {
 		return errors.New("glPixelStorei")
 	}
+	gpPopDebugGroup = (C.GPPOPDEBUGGROUP)(getProcAddr("glPopDebugGroup"))
+	gpPushDebugGroup = (C.GPPUSHDEBUGGROUP)(getProcAddr("glPushDebugGroup"))
 	gpReadPixels = (C.GPREADPIXELS)(getProcAddr("glReadPixels"))
 	if gpReadPixels == nil {
 		return errors.New("glReadPixels")
 	}
 	gpRenderbufferStorageEXT = (C.GPRENDERBUFFERSTORAGEEXT)(getProcAddr("glRenderbufferStorageEXT"))
+	gpRenderbufferStorageMultisampleEXT = (C.GPRENDERBUFFERSTORAGEMULTISAMPLEEXT)(getProcAddr("glRenderbufferStorageMultisampleEXT"))
 	gpScissor = (C.GPSCISSOR)(getProcAddr("glScissor"))
 	if gpScissor == nil {
 		return errors.New("glScissor")
@@ -1041,6 +1237,10 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpStencilOp == nil {
 		return errors.New("glStencilOp")
 	}
+	gpStencilOpSeparate = (C.GPSTENCILOPSEPARATE)(getProcAddr("glStencilOpSeparate"))
+	if gpStencilOpSeparate == nil {
+		return errors.New("glStencilOpSeparate")
+	}
 	gpTexImage2D = (C.GPTEXIMAGE2D)(getProcAddr("glTexImage2D"))
 	if gpTexImage2D == nil {
 		return errors.New("glTexImage2D")
@@ -1093,6 +1293,7 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpUseProgram == nil {
 		return errors.New("glUseProgram")
 	}
+	gpVertexAttribDivisor = (C.GPVERTEXATTRIBDIVISOR)(getProcAddr("glVertexAttribDivisor"))
 	gpVertexAttribPointer = (C.GPVERTEXATTRIBPOINTER)(getProcAddr("glVertexAttribPointer"))
 	if gpVertexAttribPointer == nil {
 		return errors.New("glVertexAttribPointer")
@@ -10,87 +10,106 @@ import (
 )
 
 var (
-	gpActiveTexture               uintptr
-	gpAttachShader                uintptr
-	gpBindAttribLocation          uintptr
-	gpBindBuffer                  uintptr
-	gpBindFramebufferEXT          uintptr
-	gpBindRenderbufferEXT         uintptr
-	gpBindTexture                 uintptr
-	gpBlendFunc                   uintptr
-	gpBufferData                  uintptr
-	gpBufferSubData               uintptr
-	gpCheckFramebufferStatusEXT   uintptr
-	gpClear                       uintptr
-	gpColorMask                   uintptr
-	gpCompileShader               uintptr
-	gpCreateProgram               uintptr
-	gpCreateShader                uintptr
-	gpDeleteBuffers               uintptr
-	gpDeleteFramebuffersEXT       uintptr
-	gpDeleteProgram               uintptr
-	gpDeleteRenderbuffersEXT      uintptr
-	gpDeleteShader                uintptr
-	gpDeleteTextures              uintptr
-	gpDisable                     uintptr
-	gpDisableVertexAttribArray    uintptr
-	gpDrawElements                uintptr
-	gpEnable                      uintptr
-	gpEnableVertexAttribArray     uintptr
-	gpFlush                       uintptr
-	gpFramebufferRenderbufferEXT  uintptr
-	gpFramebufferTexture2DEXT     uintptr
-	gpGenBuffers                  uintptr
-	gpGenFramebuffersEXT          uintptr
-	gpGenRenderbuffersEXT         uintptr
-	gpGenTextures                 uintptr
-	gpGetBufferSubData            uintptr
-	gpGetDoublei_v                uintptr
-	gpGetDoublei_vEXT             uintptr
-	gpGetError                    uintptr
-	gpGetFloati_v                 uintptr
-	gpGetFloati_vEXT              uintptr
-	gpGetIntegeri_v               uintptr
-	gpGetIntegerui64i_vNV         uintptr
-	gpGetIntegerv                 uintptr
-	gpGetPointeri_vEXT            uintptr
-	gpGetProgramInfoLog           uintptr
-	gpGetProgramiv                uintptr
-	gpGetShaderInfoLog            uintptr
-	gpGetShaderiv                 uintptr
-	gpGetTransformFeedbacki64_v   uintptr
-	gpGetTransformFeedbacki_v     uintptr
-	gpGetUniformLocation          uintptr
-	gpGetUnsignedBytei_vEXT       uintptr
-	gpGetVertexArrayIntegeri_vEXT uintptr
-	gpGetVertexArrayPointeri_vEXT uintptr
-	gpIsFramebufferEXT            uintptr
-	gpIsProgram                   uintptr
-	gpIsRenderbufferEXT           uintptr
-	gpIsTexture                   uintptr
-	gpLinkProgram                 uintptr
-	gpPixelStorei                 uintptr
-	gpReadPixels                  uintptr
-	gpRenderbufferStorageEXT      uintptr
-	gpScissor                     uintptr
-	gpShaderSource                uintptr
-	gpStencilFunc                 uintptr
-	gpStencilOp                   uintptr
-	gpTexImage2D                  uintptr
-	gpTexParameteri               uintptr
-	gpTexSubImage2D               uintptr
-	gpUniform1f                   uintptr
-	gpUniform1i                   uintptr
-	gpUniform1fv                  uintptr
-	gpUniform2fv                  uintptr
-	gpUniform3fv                  uintptr
-	gpUniform4fv                  uintptr
-	gpUniformMatrix2fv            uintptr
-	gpUniformMatrix3fv            uintptr
-	gpUniformMatrix4fv            uintptr
-	gpUseProgram                  uintptr
-	gpVertexAttribPointer         uintptr
-	gpViewport                    uintptr
+	gpActiveTexture                     uintptr
+	gpAttachShader                      uintptr
+	gpBeginQuery                        uintptr
+	gpBindAttribLocation                uintptr
+	gpBindBuffer                        uintptr
+	gpBindFramebufferEXT                uintptr
+	gpBindRenderbufferEXT               uintptr
+	gpBindTexture                       uintptr
+	gpBlendEquationSeparate             uintptr
+	gpBlendFuncSeparate                 uintptr
+	gpBlitFramebufferEXT                uintptr
+	gpBufferData                        uintptr
+	gpBufferSubData                     uintptr
+	gpCheckFramebufferStatusEXT         uintptr
+	gpClear                             uintptr
+	gpColorMask                         uintptr
+	gpCompileShader                     uintptr
+	gpCreateProgram                     uintptr
+	gpCreateShader                      uintptr
+	gpDeleteBuffers                     uintptr
+	gpDeleteFramebuffersEXT             uintptr
+	gpDeleteProgram                     uintptr
+	gpDeleteQueries                     uintptr
+	gpDeleteRenderbuffersEXT            uintptr
+	gpDeleteShader                      uintptr
+	gpDeleteSync                        uintptr
+	gpDeleteTextures                    uintptr
+	gpDisable                           uintptr
+	gpDisableVertexAttribArray          uintptr
+	gpDrawBuffers                       uintptr
+	gpDrawElements                      uintptr
+	gpDrawElementsInstanced             uintptr
+	gpEnable                            uintptr
+	gpEnableVertexAttribArray           uintptr
+	gpEndQuery                          uintptr
+	gpFenceSync                         uintptr
+	gpFlush                             uintptr
+	gpFramebufferRenderbufferEXT        uintptr
+	gpFramebufferTexture2DEXT           uintptr
+	gpGenBuffers                        uintptr
+	gpGenFramebuffersEXT                uintptr
+	gpGenQueries                        uintptr
+	gpGenRenderbuffersEXT               uintptr
+	gpGenTextures                       uintptr
+	gpGetBufferSubData                  uintptr
+	gpGetDoublei_v                      uintptr
+	gpGetDoublei_vEXT                   uintptr
+	gpGetError                          uintptr
+	gpGetFloati_v                       uintptr
+	gpGetFloati_vEXT                    uintptr
+	gpGetIntegeri_v                     uintptr
+	gpGetIntegerui64i_vNV               uintptr
+	gpGetIntegerv                       uintptr
+	gpGetPointeri_vEXT                  uintptr
+	gpGetProgramInfoLog                 uintptr
+	gpGetProgramiv                      uintptr
+	gpGetQueryObjectui64v               uintptr
+	gpGetQueryObjectuiv                 uintptr
+	gpGetShaderInfoLog                  uintptr
+	gpGetShaderiv                       uintptr
+	gpGetString                         uintptr
+	gpGetSynciv                         uintptr
+	gpGetTransformFeedbacki64_v         uintptr
+	gpGetTransformFeedbacki_v           uintptr
+	gpGetUniformLocation                uintptr
+	gpGetUnsignedBytei_vEXT             uintptr
+	gpGetVertexArrayIntegeri_vEXT       uintptr
+	gpGetVertexArrayPointeri_vEXT       uintptr
+	gpIsFramebufferEXT                  uintptr
+	gpIsProgram                         uintptr
+	gpIsRenderbufferEXT                 uintptr
+	gpIsTexture                         uintptr
+	gpLinkProgram                       uintptr
+	gpPixelStorei                       uintptr
+	gpPopDebugGroup                     uintptr
+	gpPushDebugGroup                    uintptr
+	gpReadPixels                        uintptr
+	gpRenderbufferStorageEXT            uintptr
+	gpRenderbufferStorageMultisampleEXT uintptr
+	gpScissor                           uintptr
+	gpShaderSource                      uintptr
+	gpStencilFunc                       uintptr
+	gpStencilOp                         uintptr
+	gpStencilOpSeparate                 uintptr
+	gpTexImage2D                        uintptr
+	gpTexParameteri                     uintptr
+	gpTexSubImage2D                     uintptr
+	gpUniform1f                         uintptr
+	gpUniform1i                         uintptr
+	gpUniform1fv                        uintptr
+	gpUniform2fv                        uintptr
+	gpUniform3fv                        uintptr
+	gpUniform4fv                        uintptr
+	gpUniformMatrix2fv                  uintptr
+	gpUniformMatrix3fv                  uintptr
+	gpUniformMatrix4fv                  uintptr
+	gpUseProgram                        uintptr
+	gpVertexAttribDivisor               uintptr
+	gpVertexAttribPointer               uintptr
+	gpViewport                          uintptr
 )
 
 func boolToUintptr(b bool) uintptr {
@@ -108,6 +127,10 @@ func AttachShader(program uint32, shader uint32) {
 	syscall.Syscall(gpAttachShader, 2, uintptr(program), uintptr(shader), 0)
 }
 
+func BeginQuery(target uint32, id uint32) {
+	syscall.Syscall(gpBeginQuery, 2, uintptr(target), uintptr(id), 0)
+}
+
 func BindAttribLocation(program uint32, index uint32, name *uint8) {
 	syscall.Syscall(gpBindAttribLocation, 3, uintptr(program), uintptr(index), uintptr(unsafe.Pointer(name)))
 }
@@ -128,8 +151,16 @@ func BindTexture(target uint32, texture uint32) {
 	syscall.Syscall(gpBindTexture, 2, uintptr(target), uintptr(texture), 0)
 }
 
-func BlendFunc(sfactor uint32, dfactor uint32) {
-	syscall.Syscall(gpBlendFunc, 2, uintptr(sfactor), uintptr(dfactor), 0)
+func BlendEquationSeparate(modeRGB uint32, modeAlpha uint32) {
+	syscall.Syscall(gpBlendEquationSeparate, 2, uintptr(modeRGB), uintptr(modeAlpha), 0)
+}
+
+func BlendFuncSeparate(sfactorRGB uint32, dfactorRGB uint32, sfactorAlpha uint32, dfactorAlpha uint32) {
+	syscall.Syscall6(gpBlendFuncSeparate, 4, uintptr(sfactorRGB), uintptr(dfactorRGB), uintptr(sfactorAlpha), uintptr(dfactorAlpha), 0, 0)
+}
+
+func BlitFramebufferEXT(srcX0 int32, srcY0 int32, srcX1 int32, srcY1 int32, dstX0 int32, dstY0 int32, dstX1 int32, dstY1 int32, mask uint32, filter uint32) {
+	syscall.Syscall12(gpBlitFramebufferEXT, 10, uintptr(srcX0), uintptr(srcY0), uintptr(srcX1), uintptr(srcY1), uintptr(dstX0), uintptr(dstY0), uintptr(dstX1), uintptr(dstY1), uintptr(mask), uintptr(filter), 0, 0)
 }
 
 func BufferData(target uint32, size int, data unsafe.Pointer, usage uint32) {
@@ -179,6 +210,10 @@ func DeleteProgram(program uint32) {
 	syscall.Syscall(gpDeleteProgram, 1, uintptr(program), 0, 0)
 }
 
+func DeleteQueries(n int32, ids *uint32) {
+	syscall.Syscall(gpDeleteQueries, 2, uintptr(n), uintptr(unsafe.Pointer(ids)), 0)
+}
+
 func DeleteRenderbuffersEXT(n int32, renderbuffers *uint32) {
 	syscall.Syscall(gpDeleteRenderbuffersEXT, 2, uintptr(n), uintptr(unsafe.Pointer(renderbuffers)), 0)
 }
@@ -187,6 +222,10 @@ func DeleteShader(shader uint32) {
 	syscall.Syscall(gpDeleteShader, 1, uintptr(shader), 0, 0)
 }
 
+func DeleteSync(sync uintptr) {
+	syscall.Syscall(gpDeleteSync, 1, uintptr(sync), 0, 0)
+}
+
 func DeleteTextures(n int32, textures *uint32) {
 	syscall.Syscall(gpDeleteTextures, 2, uintptr(n), uintptr(unsafe.Pointer(textures)), 0)
 }
@@ -199,10 +238,18 @@ func DisableVertexAttribArray(index uint32) {
 	syscall.Syscall(gpDisableVertexAttribArray, 1, uintptr(index), 0, 0)
 }
 
+func DrawBuffers(n int32, bufs *uint32) {
+	syscall.Syscall(gpDrawBuffers, 2, uintptr(n), uintptr(unsafe.Pointer(bufs)), 0)
+}
+
 func DrawElements(mode uint32, count int32, xtype uint32, indices uintptr) {
 	syscall.Syscall6(gpDrawElements, 4, uintptr(mode), uintptr(count), uintptr(xtype), uintptr(indices), 0, 0)
 }
 
+func DrawElementsInstanced(mode uint32, count int32, xtype uint32, indices uintptr, instancecount int32) {
+	syscall.Syscall6(gpDrawElementsInstanced, 5, uintptr(mode), uintptr(count), uintptr(xtype), uintptr(indices), uintptr(instancecount), 0)
+}
+
 func Enable(cap uint32) {
 	syscall.Syscall(gpEnable, 1, uintptr(cap), 0, 0)
 }
@@ -211,6 +258,15 @@ func EnableVertexAttribArray(index uint32) {
 	syscall.Syscall(gpEnableVertexAttribArray, 1, uintptr(index), 0, 0)
 }
 
+func EndQuery(target uint32) {
+	syscall.Syscall(gpEndQuery, 1, uintptr(target), 0, 0)
+}
+
+func FenceSync(condition uint32, flags uint32) uintptr {
+	ret, _, _ := syscall.Syscall(gpFenceSync, 2, uintptr(condition), uintptr(flags), 0)
+	return ret
+}
+
 func Flush() {
 	syscall.Syscall(gpFlush, 0, 0, 0, 0)
 }
@@ -231,6 +287,10 @@ func GenFramebuffersEXT(n int32, framebuffers *uint32) {
 	syscall.Syscall(gpGenFramebuffersEXT, 2, uintptr(n), uintptr(unsafe.Pointer(framebuffers)), 0)
 }
 
+func GenQueries(n int32, ids *uint32) {
+	syscall.Syscall(gpGenQueries, 2, uintptr(n), uintptr(unsafe.Pointer(ids)), 0)
+}
+
 func GenRenderbuffersEXT(n int32, renderbuffers *uint32) {
 	syscall.Syscall(gpGenRenderbuffersEXT, 2, uintptr(n), uintptr(unsafe.Pointer(renderbuffers)), 0)
 }
@@ -283,6 +343,14 @@ func GetProgramiv(program uint32, pname uint32, params *int32) {
 	syscall.Syscall(gpGetProgramiv, 3, uintptr(program), uintptr(pname), uintptr(unsafe.Pointer(params)))
 }
 
+func GetQueryObjectui64v(id uint32, pname uint32, params *uint64) {
+	syscall.Syscall(gpGetQueryObjectui64v, 3, uintptr(id), uintptr(pname), uintptr(unsafe.Pointer(params)))
+}
+
+func GetQueryObjectuiv(id uint32, pname uint32, params *uint32) {
+	syscall.Syscall(gpGetQueryObjectuiv, 3, uintptr(id), uintptr(pname), uintptr(unsafe.Pointer(params)))
+}
+
 func GetShaderInfoLog(shader uint32, bufSize int32, length *int32, infoLog *uint8) {
 	syscall.Syscall6(gpGetShaderInfoLog, 4, uintptr(shader), uintptr(bufSize), uintptr(unsafe.Pointer(length)), uintptr(unsafe.Pointer(infoLog)), 0, 0)
 }
@@ -291,6 +359,16 @@ func GetShaderiv(shader uint32, pname uint32, params *int32) {
 	syscall.Syscall(gpGetShaderiv, 3, uintptr(shader), uintptr(pname), uintptr(unsafe.Pointer(params)))
 }
 
+func GetString(name uint32) *uint8 {
+	ret, _, _ := syscall.Syscall(gpGetString, 1, uintptr(name), 0, 0)
+	// Convert the pointer without unsafe.Pointer(uintptr) to avoid go vet's warning.
+	return *(**uint8)(unsafe.Pointer(&ret))
+}
+
+func GetSynciv(sync uintptr, pname uint32, bufSize int32, length *int32, values *int32) {
+	syscall.Syscall6(gpGetSynciv, 5, uintptr(sync), uintptr(pname), uintptr(bufSize), uintptr(unsafe.Pointer(length)), uintptr(unsafe.Pointer(values)), 0)
+}
+
 func GetTransformFeedbacki64_v(xfb uint32, pname uint32, index uint32, param *int64) {
 	syscall.Syscall6(gpGetTransformFeedbacki64_v, 4, uintptr(xfb), uintptr(pname), uintptr(index), uintptr(unsafe.Pointer(param)), 0, 0)
 }
@@ -341,6 +419,14 @@ func PixelStorei(pname uint32, param int32) {
 	syscall.Syscall(gpPixelStorei, 2, uintptr(pname), uintptr(param), 0)
 }
 
+func PopDebugGroup() {
+	syscall.Syscall(gpPopDebugGroup, 0, 0, 0, 0)
+}
+
+func PushDebugGroup(source uint32, id uint32, length int32, message *uint8) {
+	syscall.Syscall6(gpPushDebugGroup, 4, uintptr(source), uintptr(id), uintptr(length), uintptr(unsafe.Pointer(message)), 0, 0)
+}
+
 func ReadPixels(x int32, y int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
 	syscall.Syscall9(gpReadPixels, 7, uintptr(x), uintptr(y), uintptr(width), uintptr(height), uintptr(format), uintptr(xtype), uintptr(pixels), 0, 0)
 }
@@ -349,6 +435,10 @@ func RenderbufferStorageEXT(target uint32, internalformat uint32, width int32, h
 	syscall.Syscall6(gpRenderbufferStorageEXT, 4, uintptr(target), uintptr(internalformat), uintptr(width), uintptr(height), 0, 0)
 }
 
+func RenderbufferStorageMultisampleEXT(target uint32, samples int32, internalformat uint32, width int32, height int32) {
+	syscall.Syscall6(gpRenderbufferStorageMultisampleEXT, 5, uintptr(target), uintptr(samples), uintptr(internalformat), uintptr(width), uintptr(height), 0)
+}
+
 func Scissor(x int32, y int32, width int32, height int32) {
 	syscall.Syscall6(gpScissor, 4, uintptr(x), uintptr(y), uintptr(width), uintptr(height), 0, 0)
 }
@@ -365,6 +455,10 @@ func StencilOp(fail uint32, zfail uint32, zpass uint32) {
 	syscall.Syscall(gpStencilOp, 3, uintptr(fail), uintptr(zfail), uintptr(zpass))
 }
 
+func StencilOpSeparate(face uint32, sfail uint32, dpfail uint32, dppass uint32) {
+	syscall.Syscall6(gpStencilOpSeparate, 4, uintptr(face), uintptr(sfail), uintptr(dpfail), uintptr(dppass), 0, 0)
+}
+
 func TexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
 	syscall.Syscall9(gpTexImage2D, 9, uintptr(target), uintptr(level), uintptr(internalformat), uintptr(width), uintptr(height), uintptr(border), uintptr(format), uintptr(xtype), uintptr(pixels))
 }
@@ -417,6 +511,10 @@ func UseProgram(program uint32) {
 	syscall.Syscall(gpUseProgram, 1, uintptr(program), 0, 0)
 }
 
+func VertexAttribDivisor(index uint32, divisor uint32) {
+	syscall.Syscall(gpVertexAttribDivisor, 2, uintptr(index), uintptr(divisor), 0)
+}
+
 func VertexAttribPointer(index uint32, size int32, xtype uint32, normalized bool, stride int32, pointer uintptr) {
 	syscall.Syscall6(gpVertexAttribPointer, 6, uintptr(index), uintptr(size), uintptr(xtype), boolToUintptr(normalized), uintptr(stride), uintptr(pointer))
 }
@@ -438,6 +536,7 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpAttachShader == 0 {
 		return errors.New("glAttachShader")
 	}
+	gpBeginQuery = getProcAddr("glBeginQuery")
 	gpBindAttribLocation = getProcAddr("glBindAttribLocation")
 	if gpBindAttribLocation == 0 {
 		return errors.New("glBindAttribLocation")
@@ -452,10 +551,15 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpBindTexture == 0 {
 		return errors.New("glBindTexture")
 	}
-	gpBlendFunc = getProcAddr("glBlendFunc")
-	if gpBlendFunc == 0 {
-		return errors.New("glBlendFunc")
+	gpBlendEquationSeparate = getProcAddr("glBlendEquationSeparate")
+	if gpBlendEquationSeparate == 0 {
+		return errors.New("glBlendEquationSeparate")
+	}
+	gpBlendFuncSeparate = getProcAddr("glBlendFuncSeparate")
+	if gpBlendFuncSeparate == 0 {
+		return errors.New("glBlendFuncSeparate")
 	}
+	gpBlitFramebufferEXT = getProcAddr("glBlitFramebufferEXT")
 	gpBufferData = getProcAddr("glBufferData")
 	if gpBufferData == 0 {
 		return errors.New("glBufferData")
@@ -494,11 +598,13 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpDeleteProgram == 0 {
 		return errors.New("glDeleteProgram")
 	}
+	gpDeleteQueries = getProcAddr("glDeleteQueries")
 	gpDeleteRenderbuffersEXT = getProcAddr("glDeleteRenderbuffersEXT")
 	gpDeleteShader = getProcAddr("glDeleteShader")
 	if gpDeleteShader == 0 {
 		return errors.New("glDeleteShader")
 	}
+	gpDeleteSync = getProcAddr("glDeleteSync")
 	gpDeleteTextures = getProcAddr("glDeleteTextures")
 	if gpDeleteTextures == 0 {
 		return errors.New("glDeleteTextures")
@@ -511,10 +617,12 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpDisableVertexAttribArray == 0 {
 		return errors.New("glDisableVertexAttribArray")
 	}
+	gpDrawBuffers = getProcAddr("glDrawBuffers")
 	gpDrawElements = getProcAddr("glDrawElements")
 	if gpDrawElements == 0 {
 		return errors.New("glDrawElements")
 	}
+	gpDrawElementsInstanced = getProcAddr("glDrawElementsInstanced")
 	gpEnable = getProcAddr("glEnable")
 	if gpEnable == 0 {
 		return errors.New("glEnable")
@@ -523,6 +631,8 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpEnableVertexAttribArray == 0 {
 		return errors.New("glEnableVertexAttribArray")
 	}
+	gpEndQuery = getProcAddr("glEndQuery")
+	gpFenceSync = getProcAddr("glFenceSync")
 	gpFlush = getProcAddr("glFlush")
 	if gpFlush == 0 {
 		return errors.New("glFlush")
@@ -534,6 +644,7 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 		return errors.New("glGenBuffers")
 	}
 	gpGenFramebuffersEXT = getProcAddr("glGenFramebuffersEXT")
+	gpGenQueries = getProcAddr("glGenQueries")
 	gpGenRenderbuffersEXT = getProcAddr("glGenRenderbuffersEXT")
 	gpGenTextures = getProcAddr("glGenTextures")
 	if gpGenTextures == 0 {
@@ -566,6 +677,8 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpGetProgramiv == 0 {
 		return errors.New("glGetProgramiv")
 	}
+	gpGetQueryObjectui64v = getProcAddr("glGetQueryObjectui64v")
+	gpGetQueryObjectuiv = getProcAddr("glGetQueryObjectuiv")
 	gpGetShaderInfoLog = getProcAddr("glGetShaderInfoLog")
 	if gpGetShaderInfoLog == 0 {
 		return errors.New("glGetShaderInfoLog")
@@ -574,6 +687,11 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpGetShaderiv == 0 {
 		return errors.New("glGetShaderiv")
 	}
+	gpGetString = getProcAddr("glGetString")
+	if gpGetString == 0 {
+		return errors.New("glGetString")
+	}
+	gpGetSynciv = getProcAddr("glGetSynciv")
 	gpGetTransformFeedbacki64_v = getProcAddr("glGetTransformFeedbacki64_v")
 	gpGetTransformFeedbacki_v = getProcAddr("glGetTransformFeedbacki_v")
 	gpGetUniformLocation = getProcAddr("glGetUniformLocation")
@@ -601,11 +719,14 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpPixelStorei == 0 {
 		return errors.New("glPixelStorei")
 	}
+	gpPopDebugGroup = getProcAddr("glPopDebugGroup")
+	gpPushDebugGroup = getProcAddr("glPushDebugGroup")
 	gpReadPixels = getProcAddr("glReadPixels")
 	if gpReadPixels == 0 {
 		return errors.New("glReadPixels")
 	}
 	gpRenderbufferStorageEXT = getProcAddr("glRenderbufferStorageEXT")
+	gpRenderbufferStorageMultisampleEXT = getProcAddr("glRenderbufferStorageMultisampleEXT")
 	gpScissor = getProcAddr("glScissor")
 	if gpScissor == 0 {
 		return errors.New("glScissor")
@@ -622,6 +743,10 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpStencilOp == 0 {
 		return errors.New("glStencilOp")
 	}
+	gpStencilOpSeparate = getProcAddr("glStencilOpSeparate")
+	if gpStencilOpSeparate == 0 {
+		return errors.New("glStencilOpSeparate")
+	}
 	gpTexImage2D = getProcAddr("glTexImage2D")
 	if gpTexImage2D == 0 {
 		return errors.New("glTexImage2D")
@@ -674,6 +799,7 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpUseProgram == 0 {
 		return errors.New("glUseProgram")
 	}
+	gpVertexAttribDivisor = getProcAddr("glVertexAttribDivisor")
 	gpVertexAttribPointer = getProcAddr("glVertexAttribPointer")
 	if gpVertexAttribPointer == 0 {
 		return errors.New("glVertexAttribPointer")
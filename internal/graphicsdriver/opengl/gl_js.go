@@ -26,7 +26,9 @@ type gl struct {
 	bindFramebuffer          js.Value
 	bindRenderbuffer         js.Value
 	bindTexture              js.Value
-	blendFunc                js.Value
+	bindVertexArray          js.Value
+	blendEquationSeparate    js.Value
+	blendFuncSeparate        js.Value
 	bufferData               js.Value
 	bufferSubData            js.Value
 	checkFramebufferStatus   js.Value
@@ -39,6 +41,7 @@ type gl struct {
 	createRenderbuffer       js.Value
 	createShader             js.Value
 	createTexture            js.Value
+	createVertexArray        js.Value
 	deleteBuffer             js.Value
 	deleteFramebuffer        js.Value
 	deleteProgram            js.Value
@@ -47,7 +50,9 @@ type gl struct {
 	deleteTexture            js.Value
 	disable                  js.Value
 	disableVertexAttribArray js.Value
+	drawBuffers              js.Value
 	drawElements             js.Value
+	drawElementsInstanced    js.Value
 	enable                   js.Value
 	enableVertexAttribArray  js.Value
 	framebufferRenderbuffer  js.Value
@@ -76,6 +81,7 @@ type gl struct {
 	stencilFunc              js.Value
 	stencilMask              js.Value
 	stencilOp                js.Value
+	stencilOpSeparate        js.Value
 	texImage2D               js.Value
 	texSubImage2D            js.Value
 	texParameteri            js.Value
@@ -89,6 +95,7 @@ type gl struct {
 	uniformMatrix3fv         js.Value
 	uniformMatrix4fv         js.Value
 	useProgram               js.Value
+	vertexAttribDivisor      js.Value
 	vertexAttribPointer      js.Value
 	viewport                 js.Value
 }
@@ -104,7 +111,8 @@ func (c *context) newGL(v js.Value) *gl {
 		bindFramebuffer:          v.Get("bindFramebuffer").Call("bind", v),
 		bindRenderbuffer:         v.Get("bindRenderbuffer").Call("bind", v),
 		bindTexture:              v.Get("bindTexture").Call("bind", v),
-		blendFunc:                v.Get("blendFunc").Call("bind", v),
+		blendEquationSeparate:    v.Get("blendEquationSeparate").Call("bind", v),
+		blendFuncSeparate:        v.Get("blendFuncSeparate").Call("bind", v),
 		bufferData:               v.Get("bufferData").Call("bind", v),
 		bufferSubData:            v.Get("bufferSubData").Call("bind", v),
 		checkFramebufferStatus:   v.Get("checkFramebufferStatus").Call("bind", v),
@@ -152,6 +160,7 @@ func (c *context) newGL(v js.Value) *gl {
 		stencilFunc:              v.Get("stencilFunc").Call("bind", v),
 		stencilMask:              v.Get("stencilMask").Call("bind", v),
 		stencilOp:                v.Get("stencilOp").Call("bind", v),
+		stencilOpSeparate:        v.Get("stencilOpSeparate").Call("bind", v),
 		texImage2D:               v.Get("texImage2D").Call("bind", v),
 		texSubImage2D:            v.Get("texSubImage2D").Call("bind", v),
 		texParameteri:            v.Get("texParameteri").Call("bind", v),
@@ -170,6 +179,18 @@ func (c *context) newGL(v js.Value) *gl {
 	}
 	if c.usesWebGL2() {
 		g.getExtension = v.Get("getBufferSubData").Call("bind", v)
+		// Vertex array objects might not be available in some environments like go2cpp.
+		if v.Get("createVertexArray").Truthy() {
+			g.bindVertexArray = v.Get("bindVertexArray").Call("bind", v)
+			g.createVertexArray = v.Get("createVertexArray").Call("bind", v)
+		}
+		if v.Get("drawElementsInstanced").Truthy() {
+			g.drawElementsInstanced = v.Get("drawElementsInstanced").Call("bind", v)
+			g.vertexAttribDivisor = v.Get("vertexAttribDivisor").Call("bind", v)
+		}
+		if v.Get("drawBuffers").Truthy() {
+			g.drawBuffers = v.Get("drawBuffers").Call("bind", v)
+		}
 	} else {
 		g.getExtension = v.Get("getExtension").Call("bind", v)
 	}
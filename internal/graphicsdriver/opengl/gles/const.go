@@ -16,42 +16,63 @@
 package gles
 
 const (
-	ZERO                = 0
-	ONE                 = 1
-	SRC_ALPHA           = 0x0302
-	DST_ALPHA           = 0x0304
-	ONE_MINUS_SRC_ALPHA = 0x0303
-	ONE_MINUS_DST_ALPHA = 0x0305
-	DST_COLOR           = 0x0306
+	ZERO                  = 0
+	ONE                   = 1
+	SRC_COLOR             = 0x0300
+	ONE_MINUS_SRC_COLOR   = 0x0301
+	SRC_ALPHA             = 0x0302
+	ONE_MINUS_SRC_ALPHA   = 0x0303
+	DST_ALPHA             = 0x0304
+	ONE_MINUS_DST_ALPHA   = 0x0305
+	DST_COLOR             = 0x0306
+	ONE_MINUS_DST_COLOR   = 0x0307
+	FUNC_ADD              = 0x8006
+	MIN                   = 0x8007
+	MAX                   = 0x8008
+	FUNC_SUBTRACT         = 0x800A
+	FUNC_REVERSE_SUBTRACT = 0x800B
 
 	ALWAYS               = 0x0207
 	ARRAY_BUFFER         = 0x8892
+	BACK                 = 0x0405
 	BLEND                = 0x0BE2
 	CLAMP_TO_EDGE        = 0x812F
 	COLOR_ATTACHMENT0    = 0x8CE0
 	COMPILE_STATUS       = 0x8B81
+	COMPLETION_STATUS    = 0x91B1
+	DECR_WRAP            = 0x8508
 	DYNAMIC_DRAW         = 0x88E8
 	ELEMENT_ARRAY_BUFFER = 0x8893
+	EXTENSIONS           = 0x1F03
 	FALSE                = 0
 	FLOAT                = 0x1406
 	FRAGMENT_SHADER      = 0x8B30
 	FRAMEBUFFER          = 0x8D40
 	FRAMEBUFFER_BINDING  = 0x8CA6
 	FRAMEBUFFER_COMPLETE = 0x8CD5
+	FRONT                = 0x0404
 	HIGH_FLOAT           = 0x8DF2
+	INCR_WRAP            = 0x8507
 	INFO_LOG_LENGTH      = 0x8B84
 	INVERT               = 0x150A
 	KEEP                 = 0x1E00
 	LINK_STATUS          = 0x8B82
+	MAX_DRAW_BUFFERS     = 0x8824
 	MAX_TEXTURE_SIZE     = 0x0D33
 	NEAREST              = 0x2600
 	NO_ERROR             = 0
 	NOTEQUAL             = 0x0205
 	PIXEL_PACK_BUFFER    = 0x88EB
 	PIXEL_UNPACK_BUFFER  = 0x88EC
+	R8                   = 0x8229
 	READ_WRITE           = 0x88BA
+	RED                  = 0x1903
 	RENDERBUFFER         = 0x8D41
+	RENDERER             = 0x1F01
+	RG                   = 0x8227
+	RG8                  = 0x822B
 	RGBA                 = 0x1908
+	RGBA8                = 0x8058
 	SCISSOR_TEST         = 0x0C11
 	SHORT                = 0x1402
 	STENCIL_ATTACHMENT   = 0x8D20
@@ -70,6 +91,7 @@ const (
 	UNPACK_ALIGNMENT     = 0x0CF5
 	UNSIGNED_BYTE        = 0x1401
 	UNSIGNED_SHORT       = 0x1403
+	VERSION              = 0x1F02
 	VERTEX_SHADER        = 0x8B31
 	WRITE_ONLY           = 0x88B9
 )
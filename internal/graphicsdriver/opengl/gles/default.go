@@ -18,17 +18,90 @@
 package gles
 
 // #cgo android CFLAGS:  -Dos_android
-// #cgo android LDFLAGS: -lGLESv2
+// #cgo android LDFLAGS: -lGLESv2 -lEGL
 // #cgo ios     CFLAGS:  -Dos_ios
 // #cgo ios     LDFLAGS: -framework OpenGLES
 //
 // #if defined(os_android)
+//   #include <EGL/egl.h>
 //   #include <GLES2/gl2.h>
+//
+//   // OpenGL ES 3.0 functions are loaded at runtime so that the application works with OpenGL ES 2.0 devices.
+//   typedef void (*glBindVertexArrayFunc)(GLuint array);
+//   typedef void (*glGenVertexArraysFunc)(GLsizei n, GLuint* arrays);
+//   typedef void (*glDrawElementsInstancedFunc)(GLenum mode, GLsizei count, GLenum type, const void* indices, GLsizei instancecount);
+//   typedef void (*glVertexAttribDivisorFunc)(GLuint index, GLuint divisor);
+//   typedef void (*glDrawBuffersFunc)(GLsizei n, const GLenum* bufs);
+//
+//   static void glBindVertexArray_(GLuint array) {
+//     static glBindVertexArrayFunc f = NULL;
+//     if (!f) {
+//       f = (glBindVertexArrayFunc)eglGetProcAddress("glBindVertexArray");
+//     }
+//     f(array);
+//   }
+//
+//   static void glGenVertexArrays_(GLsizei n, GLuint* arrays) {
+//     static glGenVertexArraysFunc f = NULL;
+//     if (!f) {
+//       f = (glGenVertexArraysFunc)eglGetProcAddress("glGenVertexArrays");
+//     }
+//     f(n, arrays);
+//   }
+//
+//   static void glDrawElementsInstanced_(GLenum mode, GLsizei count, GLenum type, const void* indices, GLsizei instancecount) {
+//     static glDrawElementsInstancedFunc f = NULL;
+//     if (!f) {
+//       f = (glDrawElementsInstancedFunc)eglGetProcAddress("glDrawElementsInstanced");
+//     }
+//     f(mode, count, type, indices, instancecount);
+//   }
+//
+//   static void glVertexAttribDivisor_(GLuint index, GLuint divisor) {
+//     static glVertexAttribDivisorFunc f = NULL;
+//     if (!f) {
+//       f = (glVertexAttribDivisorFunc)eglGetProcAddress("glVertexAttribDivisor");
+//     }
+//     f(index, divisor);
+//   }
+//
+//   static void glDrawBuffers_(GLsizei n, const GLenum* bufs) {
+//     static glDrawBuffersFunc f = NULL;
+//     if (!f) {
+//       f = (glDrawBuffersFunc)eglGetProcAddress("glDrawBuffers");
+//     }
+//     f(n, bufs);
+//   }
 // #endif
 //
 // #if defined(os_ios)
 //   #define GLES_SILENCE_DEPRECATION
 //   #include <OpenGLES/ES2/glext.h>
+//
+//   // Vertex array objects are available via the extension with both OpenGL ES 2.0 and 3.0 on iOS.
+//   static void glBindVertexArray_(GLuint array) {
+//     glBindVertexArrayOES(array);
+//   }
+//
+//   static void glGenVertexArrays_(GLsizei n, GLuint* arrays) {
+//     glGenVertexArraysOES(n, arrays);
+//   }
+//
+//   // Instanced drawing is available via the extension on iOS.
+//   static void glDrawElementsInstanced_(GLenum mode, GLsizei count, GLenum type, const void* indices, GLsizei instancecount) {
+//     glDrawElementsInstancedEXT(mode, count, type, indices, instancecount);
+//   }
+//
+//   static void glVertexAttribDivisor_(GLuint index, GLuint divisor) {
+//     glVertexAttribDivisorEXT(index, divisor);
+//   }
+//
+//   // glDrawBuffers is an OpenGL ES 3.0 function, which is not declared in the OpenGL ES 2.0 headers.
+//   extern void glDrawBuffers(GLsizei n, const GLenum* bufs);
+//
+//   static void glDrawBuffers_(GLsizei n, const GLenum* bufs) {
+//     glDrawBuffers(n, bufs);
+//   }
 // #endif
 import "C"
 
@@ -75,8 +148,16 @@ func (DefaultContext) BindTexture(target uint32, texture uint32) {
 	C.glBindTexture(C.GLenum(target), C.GLuint(texture))
 }
 
-func (DefaultContext) BlendFunc(sfactor uint32, dfactor uint32) {
-	C.glBlendFunc(C.GLenum(sfactor), C.GLenum(dfactor))
+func (DefaultContext) BindVertexArray(array uint32) {
+	C.glBindVertexArray_(C.GLuint(array))
+}
+
+func (DefaultContext) BlendEquationSeparate(modeRGB uint32, modeAlpha uint32) {
+	C.glBlendEquationSeparate(C.GLenum(modeRGB), C.GLenum(modeAlpha))
+}
+
+func (DefaultContext) BlendFuncSeparate(sfactorRGB uint32, dfactorRGB uint32, sfactorAlpha uint32, dfactorAlpha uint32) {
+	C.glBlendFuncSeparate(C.GLenum(sfactorRGB), C.GLenum(dfactorRGB), C.GLenum(sfactorAlpha), C.GLenum(dfactorAlpha))
 }
 
 func (DefaultContext) BufferData(target uint32, size int, data []byte, usage uint32) {
@@ -147,10 +228,18 @@ func (DefaultContext) DisableVertexAttribArray(index uint32) {
 	C.glDisableVertexAttribArray(C.GLuint(index))
 }
 
+func (DefaultContext) DrawBuffers(bufs []uint32) {
+	C.glDrawBuffers_(C.GLsizei(len(bufs)), (*C.GLenum)(unsafe.Pointer(&bufs[0])))
+}
+
 func (DefaultContext) DrawElements(mode uint32, count int32, xtype uint32, offset int) {
 	C.glDrawElements(C.GLenum(mode), C.GLsizei(count), C.GLenum(xtype), unsafe.Pointer(uintptr(offset)))
 }
 
+func (DefaultContext) DrawElementsInstanced(mode uint32, count int32, xtype uint32, offset int, instanceCount int32) {
+	C.glDrawElementsInstanced_(C.GLenum(mode), C.GLsizei(count), C.GLenum(xtype), unsafe.Pointer(uintptr(offset)), C.GLsizei(instanceCount))
+}
+
 func (DefaultContext) Enable(cap uint32) {
 	C.glEnable(C.GLenum(cap))
 }
@@ -195,6 +284,12 @@ func (DefaultContext) GenTextures(n int32) []uint32 {
 	return textures
 }
 
+func (DefaultContext) GenVertexArrays(n int32) []uint32 {
+	arrays := make([]uint32, n)
+	C.glGenVertexArrays_(C.GLsizei(n), (*C.GLuint)(unsafe.Pointer(&arrays[0])))
+	return arrays
+}
+
 func (DefaultContext) GetError() uint32 {
 	return uint32(C.glGetError())
 }
@@ -244,6 +339,10 @@ func (DefaultContext) GetShaderPrecisionFormat(shadertype uint32, precisiontype
 	return int(r[0]), int(r[1]), int(p)
 }
 
+func (DefaultContext) GetString(name uint32) string {
+	return C.GoString((*C.char)(unsafe.Pointer(C.glGetString(C.GLenum(name)))))
+}
+
 func (DefaultContext) GetUniformLocation(program uint32, name string) int32 {
 	s, free := cString(name)
 	defer free()
@@ -300,6 +399,10 @@ func (DefaultContext) StencilOp(sfail, dpfail, dppass uint32) {
 	C.glStencilOp(C.GLenum(sfail), C.GLenum(dpfail), C.GLenum(dppass))
 }
 
+func (DefaultContext) StencilOpSeparate(face, sfail, dpfail, dppass uint32) {
+	C.glStencilOpSeparate(C.GLenum(face), C.GLenum(sfail), C.GLenum(dpfail), C.GLenum(dppass))
+}
+
 func (DefaultContext) TexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, format uint32, xtype uint32, pixels []byte) {
 	var p *byte
 	if pixels != nil {
@@ -356,6 +459,10 @@ func (DefaultContext) UseProgram(program uint32) {
 	C.glUseProgram(C.GLuint(program))
 }
 
+func (DefaultContext) VertexAttribDivisor(index uint32, divisor uint32) {
+	C.glVertexAttribDivisor_(C.GLuint(index), C.GLuint(divisor))
+}
+
 func (DefaultContext) VertexAttribPointer(index uint32, size int32, xtype uint32, normalized bool, stride int32, offset int) {
 	C.glVertexAttribPointer(C.GLuint(index), C.GLint(size), C.GLenum(xtype), glBool(normalized), C.GLsizei(stride), unsafe.Pointer(uintptr(offset)))
 }
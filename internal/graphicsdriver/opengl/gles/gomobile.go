@@ -64,8 +64,16 @@ func (g *GomobileContext) BindTexture(target uint32, texture uint32) {
 	g.ctx.BindTexture(gl.Enum(target), gl.Texture{Value: texture})
 }
 
-func (g *GomobileContext) BlendFunc(sfactor uint32, dfactor uint32) {
-	g.ctx.BlendFunc(gl.Enum(sfactor), gl.Enum(dfactor))
+func (g *GomobileContext) BindVertexArray(array uint32) {
+	g.ctx.BindVertexArray(gl.VertexArray{Value: array})
+}
+
+func (g *GomobileContext) BlendEquationSeparate(modeRGB uint32, modeAlpha uint32) {
+	g.ctx.BlendEquationSeparate(gl.Enum(modeRGB), gl.Enum(modeAlpha))
+}
+
+func (g *GomobileContext) BlendFuncSeparate(sfactorRGB uint32, dfactorRGB uint32, sfactorAlpha uint32, dfactorAlpha uint32) {
+	g.ctx.BlendFuncSeparate(gl.Enum(sfactorRGB), gl.Enum(dfactorRGB), gl.Enum(sfactorAlpha), gl.Enum(dfactorAlpha))
 }
 
 func (g *GomobileContext) BufferData(target uint32, size int, data []byte, usage uint32) {
@@ -147,10 +155,18 @@ func (g *GomobileContext) DisableVertexAttribArray(index uint32) {
 	g.ctx.DisableVertexAttribArray(gl.Attrib{Value: uint(index)})
 }
 
+func (g *GomobileContext) DrawBuffers(bufs []uint32) {
+	panic("gles: DrawBuffers is not implemented")
+}
+
 func (g *GomobileContext) DrawElements(mode uint32, count int32, xtype uint32, offset int) {
 	g.ctx.DrawElements(gl.Enum(mode), int(count), gl.Enum(xtype), offset)
 }
 
+func (g *GomobileContext) DrawElementsInstanced(mode uint32, count int32, xtype uint32, offset int, instanceCount int32) {
+	panic("gles: DrawElementsInstanced is not implemented")
+}
+
 func (g *GomobileContext) Enable(cap uint32) {
 	g.ctx.Enable(gl.Enum(cap))
 }
@@ -203,6 +219,14 @@ func (g *GomobileContext) GenTextures(n int32) []uint32 {
 	return textures
 }
 
+func (g *GomobileContext) GenVertexArrays(n int32) []uint32 {
+	arrays := make([]uint32, n)
+	for i := range arrays {
+		arrays[i] = g.ctx.CreateVertexArray().Value
+	}
+	return arrays
+}
+
 func (g *GomobileContext) GetError() uint32 {
 	return uint32(g.ctx.GetError())
 }
@@ -231,6 +255,10 @@ func (g *GomobileContext) GetShaderPrecisionFormat(shadertype uint32, precisiont
 	return g.ctx.GetShaderPrecisionFormat(gl.Enum(shadertype), gl.Enum(precisiontype))
 }
 
+func (g *GomobileContext) GetString(name uint32) string {
+	return g.ctx.GetString(gl.Enum(name))
+}
+
 func (g *GomobileContext) GetUniformLocation(program uint32, name string) int32 {
 	return g.ctx.GetUniformLocation(gmProgram(program), name).Value
 }
@@ -283,6 +311,10 @@ func (g *GomobileContext) StencilOp(sfail, dpfail, dppass uint32) {
 	g.ctx.StencilOp(gl.Enum(sfail), gl.Enum(dpfail), gl.Enum(dppass))
 }
 
+func (g *GomobileContext) StencilOpSeparate(face, sfail, dpfail, dppass uint32) {
+	g.ctx.StencilOpSeparate(gl.Enum(face), gl.Enum(sfail), gl.Enum(dpfail), gl.Enum(dppass))
+}
+
 func (g *GomobileContext) TexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, format uint32, xtype uint32, pixels []byte) {
 	g.ctx.TexImage2D(gl.Enum(target), int(level), int(internalformat), int(width), int(height), gl.Enum(format), gl.Enum(xtype), pixels)
 }
@@ -344,6 +376,10 @@ func (g *GomobileContext) UseProgram(program uint32) {
 	g.ctx.UseProgram(gmProgram(program))
 }
 
+func (g *GomobileContext) VertexAttribDivisor(index uint32, divisor uint32) {
+	panic("gles: VertexAttribDivisor is not implemented")
+}
+
 func (g *GomobileContext) VertexAttribPointer(index uint32, size int32, xtype uint32, normalized bool, stride int32, offset int) {
 	g.ctx.VertexAttribPointer(gl.Attrib{Value: uint(index)}, int(size), gl.Enum(xtype), normalized, int(stride), int(offset))
 }
@@ -25,7 +25,9 @@ type Context interface {
 	BindFramebuffer(target uint32, framebuffer uint32)
 	BindRenderbuffer(target uint32, renderbuffer uint32)
 	BindTexture(target uint32, texture uint32)
-	BlendFunc(sfactor uint32, dfactor uint32)
+	BindVertexArray(array uint32)
+	BlendEquationSeparate(modeRGB uint32, modeAlpha uint32)
+	BlendFuncSeparate(sfactorRGB uint32, dfactorRGB uint32, sfactorAlpha uint32, dfactorAlpha uint32)
 	BufferData(target uint32, size int, data []byte, usage uint32)
 	BufferSubData(target uint32, offset int, data []byte)
 	CheckFramebufferStatus(target uint32) uint32
@@ -42,7 +44,9 @@ type Context interface {
 	DeleteTextures(textures []uint32)
 	Disable(cap uint32)
 	DisableVertexAttribArray(index uint32)
+	DrawBuffers(bufs []uint32)
 	DrawElements(mode uint32, count int32, xtype uint32, offset int)
+	DrawElementsInstanced(mode uint32, count int32, xtype uint32, offset int, instanceCount int32)
 	Enable(cap uint32)
 	EnableVertexAttribArray(index uint32)
 	Flush()
@@ -52,6 +56,7 @@ type Context interface {
 	GenFramebuffers(n int32) []uint32
 	GenRenderbuffers(n int32) []uint32
 	GenTextures(n int32) []uint32
+	GenVertexArrays(n int32) []uint32
 	GetError() uint32
 	GetIntegerv(dst []int32, pname uint32)
 	GetProgramiv(dst []int32, program uint32, pname uint32)
@@ -59,6 +64,7 @@ type Context interface {
 	GetShaderiv(dst []int32, shader uint32, pname uint32)
 	GetShaderInfoLog(shader uint32) string
 	GetShaderPrecisionFormat(shadertype uint32, precisiontype uint32) (rangeLow, rangeHigh, precision int)
+	GetString(name uint32) string
 	GetUniformLocation(program uint32, name string) int32
 	IsFramebuffer(framebuffer uint32) bool
 	IsProgram(program uint32) bool
@@ -72,6 +78,7 @@ type Context interface {
 	ShaderSource(shader uint32, xstring string)
 	StencilFunc(func_ uint32, ref int32, mask uint32)
 	StencilOp(sfail, dpfail, dppass uint32)
+	StencilOpSeparate(face, sfail, dpfail, dppass uint32)
 	TexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, format uint32, xtype uint32, pixels []byte)
 	TexParameteri(target uint32, pname uint32, param int32)
 	TexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, width int32, height int32, format uint32, xtype uint32, pixels []byte)
@@ -85,6 +92,7 @@ type Context interface {
 	UniformMatrix3fv(location int32, transpose bool, value []float32)
 	UniformMatrix4fv(location int32, transpose bool, value []float32)
 	UseProgram(program uint32)
+	VertexAttribDivisor(index uint32, divisor uint32)
 	VertexAttribPointer(index uint32, size int32, xtype uint32, normalized bool, stride int32, offset int)
 	Viewport(x int32, y int32, width int32, height int32)
 }
@@ -16,6 +16,7 @@ package opengl
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
@@ -43,6 +44,9 @@ type Graphics struct {
 	nextShaderID graphicsdriver.ShaderID
 	shaders      map[graphicsdriver.ShaderID]*Shader
 
+	// mrtFramebuffers is the framebuffers for multiple render targets keyed by the destination images.
+	mrtFramebuffers map[[graphics.ShaderDstImageNum]graphicsdriver.ImageID]*framebuffer
+
 	// drawCalled is true just after Draw is called. This holds true until ReplacePixels is called.
 	drawCalled bool
 
@@ -54,18 +58,81 @@ type Graphics struct {
 	// activatedTextures is a set of activated textures.
 	// textureNative cannot be a map key unfortunately.
 	activatedTextures []activatedTexture
+
+	// debugGroupDepth is the number of the debug groups pushed so far.
+	debugGroupDepth int
+
+	// frameQueries is the timer queries issued in the current frame.
+	frameQueries []queryNative
+
+	// pendingFrameQueries is the timer queries of the past frames whose results are not read yet.
+	pendingFrameQueries [][]queryNative
+
+	// unusedQueries is the timer queries that can be reused.
+	unusedQueries []queryNative
+
+	// gpuFrameDuration is the time spent by GPU for the last frame whose results are available.
+	gpuFrameDuration time.Duration
 }
 
 func (g *Graphics) Begin() {
-	// Do nothing.
+	if g.context.isTimerQueryAvailable() {
+		var q queryNative
+		if n := len(g.unusedQueries); n > 0 {
+			q = g.unusedQueries[n-1]
+			g.unusedQueries = g.unusedQueries[:n-1]
+		} else {
+			q = g.context.newQuery()
+		}
+		g.context.beginTimerQuery(q)
+		g.frameQueries = append(g.frameQueries, q)
+	}
 }
 
 func (g *Graphics) End() {
+	if g.context.isTimerQueryAvailable() {
+		g.context.endTimerQuery()
+	}
+
 	// Call glFlush to prevent black flicking (especially on Android (#226) and iOS).
 	// TODO: examples/sprites worked without this. Is this really needed?
 	g.context.flush()
 }
 
+// EndGPUFrame notifies the end of a frame, and returns the time spent by GPU for the last frame
+// whose results are available.
+//
+// The results of timer queries are read asynchronously so as not to stall the pipeline.
+// Then, the returned duration is usually of a few frames before.
+// EndGPUFrame returns 0 if timer queries are not available.
+func (g *Graphics) EndGPUFrame() time.Duration {
+	if !g.context.isTimerQueryAvailable() {
+		return 0
+	}
+
+	if len(g.frameQueries) > 0 {
+		g.pendingFrameQueries = append(g.pendingFrameQueries, g.frameQueries)
+		g.frameQueries = nil
+	}
+
+	// Frames are finished in order. Stop at the first frame whose results are not available yet.
+	for len(g.pendingFrameQueries) > 0 {
+		qs := g.pendingFrameQueries[0]
+		if !g.context.isQueryResultAvailable(qs[len(qs)-1]) {
+			break
+		}
+		var d uint64
+		for _, q := range qs {
+			d += g.context.queryResult(q)
+		}
+		g.gpuFrameDuration = time.Duration(d)
+		g.unusedQueries = append(g.unusedQueries, qs...)
+		g.pendingFrameQueries[0] = nil
+		g.pendingFrameQueries = g.pendingFrameQueries[1:]
+	}
+	return g.gpuFrameDuration
+}
+
 func (g *Graphics) SetTransparent(transparent bool) {
 	// Do nothings.
 }
@@ -96,17 +163,22 @@ func (g *Graphics) genNextShaderID() graphicsdriver.ShaderID {
 	return g.nextShaderID
 }
 
-func (g *Graphics) NewImage(width, height int) (graphicsdriver.Image, error) {
+func (g *Graphics) NewImage(width, height int, sampleCount int, format graphicsdriver.PixelFormat) (graphicsdriver.Image, error) {
+	if max := g.context.getMaxSampleCount(); sampleCount > max {
+		sampleCount = max
+	}
 	i := &Image{
-		id:       g.genNextImageID(),
-		graphics: g,
-		width:    width,
-		height:   height,
+		id:          g.genNextImageID(),
+		graphics:    g,
+		width:       width,
+		height:      height,
+		sampleCount: sampleCount,
+		format:      format,
 	}
 	w := graphics.InternalImageSize(width)
 	h := graphics.InternalImageSize(height)
 	g.checkSize(w, h)
-	t, err := g.context.newTexture(w, h)
+	t, err := g.context.newTexture(w, h, g.context.texturePixelFormat(format))
 	if err != nil {
 		return nil, err
 	}
@@ -140,6 +212,15 @@ func (g *Graphics) addImage(img *Image) {
 
 func (g *Graphics) removeImage(img *Image) {
 	delete(g.images, img.id)
+	for ids, f := range g.mrtFramebuffers {
+		for _, id := range ids {
+			if id == img.id {
+				f.delete(&g.context)
+				delete(g.mrtFramebuffers, ids)
+				break
+			}
+		}
+	}
 }
 
 func (g *Graphics) Initialize() error {
@@ -155,6 +236,7 @@ func (g *Graphics) SetVertices(vertices []float32, indices []uint16) {
 	// Note that the vertices passed to BufferSubData is not under GC management
 	// in opengl package due to unsafe-way.
 	// See BufferSubData in context_mobile.go.
+	g.state.ensureBufferSizes(&g.context, len(vertices)*4, len(indices)*2)
 	g.context.arrayBufferSubData(vertices)
 	g.context.elementArrayBufferSubData(indices)
 }
@@ -171,12 +253,90 @@ func (g *Graphics) uniformVariableName(idx int) string {
 	return name
 }
 
-func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.ShaderImageNum]graphicsdriver.ImageID, offsets [graphics.ShaderImageNum - 1][2]float32, shaderID graphicsdriver.ShaderID, indexLen int, indexOffset int, mode graphicsdriver.CompositeMode, colorM graphicsdriver.ColorM, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, uniforms []graphicsdriver.Uniform, evenOdd bool) error {
+func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.ShaderImageNum]graphicsdriver.ImageID, offsets [graphics.ShaderImageNum - 1][2]float32, shaderID graphicsdriver.ShaderID, indexLen int, indexOffset int, mode graphicsdriver.Blend, colorM graphicsdriver.ColorM, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, uniforms []graphicsdriver.Uniform, fillRule graphicsdriver.FillRule) error {
+	return g.drawTriangles(dstID, srcIDs, offsets, shaderID, indexLen, indexOffset, mode, colorM, filter, address, dstRegion, srcRegion, uniforms, fillRule, nil, nil)
+}
+
+func (g *Graphics) DrawTrianglesInstanced(dstID graphicsdriver.ImageID, srcID graphicsdriver.ImageID, instances []float32, indexLen int, indexOffset int, mode graphicsdriver.Blend, colorM graphicsdriver.ColorM, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region) error {
+	if !g.context.isInstancingAvailable() {
+		panic("opengl: instanced drawing is not available")
+	}
+	if len(instances) == 0 {
+		return nil
+	}
+	return g.drawTriangles(dstID, [graphics.ShaderImageNum]graphicsdriver.ImageID{srcID}, [graphics.ShaderImageNum - 1][2]float32{}, graphicsdriver.InvalidShaderID, indexLen, indexOffset, mode, colorM, filter, address, dstRegion, srcRegion, nil, graphicsdriver.FillAll, instances, nil)
+}
+
+func (g *Graphics) DrawTrianglesMRT(dstIDs [graphics.ShaderDstImageNum]graphicsdriver.ImageID, srcIDs [graphics.ShaderImageNum]graphicsdriver.ImageID, offsets [graphics.ShaderImageNum - 1][2]float32, shaderID graphicsdriver.ShaderID, indexLen int, indexOffset int, mode graphicsdriver.Blend, dstRegion, srcRegion graphicsdriver.Region, uniforms []graphicsdriver.Uniform) error {
+	if shaderID == graphicsdriver.InvalidShaderID {
+		panic("opengl: a shader must be specified for multiple render targets")
+	}
+	f, err := g.mrtFramebuffer(dstIDs)
+	if err != nil {
+		return err
+	}
+	return g.drawTriangles(dstIDs[0], srcIDs, offsets, shaderID, indexLen, indexOffset, mode, nil, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, srcRegion, uniforms, graphicsdriver.FillAll, nil, f)
+}
+
+// mrtFramebuffer returns a framebuffer to render onto the given images at the same time.
+func (g *Graphics) mrtFramebuffer(dstIDs [graphics.ShaderDstImageNum]graphicsdriver.ImageID) (*framebuffer, error) {
+	if f, ok := g.mrtFramebuffers[dstIDs]; ok {
+		return f, nil
+	}
+
+	var textures []textureNative
+	var width, height int
+	for _, id := range dstIDs {
+		if id == graphicsdriver.InvalidImageID {
+			continue
+		}
+		img := g.images[id]
+		if img.screen {
+			panic("opengl: the screen image cannot be a destination of multiple render targets")
+		}
+		if img.isMultisampled() {
+			panic("opengl: a multisampled image cannot be a destination of multiple render targets")
+		}
+		w, h := img.framebufferSize()
+		if len(textures) == 0 {
+			width, height = w, h
+		} else if w != width || h != height {
+			panic("opengl: all the destinations of multiple render targets must have the same size")
+		}
+		textures = append(textures, img.texture)
+	}
+	if max := g.context.getMaxDrawBuffers(); len(textures) > max {
+		panic(fmt.Sprintf("opengl: the number of destinations (%d) exceeds the maximum (%d)", len(textures), max))
+	}
+
+	f, err := newFramebufferFromTextures(&g.context, textures, width, height)
+	if err != nil {
+		return nil, err
+	}
+	if g.mrtFramebuffers == nil {
+		g.mrtFramebuffers = map[[graphics.ShaderDstImageNum]graphicsdriver.ImageID]*framebuffer{}
+	}
+	g.mrtFramebuffers[dstIDs] = f
+	return f, nil
+}
+
+func (g *Graphics) drawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.ShaderImageNum]graphicsdriver.ImageID, offsets [graphics.ShaderImageNum - 1][2]float32, shaderID graphicsdriver.ShaderID, indexLen int, indexOffset int, mode graphicsdriver.Blend, colorM graphicsdriver.ColorM, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, uniforms []graphicsdriver.Uniform, fillRule graphicsdriver.FillRule, instances []float32, mrt *framebuffer) error {
 	destination := g.images[dstID]
 
+	// Resolve the multisampled sources before binding the destination, as resolving changes the framebuffer
+	// binding.
+	for _, srcID := range srcIDs {
+		if srcID == graphicsdriver.InvalidImageID {
+			continue
+		}
+		g.images[srcID].resolve()
+	}
+
 	g.drawCalled = true
 
-	if err := destination.setViewport(); err != nil {
+	if mrt != nil {
+		g.context.setViewport(mrt)
+	} else if err := destination.setViewport(); err != nil {
 		return err
 	}
 	g.context.scissor(
@@ -185,15 +345,25 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 		int(dstRegion.Width),
 		int(dstRegion.Height),
 	)
-	g.context.blendFunc(mode)
+	g.context.blend(mode)
 
 	var program program
 	if shaderID == graphicsdriver.InvalidShaderID {
-		program = g.state.programs[programKey{
+		key := programKey{
 			useColorM: !colorM.IsIdentity(),
 			filter:    filter,
 			address:   address,
-		}]
+		}
+		if instances != nil {
+			key.instanced = true
+			p, err := g.state.instancedProgram(&g.context, key)
+			if err != nil {
+				return err
+			}
+			program = p
+		} else {
+			program = g.state.programs[key]
+		}
 
 		dw, dh := destination.framebufferSize()
 		g.uniformVars = append(g.uniformVars, uniformVariable{
@@ -356,23 +526,56 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 	}
 	g.uniformVars = g.uniformVars[:0]
 
-	if evenOdd {
+	if fillRule != graphicsdriver.FillAll {
 		if err := destination.ensureStencilBuffer(); err != nil {
 			return err
 		}
 		g.context.enableStencilTest()
-		g.context.beginStencilWithEvenOddRule()
+		switch fillRule {
+		case graphicsdriver.EvenOdd:
+			g.context.beginStencilWithEvenOddRule()
+		case graphicsdriver.NonZero:
+			g.context.beginStencilWithNonZeroRule()
+		}
 		g.context.drawElements(indexLen, indexOffset*2)
-		g.context.endStencilWithEvenOddRule()
+		g.context.endStencil()
+	}
+	if instances != nil {
+		g.state.setInstances(&g.context, instances)
+		g.context.drawElementsInstanced(indexLen, indexOffset*2, len(instances)/graphics.InstanceFloatNum)
+		g.state.unsetInstances(&g.context)
+	} else {
+		g.context.drawElements(indexLen, indexOffset*2) // 2 is uint16 size in bytes
 	}
-	g.context.drawElements(indexLen, indexOffset*2) // 2 is uint16 size in bytes
-	if evenOdd {
+	if fillRule != graphicsdriver.FillAll {
 		g.context.disableStencilTest()
 	}
 
 	return nil
 }
 
+// PushDebugGroup starts a debug group for graphics debugging tools like RenderDoc.
+// PushDebugGroup does nothing if KHR_debug is not available.
+func (g *Graphics) PushDebugGroup(name string) {
+	g.debugGroupDepth++
+	// The debug group stack already has the default group.
+	if g.debugGroupDepth >= g.context.getMaxDebugGroups() {
+		return
+	}
+	g.context.pushDebugGroup(name)
+}
+
+// PopDebugGroup ends the debug group started by the last PushDebugGroup.
+func (g *Graphics) PopDebugGroup() {
+	if g.debugGroupDepth == 0 {
+		panic("opengl: PopDebugGroup is called without PushDebugGroup")
+	}
+	if g.debugGroupDepth < g.context.getMaxDebugGroups() {
+		g.context.popDebugGroup()
+	}
+	g.debugGroupDepth--
+}
+
 func (g *Graphics) SetVsyncEnabled(enabled bool) {
 	// Do nothing
 }
@@ -405,6 +608,26 @@ func (g *Graphics) MaxImageSize() int {
 	return g.context.getMaxTextureSize()
 }
 
+func (g *Graphics) Capabilities() graphicsdriver.Capabilities {
+	return graphicsdriver.Capabilities{
+		MaxImageSize:     g.MaxImageSize(),
+		MaxSampleCount:   g.context.getMaxSampleCount(),
+		FloatTexture:     g.context.isFloatTextureAvailable(),
+		ComputeShader:    g.context.isComputeShaderAvailable(),
+		Instancing:       g.context.isInstancingAvailable(),
+		MaxRenderTargets: g.maxRenderTargets(),
+		Renderer:         g.context.renderer(),
+		Version:          g.context.version(),
+	}
+}
+
+func (g *Graphics) maxRenderTargets() int {
+	if n := g.context.getMaxDrawBuffers(); n < graphics.ShaderDstImageNum {
+		return n
+	}
+	return graphics.ShaderDstImageNum
+}
+
 func (g *Graphics) NewShader(program *shaderir.Program) (graphicsdriver.Shader, error) {
 	s, err := newShader(g.genNextShaderID(), g, program)
 	if err != nil {
@@ -414,6 +637,18 @@ func (g *Graphics) NewShader(program *shaderir.Program) (graphicsdriver.Shader,
 	return s, nil
 }
 
+func (g *Graphics) NewShaderAsync(program *shaderir.Program) (graphicsdriver.PendingShader, error) {
+	s := &Shader{
+		id:       g.genNextShaderID(),
+		graphics: g,
+		ir:       program,
+	}
+	if err := s.startCompiling(); err != nil {
+		return nil, err
+	}
+	return &pendingShader{shader: s}, nil
+}
+
 func (g *Graphics) addShader(shader *Shader) {
 	if g.shaders == nil {
 		g.shaders = map[graphicsdriver.ShaderID]*Shader{}
@@ -28,6 +28,23 @@ type Image struct {
 	width       int
 	height      int
 	screen      bool
+
+	// format is the pixel format requested for the image.
+	// The actual format of the texture is PixelFormatRGBA8 when format is not available.
+	format graphicsdriver.PixelFormat
+
+	// sampleCount is the number of samples per pixel for multisample antialiasing.
+	// If sampleCount is more than 1, the image is rendered onto the multisampled renderbuffer msaaColor, and
+	// the result is resolved into the texture when the texture is used.
+	sampleCount     int
+	msaaColor       renderbufferNative
+	msaaFramebuffer *framebuffer
+
+	// needsResolve indicates whether msaaColor has newer pixels than the texture.
+	needsResolve bool
+
+	// needsLoad indicates whether the texture has newer pixels than msaaColor.
+	needsLoad bool
 }
 
 func (i *Image) ID() graphicsdriver.ImageID {
@@ -42,6 +59,12 @@ func (i *Image) Dispose() {
 	if i.framebuffer != nil {
 		i.framebuffer.delete(&i.graphics.context)
 	}
+	if i.msaaFramebuffer != nil {
+		i.msaaFramebuffer.delete(&i.graphics.context)
+	}
+	if !i.msaaColor.equal(*new(renderbufferNative)) {
+		i.graphics.context.deleteRenderbuffer(i.msaaColor)
+	}
 	if !i.texture.equal(*new(textureNative)) {
 		i.graphics.context.deleteTexture(i.texture)
 	}
@@ -52,23 +75,127 @@ func (i *Image) Dispose() {
 	i.graphics.removeImage(i)
 }
 
+func (i *Image) isMultisampled() bool {
+	return i.sampleCount > 1
+}
+
+// setViewport sets the image's render target as the current framebuffer to render the image.
 func (i *Image) setViewport() error {
 	if err := i.ensureFramebuffer(); err != nil {
 		return err
 	}
-	i.graphics.context.setViewport(i.framebuffer)
+	if !i.isMultisampled() {
+		i.graphics.context.setViewport(i.framebuffer)
+		return nil
+	}
+
+	if i.needsLoad {
+		w, h := i.framebufferSize()
+		i.graphics.context.blitFramebuffer(i.framebuffer.native, i.msaaFramebuffer.native, w, h)
+		i.needsLoad = false
+	}
+	i.graphics.context.setViewport(i.msaaFramebuffer)
+	i.needsResolve = true
 	return nil
 }
 
+// resolve resolves the multisampled pixels into the texture if necessary.
+//
+// resolve must be called before the texture is used, e.g., as a rendering source.
+// The framebuffers already exist when needsResolve is true.
+func (i *Image) resolve() {
+	if !i.needsResolve {
+		return
+	}
+	w, h := i.framebufferSize()
+	i.graphics.context.blitFramebuffer(i.msaaFramebuffer.native, i.framebuffer.native, w, h)
+	i.needsResolve = false
+}
+
 func (i *Image) Pixels() ([]byte, error) {
 	if err := i.ensureFramebuffer(); err != nil {
 		return nil, err
 	}
+	i.resolve()
 
 	p := i.graphics.context.framebufferPixels(i.framebuffer, i.width, i.height)
+	return i.normalizePixels(p), nil
+}
+
+// normalizePixels fills the channels the image's format doesn't have in the given RGBA pixels.
+// This is necessary when the texture has more channels than the format, or when the driver returns arbitrary
+// values for the missing channels.
+func (i *Image) normalizePixels(pix []byte) []byte {
+	return i.format.UnpackPixels(i.format.PackPixels(pix))
+}
+
+func (i *Image) ReadPixelsAsync(x, y, width, height int) (graphicsdriver.PendingPixels, error) {
+	if err := i.ensureFramebuffer(); err != nil {
+		return nil, err
+	}
+	i.resolve()
+
+	if !i.graphics.context.canReadPixelsAsync() {
+		p := i.graphics.context.framebufferPixels(i.framebuffer, i.width, i.height)
+		pix := make([]byte, 4*width*height)
+		for j := 0; j < height; j++ {
+			copy(pix[4*j*width:4*(j+1)*width], p[4*((j+y)*i.width+x):])
+		}
+		return completedPixels(i.normalizePixels(pix)), nil
+	}
+
+	size := 4 * width * height
+	b := i.graphics.context.newPixelPackBuffer(size)
+	i.graphics.context.framebufferPixelsToBuffer(i.framebuffer, b, x, y, width, height)
+	p := &pendingPixels{
+		context: &i.graphics.context,
+		buffer:  b,
+		size:    size,
+		format:  i.format,
+	}
+	if i.graphics.context.isFenceAvailable() {
+		p.fence = i.graphics.context.newFence()
+	}
 	return p, nil
 }
 
+// pendingPixels represents pixels being read into a pixel pack buffer.
+type pendingPixels struct {
+	context *context
+	buffer  buffer
+	fence   fenceNative
+	size    int
+	format  graphicsdriver.PixelFormat
+
+	// polled indicates whether TryPixels was called when fences are not available.
+	polled bool
+}
+
+func (p *pendingPixels) TryPixels() ([]byte, bool) {
+	if p.context.isFenceAvailable() {
+		if !p.context.isFenceSignaled(p.fence) {
+			return nil, false
+		}
+		p.context.deleteFence(p.fence)
+	} else if !p.polled {
+		// Without fences, the progress of GPU is unknown.
+		// Wait for the next call, which is usually in the next frame, so that reading the buffer is unlikely to stall.
+		p.polled = true
+		return nil, false
+	}
+
+	pix := p.context.pixelPackBufferData(p.buffer, p.size)
+	p.context.deleteBuffer(p.buffer)
+	return p.format.UnpackPixels(p.format.PackPixels(pix)), true
+}
+
+// completedPixels represents pixels that are already read.
+type completedPixels []byte
+
+func (p completedPixels) TryPixels() ([]byte, bool) {
+	return p, true
+}
+
 func (i *Image) framebufferSize() (int, int) {
 	if i.screen {
 		// The (default) framebuffer size can't be converted to a power of 2.
@@ -94,6 +221,20 @@ func (i *Image) ensureFramebuffer() error {
 		return err
 	}
 	i.framebuffer = f
+
+	if !i.isMultisampled() {
+		return nil
+	}
+	r, err := i.graphics.context.newMultisampledColorRenderbuffer(w, h, i.sampleCount)
+	if err != nil {
+		return err
+	}
+	i.msaaColor = r
+	mf, err := newFramebufferFromRenderbuffer(&i.graphics.context, r, w, h)
+	if err != nil {
+		return err
+	}
+	i.msaaFramebuffer = mf
 	return nil
 }
 
@@ -106,6 +247,20 @@ func (i *Image) ensureStencilBuffer() error {
 		return err
 	}
 
+	if i.isMultisampled() {
+		w, h := i.framebufferSize()
+		r, err := i.graphics.context.newMultisampledStencilRenderbuffer(w, h, i.sampleCount)
+		if err != nil {
+			return err
+		}
+		i.stencil = r
+
+		if err := i.graphics.context.bindStencilBuffer(i.msaaFramebuffer.native, i.stencil); err != nil {
+			return err
+		}
+		return nil
+	}
+
 	r, err := i.graphics.context.newRenderbuffer(i.framebufferSize())
 	if err != nil {
 		return err
@@ -126,11 +281,31 @@ func (i *Image) ReplacePixels(args []*graphicsdriver.ReplacePixelsArgs) {
 		return
 	}
 
+	// The pixels outside of the regions must be kept. Resolve the multisampled pixels first.
+	i.resolve()
+
 	// glFlush is necessary on Android.
 	// glTexSubImage2D didn't work without this hack at least on Nexus 5x and NuAns NEO [Reloaded] (#211).
 	if i.graphics.drawCalled {
 		i.graphics.context.flush()
 	}
 	i.graphics.drawCalled = false
-	i.graphics.context.texSubImage2D(i.texture, args)
+	format := i.graphics.context.texturePixelFormat(i.format)
+	if format != graphicsdriver.PixelFormatRGBA8 {
+		packed := make([]*graphicsdriver.ReplacePixelsArgs, len(args))
+		for idx, a := range args {
+			packed[idx] = &graphicsdriver.ReplacePixelsArgs{
+				Pixels: format.PackPixels(a.Pixels),
+				X:      a.X,
+				Y:      a.Y,
+				Width:  a.Width,
+				Height: a.Height,
+			}
+		}
+		args = packed
+	}
+	i.graphics.context.texSubImage2D(i.texture, format, args)
+	if i.isMultisampled() {
+		i.needsLoad = true
+	}
 }
@@ -62,11 +62,6 @@ func (a *arrayBufferLayout) totalBytes() int {
 	return a.total
 }
 
-// newArrayBuffer creates OpenGL's buffer object for the array buffer.
-func (a *arrayBufferLayout) newArrayBuffer(context *context) buffer {
-	return context.newArrayBuffer(a.totalBytes() * graphics.IndicesNum)
-}
-
 // enable starts using the array buffer.
 func (a *arrayBufferLayout) enable(context *context) {
 	for i := range a.parts {
@@ -88,6 +83,27 @@ func (a *arrayBufferLayout) disable(context *context) {
 	}
 }
 
+// enableInstanced starts using the array buffer as per-instance attributes.
+// The attribute indices start from base.
+func (a *arrayBufferLayout) enableInstanced(context *context, base int) {
+	total := a.totalBytes()
+	offset := 0
+	for i, p := range a.parts {
+		context.enableVertexAttribArray(base + i)
+		context.vertexAttribPointer(base+i, p.num, total, offset)
+		context.vertexAttribDivisor(base+i, 1)
+		offset += floatSizeInBytes * p.num
+	}
+}
+
+// disableInstanced stops using the array buffer as per-instance attributes.
+func (a *arrayBufferLayout) disableInstanced(context *context, base int) {
+	for i := range a.parts {
+		context.vertexAttribDivisor(base+i, 0)
+		context.disableVertexAttribArray(base + i)
+	}
+}
+
 // theArrayBufferLayout is the array buffer layout for Ebiten.
 var theArrayBufferLayout = arrayBufferLayout{
 	// Note that GL_MAX_VERTEX_ATTRIBS is at least 16.
@@ -107,17 +123,37 @@ var theArrayBufferLayout = arrayBufferLayout{
 	},
 }
 
+// theInstanceBufferLayout is the array buffer layout for the per-instance attributes of instanced drawing.
+// The attributes follow the ones of theArrayBufferLayout.
+var theInstanceBufferLayout = arrayBufferLayout{
+	parts: []arrayBufferLayoutPart{
+		{
+			name: "A3",
+			num:  4,
+		},
+		{
+			name: "A4",
+			num:  4,
+		},
+	},
+}
+
 func init() {
 	vertexFloatNum := theArrayBufferLayout.totalBytes() / floatSizeInBytes
 	if graphics.VertexFloatNum != vertexFloatNum {
 		panic(fmt.Sprintf("vertex float num must be %d but %d", graphics.VertexFloatNum, vertexFloatNum))
 	}
+	instanceFloatNum := theInstanceBufferLayout.totalBytes() / floatSizeInBytes
+	if graphics.InstanceFloatNum != instanceFloatNum {
+		panic(fmt.Sprintf("instance float num must be %d but %d", graphics.InstanceFloatNum, instanceFloatNum))
+	}
 }
 
 type programKey struct {
 	useColorM bool
 	filter    graphicsdriver.Filter
 	address   graphicsdriver.Address
+	instanced bool
 }
 
 // openGLState is a state for
@@ -125,9 +161,21 @@ type openGLState struct {
 	// arrayBuffer is OpenGL's array buffer (vertices data).
 	arrayBuffer buffer
 
+	// arrayBufferSize is the size of arrayBuffer in bytes.
+	arrayBufferSize int
+
 	// elementArrayBuffer is OpenGL's element array buffer (indices data).
 	elementArrayBuffer buffer
 
+	// elementArrayBufferSize is the size of elementArrayBuffer in bytes.
+	elementArrayBufferSize int
+
+	// instanceBuffer is OpenGL's array buffer for the per-instance attributes.
+	instanceBuffer buffer
+
+	// instanceBufferSize is the size of instanceBuffer in bytes.
+	instanceBufferSize int
+
 	// programs is OpenGL's program for rendering a texture.
 	programs map[programKey]program
 
@@ -174,9 +222,18 @@ func (s *openGLState) reset(context *context) error {
 		if !s.elementArrayBuffer.equal(zeroBuffer) {
 			context.deleteBuffer(s.elementArrayBuffer)
 		}
+		if !s.instanceBuffer.equal(zeroBuffer) {
+			context.deleteBuffer(s.instanceBuffer)
+		}
 	}
-
-	shaderVertexModelviewNative, err := context.newVertexShader(vertexShaderStr())
+	s.arrayBuffer = zeroBuffer
+	s.arrayBufferSize = 0
+	s.elementArrayBuffer = zeroBuffer
+	s.elementArrayBufferSize = 0
+	s.instanceBuffer = zeroBuffer
+	s.instanceBufferSize = 0
+
+	shaderVertexModelviewNative, err := context.newVertexShader(vertexShaderStr(false))
 	if err != nil {
 		panic(fmt.Sprintf("graphics: shader compiling error:\n%s", err))
 	}
@@ -217,16 +274,103 @@ func (s *openGLState) reset(context *context) error {
 		}
 	}
 
-	s.arrayBuffer = theArrayBufferLayout.newArrayBuffer(context)
-
-	// Note that the indices passed to NewElementArrayBuffer is not under GC management
-	// in opengl package due to unsafe-way.
-	// See NewElementArrayBuffer in context_mobile.go.
-	s.elementArrayBuffer = context.newElementArrayBuffer(graphics.IndicesNum * 2)
+	s.ensureBufferSizes(context, theArrayBufferLayout.totalBytes()*initialVerticesNum, 2*initialIndicesNum)
 
 	return nil
 }
 
+const (
+	initialVerticesNum = 1 << 10
+	initialIndicesNum  = 3 * (1 << 10)
+)
+
+// bufferSize returns a buffer size in bytes that is power of two and is at least size.
+func bufferSize(size int) int {
+	s := 1 << 12
+	for s < size {
+		s *= 2
+	}
+	return s
+}
+
+// ensureBufferSizes ensures that the array buffer and the element array buffer have at least the given sizes
+// in bytes.
+//
+// The buffers are recreated when they are too small.
+// The new buffers are bound and the array buffer layout is enabled again, as vertex attribute pointers refer to
+// the array buffer bound when they are specified.
+func (s *openGLState) ensureBufferSizes(context *context, arrayBufferSize, elementArrayBufferSize int) {
+	if s.arrayBufferSize < arrayBufferSize {
+		if !s.arrayBuffer.equal(zeroBuffer) {
+			context.deleteBuffer(s.arrayBuffer)
+		}
+		s.arrayBufferSize = bufferSize(arrayBufferSize)
+		s.arrayBuffer = context.newArrayBuffer(s.arrayBufferSize)
+		if !s.lastProgram.equal(zeroProgram) {
+			theArrayBufferLayout.enable(context)
+		}
+	}
+	if s.elementArrayBufferSize < elementArrayBufferSize {
+		if !s.elementArrayBuffer.equal(zeroBuffer) {
+			context.deleteBuffer(s.elementArrayBuffer)
+		}
+		s.elementArrayBufferSize = bufferSize(elementArrayBufferSize)
+		s.elementArrayBuffer = context.newElementArrayBuffer(s.elementArrayBufferSize)
+	}
+}
+
+// instancedProgram returns the program for instanced drawing with the given key.
+// The programs for instanced drawing are created lazily, as instanced drawing is not available on some
+// environments and is rarely used.
+func (s *openGLState) instancedProgram(context *context, key programKey) (program, error) {
+	if p, ok := s.programs[key]; ok {
+		return p, nil
+	}
+
+	vs, err := context.newVertexShader(vertexShaderStr(true))
+	if err != nil {
+		panic(fmt.Sprintf("graphics: shader compiling error:\n%s", err))
+	}
+	defer context.deleteShader(vs)
+
+	fs, err := context.newFragmentShader(fragmentShaderStr(key.useColorM, key.filter, key.address))
+	if err != nil {
+		panic(fmt.Sprintf("graphics: shader compiling error:\n%s", err))
+	}
+	defer context.deleteShader(fs)
+
+	names := append(theArrayBufferLayout.names(), theInstanceBufferLayout.names()...)
+	p, err := context.newProgram([]shader{vs, fs}, names)
+	if err != nil {
+		return zeroProgram, err
+	}
+	s.programs[key] = p
+	return p, nil
+}
+
+// setInstances uploads the per-instance attributes and enables them.
+//
+// The instance buffer is recreated when it is too small.
+// The array buffer for the vertices is bound again after the instances are uploaded.
+func (s *openGLState) setInstances(context *context, instances []float32) {
+	if size := len(instances) * floatSizeInBytes; s.instanceBufferSize < size {
+		if !s.instanceBuffer.equal(zeroBuffer) {
+			context.deleteBuffer(s.instanceBuffer)
+		}
+		s.instanceBufferSize = bufferSize(size)
+		s.instanceBuffer = context.newArrayBuffer(s.instanceBufferSize)
+	}
+	context.bindArrayBuffer(s.instanceBuffer)
+	context.arrayBufferSubData(instances)
+	theInstanceBufferLayout.enableInstanced(context, len(theArrayBufferLayout.parts))
+	context.bindArrayBuffer(s.arrayBuffer)
+}
+
+// unsetInstances disables the per-instance attributes.
+func (s *openGLState) unsetInstances(context *context) {
+	theInstanceBufferLayout.disableInstanced(context, len(theArrayBufferLayout.parts))
+}
+
 // areSameFloat32Array returns a boolean indicating if a and b are deeply equal.
 func areSameFloat32Array(a, b []float32) bool {
 	if len(a) != len(b) {
@@ -28,6 +28,12 @@ type Shader struct {
 
 	ir *shaderir.Program
 	p  program
+
+	// vs, fs, vssrc and fssrc are used only while compiling.
+	vs    shader
+	fs    shader
+	vssrc string
+	fssrc string
 }
 
 func newShader(id graphicsdriver.ShaderID, graphics *Graphics, program *shaderir.Program) (*Shader, error) {
@@ -52,25 +58,74 @@ func (s *Shader) Dispose() {
 }
 
 func (s *Shader) compile() error {
-	vssrc, fssrc := glsl.Compile(s.ir, s.graphics.context.glslVersion())
+	if err := s.startCompiling(); err != nil {
+		return err
+	}
+	return s.finishCompiling()
+}
+
+// startCompiling starts compiling and linking the shader without waiting for the result.
+func (s *Shader) startCompiling() error {
+	s.vssrc, s.fssrc = glsl.Compile(s.ir, s.graphics.context.glslVersion())
 
-	vs, err := s.graphics.context.newVertexShader(vssrc)
+	vs, err := s.graphics.context.compileVertexShader(s.vssrc)
 	if err != nil {
-		return fmt.Errorf("opengl: vertex shader compile error: %v, source:\n%s", err, vssrc)
+		return fmt.Errorf("opengl: vertex shader compile error: %v, source:\n%s", err, s.vssrc)
 	}
-	defer s.graphics.context.deleteShader(vs)
+	s.vs = vs
 
-	fs, err := s.graphics.context.newFragmentShader(fssrc)
+	fs, err := s.graphics.context.compileFragmentShader(s.fssrc)
 	if err != nil {
-		return fmt.Errorf("opengl: fragment shader compile error: %v, source:\n%s", err, fssrc)
+		s.graphics.context.deleteShader(s.vs)
+		return fmt.Errorf("opengl: fragment shader compile error: %v, source:\n%s", err, s.fssrc)
 	}
-	defer s.graphics.context.deleteShader(fs)
+	s.fs = fs
 
-	p, err := s.graphics.context.newProgram([]shader{vs, fs}, theArrayBufferLayout.names())
+	p, err := s.graphics.context.linkProgram([]shader{s.vs, s.fs}, theArrayBufferLayout.names())
 	if err != nil {
+		s.graphics.context.deleteShader(s.vs)
+		s.graphics.context.deleteShader(s.fs)
 		return err
 	}
-
 	s.p = p
 	return nil
 }
+
+// finishCompiling waits for the compilation started by startCompiling and checks the result.
+func (s *Shader) finishCompiling() error {
+	defer func() {
+		s.graphics.context.deleteShader(s.vs)
+		s.graphics.context.deleteShader(s.fs)
+		s.vssrc = ""
+		s.fssrc = ""
+	}()
+
+	if err := s.graphics.context.checkShader(s.vs); err != nil {
+		s.graphics.context.deleteProgram(s.p)
+		return fmt.Errorf("opengl: vertex shader compile error: %v, source:\n%s", err, s.vssrc)
+	}
+	if err := s.graphics.context.checkShader(s.fs); err != nil {
+		s.graphics.context.deleteProgram(s.p)
+		return fmt.Errorf("opengl: fragment shader compile error: %v, source:\n%s", err, s.fssrc)
+	}
+	if err := s.graphics.context.checkProgram(s.p); err != nil {
+		s.graphics.context.deleteProgram(s.p)
+		return err
+	}
+	return nil
+}
+
+type pendingShader struct {
+	shader *Shader
+}
+
+func (p *pendingShader) TryShader() (graphicsdriver.Shader, bool, error) {
+	if !p.shader.graphics.context.isProgramCompleted(p.shader.p) {
+		return nil, false, nil
+	}
+	if err := p.shader.finishCompiling(); err != nil {
+		return nil, true, err
+	}
+	p.shader.graphics.addShader(p.shader)
+	return p.shader, true, nil
+}
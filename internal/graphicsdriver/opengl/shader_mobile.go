@@ -22,5 +22,8 @@ import (
 )
 
 func (c *context) glslVersion() glsl.GLSLVersion {
+	if c.isES3 {
+		return glsl.GLSLVersionES300
+	}
 	return glsl.GLSLVersionES100
 }
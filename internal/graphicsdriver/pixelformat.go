@@ -0,0 +1,79 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicsdriver
+
+import (
+	"fmt"
+)
+
+// PixelFormat represents a format of pixels on a texture.
+type PixelFormat int
+
+const (
+	// PixelFormatRGBA8 represents 8-bit red, green, blue and alpha channels.
+	PixelFormatRGBA8 PixelFormat = iota
+
+	// PixelFormatR8 represents an 8-bit red channel.
+	PixelFormatR8
+
+	// PixelFormatRG8 represents 8-bit red and green channels.
+	PixelFormatRG8
+)
+
+// BytesPerPixel returns the number of bytes per pixel.
+func (p PixelFormat) BytesPerPixel() int {
+	switch p {
+	case PixelFormatRGBA8:
+		return 4
+	case PixelFormatR8:
+		return 1
+	case PixelFormatRG8:
+		return 2
+	default:
+		panic(fmt.Sprintf("graphicsdriver: invalid pixel format: %d", p))
+	}
+}
+
+// PackPixels converts RGBA pixels into the pixels in the format by dropping the channels the format doesn't have.
+//
+// If the format is PixelFormatRGBA8, PackPixels returns the given slice as it is.
+func (p PixelFormat) PackPixels(rgba []byte) []byte {
+	if p == PixelFormatRGBA8 {
+		return rgba
+	}
+	n := p.BytesPerPixel()
+	pix := make([]byte, len(rgba)/4*n)
+	for i := 0; i < len(rgba)/4; i++ {
+		copy(pix[n*i:n*(i+1)], rgba[4*i:4*i+n])
+	}
+	return pix
+}
+
+// UnpackPixels converts the pixels in the format into RGBA pixels.
+// The color channels the format doesn't have are 0, and the alpha channel is 0xff.
+//
+// If the format is PixelFormatRGBA8, UnpackPixels returns the given slice as it is.
+func (p PixelFormat) UnpackPixels(pix []byte) []byte {
+	if p == PixelFormatRGBA8 {
+		return pix
+	}
+	n := p.BytesPerPixel()
+	rgba := make([]byte, len(pix)/n*4)
+	for i := 0; i < len(pix)/n; i++ {
+		copy(rgba[4*i:4*i+n], pix[n*i:n*(i+1)])
+		rgba[4*i+3] = 0xff
+	}
+	return rgba
+}
@@ -0,0 +1,317 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mipmap
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/affine"
+	"github.com/hajimehoshi/ebiten/v2/internal/buffered"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/shader"
+)
+
+// FilterQuality represents how a minified source is sampled with the linear filter.
+type FilterQuality int
+
+const (
+	// FilterQualityDefault samples one mipmap level chosen for the whole draw call.
+	FilterQualityDefault FilterQuality = iota
+
+	// FilterQualityTrilinear blends the two mipmap levels around the ideal level.
+	FilterQualityTrilinear
+
+	// FilterQualityAnisotropic chooses the level by the minor axis of the pixel footprint on the source,
+	// and takes multiple trilinear samples along the major axis.
+	FilterQualityAnisotropic
+)
+
+// maxAnisotropy is the maximum number of samples along the major axis for FilterQualityAnisotropic.
+const maxAnisotropy = 8
+
+// maxFilterLevel is the maximum mipmap level used for the filter qualities.
+const maxFilterLevel = 6
+
+// filterShaderSrc is a shader to sample two adjacent mipmap levels.
+//
+// The source image 0 is the level L image and the source image 1 is the level L+1 image.
+// The texture coordinates of the vertices are in the level L image.
+var filterShaderSrc = fmt.Sprintf(`package main
+
+var __imageDstTextureSize vec2
+var __textureSizes [%[1]d]vec2
+var __textureDestinationRegionOrigin vec2
+var __textureDestinationRegionSize vec2
+var __textureSourceOffsets [%[2]d]vec2
+var __textureSourceRegionOrigin vec2
+var __textureSourceRegionSize vec2
+
+var ColorMBody mat4
+var ColorMTranslation vec4
+var UseColorM float
+var Rate float
+var Step vec2
+var TapNum float
+
+func __vertex(position vec2, texCoord vec2, color vec4) (vec4, vec2, vec4) {
+	return mat4(
+		2/__imageDstTextureSize.x, 0, 0, 0,
+		0, 2/__imageDstTextureSize.y, 0, 0,
+		0, 0, 1, 0,
+		-1, -1, 0, 1,
+	) * vec4(position, 0, 1), texCoord, color
+}
+
+// bilinear0 samples the source image 0 at the position p in pixels with the linear filter.
+func bilinear0(p vec2) vec4 {
+	size := __textureSizes[0]
+	// Shift 1/512 [texel] to avoid the tie-breaking issue, as the default shader does.
+	p0 := p - vec2(0.5) + vec2(1.0/512.0)
+	p1 := p + vec2(0.5) + vec2(1.0/512.0)
+	c0 := texture2D(__t0, p0/size)
+	c1 := texture2D(__t0, vec2(p1.x, p0.y)/size)
+	c2 := texture2D(__t0, vec2(p0.x, p1.y)/size)
+	c3 := texture2D(__t0, p1/size)
+	rate := fract(p0)
+	return mix(mix(c0, c1, rate.x), mix(c2, c3, rate.x), rate.y)
+}
+
+// bilinear1 samples the source image 1 at the position p in pixels with the linear filter.
+func bilinear1(p vec2) vec4 {
+	size := __textureSizes[1]
+	p0 := p - vec2(0.5) + vec2(1.0/512.0)
+	p1 := p + vec2(0.5) + vec2(1.0/512.0)
+	c0 := texture2D(__t1, p0/size)
+	c1 := texture2D(__t1, vec2(p1.x, p0.y)/size)
+	c2 := texture2D(__t1, vec2(p0.x, p1.y)/size)
+	c3 := texture2D(__t1, p1/size)
+	rate := fract(p0)
+	return mix(mix(c0, c1, rate.x), mix(c2, c3, rate.x), rate.y)
+}
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	size0 := __textureSizes[0]
+	origin0 := __textureSourceRegionOrigin * size0
+	end0 := origin0 + __textureSourceRegionSize*size0
+	origin1 := origin0 + __textureSourceOffsets[0]*size0
+	p := texCoord * size0
+
+	c := vec4(0)
+	for i := 0; i < %[3]d; i++ {
+		if float(i) >= TapNum {
+			break
+		}
+		q := clamp(p+(float(i)-(TapNum-1)/2)*Step, origin0, end0)
+		c0 := bilinear0(q)
+		c1 := bilinear1(origin1 + (q-origin0)/2)
+		c += mix(c0, c1, Rate)
+	}
+	c /= TapNum
+
+	if UseColorM > 0 {
+		// Un-premultiply alpha.
+		// When the alpha is 0, 1.0 - sign(alpha) is 1.0, which means division does nothing.
+		c.rgb /= c.a + (1 - sign(c.a))
+		c = ColorMBody*c + ColorMTranslation
+		c *= color
+		// Premultiply alpha.
+		c.rgb *= c.a
+	} else {
+		c *= vec4(color.rgb, 1) * color.a
+	}
+	return min(c, vec4(c.a))
+}
+`, graphics.ShaderImageNum, graphics.ShaderImageNum-1, maxAnisotropy)
+
+var theFilterShader *Shader
+
+func filterShader() *Shader {
+	if theFilterShader != nil {
+		return theFilterShader
+	}
+
+	fs := token.NewFileSet()
+	f, err := parser.ParseFile(fs, "", filterShaderSrc, parser.AllErrors)
+	if err != nil {
+		panic(fmt.Sprintf("mipmap: parsing the filter shader failed: %v", err))
+	}
+	ir, err := shader.Compile(fs, f, "__vertex", "Fragment", graphics.ShaderImageNum)
+	if err != nil {
+		panic(fmt.Sprintf("mipmap: compiling the filter shader failed: %v", err))
+	}
+	theFilterShader = NewShader(ir)
+	return theFilterShader
+}
+
+// footprint returns the lengths of the major and minor axes of the region on the source that one destination pixel
+// covers in the triangle, and the unit vector of the major axis on the source.
+// If the triangle is degenerated, footprint returns false.
+func footprint(vertices []float32, i0, i1, i2 uint16) (major, minor, ux, uy float64, ok bool) {
+	const n = graphics.VertexFloatNum
+	dx0, dy0 := float64(vertices[n*i0]), float64(vertices[n*i0+1])
+	sx0, sy0 := float64(vertices[n*i0+2]), float64(vertices[n*i0+3])
+	ex1, ey1 := float64(vertices[n*i1])-dx0, float64(vertices[n*i1+1])-dy0
+	fx1, fy1 := float64(vertices[n*i1+2])-sx0, float64(vertices[n*i1+3])-sy0
+	ex2, ey2 := float64(vertices[n*i2])-dx0, float64(vertices[n*i2+1])-dy0
+	fx2, fy2 := float64(vertices[n*i2+2])-sx0, float64(vertices[n*i2+3])-sy0
+
+	det := ex1*ey2 - ex2*ey1
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return 0, 0, 0, 0, false
+	}
+
+	// J is the Jacobian matrix from the destination to the source.
+	j00 := (fx1*ey2 - fx2*ey1) / det
+	j01 := (fx2*ex1 - fx1*ex2) / det
+	j10 := (fy1*ey2 - fy2*ey1) / det
+	j11 := (fy2*ex1 - fy1*ex2) / det
+
+	// The singular values of J are the square roots of the eigenvalues of J * J^T.
+	p := j00*j00 + j01*j01
+	q := j00*j10 + j01*j11
+	r := j10*j10 + j11*j11
+	d := math.Sqrt((p-r)*(p-r)/4 + q*q)
+	l1 := (p+r)/2 + d
+	l2 := (p+r)/2 - d
+	if l2 < 0 {
+		l2 = 0
+	}
+	major = math.Sqrt(l1)
+	minor = math.Sqrt(l2)
+
+	switch {
+	case q != 0:
+		ux, uy = l1-r, q
+	case p >= r:
+		ux, uy = 1, 0
+	default:
+		ux, uy = 0, 1
+	}
+	if l := math.Hypot(ux, uy); l > 0 {
+		ux /= l
+		uy /= l
+	}
+	return major, minor, ux, uy, true
+}
+
+// drawTrianglesWithQuality draws the triangles with the source src sampled with the given quality.
+//
+// drawTrianglesWithQuality returns false when the quality doesn't make any difference from the regular linear
+// filter. In this case, nothing is drawn.
+func (m *Mipmap) drawTrianglesWithQuality(src *Mipmap, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.Blend, dstRegion graphicsdriver.Region, fillRule graphicsdriver.FillRule, quality FilterQuality) bool {
+	// Adopt the triangle that is the least minified, as the level is determined for the whole draw call.
+	lod := math.Inf(1)
+	var tapNum int
+	var stepX, stepY float64
+	for i := 0; i < len(indices)/3; i++ {
+		major, minor, ux, uy, ok := footprint(vertices, indices[3*i], indices[3*i+1], indices[3*i+2])
+		if !ok {
+			continue
+		}
+		n := 1
+		if quality == FilterQualityAnisotropic {
+			if minor > 0 {
+				n = int(math.Min(math.Ceil(major/minor), maxAnisotropy))
+			} else {
+				n = maxAnisotropy
+			}
+		}
+		l := math.Log2(major / float64(n))
+		if l >= lod {
+			continue
+		}
+		lod = l
+		tapNum = n
+		stepX = ux * major / float64(n)
+		stepY = uy * major / float64(n)
+	}
+	if math.IsInf(lod, 1) || math.IsNaN(lod) {
+		return false
+	}
+
+	level := 0
+	var rate float64
+	if lod > 0 {
+		level = int(math.Floor(lod))
+		rate = lod - float64(level)
+	}
+	if level >= maxFilterLevel {
+		level = maxFilterLevel
+		rate = 0
+	}
+
+	img0 := src.orig
+	for level > 0 {
+		if img := src.level(level); img != nil {
+			img0 = img
+			break
+		}
+		level--
+		rate = 0
+	}
+	img1 := img0
+	if rate > 0 {
+		if img := src.level(level + 1); img != nil {
+			img1 = img
+		} else {
+			rate = 0
+		}
+	}
+	if level == 0 && rate == 0 && tapNum <= 1 {
+		return false
+	}
+
+	// Calculate the dirty region before DrawTriangles, which might modify the vertices.
+	dirty := dirtyRegionFromVertices(vertices, dstRegion)
+
+	s := pow2(level)
+	if level != 0 {
+		const n = graphics.VertexFloatNum
+		for i := 0; i < len(vertices)/n; i++ {
+			vertices[i*n+2] /= s
+			vertices[i*n+3] /= s
+		}
+	}
+
+	var body [16]float32
+	var translate [4]float32
+	colorm.Elements(&body, &translate)
+	var useColorM float32
+	if !colorm.IsIdentity() {
+		useColorM = 1
+	}
+	uniforms := []graphicsdriver.Uniform{
+		{Float32s: body[:]},
+		{Float32s: translate[:]},
+		{Float32: useColorM},
+		{Float32: float32(rate)},
+		{Float32s: []float32{float32(stepX) / s, float32(stepY) / s}},
+		{Float32: float32(tapNum)},
+	}
+
+	srcRegion := graphicsdriver.Region{
+		Width:  float32(sizeForLevel(src.width, level)),
+		Height: float32(sizeForLevel(src.height, level)),
+	}
+
+	imgs := [graphics.ShaderImageNum]*buffered.Image{img0, img1}
+	m.orig.DrawTriangles(imgs, vertices, indices, affine.ColorMIdentity{}, mode, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, srcRegion, [graphics.ShaderImageNum - 1][2]float32{}, filterShader().shader, uniforms, fillRule)
+	m.markDirty(dirty)
+	return true
+}
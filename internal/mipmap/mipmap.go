@@ -16,6 +16,7 @@ package mipmap
 
 import (
 	"fmt"
+	"image"
 	"math"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/affine"
@@ -33,14 +34,36 @@ func EndFrame() error {
 	return buffered.EndFrame()
 }
 
+func FrameHash() uint64 {
+	return buffered.FrameHash()
+}
+
+// PushDebugGroup starts a group of the following image operations with the given name for graphics debugging
+// tools.
+func PushDebugGroup(name string) {
+	buffered.PushDebugGroup(name)
+}
+
+// PopDebugGroup ends the group started by the last PushDebugGroup.
+func PopDebugGroup() {
+	buffered.PopDebugGroup()
+}
+
 // Mipmap is a set of buffered.Image sorted by the order of mipmap level.
 // The level 0 image is a regular image and higher-level images are used for mipmap.
+//
+// When the level 0 image is modified, the mipmap images are not regenerated immediately.
+// Instead, the modified regions are recorded as dirty regions, and only the dirty regions are regenerated when
+// the mipmap images are actually used.
 type Mipmap struct {
 	width    int
 	height   int
 	volatile bool
 	orig     *buffered.Image
 	imgs     map[int]*buffered.Image
+
+	// dirtyRegions is the regions of the mipmap images that need to be regenerated, in the level 0's coordinates.
+	dirtyRegions map[int]image.Rectangle
 }
 
 func New(width, height int) *Mipmap {
@@ -63,6 +86,19 @@ func (m *Mipmap) SetIndependent(independent bool) {
 	m.orig.SetIndependent(independent)
 }
 
+// IsOnAtlas reports whether the original image is currently on an atlas.
+func (m *Mipmap) IsOnAtlas() bool {
+	return m.orig.IsOnAtlas()
+}
+
+func (m *Mipmap) SetSampleCount(sampleCount int) {
+	m.orig.SetSampleCount(sampleCount)
+}
+
+func (m *Mipmap) SetFormat(format graphicsdriver.PixelFormat) {
+	m.orig.SetFormat(format)
+}
+
 func (m *Mipmap) SetVolatile(volatile bool) {
 	if m.volatile == volatile {
 		return
@@ -75,27 +111,39 @@ func (m *Mipmap) SetVolatile(volatile bool) {
 	m.orig.SetVolatile(volatile)
 }
 
-func (m *Mipmap) DumpScreenshot(name string, blackbg bool) error {
-	return m.orig.DumpScreenshot(name, blackbg)
-}
-
 func (m *Mipmap) ReplacePixels(pix []byte, x, y, width, height int) error {
 	if err := m.orig.ReplacePixels(pix, x, y, width, height); err != nil {
 		return err
 	}
-	m.disposeMipmaps()
+	m.markDirty(image.Rect(x, y, x+width, y+height))
 	return nil
 }
 
-func (m *Mipmap) Pixels(x, y, width, height int) ([]byte, error) {
-	return m.orig.Pixels(x, y, width, height)
+// ReadPixels reads the pixels in the given region into pixels.
+func (m *Mipmap) ReadPixels(pixels []byte, x, y, width, height int) error {
+	return m.orig.ReadPixels(pixels, x, y, width, height)
 }
 
-func (m *Mipmap) DrawTriangles(srcs [graphics.ShaderImageNum]*Mipmap, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool, canSkipMipmap bool) {
+// ReadPixelsAsync reads the pixels in the given region without waiting for GPU.
+// f is called with the pixels a few frames later.
+func (m *Mipmap) ReadPixelsAsync(x, y, width, height int, f func([]byte)) {
+	m.orig.ReadPixelsAsync(x, y, width, height, f)
+}
+
+// DrawTriangles draws the triangles with the sources.
+//
+// quality is used only when the source is sampled with the linear filter and the unsafe address without a shader.
+func (m *Mipmap) DrawTriangles(srcs [graphics.ShaderImageNum]*Mipmap, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, quality FilterQuality, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform, fillRule graphicsdriver.FillRule, canSkipMipmap bool) {
 	if len(indices) == 0 {
 		return
 	}
 
+	if quality != FilterQualityDefault && shader == nil && filter == graphicsdriver.FilterLinear && address == graphicsdriver.AddressUnsafe && srcs[0] != nil && !srcs[0].volatile {
+		if m.drawTrianglesWithQuality(srcs[0], vertices, indices, colorm, mode, dstRegion, fillRule, quality) {
+			return
+		}
+	}
+
 	level := 0
 	// TODO: Do we need to check all the sources' states of being volatile?
 	if !canSkipMipmap && srcs[0] != nil && !srcs[0].volatile && filter != graphicsdriver.FilterScreen {
@@ -154,8 +202,103 @@ func (m *Mipmap) DrawTriangles(srcs [graphics.ShaderImageNum]*Mipmap, vertices [
 		imgs[i] = src.orig
 	}
 
-	m.orig.DrawTriangles(imgs, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, subimageOffsets, s, uniforms, evenOdd)
-	m.disposeMipmaps()
+	// Calculate the dirty region before DrawTriangles, which might modify the vertices.
+	dirty := dirtyRegionFromVertices(vertices, dstRegion)
+	m.orig.DrawTriangles(imgs, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, subimageOffsets, s, uniforms, fillRule)
+	m.markDirty(dirty)
+}
+
+// DrawTrianglesInstanced draws the triangles with the src image repeatedly for each instance.
+//
+// The mipmap images of src are never used.
+func (m *Mipmap) DrawTrianglesInstanced(src *Mipmap, vertices []float32, indices []uint16, instances []float32, colorm affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region) {
+	if len(indices) == 0 || len(instances) == 0 {
+		return
+	}
+
+	// The instances can be anywhere in the destination region. Regard the whole region as dirty.
+	dirty := dirtyRegionFromVertices(nil, dstRegion)
+	m.orig.DrawTrianglesInstanced(src.orig, vertices, indices, instances, colorm, mode, filter, address, dstRegion, srcRegion)
+	m.markDirty(dirty)
+}
+
+// DrawTrianglesMRT draws the triangles with the shader onto the multiple destinations at the same time.
+//
+// The destinations are packed from the front in dsts, and the rest are nil.
+// The mipmap images of the sources are never used.
+func DrawTrianglesMRT(dsts [graphics.ShaderDstImageNum]*Mipmap, srcs [graphics.ShaderImageNum]*Mipmap, vertices []float32, indices []uint16, mode graphicsdriver.Blend, dstRegion, srcRegion graphicsdriver.Region, subimageOffsets [graphics.ShaderImageNum - 1][2]float32, shader *Shader, uniforms []graphicsdriver.Uniform) {
+	if len(indices) == 0 {
+		return
+	}
+
+	var imgs [graphics.ShaderImageNum]*buffered.Image
+	for i, src := range srcs {
+		if src == nil {
+			continue
+		}
+		imgs[i] = src.orig
+	}
+	var dstImgs [graphics.ShaderDstImageNum]*buffered.Image
+	for i, dst := range dsts {
+		if dst == nil {
+			continue
+		}
+		dstImgs[i] = dst.orig
+	}
+
+	// Calculate the dirty region before DrawTriangles, which might modify the vertices.
+	dirty := dirtyRegionFromVertices(vertices, dstRegion)
+	buffered.DrawTrianglesMRT(dstImgs, imgs, vertices, indices, mode, dstRegion, srcRegion, subimageOffsets, shader.shader, uniforms)
+	for _, dst := range dsts {
+		if dst == nil {
+			continue
+		}
+		dst.markDirty(dirty)
+	}
+}
+
+// dirtyRegionFromVertices returns the region that the triangles can modify.
+func dirtyRegionFromVertices(vertices []float32, dstRegion graphicsdriver.Region) image.Rectangle {
+	const n = graphics.VertexFloatNum
+	minX, minY := float32(math.Inf(1)), float32(math.Inf(1))
+	maxX, maxY := float32(math.Inf(-1)), float32(math.Inf(-1))
+	for i := 0; i < len(vertices)/n; i++ {
+		x, y := vertices[i*n], vertices[i*n+1]
+		if minX > x {
+			minX = x
+		}
+		if minY > y {
+			minY = y
+		}
+		if maxX < x {
+			maxX = x
+		}
+		if maxY < y {
+			maxY = y
+		}
+	}
+	// Vertices might have NaN or infinite values. In this case, regard the whole destination region as dirty.
+	r := image.Rect(int(dstRegion.X), int(dstRegion.Y), int(math.Ceil(float64(dstRegion.X+dstRegion.Width))), int(math.Ceil(float64(dstRegion.Y+dstRegion.Height))))
+	if minX > maxX || minY > maxY || math.IsInf(float64(minX), 0) || math.IsInf(float64(minY), 0) || math.IsInf(float64(maxX), 0) || math.IsInf(float64(maxY), 0) {
+		return r
+	}
+	return r.Intersect(image.Rect(int(math.Floor(float64(minX))), int(math.Floor(float64(minY))), int(math.Ceil(float64(maxX))), int(math.Ceil(float64(maxY)))))
+}
+
+// markDirty records the region r of the level 0 image as modified.
+func (m *Mipmap) markDirty(r image.Rectangle) {
+	if r.Empty() {
+		return
+	}
+	for level, img := range m.imgs {
+		if img == nil {
+			continue
+		}
+		if m.dirtyRegions == nil {
+			m.dirtyRegions = map[int]image.Rectangle{}
+		}
+		m.dirtyRegions[level] = m.dirtyRegions[level].Union(r)
+	}
 }
 
 func (m *Mipmap) setImg(level int, img *buffered.Image) {
@@ -175,31 +318,34 @@ func (m *Mipmap) level(level int) *buffered.Image {
 	}
 
 	if img, ok := m.imgs[level]; ok {
+		if img == nil {
+			return nil
+		}
+		if r, ok := m.dirtyRegions[level]; ok {
+			// Regenerate only the dirty region. The source level is regenerated recursively if needed.
+			src := m.orig
+			if level > 1 {
+				src = m.level(level - 1)
+			}
+			m.drawLevel(img, src, level, r)
+			delete(m.dirtyRegions, level)
+		}
 		return img
 	}
 
 	var src *buffered.Image
-	var vs []float32
-	var filter graphicsdriver.Filter
 	switch {
 	case level == 1:
 		src = m.orig
-		vs = graphics.QuadVertices(0, 0, float32(m.width), float32(m.height), 0.5, 0, 0, 0.5, 0, 0, 1, 1, 1, 1)
-		filter = graphicsdriver.FilterLinear
 	case level > 1:
 		src = m.level(level - 1)
 		if src == nil {
 			m.setImg(level, nil)
 			return nil
 		}
-		w := sizeForLevel(m.width, level-1)
-		h := sizeForLevel(m.height, level-1)
-		vs = graphics.QuadVertices(0, 0, float32(w), float32(h), 0.5, 0, 0, 0.5, 0, 0, 1, 1, 1, 1)
-		filter = graphicsdriver.FilterLinear
 	default:
 		panic(fmt.Sprintf("ebiten: invalid level: %d", level))
 	}
-	is := graphics.QuadIndices()
 
 	w2 := sizeForLevel(m.width, level-1)
 	h2 := sizeForLevel(m.height, level-1)
@@ -216,17 +362,39 @@ func (m *Mipmap) level(level int) *buffered.Image {
 	}
 	s := buffered.NewImage(w2, h2)
 	s.SetVolatile(m.volatile)
+	m.drawLevel(s, src, level, image.Rect(0, 0, m.width, m.height))
+	m.setImg(level, s)
+
+	return m.imgs[level]
+}
+
+// drawLevel renders the region r of the level-1 image src onto the level image dst with scaling down.
+// r is in the level 0's coordinates.
+func (m *Mipmap) drawLevel(dst, src *buffered.Image, level int, r image.Rectangle) {
+	w := sizeForLevel(m.width, level)
+	h := sizeForLevel(m.height, level)
+
+	// Convert r to the level's coordinates. A pixel at the level is affected by 2^level pixels at the level 0.
+	s := 1 << uint(level)
+	x0 := r.Min.X / s
+	y0 := r.Min.Y / s
+	x1 := (r.Max.X + s - 1) / s
+	y1 := (r.Max.Y + s - 1) / s
+	dr := image.Rect(x0, y0, x1, y1).Intersect(image.Rect(0, 0, w, h))
+	if dr.Empty() {
+		return
+	}
 
+	// Each pixel at the level is the average of the 2x2 pixels at the source level with the linear filter.
+	vs := graphics.QuadVertices(float32(2*dr.Min.X), float32(2*dr.Min.Y), float32(2*dr.Max.X), float32(2*dr.Max.Y), 0.5, 0, 0, 0.5, float32(dr.Min.X), float32(dr.Min.Y), 1, 1, 1, 1)
+	is := graphics.QuadIndices()
 	dstRegion := graphicsdriver.Region{
 		X:      0,
 		Y:      0,
-		Width:  float32(w2),
-		Height: float32(h2),
+		Width:  float32(w),
+		Height: float32(h),
 	}
-	s.DrawTriangles([graphics.ShaderImageNum]*buffered.Image{src}, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, filter, graphicsdriver.AddressUnsafe, dstRegion, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, false)
-	m.setImg(level, s)
-
-	return m.imgs[level]
+	dst.DrawTriangles([graphics.ShaderImageNum]*buffered.Image{src}, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterLinear, graphicsdriver.AddressUnsafe, dstRegion, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll)
 }
 
 func sizeForLevel(x int, level int) int {
@@ -254,6 +422,9 @@ func (m *Mipmap) disposeMipmaps() {
 	for k := range m.imgs {
 		delete(m.imgs, k)
 	}
+	for k := range m.dirtyRegions {
+		delete(m.dirtyRegions, k)
+	}
 }
 
 // mipmapLevel returns an appropriate mipmap level for the given distance.
@@ -331,6 +502,19 @@ func NewShader(program *shaderir.Program) *Shader {
 	}
 }
 
+// NewShaderAsync creates a shader without waiting for the compilation.
+// f is called after the shader is compiled.
+func NewShaderAsync(program *shaderir.Program, f func()) *Shader {
+	return &Shader{
+		shader: buffered.NewShaderAsync(program, f),
+	}
+}
+
+// Precompile creates the pipeline states of the shader for the given destination format and blends in advance.
+func (s *Shader) Precompile(format graphicsdriver.PixelFormat, blends []graphicsdriver.Blend) {
+	s.shader.Precompile(format, blends)
+}
+
 func (s *Shader) MarkDisposed() {
 	s.shader.MarkDisposed()
 	s.shader = nil
@@ -147,6 +147,21 @@ func (p *Page) Size() int {
 	return p.size
 }
 
+// UsedArea returns the total area of the allocated nodes in pixels.
+func (p *Page) UsedArea() int {
+	if p.root == nil {
+		return 0
+	}
+	var area int
+	_ = walk(p.root, func(n *Node) error {
+		if n.used {
+			area += n.width * n.height
+		}
+		return nil
+	})
+	return area
+}
+
 func (p *Page) SetMaxSize(size int) {
 	if p.maxSize > size {
 		panic("packing: maxSize cannot be decreased")
@@ -70,14 +70,15 @@ type drawTrianglesHistoryItem struct {
 	vertices  []float32
 	indices   []uint16
 	colorm    affine.ColorM
-	mode      graphicsdriver.CompositeMode
+	mode      graphicsdriver.Blend
 	filter    graphicsdriver.Filter
 	address   graphicsdriver.Address
 	dstRegion graphicsdriver.Region
 	srcRegion graphicsdriver.Region
 	shader    *Shader
 	uniforms  []graphicsdriver.Uniform
-	evenOdd   bool
+	fillRule  graphicsdriver.FillRule
+	instances []float32
 }
 
 // Image represents an image that can be restored when GL context is lost.
@@ -104,6 +105,12 @@ type Image struct {
 
 	// priority indicates whether the image is restored in high priority when context-lost happens.
 	priority bool
+
+	// sampleCount is the number of samples per pixel for multisample antialiasing.
+	sampleCount int
+
+	// format is the pixel format of the image's texture.
+	format graphicsdriver.PixelFormat
 }
 
 var emptyImage *Image
@@ -118,10 +125,11 @@ func ensureEmptyImage() *Image {
 	// w and h are the empty image's size. They indicate the 1x1 image with 1px padding around.
 	const w, h = 3, 3
 	emptyImage = &Image{
-		image:    graphicscommand.NewImage(w, h),
-		width:    w,
-		height:   h,
-		priority: true,
+		image:       graphicscommand.NewImage(w, h),
+		width:       w,
+		height:      h,
+		priority:    true,
+		sampleCount: 1,
 	}
 	pix := make([]byte, 4*w*h)
 	for i := range pix {
@@ -141,14 +149,27 @@ func ensureEmptyImage() *Image {
 //
 // Note that Dispose is not called automatically.
 func NewImage(width, height int) *Image {
+	return NewImageWithAttributes(width, height, 1, graphicsdriver.PixelFormatRGBA8)
+}
+
+// NewImageWithAttributes creates an empty image with the given attributes.
+// sampleCount is the number of samples per pixel for multisample antialiasing.
+// format is the pixel format of the image's texture.
+//
+// The returned image is cleared.
+//
+// Note that Dispose is not called automatically.
+func NewImageWithAttributes(width, height int, sampleCount int, format graphicsdriver.PixelFormat) *Image {
 	if !graphicsDriverInitialized {
 		panic("restorable: graphics driver must be ready at NewImage but not")
 	}
 
 	i := &Image{
-		image:  graphicscommand.NewImage(width, height),
-		width:  width,
-		height: height,
+		image:       graphicscommand.NewImageWithAttributes(width, height, sampleCount, format),
+		width:       width,
+		height:      height,
+		sampleCount: sampleCount,
+		format:      format,
 	}
 	clearImage(i.image)
 	theImages.add(i)
@@ -199,7 +220,7 @@ func (i *Image) Extend(width, height int) *Image {
 		Width:  float32(sw),
 		Height: float32(sh),
 	}
-	newImg.DrawTriangles(srcs, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, false)
+	newImg.DrawTriangles(srcs, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendCopy, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dr, graphicsdriver.Region{}, nil, nil, graphicsdriver.FillAll)
 
 	// Overwrite the history as if the image newImg is created only by ReplacePixels. Now drawTrianglesHistory
 	// and basePixels cannot be mixed.
@@ -262,7 +283,7 @@ func clearImage(i *graphicscommand.Image) {
 		Width:  float32(dw),
 		Height: float32(dh),
 	}
-	i.DrawTriangles(srcs, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.CompositeModeClear, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, graphicsdriver.Region{}, nil, nil, false)
+	i.DrawTriangles(srcs, offsets, vs, is, affine.ColorMIdentity{}, graphicsdriver.BlendClear, graphicsdriver.FilterNearest, graphicsdriver.AddressUnsafe, dstRegion, graphicsdriver.Region{}, nil, nil, graphicsdriver.FillAll)
 }
 
 // BasePixelsForTesting returns the image's basePixels for testing.
@@ -365,10 +386,27 @@ func (i *Image) ReplacePixels(pixels []byte, x, y, width, height int) {
 //   5: Color G
 //   6: Color B
 //   7: Color Y
-func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool) {
+func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, fillRule graphicsdriver.FillRule) {
 	if i.priority {
 		panic("restorable: DrawTriangles cannot be called on a priority image")
 	}
+	i.drawTriangles(srcs, offsets, vertices, indices, nil, colorm, mode, filter, address, dstRegion, srcRegion, shader, uniforms, fillRule)
+}
+
+// DrawTrianglesInstanced draws triangles with the given image repeatedly for each instance.
+//
+// Each instance has graphics.InstanceFloatNum values. See graphicscommand.Image.DrawTrianglesInstanced.
+func (i *Image) DrawTrianglesInstanced(src *Image, vertices []float32, indices []uint16, instances []float32, colorm affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region) {
+	if i.priority {
+		panic("restorable: DrawTrianglesInstanced cannot be called on a priority image")
+	}
+	if len(instances) == 0 {
+		return
+	}
+	i.drawTriangles([graphics.ShaderImageNum]*Image{src}, [graphics.ShaderImageNum - 1][2]float32{}, vertices, indices, instances, colorm, mode, filter, address, dstRegion, srcRegion, nil, nil, graphicsdriver.FillAll)
+}
+
+func (i *Image) drawTriangles(srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, instances []float32, colorm affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, fillRule graphicsdriver.FillRule) {
 	if len(vertices) == 0 {
 		return
 	}
@@ -389,7 +427,7 @@ func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, offsets [gra
 	if srcstale || i.screen || !NeedsRestoring() || i.volatile {
 		i.makeStale()
 	} else {
-		i.appendDrawTrianglesHistory(srcs, offsets, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, shader, uniforms, evenOdd)
+		i.appendDrawTrianglesHistory(srcs, offsets, vertices, indices, instances, colorm, mode, filter, address, dstRegion, srcRegion, shader, uniforms, fillRule)
 	}
 
 	var s *graphicscommand.Shader
@@ -406,11 +444,48 @@ func (i *Image) DrawTriangles(srcs [graphics.ShaderImageNum]*Image, offsets [gra
 		}
 		s = shader.shader
 	}
-	i.image.DrawTriangles(imgs, offsets, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, s, uniforms, evenOdd)
+	if instances != nil {
+		i.image.DrawTrianglesInstanced(imgs[0], vertices, indices, instances, colorm, mode, filter, address, dstRegion, srcRegion)
+		return
+	}
+	i.image.DrawTriangles(imgs, offsets, vertices, indices, colorm, mode, filter, address, dstRegion, srcRegion, s, uniforms, fillRule)
+}
+
+// DrawTrianglesMRT draws triangles with the given shader onto the multiple destinations at the same time.
+//
+// The destinations are packed from the front in dsts, and the rest are nil.
+//
+// The drawing history for multiple render targets is not recorded, and the destinations become stale instead.
+func DrawTrianglesMRT(dsts [graphics.ShaderDstImageNum]*Image, srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, mode graphicsdriver.Blend, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform) {
+	if len(vertices) == 0 {
+		return
+	}
+
+	var dstImgs [graphics.ShaderDstImageNum]*graphicscommand.Image
+	for i, dst := range dsts {
+		if dst == nil {
+			continue
+		}
+		if dst.priority {
+			panic("restorable: DrawTrianglesMRT cannot be called on a priority image")
+		}
+		theImages.makeStaleIfDependingOn(dst)
+		dst.makeStale()
+		dstImgs[i] = dst.image
+	}
+
+	var imgs [graphics.ShaderImageNum]*graphicscommand.Image
+	for i, src := range srcs {
+		if src == nil {
+			continue
+		}
+		imgs[i] = src.image
+	}
+	graphicscommand.DrawTrianglesMRT(dstImgs, imgs, offsets, vertices, indices, mode, dstRegion, srcRegion, shader.shader, uniforms)
 }
 
 // appendDrawTrianglesHistory appends a draw-image history item to the image.
-func (i *Image) appendDrawTrianglesHistory(srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, colorm affine.ColorM, mode graphicsdriver.CompositeMode, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, evenOdd bool) {
+func (i *Image) appendDrawTrianglesHistory(srcs [graphics.ShaderImageNum]*Image, offsets [graphics.ShaderImageNum - 1][2]float32, vertices []float32, indices []uint16, instances []float32, colorm affine.ColorM, mode graphicsdriver.Blend, filter graphicsdriver.Filter, address graphicsdriver.Address, dstRegion, srcRegion graphicsdriver.Region, shader *Shader, uniforms []graphicsdriver.Uniform, fillRule graphicsdriver.FillRule) {
 	if i.stale || i.volatile || i.screen {
 		return
 	}
@@ -429,6 +504,12 @@ func (i *Image) appendDrawTrianglesHistory(srcs [graphics.ShaderImageNum]*Image,
 	is := make([]uint16, len(indices))
 	copy(is, indices)
 
+	var insts []float32
+	if instances != nil {
+		insts = make([]float32, len(instances))
+		copy(insts, instances)
+	}
+
 	item := &drawTrianglesHistoryItem{
 		images:    srcs,
 		offsets:   offsets,
@@ -442,7 +523,8 @@ func (i *Image) appendDrawTrianglesHistory(srcs [graphics.ShaderImageNum]*Image,
 		srcRegion: srcRegion,
 		shader:    shader,
 		uniforms:  uniforms,
-		evenOdd:   evenOdd,
+		fillRule:  fillRule,
+		instances: insts,
 	}
 	i.drawTrianglesHistory = append(i.drawTrianglesHistory, item)
 }
@@ -475,6 +557,14 @@ func (i *Image) At(x, y int) (byte, byte, byte, byte, error) {
 	return r, g, b, a, nil
 }
 
+// ReadPixelsAsync reads the pixels in the given region without waiting for GPU.
+// f is called with the pixels a few frames later.
+//
+// Note that this must not be called until context is available.
+func (i *Image) ReadPixelsAsync(x, y, width, height int, f func([]byte)) {
+	i.image.ReadPixelsAsync(x, y, width, height, f)
+}
+
 // makeStaleIfDependingOn makes the image stale if the image depends on target.
 func (i *Image) makeStaleIfDependingOn(target *Image) {
 	if i.stale {
@@ -588,7 +678,7 @@ func (i *Image) restore() error {
 		return nil
 	}
 	if i.volatile {
-		i.image = graphicscommand.NewImage(w, h)
+		i.image = graphicscommand.NewImageWithAttributes(w, h, i.sampleCount, i.format)
 		clearImage(i.image)
 		return nil
 	}
@@ -596,7 +686,7 @@ func (i *Image) restore() error {
 		panic("restorable: pixels must not be stale when restoring")
 	}
 
-	gimg := graphicscommand.NewImage(w, h)
+	gimg := graphicscommand.NewImageWithAttributes(w, h, i.sampleCount, i.format)
 	// Clear the image explicitly.
 	if i != ensureEmptyImage() {
 		// As clearImage uses emptyImage, clearImage cannot be called on emptyImage.
@@ -621,7 +711,11 @@ func (i *Image) restore() error {
 			}
 			imgs[i] = img.image
 		}
-		gimg.DrawTriangles(imgs, c.offsets, c.vertices, c.indices, c.colorm, c.mode, c.filter, c.address, c.dstRegion, c.srcRegion, s, c.uniforms, c.evenOdd)
+		if c.instances != nil {
+			gimg.DrawTrianglesInstanced(imgs[0], c.vertices, c.indices, c.instances, c.colorm, c.mode, c.filter, c.address, c.dstRegion, c.srcRegion)
+			continue
+		}
+		gimg.DrawTriangles(imgs, c.offsets, c.vertices, c.indices, c.colorm, c.mode, c.filter, c.address, c.dstRegion, c.srcRegion, s, c.uniforms, c.fillRule)
 	}
 
 	if len(i.drawTrianglesHistory) > 0 {
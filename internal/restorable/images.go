@@ -70,6 +70,11 @@ func ResolveStaleImages() error {
 	if err := graphicscommand.FlushCommands(); err != nil {
 		return err
 	}
+	graphicscommand.EndGPUFrame()
+	graphicscommand.ResolvePendingPixels()
+	if err := graphicscommand.ResolvePendingShaders(); err != nil {
+		return err
+	}
 	if !NeedsRestoring() {
 		return nil
 	}
@@ -124,6 +129,18 @@ func RestoreIfNeeded() error {
 // DumpImages dumps all the current images to the specified directory.
 //
 // This is for testing usage.
+// PushDebugGroup starts a group of the following commands with the given name for graphics debugging tools.
+//
+// The groups are not recorded in the drawing history and are not reproduced when restoring.
+func PushDebugGroup(name string) {
+	graphicscommand.PushDebugGroup(name)
+}
+
+// PopDebugGroup ends the group started by the last PushDebugGroup.
+func PopDebugGroup() {
+	graphicscommand.PopDebugGroup()
+}
+
 func DumpImages(dir string) error {
 	for img := range theImages.images {
 		if err := img.Dump(filepath.Join(dir, "*.png"), false, image.Rect(0, 0, img.width, img.height)); err != nil {
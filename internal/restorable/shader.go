@@ -16,6 +16,7 @@ package restorable
 
 import (
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicscommand"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
 )
 
@@ -33,6 +34,22 @@ func NewShader(program *shaderir.Program) *Shader {
 	return s
 }
 
+// NewShaderAsync creates a shader without waiting for the compilation.
+// f is called after the shader is compiled.
+func NewShaderAsync(program *shaderir.Program, f func()) *Shader {
+	s := &Shader{
+		shader: graphicscommand.NewShaderAsync(program, f),
+		ir:     program,
+	}
+	theImages.addShader(s)
+	return s
+}
+
+// Precompile creates the pipeline states of the shader for the given destination format and blends in advance.
+func (s *Shader) Precompile(format graphicsdriver.PixelFormat, blends []graphicsdriver.Blend) {
+	s.shader.Precompile(format, blends)
+}
+
 func (s *Shader) Dispose() {
 	theImages.removeShader(s)
 	s.shader.Dispose()
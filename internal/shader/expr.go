@@ -21,6 +21,7 @@ import (
 	"go/token"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
 )
@@ -66,7 +67,7 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 				},
 			}, []shaderir.Type{{Main: shaderir.Float}}, nil, true
 		default:
-			cs.addError(e.Pos(), fmt.Sprintf("literal not implemented: %#v", e))
+			cs.addError(e.Pos(), ErrorCodeUnsupported, fmt.Sprintf("literal not implemented: %#v", e))
 		}
 
 	case *ast.BinaryExpr:
@@ -78,7 +79,7 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 			return nil, nil, nil, false
 		}
 		if len(lhs) != 1 {
-			cs.addError(e.Pos(), fmt.Sprintf("multiple-value context is not available at a binary operator: %s", e.X))
+			cs.addError(e.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("multiple-value context is not available at a binary operator: %s", e.X))
 			return nil, nil, nil, false
 		}
 		stmts = append(stmts, ss...)
@@ -89,7 +90,7 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 			return nil, nil, nil, false
 		}
 		if len(rhs) != 1 {
-			cs.addError(e.Pos(), fmt.Sprintf("multiple-value context is not available at a binary operator: %s", e.Y))
+			cs.addError(e.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("multiple-value context is not available at a binary operator: %s", e.Y))
 			return nil, nil, nil, false
 		}
 		stmts = append(stmts, ss...)
@@ -110,7 +111,8 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 				v = gconstant.MakeBool(gconstant.Compare(lhs[0].Const, op, rhs[0].Const))
 				t = shaderir.Type{Main: shaderir.Bool}
 			default:
-				if op == token.REM {
+				switch op {
+				case token.REM, token.SHL, token.SHR, token.AND, token.OR, token.XOR, token.AND_NOT:
 					if lhs[0].Const.Kind() != gconstant.Int || rhs[0].Const.Kind() != gconstant.Int {
 						var wrongTypeName string
 						if lhs[0].Const.Kind() != gconstant.Int {
@@ -118,11 +120,20 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 						} else {
 							wrongTypeName = goConstantKindString(rhs[0].Const.Kind())
 						}
-						cs.addError(e.Pos(), fmt.Sprintf("invalid operation: operator %% not defined on untyped %s", wrongTypeName))
+						cs.addError(e.Pos(), ErrorCodeInvalidOperation, fmt.Sprintf("invalid operation: operator %s not defined on untyped %s", op, wrongTypeName))
 						return nil, nil, nil, false
 					}
 				}
-				v = gconstant.BinaryOp(lhs[0].Const, op, rhs[0].Const)
+				if op == token.SHL || op == token.SHR {
+					s, ok := gconstant.Uint64Val(rhs[0].Const)
+					if !ok || s >= 32 {
+						cs.addError(e.Pos(), ErrorCodeInvalidConstant, fmt.Sprintf("invalid shift count: %s", rhs[0].Const.String()))
+						return nil, nil, nil, false
+					}
+					v = gconstant.Shift(lhs[0].Const, op, uint(s))
+				} else {
+					v = gconstant.BinaryOp(lhs[0].Const, op, rhs[0].Const)
+				}
 				if v.Kind() == gconstant.Float {
 					t = shaderir.Type{Main: shaderir.Float}
 				} else {
@@ -138,9 +149,28 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 			}, []shaderir.Type{t}, stmts, true
 		}
 
-		op, ok := shaderir.OpFromToken(e.Op)
+		// x &^ y is x & ^y.
+		tok := e.Op
+		if tok == token.AND_NOT {
+			tok = token.AND
+			if rhs[0].Type == shaderir.NumberExpr {
+				if rhs[0].Const.Kind() != gconstant.Int {
+					cs.addError(e.Pos(), ErrorCodeInvalidOperation, fmt.Sprintf("invalid operation: operator ^ not defined on untyped %s", goConstantKindString(rhs[0].Const.Kind())))
+					return nil, nil, nil, false
+				}
+				rhs[0].Const = gconstant.UnaryOp(token.XOR, rhs[0].Const, 0)
+			} else {
+				rhs[0] = shaderir.Expr{
+					Type:  shaderir.Unary,
+					Op:    shaderir.ComplementOp,
+					Exprs: []shaderir.Expr{rhs[0]},
+				}
+			}
+		}
+
+		op, ok := shaderir.OpFromToken(tok)
 		if !ok {
-			cs.addError(e.Pos(), fmt.Sprintf("unexpected operator: %s", e.Op))
+			cs.addError(e.Pos(), ErrorCodeInvalidOperation, fmt.Sprintf("unexpected operator: %s", e.Op))
 			return nil, nil, nil, false
 		}
 
@@ -148,18 +178,28 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 		switch {
 		case op == shaderir.LessThanOp || op == shaderir.LessThanEqualOp || op == shaderir.GreaterThanOp || op == shaderir.GreaterThanEqualOp || op == shaderir.EqualOp || op == shaderir.NotEqualOp || op == shaderir.AndAnd || op == shaderir.OrOr:
 			// TODO: Check types of the operands.
+			if lhs[0].Type == shaderir.NumberExpr && rhst.Main == shaderir.Int {
+				if !cs.forceToInt(e, &lhs[0]) {
+					return nil, nil, nil, false
+				}
+			}
+			if rhs[0].Type == shaderir.NumberExpr && lhst.Main == shaderir.Int {
+				if !cs.forceToInt(e, &rhs[0]) {
+					return nil, nil, nil, false
+				}
+			}
 			t = shaderir.Type{Main: shaderir.Bool}
 		case lhs[0].Type == shaderir.NumberExpr && rhs[0].Type != shaderir.NumberExpr:
 			switch rhst.Main {
 			case shaderir.Mat2, shaderir.Mat3, shaderir.Mat4:
 				if op != shaderir.Mul {
-					cs.addError(e.Pos(), fmt.Sprintf("types don't match: %s %s %s", lhst.String(), e.Op, rhst.String()))
+					cs.addError(e.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("types don't match: %s %s %s", lhst.String(), e.Op, rhst.String()))
 					return nil, nil, nil, false
 				}
 			}
-			if rhst.Main == shaderir.Int {
+			if isIntType(&rhst) {
 				if !canTruncateToInteger(lhs[0].Const) {
-					cs.addError(e.Pos(), fmt.Sprintf("constant %s truncated to integer", lhs[0].Const.String()))
+					cs.addError(e.Pos(), ErrorCodeInvalidConstant, fmt.Sprintf("constant %s truncated to integer", lhs[0].Const.String()))
 					return nil, nil, nil, false
 				}
 				lhs[0].ConstType = shaderir.ConstTypeInt
@@ -169,13 +209,13 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 			switch lhst.Main {
 			case shaderir.Mat2, shaderir.Mat3, shaderir.Mat4:
 				if op != shaderir.Mul && op != shaderir.Div {
-					cs.addError(e.Pos(), fmt.Sprintf("types don't match: %s %s %s", lhst.String(), e.Op, rhst.String()))
+					cs.addError(e.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("types don't match: %s %s %s", lhst.String(), e.Op, rhst.String()))
 					return nil, nil, nil, false
 				}
 			}
-			if lhst.Main == shaderir.Int {
+			if isIntType(&lhst) {
 				if !canTruncateToInteger(rhs[0].Const) {
-					cs.addError(e.Pos(), fmt.Sprintf("constant %s truncated to integer", rhs[0].Const.String()))
+					cs.addError(e.Pos(), ErrorCodeInvalidConstant, fmt.Sprintf("constant %s truncated to integer", rhs[0].Const.String()))
 					return nil, nil, nil, false
 				}
 				rhs[0].ConstType = shaderir.ConstTypeInt
@@ -183,22 +223,26 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 			t = lhst
 		case lhst.Equal(&rhst):
 			if op == shaderir.Div && (rhst.Main == shaderir.Mat2 || rhst.Main == shaderir.Mat3 || rhst.Main == shaderir.Mat4) {
-				cs.addError(e.Pos(), fmt.Sprintf("invalid operation: operator %s not defined on %s", e.Op, rhst.String()))
+				cs.addError(e.Pos(), ErrorCodeInvalidOperation, fmt.Sprintf("invalid operation: operator %s not defined on %s", e.Op, rhst.String()))
 				return nil, nil, nil, false
 			}
 			t = lhst
+		case lhst.Main == shaderir.Int && isIntVectorType(&rhst):
+			t = rhst
+		case isIntVectorType(&lhst) && rhst.Main == shaderir.Int:
+			t = lhst
 		case lhst.Main == shaderir.Float:
 			switch rhst.Main {
 			case shaderir.Float, shaderir.Vec2, shaderir.Vec3, shaderir.Vec4:
 				t = rhst
 			case shaderir.Mat2, shaderir.Mat3, shaderir.Mat4:
 				if op != shaderir.Mul {
-					cs.addError(e.Pos(), fmt.Sprintf("types don't match: %s %s %s", lhst.String(), e.Op, rhst.String()))
+					cs.addError(e.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("types don't match: %s %s %s", lhst.String(), e.Op, rhst.String()))
 					return nil, nil, nil, false
 				}
 				t = lhst
 			default:
-				cs.addError(e.Pos(), fmt.Sprintf("types don't match: %s %s %s", lhst.String(), e.Op, rhst.String()))
+				cs.addError(e.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("types don't match: %s %s %s", lhst.String(), e.Op, rhst.String()))
 				return nil, nil, nil, false
 			}
 		case rhst.Main == shaderir.Float:
@@ -207,12 +251,12 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 				t = lhst
 			case shaderir.Mat2, shaderir.Mat3, shaderir.Mat4:
 				if op != shaderir.Mul && op != shaderir.Div {
-					cs.addError(e.Pos(), fmt.Sprintf("types don't match: %s %s %s", lhst.String(), e.Op, rhst.String()))
+					cs.addError(e.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("types don't match: %s %s %s", lhst.String(), e.Op, rhst.String()))
 					return nil, nil, nil, false
 				}
 				t = lhst
 			default:
-				cs.addError(e.Pos(), fmt.Sprintf("types don't match: %s %s %s", lhst.String(), e.Op, rhst.String()))
+				cs.addError(e.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("types don't match: %s %s %s", lhst.String(), e.Op, rhst.String()))
 				return nil, nil, nil, false
 			}
 		case op == shaderir.Mul && (lhst.Main == shaderir.Vec2 && rhst.Main == shaderir.Mat2 ||
@@ -225,24 +269,33 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 			lhst.Main == shaderir.Mat4 && rhst.Main == shaderir.Vec4):
 			t = shaderir.Type{Main: shaderir.Vec4}
 		default:
-			cs.addError(e.Pos(), fmt.Sprintf("invalid expression: %s %s %s", lhst.String(), e.Op, rhst.String()))
+			cs.addError(e.Pos(), ErrorCodeInvalidOperation, fmt.Sprintf("invalid expression: %s %s %s", lhst.String(), e.Op, rhst.String()))
 			return nil, nil, nil, false
 		}
 
-		// For `%`, both types must be deducible to integers.
-		if op == shaderir.ModOp {
-			// TODO: What about ivec?
-			if lhst.Main != shaderir.Int && (lhs[0].ConstType == shaderir.ConstTypeNone || !canTruncateToInteger(lhs[0].Const)) ||
-				rhst.Main != shaderir.Int && (rhs[0].ConstType == shaderir.ConstTypeNone || !canTruncateToInteger(rhs[0].Const)) {
+		// For the integer operators, both types must be deducible to integers.
+		if isIntOp(op) {
+			if !isIntType(&lhst) && (lhs[0].ConstType == shaderir.ConstTypeNone || !canTruncateToInteger(lhs[0].Const)) ||
+				!isIntType(&rhst) && (rhs[0].ConstType == shaderir.ConstTypeNone || !canTruncateToInteger(rhs[0].Const)) {
 				var wrongType shaderir.Type
-				if lhst.Main != shaderir.Int {
+				if !isIntType(&lhst) {
 					wrongType = lhst
 				} else {
 					wrongType = rhst
 				}
-				cs.addError(e.Pos(), fmt.Sprintf("invalid operation: operator %% not defined on %s", wrongType.String()))
+				cs.addError(e.Pos(), ErrorCodeInvalidOperation, fmt.Sprintf("invalid operation: operator %s not defined on %s", e.Op, wrongType.String()))
 				return nil, nil, nil, false
 			}
+
+			// Make both operands the same type, as some backends emulate the operators with functions.
+			if isIntVectorType(&t) {
+				if !isIntVectorType(&lhst) {
+					lhs[0] = intVectorConstructor(lhs[0], t.Main)
+				}
+				if !isIntVectorType(&rhst) {
+					rhs[0] = intVectorConstructor(rhs[0], t.Main)
+				}
+			}
 		}
 
 		return []shaderir.Expr{
@@ -268,7 +321,7 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 				return nil, nil, nil, false
 			}
 			if len(es) > 1 && len(e.Args) > 1 {
-				cs.addError(e.Pos(), fmt.Sprintf("single-value context and multiple-value context cannot be mixed: %s", e.Fun))
+				cs.addError(e.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("single-value context and multiple-value context cannot be mixed: %s", e.Fun))
 				return nil, nil, nil, false
 			}
 			args = append(args, es...)
@@ -282,7 +335,7 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 			return nil, nil, nil, false
 		}
 		if len(es) != 1 {
-			cs.addError(e.Pos(), fmt.Sprintf("multiple-value context is not available at a callee: %s", e.Fun))
+			cs.addError(e.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("multiple-value context is not available at a callee: %s", e.Fun))
 			return nil, nil, nil, false
 		}
 		callee = es[0]
@@ -293,11 +346,11 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 		if callee.Type == shaderir.BuiltinFuncExpr {
 			if callee.BuiltinFunc == shaderir.Len || callee.BuiltinFunc == shaderir.Cap {
 				if len(args) != 1 {
-					cs.addError(e.Pos(), fmt.Sprintf("number of %s's arguments must be 1 but %d", callee.BuiltinFunc, len(args)))
+					cs.addError(e.Pos(), ErrorCodeWrongArgumentCount, fmt.Sprintf("number of %s's arguments must be 1 but %d", callee.BuiltinFunc, len(args)))
 					return nil, nil, nil, false
 				}
 				if argts[0].Main != shaderir.Array {
-					cs.addError(e.Pos(), fmt.Sprintf("%s takes an array but %s", callee.BuiltinFunc, argts[0].String()))
+					cs.addError(e.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("%s takes an array but %s", callee.BuiltinFunc, argts[0].String()))
 					return nil, nil, nil, false
 				}
 				return []shaderir.Expr{
@@ -329,6 +382,22 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 				t = shaderir.Type{Main: shaderir.Mat3}
 			case shaderir.Mat4F:
 				t = shaderir.Type{Main: shaderir.Mat4}
+			case shaderir.IVec2F, shaderir.IVec3F, shaderir.IVec4F:
+				switch callee.BuiltinFunc {
+				case shaderir.IVec2F:
+					t = shaderir.Type{Main: shaderir.IVec2}
+				case shaderir.IVec3F:
+					t = shaderir.Type{Main: shaderir.IVec3}
+				case shaderir.IVec4F:
+					t = shaderir.Type{Main: shaderir.IVec4}
+				}
+				for i := range args {
+					if args[i].Type == shaderir.NumberExpr {
+						if !cs.forceToInt(e, &args[i]) {
+							return nil, nil, nil, false
+						}
+					}
+				}
 			case shaderir.Step:
 				t = argts[1]
 			case shaderir.Smoothstep:
@@ -339,6 +408,77 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 				t = shaderir.Type{Main: shaderir.Vec3}
 			case shaderir.Texture2DF:
 				t = shaderir.Type{Main: shaderir.Vec4}
+			case shaderir.Smootherstep:
+				if len(args) != 3 {
+					cs.addError(e.Pos(), ErrorCodeWrongArgumentCount, fmt.Sprintf("number of %s's arguments must be 3 but %d", callee.BuiltinFunc, len(args)))
+					return nil, nil, nil, false
+				}
+				for i := range args {
+					if args[i].Type == shaderir.NumberExpr {
+						args[i].ConstType = shaderir.ConstTypeFloat
+						argts[i] = shaderir.Type{Main: shaderir.Float}
+					}
+				}
+				switch argts[2].Main {
+				case shaderir.Float, shaderir.Vec2, shaderir.Vec3, shaderir.Vec4:
+				default:
+					cs.addError(e.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("%s takes a float or a vector but %s", callee.BuiltinFunc, argts[2].String()))
+					return nil, nil, nil, false
+				}
+				for _, et := range argts[:2] {
+					if !et.Equal(&argts[2]) && et.Main != shaderir.Float {
+						cs.addError(e.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("%s's edges must be float or %s but %s", callee.BuiltinFunc, argts[2].String(), et.String()))
+						return nil, nil, nil, false
+					}
+				}
+				if !argts[0].Equal(&argts[1]) {
+					cs.addError(e.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("%s's edges must have the same type but %s and %s", callee.BuiltinFunc, argts[0].String(), argts[1].String()))
+					return nil, nil, nil, false
+				}
+				t = argts[2]
+			case shaderir.HSVToRGB, shaderir.RGBToHSV, shaderir.Premultiply, shaderir.Unpremultiply,
+				shaderir.Hash, shaderir.Noise, shaderir.Rotation2D, shaderir.Rotation3D:
+				var params [][]shaderir.Type
+				switch callee.BuiltinFunc {
+				case shaderir.HSVToRGB, shaderir.RGBToHSV:
+					params = [][]shaderir.Type{{{Main: shaderir.Vec3}}}
+					t = shaderir.Type{Main: shaderir.Vec3}
+				case shaderir.Premultiply, shaderir.Unpremultiply:
+					params = [][]shaderir.Type{{{Main: shaderir.Vec4}}}
+					t = shaderir.Type{Main: shaderir.Vec4}
+				case shaderir.Hash, shaderir.Noise:
+					params = [][]shaderir.Type{{{Main: shaderir.Float}, {Main: shaderir.Vec2}, {Main: shaderir.Vec3}}}
+					t = shaderir.Type{Main: shaderir.Float}
+				case shaderir.Rotation2D:
+					params = [][]shaderir.Type{{{Main: shaderir.Float}}}
+					t = shaderir.Type{Main: shaderir.Mat2}
+				case shaderir.Rotation3D:
+					params = [][]shaderir.Type{{{Main: shaderir.Vec3}}, {{Main: shaderir.Float}}}
+					t = shaderir.Type{Main: shaderir.Mat3}
+				}
+				if len(args) != len(params) {
+					cs.addError(e.Pos(), ErrorCodeWrongArgumentCount, fmt.Sprintf("number of %s's arguments must be %d but %d", callee.BuiltinFunc, len(params), len(args)))
+					return nil, nil, nil, false
+				}
+				for i := range args {
+					if args[i].Type == shaderir.NumberExpr {
+						args[i].ConstType = shaderir.ConstTypeFloat
+						argts[i] = shaderir.Type{Main: shaderir.Float}
+					}
+					var ok bool
+					var tss []string
+					for _, p := range params[i] {
+						if argts[i].Equal(&p) {
+							ok = true
+							break
+						}
+						tss = append(tss, p.String())
+					}
+					if !ok {
+						cs.addError(e.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("%s's argument #%d must be %s but %s", callee.BuiltinFunc, i+1, strings.Join(tss, " or "), argts[i].String()))
+						return nil, nil, nil, false
+					}
+				}
 			default:
 				// If the argument is a non-typed constant value, treat is as a float value (#1874).
 				if args[0].Type == shaderir.NumberExpr && args[0].ConstType == shaderir.ConstTypeNone {
@@ -356,18 +496,18 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 		}
 
 		if callee.Type != shaderir.FunctionExpr {
-			cs.addError(e.Pos(), fmt.Sprintf("function callee must be a funciton name but %s", e.Fun))
+			cs.addError(e.Pos(), ErrorCodeInvalid, fmt.Sprintf("function callee must be a funciton name but %s", e.Fun))
 			return nil, nil, nil, false
 		}
 
 		f := cs.funcs[callee.Index]
 
 		if len(f.ir.InParams) < len(args) {
-			cs.addError(e.Pos(), fmt.Sprintf("too many arguments in call to %s", e.Fun))
+			cs.addError(e.Pos(), ErrorCodeWrongArgumentCount, fmt.Sprintf("too many arguments in call to %s", e.Fun))
 			return nil, nil, nil, false
 		}
 		if len(f.ir.InParams) > len(args) {
-			cs.addError(e.Pos(), fmt.Sprintf("not enough arguments in call to %s", e.Fun))
+			cs.addError(e.Pos(), ErrorCodeWrongArgumentCount, fmt.Sprintf("not enough arguments in call to %s", e.Fun))
 			return nil, nil, nil, false
 		}
 
@@ -394,7 +534,7 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 
 		if t := f.ir.Return; t.Main != shaderir.None {
 			if len(outParams) != 0 {
-				cs.addError(e.Pos(), fmt.Sprintf("a function returning value cannot have out-params so far: %s", e.Fun))
+				cs.addError(e.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("a function returning value cannot have out-params so far: %s", e.Fun))
 				return nil, nil, nil, false
 			}
 
@@ -459,7 +599,7 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 			// In the context where a local variable is marked as used, any expressions must have its
 			// meaning. Then, a blank identifier is not available there.
 			if markLocalVariableUsed {
-				cs.addError(e.Pos(), fmt.Sprintf("cannot use _ as value"))
+				cs.addError(e.Pos(), ErrorCodeInvalidOperation, fmt.Sprintf("cannot use _ as value"))
 				return nil, nil, nil, false
 			}
 			return []shaderir.Expr{
@@ -526,33 +666,49 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 				},
 			}, []shaderir.Type{{Main: shaderir.Bool}}, nil, true
 		}
-		cs.addError(e.Pos(), fmt.Sprintf("unexpected identifier: %s", e.Name))
+		cs.addError(e.Pos(), ErrorCodeUndefined, fmt.Sprintf("unexpected identifier: %s", e.Name))
 
 	case *ast.ParenExpr:
 		return cs.parseExpr(block, e.X, markLocalVariableUsed)
 
 	case *ast.SelectorExpr:
-		exprs, _, stmts, ok := cs.parseExpr(block, e.X, true)
+		exprs, ts, stmts, ok := cs.parseExpr(block, e.X, true)
 		if !ok {
 			return nil, nil, nil, false
 		}
 		if len(exprs) != 1 {
-			cs.addError(e.Pos(), fmt.Sprintf("multiple-value context is not available at a selector: %s", e.X))
+			cs.addError(e.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("multiple-value context is not available at a selector: %s", e.X))
 			return nil, nil, nil, false
 		}
 		var t shaderir.Type
-		switch len(e.Sel.Name) {
-		case 1:
-			t.Main = shaderir.Float
-		case 2:
-			t.Main = shaderir.Vec2
-		case 3:
-			t.Main = shaderir.Vec3
-		case 4:
-			t.Main = shaderir.Vec4
-		default:
-			cs.addError(e.Pos(), fmt.Sprintf("unexpected swizzling: %s", e.Sel.Name))
-			return nil, nil, nil, false
+		if len(ts) == 1 && isIntVectorType(&ts[0]) {
+			switch len(e.Sel.Name) {
+			case 1:
+				t.Main = shaderir.Int
+			case 2:
+				t.Main = shaderir.IVec2
+			case 3:
+				t.Main = shaderir.IVec3
+			case 4:
+				t.Main = shaderir.IVec4
+			default:
+				cs.addError(e.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("unexpected swizzling: %s", e.Sel.Name))
+				return nil, nil, nil, false
+			}
+		} else {
+			switch len(e.Sel.Name) {
+			case 1:
+				t.Main = shaderir.Float
+			case 2:
+				t.Main = shaderir.Vec2
+			case 3:
+				t.Main = shaderir.Vec3
+			case 4:
+				t.Main = shaderir.Vec4
+			default:
+				cs.addError(e.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("unexpected swizzling: %s", e.Sel.Name))
+				return nil, nil, nil, false
+			}
 		}
 		return []shaderir.Expr{
 			{
@@ -573,11 +729,15 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 			return nil, nil, nil, false
 		}
 		if len(exprs) != 1 {
-			cs.addError(e.Pos(), fmt.Sprintf("multiple-value context is not available at a unary operator: %s", e.X))
+			cs.addError(e.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("multiple-value context is not available at a unary operator: %s", e.X))
 			return nil, nil, nil, false
 		}
 
 		if exprs[0].Type == shaderir.NumberExpr {
+			if e.Op == token.XOR && exprs[0].Const.Kind() != gconstant.Int {
+				cs.addError(e.Pos(), ErrorCodeInvalidOperation, fmt.Sprintf("invalid operation: operator ^ not defined on untyped %s", goConstantKindString(exprs[0].Const.Kind())))
+				return nil, nil, nil, false
+			}
 			v := gconstant.UnaryOp(e.Op, exprs[0].Const, 0)
 			t := shaderir.Type{Main: shaderir.Int}
 			if v.Kind() == gconstant.Float {
@@ -599,8 +759,14 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 			op = shaderir.Sub
 		case token.NOT:
 			op = shaderir.NotOp
+		case token.XOR:
+			if !isIntType(&t[0]) {
+				cs.addError(e.Pos(), ErrorCodeInvalidOperation, fmt.Sprintf("invalid operation: operator ^ not defined on %s", t[0].String()))
+				return nil, nil, nil, false
+			}
+			op = shaderir.ComplementOp
 		default:
-			cs.addError(e.Pos(), fmt.Sprintf("unexpected operator: %s", e.Op))
+			cs.addError(e.Pos(), ErrorCodeInvalidOperation, fmt.Sprintf("unexpected operator: %s", e.Op))
 			return nil, nil, nil, false
 		}
 		return []shaderir.Expr{
@@ -632,7 +798,7 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 				return nil, nil, nil, false
 			}
 			if len(exprs) != 1 {
-				cs.addError(e.Pos(), fmt.Sprintf("multiple-value context is not available at a composite literal"))
+				cs.addError(e.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("multiple-value context is not available at a composite literal"))
 				return nil, nil, nil, false
 			}
 			stmts = append(stmts, ss...)
@@ -676,13 +842,13 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 		stmts = append(stmts, ss...)
 
 		if len(exprs) != 1 {
-			cs.addError(e.Pos(), fmt.Sprintf("multiple-value context is not available at an index expression"))
+			cs.addError(e.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("multiple-value context is not available at an index expression"))
 			return nil, nil, nil, false
 		}
 		idx := exprs[0]
 		if idx.Type == shaderir.NumberExpr {
 			if !canTruncateToInteger(idx.Const) {
-				cs.addError(e.Pos(), fmt.Sprintf("constant %s truncated to integer", idx.Const.String()))
+				cs.addError(e.Pos(), ErrorCodeInvalidConstant, fmt.Sprintf("constant %s truncated to integer", idx.Const.String()))
 				return nil, nil, nil, false
 			}
 			idx.ConstType = shaderir.ConstTypeInt
@@ -694,7 +860,7 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 		}
 		stmts = append(stmts, ss...)
 		if len(exprs) != 1 {
-			cs.addError(e.Pos(), fmt.Sprintf("multiple-value context is not available at an index expression"))
+			cs.addError(e.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("multiple-value context is not available at an index expression"))
 			return nil, nil, nil, false
 		}
 		x := exprs[0]
@@ -704,6 +870,8 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 		switch t.Main {
 		case shaderir.Vec2, shaderir.Vec3, shaderir.Vec4:
 			typ = shaderir.Type{Main: shaderir.Float}
+		case shaderir.IVec2, shaderir.IVec3, shaderir.IVec4:
+			typ = shaderir.Type{Main: shaderir.Int}
 		case shaderir.Mat2:
 			typ = shaderir.Type{Main: shaderir.Vec2}
 		case shaderir.Mat3:
@@ -713,7 +881,7 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 		case shaderir.Array:
 			typ = t.Sub[0]
 		default:
-			cs.addError(e.Pos(), fmt.Sprintf("index operator cannot be applied to the type %s", t.String()))
+			cs.addError(e.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("index operator cannot be applied to the type %s", t.String()))
 			return nil, nil, nil, false
 		}
 
@@ -728,7 +896,7 @@ func (cs *compileState) parseExpr(block *block, expr ast.Expr, markLocalVariable
 		}, []shaderir.Type{typ}, stmts, true
 
 	default:
-		cs.addError(e.Pos(), fmt.Sprintf("expression not implemented: %#v", e))
+		cs.addError(e.Pos(), ErrorCodeUnsupported, fmt.Sprintf("expression not implemented: %#v", e))
 	}
 	return nil, nil, nil, false
 }
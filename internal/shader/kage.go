@@ -0,0 +1,298 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shader
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
+)
+
+var shaderSuffix string
+
+func init() {
+	shaderSuffix = `
+var __imageDstTextureSize vec2
+
+// imageSrcTextureSize returns the destination image's texture size in pixels.
+func imageDstTextureSize() vec2 {
+	return __imageDstTextureSize
+}
+`
+
+	shaderSuffix += fmt.Sprintf(`
+var __textureSizes [%[1]d]vec2
+
+// imageSrcTextureSize returns the source image's texture size in pixels.
+// As an image is a part of internal texture, the texture is usually bigger than the image.
+// The texture's size is useful when you want to calculate pixels from texels.
+func imageSrcTextureSize() vec2 {
+	return __textureSizes[0]
+}
+
+// The unit is the source texture's texel.
+var __textureDestinationRegionOrigin vec2
+
+// The unit is the source texture's texel.
+var __textureDestinationRegionSize vec2
+
+// imageDstRegionOnTexture returns the destination image's region (the origin and the size) on its texture.
+// The unit is the source texture's texel.
+//
+// As an image is a part of internal texture, the image can be located at an arbitrary position on the texture.
+func imageDstRegionOnTexture() (vec2, vec2) {
+	return __textureDestinationRegionOrigin, __textureDestinationRegionSize
+}
+
+// The unit is the source texture's texel.
+var __textureSourceOffsets [%[2]d]vec2
+
+// The unit is the source texture's texel.
+var __textureSourceRegionOrigin vec2
+
+// The unit is the source texture's texel.
+var __textureSourceRegionSize vec2
+
+// imageSrcRegionOnTexture returns the source image's region (the origin and the size) on its texture.
+// The unit is the source texture's texel.
+//
+// As an image is a part of internal texture, the image can be located at an arbitrary position on the texture.
+func imageSrcRegionOnTexture() (vec2, vec2) {
+	return __textureSourceRegionOrigin, __textureSourceRegionSize
+}
+`, graphics.ShaderImageNum, graphics.ShaderImageNum-1)
+
+	for i := 0; i < graphics.ShaderImageNum; i++ {
+		pos := "pos"
+		if i >= 1 {
+			// Convert the position in texture0's texels to the target texture texels.
+			pos = fmt.Sprintf("(pos + __textureSourceOffsets[%d]) * __textureSizes[0] / __textureSizes[%d]", i-1, i)
+		}
+		// __t%d is a special variable for a texture variable.
+		shaderSuffix += fmt.Sprintf(`
+func imageSrc%[1]dUnsafeAt(pos vec2) vec4 {
+	// pos is the position in texels of the source texture (= 0th image's texture).
+	return texture2D(__t%[1]d, %[2]s)
+}
+
+func imageSrc%[1]dAt(pos vec2) vec4 {
+	// pos is the position in texels of the source texture (= 0th image's texture).
+	return texture2D(__t%[1]d, %[2]s) *
+		step(__textureSourceRegionOrigin.x, pos.x) *
+		(1 - step(__textureSourceRegionOrigin.x + __textureSourceRegionSize.x, pos.x)) *
+		step(__textureSourceRegionOrigin.y, pos.y) *
+		(1 - step(__textureSourceRegionOrigin.y + __textureSourceRegionSize.y, pos.y))
+}
+
+func __imageSrc%[1]dAtLevel(pos vec2, level float) vec4 {
+	// A pixel of the level covers size x size pixels of the image, aligned to the source region.
+	// Sample 4x4 pixels in the level's pixel and average them. This is exact when level <= 2.
+	// The position is clamped to the source region.
+	// The calculation is in pixels of the source texture (= 0th image's texture).
+	texSize := __textureSizes[0]
+	size := exp2(level)
+	regionMin := __textureSourceRegionOrigin * texSize
+	regionMax := (__textureSourceRegionOrigin + __textureSourceRegionSize) * texSize
+	origin := floor((pos*texSize - regionMin) / size) * size + regionMin
+	sum := vec4(0)
+	for j := 0; j < 4; j++ {
+		for i := 0; i < 4; i++ {
+			p := origin + floor(vec2(float(i), float(j)) * size / 4) + 0.5
+			sum += imageSrc%[1]dUnsafeAt(clamp(p, regionMin + 0.5, regionMax - 0.5) / texSize)
+		}
+	}
+	return sum / 16
+}
+
+// imageSrc%[1]dAtLod returns the color of the image %[1]d at pos as if the image had mipmaps.
+// lod is the level of detail: 0 is the original image, and the level n is the image scaled down by 2^n.
+// A fractional lod blends the two nearest levels.
+//
+// lod is clamped to [0, 2]: the levels are not precomputed but calculated by averaging 4x4 pixels of the image,
+// which is exact only up to the level 2. Even so, this is much more expensive than imageSrc%[1]dAt.
+//
+// Unlike imageSrc%[1]dAt, imageSrc%[1]dAtLod doesn't return a transparent color outside the source region,
+// but the color at the nearest edge of the region.
+func imageSrc%[1]dAtLod(pos vec2, lod float) vec4 {
+	// pos is the position in texels of the source texture (= 0th image's texture).
+	l := clamp(lod, 0, 2)
+	level := min(floor(l), 1)
+	return mix(__imageSrc%[1]dAtLevel(pos, level), __imageSrc%[1]dAtLevel(pos, level + 1), l - level)
+}
+`, i, pos)
+	}
+
+	shaderSuffix += `
+// imageDstClipPosition converts the given position in pixels on the destination texture
+// into the position in the clip space.
+// This is useful to return a position from the vertex entry point Vertex.
+func imageDstClipPosition(position vec2) vec4 {
+	return mat4(
+		2/__imageDstTextureSize.x, 0, 0, 0,
+		0, 2/__imageDstTextureSize.y, 0, 0,
+		0, 0, 1, 0,
+		-1, -1, 0, 1,
+	) * vec4(position, 0, 1)
+}
+
+func __vertex(position vec2, texCoord vec2, color vec4) (vec4, vec2, vec4) {
+	return imageDstClipPosition(position), texCoord, color
+}
+`
+}
+
+const (
+	defaultVertexEntry = "__vertex"
+	userVertexEntry    = "Vertex"
+	fragmentEntry      = "Fragment"
+)
+
+// CompileSources compiles Kage sources with Ebiten's built-in functions and variables, and returns the result.
+//
+// When multiple sources are given, their declarations are merged as if they were in one source.
+// filenames are the names of the sources used in the errors. filenames can be nil.
+//
+// If the sources are invalid, CompileSources returns a *ParseError.
+func CompileSources(srcs [][]byte, filenames []string) (*shaderir.Program, error) {
+	if filenames != nil && len(filenames) != len(srcs) {
+		panic(fmt.Sprintf("shader: len(filenames) (%d) must equal to len(srcs) (%d)", len(filenames), len(srcs)))
+	}
+
+	sources := map[string][]byte{}
+	p, err := compileSources(srcs, filenames, sources)
+	if err != nil {
+		if perr, ok := err.(*ParseError); ok {
+			for i := range perr.Errors {
+				e := &perr.Errors[i]
+				if e.Pos.IsValid() {
+					e.Snippet = sourceLine(sources[e.Pos.Filename], e.Pos.Line)
+				}
+			}
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+func compileSources(srcs [][]byte, filenames []string, sources map[string][]byte) (*shaderir.Program, error) {
+	fs := token.NewFileSet()
+	var f *ast.File
+	for i, src := range srcs {
+		var name string
+		if filenames != nil {
+			name = filenames[i]
+		}
+		sources[name] = src
+		file, err := parser.ParseFile(fs, name, src, parser.AllErrors)
+		if err != nil {
+			if list, ok := err.(scanner.ErrorList); ok {
+				perr := &ParseError{}
+				for _, e := range list {
+					perr.Errors = append(perr.Errors, Error{
+						Pos:  e.Pos,
+						Code: ErrorCodeSyntax,
+						Msg:  e.Msg,
+					})
+				}
+				return nil, perr
+			}
+			return nil, err
+		}
+
+		if f == nil {
+			f = file
+			continue
+		}
+		if file.Name.Name != f.Name.Name {
+			return nil, newParseError(fs.Position(file.Package), ErrorCodeInvalid, fmt.Sprintf("package %s; expected %s", file.Name.Name, f.Name.Name))
+		}
+		f.Decls = append(f.Decls, file.Decls...)
+	}
+	if f == nil {
+		return nil, newParseError(token.Position{}, ErrorCodeInvalid, "no sources are given")
+	}
+
+	// Parse shaderSuffix as a separate file so that syntax errors in the sources don't spread to it.
+	suffix, err := parser.ParseFile(fs, "<builtin>", "package "+f.Name.Name+"\n"+shaderSuffix, parser.AllErrors)
+	if err != nil {
+		panic(fmt.Sprintf("shader: parsing the shader suffix failed: %v", err))
+	}
+	f.Decls = append(f.Decls, suffix.Decls...)
+
+	// Use the user's vertex entry point if exists. Otherwise, the default one in shaderSuffix is used.
+	vert := defaultVertexEntry
+	var vertDecl *ast.FuncDecl
+	for _, d := range f.Decls {
+		if fd, ok := d.(*ast.FuncDecl); ok && fd.Recv == nil && fd.Name.Name == userVertexEntry {
+			vert = userVertexEntry
+			vertDecl = fd
+			break
+		}
+	}
+
+	s, err := Compile(fs, f, vert, fragmentEntry, graphics.ShaderImageNum)
+	if err != nil {
+		return nil, err
+	}
+
+	// The vertex attributes are fixed: a position, a texture coordinate and a color.
+	if vertDecl != nil {
+		if len(s.Attributes) != 3 || s.Attributes[0].Main != shaderir.Vec2 || s.Attributes[1].Main != shaderir.Vec2 || s.Attributes[2].Main != shaderir.Vec4 {
+			return nil, newParseError(fs.Position(vertDecl.Pos()), ErrorCodeEntryPoint, fmt.Sprintf("vertex shader entry point '%s' must have the parameters (vec2, vec2, vec4)", userVertexEntry))
+		}
+	}
+
+	if s.VertexFunc.Block == nil {
+		return nil, newParseError(token.Position{}, ErrorCodeEntryPoint, fmt.Sprintf("vertex shader entry point '%s' is missing", vert))
+	}
+	if s.FragmentFunc.Block == nil {
+		return nil, newParseError(token.Position{}, ErrorCodeEntryPoint, fmt.Sprintf("fragment shader entry point '%s' is missing", fragmentEntry))
+	}
+	return s, nil
+}
+
+func newParseError(pos token.Position, code ErrorCode, msg string) *ParseError {
+	return &ParseError{
+		Errors: []Error{
+			{
+				Pos:  pos,
+				Code: code,
+				Msg:  msg,
+			},
+		},
+	}
+}
+
+// sourceLine returns the line-th line (1-based) of src without the trailing newline.
+func sourceLine(src []byte, line int) string {
+	for i := 1; i < line; i++ {
+		idx := bytes.IndexByte(src, '\n')
+		if idx < 0 {
+			return ""
+		}
+		src = src[idx+1:]
+	}
+	if idx := bytes.IndexByte(src, '\n'); idx >= 0 {
+		src = src[:idx]
+	}
+	return strings.TrimSuffix(string(src), "\r")
+}
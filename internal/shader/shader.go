@@ -21,6 +21,7 @@ import (
 	"go/token"
 	"strings"
 
+	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
 	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
 )
 
@@ -44,6 +45,14 @@ type function struct {
 	ir shaderir.Func
 }
 
+// breakTarget represents a statement that a break statement exits.
+type breakTarget int
+
+const (
+	breakTargetFor breakTarget = iota
+	breakTargetSwitch
+)
+
 type compileState struct {
 	fs *token.FileSet
 
@@ -58,7 +67,11 @@ type compileState struct {
 
 	varyingParsed bool
 
-	errs []string
+	// breakTargets is the stack of the statements enclosing the statement being parsed, which a break statement
+	// exits.
+	breakTargets []breakTarget
+
+	errs []Error
 }
 
 func (cs *compileState) findFunction(name string) (int, bool) {
@@ -173,12 +186,53 @@ func (b *block) findConstant(name string) (constant, bool) {
 	return constant{}, false
 }
 
+// ErrorCode is a machine-readable code to categorize an Error.
+type ErrorCode string
+
+const (
+	ErrorCodeSyntax              ErrorCode = "syntax"
+	ErrorCodeRedeclared          ErrorCode = "redeclared"
+	ErrorCodeUndefined           ErrorCode = "undefined"
+	ErrorCodeUnused              ErrorCode = "unused"
+	ErrorCodeMismatchedTypes     ErrorCode = "mismatched-types"
+	ErrorCodeInvalidOperation    ErrorCode = "invalid-operation"
+	ErrorCodeInvalidConstant     ErrorCode = "invalid-constant"
+	ErrorCodeWrongArgumentCount  ErrorCode = "wrong-argument-count"
+	ErrorCodeWrongValueCount     ErrorCode = "wrong-value-count"
+	ErrorCodeInvalidControlFlow  ErrorCode = "invalid-control-flow"
+	ErrorCodeInvalidForStatement ErrorCode = "invalid-for-statement"
+	ErrorCodeEntryPoint          ErrorCode = "entry-point"
+	ErrorCodeUnsupported         ErrorCode = "unsupported"
+	ErrorCodeInvalid             ErrorCode = "invalid"
+)
+
+// Error is an error at a position in a source.
+type Error struct {
+	Pos  token.Position
+	Code ErrorCode
+	Msg  string
+
+	// Snippet is the line of the source at Pos. Snippet is set only by CompileSources.
+	Snippet string
+}
+
+func (e *Error) Error() string {
+	if !e.Pos.IsValid() {
+		return e.Msg
+	}
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
+
 type ParseError struct {
-	errs []string
+	Errors []Error
 }
 
 func (p *ParseError) Error() string {
-	return strings.Join(p.errs, "\n")
+	strs := make([]string, 0, len(p.Errors))
+	for i := range p.Errors {
+		strs = append(strs, p.Errors[i].Error())
+	}
+	return strings.Join(strs, "\n")
 }
 
 func Compile(fs *token.FileSet, f *ast.File, vertexEntry, fragmentEntry string, textureNum int) (*shaderir.Program, error) {
@@ -203,9 +257,12 @@ func Compile(fs *token.FileSet, f *ast.File, vertexEntry, fragmentEntry string,
 	return &s.ir, nil
 }
 
-func (s *compileState) addError(pos token.Pos, str string) {
-	p := s.fs.Position(pos)
-	s.errs = append(s.errs, fmt.Sprintf("%s: %s", p, str))
+func (s *compileState) addError(pos token.Pos, code ErrorCode, str string) {
+	s.errs = append(s.errs, Error{
+		Pos:  s.fs.Position(pos),
+		Code: code,
+		Msg:  str,
+	})
 }
 
 func (cs *compileState) parse(f *ast.File) {
@@ -241,6 +298,7 @@ func (cs *compileState) parse(f *ast.File) {
 
 	// Parse function names so that any other function call the others.
 	// The function data is provisional and will be updated soon.
+	var vertexEntryFound, fragmentEntryFound bool
 	for _, d := range f.Decls {
 		fd, ok := d.(*ast.FuncDecl)
 		if !ok {
@@ -248,15 +306,25 @@ func (cs *compileState) parse(f *ast.File) {
 		}
 		n := fd.Name.Name
 		if n == cs.vertexEntry {
+			if vertexEntryFound {
+				cs.addError(d.Pos(), ErrorCodeRedeclared, fmt.Sprintf("redeclared function: %s", n))
+				return
+			}
+			vertexEntryFound = true
 			continue
 		}
 		if n == cs.fragmentEntry {
+			if fragmentEntryFound {
+				cs.addError(d.Pos(), ErrorCodeRedeclared, fmt.Sprintf("redeclared function: %s", n))
+				return
+			}
+			fragmentEntryFound = true
 			continue
 		}
 
 		for _, f := range cs.funcs {
 			if f.name == n {
-				cs.addError(d.Pos(), fmt.Sprintf("redeclared function: %s", n))
+				cs.addError(d.Pos(), ErrorCodeRedeclared, fmt.Sprintf("redeclared function: %s", n))
 				return
 			}
 		}
@@ -342,7 +410,7 @@ func (cs *compileState) parseDecl(b *block, d ast.Decl) ([]shaderir.Stmt, bool)
 					for i, v := range vs {
 						if !strings.HasPrefix(v.name, "__") {
 							if v.name[0] < 'A' || 'Z' < v.name[0] {
-								cs.addError(s.Names[i].Pos(), fmt.Sprintf("global variables must be exposed: %s", v.name))
+								cs.addError(s.Names[i].Pos(), ErrorCodeInvalid, fmt.Sprintf("global variables must be exposed: %s", v.name))
 							}
 						}
 						cs.ir.UniformNames = append(cs.ir.UniformNames, v.name)
@@ -373,9 +441,9 @@ func (cs *compileState) parseDecl(b *block, d ast.Decl) ([]shaderir.Stmt, bool)
 				}
 			}
 		case token.IMPORT:
-			cs.addError(d.Pos(), "import is forbidden")
+			cs.addError(d.Pos(), ErrorCodeUnsupported, "import is forbidden")
 		default:
-			cs.addError(d.Pos(), "unexpected token")
+			cs.addError(d.Pos(), ErrorCodeInvalid, "unexpected token")
 		}
 	case *ast.FuncDecl:
 		f, ok := cs.parseFunc(b, d)
@@ -383,7 +451,7 @@ func (cs *compileState) parseDecl(b *block, d ast.Decl) ([]shaderir.Stmt, bool)
 			return nil, false
 		}
 		if b != &cs.global {
-			cs.addError(d.Pos(), "non-global function is not implemented")
+			cs.addError(d.Pos(), ErrorCodeUnsupported, "non-global function is not implemented")
 			return nil, false
 		}
 		switch d.Name.Name {
@@ -403,7 +471,7 @@ func (cs *compileState) parseDecl(b *block, d ast.Decl) ([]shaderir.Stmt, bool)
 			}
 		}
 	default:
-		cs.addError(d.Pos(), "unexpected decl")
+		cs.addError(d.Pos(), ErrorCodeInvalid, "unexpected decl")
 		return nil, false
 	}
 
@@ -439,7 +507,7 @@ func (cs *compileState) functionReturnTypes(block *block, expr ast.Expr) ([]shad
 
 func (s *compileState) parseVariable(block *block, vs *ast.ValueSpec) ([]variable, []shaderir.Expr, []shaderir.Stmt, bool) {
 	if len(vs.Names) != len(vs.Values) && len(vs.Values) != 1 && len(vs.Values) != 0 {
-		s.addError(vs.Pos(), fmt.Sprintf("the numbers of lhs and rhs don't match"))
+		s.addError(vs.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("the numbers of lhs and rhs don't match"))
 		return nil, nil, nil, false
 	}
 
@@ -484,7 +552,7 @@ func (s *compileState) parseVariable(block *block, vs *ast.ValueSpec) ([]variabl
 					ts = origts
 				}
 				if len(ts) > 1 {
-					s.addError(vs.Pos(), fmt.Sprintf("the numbers of lhs and rhs don't match"))
+					s.addError(vs.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("the numbers of lhs and rhs don't match"))
 				}
 				t = ts[0]
 			}
@@ -521,7 +589,7 @@ func (s *compileState) parseVariable(block *block, vs *ast.ValueSpec) ([]variabl
 						inittypes = ts
 					}
 					if len(ts) != len(vs.Names) {
-						s.addError(vs.Pos(), fmt.Sprintf("the numbers of lhs and rhs don't match"))
+						s.addError(vs.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("the numbers of lhs and rhs don't match"))
 						continue
 					}
 				}
@@ -537,13 +605,13 @@ func (s *compileState) parseVariable(block *block, vs *ast.ValueSpec) ([]variabl
 		name := n.Name
 		for _, v := range append(block.vars, vars...) {
 			if v.name == name {
-				s.addError(vs.Pos(), fmt.Sprintf("duplicated local variable name: %s", name))
+				s.addError(vs.Pos(), ErrorCodeRedeclared, fmt.Sprintf("duplicated local variable name: %s", name))
 				return nil, nil, nil, false
 			}
 		}
 		for _, c := range block.consts {
 			if c.name == name {
-				s.addError(vs.Pos(), fmt.Sprintf("duplicated local constant/variable name: %s", name))
+				s.addError(vs.Pos(), ErrorCodeRedeclared, fmt.Sprintf("duplicated local constant/variable name: %s", name))
 				return nil, nil, nil, false
 			}
 		}
@@ -571,13 +639,13 @@ func (s *compileState) parseConstant(block *block, vs *ast.ValueSpec) ([]constan
 		name := n.Name
 		for _, c := range block.consts {
 			if c.name == name {
-				s.addError(vs.Pos(), fmt.Sprintf("duplicated local constant name: %s", name))
+				s.addError(vs.Pos(), ErrorCodeRedeclared, fmt.Sprintf("duplicated local constant name: %s", name))
 				return nil, false
 			}
 		}
 		for _, v := range block.vars {
 			if v.name == name {
-				s.addError(vs.Pos(), fmt.Sprintf("duplicated local constant/variable name: %s", name))
+				s.addError(vs.Pos(), ErrorCodeRedeclared, fmt.Sprintf("duplicated local constant/variable name: %s", name))
 				return nil, false
 			}
 		}
@@ -587,15 +655,15 @@ func (s *compileState) parseConstant(block *block, vs *ast.ValueSpec) ([]constan
 			return nil, false
 		}
 		if len(ss) > 0 {
-			s.addError(vs.Pos(), fmt.Sprintf("invalid constant expression: %s", name))
+			s.addError(vs.Pos(), ErrorCodeInvalidConstant, fmt.Sprintf("invalid constant expression: %s", name))
 			return nil, false
 		}
 		if len(ts) != 1 || len(es) != 1 {
-			s.addError(vs.Pos(), fmt.Sprintf("invalid constant expression: %s", n))
+			s.addError(vs.Pos(), ErrorCodeInvalidConstant, fmt.Sprintf("invalid constant expression: %s", n))
 			return nil, false
 		}
 		if es[0].Type != shaderir.NumberExpr {
-			s.addError(vs.Pos(), fmt.Sprintf("constant expresion must be a number but not: %s", n))
+			s.addError(vs.Pos(), ErrorCodeInvalidConstant, fmt.Sprintf("constant expresion must be a number but not: %s", n))
 			return nil, false
 		}
 		cs = append(cs, constant{
@@ -650,15 +718,15 @@ func (cs *compileState) parseFuncParams(block *block, d *ast.FuncDecl) (in, out
 
 func (cs *compileState) parseFunc(block *block, d *ast.FuncDecl) (function, bool) {
 	if d.Name == nil {
-		cs.addError(d.Pos(), "function must have a name")
+		cs.addError(d.Pos(), ErrorCodeInvalid, "function must have a name")
 		return function{}, false
 	}
 	if d.Name.Name == "init" {
-		cs.addError(d.Pos(), "init function is not implemented")
+		cs.addError(d.Pos(), ErrorCodeUnsupported, "init function is not implemented")
 		return function{}, false
 	}
 	if d.Body == nil {
-		cs.addError(d.Pos(), "function must have a body")
+		cs.addError(d.Pos(), ErrorCodeInvalid, "function must have a body")
 		return function{}, false
 	}
 
@@ -666,12 +734,12 @@ func (cs *compileState) parseFunc(block *block, d *ast.FuncDecl) (function, bool
 
 	checkVaryings := func(vs []variable) {
 		if len(cs.ir.Varyings) != len(vs) {
-			cs.addError(d.Pos(), fmt.Sprintf("the number of vertex entry point's returning values and the number of framgent entry point's params must be the same"))
+			cs.addError(d.Pos(), ErrorCodeEntryPoint, fmt.Sprintf("the number of vertex entry point's returning values and the number of framgent entry point's params must be the same"))
 			return
 		}
 		for i, t := range cs.ir.Varyings {
 			if t.Main != vs[i].typ.Main {
-				cs.addError(d.Pos(), fmt.Sprintf("vertex entry point's returning value types and framgent entry point's param types must match"))
+				cs.addError(d.Pos(), ErrorCodeEntryPoint, fmt.Sprintf("vertex entry point's returning value types and framgent entry point's param types must match"))
 			}
 		}
 	}
@@ -685,11 +753,11 @@ func (cs *compileState) parseFunc(block *block, d *ast.FuncDecl) (function, bool
 
 			// The first out-param is treated as gl_Position in GLSL.
 			if len(outParams) == 0 {
-				cs.addError(d.Pos(), fmt.Sprintf("vertex entry point must have at least one returning vec4 value for a position"))
+				cs.addError(d.Pos(), ErrorCodeEntryPoint, fmt.Sprintf("vertex entry point must have at least one returning vec4 value for a position"))
 				return function{}, false
 			}
 			if outParams[0].typ.Main != shaderir.Vec4 {
-				cs.addError(d.Pos(), fmt.Sprintf("vertex entry point must have at least one returning vec4 value for a position"))
+				cs.addError(d.Pos(), ErrorCodeEntryPoint, fmt.Sprintf("vertex entry point must have at least one returning vec4 value for a position"))
 				return function{}, false
 			}
 
@@ -704,22 +772,25 @@ func (cs *compileState) parseFunc(block *block, d *ast.FuncDecl) (function, bool
 			cs.varyingParsed = true
 		case cs.fragmentEntry:
 			if len(inParams) == 0 {
-				cs.addError(d.Pos(), fmt.Sprintf("fragment entry point must have at least one vec4 parameter for a position"))
+				cs.addError(d.Pos(), ErrorCodeEntryPoint, fmt.Sprintf("fragment entry point must have at least one vec4 parameter for a position"))
 				return function{}, false
 			}
 			if inParams[0].typ.Main != shaderir.Vec4 {
-				cs.addError(d.Pos(), fmt.Sprintf("fragment entry point must have at least one vec4 parameter for a position"))
+				cs.addError(d.Pos(), ErrorCodeEntryPoint, fmt.Sprintf("fragment entry point must have at least one vec4 parameter for a position"))
 				return function{}, false
 			}
 
-			if len(outParams) != 1 {
-				cs.addError(d.Pos(), fmt.Sprintf("fragment entry point must have one returning vec4 value for a color"))
+			if len(outParams) == 0 || len(outParams) > graphics.ShaderDstImageNum {
+				cs.addError(d.Pos(), ErrorCodeEntryPoint, fmt.Sprintf("fragment entry point must have 1 to %d returning vec4 values for colors", graphics.ShaderDstImageNum))
 				return function{}, false
 			}
-			if outParams[0].typ.Main != shaderir.Vec4 {
-				cs.addError(d.Pos(), fmt.Sprintf("fragment entry point must have one returning vec4 value for a color"))
-				return function{}, false
+			for _, p := range outParams {
+				if p.typ.Main != shaderir.Vec4 {
+					cs.addError(d.Pos(), ErrorCodeEntryPoint, fmt.Sprintf("fragment entry point must have 1 to %d returning vec4 values for colors", graphics.ShaderDstImageNum))
+					return function{}, false
+				}
 			}
+			cs.ir.ColorsOutputCount = len(outParams)
 
 			if cs.varyingParsed {
 				checkVaryings(inParams[1:])
@@ -738,23 +809,27 @@ func (cs *compileState) parseFunc(block *block, d *ast.FuncDecl) (function, bool
 	}
 
 	if len(outParams) > 0 {
-		var hasReturn func(stmts []shaderir.Stmt) bool
-		hasReturn = func(stmts []shaderir.Stmt) bool {
-			for _, stmt := range stmts {
-				if stmt.Type == shaderir.Return {
-					return true
-				}
-				for _, b := range stmt.Blocks {
-					if hasReturn(b.Stmts) {
-						return true
-					}
-				}
+		// As Go does, a function with results must end in a terminating statement.
+		// https://golang.org/ref/spec#Terminating_statements
+		var isTerminating func(stmts []shaderir.Stmt) bool
+		isTerminating = func(stmts []shaderir.Stmt) bool {
+			if len(stmts) == 0 {
+				return false
+			}
+			last := stmts[len(stmts)-1]
+			switch last.Type {
+			case shaderir.Return, shaderir.Discard:
+				return true
+			case shaderir.BlockStmt:
+				return isTerminating(last.Blocks[0].Stmts)
+			case shaderir.If:
+				return len(last.Blocks) == 2 && isTerminating(last.Blocks[0].Stmts) && isTerminating(last.Blocks[1].Stmts)
 			}
 			return false
 		}
 
-		if !hasReturn(b.ir.Stmts) {
-			cs.addError(d.Pos(), fmt.Sprintf("function %s must have a return statement but not", d.Name))
+		if !isTerminating(b.ir.Stmts) {
+			cs.addError(d.Body.Rbrace, ErrorCodeInvalidControlFlow, fmt.Sprintf("missing return at the end of function %s", d.Name))
 			return function{}, false
 		}
 	}
@@ -835,7 +910,7 @@ func (cs *compileState) parseBlock(outer *block, fname string, stmts []ast.Stmt,
 
 	if checkLocalVariableUsage && len(block.unusedVars) > 0 {
 		for idx, pos := range block.unusedVars {
-			cs.addError(pos, fmt.Sprintf("local variable %s is not used", block.vars[idx].name))
+			cs.addError(pos, ErrorCodeUnused, fmt.Sprintf("local variable %s is not used", block.vars[idx].name))
 		}
 		return nil, false
 	}
@@ -26,7 +26,7 @@ import (
 
 func (cs *compileState) forceToInt(node ast.Node, expr *shaderir.Expr) bool {
 	if !canTruncateToInteger(expr.Const) {
-		cs.addError(node.Pos(), fmt.Sprintf("constant %s truncated to integer", expr.Const.String()))
+		cs.addError(node.Pos(), ErrorCodeInvalidConstant, fmt.Sprintf("constant %s truncated to integer", expr.Const.String()))
 		return false
 	}
 	expr.ConstType = shaderir.ConstTypeInt
@@ -41,7 +41,7 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 		switch stmt.Tok {
 		case token.DEFINE:
 			if len(stmt.Lhs) != len(stmt.Rhs) && len(stmt.Rhs) != 1 {
-				cs.addError(stmt.Pos(), fmt.Sprintf("single-value context and multiple-value context cannot be mixed"))
+				cs.addError(stmt.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("single-value context and multiple-value context cannot be mixed"))
 				return nil, false
 			}
 
@@ -52,7 +52,7 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 			stmts = append(stmts, ss...)
 		case token.ASSIGN:
 			if len(stmt.Lhs) != len(stmt.Rhs) && len(stmt.Rhs) != 1 {
-				cs.addError(stmt.Pos(), fmt.Sprintf("single-value context and multiple-value context cannot be mixed"))
+				cs.addError(stmt.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("single-value context and multiple-value context cannot be mixed"))
 				return nil, false
 			}
 			ss, ok := cs.assign(block, fname, stmt.Pos(), stmt.Lhs, stmt.Rhs, inParams, false)
@@ -60,7 +60,8 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 				return nil, false
 			}
 			stmts = append(stmts, ss...)
-		case token.ADD_ASSIGN, token.SUB_ASSIGN, token.MUL_ASSIGN, token.QUO_ASSIGN, token.REM_ASSIGN:
+		case token.ADD_ASSIGN, token.SUB_ASSIGN, token.MUL_ASSIGN, token.QUO_ASSIGN, token.REM_ASSIGN,
+			token.SHL_ASSIGN, token.SHR_ASSIGN, token.AND_ASSIGN, token.OR_ASSIGN, token.XOR_ASSIGN, token.AND_NOT_ASSIGN:
 			var op shaderir.Op
 			switch stmt.Tok {
 			case token.ADD_ASSIGN:
@@ -73,6 +74,16 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 				op = shaderir.Div
 			case token.REM_ASSIGN:
 				op = shaderir.ModOp
+			case token.SHL_ASSIGN:
+				op = shaderir.LeftShift
+			case token.SHR_ASSIGN:
+				op = shaderir.RightShift
+			case token.AND_ASSIGN, token.AND_NOT_ASSIGN:
+				op = shaderir.And
+			case token.OR_ASSIGN:
+				op = shaderir.Or
+			case token.XOR_ASSIGN:
+				op = shaderir.Xor
 			}
 
 			rhs, rts, ss, ok := cs.parseExpr(block, stmt.Rhs[0], true)
@@ -96,7 +107,7 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 
 			if lts[0].Main == rts[0].Main {
 				if op == shaderir.Div && (rts[0].Main == shaderir.Mat2 || rts[0].Main == shaderir.Mat3 || rts[0].Main == shaderir.Mat4) {
-					cs.addError(stmt.Pos(), fmt.Sprintf("invalid operation: operator / not defined on %s", rts[0].String()))
+					cs.addError(stmt.Pos(), ErrorCodeInvalidOperation, fmt.Sprintf("invalid operation: operator / not defined on %s", rts[0].String()))
 					return nil, false
 				}
 			} else {
@@ -105,12 +116,19 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 					if !cs.forceToInt(stmt, &rhs[0]) {
 						return nil, false
 					}
+				case shaderir.IVec2, shaderir.IVec3, shaderir.IVec4:
+					if rts[0].Main == shaderir.Int {
+						// OK
+					} else {
+						cs.addError(stmt.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("invalid operation: mismatched types %s and %s", lts[0].String(), rts[0].String()))
+						return nil, false
+					}
 				case shaderir.Float:
 					if rhs[0].Const != nil && rhs[0].Const.Kind() == gconstant.Int {
 						rhs[0].Const = gconstant.ToFloat(rhs[0].Const)
 						rhs[0].ConstType = shaderir.ConstTypeFloat
 					} else {
-						cs.addError(stmt.Pos(), fmt.Sprintf("invalid operation: mismatched types %s and %s", lts[0].String(), rts[0].String()))
+						cs.addError(stmt.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("invalid operation: mismatched types %s and %s", lts[0].String(), rts[0].String()))
 						return nil, false
 					}
 				case shaderir.Vec2, shaderir.Vec3, shaderir.Vec4, shaderir.Mat2, shaderir.Mat3, shaderir.Mat4:
@@ -129,18 +147,36 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 						rhs[0].Const = gconstant.ToFloat(rhs[0].Const)
 						rhs[0].ConstType = shaderir.ConstTypeFloat
 					} else {
-						cs.addError(stmt.Pos(), fmt.Sprintf("invalid operation: mismatched types %s and %s", lts[0].String(), rts[0].String()))
+						cs.addError(stmt.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("invalid operation: mismatched types %s and %s", lts[0].String(), rts[0].String()))
 						return nil, false
 					}
 				default:
-					cs.addError(stmt.Pos(), fmt.Sprintf("invalid operation: mismatched types %s and %s", lts[0].String(), rts[0].String()))
+					cs.addError(stmt.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("invalid operation: mismatched types %s and %s", lts[0].String(), rts[0].String()))
 					return nil, false
 				}
 			}
 
-			if op == shaderir.ModOp && lts[0].Main != shaderir.Int {
-				cs.addError(stmt.Pos(), fmt.Sprintf("invalid operation: operator %% not defined on %s", lts[0].String()))
-				return nil, false
+			if isIntOp(op) {
+				if !isIntType(&lts[0]) {
+					cs.addError(stmt.Pos(), ErrorCodeInvalidOperation, fmt.Sprintf("invalid operation: operator %s not defined on %s", stmt.Tok, lts[0].String()))
+					return nil, false
+				}
+				if isIntVectorType(&lts[0]) && !isIntVectorType(&rts[0]) {
+					rhs[0] = intVectorConstructor(rhs[0], lts[0].Main)
+				}
+			}
+
+			// x &^= y is x &= ^y.
+			if stmt.Tok == token.AND_NOT_ASSIGN {
+				if rhs[0].Type == shaderir.NumberExpr {
+					rhs[0].Const = gconstant.UnaryOp(token.XOR, gconstant.ToInt(rhs[0].Const), 0)
+				} else {
+					rhs[0] = shaderir.Expr{
+						Type:  shaderir.Unary,
+						Op:    shaderir.ComplementOp,
+						Exprs: []shaderir.Expr{rhs[0]},
+					}
+				}
 			}
 
 			stmts = append(stmts, shaderir.Stmt{
@@ -158,7 +194,7 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 				},
 			})
 		default:
-			cs.addError(stmt.Pos(), fmt.Sprintf("unexpected token: %s", stmt.Tok))
+			cs.addError(stmt.Pos(), ErrorCodeInvalid, fmt.Sprintf("unexpected token: %s", stmt.Tok))
 		}
 	case *ast.BlockStmt:
 		b, ok := cs.parseBlock(block, fname, stmt.List, inParams, outParams, true)
@@ -179,17 +215,17 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 		stmts = append(stmts, ss...)
 
 	case *ast.ForStmt:
-		msg := "for-statement must follow this format: for (varname) := (constant); (varname) (op) (constant); (varname) (op) (constant) { ..."
+		msg := "for-statement must follow this format: for (varname) := (expr); (varname) (op) (expr); (varname) (op) (constant) { ..."
 		if stmt.Init == nil {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 		if stmt.Cond == nil {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 		if stmt.Post == nil {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 
@@ -203,99 +239,95 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 		ss := pseudoBlock.ir.Stmts
 
 		if len(ss) != 1 {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 		if ss[0].Type != shaderir.Assign {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 		if ss[0].Exprs[0].Type != shaderir.LocalVariable {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 		varidx := ss[0].Exprs[0].Index
-		if ss[0].Exprs[1].Type != shaderir.NumberExpr {
-			cs.addError(stmt.Pos(), msg)
-			return nil, false
-		}
+		initExpr := ss[0].Exprs[1]
 
 		vartype := pseudoBlock.vars[0].typ
-		init := ss[0].Exprs[1].Const
+		if vartype.Main != shaderir.Int && vartype.Main != shaderir.Float {
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, "for-statement's counter must be int or float")
+			return nil, false
+		}
 
 		exprs, ts, ss, ok := cs.parseExpr(pseudoBlock, stmt.Cond, true)
 		if !ok {
 			return nil, false
 		}
 		if len(exprs) != 1 {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 		if len(ts) != 1 || ts[0].Main != shaderir.Bool {
-			cs.addError(stmt.Pos(), "for-statement's condition must be bool")
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, "for-statement's condition must be bool")
 			return nil, false
 		}
 		if len(ss) != 0 {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 		if exprs[0].Type != shaderir.Binary {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 		op := exprs[0].Op
 		if op != shaderir.LessThanOp && op != shaderir.LessThanEqualOp && op != shaderir.GreaterThanOp && op != shaderir.GreaterThanEqualOp && op != shaderir.EqualOp && op != shaderir.NotEqualOp {
-			cs.addError(stmt.Pos(), "for-statement's condition must have one of these operators: <, <=, >, >=, ==, !=")
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, "for-statement's condition must have one of these operators: <, <=, >, >=, ==, !=")
 			return nil, false
 		}
 		if exprs[0].Exprs[0].Type != shaderir.LocalVariable {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 		if exprs[0].Exprs[0].Index != varidx {
-			cs.addError(stmt.Pos(), msg)
-			return nil, false
-		}
-		if exprs[0].Exprs[1].Type != shaderir.NumberExpr {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
-		end := exprs[0].Exprs[1].Const
+		endExpr := exprs[0].Exprs[1]
 
 		postSs, ok := cs.parseStmt(pseudoBlock, fname, stmt.Post, inParams, outParams)
 		if !ok {
 			return nil, false
 		}
 		if len(postSs) != 1 {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 		if postSs[0].Type != shaderir.Assign {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 		if postSs[0].Exprs[0].Type != shaderir.LocalVariable {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 		if postSs[0].Exprs[0].Index != varidx {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 		if postSs[0].Exprs[1].Type != shaderir.Binary {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 		if postSs[0].Exprs[1].Exprs[0].Type != shaderir.LocalVariable {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 		if postSs[0].Exprs[1].Exprs[0].Index != varidx {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 		if postSs[0].Exprs[1].Exprs[1].Type != shaderir.NumberExpr {
-			cs.addError(stmt.Pos(), msg)
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, msg)
 			return nil, false
 		}
 		delta := postSs[0].Exprs[1].Exprs[1].Const
@@ -304,11 +336,13 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 		case shaderir.Sub:
 			delta = gconstant.UnaryOp(token.SUB, delta, 0)
 		default:
-			cs.addError(stmt.Pos(), "for-statement's post statement must have one of these operators: +=, -=, ++, --")
+			cs.addError(stmt.Pos(), ErrorCodeInvalidForStatement, "for-statement's post statement must have one of these operators: +=, -=, ++, --")
 			return nil, false
 		}
 
+		cs.breakTargets = append(cs.breakTargets, breakTargetFor)
 		b, ok := cs.parseBlock(pseudoBlock, fname, []ast.Stmt{stmt.Body}, inParams, outParams, true)
+		cs.breakTargets = cs.breakTargets[:len(cs.breakTargets)-1]
 		if !ok {
 			return nil, false
 		}
@@ -324,16 +358,22 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 		v.forLoopCounter = true
 		block.vars = append(block.vars, v)
 
-		stmts = append(stmts, shaderir.Stmt{
+		forStmt := shaderir.Stmt{
 			Type:        shaderir.For,
 			Blocks:      []*shaderir.Block{bodyir},
 			ForVarType:  vartype,
 			ForVarIndex: varidx,
-			ForInit:     init,
-			ForEnd:      end,
 			ForOp:       op,
 			ForDelta:    delta,
-		})
+		}
+		if initExpr.Type == shaderir.NumberExpr && endExpr.Type == shaderir.NumberExpr {
+			forStmt.ForInit = initExpr.Const
+			forStmt.ForEnd = endExpr.Const
+		} else {
+			// The bounds are determined at runtime. The backends emit a loop with a constant cap instead.
+			forStmt.Exprs = []shaderir.Expr{initExpr, endExpr}
+		}
+		stmts = append(stmts, forStmt)
 
 	case *ast.IfStmt:
 		if stmt.Init != nil {
@@ -360,7 +400,7 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 			for _, t := range ts {
 				tss = append(tss, t.String())
 			}
-			cs.addError(stmt.Pos(), fmt.Sprintf("if-condition must be bool but: %s", strings.Join(tss, ", ")))
+			cs.addError(stmt.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("if-condition must be bool but: %s", strings.Join(tss, ", ")))
 			return nil, false
 		}
 		stmts = append(stmts, ss...)
@@ -395,6 +435,13 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 			Blocks: bs,
 		})
 
+	case *ast.SwitchStmt:
+		ss, ok := cs.parseSwitch(block, fname, stmt, inParams, outParams)
+		if !ok {
+			return nil, false
+		}
+		stmts = append(stmts, ss...)
+
 	case *ast.IncDecStmt:
 		exprs, _, ss, ok := cs.parseExpr(block, stmt.X, true)
 		if !ok {
@@ -432,7 +479,7 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 			if !(len(stmt.Results) == 0 && len(outParams) > 0 && outParams[0].name != "") {
 				// TODO: Check variable shadowings.
 				// https://golang.org/ref/spec#Return_statements
-				cs.addError(stmt.Pos(), fmt.Sprintf("the number of returning variables must be %d but %d", len(outParams), len(stmt.Results)))
+				cs.addError(stmt.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("the number of returning variables must be %d but %d", len(outParams), len(stmt.Results)))
 				return nil, false
 			}
 		}
@@ -446,18 +493,18 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 
 			if len(exprs) > 1 {
 				if len(stmt.Results) > 1 || len(outParams) == 1 {
-					cs.addError(r.Pos(), "single-value context and multiple-value context cannot be mixed")
+					cs.addError(r.Pos(), ErrorCodeWrongValueCount, "single-value context and multiple-value context cannot be mixed")
 					return nil, false
 				}
 			}
 
 			if len(outParams) > 1 && len(stmt.Results) == 1 {
 				if len(exprs) == 1 {
-					cs.addError(stmt.Pos(), fmt.Sprintf("the number of returning variables must be %d but %d", len(outParams), len(stmt.Results)))
+					cs.addError(stmt.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("the number of returning variables must be %d but %d", len(outParams), len(stmt.Results)))
 					return nil, false
 				}
 				if len(exprs) > 1 && len(exprs) != len(outParams) {
-					cs.addError(stmt.Pos(), fmt.Sprintf("the number of returning variables must be %d but %d", len(outParams), len(exprs)))
+					cs.addError(stmt.Pos(), ErrorCodeWrongValueCount, fmt.Sprintf("the number of returning variables must be %d but %d", len(outParams), len(exprs)))
 					return nil, false
 				}
 			}
@@ -477,7 +524,7 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 				}
 
 				if !t.Equal(&outParams[i+j].typ) {
-					cs.addError(stmt.Pos(), fmt.Sprintf("cannot use type %s as type %s in return argument", t.String(), &outParams[i].typ))
+					cs.addError(stmt.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("cannot use type %s as type %s in return argument", t.String(), &outParams[i].typ))
 					return nil, false
 				}
 
@@ -498,26 +545,70 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 		})
 
 	case *ast.BranchStmt:
+		if stmt.Label != nil {
+			cs.addError(stmt.Pos(), ErrorCodeUnsupported, fmt.Sprintf("%s with a label is not supported", stmt.Tok))
+			return nil, false
+		}
 		switch stmt.Tok {
 		case token.BREAK:
+			if len(cs.breakTargets) == 0 {
+				cs.addError(stmt.Pos(), ErrorCodeInvalidControlFlow, "break is not in a loop or switch")
+				return nil, false
+			}
+			// A switch statement is converted to if-else statements, then break can be used only at the end of
+			// a case clause.
+			if cs.breakTargets[len(cs.breakTargets)-1] == breakTargetSwitch {
+				cs.addError(stmt.Pos(), ErrorCodeInvalidControlFlow, "break in a switch statement must be at the end of a case clause")
+				return nil, false
+			}
 			stmts = append(stmts, shaderir.Stmt{
 				Type: shaderir.Break,
 			})
 		case token.CONTINUE:
+			var inFor bool
+			for _, t := range cs.breakTargets {
+				if t == breakTargetFor {
+					inFor = true
+					break
+				}
+			}
+			if !inFor {
+				cs.addError(stmt.Pos(), ErrorCodeInvalidControlFlow, "continue is not in a loop")
+				return nil, false
+			}
 			stmts = append(stmts, shaderir.Stmt{
 				Type: shaderir.Continue,
 			})
+		case token.FALLTHROUGH:
+			cs.addError(stmt.Pos(), ErrorCodeUnsupported, "fallthrough is not supported")
+			return nil, false
 		default:
-			cs.addError(stmt.Pos(), fmt.Sprintf("invalid token: %s", stmt.Tok))
+			cs.addError(stmt.Pos(), ErrorCodeInvalid, fmt.Sprintf("invalid token: %s", stmt.Tok))
 			return nil, false
 		}
 
 	case *ast.ExprStmt:
-		if _, ok := stmt.X.(*ast.CallExpr); !ok {
-			cs.addError(stmt.Pos(), fmt.Sprintf("the statement is evaluated but not used"))
+		call, ok := stmt.X.(*ast.CallExpr)
+		if !ok {
+			cs.addError(stmt.Pos(), ErrorCodeUnused, fmt.Sprintf("the statement is evaluated but not used"))
 			return nil, false
 		}
 
+		if cs.isDiscardCall(block, call) {
+			if fname != cs.fragmentEntry {
+				cs.addError(stmt.Pos(), ErrorCodeEntryPoint, "discard can be called only in the fragment entry point")
+				return nil, false
+			}
+			if len(call.Args) != 0 {
+				cs.addError(stmt.Pos(), ErrorCodeWrongArgumentCount, "too many arguments in call to discard")
+				return nil, false
+			}
+			stmts = append(stmts, shaderir.Stmt{
+				Type: shaderir.Discard,
+			})
+			return stmts, true
+		}
+
 		exprs, _, ss, ok := cs.parseExpr(block, stmt.X, true)
 		if !ok {
 			return nil, false
@@ -531,7 +622,7 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 				continue
 			}
 			if expr.Exprs[0].Type == shaderir.BuiltinFuncExpr {
-				cs.addError(stmt.Pos(), fmt.Sprintf("the statement is evaluated but not used"))
+				cs.addError(stmt.Pos(), ErrorCodeUnused, fmt.Sprintf("the statement is evaluated but not used"))
 				return nil, false
 			}
 			stmts = append(stmts, shaderir.Stmt{
@@ -541,7 +632,7 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 		}
 
 	default:
-		cs.addError(stmt.Pos(), fmt.Sprintf("unexpected statement: %#v", stmt))
+		cs.addError(stmt.Pos(), ErrorCodeInvalid, fmt.Sprintf("unexpected statement: %#v", stmt))
 		return nil, false
 	}
 	return stmts, true
@@ -567,7 +658,7 @@ func (cs *compileState) assign(block *block, fname string, pos token.Pos, lhs, r
 				if name != "_" {
 					for _, v := range block.vars {
 						if v.name == name {
-							cs.addError(pos, fmt.Sprintf("duplicated local variable name: %s", name))
+							cs.addError(pos, ErrorCodeRedeclared, fmt.Sprintf("duplicated local variable name: %s", name))
 							return nil, false
 						}
 					}
@@ -577,7 +668,7 @@ func (cs *compileState) assign(block *block, fname string, pos token.Pos, lhs, r
 					ts = origts
 				}
 				if len(ts) > 1 {
-					cs.addError(pos, fmt.Sprintf("single-value context and multiple-value context cannot be mixed"))
+					cs.addError(pos, ErrorCodeWrongValueCount, fmt.Sprintf("single-value context and multiple-value context cannot be mixed"))
 					return nil, false
 				}
 
@@ -589,11 +680,11 @@ func (cs *compileState) assign(block *block, fname string, pos token.Pos, lhs, r
 			}
 
 			if len(r) > 1 {
-				cs.addError(pos, fmt.Sprintf("single-value context and multiple-value context cannot be mixed"))
+				cs.addError(pos, ErrorCodeWrongValueCount, fmt.Sprintf("single-value context and multiple-value context cannot be mixed"))
 				return nil, false
 			}
 
-			l, _, ss, ok := cs.parseExpr(block, lhs[i], false)
+			l, lts, ss, ok := cs.parseExpr(block, lhs[i], false)
 			if !ok {
 				return nil, false
 			}
@@ -621,16 +712,20 @@ func (cs *compileState) assign(block *block, fname string, pos token.Pos, lhs, r
 			}
 
 			if isAssignmentForbidden(&l[0]) {
-				cs.addError(pos, fmt.Sprintf("a uniform variable cannot be assigned"))
+				cs.addError(pos, ErrorCodeInvalidOperation, fmt.Sprintf("a uniform variable cannot be assigned"))
 				return nil, false
 			}
 			allblank = false
 
 			if r[0].Type == shaderir.NumberExpr {
-				t, ok := block.findLocalVariableByIndex(l[0].Index)
-				if !ok {
-					cs.addError(pos, fmt.Sprintf("unexpected local variable index: %d", l[0].Index))
-					return nil, false
+				t := lts[0]
+				if l[0].Type == shaderir.LocalVariable {
+					lt, ok := block.findLocalVariableByIndex(l[0].Index)
+					if !ok {
+						cs.addError(pos, ErrorCodeInvalid, fmt.Sprintf("unexpected local variable index: %d", l[0].Index))
+						return nil, false
+					}
+					t = lt
 				}
 				switch t.Main {
 				case shaderir.Int:
@@ -686,7 +781,7 @@ func (cs *compileState) assign(block *block, fname string, pos token.Pos, lhs, r
 					return nil, false
 				}
 				if len(rhsExprs) != len(lhs) {
-					cs.addError(pos, fmt.Sprintf("single-value context and multiple-value context cannot be mixed"))
+					cs.addError(pos, ErrorCodeWrongValueCount, fmt.Sprintf("single-value context and multiple-value context cannot be mixed"))
 				}
 				stmts = append(stmts, ss...)
 			}
@@ -696,7 +791,7 @@ func (cs *compileState) assign(block *block, fname string, pos token.Pos, lhs, r
 				if name != "_" {
 					for _, v := range block.vars {
 						if v.name == name {
-							cs.addError(pos, fmt.Sprintf("duplicated local variable name: %s", name))
+							cs.addError(pos, ErrorCodeRedeclared, fmt.Sprintf("duplicated local variable name: %s", name))
 							return nil, false
 						}
 					}
@@ -729,7 +824,7 @@ func (cs *compileState) assign(block *block, fname string, pos token.Pos, lhs, r
 	}
 
 	if define && allblank {
-		cs.addError(pos, fmt.Sprintf("no new variables on left side of :="))
+		cs.addError(pos, ErrorCodeInvalidOperation, fmt.Sprintf("no new variables on left side of :="))
 		return nil, false
 	}
 
@@ -748,3 +843,179 @@ func toDefaultType(v gconstant.Value) shaderir.Type {
 	// TODO: Should this be an error?
 	return shaderir.Type{}
 }
+
+// switchTagName is the name of the local variable to hold the tag value of a switch statement.
+// This is not a valid identifier so that this never conflicts with user-defined names.
+const switchTagName = "switch tag"
+
+// parseSwitch parses a switch statement.
+//
+// As the shader languages for some environments don't have switch statements, or have only switch statements
+// for integers, a switch statement is rewritten as a chain of if-else statements:
+//
+//	{
+//		init
+//		tag := tagExpr
+//		if tag == a || tag == b {
+//			...
+//		} else if tag == c {
+//			...
+//		} else {
+//			(default)
+//		}
+//	}
+func (cs *compileState) parseSwitch(block *block, fname string, stmt *ast.SwitchStmt, inParams, outParams []variable) ([]shaderir.Stmt, bool) {
+	var clauses []*ast.CaseClause
+	var defaultClause *ast.CaseClause
+	for _, s := range stmt.Body.List {
+		c := s.(*ast.CaseClause)
+		if c.List == nil {
+			if defaultClause != nil {
+				cs.addError(c.Pos(), ErrorCodeInvalid, "multiple defaults in switch")
+				return nil, false
+			}
+			defaultClause = c
+			continue
+		}
+		clauses = append(clauses, c)
+	}
+
+	var stmts []ast.Stmt
+	if stmt.Init != nil {
+		stmts = append(stmts, stmt.Init)
+	}
+
+	var tag ast.Expr
+	if stmt.Tag != nil {
+		if len(clauses) > 0 {
+			tag = &ast.Ident{
+				NamePos: stmt.Tag.Pos(),
+				Name:    switchTagName,
+			}
+			stmts = append(stmts, &ast.AssignStmt{
+				Lhs:    []ast.Expr{tag},
+				TokPos: stmt.Tag.Pos(),
+				Tok:    token.DEFINE,
+				Rhs:    []ast.Expr{stmt.Tag},
+			})
+		} else {
+			// When there are no cases to compare, the tag value is never used.
+			stmts = append(stmts, &ast.AssignStmt{
+				Lhs: []ast.Expr{
+					&ast.Ident{
+						NamePos: stmt.Tag.Pos(),
+						Name:    "_",
+					},
+				},
+				TokPos: stmt.Tag.Pos(),
+				Tok:    token.ASSIGN,
+				Rhs:    []ast.Expr{stmt.Tag},
+			})
+		}
+	}
+
+	var ifStmt ast.Stmt
+	if defaultClause != nil {
+		ifStmt = &ast.BlockStmt{
+			Lbrace: defaultClause.Colon,
+			List:   caseClauseBody(defaultClause),
+		}
+	}
+	for i := len(clauses) - 1; i >= 0; i-- {
+		c := clauses[i]
+
+		var cond ast.Expr
+		for _, e := range c.List {
+			if tag != nil {
+				e = &ast.BinaryExpr{
+					X:     tag,
+					OpPos: e.Pos(),
+					Op:    token.EQL,
+					Y:     e,
+				}
+			}
+			if cond == nil {
+				cond = e
+				continue
+			}
+			cond = &ast.BinaryExpr{
+				X:     cond,
+				OpPos: e.Pos(),
+				Op:    token.LOR,
+				Y:     e,
+			}
+		}
+
+		s := &ast.IfStmt{
+			If:   c.Case,
+			Cond: cond,
+			Body: &ast.BlockStmt{
+				Lbrace: c.Colon,
+				List:   caseClauseBody(c),
+			},
+		}
+		if ifStmt != nil {
+			s.Else = ifStmt
+		}
+		ifStmt = s
+	}
+	if ifStmt != nil {
+		stmts = append(stmts, ifStmt)
+	}
+
+	cs.breakTargets = append(cs.breakTargets, breakTargetSwitch)
+	defer func() {
+		cs.breakTargets = cs.breakTargets[:len(cs.breakTargets)-1]
+	}()
+
+	b, ok := cs.parseBlock(block, fname, stmts, inParams, outParams, true)
+	if !ok {
+		return nil, false
+	}
+	for _, v := range b.vars {
+		if v.name != switchTagName {
+			continue
+		}
+		switch v.typ.Main {
+		case shaderir.Bool, shaderir.Int, shaderir.Float:
+		default:
+			cs.addError(stmt.Tag.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("switch tag must be bool, int or float but: %s", v.typ.String()))
+			return nil, false
+		}
+	}
+
+	return []shaderir.Stmt{
+		{
+			Type:   shaderir.BlockStmt,
+			Blocks: []*shaderir.Block{b.ir},
+		},
+	}, true
+}
+
+// caseClauseBody returns the statements of the case clause c without the trailing break statement.
+func caseClauseBody(c *ast.CaseClause) []ast.Stmt {
+	body := c.Body
+	if len(body) == 0 {
+		return body
+	}
+	if b, ok := body[len(body)-1].(*ast.BranchStmt); ok && b.Tok == token.BREAK && b.Label == nil {
+		return body[:len(body)-1]
+	}
+	return body
+}
+
+// isDiscardCall reports whether the call expression is a call of the built-in discard, which is not shadowed by
+// a user-defined function.
+func (cs *compileState) isDiscardCall(block *block, call *ast.CallExpr) bool {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok || ident.Name != "discard" {
+		return false
+	}
+	if _, ok := cs.findFunction(ident.Name); ok {
+		return false
+	}
+	if _, _, ok := block.findLocalVariable(ident.Name, false); ok {
+		return false
+	}
+	return true
+}
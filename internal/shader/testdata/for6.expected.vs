@@ -0,0 +1,37 @@
+uniform float U0;
+
+void F0(in int l0, out vec2 l1);
+
+void F0(in int l0, out vec2 l1) {
+	vec2 l2 = vec2(0);
+	l2 = vec2(0.0);
+	{
+		int l3 = 0;
+		for (int l3_n = 0; l3_n < 1024; l3_n++) {
+			if (l3_n > 0) {
+				l3++;
+			}
+			if (!(l3 < l0)) {
+				break;
+			}
+			if (((l2).x) >= (100.0)) {
+				continue;
+			}
+			(l2).x = ((l2).x) + (float(l3));
+		}
+	}
+	{
+		float l4 = U0;
+		for (int l4_n = 0; l4_n < 1024; l4_n++) {
+			if (l4_n > 0) {
+				l4 -= 5.0000000000e-01;
+			}
+			if (!(l4 >= 0.0)) {
+				break;
+			}
+			(l2).y = ((l2).y) + (l4);
+		}
+	}
+	l1 = l2;
+	return;
+}
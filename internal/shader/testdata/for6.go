@@ -0,0 +1,17 @@
+package main
+
+var Count float
+
+func Foo(n int) vec2 {
+	v := vec2(0)
+	for i := 0; i < n; i++ {
+		if v.x >= 100 {
+			continue
+		}
+		v.x += float(i)
+	}
+	for f := Count; f >= 0; f -= 0.5 {
+		v.y += f
+	}
+	return v
+}
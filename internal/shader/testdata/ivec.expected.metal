@@ -0,0 +1,22 @@
+void F0(int l0, int2 l1, thread int3& l2);
+
+void F0(int l0, int2 l1, thread int3& l2) {
+	int l3 = 0;
+	int l4 = 0;
+	int l5 = 0;
+	int l6 = 0;
+	int3 l7 = int3(0);
+	l3 = (l0) % (7);
+	l4 = ((l0) << (2)) | ((l0) >> (1));
+	l5 = ((l0) & (255)) ^ (~(l0));
+	l6 = (l0) & (-4);
+	l1 = (l1) % (int2(3));
+	l1 = (l1) << (int2(1, 2));
+	l1 = (l1) & (int2(15));
+	l7 = int3(l1, (((l3) + (l4)) + (l5)) + (l6));
+	if (((l7).z) == (1)) {
+		(l7).xy = (l1).yx;
+	}
+	l2 = l7;
+	return;
+}
@@ -0,0 +1,22 @@
+void F0(in int l0, in ivec2 l1, out ivec3 l2);
+
+void F0(in int l0, in ivec2 l1, out ivec3 l2) {
+	int l3 = 0;
+	int l4 = 0;
+	int l5 = 0;
+	int l6 = 0;
+	ivec3 l7 = ivec3(0);
+	l3 = modInt((l0), (7));
+	l4 = orInt((leftShiftInt((l0), (2))), (rightShiftInt((l0), (1))));
+	l5 = xorInt((andInt((l0), (255))), ((-(l0) - 1)));
+	l6 = andInt((l0), (-4));
+	l1 = modInt((l1), (ivec2(3)));
+	l1 = leftShiftInt((l1), (ivec2(1, 2)));
+	l1 = andInt((l1), (ivec2(15)));
+	l7 = ivec3(l1, (((l3) + (l4)) + (l5)) + (l6));
+	if (((l7).z) == (1)) {
+		(l7).xy = (l1).yx;
+	}
+	l2 = l7;
+	return;
+}
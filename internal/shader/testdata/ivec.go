@@ -0,0 +1,16 @@
+package main
+
+func Foo(x int, v ivec2) ivec3 {
+	a := x % 7
+	b := (x << 2) | (x >> 1)
+	c := x&0xff ^ ^x
+	d := x &^ 3
+	v %= 3
+	v <<= ivec2(1, 2)
+	v &= 0xf
+	w := ivec3(v, a+b+c+d)
+	if w.z == 1 {
+		w.xy = v.yx
+	}
+	return w
+}
@@ -0,0 +1,10 @@
+varying vec2 V0;
+varying vec4 V1;
+
+void main(void) {
+	vec4 l0 = vec4(0);
+	l0 = V1;
+	gl_FragData[0] = vec4((gl_FragCoord).x, (V0).y, (l0).z, 1.0);
+	gl_FragData[1] = l0;
+	return;
+}
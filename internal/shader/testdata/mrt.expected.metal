@@ -0,0 +1,36 @@
+struct Attributes {
+	packed_float2 M0;
+	packed_float2 M1;
+	packed_float4 M2;
+};
+
+struct Varyings {
+	float4 Position [[position]];
+	float2 M0;
+	float4 M1;
+};
+
+struct FragmentOut {
+	float4 M0 [[color(0)]];
+	float4 M1 [[color(1)]];
+};
+
+vertex Varyings Vertex(
+	uint vid [[vertex_id]],
+	const device Attributes* attributes [[buffer(0)]]) {
+	Varyings varyings = {};
+	varyings.Position = float4(attributes[vid].M0, 0.0, 1.0);
+	varyings.M0 = attributes[vid].M1;
+	varyings.M1 = attributes[vid].M2;
+	return varyings;
+}
+
+fragment FragmentOut Fragment(
+	Varyings varyings [[stage_in]]) {
+	FragmentOut out = {};
+	float4 l0 = float4(0);
+	l0 = varyings.M1;
+	out.M0 = float4((varyings.Position).x, (varyings.M0).y, (l0).z, 1.0);
+	out.M1 = l0;
+	return out;
+}
@@ -0,0 +1,10 @@
+package main
+
+func Vertex(position vec2, texCoord vec2, color vec4) (position vec4, texCoord vec2, color vec4) {
+	return vec4(position, 0, 1), texCoord, color
+}
+
+func Fragment(position vec4, texCoord vec2, color vec4) (vec4, vec4) {
+	c := color
+	return vec4(position.x, texCoord.y, c.z, 1), c
+}
@@ -0,0 +1,17 @@
+uniform int U0;
+
+void main(void) {
+	if (((gl_FragCoord).x) < (0.0)) {
+		discard;
+	}
+	{
+		int l0 = 0;
+		l0 = U0;
+		if ((l0) == (0)) {
+			gl_FragColor = vec4(1.0);
+			return;
+		} else {
+			discard;
+		}
+	}
+}
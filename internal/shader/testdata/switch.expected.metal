@@ -0,0 +1,69 @@
+struct Attributes {
+	packed_float2 M0;
+};
+
+void F0(constant int& U0, float l0, thread float2& l1);
+
+void F0(constant int& U0, float l0, thread float2& l1) {
+	{
+		int l2 = 0;
+		l2 = U0;
+		if ((l2) == (0)) {
+			l1 = float2(l0);
+			return;
+		} else {
+			if (((l2) == (1)) || ((l2) == (2))) {
+				l0 = (l0) * (2.0);
+			} else {
+				l0 = -(l0);
+			}
+		}
+	}
+	{
+		if ((l0) < (0.0)) {
+			l1 = float2(0.0);
+			return;
+		} else {
+			if ((l0) > (1.0)) {
+				l0 = 1.0;
+			}
+		}
+	}
+	{
+		float l2 = float(0);
+		l2 = (l0) * (2.0);
+	}
+	l1 = float2(l0, 1.0);
+	return;
+}
+
+vertex Varyings Vertex(
+	uint vid [[vertex_id]],
+	const device Attributes* attributes [[buffer(0)]],
+	constant int& U0 [[buffer(1)]]) {
+	Varyings varyings = {};
+	float2 l0 = float2(0);
+	F0(U0, (attributes[vid].M0).x, l0);
+	varyings.Position = float4(l0, 0.0, 1.0);
+	return varyings;
+}
+
+fragment float4 Fragment(
+	Varyings varyings [[stage_in]],
+	constant int& U0 [[buffer(1)]]) {
+	float4 out = float4(0);
+	if (((varyings.Position).x) < (0.0)) {
+		discard_fragment();
+	}
+	{
+		int l0 = 0;
+		l0 = U0;
+		if ((l0) == (0)) {
+			out = float4(1.0);
+			return out;
+		} else {
+			discard_fragment();
+		}
+	}
+	return out;
+}
@@ -0,0 +1,44 @@
+uniform int U0;
+attribute vec2 A0;
+
+void F0(in float l0, out vec2 l1);
+
+void F0(in float l0, out vec2 l1) {
+	{
+		int l2 = 0;
+		l2 = U0;
+		if ((l2) == (0)) {
+			l1 = vec2(l0);
+			return;
+		} else {
+			if (((l2) == (1)) || ((l2) == (2))) {
+				l0 = (l0) * (2.0);
+			} else {
+				l0 = -(l0);
+			}
+		}
+	}
+	{
+		if ((l0) < (0.0)) {
+			l1 = vec2(0.0);
+			return;
+		} else {
+			if ((l0) > (1.0)) {
+				l0 = 1.0;
+			}
+		}
+	}
+	{
+		float l2 = float(0);
+		l2 = (l0) * (2.0);
+	}
+	l1 = vec2(l0, 1.0);
+	return;
+}
+
+void main(void) {
+	vec2 l0 = vec2(0);
+	F0((A0).x, l0);
+	gl_Position = vec4(l0, 0.0, 1.0);
+	return;
+}
@@ -0,0 +1,40 @@
+package main
+
+var Mode int
+
+func Foo(x float) vec2 {
+	switch Mode {
+	case 0:
+		return vec2(x)
+	case 1, 2:
+		x *= 2
+	default:
+		x = -x
+		break
+	}
+	switch {
+	case x < 0:
+		return vec2(0)
+	case x > 1:
+		x = 1
+	}
+	switch y := x * 2; y {
+	}
+	return vec2(x, 1)
+}
+
+func Vertex(pos vec2) vec4 {
+	return vec4(Foo(pos.x), 0, 1)
+}
+
+func Fragment(pos vec4) vec4 {
+	if pos.x < 0 {
+		discard()
+	}
+	switch Mode {
+	case 0:
+		return vec4(1)
+	default:
+		discard()
+	}
+}
@@ -44,13 +44,19 @@ func (cs *compileState) parseType(block *block, expr ast.Expr) (shaderir.Type, b
 			return shaderir.Type{Main: shaderir.Mat3}, true
 		case "mat4":
 			return shaderir.Type{Main: shaderir.Mat4}, true
+		case "ivec2":
+			return shaderir.Type{Main: shaderir.IVec2}, true
+		case "ivec3":
+			return shaderir.Type{Main: shaderir.IVec3}, true
+		case "ivec4":
+			return shaderir.Type{Main: shaderir.IVec4}, true
 		default:
-			cs.addError(t.Pos(), fmt.Sprintf("unexpected type: %s", t.Name))
+			cs.addError(t.Pos(), ErrorCodeUndefined, fmt.Sprintf("unexpected type: %s", t.Name))
 			return shaderir.Type{}, false
 		}
 	case *ast.ArrayType:
 		if t.Len == nil {
-			cs.addError(t.Pos(), fmt.Sprintf("array length must be specified"))
+			cs.addError(t.Pos(), ErrorCodeInvalidConstant, fmt.Sprintf("array length must be specified"))
 			return shaderir.Type{}, false
 		}
 		var length int
@@ -62,16 +68,16 @@ func (cs *compileState) parseType(block *block, expr ast.Expr) (shaderir.Type, b
 				return shaderir.Type{}, false
 			}
 			if len(exprs) != 1 {
-				cs.addError(t.Pos(), fmt.Sprintf("invalid length of array"))
+				cs.addError(t.Pos(), ErrorCodeInvalidConstant, fmt.Sprintf("invalid length of array"))
 				return shaderir.Type{}, false
 			}
 			if exprs[0].Type != shaderir.NumberExpr {
-				cs.addError(t.Pos(), fmt.Sprintf("length of array must be a constant number"))
+				cs.addError(t.Pos(), ErrorCodeInvalidConstant, fmt.Sprintf("length of array must be a constant number"))
 				return shaderir.Type{}, false
 			}
 			l, ok := gconstant.Int64Val(exprs[0].Const)
 			if !ok {
-				cs.addError(t.Pos(), fmt.Sprintf("length of array must be an integer"))
+				cs.addError(t.Pos(), ErrorCodeMismatchedTypes, fmt.Sprintf("length of array must be an integer"))
 				return shaderir.Type{}, false
 			}
 			length = int(l)
@@ -82,7 +88,7 @@ func (cs *compileState) parseType(block *block, expr ast.Expr) (shaderir.Type, b
 			return shaderir.Type{}, false
 		}
 		if elm.Main == shaderir.Array {
-			cs.addError(t.Pos(), fmt.Sprintf("array of array is forbidden"))
+			cs.addError(t.Pos(), ErrorCodeUnsupported, fmt.Sprintf("array of array is forbidden"))
 			return shaderir.Type{}, false
 		}
 		return shaderir.Type{
@@ -91,10 +97,60 @@ func (cs *compileState) parseType(block *block, expr ast.Expr) (shaderir.Type, b
 			Length: length,
 		}, true
 	case *ast.StructType:
-		cs.addError(t.Pos(), "struct is not implemented")
+		cs.addError(t.Pos(), ErrorCodeUnsupported, "struct is not implemented")
 		return shaderir.Type{}, false
 	default:
-		cs.addError(t.Pos(), fmt.Sprintf("unepxected type: %v", t))
+		cs.addError(t.Pos(), ErrorCodeInvalid, fmt.Sprintf("unepxected type: %v", t))
 		return shaderir.Type{}, false
 	}
 }
+
+// isIntType reports whether t is int or an integer vector type.
+func isIntType(t *shaderir.Type) bool {
+	switch t.Main {
+	case shaderir.Int, shaderir.IVec2, shaderir.IVec3, shaderir.IVec4:
+		return true
+	}
+	return false
+}
+
+// isIntVectorType reports whether t is an integer vector type.
+func isIntVectorType(t *shaderir.Type) bool {
+	switch t.Main {
+	case shaderir.IVec2, shaderir.IVec3, shaderir.IVec4:
+		return true
+	}
+	return false
+}
+
+// isIntOp reports whether op is an operator that is defined only on integers.
+func isIntOp(op shaderir.Op) bool {
+	switch op {
+	case shaderir.ModOp, shaderir.LeftShift, shaderir.RightShift, shaderir.And, shaderir.Or, shaderir.Xor:
+		return true
+	}
+	return false
+}
+
+// intVectorConstructor returns an expression to convert the int expression expr to the integer vector type t.
+func intVectorConstructor(expr shaderir.Expr, t shaderir.BasicType) shaderir.Expr {
+	var f shaderir.BuiltinFunc
+	switch t {
+	case shaderir.IVec2:
+		f = shaderir.IVec2F
+	case shaderir.IVec3:
+		f = shaderir.IVec3F
+	case shaderir.IVec4:
+		f = shaderir.IVec4F
+	}
+	return shaderir.Expr{
+		Type: shaderir.Call,
+		Exprs: []shaderir.Expr{
+			{
+				Type:        shaderir.BuiltinFuncExpr,
+				BuiltinFunc: f,
+			},
+			expr,
+		},
+	}
+}
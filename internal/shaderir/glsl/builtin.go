@@ -0,0 +1,135 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glsl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// builtinFunctions is the GLSL implementation of Kage's built-in functions that GLSL doesn't have.
+var builtinFunctions = smootherstepFunctions() + `
+
+vec3 rgbToHSV(vec3 c) {
+	vec4 k = vec4(0.0, -1.0/3.0, 2.0/3.0, -1.0);
+	vec4 p = mix(vec4(c.bg, k.wz), vec4(c.gb, k.xy), step(c.b, c.g));
+	vec4 q = mix(vec4(p.xyw, c.r), vec4(c.r, p.yzx), step(p.x, c.r));
+	float d = q.x - min(q.w, q.y);
+	float e = 1.0e-10;
+	return vec3(abs(q.z + (q.w - q.y) / (6.0*d + e)), d / (q.x + e), q.x);
+}
+
+vec3 hsvToRGB(vec3 c) {
+	vec4 k = vec4(1.0, 2.0/3.0, 1.0/3.0, 3.0);
+	vec3 p = abs(fract(c.xxx + k.xyz) * 6.0 - k.www);
+	return c.z * mix(k.xxx, clamp(p - k.xxx, vec3(0.0), vec3(1.0)), c.y);
+}
+
+float hash(float p) {
+	p = fract(p * 0.1031);
+	p *= p + 33.33;
+	p *= p + p;
+	return fract(p);
+}
+
+float hash(vec2 p) {
+	vec3 p3 = fract(p.xyx * 0.1031);
+	p3 += dot(p3, p3.yzx + 33.33);
+	return fract((p3.x + p3.y) * p3.z);
+}
+
+float hash(vec3 p) {
+	vec3 p3 = fract(p * 0.1031);
+	p3 += dot(p3, p3.zyx + 31.32);
+	return fract((p3.x + p3.y) * p3.z);
+}
+
+float noise(float p) {
+	float i = floor(p);
+	float f = fract(p);
+	float u = f * f * (3.0 - 2.0*f);
+	return mix(hash(i), hash(i + 1.0), u);
+}
+
+float noise(vec2 p) {
+	vec2 i = floor(p);
+	vec2 f = fract(p);
+	vec2 u = f * f * (3.0 - 2.0*f);
+	float a = hash(i);
+	float b = hash(i + vec2(1.0, 0.0));
+	float c = hash(i + vec2(0.0, 1.0));
+	float d = hash(i + vec2(1.0, 1.0));
+	return mix(mix(a, b, u.x), mix(c, d, u.x), u.y);
+}
+
+float noise(vec3 p) {
+	vec3 i = floor(p);
+	vec3 f = fract(p);
+	vec3 u = f * f * (3.0 - 2.0*f);
+	float a = mix(hash(i), hash(i + vec3(1.0, 0.0, 0.0)), u.x);
+	float b = mix(hash(i + vec3(0.0, 1.0, 0.0)), hash(i + vec3(1.0, 1.0, 0.0)), u.x);
+	float c = mix(hash(i + vec3(0.0, 0.0, 1.0)), hash(i + vec3(1.0, 0.0, 1.0)), u.x);
+	float d = mix(hash(i + vec3(0.0, 1.0, 1.0)), hash(i + vec3(1.0, 1.0, 1.0)), u.x);
+	return mix(mix(a, b, u.y), mix(c, d, u.y), u.z);
+}
+
+mat2 rotation2D(float angle) {
+	float s = sin(angle);
+	float c = cos(angle);
+	return mat2(c, s, -s, c);
+}
+
+mat3 rotation3D(vec3 axis, float angle) {
+	vec3 a = normalize(axis);
+	float s = sin(angle);
+	float c = cos(angle);
+	vec3 t = (1.0 - c) * a;
+	return mat3(
+		t.x*a.x + c, t.x*a.y + a.z*s, t.x*a.z - a.y*s,
+		t.x*a.y - a.z*s, t.y*a.y + c, t.y*a.z + a.x*s,
+		t.x*a.z + a.y*s, t.y*a.z - a.x*s, t.z*a.z + c);
+}
+
+vec4 premultiply(vec4 c) {
+	return vec4(c.rgb * c.a, c.a);
+}
+
+vec4 unpremultiply(vec4 c) {
+	if (c.a == 0.0) {
+		return vec4(0.0);
+	}
+	return vec4(c.rgb / c.a, c.a);
+}`
+
+// smootherstepFunctions returns the overloads of smootherstep.
+func smootherstepFunctions() string {
+	var lines []string
+	for _, t := range []string{"float", "vec2", "vec3", "vec4"} {
+		edgeTypes := []string{t}
+		if t != "float" {
+			edgeTypes = append(edgeTypes, "float")
+		}
+		for _, et := range edgeTypes {
+			if len(lines) > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, fmt.Sprintf("%[1]s smootherstep(%[2]s edge0, %[2]s edge1, %[1]s x) {", t, et))
+			lines = append(lines, fmt.Sprintf("\tx = clamp((x - edge0) / (edge1 - edge0), %[1]s(0.0), %[1]s(1.0));", t))
+			lines = append(lines, "\treturn x * x * x * (x * (x*6.0 - 15.0) + 10.0);")
+			lines = append(lines, "}")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -34,18 +34,104 @@ const (
 )
 
 // utilFunctions is GLSL utility functions for old GLSL versions.
-const utilFunctions = `int modInt(int x, int y) {
+//
+// Old GLSL versions don't have the integer operators %, &, |, ^, << and >>. The functions emulate them.
+var utilFunctions = `int modInt(int x, int y) {
 	return x - y*(x/y);
-}`
+}
+
+int andNonNegativeInt(int x, int y) {
+	int r = 0;
+	int b = 1;
+	for (int i = 0; i < 31; i++) {
+		if (x == 0 || y == 0) {
+			break;
+		}
+		if (modInt(x, 2) == 1 && modInt(y, 2) == 1) {
+			r += b;
+		}
+		x /= 2;
+		y /= 2;
+		b *= 2;
+	}
+	return r;
+}
+
+int andInt(int x, int y) {
+	// For negative values, use ~x = -x-1 and x|y = x+y-(x&y).
+	if (x < 0 && y < 0) {
+		return -1 - ((-x-1) + (-y-1) - andNonNegativeInt(-x-1, -y-1));
+	}
+	if (x < 0) {
+		return y - andNonNegativeInt(y, -x-1);
+	}
+	if (y < 0) {
+		return x - andNonNegativeInt(x, -y-1);
+	}
+	return andNonNegativeInt(x, y);
+}
+
+int orInt(int x, int y) {
+	return x + y - andInt(x, y);
+}
+
+int xorInt(int x, int y) {
+	return x + y - 2*andInt(x, y);
+}
+
+int leftShiftInt(int x, int y) {
+	return x * int(exp2(float(y)));
+}
+
+int rightShiftInt(int x, int y) {
+	int d = int(exp2(float(y)));
+	int q = x / d;
+	// Round toward negative infinity as an arithmetic shift does.
+	if (x < 0 && q*d != x) {
+		q--;
+	}
+	return q;
+}` + intVectorUtilFunctions()
+
+// intVectorUtilFunctions returns the overloads of the utility functions for integer vectors.
+func intVectorUtilFunctions() string {
+	var lines []string
+	for n := 2; n <= 4; n++ {
+		t := fmt.Sprintf("ivec%d", n)
+		lines = append(lines, "")
+		lines = append(lines, fmt.Sprintf("%[1]s modInt(%[1]s x, %[1]s y) {", t))
+		lines = append(lines, "\treturn x - y*(x/y);")
+		lines = append(lines, "}")
+		for _, f := range []string{"andInt", "leftShiftInt", "rightShiftInt"} {
+			var args []string
+			for _, c := range "xyzw"[:n] {
+				args = append(args, fmt.Sprintf("%[1]s(x.%[2]c, y.%[2]c)", f, c))
+			}
+			lines = append(lines, "")
+			lines = append(lines, fmt.Sprintf("%[1]s %[2]s(%[1]s x, %[1]s y) {", t, f))
+			lines = append(lines, fmt.Sprintf("\treturn %s(%s);", t, strings.Join(args, ", ")))
+			lines = append(lines, "}")
+		}
+		lines = append(lines, "")
+		lines = append(lines, fmt.Sprintf("%[1]s orInt(%[1]s x, %[1]s y) {", t))
+		lines = append(lines, "\treturn x + y - andInt(x, y);")
+		lines = append(lines, "}")
+		lines = append(lines, "")
+		lines = append(lines, fmt.Sprintf("%[1]s xorInt(%[1]s x, %[1]s y) {", t))
+		lines = append(lines, "\treturn x + y - 2*andInt(x, y);")
+		lines = append(lines, "}")
+	}
+	return strings.Join(lines, "\n")
+}
 
 func VertexPrelude(version GLSLVersion) string {
 	switch version {
 	case GLSLVersionDefault:
-		return utilFunctions
+		return utilFunctions + "\n\n" + builtinFunctions
 	case GLSLVersionES100:
-		return utilFunctions
+		return utilFunctions + "\n\n" + builtinFunctions
 	case GLSLVersionES300:
-		return `#version 300 es`
+		return `#version 300 es` + "\n\n" + builtinFunctions
 	}
 	return ""
 }
@@ -68,6 +154,7 @@ precision highp float;
 	if version == GLSLVersionDefault || version == GLSLVersionES100 {
 		prelude += "\n\n" + utilFunctions
 	}
+	prelude += "\n\n" + builtinFunctions
 	return prelude
 }
 
@@ -226,8 +313,18 @@ func Compile(p *shaderir.Program, version GLSLVersion) (vertexShader, fragmentSh
 				fslines = append(fslines, fmt.Sprintf("%s %s;", keyword, c.glslVarDecl(p, &t, fmt.Sprintf("V%d", i))))
 			}
 		}
-		if version == GLSLVersionES300 {
+		switch n := p.FragmentColorsCount(); {
+		case version == GLSLVersionES300 && n == 1:
 			fslines = append(fslines, "out vec4 fragColor;")
+		case version == GLSLVersionES300:
+			for i := 0; i < n; i++ {
+				fslines = append(fslines, fmt.Sprintf("layout(location = %d) out vec4 fragColor%d;", i, i))
+			}
+		case version == GLSLVersionES100:
+			// The second and the following colors are just discarded.
+			for i := 1; i < n; i++ {
+				fslines = append(fslines, fmt.Sprintf("vec4 fragColor%d;", i))
+			}
 		}
 
 		var funcs []*shaderir.Func
@@ -339,7 +436,7 @@ func (c *compileContext) glslVarInit(p *shaderir.Program, t *shaderir.Type) stri
 		return "false"
 	case shaderir.Int:
 		return "0"
-	case shaderir.Float, shaderir.Vec2, shaderir.Vec3, shaderir.Vec4, shaderir.Mat2, shaderir.Mat3, shaderir.Mat4:
+	case shaderir.Float, shaderir.Vec2, shaderir.Vec3, shaderir.Vec4, shaderir.Mat2, shaderir.Mat3, shaderir.Mat4, shaderir.IVec2, shaderir.IVec3, shaderir.IVec4:
 		return fmt.Sprintf("%s(0)", basicTypeString(t.Main))
 	default:
 		t0, t1 := c.glslType(p, t)
@@ -428,13 +525,28 @@ func (c *compileContext) localVariableName(p *shaderir.Program, topBlock, block
 			return "gl_FragCoord"
 		case idx < nv+1:
 			return fmt.Sprintf("V%d", idx-1)
-		case idx == nv+1:
-			if c.version == GLSLVersionES300 {
-				return "fragColor"
+		case idx < nv+1+p.FragmentColorsCount():
+			i := idx - (nv + 1)
+			if p.FragmentColorsCount() == 1 {
+				if c.version == GLSLVersionES300 {
+					return "fragColor"
+				}
+				return "gl_FragColor"
+			}
+			switch c.version {
+			case GLSLVersionES100:
+				// GLSL ES 1.00 doesn't have multiple outputs without extensions. Only the first color is output.
+				if i == 0 {
+					return "gl_FragColor"
+				}
+				return fmt.Sprintf("fragColor%d", i)
+			case GLSLVersionES300:
+				return fmt.Sprintf("fragColor%d", i)
+			default:
+				return fmt.Sprintf("gl_FragData[%d]", i)
 			}
-			return "gl_FragColor"
 		default:
-			return fmt.Sprintf("l%d", idx-(nv+2))
+			return fmt.Sprintf("l%d", idx-(nv+1+p.FragmentColorsCount()))
 		}
 	default:
 		return fmt.Sprintf("l%d", idx)
@@ -505,14 +617,37 @@ func (c *compileContext) glslBlock(p *shaderir.Program, topBlock, block *shaderi
 			switch e.Op {
 			case shaderir.Add, shaderir.Sub, shaderir.NotOp:
 				op = string(e.Op)
+			case shaderir.ComplementOp:
+				if c.version == GLSLVersionDefault || c.version == GLSLVersionES100 {
+					// '~' is not defined.
+					return fmt.Sprintf("(-(%s) - 1)", glslExpr(&e.Exprs[0]))
+				}
+				op = string(e.Op)
 			default:
 				op = fmt.Sprintf("?(unexpected op: %s)", string(e.Op))
 			}
 			return fmt.Sprintf("%s(%s)", op, glslExpr(&e.Exprs[0]))
 		case shaderir.Binary:
-			if e.Op == shaderir.ModOp && (c.version == GLSLVersionDefault || c.version == GLSLVersionES100) {
-				// '%' is not defined.
-				return fmt.Sprintf("modInt((%s), (%s))", glslExpr(&e.Exprs[0]), glslExpr(&e.Exprs[1]))
+			if c.version == GLSLVersionDefault || c.version == GLSLVersionES100 {
+				// The integer operators are not defined.
+				var f string
+				switch e.Op {
+				case shaderir.ModOp:
+					f = "modInt"
+				case shaderir.And:
+					f = "andInt"
+				case shaderir.Or:
+					f = "orInt"
+				case shaderir.Xor:
+					f = "xorInt"
+				case shaderir.LeftShift:
+					f = "leftShiftInt"
+				case shaderir.RightShift:
+					f = "rightShiftInt"
+				}
+				if f != "" {
+					return fmt.Sprintf("%s((%s), (%s))", f, glslExpr(&e.Exprs[0]), glslExpr(&e.Exprs[1]))
+				}
 			}
 			return fmt.Sprintf("(%s) %s (%s)", glslExpr(&e.Exprs[0]), e.Op, glslExpr(&e.Exprs[1]))
 		case shaderir.Selection:
@@ -600,9 +735,27 @@ func (c *compileContext) glslBlock(p *shaderir.Program, topBlock, block *shaderi
 			}
 
 			t := s.ForVarType
+			t0, t1 := typeString(&t)
+			if len(s.Exprs) > 0 {
+				// The bounds are not constants. As GLSL ES 1.0 requires constant loop bounds, iterate with
+				// another counter with a constant cap, and check the actual condition in the loop.
+				n := v + "_n"
+				lines = append(lines, fmt.Sprintf("%s{", idt))
+				lines = append(lines, fmt.Sprintf("%s\t%s %s%s = %s;", idt, t0, v, t1, glslExpr(&s.Exprs[0])))
+				lines = append(lines, fmt.Sprintf("%s\tfor (int %s = 0; %s < %d; %s++) {", idt, n, n, shaderir.MaxForLoopIterations, n))
+				lines = append(lines, fmt.Sprintf("%s\t\tif (%s > 0) {", idt, n))
+				lines = append(lines, fmt.Sprintf("%s\t\t\t%s;", idt, delta))
+				lines = append(lines, fmt.Sprintf("%s\t\t}", idt))
+				lines = append(lines, fmt.Sprintf("%s\t\tif (!(%s %s %s)) {", idt, v, op, glslExpr(&s.Exprs[1])))
+				lines = append(lines, fmt.Sprintf("%s\t\t\tbreak;", idt))
+				lines = append(lines, fmt.Sprintf("%s\t\t}", idt))
+				lines = append(lines, c.glslBlock(p, topBlock, s.Blocks[0], level+2)...)
+				lines = append(lines, fmt.Sprintf("%s\t}", idt))
+				lines = append(lines, fmt.Sprintf("%s}", idt))
+				break
+			}
 			init := constantToNumberLiteral(ct, s.ForInit)
 			end := constantToNumberLiteral(ct, s.ForEnd)
-			t0, t1 := typeString(&t)
 			lines = append(lines, fmt.Sprintf("%sfor (%s %s%s = %s; %s %s %s; %s) {", idt, t0, v, t1, init, v, op, end, delta))
 			lines = append(lines, c.glslBlock(p, topBlock, s.Blocks[0], level+1)...)
 			lines = append(lines, fmt.Sprintf("%s}", idt))
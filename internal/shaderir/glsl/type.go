@@ -54,6 +54,12 @@ func basicTypeString(t shaderir.BasicType) string {
 		return "mat3"
 	case shaderir.Mat4:
 		return "mat4"
+	case shaderir.IVec2:
+		return "ivec2"
+	case shaderir.IVec3:
+		return "ivec3"
+	case shaderir.IVec4:
+		return "ivec4"
 	case shaderir.Array:
 		return "?(array)"
 	case shaderir.Struct:
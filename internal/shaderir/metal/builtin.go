@@ -0,0 +1,124 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metal
+
+// builtinFunctions is the Metal implementation of Kage's built-in functions that Metal doesn't have.
+const builtinFunctions = `float smootherstep(float edge0, float edge1, float x) {
+	x = saturate((x - edge0) / (edge1 - edge0));
+	return x * x * x * (x * (x*6.0 - 15.0) + 10.0);
+}
+
+template<typename T>
+T smootherstep(T edge0, T edge1, T x) {
+	x = saturate((x - edge0) / (edge1 - edge0));
+	return x * x * x * (x * (x*6.0 - 15.0) + 10.0);
+}
+
+template<typename T>
+T smootherstep(float edge0, float edge1, T x) {
+	x = saturate((x - edge0) / (edge1 - edge0));
+	return x * x * x * (x * (x*6.0 - 15.0) + 10.0);
+}
+
+float3 rgbToHSV(float3 c) {
+	float4 k = float4(0.0, -1.0/3.0, 2.0/3.0, -1.0);
+	float4 p = mix(float4(c.bg, k.wz), float4(c.gb, k.xy), float4(step(c.b, c.g)));
+	float4 q = mix(float4(p.xyw, c.r), float4(c.r, p.yzx), float4(step(p.x, c.r)));
+	float d = q.x - min(q.w, q.y);
+	float e = 1.0e-10;
+	return float3(abs(q.z + (q.w - q.y) / (6.0*d + e)), d / (q.x + e), q.x);
+}
+
+float3 hsvToRGB(float3 c) {
+	float4 k = float4(1.0, 2.0/3.0, 1.0/3.0, 3.0);
+	float3 p = abs(fract(c.xxx + k.xyz) * 6.0 - k.www);
+	return c.z * mix(k.xxx, saturate(p - k.xxx), float3(c.y));
+}
+
+float hash(float p) {
+	p = fract(p * 0.1031);
+	p *= p + 33.33;
+	p *= p + p;
+	return fract(p);
+}
+
+float hash(float2 p) {
+	float3 p3 = fract(p.xyx * 0.1031);
+	p3 += dot(p3, p3.yzx + 33.33);
+	return fract((p3.x + p3.y) * p3.z);
+}
+
+float hash(float3 p) {
+	float3 p3 = fract(p * 0.1031);
+	p3 += dot(p3, p3.zyx + 31.32);
+	return fract((p3.x + p3.y) * p3.z);
+}
+
+float noise(float p) {
+	float i = floor(p);
+	float f = fract(p);
+	float u = f * f * (3.0 - 2.0*f);
+	return mix(hash(i), hash(i + 1.0), u);
+}
+
+float noise(float2 p) {
+	float2 i = floor(p);
+	float2 f = fract(p);
+	float2 u = f * f * (3.0 - 2.0*f);
+	float a = hash(i);
+	float b = hash(i + float2(1.0, 0.0));
+	float c = hash(i + float2(0.0, 1.0));
+	float d = hash(i + float2(1.0, 1.0));
+	return mix(mix(a, b, u.x), mix(c, d, u.x), u.y);
+}
+
+float noise(float3 p) {
+	float3 i = floor(p);
+	float3 f = fract(p);
+	float3 u = f * f * (3.0 - 2.0*f);
+	float a = mix(hash(i), hash(i + float3(1.0, 0.0, 0.0)), u.x);
+	float b = mix(hash(i + float3(0.0, 1.0, 0.0)), hash(i + float3(1.0, 1.0, 0.0)), u.x);
+	float c = mix(hash(i + float3(0.0, 0.0, 1.0)), hash(i + float3(1.0, 0.0, 1.0)), u.x);
+	float d = mix(hash(i + float3(0.0, 1.0, 1.0)), hash(i + float3(1.0, 1.0, 1.0)), u.x);
+	return mix(mix(a, b, u.y), mix(c, d, u.y), u.z);
+}
+
+float2x2 rotation2D(float angle) {
+	float s = sin(angle);
+	float c = cos(angle);
+	return float2x2(c, s, -s, c);
+}
+
+float3x3 rotation3D(float3 axis, float angle) {
+	float3 a = normalize(axis);
+	float s = sin(angle);
+	float c = cos(angle);
+	float3 t = (1.0 - c) * a;
+	return float3x3(
+		t.x*a.x + c, t.x*a.y + a.z*s, t.x*a.z - a.y*s,
+		t.x*a.y - a.z*s, t.y*a.y + c, t.y*a.z + a.x*s,
+		t.x*a.z + a.y*s, t.y*a.z - a.x*s, t.z*a.z + c);
+}
+
+float4 premultiply(float4 c) {
+	return float4(c.rgb * c.a, c.a);
+}
+
+float4 unpremultiply(float4 c) {
+	if (c.a == 0.0) {
+		return float4(0.0);
+	}
+	return float4(c.rgb / c.a, c.a);
+}`
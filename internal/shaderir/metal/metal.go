@@ -52,7 +52,9 @@ const Prelude = `#include <metal_stdlib>
 
 using namespace metal;
 
-constexpr sampler texture_sampler{filter::nearest};`
+constexpr sampler texture_sampler{filter::nearest};
+
+` + builtinFunctions
 
 func Compile(p *shaderir.Program, vertex, fragment string) (shader string) {
 	c := &compileContext{
@@ -82,6 +84,15 @@ func Compile(p *shaderir.Program, vertex, fragment string) (shader string) {
 		lines = append(lines, "};")
 	}
 
+	if p.FragmentColorsCount() > 1 {
+		lines = append(lines, "")
+		lines = append(lines, "struct FragmentOut {")
+		for i := 0; i < p.FragmentColorsCount(); i++ {
+			lines = append(lines, fmt.Sprintf("\tfloat4 M%[1]d [[color(%[1]d)]];", i))
+		}
+		lines = append(lines, "};")
+	}
+
 	if len(p.Funcs) > 0 {
 		lines = append(lines, "")
 		for _, f := range p.Funcs {
@@ -120,8 +131,12 @@ func Compile(p *shaderir.Program, vertex, fragment string) (shader string) {
 
 	if p.FragmentFunc.Block != nil && len(p.FragmentFunc.Block.Stmts) > 0 {
 		lines = append(lines, "")
+		outType := "float4"
+		if p.FragmentColorsCount() > 1 {
+			outType = "FragmentOut"
+		}
 		lines = append(lines,
-			fmt.Sprintf("fragment float4 %s(", fragment),
+			fmt.Sprintf("fragment %s %s(", outType, fragment),
 			"\tVaryings varyings [[stage_in]]")
 		for i, u := range p.Uniforms {
 			lines[len(lines)-1] += ","
@@ -132,7 +147,11 @@ func Compile(p *shaderir.Program, vertex, fragment string) (shader string) {
 			lines = append(lines, fmt.Sprintf("\ttexture2d<float> T%[1]d [[texture(%[1]d)]]", i))
 		}
 		lines[len(lines)-1] += ") {"
-		lines = append(lines, fmt.Sprintf("\tfloat4 %s = float4(0);", fragmentOut))
+		if p.FragmentColorsCount() > 1 {
+			lines = append(lines, fmt.Sprintf("\tFragmentOut %s = {};", fragmentOut))
+		} else {
+			lines = append(lines, fmt.Sprintf("\tfloat4 %s = float4(0);", fragmentOut))
+		}
 		lines = append(lines, c.metalBlock(p, p.FragmentFunc.Block, p.FragmentFunc.Block, 0)...)
 		if last := fmt.Sprintf("\treturn %s;", fragmentOut); lines[len(lines)-1] != last {
 			lines = append(lines, last)
@@ -203,7 +222,7 @@ func (c *compileContext) metalVarInit(p *shaderir.Program, t *shaderir.Type) str
 		return "false"
 	case shaderir.Int:
 		return "0"
-	case shaderir.Float, shaderir.Vec2, shaderir.Vec3, shaderir.Vec4, shaderir.Mat2, shaderir.Mat3, shaderir.Mat4:
+	case shaderir.Float, shaderir.Vec2, shaderir.Vec3, shaderir.Vec4, shaderir.Mat2, shaderir.Mat3, shaderir.Mat4, shaderir.IVec2, shaderir.IVec3, shaderir.IVec4:
 		return fmt.Sprintf("%s(0)", basicTypeString(t.Main, false))
 	default:
 		t := c.metalType(p, t, false, false)
@@ -301,10 +320,13 @@ func localVariableName(p *shaderir.Program, topBlock *shaderir.Block, idx int) s
 			return fmt.Sprintf("varyings.Position")
 		case idx < nv+1:
 			return fmt.Sprintf("varyings.M%d", idx-1)
-		case idx == nv+1:
+		case idx < nv+1+p.FragmentColorsCount():
+			if p.FragmentColorsCount() > 1 {
+				return fmt.Sprintf("%s.M%d", fragmentOut, idx-(nv+1))
+			}
 			return fragmentOut
 		default:
-			return fmt.Sprintf("l%d", idx-(nv+2))
+			return fmt.Sprintf("l%d", idx-(nv+1+p.FragmentColorsCount()))
 		}
 	default:
 		return fmt.Sprintf("l%d", idx)
@@ -365,7 +387,7 @@ func (c *compileContext) metalBlock(p *shaderir.Program, topBlock, block *shader
 		case shaderir.Unary:
 			var op string
 			switch e.Op {
-			case shaderir.Add, shaderir.Sub, shaderir.NotOp:
+			case shaderir.Add, shaderir.Sub, shaderir.NotOp, shaderir.ComplementOp:
 				op = string(e.Op)
 			default:
 				op = fmt.Sprintf("?(unexpected op: %s)", string(e.Op))
@@ -470,9 +492,27 @@ func (c *compileContext) metalBlock(p *shaderir.Program, topBlock, block *shader
 			}
 
 			t := s.ForVarType
+			ts := typeString(&t, false, false)
+			if len(s.Exprs) > 0 {
+				// The bounds are not constants. Iterate with another counter with a constant cap as GLSL does,
+				// so that the shader behaves in the same way on any backends.
+				n := v + "_n"
+				lines = append(lines, fmt.Sprintf("%s{", idt))
+				lines = append(lines, fmt.Sprintf("%s\t%s %s = %s;", idt, ts, v, metalExpr(&s.Exprs[0])))
+				lines = append(lines, fmt.Sprintf("%s\tfor (int %s = 0; %s < %d; %s++) {", idt, n, n, shaderir.MaxForLoopIterations, n))
+				lines = append(lines, fmt.Sprintf("%s\t\tif (%s > 0) {", idt, n))
+				lines = append(lines, fmt.Sprintf("%s\t\t\t%s;", idt, delta))
+				lines = append(lines, fmt.Sprintf("%s\t\t}", idt))
+				lines = append(lines, fmt.Sprintf("%s\t\tif (!(%s %s %s)) {", idt, v, op, metalExpr(&s.Exprs[1])))
+				lines = append(lines, fmt.Sprintf("%s\t\t\tbreak;", idt))
+				lines = append(lines, fmt.Sprintf("%s\t\t}", idt))
+				lines = append(lines, c.metalBlock(p, topBlock, s.Blocks[0], level+2)...)
+				lines = append(lines, fmt.Sprintf("%s\t}", idt))
+				lines = append(lines, fmt.Sprintf("%s}", idt))
+				break
+			}
 			init := constantToNumberLiteral(ct, s.ForInit)
 			end := constantToNumberLiteral(ct, s.ForEnd)
-			ts := typeString(&t, false, false)
 			lines = append(lines, fmt.Sprintf("%sfor (%s %s = %s; %s %s %s; %s) {", idt, ts, v, init, v, op, end, delta))
 			lines = append(lines, c.metalBlock(p, topBlock, s.Blocks[0], level+1)...)
 			lines = append(lines, fmt.Sprintf("%s}", idt))
@@ -492,7 +532,7 @@ func (c *compileContext) metalBlock(p *shaderir.Program, topBlock, block *shader
 				lines = append(lines, fmt.Sprintf("%sreturn %s;", idt, metalExpr(&s.Exprs[0])))
 			}
 		case shaderir.Discard:
-			lines = append(lines, idt+"discard;")
+			lines = append(lines, idt+"discard_fragment();")
 		default:
 			lines = append(lines, fmt.Sprintf("%s?(unexpected stmt: %d)", idt, s.Type))
 		}
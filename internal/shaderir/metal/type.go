@@ -71,6 +71,12 @@ func basicTypeString(t shaderir.BasicType, packed bool) string {
 		return "float3x3"
 	case shaderir.Mat4:
 		return "float4x4"
+	case shaderir.IVec2:
+		return "int2"
+	case shaderir.IVec3:
+		return "int3"
+	case shaderir.IVec4:
+		return "int4"
 	case shaderir.Array:
 		return "?(array)"
 	case shaderir.Struct:
@@ -100,6 +106,12 @@ func builtinFuncString(f shaderir.BuiltinFunc) string {
 		return "float3x3"
 	case shaderir.Mat4F:
 		return "float4x4"
+	case shaderir.IVec2F:
+		return "int2"
+	case shaderir.IVec3F:
+		return "int3"
+	case shaderir.IVec4F:
+		return "int4"
 	case shaderir.Inversesqrt:
 		return "rsqrt"
 	case shaderir.Mod:
@@ -30,6 +30,18 @@ type Program struct {
 	Funcs        []Func
 	VertexFunc   VertexFunc
 	FragmentFunc FragmentFunc
+
+	// ColorsOutputCount is the number of output colors of the fragment function.
+	ColorsOutputCount int
+}
+
+// FragmentColorsCount returns the number of output colors of the fragment function.
+// If ColorsOutputCount is not specified, FragmentColorsCount returns 1.
+func (p *Program) FragmentColorsCount() int {
+	if p.ColorsOutputCount == 0 {
+		return 1
+	}
+	return p.ColorsOutputCount
 }
 
 type Func struct {
@@ -49,10 +61,11 @@ type VertexFunc struct {
 	Block *Block
 }
 
-// FragmentFunc takes pseudo params, and the number is len(varyings) + 2.
+// FragmentFunc takes pseudo params, and the number is len(varyings) + ColorsOutputCount + 1.
 // If index == 0, the param represents the coordinate of the fragment (gl_FragCoord in GLSL).
-// If index == len(varyings), the param represents (index-1)th verying variable.
-// If index == len(varyings)+1, the param is an out-param representing the color of the pixel (gl_FragColor in GLSL).
+// If 1 <= index <= len(varyings), the param represents (index-1)th verying variable.
+// If len(varyings)+1 <= index < len(varyings)+ColorsOutputCount+1, the param is an out-param representing
+// the (index-len(varyings)-1)th color of the pixel (gl_FragColor or gl_FragData in GLSL).
 type FragmentFunc struct {
 	Block *Block
 }
@@ -64,18 +77,33 @@ type Block struct {
 }
 
 type Stmt struct {
-	Type        StmtType
-	Exprs       []Expr
-	Blocks      []*Block
+	Type   StmtType
+	Exprs  []Expr
+	Blocks []*Block
+
+	// ForVarType, ForVarIndex, ForInit, ForEnd, ForOp and ForDelta represent a for-loop
+	// 'for v := ForInit; v ForOp ForEnd; v += ForDelta'.
+	//
+	// If the initial value or the end value is not a constant, ForInit and ForEnd are nil,
+	// and Exprs has the initial value and the end value expressions instead.
+	// Such a loop is limited to MaxForLoopIterations iterations.
 	ForVarType  Type
 	ForVarIndex int
 	ForInit     constant.Value
 	ForEnd      constant.Value
 	ForOp       Op
 	ForDelta    constant.Value
-	InitIndex   int
+
+	InitIndex int
 }
 
+// MaxForLoopIterations is the maximum number of iterations of a for-loop with non-constant bounds.
+//
+// Some shading languages like GLSL ES 1.0 require constant loop bounds.
+// A loop with non-constant bounds is emitted as a loop with this constant cap, which breaks when the actual
+// condition is not satisfied.
+const MaxForLoopIterations = 1024
+
 type StmtType int
 
 const (
@@ -153,6 +181,7 @@ const (
 	Or                 Op = "|"
 	AndAnd             Op = "&&"
 	OrOr               Op = "||"
+	ComplementOp       Op = "~"
 )
 
 func OpFromToken(t token.Token) (Op, bool) {
@@ -213,6 +242,9 @@ const (
 	Mat2F       BuiltinFunc = "mat2"
 	Mat3F       BuiltinFunc = "mat3"
 	Mat4F       BuiltinFunc = "mat4"
+	IVec2F      BuiltinFunc = "ivec2"
+	IVec3F      BuiltinFunc = "ivec3"
+	IVec4F      BuiltinFunc = "ivec4"
 	Radians     BuiltinFunc = "radians"
 	Degrees     BuiltinFunc = "degrees"
 	Sin         BuiltinFunc = "sin"
@@ -253,6 +285,18 @@ const (
 	Dfdx        BuiltinFunc = "dfdx"
 	Dfdy        BuiltinFunc = "dfdy"
 	Fwidth      BuiltinFunc = "fwidth"
+
+	// The functions below are not in GLSL. Each backend has their implementations.
+
+	Smootherstep  BuiltinFunc = "smootherstep"
+	HSVToRGB      BuiltinFunc = "hsvToRGB"
+	RGBToHSV      BuiltinFunc = "rgbToHSV"
+	Hash          BuiltinFunc = "hash"
+	Noise         BuiltinFunc = "noise"
+	Rotation2D    BuiltinFunc = "rotation2D"
+	Rotation3D    BuiltinFunc = "rotation3D"
+	Premultiply   BuiltinFunc = "premultiply"
+	Unpremultiply BuiltinFunc = "unpremultiply"
 )
 
 func ParseBuiltinFunc(str string) (BuiltinFunc, bool) {
@@ -268,6 +312,9 @@ func ParseBuiltinFunc(str string) (BuiltinFunc, bool) {
 		Mat2F,
 		Mat3F,
 		Mat4F,
+		IVec2F,
+		IVec3F,
+		IVec4F,
 		Sin,
 		Cos,
 		Tan,
@@ -305,7 +352,16 @@ func ParseBuiltinFunc(str string) (BuiltinFunc, bool) {
 		Texture2DF,
 		Dfdx,
 		Dfdy,
-		Fwidth:
+		Fwidth,
+		Smootherstep,
+		HSVToRGB,
+		RGBToHSV,
+		Hash,
+		Noise,
+		Rotation2D,
+		Rotation3D,
+		Premultiply,
+		Unpremultiply:
 		return BuiltinFunc(str), true
 	}
 	return "", false
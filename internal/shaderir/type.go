@@ -65,6 +65,12 @@ func (t *Type) String() string {
 		return "mat3"
 	case Mat4:
 		return "mat4"
+	case IVec2:
+		return "ivec2"
+	case IVec3:
+		return "ivec3"
+	case IVec4:
+		return "ivec4"
 	case Array:
 		return fmt.Sprintf("%s[%d]", t.Sub[0].String(), t.Length)
 	case Struct:
@@ -117,6 +123,9 @@ const (
 	Mat2
 	Mat3
 	Mat4
+	IVec2
+	IVec3
+	IVec4
 	Array
 	Struct
 )
@@ -177,10 +186,10 @@ func (p *Program) LocalVariableType(topBlock, block *Block, idx int) Type {
 			return Type{Main: Vec4}
 		case idx < nv+1:
 			return p.Varyings[idx-1]
-		case idx == nv+1:
+		case idx < nv+1+p.FragmentColorsCount():
 			return Type{Main: Vec4}
 		default:
-			return localVariableType(p, topBlock, block, idx-(nv+2))
+			return localVariableType(p, topBlock, block, idx-(nv+1+p.FragmentColorsCount()))
 		}
 	default:
 		return localVariableType(p, topBlock, block, idx)
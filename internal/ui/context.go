@@ -18,11 +18,13 @@ import (
 	"math"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/buffered"
 	"github.com/hajimehoshi/ebiten/v2/internal/clock"
 	"github.com/hajimehoshi/ebiten/v2/internal/debug"
 	graphicspkg "github.com/hajimehoshi/ebiten/v2/internal/graphics"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicscommand"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/hooks"
 )
@@ -32,7 +34,12 @@ const DefaultTPS = 60
 type Game interface {
 	Layout(outsideWidth, outsideHeight float64, deviceScaleFactor float64) (int, int)
 	Update() error
-	Draw(screenScale float64, offsetX, offsetY float64, needsClearingScreen bool, framebufferYDirection graphicsdriver.YDirection, screenClearedEveryFrame bool) error
+	Draw(screenScaleX, screenScaleY float64, offsetX, offsetY float64, needsClearingScreen bool, framebufferYDirection graphicsdriver.YDirection, screenClearedEveryFrame bool) error
+	OnSuspend()
+	OnResume()
+	OnFocusChanged(focused bool)
+	OnWindowResized(width, height int)
+	OnWindowMaximizedChanged(maximized bool)
 }
 
 type contextImpl struct {
@@ -40,6 +47,16 @@ type contextImpl struct {
 
 	updateCalled bool
 
+	// The lifecycle states that are notified to the game.
+	suspended bool
+	unfocused bool
+
+	// The window states that are notified to the game.
+	windowStateInitialized bool
+	windowWidth            int
+	windowHeight           int
+	windowMaximized        bool
+
 	// The following members must be protected by the mutex m.
 	outsideWidth  float64
 	outsideHeight float64
@@ -86,6 +103,9 @@ func (c *contextImpl) updateFrameImpl(updateCount int, outsideWidth, outsideHeig
 		return err
 	}
 
+	// Call the callbacks for the pixels read and the shaders compiled asynchronously in the previous frames.
+	graphicscommand.RunAsyncCallbacks()
+
 	// Ensure that Update is called once before Draw so that Update can be used for initialization.
 	if !c.updateCalled && updateCount == 0 {
 		updateCount = 1
@@ -105,8 +125,8 @@ func (c *contextImpl) updateFrameImpl(updateCount int, outsideWidth, outsideHeig
 	}
 
 	// Draw the game.
-	screenScale, offsetX, offsetY := c.screenScaleAndOffsets(deviceScaleFactor)
-	if err := c.game.Draw(screenScale, offsetX, offsetY, graphics().NeedsClearingScreen(), graphics().FramebufferYDirection(), theGlobalState.isScreenClearedEveryFrame()); err != nil {
+	screenScaleX, screenScaleY, offsetX, offsetY := c.screenScaleAndOffsets(deviceScaleFactor)
+	if err := c.game.Draw(screenScaleX, screenScaleY, offsetX, offsetY, graphics().NeedsClearingScreen(), graphics().FramebufferYDirection(), theGlobalState.isScreenClearedEveryFrame()); err != nil {
 		return err
 	}
 
@@ -120,6 +140,53 @@ func (c *contextImpl) updateFrameImpl(updateCount int, outsideWidth, outsideHeig
 	})
 }
 
+// updateLifecycle notifies the game of the changes of the lifecycle states.
+//
+// updateLifecycle must not be called concurrently with the game's Update and Draw.
+func (c *contextImpl) updateLifecycle(suspended, focused bool) {
+	// The focus is lost before the game is suspended, and the focus is gained after the game is resumed.
+	if suspended && !c.suspended {
+		c.updateFocus(focused)
+		c.suspended = true
+		c.game.OnSuspend()
+		return
+	}
+	if !suspended && c.suspended {
+		c.suspended = false
+		c.game.OnResume()
+	}
+	c.updateFocus(focused)
+}
+
+// updateWindowState notifies the game of the changes of the window size and the maximized state.
+// The initial state is not notified.
+//
+// updateWindowState must not be called concurrently with the game's Update and Draw.
+func (c *contextImpl) updateWindowState(width, height int, maximized bool) {
+	if !c.windowStateInitialized {
+		c.windowStateInitialized = true
+		c.windowWidth, c.windowHeight, c.windowMaximized = width, height, maximized
+		return
+	}
+
+	if c.windowMaximized != maximized {
+		c.windowMaximized = maximized
+		c.game.OnWindowMaximizedChanged(maximized)
+	}
+	if c.windowWidth != width || c.windowHeight != height {
+		c.windowWidth, c.windowHeight = width, height
+		c.game.OnWindowResized(width, height)
+	}
+}
+
+func (c *contextImpl) updateFocus(focused bool) {
+	if c.unfocused == !focused {
+		return
+	}
+	c.unfocused = !focused
+	c.game.OnFocusChanged(focused)
+}
+
 func (c *contextImpl) layoutGame(outsideWidth, outsideHeight float64, deviceScaleFactor float64) (int, int) {
 	c.m.Lock()
 	defer c.m.Unlock()
@@ -133,36 +200,63 @@ func (c *contextImpl) layoutGame(outsideWidth, outsideHeight float64, deviceScal
 }
 
 func (c *contextImpl) adjustPosition(x, y float64, deviceScaleFactor float64) (float64, float64) {
-	s, ox, oy := c.screenScaleAndOffsets(deviceScaleFactor)
+	sx, sy, ox, oy := c.screenScaleAndOffsets(deviceScaleFactor)
 	// The scale 0 indicates that the screen is not initialized yet.
 	// As any cursor values don't make sense, just return NaN.
-	if s == 0 {
+	if sx == 0 || sy == 0 {
 		return math.NaN(), math.NaN()
 	}
-	return (x*deviceScaleFactor - ox) / s, (y*deviceScaleFactor - oy) / s
+	return (x*deviceScaleFactor - ox) / sx, (y*deviceScaleFactor - oy) / sy
+}
+
+// unadjustPosition is the inverse of adjustPosition.
+// unadjustPosition converts a position in the game screen to the outside coordinates.
+func (c *contextImpl) unadjustPosition(x, y float64, deviceScaleFactor float64) (float64, float64) {
+	sx, sy, ox, oy := c.screenScaleAndOffsets(deviceScaleFactor)
+	return (x*sx + ox) / deviceScaleFactor, (y*sy + oy) / deviceScaleFactor
 }
 
-func (c *contextImpl) screenScaleAndOffsets(deviceScaleFactor float64) (float64, float64, float64) {
+func (c *contextImpl) screenScaleAndOffsets(deviceScaleFactor float64) (float64, float64, float64, float64) {
 	c.m.Lock()
 	defer c.m.Unlock()
 
 	if c.screenWidth == 0 || c.screenHeight == 0 {
-		return 0, 0, 0
+		return 0, 0, 0, 0
 	}
 
 	scaleX := c.outsideWidth / float64(c.screenWidth) * deviceScaleFactor
 	scaleY := c.outsideHeight / float64(c.screenHeight) * deviceScaleFactor
-	scale := math.Min(scaleX, scaleY)
-	width := float64(c.screenWidth) * scale
-	height := float64(c.screenHeight) * scale
+	mode := theGlobalState.screenScaleMode()
+	switch mode {
+	case ScreenScaleModeFit:
+		scale := math.Min(scaleX, scaleY)
+		scaleX, scaleY = scale, scale
+	case ScreenScaleModeInteger:
+		// Use the largest integer scale that fits the outside, but don't let the screen vanish when the outside
+		// is smaller than the screen.
+		scale := math.Min(scaleX, scaleY)
+		if scale >= 1 {
+			scale = math.Floor(scale)
+		}
+		scaleX, scaleY = scale, scale
+	case ScreenScaleModeStretch:
+		// Use the scales as they are.
+	}
+	width := float64(c.screenWidth) * scaleX
+	height := float64(c.screenHeight) * scaleY
 	x := (c.outsideWidth*deviceScaleFactor - width) / 2
 	y := (c.outsideHeight*deviceScaleFactor - height) / 2
-	return scale, x, y
+	if mode == ScreenScaleModeInteger {
+		// Align the screen with the pixels so that the screen is rendered pixel-perfectly.
+		x, y = math.Floor(x), math.Floor(y)
+	}
+	return scaleX, scaleY, x, y
 }
 
 var theGlobalState = globalState{
 	maxTPS_:                    DefaultTPS,
 	isScreenClearedEveryFrame_: 1,
+	vsyncInterval_:             1,
 }
 
 // globalState represents a global state in this package.
@@ -172,6 +266,10 @@ type globalState struct {
 	fpsMode_                   int32
 	maxTPS_                    int32
 	isScreenClearedEveryFrame_ int32
+	screenScaleMode_           int32
+	isDeterministic_           int32
+	vsyncInterval_             int32
+	preferredFPS_              int32
 }
 
 func (g *globalState) err() error {
@@ -194,8 +292,31 @@ func (g *globalState) setFPSMode(fpsMode FPSModeType) {
 	atomic.StoreInt32(&g.fpsMode_, int32(fpsMode))
 }
 
+func (g *globalState) vsyncInterval() int {
+	return int(atomic.LoadInt32(&g.vsyncInterval_))
+}
+
+func (g *globalState) setVsyncInterval(interval int) {
+	if interval < 1 {
+		panic("ebiten: interval must be >= 1")
+	}
+	atomic.StoreInt32(&g.vsyncInterval_, int32(interval))
+}
+
+func (g *globalState) preferredFPS() int {
+	return int(atomic.LoadInt32(&g.preferredFPS_))
+}
+
+func (g *globalState) setPreferredFPS(fps int) {
+	if fps < 0 {
+		panic("ebiten: fps must be >= 0")
+	}
+	atomic.StoreInt32(&g.preferredFPS_, int32(fps))
+}
+
 func (g *globalState) maxTPS() int {
-	if g.fpsMode() == FPSModeVsyncOffMinimum {
+	// In the deterministic mode, Update is called exactly once per frame regardless of the elapsed time.
+	if g.fpsMode() == FPSModeVsyncOffMinimum || g.isDeterministic() {
 		return clock.SyncWithFPS
 	}
 	return int(atomic.LoadInt32(&g.maxTPS_))
@@ -220,6 +341,26 @@ func (g *globalState) setScreenClearedEveryFrame(cleared bool) {
 	atomic.StoreInt32(&g.isScreenClearedEveryFrame_, v)
 }
 
+func (g *globalState) isDeterministic() bool {
+	return atomic.LoadInt32(&g.isDeterministic_) != 0
+}
+
+func (g *globalState) setDeterministic(deterministic bool) {
+	v := int32(0)
+	if deterministic {
+		v = 1
+	}
+	atomic.StoreInt32(&g.isDeterministic_, v)
+}
+
+func (g *globalState) screenScaleMode() ScreenScaleModeType {
+	return ScreenScaleModeType(atomic.LoadInt32(&g.screenScaleMode_))
+}
+
+func (g *globalState) setScreenScaleMode(mode ScreenScaleModeType) {
+	atomic.StoreInt32(&g.screenScaleMode_, int32(mode))
+}
+
 func SetError(err error) {
 	theGlobalState.setError(err)
 }
@@ -233,6 +374,36 @@ func SetFPSMode(fpsMode FPSModeType) {
 	Get().SetFPSMode(fpsMode)
 }
 
+func VsyncInterval() int {
+	return theGlobalState.vsyncInterval()
+}
+
+func SetVsyncInterval(interval int) {
+	theGlobalState.setVsyncInterval(interval)
+}
+
+func PreferredFPS() int {
+	return theGlobalState.preferredFPS()
+}
+
+func SetPreferredFPS(fps int) {
+	theGlobalState.setPreferredFPS(fps)
+	Get().SetPreferredFPS(fps)
+}
+
+// DeltaTime returns the duration of the current tick.
+func DeltaTime() time.Duration {
+	// In the deterministic mode, the duration must not depend on the actual elapsed time.
+	if theGlobalState.isDeterministic() {
+		tps := int(atomic.LoadInt32(&theGlobalState.maxTPS_))
+		if tps <= 0 {
+			tps = DefaultTPS
+		}
+		return time.Second / time.Duration(tps)
+	}
+	return clock.DeltaTime()
+}
+
 func MaxTPS() int {
 	return theGlobalState.maxTPS()
 }
@@ -248,3 +419,19 @@ func IsScreenClearedEveryFrame() bool {
 func SetScreenClearedEveryFrame(cleared bool) {
 	theGlobalState.setScreenClearedEveryFrame(cleared)
 }
+
+func ScreenScaleMode() ScreenScaleModeType {
+	return theGlobalState.screenScaleMode()
+}
+
+func SetScreenScaleMode(mode ScreenScaleModeType) {
+	theGlobalState.setScreenScaleMode(mode)
+}
+
+func IsDeterministic() bool {
+	return theGlobalState.isDeterministic()
+}
+
+func SetDeterministic(deterministic bool) {
+	theGlobalState.setDeterministic(deterministic)
+}
@@ -0,0 +1,39 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicscommand"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+)
+
+// GPUFrameDuration returns the time spent by GPU for the last measured frame.
+// GPUFrameDuration returns 0 if the current graphics library cannot measure the time.
+func GPUFrameDuration() time.Duration {
+	return graphicscommand.GPUFrameDuration()
+}
+
+// LastFrameStats returns the statistics of the graphics commands executed in the last frame.
+func LastFrameStats() graphicscommand.FrameStats {
+	return graphicscommand.LastFrameStats()
+}
+
+// GraphicsCapabilities returns the capabilities of the graphics device.
+// GraphicsCapabilities returns the zero value before the graphics driver is initialized.
+func GraphicsCapabilities() graphicsdriver.Capabilities {
+	return graphicscommand.Capabilities()
+}
@@ -35,6 +35,7 @@ import "C"
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/metal"
@@ -53,15 +54,30 @@ func supportsMetal() bool {
 	return metal.Get() != nil
 }
 
-var graphicsOnce sync.Once
+var (
+	graphicsOnce sync.Once
+
+	theGraphicsLibrary int32
+)
 
 func graphics() graphicsdriver.Graphics {
 	graphicsOnce.Do(func() {
-		if supportsMetal() {
+		// If Metal is requested but not supported, fall back to OpenGL.
+		if graphicsLibraryToUse() != GraphicsLibraryOpenGL && supportsMetal() {
 			theGraphics = metal.Get()
+			atomic.StoreInt32(&theGraphicsLibrary, int32(GraphicsLibraryMetal))
 			return
 		}
 		theGraphics = opengl.Get()
+		atomic.StoreInt32(&theGraphicsLibrary, int32(GraphicsLibraryOpenGL))
 	})
 	return theGraphics
 }
+
+func isGraphicsLibraryAvailable(library GraphicsLibrary) bool {
+	return library == GraphicsLibraryAuto || library == GraphicsLibraryOpenGL || library == GraphicsLibraryMetal
+}
+
+func activeGraphicsLibrary() GraphicsLibrary {
+	return GraphicsLibrary(atomic.LoadInt32(&theGraphicsLibrary))
+}
@@ -26,3 +26,11 @@ func graphics() graphicsdriver.Graphics {
 	// Metal might not be supported on emulators on Intel machines.
 	return opengl.Get()
 }
+
+func isGraphicsLibraryAvailable(library GraphicsLibrary) bool {
+	return library == GraphicsLibraryAuto || library == GraphicsLibraryOpenGL
+}
+
+func activeGraphicsLibrary() GraphicsLibrary {
+	return GraphicsLibraryOpenGL
+}
@@ -29,3 +29,11 @@ func graphics() graphicsdriver.Graphics {
 	}
 	return g
 }
+
+func isGraphicsLibraryAvailable(library GraphicsLibrary) bool {
+	return library == GraphicsLibraryAuto || library == GraphicsLibraryMetal
+}
+
+func activeGraphicsLibrary() GraphicsLibrary {
+	return GraphicsLibraryMetal
+}
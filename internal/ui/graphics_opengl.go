@@ -25,3 +25,11 @@ import (
 func graphics() graphicsdriver.Graphics {
 	return opengl.Get()
 }
+
+func isGraphicsLibraryAvailable(library GraphicsLibrary) bool {
+	return library == GraphicsLibraryAuto || library == GraphicsLibraryOpenGL
+}
+
+func activeGraphicsLibrary() GraphicsLibrary {
+	return GraphicsLibraryOpenGL
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+type GraphicsLibrary int
+
+const (
+	GraphicsLibraryAuto GraphicsLibrary = iota
+	GraphicsLibraryOpenGL
+	GraphicsLibraryMetal
+)
+
+func (g GraphicsLibrary) String() string {
+	switch g {
+	case GraphicsLibraryAuto:
+		return "Auto"
+	case GraphicsLibraryOpenGL:
+		return "OpenGL"
+	case GraphicsLibraryMetal:
+		return "Metal"
+	}
+	return fmt.Sprintf("GraphicsLibrary(%d)", int(g))
+}
+
+var requestedGraphicsLibrary int32
+
+// SetGraphicsLibrary sets the graphics library to use.
+// SetGraphicsLibrary must be called before the game starts.
+func SetGraphicsLibrary(library GraphicsLibrary) error {
+	if !isGraphicsLibraryAvailable(library) {
+		return fmt.Errorf("ui: the graphics library %s is not available in this environment", library)
+	}
+	atomic.StoreInt32(&requestedGraphicsLibrary, int32(library))
+	return nil
+}
+
+func graphicsLibraryToUse() GraphicsLibrary {
+	return GraphicsLibrary(atomic.LoadInt32(&requestedGraphicsLibrary))
+}
+
+// ActiveGraphicsLibrary returns the graphics library in use.
+// ActiveGraphicsLibrary returns GraphicsLibraryAuto if the graphics library is not determined yet.
+func ActiveGraphicsLibrary() GraphicsLibrary {
+	return activeGraphicsLibrary()
+}
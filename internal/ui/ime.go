@@ -0,0 +1,44 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"image"
+	"math"
+)
+
+// imeState is the state of the input method editor.
+type imeState struct {
+	text       string
+	caret      int
+	candidates []string
+	selected   int
+}
+
+// imeCaretRect converts the IME caret rectangle in the game screen to the outside coordinates, and then to the unit
+// that the OS expects by toOSUnit.
+// The result is rounded outward so that it covers the given rectangle.
+//
+// imeCaretRect returns an empty rectangle if rect is empty.
+func (c *contextImpl) imeCaretRect(rect image.Rectangle, deviceScaleFactor float64, toOSUnit func(float64) float64) image.Rectangle {
+	if rect.Empty() {
+		return image.Rectangle{}
+	}
+	x0, y0 := c.unadjustPosition(float64(rect.Min.X), float64(rect.Min.Y), deviceScaleFactor)
+	x1, y1 := c.unadjustPosition(float64(rect.Max.X), float64(rect.Max.Y), deviceScaleFactor)
+	return image.Rect(
+		int(math.Floor(toOSUnit(x0))), int(math.Floor(toOSUnit(y0))),
+		int(math.Ceil(toOSUnit(x1))), int(math.Ceil(toOSUnit(y1))))
+}
@@ -0,0 +1,111 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"image"
+	"testing"
+)
+
+func TestIMECaretRect(t *testing.T) {
+	identity := func(v float64) float64 {
+		return v
+	}
+
+	cases := []struct {
+		name              string
+		outsideWidth      float64
+		outsideHeight     float64
+		screenWidth       int
+		screenHeight      int
+		deviceScaleFactor float64
+		toOSUnit          func(float64) float64
+		rect              image.Rectangle
+		want              image.Rectangle
+	}{
+		{
+			name:              "scaled",
+			outsideWidth:      640,
+			outsideHeight:     480,
+			screenWidth:       320,
+			screenHeight:      240,
+			deviceScaleFactor: 2,
+			toOSUnit:          identity,
+			rect:              image.Rect(10, 20, 30, 40),
+			want:              image.Rect(20, 40, 60, 80),
+		},
+		{
+			name:              "letterboxed",
+			outsideWidth:      800,
+			outsideHeight:     480,
+			screenWidth:       320,
+			screenHeight:      240,
+			deviceScaleFactor: 2,
+			toOSUnit:          identity,
+			rect:              image.Rect(10, 20, 30, 40),
+			want:              image.Rect(100, 40, 140, 80),
+		},
+		{
+			name:              "rounded outward",
+			outsideWidth:      100,
+			outsideHeight:     100,
+			screenWidth:       30,
+			screenHeight:      30,
+			deviceScaleFactor: 1,
+			toOSUnit:          identity,
+			rect:              image.Rect(1, 1, 2, 2),
+			want:              image.Rect(3, 3, 7, 7),
+		},
+		{
+			name:              "os unit",
+			outsideWidth:      640,
+			outsideHeight:     480,
+			screenWidth:       320,
+			screenHeight:      240,
+			deviceScaleFactor: 2,
+			toOSUnit: func(v float64) float64 {
+				return v * 2
+			},
+			rect: image.Rect(10, 20, 30, 40),
+			want: image.Rect(40, 80, 120, 160),
+		},
+		{
+			name:              "empty",
+			outsideWidth:      640,
+			outsideHeight:     480,
+			screenWidth:       320,
+			screenHeight:      240,
+			deviceScaleFactor: 2,
+			toOSUnit:          identity,
+			rect:              image.Rectangle{},
+			want:              image.Rectangle{},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			ctx := &contextImpl{
+				outsideWidth:  c.outsideWidth,
+				outsideHeight: c.outsideHeight,
+				screenWidth:   c.screenWidth,
+				screenHeight:  c.screenHeight,
+			}
+			if got := ctx.imeCaretRect(c.rect, c.deviceScaleFactor, c.toOSUnit); got != c.want {
+				t.Errorf("got: %v, want: %v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !ebitencbackend
+// +build !ebitencbackend
+
+package ui
+
+import (
+	"image"
+	"unicode/utf16"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	gcsCompStr   = 0x0008
+	gcsCursorPos = 0x0080
+
+	cfsPoint   = 0x0002
+	cfsExclude = 0x0080
+)
+
+type compositionForm struct {
+	dwStyle      uint32
+	ptCurrentPos point
+	rcArea       rect
+}
+
+type candidateForm struct {
+	dwIndex      uint32
+	dwStyle      uint32
+	ptCurrentPos point
+	rcArea       rect
+}
+
+// candidateList is the header of CANDIDATELIST. dwOffset follows this header.
+type candidateList struct {
+	dwSize      uint32
+	dwStyle     uint32
+	dwCount     uint32
+	dwSelection uint32
+	dwPageStart uint32
+	dwPageSize  uint32
+}
+
+var (
+	imm32 = windows.NewLazySystemDLL("imm32.dll")
+
+	procImmGetContext            = imm32.NewProc("ImmGetContext")
+	procImmReleaseContext        = imm32.NewProc("ImmReleaseContext")
+	procImmGetCompositionStringW = imm32.NewProc("ImmGetCompositionStringW")
+	procImmGetCandidateListW     = imm32.NewProc("ImmGetCandidateListW")
+	procImmSetCompositionWindow  = imm32.NewProc("ImmSetCompositionWindow")
+	procImmSetCandidateWindow    = imm32.NewProc("ImmSetCandidateWindow")
+)
+
+func immGetContext(hwnd windows.HWND) uintptr {
+	r, _, _ := procImmGetContext.Call(uintptr(hwnd))
+	return r
+}
+
+func immReleaseContext(hwnd windows.HWND, himc uintptr) {
+	procImmReleaseContext.Call(uintptr(hwnd), himc)
+}
+
+func immGetCompositionString(himc uintptr, index uint32) []uint16 {
+	// The returned value is the size in bytes. A negative value is an error.
+	r, _, _ := procImmGetCompositionStringW.Call(himc, uintptr(index), 0, 0)
+	n := int32(r)
+	if n <= 0 {
+		return nil
+	}
+	buf := make([]uint16, n/2)
+	procImmGetCompositionStringW.Call(himc, uintptr(index), uintptr(unsafe.Pointer(&buf[0])), uintptr(n))
+	return buf
+}
+
+func immGetCompositionCursorPos(himc uintptr) int {
+	r, _, _ := procImmGetCompositionStringW.Call(himc, gcsCursorPos, 0, 0)
+	if int32(r) < 0 {
+		return 0
+	}
+	return int(int32(r))
+}
+
+func immGetCandidateList(himc uintptr) ([]string, int) {
+	r, _, _ := procImmGetCandidateListW.Call(himc, 0, 0, 0)
+	if r == 0 {
+		return nil, -1
+	}
+	buf := make([]byte, r)
+	if r, _, _ := procImmGetCandidateListW.Call(himc, 0, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf))); r == 0 {
+		return nil, -1
+	}
+
+	l := (*candidateList)(unsafe.Pointer(&buf[0]))
+	offsets := buf[unsafe.Sizeof(*l):]
+	candidates := make([]string, 0, l.dwCount)
+	for i := 0; i < int(l.dwCount); i++ {
+		offset := *(*uint32)(unsafe.Pointer(&offsets[4*i]))
+		var str []uint16
+		for j := int(offset); j+1 < len(buf); j += 2 {
+			c := *(*uint16)(unsafe.Pointer(&buf[j]))
+			if c == 0 {
+				break
+			}
+			str = append(str, c)
+		}
+		candidates = append(candidates, string(utf16.Decode(str)))
+	}
+	return candidates, int(l.dwSelection)
+}
+
+func immSetCaretRect(himc uintptr, caret image.Rectangle) {
+	cf := compositionForm{
+		dwStyle: cfsPoint,
+		ptCurrentPos: point{
+			x: int32(caret.Min.X),
+			y: int32(caret.Min.Y),
+		},
+	}
+	procImmSetCompositionWindow.Call(himc, uintptr(unsafe.Pointer(&cf)))
+
+	// Let the candidate window avoid the caret.
+	cdf := candidateForm{
+		dwStyle: cfsExclude,
+		ptCurrentPos: point{
+			x: int32(caret.Min.X),
+			y: int32(caret.Max.Y),
+		},
+		rcArea: rect{
+			left:   int32(caret.Min.X),
+			top:    int32(caret.Min.Y),
+			right:  int32(caret.Max.X),
+			bottom: int32(caret.Max.Y),
+		},
+	}
+	procImmSetCandidateWindow.Call(himc, uintptr(unsafe.Pointer(&cdf)))
+}
+
+// updateIMEByOS must be called from the main thread.
+func (u *UserInterface) updateIMEByOS(caret image.Rectangle) imeState {
+	state := imeState{selected: -1}
+
+	if err := imm32.Load(); err != nil {
+		return state
+	}
+
+	hwnd := windows.HWND(u.window.GetWin32Window())
+	himc := immGetContext(hwnd)
+	if himc == 0 {
+		return state
+	}
+	defer immReleaseContext(hwnd, himc)
+
+	if !caret.Empty() {
+		immSetCaretRect(himc, caret)
+	}
+
+	str := immGetCompositionString(himc, gcsCompStr)
+	if len(str) == 0 {
+		return state
+	}
+	state.text = string(utf16.Decode(str))
+
+	// The cursor position is in UTF-16 code units. Convert this to runes.
+	if c := immGetCompositionCursorPos(himc); c <= len(str) {
+		state.caret = len(utf16.Decode(str[:c]))
+	}
+
+	state.candidates, state.selected = immGetCandidateList(himc)
+	return state
+}
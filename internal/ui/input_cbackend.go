@@ -18,6 +18,7 @@
 package ui
 
 import (
+	"image"
 	"sync"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/cbackend"
@@ -51,6 +52,21 @@ func (i *Input) AppendInputChars(runes []rune) []rune {
 	return nil
 }
 
+func (i *Input) IMEComposition() (text string, caret int) {
+	return "", 0
+}
+
+func (i *Input) AppendIMECandidates(candidates []string) ([]string, int) {
+	return candidates, -1
+}
+
+func (i *Input) SetIMECaretRect(rect image.Rectangle) {
+}
+
+func (i *Input) AppendDroppedFiles(files []DroppedFile) []DroppedFile {
+	return nil
+}
+
 func (i *Input) AppendTouchIDs(touchIDs []TouchID) []TouchID {
 	i.m.Lock()
 	defer i.m.Unlock()
@@ -18,7 +18,11 @@
 package ui
 
 import (
+	"image"
+	"io"
 	"math"
+	"os"
+	"path/filepath"
 	"sync"
 	"unicode"
 
@@ -36,6 +40,9 @@ type Input struct {
 	cursorY            int
 	touches            map[TouchID]pos // TODO: Implement this (#417)
 	runeBuffer         []rune
+	droppedFiles       []DroppedFile
+	imeCaretRect       image.Rectangle
+	ime                imeState
 	ui                 *UserInterface
 }
 
@@ -92,6 +99,42 @@ func (i *Input) AppendInputChars(runes []rune) []rune {
 	return append(runes, i.runeBuffer...)
 }
 
+func (i *Input) AppendDroppedFiles(files []DroppedFile) []DroppedFile {
+	if !i.ui.isRunning() {
+		return nil
+	}
+
+	i.ui.m.RLock()
+	defer i.ui.m.RUnlock()
+	return append(files, i.droppedFiles...)
+}
+
+func (i *Input) IMEComposition() (text string, caret int) {
+	if !i.ui.isRunning() {
+		return "", 0
+	}
+
+	i.ui.m.RLock()
+	defer i.ui.m.RUnlock()
+	return i.ime.text, i.ime.caret
+}
+
+func (i *Input) AppendIMECandidates(candidates []string) ([]string, int) {
+	if !i.ui.isRunning() {
+		return candidates, -1
+	}
+
+	i.ui.m.RLock()
+	defer i.ui.m.RUnlock()
+	return append(candidates, i.ime.candidates...), i.ime.selected
+}
+
+func (i *Input) SetIMECaretRect(rect image.Rectangle) {
+	i.ui.m.Lock()
+	defer i.ui.m.Unlock()
+	i.imeCaretRect = rect
+}
+
 func (i *Input) resetForTick() {
 	if !i.ui.isRunning() {
 		return
@@ -100,6 +143,7 @@ func (i *Input) resetForTick() {
 	i.ui.m.Lock()
 	defer i.ui.m.Unlock()
 	i.runeBuffer = i.runeBuffer[:0]
+	i.droppedFiles = i.droppedFiles[:0]
 	i.scrollX, i.scrollY = 0, 0
 }
 
@@ -177,6 +221,21 @@ func (i *Input) update(window *glfw.Window, context *contextImpl) error {
 			i.scrollX = xoff
 			i.scrollY = yoff
 		}))
+		window.SetDropCallback(glfw.ToDropCallback(func(w *glfw.Window, names []string) {
+			// As this function is called from GLFW callbacks, the current thread is main.
+			i.ui.m.Lock()
+			defer i.ui.m.Unlock()
+			for _, name := range names {
+				path := name
+				i.droppedFiles = append(i.droppedFiles, DroppedFile{
+					Name: filepath.Base(path),
+					Path: path,
+					Open: func() (io.ReadCloser, error) {
+						return os.Open(path)
+					},
+				})
+			}
+		}))
 	})
 	if i.keyPressed == nil {
 		i.keyPressed = map[glfw.Key]bool{}
@@ -203,6 +262,12 @@ func (i *Input) update(window *glfw.Window, context *contextImpl) error {
 		i.cursorX, i.cursorY = int(cx), int(cy)
 	}
 
+	// Convert the caret rectangle to the window's pixels, as the OS expects.
+	caret := context.imeCaretRect(i.imeCaretRect, s, func(v float64) float64 {
+		return i.ui.dipToGLFWPixel(v, m)
+	})
+	i.ime = i.ui.updateIMEByOS(caret)
+
 	gamepad.Update()
 	return nil
 }
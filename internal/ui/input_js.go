@@ -15,8 +15,14 @@
 package ui
 
 import (
+	"bytes"
+	"image"
+	"io"
+	"io/ioutil"
+	"strconv"
 	"syscall/js"
 	"unicode"
+	"unicode/utf8"
 )
 
 var (
@@ -30,6 +36,11 @@ var (
 	stringTouchstart = js.ValueOf("touchstart")
 	stringTouchend   = js.ValueOf("touchend")
 	stringTouchmove  = js.ValueOf("touchmove")
+
+	stringCompositionstart  = js.ValueOf("compositionstart")
+	stringCompositionupdate = js.ValueOf("compositionupdate")
+	stringCompositionend    = js.ValueOf("compositionend")
+	stringInput             = js.ValueOf("input")
 )
 
 var jsKeys []js.Value
@@ -68,6 +79,10 @@ type Input struct {
 	wheelY             float64
 	touches            map[TouchID]pos
 	runeBuffer         []rune
+	droppedFiles       []DroppedFile
+	imeCaretRect       image.Rectangle
+	imeTextAreaRect    image.Rectangle
+	ime                imeState
 	ui                 *UserInterface
 }
 
@@ -101,8 +116,26 @@ func (i *Input) AppendInputChars(runes []rune) []rune {
 	return append(runes, i.runeBuffer...)
 }
 
+func (i *Input) IMEComposition() (text string, caret int) {
+	return i.ime.text, i.ime.caret
+}
+
+func (i *Input) AppendIMECandidates(candidates []string) ([]string, int) {
+	// Browsers don't expose the IME candidates.
+	return candidates, -1
+}
+
+func (i *Input) SetIMECaretRect(rect image.Rectangle) {
+	i.imeCaretRect = rect
+}
+
+func (i *Input) AppendDroppedFiles(files []DroppedFile) []DroppedFile {
+	return append(files, i.droppedFiles...)
+}
+
 func (i *Input) resetForTick() {
 	i.runeBuffer = nil
+	i.droppedFiles = nil
 	i.wheelX = 0
 	i.wheelY = 0
 }
@@ -254,6 +287,121 @@ func (i *Input) updateFromEvent(e js.Value) {
 	i.ui.forceUpdateOnMinimumFPSMode()
 }
 
+// updateIMEFromEvent updates the IME composition by a composition event or an input event of the IME text area.
+func (i *Input) updateIMEFromEvent(e js.Value) {
+	switch t := e.Get("type"); {
+	case t.Equal(stringCompositionstart) || t.Equal(stringCompositionupdate):
+		i.ime.text = e.Get("data").String()
+		i.ime.caret = utf8.RuneCountInString(i.ime.text)
+	case t.Equal(stringInput):
+		// The caret in the composition is available only as the selection of the text area.
+		// The text area has only the composition text, as the other inputs are prevented at keypress events.
+		if !e.Get("isComposing").Truthy() {
+			return
+		}
+		i.ime.caret = utf16OffsetToRuneOffset(imeTextArea.Get("value").String(), imeTextArea.Get("selectionStart").Int())
+	case t.Equal(stringCompositionend):
+		for _, r := range e.Get("data").String() {
+			if unicode.IsPrint(r) {
+				i.runeBuffer = append(i.runeBuffer, r)
+			}
+		}
+		i.ime = imeState{}
+		imeTextArea.Set("value", "")
+	}
+
+	i.ui.forceUpdateOnMinimumFPSMode()
+}
+
+// utf16OffsetToRuneOffset converts the offset in UTF-16 code units of str to the offset in runes.
+func utf16OffsetToRuneOffset(str string, offset int) int {
+	var n, o int
+	for _, r := range str {
+		if o >= offset {
+			break
+		}
+		// A rune out of the BMP is a surrogate pair in UTF-16.
+		if r >= 0x10000 {
+			o += 2
+		} else {
+			o++
+		}
+		n++
+	}
+	return n
+}
+
+// updateIME moves the IME text area to the IME caret rectangle, and moves the focus between the canvas and the
+// IME text area depending on whether the rectangle is set.
+func (i *Input) updateIME() {
+	if !imeTextArea.Truthy() {
+		return
+	}
+
+	rect := i.ui.context.imeCaretRect(i.imeCaretRect, i.ui.DeviceScaleFactor(), func(v float64) float64 {
+		return v
+	})
+	active := document.Get("activeElement")
+	if rect.Empty() {
+		if active.Equal(imeTextArea) {
+			canvas.Call("focus")
+		}
+		i.ime = imeState{}
+		return
+	}
+
+	if i.imeTextAreaRect != rect {
+		style := imeTextArea.Get("style")
+		style.Set("left", strconv.Itoa(rect.Min.X)+"px")
+		style.Set("top", strconv.Itoa(rect.Min.Y)+"px")
+		style.Set("width", strconv.Itoa(rect.Dx())+"px")
+		style.Set("height", strconv.Itoa(rect.Dy())+"px")
+		i.imeTextAreaRect = rect
+	}
+	// Don't steal the focus from the other elements than the canvas.
+	if active.Equal(canvas) {
+		imeTextArea.Call("focus")
+	}
+}
+
+// updateDroppedFilesFromEvent reads the files of a drop event.
+// As the contents are read asynchronously, the files are reported at a later tick than the event.
+func (i *Input) updateDroppedFilesFromEvent(e js.Value) {
+	dt := e.Get("dataTransfer")
+	if !dt.Truthy() {
+		return
+	}
+	files := dt.Get("files")
+	for idx := 0; idx < files.Length(); idx++ {
+		f := files.Index(idx)
+		name := f.Get("name").String()
+
+		var then, catch js.Func
+		release := func() {
+			then.Release()
+			catch.Release()
+		}
+		then = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			defer release()
+			u8 := js.Global().Get("Uint8Array").New(args[0])
+			bs := make([]byte, u8.Length())
+			js.CopyBytesToGo(bs, u8)
+			i.droppedFiles = append(i.droppedFiles, DroppedFile{
+				Name: name,
+				Open: func() (io.ReadCloser, error) {
+					return ioutil.NopCloser(bytes.NewReader(bs)), nil
+				},
+			})
+			return nil
+		})
+		catch = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			defer release()
+			return nil
+		})
+		f.Call("arrayBuffer").Call("then", then).Call("catch", catch)
+	}
+}
+
 func (i *Input) setMouseCursorFromEvent(e js.Value) {
 	if i.ui.cursorMode == CursorModeCaptured {
 		x, y := e.Get("clientX").Int(), e.Get("clientY").Int()
@@ -18,6 +18,10 @@
 
 package ui
 
+import (
+	"image"
+)
+
 type Input struct {
 	keys    map[Key]struct{}
 	runes   []rune
@@ -57,6 +61,21 @@ func (i *Input) AppendInputChars(runes []rune) []rune {
 	return append(runes, i.runes...)
 }
 
+func (i *Input) IMEComposition() (text string, caret int) {
+	return "", 0
+}
+
+func (i *Input) AppendIMECandidates(candidates []string) ([]string, int) {
+	return candidates, -1
+}
+
+func (i *Input) SetIMECaretRect(rect image.Rectangle) {
+}
+
+func (i *Input) AppendDroppedFiles(files []DroppedFile) []DroppedFile {
+	return nil
+}
+
 func (i *Input) IsKeyPressed(key Key) bool {
 	i.ui.m.RLock()
 	defer i.ui.m.RUnlock()
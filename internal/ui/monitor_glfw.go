@@ -0,0 +1,147 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !android && !ios && !js && !ebitencbackend
+// +build !android,!ios,!js,!ebitencbackend
+
+package ui
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/glfw"
+)
+
+// Monitor represents a monitor.
+//
+// The values are recorded when the monitor is detected, and are updated when the monitor configuration changes.
+type Monitor struct {
+	m *glfw.Monitor
+
+	name              string
+	bounds            image.Rectangle
+	deviceScaleFactor float64
+	refreshRate       int
+}
+
+// newMonitor creates a new Monitor.
+//
+// newMonitor must be called from the main thread.
+func newMonitor(m *glfw.Monitor, vm *glfw.VidMode) *Monitor {
+	x, y := m.GetPos()
+	// The position is in the virtual screen coordinate, which is not precise when the monitors have different
+	// scales. This is similar to the window position.
+	x = int(theUI.dipFromGLFWPixel(float64(x), m))
+	y = int(theUI.dipFromGLFWPixel(float64(y), m))
+	var w, h, r int
+	if vm != nil {
+		w = int(theUI.dipFromGLFWMonitorPixel(float64(vm.Width), m))
+		h = int(theUI.dipFromGLFWMonitorPixel(float64(vm.Height), m))
+		r = vm.RefreshRate
+	}
+	return &Monitor{
+		m:                 m,
+		name:              m.GetName(),
+		bounds:            image.Rect(x, y, x+w, y+h),
+		deviceScaleFactor: theUI.deviceScaleFactor(m),
+		refreshRate:       r,
+	}
+}
+
+// Name returns the human-readable name of the monitor.
+func (m *Monitor) Name() string {
+	return m.name
+}
+
+// Bounds returns the monitor's bounds in device-independent pixels.
+func (m *Monitor) Bounds() image.Rectangle {
+	return m.bounds
+}
+
+// DeviceScaleFactor returns the device scale factor of the monitor.
+func (m *Monitor) DeviceScaleFactor() float64 {
+	return m.deviceScaleFactor
+}
+
+// RefreshRate returns the refresh rate of the monitor in Hz.
+// RefreshRate returns 0 when the refresh rate is unknown.
+func (m *Monitor) RefreshRate() int {
+	return m.refreshRate
+}
+
+// publicMonitor returns the Monitor corresponding to the given GLFW monitor, or nil if not found.
+//
+// publicMonitor must be called from the main thread.
+func publicMonitor(m *glfw.Monitor) *Monitor {
+	if m == nil {
+		return nil
+	}
+	x, y := m.GetPos()
+	for _, mon := range ensureMonitors() {
+		if mon.x == x && mon.y == y {
+			return mon.monitor
+		}
+	}
+	return nil
+}
+
+func (u *UserInterface) setInitMonitor(m *Monitor) {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	u.initMonitor = m.m
+	u.initFullscreenWidthInDIP = m.bounds.Dx()
+	u.initFullscreenHeightInDIP = m.bounds.Dy()
+}
+
+// setWindowMonitor moves the window to the given monitor.
+// If the window is in fullscreen, the window becomes fullscreen on the given monitor.
+//
+// setWindowMonitor must be called from the main thread.
+func (u *UserInterface) setWindowMonitor(m *Monitor) {
+	if publicMonitor(u.currentMonitor()) == m {
+		return
+	}
+
+	fullscreen := u.isFullscreen()
+	w, h := u.windowWidthInDIP, u.windowHeightInDIP
+	if fullscreen {
+		u.setWindowSizeInDIP(w, h, false)
+	}
+
+	// Put the window at the same place as the initial window position.
+	x := (m.bounds.Dx() - w) / 2
+	y := (m.bounds.Dy() - h) / 3
+	u.setWindowPositionInDIP(x, y, m.m)
+
+	if fullscreen {
+		u.setWindowSizeInDIP(w, h, true)
+	}
+}
+
+func (u *UserInterface) AppendMonitors(monitors []*Monitor) []*Monitor {
+	if !u.isRunning() {
+		for _, m := range ensureMonitors() {
+			monitors = append(monitors, m.monitor)
+		}
+		return monitors
+	}
+
+	u.t.Call(func() {
+		for _, m := range ensureMonitors() {
+			monitors = append(monitors, m.monitor)
+		}
+	})
+	return monitors
+}
@@ -0,0 +1,47 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build android || ios || js || ebitencbackend
+// +build android ios js ebitencbackend
+
+package ui
+
+import (
+	"image"
+)
+
+// Monitor represents a monitor.
+//
+// Monitors are not available on this environment.
+type Monitor struct{}
+
+func (*Monitor) Name() string {
+	return ""
+}
+
+func (*Monitor) Bounds() image.Rectangle {
+	return image.Rectangle{}
+}
+
+func (*Monitor) DeviceScaleFactor() float64 {
+	return 0
+}
+
+func (*Monitor) RefreshRate() int {
+	return 0
+}
+
+func (u *UserInterface) AppendMonitors(monitors []*Monitor) []*Monitor {
+	return monitors
+}
@@ -0,0 +1,198 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !ebitencbackend
+// +build !ebitencbackend
+
+package ui
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	tbpfNoProgress    = 0x0
+	tbpfIndeterminate = 0x1
+	tbpfNormal        = 0x2
+	tbpfError         = 0x4
+	tbpfPaused        = 0x8
+
+	// taskbarProgressTotal is the total value of the progress passed to ITaskbarList3::SetProgressValue.
+	taskbarProgressTotal = 10000
+)
+
+var (
+	clsidTaskbarList = windows.GUID{
+		Data1: 0x56fdf344,
+		Data2: 0xfd6d,
+		Data3: 0x11d0,
+		Data4: [...]byte{0x95, 0x8a, 0x00, 0x60, 0x97, 0xc9, 0xa0, 0x90},
+	}
+	iidITaskbarList3 = windows.GUID{
+		Data1: 0xea1afb91,
+		Data2: 0x9e28,
+		Data3: 0x4b86,
+		Data4: [...]byte{0x90, 0xe9, 0x9e, 0x9f, 0x8a, 0x5e, 0xef, 0xaf},
+	}
+)
+
+var (
+	ole32 = windows.NewLazySystemDLL("ole32.dll")
+
+	procCoCreateInstance = ole32.NewProc("CoCreateInstance")
+)
+
+func coCreateInstance(clsid *windows.GUID, clsContext uint32, iid *windows.GUID) (unsafe.Pointer, error) {
+	var v unsafe.Pointer
+	r, _, _ := procCoCreateInstance.Call(uintptr(unsafe.Pointer(clsid)), 0, uintptr(clsContext), uintptr(unsafe.Pointer(iid)), uintptr(unsafe.Pointer(&v)))
+	if uint32(r) != uint32(windows.S_OK) {
+		return nil, fmt.Errorf("ui: CoCreateInstance failed: HRESULT(%d)", uint32(r))
+	}
+	return v, nil
+}
+
+type iTaskbarList3 struct {
+	vtbl *iTaskbarList3Vtbl
+}
+
+// iTaskbarList3Vtbl is the virtual function table of ITaskbarList3.
+// The functions after SetProgressState are omitted as they are not used.
+type iTaskbarList3Vtbl struct {
+	QueryInterface       uintptr
+	AddRef               uintptr
+	Release              uintptr
+	HrInit               uintptr
+	AddTab               uintptr
+	DeleteTab            uintptr
+	ActivateTab          uintptr
+	SetActiveAlt         uintptr
+	MarkFullscreenWindow uintptr
+	SetProgressValue     uintptr
+	SetProgressState     uintptr
+}
+
+func (i *iTaskbarList3) HrInit() error {
+	r, _, _ := syscall.Syscall(i.vtbl.HrInit, 1, uintptr(unsafe.Pointer(i)), 0, 0)
+	if uint32(r) != uint32(windows.S_OK) {
+		return fmt.Errorf("ui: ITaskbarList3::HrInit failed: HRESULT(%d)", uint32(r))
+	}
+	return nil
+}
+
+func (i *iTaskbarList3) Release() {
+	syscall.Syscall(i.vtbl.Release, 1, uintptr(unsafe.Pointer(i)), 0, 0)
+}
+
+func (i *iTaskbarList3) SetProgressValue(hwnd windows.HWND, completed, total uint64) error {
+	var r uintptr
+	if unsafe.Sizeof(uintptr(0)) == 4 {
+		// On 32bit machines, a 64bit argument is passed as two 32bit values.
+		r, _, _ = syscall.Syscall6(i.vtbl.SetProgressValue, 6, uintptr(unsafe.Pointer(i)), uintptr(hwnd),
+			uintptr(uint32(completed)), uintptr(uint32(completed>>32)), uintptr(uint32(total)), uintptr(uint32(total>>32)))
+	} else {
+		r, _, _ = syscall.Syscall6(i.vtbl.SetProgressValue, 4, uintptr(unsafe.Pointer(i)), uintptr(hwnd), uintptr(completed), uintptr(total), 0, 0)
+	}
+	if uint32(r) != uint32(windows.S_OK) {
+		return fmt.Errorf("ui: ITaskbarList3::SetProgressValue failed: HRESULT(%d)", uint32(r))
+	}
+	return nil
+}
+
+func (i *iTaskbarList3) SetProgressState(hwnd windows.HWND, flags uint32) error {
+	r, _, _ := syscall.Syscall(i.vtbl.SetProgressState, 3, uintptr(unsafe.Pointer(i)), uintptr(hwnd), uintptr(flags))
+	if uint32(r) != uint32(windows.S_OK) {
+		return fmt.Errorf("ui: ITaskbarList3::SetProgressState failed: HRESULT(%d)", uint32(r))
+	}
+	return nil
+}
+
+var (
+	// theTaskbarList is accessed only from the main thread.
+	theTaskbarList *iTaskbarList3
+
+	// taskbarListUnavailable reports whether creating theTaskbarList failed.
+	taskbarListUnavailable bool
+)
+
+// ensureTaskbarList must be called from the main thread.
+func ensureTaskbarList() (*iTaskbarList3, error) {
+	if theTaskbarList != nil {
+		return theTaskbarList, nil
+	}
+
+	// COM might be already initialized in a different mode, and then CoInitializeEx returns an error.
+	// Even in this case, COM is available on this thread.
+	_ = windows.CoInitializeEx(0, windows.COINIT_APARTMENTTHREADED)
+
+	p, err := coCreateInstance(&clsidTaskbarList, windows.CLSCTX_INPROC_SERVER, &iidITaskbarList3)
+	if err != nil {
+		return nil, err
+	}
+	t := (*iTaskbarList3)(p)
+	if err := t.HrInit(); err != nil {
+		t.Release()
+		return nil, err
+	}
+	theTaskbarList = t
+	return t, nil
+}
+
+// setTaskbarProgressByOS must be called from the main thread.
+func (u *UserInterface) setTaskbarProgressByOS(state TaskbarProgressState, progress float64) {
+	if taskbarListUnavailable {
+		return
+	}
+	t, err := ensureTaskbarList()
+	if err != nil {
+		// The taskbar is not available, e.g., on Windows Server Core. Ignore the error.
+		taskbarListUnavailable = true
+		return
+	}
+
+	var flags uint32
+	switch state {
+	case TaskbarProgressStateNone:
+		flags = tbpfNoProgress
+	case TaskbarProgressStateIndeterminate:
+		flags = tbpfIndeterminate
+	case TaskbarProgressStateNormal:
+		flags = tbpfNormal
+	case TaskbarProgressStateError:
+		flags = tbpfError
+	case TaskbarProgressStatePaused:
+		flags = tbpfPaused
+	default:
+		panic(fmt.Sprintf("ui: invalid taskbar progress state: %d", state))
+	}
+
+	hwnd := windows.HWND(u.window.GetWin32Window())
+	if err := t.SetProgressState(hwnd, flags); err != nil {
+		return
+	}
+	if flags == tbpfNoProgress || flags == tbpfIndeterminate {
+		return
+	}
+
+	if progress < 0 {
+		progress = 0
+	}
+	if progress > 1 {
+		progress = 1
+	}
+	_ = t.SetProgressValue(hwnd, uint64(progress*taskbarProgressTotal), taskbarProgressTotal)
+}
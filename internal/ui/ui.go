@@ -41,6 +41,14 @@ const (
 	FPSModeVsyncOffMinimum
 )
 
+type ScreenScaleModeType int
+
+const (
+	ScreenScaleModeFit ScreenScaleModeType = iota
+	ScreenScaleModeInteger
+	ScreenScaleModeStretch
+)
+
 type CursorMode int
 
 const (
@@ -67,3 +75,13 @@ const (
 	WindowResizingModeOnlyFullscreenEnabled
 	WindowResizingModeEnabled
 )
+
+type TaskbarProgressState int
+
+const (
+	TaskbarProgressStateNone TaskbarProgressState = iota
+	TaskbarProgressStateIndeterminate
+	TaskbarProgressStateNormal
+	TaskbarProgressStateError
+	TaskbarProgressStatePaused
+)
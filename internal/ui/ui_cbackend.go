@@ -18,6 +18,7 @@
 package ui
 
 import (
+	"image"
 	"runtime"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/cbackend"
@@ -85,6 +86,9 @@ func (*UserInterface) CursorShape() CursorShape {
 func (*UserInterface) SetCursorShape(shape CursorShape) {
 }
 
+func (*UserInterface) SetCursorImage(img image.Image, hotspotX, hotspotY int) {
+}
+
 func (*UserInterface) IsFullscreen() bool {
 	return false
 }
@@ -92,6 +96,10 @@ func (*UserInterface) IsFullscreen() bool {
 func (*UserInterface) SetFullscreen(fullscreen bool) {
 }
 
+func (*UserInterface) NativeWindow() uintptr {
+	return 0
+}
+
 func (*UserInterface) IsRunnableOnUnfocused() bool {
 	return false
 }
@@ -102,6 +110,9 @@ func (*UserInterface) SetRunnableOnUnfocused(runnableOnUnfocused bool) {
 func (*UserInterface) SetFPSMode(mode FPSModeType) {
 }
 
+func (*UserInterface) SetPreferredFPS(fps int) {
+}
+
 func (*UserInterface) ScheduleFrame() {
 }
 
@@ -112,9 +123,18 @@ func (*UserInterface) IsScreenTransparent() bool {
 func (*UserInterface) SetScreenTransparent(transparent bool) {
 }
 
+func (*UserInterface) IsSystemDarkMode() bool {
+	return false
+}
+
 func (*UserInterface) SetInitFocused(focused bool) {
 }
 
+func (*UserInterface) SetHeadless(headless bool) error {
+	// The C backend doesn't have a window. The game always runs as if it were headless.
+	return nil
+}
+
 func (*UserInterface) Input() *Input {
 	return &theUserInterface.input
 }
@@ -61,17 +61,29 @@ type UserInterface struct {
 	maxWindowWidthInDIP  int
 	maxWindowHeightInDIP int
 
+	// The aspect ratio of the window. If either is 0, the aspect ratio is not locked.
+	windowAspectRatioNumer int
+	windowAspectRatioDenom int
+
 	running              uint32
 	origPosX             int
 	origPosY             int
 	runnableOnUnfocused  bool
 	fpsMode              FPSModeType
 	iconImages           []image.Image
+	iconImagesUpdated    bool
 	cursorShape          CursorShape
+	cursorImage          image.Image
+	cursorHotspotX       int
+	cursorHotspotY       int
 	windowClosingHandled bool
 	windowBeingClosed    bool
 	windowResizingMode   WindowResizingMode
 
+	// customCursor is the cursor created from cursorImage.
+	// customCursor must be accessed from the main thread.
+	customCursor *glfw.Cursor
+
 	// setSizeCallbackEnabled must be accessed from the main thread.
 	setSizeCallbackEnabled bool
 
@@ -98,6 +110,9 @@ type UserInterface struct {
 	initScreenTransparent    bool
 	initFocused              bool
 
+	// headless is not changed after the main loop starts.
+	headless bool
+
 	fpsModeInited bool
 
 	input   Input
@@ -105,6 +120,7 @@ type UserInterface struct {
 
 	sizeCallback                   glfw.SizeCallback
 	closeCallback                  glfw.CloseCallback
+	contentScaleCallback           glfw.ContentScaleCallback
 	framebufferSizeCallback        glfw.FramebufferSizeCallback
 	defaultFramebufferSizeCallback glfw.FramebufferSizeCallback
 	framebufferSizeCallbackCh      chan struct{}
@@ -202,6 +218,9 @@ type monitor struct {
 	// Pos of monitor in virtual coords
 	x int
 	y int
+
+	// monitor is the monitor exposed to the outside of this package.
+	monitor *Monitor
 }
 
 // monitors is the monitor list cache for desktop glfw compile targets.
@@ -213,18 +232,21 @@ var monitors []*monitor
 
 func updateMonitors() {
 	monitors = nil
+	clearVideoModeScaleCache()
+	devicescale.ClearCache()
+
 	ms := glfw.GetMonitors()
 	for _, m := range ms {
 		x, y := m.GetPos()
+		vm := m.GetVideoMode()
 		monitors = append(monitors, &monitor{
-			m:  m,
-			vm: m.GetVideoMode(),
-			x:  x,
-			y:  y,
+			m:       m,
+			vm:      vm,
+			x:       x,
+			y:       y,
+			monitor: newMonitor(m, vm),
 		})
 	}
-	clearVideoModeScaleCache()
-	devicescale.ClearCache()
 }
 
 func ensureMonitors() []*monitor {
@@ -280,6 +302,26 @@ func (u *UserInterface) setWindowSizeLimitsInDIP(minw, minh, maxw, maxh int) boo
 	return true
 }
 
+func (u *UserInterface) getWindowAspectRatio() (numer, denom int) {
+	u.m.RLock()
+	defer u.m.RUnlock()
+	return u.windowAspectRatioNumer, u.windowAspectRatioDenom
+}
+
+func (u *UserInterface) setWindowAspectRatio(numer, denom int) bool {
+	u.m.Lock()
+	defer u.m.Unlock()
+	if numer <= 0 || denom <= 0 {
+		numer, denom = 0, 0
+	}
+	if u.windowAspectRatioNumer == numer && u.windowAspectRatioDenom == denom {
+		return false
+	}
+	u.windowAspectRatioNumer = numer
+	u.windowAspectRatioDenom = denom
+	return true
+}
+
 func (u *UserInterface) isInitFullscreen() bool {
 	u.m.RLock()
 	v := u.initFullscreen
@@ -313,12 +355,29 @@ func (u *UserInterface) getCursorShape() CursorShape {
 	return v
 }
 
-func (u *UserInterface) setCursorShape(shape CursorShape) CursorShape {
+// setCursorShape sets the cursor shape and resets the cursor image.
+// setCursorShape returns true if the cursor needs to be updated.
+func (u *UserInterface) setCursorShape(shape CursorShape) bool {
 	u.m.Lock()
-	old := u.cursorShape
+	defer u.m.Unlock()
+	updated := u.cursorShape != shape || u.cursorImage != nil
 	u.cursorShape = shape
-	u.m.Unlock()
-	return old
+	u.cursorImage = nil
+	return updated
+}
+
+func (u *UserInterface) getCursorImage() (image.Image, int, int) {
+	u.m.RLock()
+	defer u.m.RUnlock()
+	return u.cursorImage, u.cursorHotspotX, u.cursorHotspotY
+}
+
+func (u *UserInterface) setCursorImage(img image.Image, hotspotX, hotspotY int) {
+	u.m.Lock()
+	defer u.m.Unlock()
+	u.cursorImage = img
+	u.cursorHotspotX = hotspotX
+	u.cursorHotspotY = hotspotY
 }
 
 func (u *UserInterface) isInitWindowDecorated() bool {
@@ -360,19 +419,37 @@ func (u *UserInterface) setInitScreenTransparent(transparent bool) {
 	u.m.Unlock()
 }
 
-func (u *UserInterface) getIconImages() []image.Image {
-	u.m.RLock()
+// takeIconImages returns the icon images and true if the icon images were updated after the last call.
+func (u *UserInterface) takeIconImages() ([]image.Image, bool) {
+	u.m.Lock()
+	defer u.m.Unlock()
+	if !u.iconImagesUpdated {
+		return nil, false
+	}
 	i := u.iconImages
-	u.m.RUnlock()
-	return i
+	u.iconImages = nil
+	u.iconImagesUpdated = false
+	return i, true
 }
 
 func (u *UserInterface) setIconImages(iconImages []image.Image) {
 	u.m.Lock()
 	u.iconImages = iconImages
+	u.iconImagesUpdated = true
 	u.m.Unlock()
 }
 
+// setIconImagesIfNotUpdated sets the icon images unless the icon images are updated by another call.
+func (u *UserInterface) setIconImagesIfNotUpdated(iconImages []image.Image) {
+	u.m.Lock()
+	defer u.m.Unlock()
+	if u.iconImagesUpdated {
+		return
+	}
+	u.iconImages = iconImages
+	u.iconImagesUpdated = true
+}
+
 func (u *UserInterface) getInitWindowPositionInDIP() (int, int) {
 	u.m.RLock()
 	defer u.m.RUnlock()
@@ -463,6 +540,13 @@ func (u *UserInterface) setInitFocused(focused bool) {
 	u.m.Unlock()
 }
 
+func (u *UserInterface) isHeadless() bool {
+	u.m.RLock()
+	v := u.headless
+	u.m.RUnlock()
+	return v
+}
+
 func (u *UserInterface) ScreenSizeInFullscreen() (int, int) {
 	if !u.isRunning() {
 		return u.initFullscreenWidthInDIP, u.initFullscreenHeightInDIP
@@ -529,6 +613,20 @@ func (u *UserInterface) IsFocused() bool {
 	return focused
 }
 
+// NativeWindow returns the native window handle: HWND on Windows, NSWindow* on macOS and X11 Window on Linux and BSDs.
+// NativeWindow returns 0 before the main loop starts.
+func (u *UserInterface) NativeWindow() uintptr {
+	if !u.isRunning() {
+		return 0
+	}
+
+	var w uintptr
+	u.t.Call(func() {
+		w = u.nativeWindow()
+	})
+	return w
+}
+
 func (u *UserInterface) SetRunnableOnUnfocused(runnableOnUnfocused bool) {
 	u.setRunnableOnUnfocused(runnableOnUnfocused)
 }
@@ -554,6 +652,10 @@ func (u *UserInterface) SetFPSMode(mode FPSModeType) {
 	})
 }
 
+func (u *UserInterface) SetPreferredFPS(fps int) {
+	// The preferred FPS is applied at updateVsync, which is called every frame.
+}
+
 func (u *UserInterface) ScheduleFrame() {
 	if !u.isRunning() {
 		return
@@ -602,16 +704,41 @@ func (u *UserInterface) CursorShape() CursorShape {
 }
 
 func (u *UserInterface) SetCursorShape(shape CursorShape) {
-	old := u.setCursorShape(shape)
-	if old == shape {
+	if !u.setCursorShape(shape) {
 		return
 	}
 	if !u.isRunning() {
 		return
 	}
-	u.t.Call(func() {
-		u.setNativeCursor(shape)
-	})
+	u.t.Call(u.updateCursor)
+}
+
+func (u *UserInterface) SetCursorImage(img image.Image, hotspotX, hotspotY int) {
+	u.setCursorImage(img, hotspotX, hotspotY)
+	if !u.isRunning() {
+		return
+	}
+	u.t.Call(u.updateCursor)
+}
+
+// updateCursor applies the cursor image or the cursor shape to the window.
+//
+// updateCursor must be called from the main thread.
+func (u *UserInterface) updateCursor() {
+	if u.customCursor != nil {
+		// The current cursor must not be destroyed while it is used.
+		u.window.SetCursor(nil)
+		u.customCursor.Destroy()
+		u.customCursor = nil
+	}
+
+	img, x, y := u.getCursorImage()
+	if img == nil {
+		u.setNativeCursor(u.getCursorShape())
+		return
+	}
+	u.customCursor = glfw.CreateCursor(img, x, y)
+	u.window.SetCursor(u.customCursor)
 }
 
 func (u *UserInterface) DeviceScaleFactor() float64 {
@@ -672,15 +799,17 @@ func (u *UserInterface) createWindow(width, height int) error {
 	}
 
 	u.window.SetInputMode(glfw.CursorMode, driverCursorModeToGLFWCursorMode(u.getInitCursorMode()))
-	u.window.SetCursor(glfwSystemCursors[u.getCursorShape()])
+	u.updateCursor()
 	u.window.SetTitle(u.title)
 	// Icons are set after every frame. They don't have to be cared here.
 
 	u.registerWindowSetSizeCallback()
 	u.registerWindowCloseCallback()
 	u.registerWindowFramebufferSizeCallback()
+	u.registerWindowContentScaleCallback()
 
 	u.updateWindowSizeLimits()
+	u.updateWindowAspectRatio()
 
 	return nil
 }
@@ -768,6 +897,20 @@ func (u *UserInterface) registerWindowFramebufferSizeCallback() {
 	u.window.SetFramebufferSizeCallback(u.defaultFramebufferSizeCallback)
 }
 
+// registerWindowContentScaleCallback must be called from the main thread.
+func (u *UserInterface) registerWindowContentScaleCallback() {
+	if u.contentScaleCallback == 0 {
+		// The content scale is changed when the window is moved to a monitor with a different DPI, or when
+		// the scale setting of the monitor is changed (e.g. WM_DPICHANGED on Windows).
+		// The device scale factors are cached per monitor. Clear the caches so that the new scale is used.
+		u.contentScaleCallback = glfw.ToContentScaleCallback(func(_ *glfw.Window, _, _ float32) {
+			clearVideoModeScaleCache()
+			devicescale.ClearCache()
+		})
+	}
+	u.window.SetContentScaleCallback(u.contentScaleCallback)
+}
+
 // waitForFramebufferSizeCallback waits for GLFW's FramebufferSize callback.
 // f is a process executed after registering the callback.
 // If the callback is not invoked for a while, waitForFramebufferSizeCallback times out and return.
@@ -859,7 +1002,8 @@ func (u *UserInterface) init() error {
 	glfw.WindowHint(glfw.FocusOnShow, focused)
 
 	// Set the window visible explicitly or the application freezes on Wayland (#974).
-	if os.Getenv("WAYLAND_DISPLAY") != "" {
+	// In the headless mode, the window is never shown.
+	if os.Getenv("WAYLAND_DISPLAY") != "" && !u.isHeadless() {
 		glfw.WindowHint(glfw.Visible, glfw.True)
 	}
 
@@ -879,14 +1023,16 @@ func (u *UserInterface) init() error {
 	u.setWindowPositionInDIP(wx, wy, u.initMonitor)
 	u.setWindowSizeInDIP(ww, wh, u.isFullscreen())
 
-	// Maximizing a window requires a proper size and position. Call Maximize here (#1117).
-	if u.isInitWindowMaximized() {
-		u.window.Maximize()
-	}
-
 	u.setWindowResizingModeForOS(u.windowResizingMode)
 
-	u.window.Show()
+	// In the headless mode, the window exists only for the graphics context and is never shown.
+	if !u.isHeadless() {
+		// Maximizing a window requires a proper size and position. Call Maximize here (#1117).
+		if u.isInitWindowMaximized() {
+			u.window.Maximize()
+		}
+		u.window.Show()
+	}
 
 	if g, ok := graphics().(interface{ SetWindow(uintptr) }); ok {
 		g.SetWindow(u.nativeWindow())
@@ -981,19 +1127,60 @@ func (u *UserInterface) update() (float64, float64, error) {
 		return 0, 0, err
 	}
 
-	for !u.isRunnableOnUnfocused() && u.window.GetAttrib(glfw.Focused) == 0 && !u.window.ShouldClose() {
-		if err := hooks.SuspendAudio(); err != nil {
+	if !u.shouldWaitForFocus() {
+		if err := hooks.ResumeAudio(); err != nil {
 			return 0, 0, err
 		}
+	}
+
+	return outsideWidth, outsideHeight, nil
+}
+
+// shouldWaitForFocus reports whether the game should stop until the window is focused.
+//
+// shouldWaitForFocus must be called from the main thread.
+func (u *UserInterface) shouldWaitForFocus() bool {
+	// A hidden window is never focused. In the headless mode, the game always runs.
+	return !u.isHeadless() && !u.isRunnableOnUnfocused() && u.window.GetAttrib(glfw.Focused) == 0 && !u.window.ShouldClose()
+}
+
+// waitForFocus waits until the window is focused.
+//
+// waitForFocus must be called from the main thread.
+func (u *UserInterface) waitForFocus() error {
+	for u.shouldWaitForFocus() {
+		if err := hooks.SuspendAudio(); err != nil {
+			return err
+		}
 		// Wait for an arbitrary period to avoid busy loop.
 		time.Sleep(time.Second / 60)
 		glfw.PollEvents()
 	}
-	if err := hooks.ResumeAudio(); err != nil {
-		return 0, 0, err
+	return hooks.ResumeAudio()
+}
+
+// lifecycleState returns whether the game is suspended and whether the window is focused.
+//
+// lifecycleState must be called from the main thread.
+func (u *UserInterface) lifecycleState() (suspended, focused bool) {
+	if u.isHeadless() {
+		return false, true
 	}
+	focused = u.window.GetAttrib(glfw.Focused) == glfw.True
+	suspended = u.window.GetAttrib(glfw.Iconified) == glfw.True || u.shouldWaitForFocus()
+	return
+}
 
-	return outsideWidth, outsideHeight, nil
+// windowState returns the window size in device-independent pixels and whether the window is maximized.
+//
+// windowState must be called from the main thread.
+func (u *UserInterface) windowState() (width, height int, maximized bool) {
+	width, height = u.windowWidthInDIP, u.windowHeightInDIP
+	if u.isHeadless() {
+		return
+	}
+	maximized = u.windowResizingMode == WindowResizingModeEnabled && u.window.GetAttrib(glfw.Maximized) == glfw.True
+	return
 }
 
 func (u *UserInterface) loop() error {
@@ -1005,7 +1192,7 @@ func (u *UserInterface) loop() error {
 		// On Windows, the focusing state might be always false (#987).
 		// On Windows, even if a window is in another workspace, vsync seems to work.
 		// Then let's assume the window is always 'focused' as a workaround.
-		if runtime.GOOS != "windows" {
+		if runtime.GOOS != "windows" && !u.isHeadless() {
 			unfocused = u.window.GetAttrib(glfw.Focused) == glfw.False
 		}
 
@@ -1017,51 +1204,74 @@ func (u *UserInterface) loop() error {
 
 		var outsideWidth, outsideHeight float64
 		var deviceScaleFactor float64
+		var suspended, focused, wait bool
+		var windowWidth, windowHeight int
+		var maximized bool
 		var err error
 		if u.t.Call(func() {
 			outsideWidth, outsideHeight, err = u.update()
 			deviceScaleFactor = u.deviceScaleFactor(u.currentMonitor())
+			suspended, focused = u.lifecycleState()
+			wait = u.shouldWaitForFocus()
+			windowWidth, windowHeight, maximized = u.windowState()
 		}); err != nil {
 			return err
 		}
 
+		// Notify the game of the lifecycle changes before the main thread waits for the focus.
+		u.context.updateLifecycle(suspended, focused)
+		u.context.updateWindowState(windowWidth, windowHeight, maximized)
+		if wait {
+			if u.t.Call(func() {
+				err = u.waitForFocus()
+			}); err != nil {
+				return err
+			}
+			// Update the states again after the window is focused.
+			continue
+		}
+
 		if err := u.context.updateFrame(outsideWidth, outsideHeight, deviceScaleFactor); err != nil {
 			return err
 		}
 
 		// Create icon images in a different goroutine (#1478).
 		// In the fullscreen mode, SetIcon fails (#1578).
-		if imgs := u.getIconImages(); len(imgs) > 0 && !u.isFullscreen() {
-			u.setIconImages(nil)
-
-			// Convert the icons in the different goroutine, as (*ebiten.Image).At cannot be invoked
-			// from this goroutine. At works only in between BeginFrame and EndFrame.
-			go func() {
-				newImgs := make([]image.Image, len(imgs))
-				for i, img := range imgs {
-					// TODO: If img is not *ebiten.Image, this converting is not necessary.
-					// However, this package cannot refer *ebiten.Image due to the package
-					// dependencies.
-
-					b := img.Bounds()
-					rgba := image.NewRGBA(b)
-					for j := b.Min.Y; j < b.Max.Y; j++ {
-						for i := b.Min.X; i < b.Max.X; i++ {
-							rgba.Set(i, j, img.At(i, j))
+		if !u.isFullscreen() {
+			if imgs, ok := u.takeIconImages(); ok {
+				// Convert the icons in the different goroutine, as (*ebiten.Image).At cannot be invoked
+				// from this goroutine. At works only in between BeginFrame and EndFrame.
+				go func() {
+					newImgs := make([]image.Image, len(imgs))
+					for i, img := range imgs {
+						// The ebiten package passes *ebiten.Image icons as *image.RGBA after reading their
+						// pixels from GPU. Other images might still refer *ebiten.Image.
+						if rgba, ok := img.(*image.RGBA); ok {
+							newImgs[i] = rgba
+							continue
 						}
+
+						b := img.Bounds()
+						rgba := image.NewRGBA(b)
+						for j := b.Min.Y; j < b.Max.Y; j++ {
+							for i := b.Min.X; i < b.Max.X; i++ {
+								rgba.Set(i, j, img.At(i, j))
+							}
+						}
+						newImgs[i] = rgba
 					}
-					newImgs[i] = rgba
-				}
-
-				u.t.Call(func() {
-					// In the fullscreen mode, reset the icon images and try again later.
-					if u.isFullscreen() {
-						u.setIconImages(imgs)
-						return
-					}
-					u.window.SetIcon(newImgs)
-				})
-			}()
+
+					u.t.Call(func() {
+						// In the fullscreen mode, reset the icon images and try again later.
+						if u.isFullscreen() {
+							u.setIconImagesIfNotUpdated(imgs)
+							return
+						}
+						u.window.SetIcon(newImgs)
+						u.setApplicationIcon(newImgs)
+					})
+				}()
+			}
 		}
 
 		// swapBuffers also checks IsGL, so this condition is redundant.
@@ -1123,6 +1333,16 @@ func (u *UserInterface) updateWindowSizeLimits() {
 	u.window.SetSizeLimits(minw, minh, maxw, maxh)
 }
 
+// updateWindowAspectRatio must be called from the main thread.
+func (u *UserInterface) updateWindowAspectRatio() {
+	numer, denom := u.getWindowAspectRatio()
+	if numer == 0 || denom == 0 {
+		u.window.SetAspectRatio(glfw.DontCare, glfw.DontCare)
+		return
+	}
+	u.window.SetAspectRatio(numer, denom)
+}
+
 // adjustWindowSizeBasedOnSizeLimitsInDIP adjust the size based on the window size limits.
 // width and height are in device-independent pixels.
 func (u *UserInterface) adjustWindowSizeBasedOnSizeLimitsInDIP(width, height int) (int, int) {
@@ -1275,13 +1495,52 @@ func (u *UserInterface) updateVsync() {
 		// TODO: (#405) If triple buffering is needed, SwapInterval(0) should be called,
 		// but is this correct? If glfw.SwapInterval(0) and the driver doesn't support triple
 		// buffering, what will happen?
-		if u.fpsMode == FPSModeVsyncOn {
-			glfw.SwapInterval(1)
+		if u.isVsyncEffective() {
+			glfw.SwapInterval(u.swapInterval())
 		} else {
 			glfw.SwapInterval(0)
 		}
 	}
-	graphics().SetVsyncEnabled(u.fpsMode == FPSModeVsyncOn)
+	graphics().SetVsyncEnabled(u.isVsyncEffective())
+
+	// A graphics driver without swap intervals (e.g. Metal) delays the presentation by a duration instead.
+	if g, ok := graphics().(interface{ SetMinimumPresentDuration(time.Duration) }); ok {
+		var d time.Duration
+		if interval := u.swapInterval(); u.isVsyncEffective() && interval > 1 {
+			if r := u.currentMonitor().GetVideoMode().RefreshRate; r > 0 {
+				// Subtract a half of a vblank so that the presentation is not delayed to the next vblank.
+				d = time.Duration((float64(interval) - 0.5) * float64(time.Second) / float64(r))
+			}
+		}
+		g.SetMinimumPresentDuration(d)
+	}
+}
+
+// swapInterval returns the number of vertical blanks per frame.
+// The preferred FPS is converted to the number of vertical blanks based on the refresh rate of the current monitor.
+// For example, 60 FPS on a 120 Hz display is 2 vertical blanks per frame.
+//
+// swapInterval must be called from the main thread.
+func (u *UserInterface) swapInterval() int {
+	n := theGlobalState.vsyncInterval()
+	fps := theGlobalState.preferredFPS()
+	if fps == 0 {
+		return n
+	}
+	r := u.currentMonitor().GetVideoMode().RefreshRate
+	if r <= 0 {
+		return n
+	}
+	if m := r / fps; m > n {
+		n = m
+	}
+	return n
+}
+
+// isVsyncEffective reports whether the presentation should wait for vsync.
+// In the headless mode, nothing is presented on the display and vsync is always disabled.
+func (u *UserInterface) isVsyncEffective() bool {
+	return u.fpsMode == FPSModeVsyncOn && !u.isHeadless()
 }
 
 // currentMonitor returns the current active monitor.
@@ -1340,6 +1599,10 @@ func (u *UserInterface) IsScreenTransparent() bool {
 	return val
 }
 
+func (u *UserInterface) IsSystemDarkMode() bool {
+	return isSystemDarkMode()
+}
+
 func (u *UserInterface) resetForTick() {
 	u.input.resetForTick()
 
@@ -1355,6 +1618,16 @@ func (u *UserInterface) SetInitFocused(focused bool) {
 	u.setInitFocused(focused)
 }
 
+func (u *UserInterface) SetHeadless(headless bool) error {
+	if u.isRunning() {
+		panic("ui: SetHeadless must be called before the main loop")
+	}
+	u.m.Lock()
+	u.headless = headless
+	u.m.Unlock()
+	return nil
+}
+
 func (u *UserInterface) Input() *Input {
 	return &u.input
 }
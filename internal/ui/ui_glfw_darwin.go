@@ -21,6 +21,7 @@ package ui
 // #cgo LDFLAGS: -framework AppKit
 //
 // #import <AppKit/AppKit.h>
+// #include <stdlib.h>
 //
 // @interface EbitenWindowDelegate : NSObject <NSWindowDelegate>
 // @end
@@ -223,15 +224,70 @@ package ui
 //     window.collectionBehavior &= ~NSWindowCollectionBehaviorFullScreenPrimary;
 //   }
 // }
+//
+// static void setApplicationIconImage(uint8_t* pixels, int width, int height) {
+//   @autoreleasepool {
+//     if (!pixels) {
+//       // Revert to the default icon.
+//       [NSApp setApplicationIconImage:nil];
+//       return;
+//     }
+//     NSBitmapImageRep* rep = [[NSBitmapImageRep alloc]
+//         initWithBitmapDataPlanes:NULL
+//                       pixelsWide:width
+//                       pixelsHigh:height
+//                    bitsPerSample:8
+//                  samplesPerPixel:4
+//                         hasAlpha:YES
+//                         isPlanar:NO
+//                   colorSpaceName:NSDeviceRGBColorSpace
+//                      bytesPerRow:width * 4
+//                     bitsPerPixel:32];
+//     memcpy([rep bitmapData], pixels, width * height * 4);
+//     NSImage* image = [[NSImage alloc] initWithSize:NSMakeSize(width, height)];
+//     [image addRepresentation:rep];
+//     [NSApp setApplicationIconImage:image];
+//     [image release];
+//     [rep release];
+//   }
+// }
+//
+// static void setDockBadgeLabel(const char* label) {
+//   @autoreleasepool {
+//     NSString* str = [NSString stringWithUTF8String:label];
+//     if (str.length == 0) {
+//       str = nil;
+//     }
+//     [[NSApp dockTile] setBadgeLabel:str];
+//   }
+// }
+//
+// static bool isSystemDarkMode() {
+//   @autoreleasepool {
+//     NSString* style = [[NSUserDefaults standardUserDefaults] stringForKey:@"AppleInterfaceStyle"];
+//     return [style isEqualToString:@"Dark"];
+//   }
+// }
 import "C"
 
 import (
+	"fmt"
+	"image"
+	"image/draw"
+	"unsafe"
+
 	"github.com/hajimehoshi/ebiten/v2/internal/glfw"
 )
 
 // clearVideoModeScaleCache must be called from the main thread.
 func clearVideoModeScaleCache() {}
 
+// isSystemDarkMode reports whether the system appearance is dark.
+// The window chrome follows the system appearance automatically on macOS.
+func isSystemDarkMode() bool {
+	return bool(C.isSystemDarkMode())
+}
+
 // dipFromGLFWMonitorPixel must be called from the main thread.
 func (u *UserInterface) dipFromGLFWMonitorPixel(x float64, monitor *glfw.Monitor) float64 {
 	return x
@@ -299,6 +355,33 @@ func (u *UserInterface) isNativeFullscreen() bool {
 	return bool(C.isNativeFullscreen(C.uintptr_t(u.window.GetCocoaWindow())))
 }
 
+// setApplicationIcon sets the Dock icon, as macOS windows don't have icons.
+//
+// setApplicationIcon must be called from the main thread.
+func (u *UserInterface) setApplicationIcon(iconImages []image.Image) {
+	if len(iconImages) == 0 {
+		C.setApplicationIconImage(nil, 0, 0)
+		return
+	}
+
+	// Use the largest image for the Dock.
+	img := iconImages[0]
+	for _, i := range iconImages[1:] {
+		if i.Bounds().Dx()*i.Bounds().Dy() > img.Bounds().Dx()*img.Bounds().Dy() {
+			img = i
+		}
+	}
+
+	// NSBitmapImageRep expects premultiplied alpha values by default, as image.RGBA does.
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+	if len(rgba.Pix) == 0 {
+		return
+	}
+	C.setApplicationIconImage((*C.uint8_t)(unsafe.Pointer(&rgba.Pix[0])), C.int(b.Dx()), C.int(b.Dy()))
+}
+
 func (u *UserInterface) setNativeCursor(shape CursorShape) {
 	C.setNativeCursor(C.int(shape))
 }
@@ -328,6 +411,28 @@ func (u *UserInterface) setWindowResizingModeForOS(mode WindowResizingMode) {
 	C.setAllowFullscreen(C.uintptr_t(u.window.GetCocoaWindow()), C.bool(allowFullscreen))
 }
 
+// setTaskbarProgressByOS must be called from the main thread.
+func (u *UserInterface) setTaskbarProgressByOS(state TaskbarProgressState, progress float64) {
+	// Show the progress as a badge of the Dock icon.
+	var label string
+	switch state {
+	case TaskbarProgressStateNormal, TaskbarProgressStatePaused:
+		label = fmt.Sprintf("%d%%", int(progress*100))
+	case TaskbarProgressStateError:
+		label = "!"
+	}
+	l := C.CString(label)
+	defer C.free(unsafe.Pointer(l))
+	C.setDockBadgeLabel(l)
+}
+
+// updateIMEByOS must be called from the main thread.
+func (u *UserInterface) updateIMEByOS(caret image.Rectangle) imeState {
+	// TODO: Implement this. GLFW 3.3 implements NSTextInputClient for its content view by itself and discards
+	// the marked text, so the composition text is not available without replacing GLFW's view.
+	return imeState{selected: -1}
+}
+
 func initializeWindowAfterCreation(w *glfw.Window) {
 	// TODO: Register NSWindowWillEnterFullScreenNotification and so on.
 	// Enable resizing temporary before making the window fullscreen.
@@ -19,6 +19,7 @@ package ui
 
 import (
 	"fmt"
+	"image"
 	"math"
 	"runtime"
 
@@ -39,6 +40,12 @@ func clearVideoModeScaleCache() {
 	}
 }
 
+// isSystemDarkMode reports whether the system theme is dark.
+// There is no standard way to get the theme on Linux/Unix, and this always returns false so far.
+func isSystemDarkMode() bool {
+	return false
+}
+
 // videoModeScale must be called from the main thread.
 func videoModeScale(m *glfw.Monitor) float64 {
 	// Caching wrapper for videoModeScaleUncached as
@@ -165,8 +172,7 @@ func monitorFromWindowByOS(_ *glfw.Window) *glfw.Monitor {
 }
 
 func (u *UserInterface) nativeWindow() uintptr {
-	// TODO: Implement this.
-	return 0
+	return u.window.GetX11Window()
 }
 
 func (u *UserInterface) isNativeFullscreen() bool {
@@ -178,6 +184,11 @@ func (u *UserInterface) setNativeCursor(shape CursorShape) {
 	u.window.SetCursor(glfwSystemCursors[shape])
 }
 
+// setApplicationIcon must be called from the main thread.
+func (u *UserInterface) setApplicationIcon(iconImages []image.Image) {
+	// The taskbar icon follows the window icon.
+}
+
 func (u *UserInterface) isNativeFullscreenAvailable() bool {
 	return false
 }
@@ -192,6 +203,18 @@ func (u *UserInterface) adjustViewSize() {
 func (u *UserInterface) setWindowResizingModeForOS(mode WindowResizingMode) {
 }
 
+// setTaskbarProgressByOS must be called from the main thread.
+func (u *UserInterface) setTaskbarProgressByOS(state TaskbarProgressState, progress float64) {
+	// TODO: Implement this with the Unity LauncherEntry D-Bus API.
+}
+
+// updateIMEByOS must be called from the main thread.
+func (u *UserInterface) updateIMEByOS(caret image.Rectangle) imeState {
+	// TODO: Implement this. GLFW 3.3 creates the XIM input context by itself and doesn't expose it nor the
+	// preedit callbacks, so the composition text is not available without a patched GLFW.
+	return imeState{selected: -1}
+}
+
 func initializeWindowAfterCreation(w *glfw.Window) {
 	// Show the window once before getting the position of the window.
 	// On Linux/Unix, the window position is not reliable before showing.
@@ -19,10 +19,12 @@ package ui
 
 import (
 	"fmt"
+	"image"
 	"runtime"
 	"unsafe"
 
 	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/glfw"
 )
@@ -30,6 +32,9 @@ import (
 const (
 	smCyCaption             = 4
 	monitorDefaultToNearest = 2
+
+	dwmwaUseImmersiveDarkModeBefore20H1 = 19
+	dwmwaUseImmersiveDarkMode           = 20
 )
 
 type rect struct {
@@ -57,6 +62,10 @@ var (
 	procMonitorFromWindow = user32.NewProc("MonitorFromWindow")
 	procGetMonitorInfoW   = user32.NewProc("GetMonitorInfoW")
 	procGetCursorPos      = user32.NewProc("GetCursorPos")
+
+	dwmapi = windows.NewLazySystemDLL("dwmapi.dll")
+
+	procDwmSetWindowAttribute = dwmapi.NewProc("DwmSetWindowAttribute")
 )
 
 func getSystemMetrics(nIndex int) (int32, error) {
@@ -97,6 +106,46 @@ func getCursorPos() (int32, int32, error) {
 	return pt.x, pt.y, nil
 }
 
+func dwmSetWindowAttribute(hwnd windows.HWND, dwAttribute uint32, pvAttribute unsafe.Pointer, cbAttribute uint32) error {
+	if err := procDwmSetWindowAttribute.Find(); err != nil {
+		return err
+	}
+	r, _, _ := procDwmSetWindowAttribute.Call(uintptr(hwnd), uintptr(dwAttribute), uintptr(pvAttribute), uintptr(cbAttribute))
+	if r != uintptr(windows.S_OK) {
+		return fmt.Errorf("ui: DwmSetWindowAttribute failed: HRESULT(%d)", r)
+	}
+	return nil
+}
+
+func isSystemDarkMode() bool {
+	k, err := registry.OpenKey(registry.CURRENT_USER, `Software\Microsoft\Windows\CurrentVersion\Themes\Personalize`, registry.QUERY_VALUE)
+	if err != nil {
+		// The key doesn't exist before Windows 10.
+		return false
+	}
+	defer k.Close()
+
+	v, _, err := k.GetIntegerValue("AppsUseLightTheme")
+	if err != nil {
+		return false
+	}
+	return v == 0
+}
+
+// setWindowDarkMode makes the title bar of the window dark or light.
+func setWindowDarkMode(hwnd windows.HWND, dark bool) {
+	var v int32
+	if dark {
+		v = 1
+	}
+	if err := dwmSetWindowAttribute(hwnd, dwmwaUseImmersiveDarkMode, unsafe.Pointer(&v), uint32(unsafe.Sizeof(v))); err == nil {
+		return
+	}
+	// Windows 10 before 20H1 uses an undocumented attribute value.
+	// Ignore the error since the attribute is not available before Windows 10 1809.
+	_ = dwmSetWindowAttribute(hwnd, dwmwaUseImmersiveDarkModeBefore20H1, unsafe.Pointer(&v), uint32(unsafe.Sizeof(v)))
+}
+
 // clearVideoModeScaleCache must be called from the main thread.
 func clearVideoModeScaleCache() {}
 
@@ -192,6 +241,11 @@ func (u *UserInterface) setNativeCursor(shape CursorShape) {
 	u.window.SetCursor(glfwSystemCursors[shape])
 }
 
+// setApplicationIcon must be called from the main thread.
+func (u *UserInterface) setApplicationIcon(iconImages []image.Image) {
+	// The taskbar icon follows the window icon.
+}
+
 func (u *UserInterface) isNativeFullscreenAvailable() bool {
 	return false
 }
@@ -207,4 +261,8 @@ func (u *UserInterface) setWindowResizingModeForOS(mode WindowResizingMode) {
 }
 
 func initializeWindowAfterCreation(w *glfw.Window) {
+	// Match the title bar with the system theme.
+	if isSystemDarkMode() {
+		setWindowDarkMode(windows.HWND(w.GetWin32Window()), true)
+	}
 }
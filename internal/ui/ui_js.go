@@ -15,6 +15,12 @@
 package ui
 
 import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
 	"syscall/js"
 	"time"
 
@@ -55,9 +61,11 @@ type UserInterface struct {
 	cursorMode          CursorMode
 	cursorPrevMode      CursorMode
 	cursorShape         CursorShape
+	cursorImageCSS      string
 	onceUpdateCalled    bool
 
 	lastDeviceScaleFactor float64
+	animationFrameCount   int
 
 	context *contextImpl
 	input   Input
@@ -80,6 +88,7 @@ var (
 	window                = js.Global().Get("window")
 	document              = js.Global().Get("document")
 	canvas                js.Value
+	imeTextArea           js.Value
 	requestAnimationFrame = js.Global().Get("requestAnimationFrame")
 	setTimeout            = js.Global().Get("setTimeout")
 	go2cpp                = js.Global().Get("go2cpp")
@@ -145,6 +154,11 @@ func (u *UserInterface) SetRunnableOnUnfocused(runnableOnUnfocused bool) {
 	u.runnableOnUnfocused = runnableOnUnfocused
 }
 
+// NativeWindow returns the canvas element.
+func (u *UserInterface) NativeWindow() js.Value {
+	return canvas
+}
+
 func (u *UserInterface) IsRunnableOnUnfocused() bool {
 	return u.runnableOnUnfocused
 }
@@ -153,6 +167,10 @@ func (u *UserInterface) SetFPSMode(mode FPSModeType) {
 	u.fpsMode = mode
 }
 
+func (u *UserInterface) SetPreferredFPS(fps int) {
+	// Do nothing. requestAnimationFrame doesn't tell the refresh rate of the display.
+}
+
 func (u *UserInterface) ScheduleFrame() {
 	u.renderingScheduled = true
 }
@@ -179,7 +197,7 @@ func (u *UserInterface) SetCursorMode(mode CursorMode) {
 	u.cursorMode = mode
 	switch mode {
 	case CursorModeVisible:
-		canvas.Get("style").Set("cursor", driverCursorShapeToCSSCursor(u.cursorShape))
+		canvas.Get("style").Set("cursor", u.cssCursor())
 	case CursorModeHidden:
 		canvas.Get("style").Set("cursor", stringNone)
 	case CursorModeCaptured:
@@ -205,14 +223,42 @@ func (u *UserInterface) SetCursorShape(shape CursorShape) {
 	if !canvas.Truthy() {
 		return
 	}
-	if u.cursorShape == shape {
+	if u.cursorShape == shape && u.cursorImageCSS == "" {
 		return
 	}
 
 	u.cursorShape = shape
+	u.cursorImageCSS = ""
+	if u.cursorMode == CursorModeVisible {
+		canvas.Get("style").Set("cursor", u.cssCursor())
+	}
+}
+
+func (u *UserInterface) SetCursorImage(img image.Image, hotspotX, hotspotY int) {
+	if !canvas.Truthy() {
+		return
+	}
+
+	u.cursorImageCSS = ""
+	if img != nil {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			panic(fmt.Sprintf("ui: png.Encode failed: %v", err))
+		}
+		// The fallback is needed as a CSS cursor with an image is ignored without a fallback.
+		u.cursorImageCSS = fmt.Sprintf("url(data:image/png;base64,%s) %d %d, %s", base64.StdEncoding.EncodeToString(buf.Bytes()), hotspotX, hotspotY, driverCursorShapeToCSSCursor(u.cursorShape))
+	}
 	if u.cursorMode == CursorModeVisible {
-		canvas.Get("style").Set("cursor", driverCursorShapeToCSSCursor(u.cursorShape))
+		canvas.Get("style").Set("cursor", u.cssCursor())
+	}
+}
+
+// cssCursor returns the CSS cursor value for the current cursor image or shape.
+func (u *UserInterface) cssCursor() string {
+	if u.cursorImageCSS != "" {
+		return u.cursorImageCSS
 	}
+	return driverCursorShapeToCSSCursor(u.cursorShape)
 }
 
 func (u *UserInterface) DeviceScaleFactor() float64 {
@@ -267,14 +313,33 @@ func (u *UserInterface) update() error {
 	return u.updateImpl(false)
 }
 
+// lifecycleState returns whether the game is suspended and whether the document is focused.
+func (u *UserInterface) lifecycleState() (suspended, focused bool) {
+	if go2cpp.Truthy() {
+		return false, true
+	}
+	return documentHidden.Invoke().Bool() || u.suspended(), u.isFocused()
+}
+
+// updateLifecycle notifies the game of the lifecycle changes.
+func (u *UserInterface) updateLifecycle() {
+	if u.context == nil {
+		return
+	}
+	u.context.updateLifecycle(u.lifecycleState())
+}
+
 func (u *UserInterface) updateImpl(force bool) error {
 	// context can be nil when an event is fired but the loop doesn't start yet (#1928).
 	if u.context == nil {
 		return nil
 	}
 
+	u.context.updateLifecycle(u.lifecycleState())
+
 	gamepad.Update()
 	u.input.updateForGo2Cpp()
+	u.input.updateIME()
 
 	a := u.DeviceScaleFactor()
 	if u.lastDeviceScaleFactor != a {
@@ -318,6 +383,17 @@ func (u *UserInterface) loop(game Game) <-chan error {
 
 	var cf js.Func
 	f := func() {
+		// Skip animation frames to present every n-th vblank.
+		if u.fpsMode == FPSModeVsyncOn {
+			if n := theGlobalState.vsyncInterval(); n > 1 {
+				u.animationFrameCount++
+				if u.animationFrameCount < n {
+					requestAnimationFrame.Invoke(cf)
+					return
+				}
+				u.animationFrameCount = 0
+			}
+		}
 		if u.needsUpdate() {
 			u.onceUpdateCalled = true
 			u.renderingScheduled = false
@@ -372,6 +448,8 @@ func (u *UserInterface) loop(game Game) <-chan error {
 		for {
 			select {
 			case <-t.C:
+				// Notify the game of the lifecycle changes here too, as the game is not updated while the tab is hidden.
+				u.updateLifecycle()
 				if u.suspended() {
 					if err := hooks.SuspendAudio(); err != nil {
 						errCh <- err
@@ -445,6 +523,32 @@ func init() {
 
 	setCanvasEventHandlers(canvas)
 
+	// A browser's IME works only on an editable element, while a canvas is not editable.
+	// Create a hidden text area that receives the keyboard inputs instead of the canvas while the IME caret
+	// rectangle is set. The browser shows the IME windows next to the text area.
+	imeTextArea = document.Call("createElement", "textarea")
+	imeTextArea.Call("setAttribute", "autocomplete", "off")
+	imeTextArea.Call("setAttribute", "spellcheck", "false")
+	imeTextArea.Call("setAttribute", "tabindex", -1)
+	imeTextAreaStyle := imeTextArea.Get("style")
+	imeTextAreaStyle.Set("position", "fixed")
+	imeTextAreaStyle.Set("left", "0")
+	imeTextAreaStyle.Set("top", "0")
+	imeTextAreaStyle.Set("width", "1px")
+	imeTextAreaStyle.Set("height", "1px")
+	imeTextAreaStyle.Set("margin", "0")
+	imeTextAreaStyle.Set("padding", "0")
+	imeTextAreaStyle.Set("border", "0")
+	imeTextAreaStyle.Set("outline", "none")
+	imeTextAreaStyle.Set("resize", "none")
+	imeTextAreaStyle.Set("overflow", "hidden")
+	imeTextAreaStyle.Set("opacity", "0")
+	imeTextAreaStyle.Set("pointerEvents", "none")
+	document.Get("body").Call("appendChild", imeTextArea)
+
+	setKeyboardEventHandlers(imeTextArea)
+	setIMEEventHandlers(imeTextArea)
+
 	// Pointer Lock
 	document.Call("addEventListener", "pointerlockchange", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
 		if document.Get("pointerLockElement").Truthy() {
@@ -483,13 +587,17 @@ func setWindowEventHandlers(v js.Value) {
 	}))
 }
 
-func setCanvasEventHandlers(v js.Value) {
-	// Keyboard
+func setKeyboardEventHandlers(v js.Value) {
 	v.Call("addEventListener", "keydown", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
 		// Focus the canvas explicitly to activate tha game (#961).
 		v.Call("focus")
 
 		e := args[0]
+		// The keys during an IME composition are for the IME.
+		// Don't handle them, or e.g. preventing Backspace would break the composition.
+		if e.Get("isComposing").Truthy() {
+			return nil
+		}
 		// Don't 'preventDefault' on keydown events or keypress events wouldn't work (#715).
 		theUI.input.updateFromEvent(e)
 		return nil
@@ -502,10 +610,27 @@ func setCanvasEventHandlers(v js.Value) {
 	}))
 	v.Call("addEventListener", "keyup", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
 		e := args[0]
-		e.Call("preventDefault")
+		// Release the key even during an IME composition, as the key might be pressed before the composition.
+		if !e.Get("isComposing").Truthy() {
+			e.Call("preventDefault")
+		}
 		theUI.input.updateFromEvent(e)
 		return nil
 	}))
+}
+
+func setIMEEventHandlers(v js.Value) {
+	for _, name := range []string{"compositionstart", "compositionupdate", "compositionend", "input"} {
+		v.Call("addEventListener", name, js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			theUI.input.updateIMEFromEvent(args[0])
+			return nil
+		}))
+	}
+}
+
+func setCanvasEventHandlers(v js.Value) {
+	// Keyboard
+	setKeyboardEventHandlers(v)
 
 	// Mouse
 	v.Call("addEventListener", "mousedown", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
@@ -559,6 +684,19 @@ func setCanvasEventHandlers(v js.Value) {
 		return nil
 	}))
 
+	// Drag and drop
+	v.Call("addEventListener", "dragover", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		e := args[0]
+		e.Call("preventDefault")
+		return nil
+	}))
+	v.Call("addEventListener", "drop", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		e := args[0]
+		e.Call("preventDefault")
+		theUI.input.updateDroppedFilesFromEvent(e)
+		return nil
+	}))
+
 	// Context menu
 	v.Call("addEventListener", "contextmenu", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
 		e := args[0]
@@ -626,6 +764,13 @@ func (u *UserInterface) IsScreenTransparent() bool {
 	return bodyStyle.Get("backgroundColor").Equal(stringTransparent)
 }
 
+func (u *UserInterface) IsSystemDarkMode() bool {
+	if !window.Get("matchMedia").Truthy() {
+		return false
+	}
+	return window.Call("matchMedia", "(prefers-color-scheme: dark)").Get("matches").Bool()
+}
+
 func (u *UserInterface) resetForTick() {
 	u.input.resetForTick()
 }
@@ -637,6 +782,13 @@ func (u *UserInterface) SetInitFocused(focused bool) {
 	u.initFocused = focused
 }
 
+func (u *UserInterface) SetHeadless(headless bool) error {
+	if headless {
+		return errors.New("ui: the headless mode is not available on browsers")
+	}
+	return nil
+}
+
 func (u *UserInterface) Input() *Input {
 	return &u.input
 }
@@ -19,7 +19,9 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"image"
 	"runtime/debug"
 	"sync"
 	"sync/atomic"
@@ -52,8 +54,9 @@ var (
 	renderEndCh = make(chan struct{})
 
 	theUI = &UserInterface{
-		foreground: 1,
-		errCh:      make(chan error),
+		foreground:  1,
+		lifecycleCh: make(chan struct{}, 1),
+		errCh:       make(chan error),
 
 		// Give a default outside size so that the game can start without initializing them.
 		outsideWidth:  640,
@@ -101,6 +104,9 @@ type UserInterface struct {
 	foreground int32
 	errCh      chan error
 
+	// lifecycleCh is notified when the foreground state is changed.
+	lifecycleCh chan struct{}
+
 	// Used for gomobile-build
 	gbuildWidthPx   int
 	gbuildHeightPx  int
@@ -229,6 +235,11 @@ func (u *UserInterface) SetForeground(foreground bool) error {
 	}
 	atomic.StoreInt32(&u.foreground, v)
 
+	select {
+	case u.lifecycleCh <- struct{}{}:
+	default:
+	}
+
 	if foreground {
 		return hooks.ResumeAudio()
 	} else {
@@ -315,11 +326,22 @@ func (u *UserInterface) outsideSize() (float64, float64) {
 }
 
 func (u *UserInterface) update() error {
-	<-renderCh
+	// Rendering stops while the app is in the background.
+	// Notify the game of the lifecycle changes while waiting for rendering.
+	for waiting := true; waiting; {
+		select {
+		case <-renderCh:
+			waiting = false
+		case <-u.lifecycleCh:
+			u.updateLifecycle()
+		}
+	}
 	defer func() {
 		renderEndCh <- struct{}{}
 	}()
 
+	u.updateLifecycle()
+
 	w, h := u.outsideSize()
 	if err := u.context.updateFrame(w, h, deviceScale()); err != nil {
 		return err
@@ -327,6 +349,12 @@ func (u *UserInterface) update() error {
 	return nil
 }
 
+// updateLifecycle must be called on the same goroutine as update().
+func (u *UserInterface) updateLifecycle() {
+	foreground := u.IsFocused()
+	u.context.updateLifecycle(!foreground, foreground)
+}
+
 func (u *UserInterface) ScreenSizeInFullscreen() (int, int) {
 	// TODO: This function should return gbuildWidthPx, gbuildHeightPx,
 	// but these values are not initialized until the main loop starts.
@@ -377,6 +405,10 @@ func (u *UserInterface) SetCursorShape(shape CursorShape) {
 	// Do nothing
 }
 
+func (u *UserInterface) SetCursorImage(img image.Image, hotspotX, hotspotY int) {
+	// Do nothing
+}
+
 func (u *UserInterface) IsFullscreen() bool {
 	return false
 }
@@ -389,6 +421,10 @@ func (u *UserInterface) IsFocused() bool {
 	return atomic.LoadInt32(&u.foreground) != 0
 }
 
+func (u *UserInterface) NativeWindow() uintptr {
+	return 0
+}
+
 func (u *UserInterface) IsRunnableOnUnfocused() bool {
 	return false
 }
@@ -409,6 +445,17 @@ func (u *UserInterface) updateExplicitRenderingModeIfNeeded() {
 	u.renderRequester.SetExplicitRenderingMode(u.fpsMode == FPSModeVsyncOffMinimum)
 }
 
+func (u *UserInterface) SetPreferredFPS(fps int) {
+	u.updatePreferredFPSIfNeeded()
+}
+
+func (u *UserInterface) updatePreferredFPSIfNeeded() {
+	if u.renderRequester == nil {
+		return
+	}
+	u.renderRequester.SetPreferredFrameRate(theGlobalState.preferredFPS())
+}
+
 func (u *UserInterface) DeviceScaleFactor() float64 {
 	return deviceScale()
 }
@@ -421,6 +468,11 @@ func (u *UserInterface) IsScreenTransparent() bool {
 	return false
 }
 
+func (u *UserInterface) IsSystemDarkMode() bool {
+	// TODO: Implement this for Android and iOS.
+	return false
+}
+
 func (u *UserInterface) resetForTick() {
 	u.input.resetForTick()
 }
@@ -429,6 +481,13 @@ func (u *UserInterface) SetInitFocused(focused bool) {
 	// Do nothing
 }
 
+func (u *UserInterface) SetHeadless(headless bool) error {
+	if headless {
+		return errors.New("ui: the headless mode is not available on mobiles")
+	}
+	return nil
+}
+
 func (u *UserInterface) Input() *Input {
 	return &u.input
 }
@@ -452,12 +511,14 @@ func (u *UserInterface) UpdateInput(keys map[Key]struct{}, runes []rune, touches
 
 type RenderRequester interface {
 	SetExplicitRenderingMode(explicitRendering bool)
+	SetPreferredFrameRate(fps int)
 	RequestRenderIfNeeded()
 }
 
 func (u *UserInterface) SetRenderRequester(renderRequester RenderRequester) {
 	u.renderRequester = renderRequester
 	u.updateExplicitRenderingModeIfNeeded()
+	u.updatePreferredFPSIfNeeded()
 }
 
 func (u *UserInterface) ScheduleFrame() {
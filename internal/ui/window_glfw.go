@@ -194,6 +194,30 @@ func (w *Window) SetPosition(x, y int) {
 	})
 }
 
+func (w *Window) Monitor() *Monitor {
+	if !w.ui.isRunning() {
+		w.ui.m.Lock()
+		m := w.ui.initMonitor
+		w.ui.m.Unlock()
+		return publicMonitor(m)
+	}
+	var m *Monitor
+	w.ui.t.Call(func() {
+		m = publicMonitor(w.ui.currentMonitor())
+	})
+	return m
+}
+
+func (w *Window) SetMonitor(monitor *Monitor) {
+	if !w.ui.isRunning() {
+		w.ui.setInitMonitor(monitor)
+		return
+	}
+	w.ui.t.Call(func() {
+		w.ui.setWindowMonitor(monitor)
+	})
+}
+
 func (w *Window) Size() (int, int) {
 	if !w.ui.isRunning() {
 		ww, wh := w.ui.getInitWindowSizeInDIP()
@@ -238,6 +262,39 @@ func (w *Window) SetSizeLimits(minw, minh, maxw, maxh int) {
 	w.ui.t.Call(w.ui.updateWindowSizeLimits)
 }
 
+func (w *Window) AspectRatioLocked() (width, height int) {
+	return w.ui.getWindowAspectRatio()
+}
+
+func (w *Window) SetAspectRatioLocked(width, height int) {
+	if !w.ui.setWindowAspectRatio(width, height) {
+		return
+	}
+	if !w.ui.isRunning() {
+		return
+	}
+
+	w.ui.t.Call(w.ui.updateWindowAspectRatio)
+}
+
+func (w *Window) SetTaskbarProgress(state TaskbarProgressState, progress float64) {
+	if !w.ui.isRunning() {
+		// Do nothing
+		return
+	}
+	w.ui.t.Call(func() {
+		w.ui.setTaskbarProgressByOS(state, progress)
+	})
+}
+
+func (w *Window) RequestAttention() {
+	if !w.ui.isRunning() {
+		// Do nothing
+		return
+	}
+	w.ui.t.Call(w.ui.window.RequestAttention)
+}
+
 func (w *Window) SetIcon(iconImages []image.Image) {
 	// The icons are actually set at (*UserInterface).loop.
 	w.ui.setIconImages(iconImages)
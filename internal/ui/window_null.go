@@ -44,6 +44,13 @@ func (*Window) Position() (int, int) {
 func (*Window) SetPosition(x, y int) {
 }
 
+func (*Window) Monitor() *Monitor {
+	return nil
+}
+
+func (*Window) SetMonitor(monitor *Monitor) {
+}
+
 func (*Window) Size() (int, int) {
 	return 0, 0
 }
@@ -58,6 +65,19 @@ func (*Window) SizeLimits() (minw, minh, maxw, maxh int) {
 func (*Window) SetSizeLimits(minw, minh, maxw, maxh int) {
 }
 
+func (*Window) AspectRatioLocked() (width, height int) {
+	return 0, 0
+}
+
+func (*Window) SetAspectRatioLocked(width, height int) {
+}
+
+func (*Window) SetTaskbarProgress(state TaskbarProgressState, progress float64) {
+}
+
+func (*Window) RequestAttention() {
+}
+
 func (*Window) IsFloating() bool {
 	return false
 }
@@ -94,6 +94,7 @@ func DeviceScale() float64 {
 
 type RenderRequester interface {
 	SetExplicitRenderingMode(explicitRendering bool)
+	SetPreferredFrameRate(fps int)
 	RequestRenderIfNeeded()
 }
 
@@ -0,0 +1,63 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/ui"
+)
+
+// Monitor represents a monitor available on the system.
+//
+// The same physical monitor is represented by the same *Monitor as long as the monitor configuration doesn't change.
+type Monitor ui.Monitor
+
+// Name returns the human-readable name of the monitor.
+func (m *Monitor) Name() string {
+	return (*ui.Monitor)(m).Name()
+}
+
+// Bounds returns the monitor's bounds in device-independent pixels.
+//
+// The origin is in the virtual screen coordinate system covering all the monitors.
+// With multiple monitors with different device scale factors, the origin might not be precise.
+func (m *Monitor) Bounds() image.Rectangle {
+	return (*ui.Monitor)(m).Bounds()
+}
+
+// DeviceScaleFactor returns the device scale factor of the monitor.
+func (m *Monitor) DeviceScaleFactor() float64 {
+	return (*ui.Monitor)(m).DeviceScaleFactor()
+}
+
+// RefreshRate returns the refresh rate of the monitor in Hz.
+//
+// RefreshRate returns 0 when the refresh rate is unknown.
+func (m *Monitor) RefreshRate() int {
+	return (*ui.Monitor)(m).RefreshRate()
+}
+
+// AppendMonitors appends the monitors available on the system to monitors, and returns the extended slice.
+//
+// AppendMonitors appends nothing on browsers and mobiles.
+//
+// AppendMonitors must be called on the main thread before RunGame, and is concurrent-safe after RunGame.
+func AppendMonitors(monitors []*Monitor) []*Monitor {
+	for _, m := range ui.Get().AppendMonitors(nil) {
+		monitors = append(monitors, (*Monitor)(m))
+	}
+	return monitors
+}
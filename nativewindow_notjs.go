@@ -0,0 +1,33 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//go:build !js
+// +build !js
+
+package ebiten
+
+import (
+	"github.com/hajimehoshi/ebiten/v2/internal/ui"
+)
+
+// NativeWindow returns the handle of the native window.
+//
+// The returned value is HWND on Windows, NSWindow* on macOS, and an X11 Window on Linux and BSDs.
+// NativeWindow returns 0 on the other environments, or before the main loop starts.
+//
+// The handle is valid while the game is running. Do not destroy the window via the handle.
+//
+// NativeWindow is concurrent-safe.
+func NativeWindow() uintptr {
+	return ui.Get().NativeWindow()
+}
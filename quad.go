@@ -0,0 +1,227 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/mipmap"
+)
+
+// Quad represents a quadrilateral on a destination image.
+//
+// The points correspond to the upper-left, upper-right, lower-right and lower-left corners of a source image
+// in this order.
+type Quad struct {
+	X0, Y0 float64
+	X1, Y1 float64
+	X2, Y2 float64
+	X3, Y3 float64
+}
+
+// DrawImageQuadOptions represents options for DrawImageQuad.
+type DrawImageQuadOptions struct {
+	// ColorM is a color matrix to draw.
+	// The default (zero) value is identity, which doesn't change any color.
+	ColorM ColorM
+
+	// CompositeMode is a composite mode to draw.
+	// The default (zero) value is regular alpha blending.
+	CompositeMode CompositeMode
+
+	// Blend is a blending way of the source color and the destination color.
+	// If Blend is not the zero value, Blend is used and CompositeMode is ignored.
+	// The default (zero) value is to use CompositeMode.
+	Blend Blend
+
+	// Filter is a type of texture filter.
+	// The default (zero) value is FilterNearest.
+	Filter Filter
+
+	// FilterQuality is the quality of the filter when the image is minified.
+	// FilterQuality is used only when Filter is FilterLinear.
+	// The default (zero) value is FilterQualityDefault.
+	FilterQuality FilterQuality
+
+	// Perspective indicates whether the source image is mapped with a perspective projection.
+	//
+	// If Perspective is false, the quad is split into two triangles and each triangle is mapped linearly.
+	// This is exact when the quad is a parallelogram, but a distorted quad shows a seam along its diagonal.
+	//
+	// If Perspective is true, the source image is mapped as if it were a plane seen in perspective,
+	// which is useful for Mode 7 style floors and card-flip effects.
+	// The mapping is approximated by subdividing the quad into small cells.
+	//
+	// The default (zero) value is false.
+	Perspective bool
+}
+
+// quadPerspectiveDivision is the number of the subdivisions in each direction for a perspective quad.
+const quadPerspectiveDivision = 16
+
+// DrawImageQuad draws the given image on the image i so that the corners of img are mapped to the points of quad.
+//
+// DrawImageQuad works like DrawImage except for how the image is placed.
+// The rule in which DrawImageQuad works effectively is same as DrawImage's.
+//
+// When the image i is disposed, DrawImageQuad does nothing.
+// When the given image img is disposed, DrawImageQuad panics.
+//
+// When the given image is as same as i, DrawImageQuad panics.
+func (i *Image) DrawImageQuad(img *Image, quad Quad, options *DrawImageQuadOptions) {
+	i.copyCheck()
+
+	if img.isDisposed() {
+		panic("ebiten: the given image to DrawImageQuad must not be disposed")
+	}
+	if i.isDisposed() {
+		return
+	}
+
+	dstBounds := i.Bounds()
+	dstRegion := graphicsdriver.Region{
+		X:      float32(dstBounds.Min.X),
+		Y:      float32(dstBounds.Min.Y),
+		Width:  float32(dstBounds.Dx()),
+		Height: float32(dstBounds.Dy()),
+	}
+
+	if options == nil {
+		options = &DrawImageQuadOptions{}
+	}
+
+	bounds := img.Bounds()
+	mode := internalBlend(options.CompositeMode, options.Blend)
+	filter := graphicsdriver.Filter(options.Filter)
+
+	n := 1
+	if options.Perspective {
+		n = quadPerspectiveDivision
+	}
+	h := newHomography(quad)
+
+	sx0 := float64(bounds.Min.X)
+	sy0 := float64(bounds.Min.Y)
+	sw := float64(bounds.Dx())
+	sh := float64(bounds.Dy())
+
+	vs := graphics.Vertices((n + 1) * (n + 1))
+	for y := 0; y <= n; y++ {
+		v := float64(y) / float64(n)
+		for x := 0; x <= n; x++ {
+			u := float64(x) / float64(n)
+			var dx, dy float64
+			if options.Perspective {
+				dx, dy = h.apply(u, v)
+			} else {
+				dx, dy = quad.corner(x, y)
+			}
+			idx := (y*(n+1) + x) * graphics.VertexFloatNum
+			vs[idx] = float32(dx)
+			vs[idx+1] = float32(dy)
+			vs[idx+2] = float32(sx0 + u*sw)
+			vs[idx+3] = float32(sy0 + v*sh)
+			vs[idx+4] = 1
+			vs[idx+5] = 1
+			vs[idx+6] = 1
+			vs[idx+7] = 1
+		}
+	}
+
+	is := make([]uint16, 0, 6*n*n)
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			i0 := uint16(y*(n+1) + x)
+			i1 := i0 + 1
+			i2 := i0 + uint16(n+1)
+			i3 := i2 + 1
+			is = append(is, i0, i1, i2, i1, i2, i3)
+		}
+	}
+
+	srcs := [graphics.ShaderImageNum]*mipmap.Mipmap{img.mipmap}
+
+	i.mipmap.DrawTriangles(srcs, vs, is, options.ColorM.affineColorM(), mode, filter, mipmap.FilterQuality(options.FilterQuality), graphicsdriver.AddressUnsafe, dstRegion, graphicsdriver.Region{}, [graphics.ShaderImageNum - 1][2]float32{}, nil, nil, graphicsdriver.FillAll, false)
+}
+
+// homography maps a point (u, v) in the unit square to a point on a quad.
+//
+//	x = (a*u + b*v + c) / (g*u + h*v + 1)
+//	y = (d*u + e*v + f) / (g*u + h*v + 1)
+type homography struct {
+	a, b, c float64
+	d, e, f float64
+	g, h    float64
+}
+
+// newHomography returns a homography from the unit square to quad.
+//
+// If quad is a parallelogram or is degenerated, the returned homography is affine.
+func newHomography(quad Quad) homography {
+	affine := homography{
+		a: quad.X1 - quad.X0,
+		b: quad.X3 - quad.X0,
+		c: quad.X0,
+		d: quad.Y1 - quad.Y0,
+		e: quad.Y3 - quad.Y0,
+		f: quad.Y0,
+	}
+
+	dx3 := quad.X0 - quad.X1 + quad.X2 - quad.X3
+	dy3 := quad.Y0 - quad.Y1 + quad.Y2 - quad.Y3
+	if dx3 == 0 && dy3 == 0 {
+		return affine
+	}
+
+	dx1 := quad.X1 - quad.X2
+	dy1 := quad.Y1 - quad.Y2
+	dx2 := quad.X3 - quad.X2
+	dy2 := quad.Y3 - quad.Y2
+	den := dx1*dy2 - dx2*dy1
+	if den == 0 {
+		return affine
+	}
+	g := (dx3*dy2 - dx2*dy3) / den
+	h := (dx1*dy3 - dx3*dy1) / den
+	return homography{
+		a: quad.X1 - quad.X0 + g*quad.X1,
+		b: quad.X3 - quad.X0 + h*quad.X3,
+		c: quad.X0,
+		d: quad.Y1 - quad.Y0 + g*quad.Y1,
+		e: quad.Y3 - quad.Y0 + h*quad.Y3,
+		f: quad.Y0,
+		g: g,
+		h: h,
+	}
+}
+
+// corner returns the point of the quad's corner at (x, y), where x and y are 0 or 1.
+func (q Quad) corner(x, y int) (float64, float64) {
+	switch {
+	case x == 0 && y == 0:
+		return q.X0, q.Y0
+	case x == 1 && y == 0:
+		return q.X1, q.Y1
+	case x == 1 && y == 1:
+		return q.X2, q.Y2
+	default:
+		return q.X3, q.Y3
+	}
+}
+
+func (h homography) apply(u, v float64) (float64, float64) {
+	w := h.g*u + h.h*v + 1
+	return (h.a*u + h.b*v + h.c) / w, (h.d*u + h.e*v + h.f) / w
+}
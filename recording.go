@@ -0,0 +1,188 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"errors"
+	"image"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+)
+
+// FrameEncoder encodes frames recorded by StartRecording.
+//
+// The functions of FrameEncoder are called on a goroutine other than the game's goroutine.
+// See the package ebitenutil for FrameEncoder implementations, e.g., ebitenutil.Recorder to keep the recent frames
+// as a short clip.
+type FrameEncoder interface {
+	// EncodeFrame encodes a frame.
+	// t is the elapsed time from the start of the recording to the frame.
+	// img is not used by the recorder after EncodeFrame returns, and EncodeFrame can keep img.
+	EncodeFrame(img *image.RGBA, t time.Duration) error
+
+	// Close finishes the encoding.
+	Close() error
+}
+
+// recordingQueueSize is the maximum number of frames waiting for being encoded.
+const recordingQueueSize = 60
+
+type recordedFrame struct {
+	image *image.RGBA
+	time  time.Duration
+}
+
+type recording struct {
+	frames chan recordedFrame
+	start  time.Time
+	done   chan error
+}
+
+var (
+	theRecording  *recording
+	theRecordingM sync.Mutex
+)
+
+// StartRecording starts recording the window's content every frame, and encodes the frames with encoder.
+//
+// The frames are the same as what Screenshot returns. The frames are read from GPU asynchronously and
+// encoded on another goroutine so that the recording doesn't block the game loop.
+// If encoder is slower than the game, some frames are dropped.
+//
+// StartRecording returns an error if a recording has already started.
+//
+// StartRecording is concurrent-safe.
+func StartRecording(encoder FrameEncoder) error {
+	theRecordingM.Lock()
+	defer theRecordingM.Unlock()
+
+	if theRecording != nil {
+		return errors.New("ebiten: a recording has already started")
+	}
+
+	r := &recording{
+		frames: make(chan recordedFrame, recordingQueueSize),
+		start:  time.Now(),
+		done:   make(chan error, 1),
+	}
+	go func() {
+		defer close(r.done)
+
+		var err error
+		for f := range r.frames {
+			// After an error, discard the rest of the frames.
+			if err != nil {
+				continue
+			}
+			err = encoder.EncodeFrame(f.image, f.time)
+		}
+		if cerr := encoder.Close(); err == nil {
+			err = cerr
+		}
+		r.done <- err
+	}()
+	theRecording = r
+	return nil
+}
+
+// StopRecording stops the current recording.
+//
+// StopRecording returns a channel that receives the result of the encoding when the encoder finishes.
+// If no recording has started, the channel receives an error.
+//
+// StopRecording is concurrent-safe.
+func StopRecording() <-chan error {
+	theRecordingM.Lock()
+	defer theRecordingM.Unlock()
+
+	if theRecording == nil {
+		ch := make(chan error, 1)
+		ch <- errors.New("ebiten: no recording has started")
+		close(ch)
+		return ch
+	}
+
+	r := theRecording
+	theRecording = nil
+	close(r.frames)
+	return r.done
+}
+
+// recordFrame records the final screen if a recording has started.
+//
+// recordFrame must be called from the game's goroutine.
+func (c *gameForUI) recordFrame() {
+	theRecordingM.Lock()
+	r := theRecording
+	theRecordingM.Unlock()
+
+	if r == nil {
+		if c.recordingBuffer != nil {
+			c.recordingBuffer.Dispose()
+			c.recordingBuffer = nil
+		}
+		return
+	}
+
+	w, h := c.screen.Size()
+	if c.recordingBuffer != nil {
+		if bw, bh := c.recordingBuffer.Size(); bw != w || bh != h {
+			c.recordingBuffer.Dispose()
+			c.recordingBuffer = nil
+		}
+	}
+	if c.recordingBuffer == nil {
+		c.recordingBuffer = NewImage(w, h)
+	}
+	buf := c.recordingBuffer
+	buf.Clear()
+	c.drawFinalScreen(buf, graphicsdriver.Downward)
+
+	t := time.Since(r.start)
+	opaque := !IsScreenTransparent()
+	buf.ReadPixelsAsync(buf.Bounds(), func(pix []byte) {
+		rgba := &image.RGBA{
+			Pix:    pix,
+			Stride: 4 * w,
+			Rect:   image.Rect(0, 0, w, h),
+		}
+		if opaque {
+			fillAlpha(rgba.Pix)
+		}
+
+		theRecordingM.Lock()
+		defer theRecordingM.Unlock()
+
+		// The recording might have been stopped or restarted.
+		if theRecording != r {
+			return
+		}
+		select {
+		case r.frames <- recordedFrame{image: rgba, time: t}:
+		default:
+			// The encoder is too slow. Drop this frame.
+		}
+	})
+}
+
+// fillAlpha makes all the pixels opaque as if they are rendered on a black background.
+// As the pixels have premultiplied alpha values, the color values don't have to be changed.
+func fillAlpha(pix []byte) {
+	for i := 3; i < len(pix); i += 4 {
+		pix[i] = 0xff
+	}
+}
@@ -15,9 +15,14 @@
 package ebiten
 
 import (
+	"context"
+	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/hajimehoshi/ebiten/v2/internal/atlas"
 	"github.com/hajimehoshi/ebiten/v2/internal/clock"
+	"github.com/hajimehoshi/ebiten/v2/internal/mipmap"
 	"github.com/hajimehoshi/ebiten/v2/internal/ui"
 )
 
@@ -59,6 +64,35 @@ type Game interface {
 	Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int)
 }
 
+// SuspendHandler is an optional interface for Game to be notified when the game is suspended and resumed.
+//
+// The game is suspended when the app goes to the background on mobiles, when the tab is hidden on browsers,
+// and when the window is minimized on desktops. The game is also suspended when the window or the document is
+// unfocused and the game is not runnable on unfocused (see SetRunnableOnUnfocused).
+// OnSuspend is a good place to pause the game and save its state.
+//
+// Note that the game might still be updated while being suspended, e.g., when a window is minimized and the game
+// is runnable on unfocused.
+//
+// The functions are never called concurrently with Game's Update and Draw.
+type SuspendHandler interface {
+	// OnSuspend is called when the game is suspended.
+	OnSuspend()
+
+	// OnResume is called when the game is resumed.
+	OnResume()
+}
+
+// FocusHandler is an optional interface for Game to be notified when the focus of the window is changed.
+//
+// On mobiles, the app is focused while the app is in the foreground.
+//
+// OnFocusChanged is never called concurrently with Game's Update and Draw.
+type FocusHandler interface {
+	// OnFocusChanged is called when the window gains or loses the focus.
+	OnFocusChanged(focused bool)
+}
+
 // DefaultTPS represents a default ticks per second, that represents how many times game updating happens in a second.
 const DefaultTPS = ui.DefaultTPS
 
@@ -75,6 +109,92 @@ func CurrentFPS() float64 {
 	return clock.CurrentFPS()
 }
 
+// FrameStatistics represents statistics of frames for frame-pacing analysis.
+type FrameStatistics struct {
+	// FrameCount is the number of frames since the game started.
+	FrameCount uint64
+
+	// LastFrameDuration is the duration between the last two frames.
+	LastFrameDuration time.Duration
+
+	// MaxFrameDuration is the maximum duration between two consecutive frames in the last one-second period.
+	// A MaxFrameDuration much longer than the display's refresh interval indicates a frame-pacing glitch.
+	MaxFrameDuration time.Duration
+
+	// GPUFrameDuration is the time spent by GPU to render a frame.
+	// As the GPU time is read asynchronously not to stall the rendering, this is of a few frames before.
+	//
+	// GPUFrameDuration is 0 if the graphics library cannot measure the GPU time.
+	// Currently, GPUFrameDuration is available only with OpenGL on desktops and Metal.
+	GPUFrameDuration time.Duration
+}
+
+// ReadFrameStatistics writes the current statistics of frames into stats.
+//
+// The statistics except for GPUFrameDuration are measured on the CPU side at the beginning of each frame, and
+// don't include the timing information of the GPU or the display.
+//
+// This value is for measurement and/or debug, and your game logic should not rely on this value.
+//
+// ReadFrameStatistics is concurrent-safe.
+func ReadFrameStatistics(stats *FrameStatistics) {
+	stats.FrameCount, stats.LastFrameDuration, stats.MaxFrameDuration = clock.FrameStatistics()
+	stats.GPUFrameDuration = ui.GPUFrameDuration()
+}
+
+// DrawStatistics represents statistics of draw commands in a frame for batching analysis.
+//
+// Ebiten merges consecutive draw operations into one draw call when they can be batched, e.g., when they use
+// the same destination, the same source atlas, the same shader and the same blend.
+// If DrawCalls is close to DrawRequests, the draw order might break batching.
+type DrawStatistics struct {
+	// DrawRequests is the number of the requested draw operations, including DrawImage, DrawTriangles,
+	// DrawRectShader, DrawTrianglesShader and the internal operations like moving images between atlases.
+	DrawRequests int
+
+	// DrawCalls is the number of the draw calls issued to the graphics library after merging the requests.
+	DrawCalls int
+
+	// PipelineSwitches is the number of the times when the shader or the blend changed between
+	// two consecutive draw calls.
+	PipelineSwitches int
+
+	// Vertices is the number of the vertices submitted to the graphics library.
+	Vertices int
+
+	// AtlasReallocations is the number of the times when a texture atlas was created or extended.
+	AtlasReallocations int
+}
+
+// ReadDrawStatistics writes the statistics of draw commands in the last frame into stats.
+//
+// This value is for measurement and/or debug, and your game logic should not rely on this value.
+//
+// ReadDrawStatistics is concurrent-safe.
+func ReadDrawStatistics(stats *DrawStatistics) {
+	s := ui.LastFrameStats()
+	stats.DrawRequests = s.DrawRequests
+	stats.DrawCalls = s.DrawCalls
+	stats.PipelineSwitches = s.PipelineSwitches
+	stats.Vertices = s.Vertices
+	stats.AtlasReallocations = atlas.LastFrameReallocations()
+}
+
+// DebugMarker groups the image operations in f with the given name for graphics debugging tools.
+//
+// With a graphics debugger like RenderDoc, the rendering commands in a capture are shown in a named region, and
+// it is easier to find the commands for each part of your game. DebugMarker can be nested.
+//
+// DebugMarker is effective only with OpenGL that supports KHR_debug (OpenGL 4.3) so far.
+// Otherwise, DebugMarker just calls f.
+//
+// DebugMarker is for debugging, and doesn't affect the rendering result.
+func DebugMarker(name string, f func()) {
+	mipmap.PushDebugGroup(name)
+	defer mipmap.PopDebugGroup()
+	f()
+}
+
 var (
 	isRunGameEnded_ = int32(0)
 )
@@ -94,6 +214,44 @@ func IsScreenClearedEveryFrame() bool {
 	return ui.IsScreenClearedEveryFrame()
 }
 
+var (
+	screenShaderM        sync.Mutex
+	screenShader         *Shader
+	screenShaderUniforms map[string]interface{}
+)
+
+// SetScreenShader sets the shader to render the offscreen onto the window.
+//
+// The offscreen is the image passed to Game.Draw. The shader is used instead of the default filter when the
+// offscreen is rendered onto the window at the end of each frame, and runs for each pixel of the window at
+// the native resolution.
+// This is useful for post effects like CRT filters, color-blindness correction and gamma adjustment.
+//
+// In the shader, the offscreen is the source image 0, and uniforms are the uniform variables.
+// The rules of uniforms are the same as DrawRectShaderOptions.Uniforms.
+// The composite mode is CompositeModeCopy, so the shader's alpha values are written to the window as they are.
+//
+// If shader is nil, the default filter is used. The default is nil.
+//
+// SetScreenShader is concurrent-safe.
+func SetScreenShader(shader *Shader, uniforms map[string]interface{}) {
+	us := make(map[string]interface{}, len(uniforms))
+	for k, v := range uniforms {
+		us[k] = v
+	}
+
+	screenShaderM.Lock()
+	defer screenShaderM.Unlock()
+	screenShader = shader
+	screenShaderUniforms = us
+}
+
+func currentScreenShader() (*Shader, map[string]interface{}) {
+	screenShaderM.Lock()
+	defer screenShaderM.Unlock()
+	return screenShader, screenShaderUniforms
+}
+
 type imageDumperGame struct {
 	game Game
 	d    *imageDumper
@@ -123,6 +281,36 @@ func (i *imageDumperGame) Layout(outsideWidth, outsideHeight int) (screenWidth,
 	return i.game.Layout(outsideWidth, outsideHeight)
 }
 
+func (i *imageDumperGame) OnSuspend() {
+	if h, ok := i.game.(SuspendHandler); ok {
+		h.OnSuspend()
+	}
+}
+
+func (i *imageDumperGame) OnResume() {
+	if h, ok := i.game.(SuspendHandler); ok {
+		h.OnResume()
+	}
+}
+
+func (i *imageDumperGame) OnFocusChanged(focused bool) {
+	if h, ok := i.game.(FocusHandler); ok {
+		h.OnFocusChanged(focused)
+	}
+}
+
+func (i *imageDumperGame) OnWindowResized(width, height int) {
+	if h, ok := i.game.(WindowResizeHandler); ok {
+		h.OnWindowResized(width, height)
+	}
+}
+
+func (i *imageDumperGame) OnWindowMaximizedChanged(maximized bool) {
+	if h, ok := i.game.(WindowMaximizeHandler); ok {
+		h.OnWindowMaximizedChanged(maximized)
+	}
+}
+
 // RunGame starts the main loop and runs the game.
 // game's Update function is called every tick to update the game logic.
 // game's Draw function is called every frame to draw the screen.
@@ -146,12 +334,115 @@ func (i *imageDumperGame) Layout(outsideWidth, outsideHeight int) (screenWidth,
 //
 // Don't call RunGame twice or more in one process.
 func RunGame(game Game) error {
+	return RunGameWithOptions(game, nil)
+}
+
+// GraphicsLibrary represents graphics libraries supported by the engine.
+type GraphicsLibrary = ui.GraphicsLibrary
+
+const (
+	// GraphicsLibraryAuto represents the automatic choice of a graphics library by Ebiten.
+	GraphicsLibraryAuto GraphicsLibrary = ui.GraphicsLibraryAuto
+
+	// GraphicsLibraryOpenGL represents OpenGL, OpenGL ES or WebGL.
+	GraphicsLibraryOpenGL GraphicsLibrary = ui.GraphicsLibraryOpenGL
+
+	// GraphicsLibraryMetal represents Metal.
+	// Metal is available only on macOS and iOS.
+	GraphicsLibraryMetal GraphicsLibrary = ui.GraphicsLibraryMetal
+)
+
+// RunGameOptions represents options for RunGameWithOptions.
+type RunGameOptions struct {
+	// GraphicsLibrary is a graphics library Ebiten will use.
+	//
+	// The default (zero) value is GraphicsLibraryAuto, which lets Ebiten choose the graphics library.
+	//
+	// On macOS, if GraphicsLibraryMetal is specified but Metal is not supported on the machine,
+	// Ebiten falls back to OpenGL.
+	GraphicsLibrary GraphicsLibrary
+
+	// Headless represents whether the game runs without showing a window.
+	//
+	// In the headless mode, Update and Draw are called as usual, but nothing is shown on the display.
+	// The image passed to Draw is an offscreen image, and its pixels can be read with ReadPixels or At
+	// e.g. to verify the rendering results in automated tests.
+	// The window size specified by SetWindowSize is used as the outside size passed to Layout.
+	// Vsync is always disabled, and the game is never treated as unfocused.
+	// There is no user input.
+	//
+	// On desktops, a graphics context still requires a display server like X11, and an invisible window
+	// is created for the context.
+	// On browsers and mobiles, the headless mode is not available and RunGameWithOptions returns an error.
+	//
+	// The default (zero) value is false.
+	Headless bool
+}
+
+// RunGameWithOptions starts the main loop and runs the game with the specified options.
+// game's Update function is called every tick to update the game logic.
+// game's Draw function is called every frame to draw the screen.
+// game's Layout function is called when necessary, and you can specify the logical screen size by the function.
+//
+// options can be nil. In this case, the default options are used.
+//
+// RunGameWithOptions returns an error immediately when the specified graphics library or the headless mode
+// is not available on the platform.
+//
+// The other behaviors are the same as RunGame.
+//
+// Don't call RunGameWithOptions twice or more in one process.
+func RunGameWithOptions(game Game, options *RunGameOptions) error {
+	return runGame(context.Background(), game, options)
+}
+
+// RunGameWithContext starts the main loop and runs the game like RunGame, and stops the main loop when ctx is
+// canceled.
+//
+// When ctx is canceled, the game's Update is no longer called, the main loop ends, and RunGameWithContext
+// returns nil. This is useful for tools and servers embedding a game that need to stop the game
+// programmatically without returning an error from Update.
+// Note that the cancellation is checked only when the game is updated. For example, if the window is unfocused
+// and the game is not runnable on unfocused, the main loop ends after the window is focused again.
+//
+// The other behaviors are the same as RunGame.
+//
+// Don't call RunGameWithContext twice or more in one process.
+func RunGameWithContext(ctx context.Context, game Game) error {
+	return runGame(ctx, game, nil)
+}
+
+func runGame(ctx context.Context, game Game, options *RunGameOptions) error {
 	defer atomic.StoreInt32(&isRunGameEnded_, 1)
 
+	if options != nil {
+		if err := ui.SetGraphicsLibrary(options.GraphicsLibrary); err != nil {
+			return err
+		}
+		if err := ui.Get().SetHeadless(options.Headless); err != nil {
+			return err
+		}
+	}
+
 	initializeWindowPositionIfNeeded(WindowSize())
 	g := newGameForUI(&imageDumperGame{
 		game: game,
 	})
+	g.ctx = ctx
+
+	// Wake up the main loop, which might be waiting for events, to notice the cancellation.
+	if done := ctx.Done(); done != nil {
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			select {
+			case <-done:
+				ui.Get().ScheduleFrame()
+			case <-stop:
+			}
+		}()
+	}
+
 	if err := ui.Get().Run(g); err != nil {
 		if err == ui.RegularTermination {
 			return nil
@@ -161,6 +452,16 @@ func RunGame(game Game) error {
 	return nil
 }
 
+// ActiveGraphicsLibrary returns the graphics library in use.
+//
+// ActiveGraphicsLibrary might return GraphicsLibraryAuto when the graphics library is not determined yet,
+// e.g., before RunGame is called.
+//
+// ActiveGraphicsLibrary is concurrent-safe.
+func ActiveGraphicsLibrary() GraphicsLibrary {
+	return ui.ActiveGraphicsLibrary()
+}
+
 func isRunGameEnded() bool {
 	return atomic.LoadInt32(&isRunGameEnded_) != 0
 }
@@ -220,8 +521,11 @@ func CursorShape() CursorShapeType {
 
 // SetCursorShape sets the cursor shape.
 //
+// SetCursorShape reverts the cursor image set by SetCursorImage.
+//
 // SetCursorShape is concurrent-safe.
 func SetCursorShape(shape CursorShapeType) {
+	atomic.AddUint64(&cursorImageGeneration, 1)
 	ui.Get().SetCursorShape(shape)
 }
 
@@ -293,6 +597,9 @@ func SetRunnableOnUnfocused(runnableOnUnfocused bool) {
 // DeviceScaleFactor returns a meaningful value on high-DPI display environment,
 // otherwise DeviceScaleFactor returns 1.
 //
+// On desktops, the value can change while the game is running, e.g., when the window is moved to another monitor
+// with a different DPI, or when the scale setting of the monitor is changed.
+//
 // DeviceScaleFactor might panic on init function on some devices like Android.
 // Then, it is not recommended to call DeviceScaleFactor from init functions.
 //
@@ -326,6 +633,46 @@ func SetVsyncEnabled(enabled bool) {
 	}
 }
 
+// ScreenScaleModeType is a type of the modes how the screen is scaled to the window.
+type ScreenScaleModeType = ui.ScreenScaleModeType
+
+const (
+	// ScreenScaleModeFit scales the screen as large as possible with keeping the aspect ratio.
+	// The screen is letterboxed when the aspect ratios of the screen and the window differ.
+	// This is the default mode.
+	ScreenScaleModeFit ScreenScaleModeType = ui.ScreenScaleModeFit
+
+	// ScreenScaleModeInteger scales the screen with the largest integer scale that fits the window,
+	// and letterboxes the screen. The screen is aligned with the window's pixels.
+	// This is useful for pixel-art games to get pixel-perfect results.
+	//
+	// If the window is smaller than the screen, the screen is scaled down in the same way as ScreenScaleModeFit.
+	ScreenScaleModeInteger ScreenScaleModeType = ui.ScreenScaleModeInteger
+
+	// ScreenScaleModeStretch scales the screen to fill the whole window without keeping the aspect ratio.
+	ScreenScaleModeStretch ScreenScaleModeType = ui.ScreenScaleModeStretch
+)
+
+// ScreenScaleMode returns the current mode how the screen is scaled to the window.
+//
+// ScreenScaleMode is concurrent-safe.
+func ScreenScaleMode() ScreenScaleModeType {
+	return ui.ScreenScaleMode()
+}
+
+// SetScreenScaleMode sets the mode how the screen is scaled to the window.
+//
+// The screen is the image passed to Game.Draw, and its size is determined by Game.Layout.
+// The scaling is applied when the screen is rendered onto the window, and cursor and touch positions are
+// adjusted accordingly.
+//
+// The default mode is ScreenScaleModeFit.
+//
+// SetScreenScaleMode is concurrent-safe.
+func SetScreenScaleMode(mode ScreenScaleModeType) {
+	ui.SetScreenScaleMode(mode)
+}
+
 // FPSModeType is a type of FPS modes.
 type FPSModeType = ui.FPSModeType
 
@@ -369,6 +716,58 @@ func SetFPSMode(mode FPSModeType) {
 	ui.SetFPSMode(mode)
 }
 
+// VsyncInterval returns the number of vertical blanks per frame in FPSModeVsyncOn.
+//
+// VsyncInterval is concurrent-safe.
+func VsyncInterval() int {
+	return ui.VsyncInterval()
+}
+
+// SetVsyncInterval sets the number of vertical blanks per frame in FPSModeVsyncOn.
+// The default value is 1, which presents a frame at every vertical blank.
+//
+// For example, with 2, a frame is presented at every second vertical blank, i.e., 30 FPS on a 60 Hz display.
+// This is useful to make a game locked to a lower FPS have even frame pacing.
+// TPS is not affected by the interval.
+//
+// The interval is used only in FPSModeVsyncOn.
+// The interval is available on desktops and browsers, and is ignored on mobiles.
+// Some graphics drivers might ignore the interval.
+//
+// If interval is less than 1, SetVsyncInterval panics.
+//
+// SetVsyncInterval is concurrent-safe.
+func SetVsyncInterval(interval int) {
+	ui.SetVsyncInterval(interval)
+}
+
+// PreferredFPS returns the preferred FPS specified by SetPreferredFPS.
+//
+// PreferredFPS is concurrent-safe.
+func PreferredFPS() int {
+	return ui.PreferredFPS()
+}
+
+// SetPreferredFPS sets the FPS hint for the display.
+// The default value is 0, which means that the frames are presented at the refresh rate of the display.
+//
+// On displays with a high or variable refresh rate like ProMotion, SetPreferredFPS tells the system the frame
+// rate the game wants.
+// On desktops, frames are presented at every n-th vertical blank so that FPS doesn't exceed the preferred FPS,
+// e.g., 60 FPS on a 120 Hz display. This works together with SetVsyncInterval, and the larger interval is used.
+// On iOS, the value is passed to the display link. To use more than 60 Hz on ProMotion iPhones,
+// CADisableMinimumFrameDurationOnPhone must be true in Info.plist.
+// On browsers and Android, the preferred FPS is ignored so far.
+//
+// The preferred FPS is used only in FPSModeVsyncOn. TPS is not affected by the preferred FPS.
+//
+// If fps is negative, SetPreferredFPS panics.
+//
+// SetPreferredFPS is concurrent-safe.
+func SetPreferredFPS(fps int) {
+	ui.SetPreferredFPS(fps)
+}
+
 // ScheduleFrame schedules a next frame when the current FPS mode is FPSModeVsyncOffMinimum.
 //
 // ScheduleFrame is concurrent-safe.
@@ -393,6 +792,22 @@ func CurrentTPS() float64 {
 	return clock.CurrentTPS()
 }
 
+// DeltaTime returns the duration of the current tick, that is the time that the current Update should advance
+// the game by.
+//
+// If TPS is SyncWithFPS, DeltaTime returns the actual elapsed time between the previous Update and the current
+// Update, measured with the monotonic clock. This is useful for variable-timestep games.
+// DeltaTime returns 0 at the first Update.
+// Otherwise, DeltaTime returns 1/TPS as Update is called TPS times a second.
+//
+// In the deterministic mode, DeltaTime always returns 1/TPS, or 1/DefaultTPS if TPS is SyncWithFPS, so that
+// the game proceeds in the same way regardless of the elapsed time.
+//
+// DeltaTime is concurrent-safe.
+func DeltaTime() time.Duration {
+	return ui.DeltaTime()
+}
+
 // SyncWithFPS is a special TPS value that means TPS syncs with FPS.
 const SyncWithFPS = clock.SyncWithFPS
 
@@ -413,6 +828,54 @@ func SetMaxTPS(tps int) {
 	ui.SetMaxTPS(tps)
 }
 
+// SetDeterministic enables or disables the deterministic mode.
+//
+// In the deterministic mode, Update is called exactly once per frame regardless of the elapsed time, so that
+// the game proceeds in the same way with the same inputs. This is useful for lockstep netcode and replay-based
+// regression tests together with FrameHash.
+// Note that Ebiten doesn't use random numbers internally. Use fixed seeds for random numbers in your game.
+//
+// The default value is false.
+//
+// SetDeterministic is concurrent-safe.
+func SetDeterministic(deterministic bool) {
+	ui.SetDeterministic(deterministic)
+}
+
+// IsDeterministic reports whether the deterministic mode is enabled.
+//
+// IsDeterministic is concurrent-safe.
+func IsDeterministic() bool {
+	return ui.IsDeterministic()
+}
+
+// FrameHash returns a hash of the image operations in the last frame.
+//
+// The hash is calculated from the image creations, the disposals, the pixel replacements and the draw calls
+// with their arguments, in the order they are called.
+// The operations for rendering the screen onto the window are not included, so the hash doesn't depend on
+// the window size or the platform.
+// Images and shaders are identified with the order of their creation.
+//
+// Two runs of a deterministic game with the same inputs should have the same sequence of FrameHash values.
+// FrameHash doesn't read any pixels from GPU, so the hash doesn't reflect the GPU's rendering differences.
+//
+// FrameHash is concurrent-safe.
+func FrameHash() uint64 {
+	return mipmap.FrameHash()
+}
+
+// IsSystemDarkMode reports whether the user's system theme is dark.
+//
+// On Windows, the title bar of the window follows the system theme.
+// On browsers, IsSystemDarkMode reports whether the 'prefers-color-scheme' media query matches 'dark'.
+// On Linux/Unix and mobiles, IsSystemDarkMode always returns false so far.
+//
+// IsSystemDarkMode is concurrent-safe.
+func IsSystemDarkMode() bool {
+	return ui.Get().IsSystemDarkMode()
+}
+
 // IsScreenTransparent reports whether the window is transparent.
 //
 // IsScreenTransparent is concurrent-safe.
@@ -0,0 +1,36 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"errors"
+	"image"
+)
+
+// Screenshot returns the window's content as it is rendered at the latest frame.
+//
+// The result is in the window's native resolution and includes the effect of the screen filter or the screen shader
+// set by SetScreenShader.
+// If Screenshot is called in Draw, the result includes only what is drawn so far in the frame.
+//
+// Screenshot loads pixels from GPU to system memory, which means that Screenshot can be slow.
+//
+// Screenshot must be called from Update or Draw.
+func Screenshot() (*image.RGBA, error) {
+	if theGameForUI == nil {
+		return nil, errors.New("ebiten: Screenshot must be called after the game starts")
+	}
+	return theGameForUI.screenshot()
+}
@@ -15,109 +15,15 @@
 package ebiten
 
 import (
-	"bytes"
 	"fmt"
-	"go/parser"
-	"go/token"
 	"strings"
 
-	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/mipmap"
 	"github.com/hajimehoshi/ebiten/v2/internal/shader"
 	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
 )
 
-var shaderSuffix string
-
-func init() {
-	shaderSuffix = `
-var __imageDstTextureSize vec2
-
-// imageSrcTextureSize returns the destination image's texture size in pixels.
-func imageDstTextureSize() vec2 {
-	return __imageDstTextureSize
-}
-`
-
-	shaderSuffix += fmt.Sprintf(`
-var __textureSizes [%[1]d]vec2
-
-// imageSrcTextureSize returns the source image's texture size in pixels.
-// As an image is a part of internal texture, the texture is usually bigger than the image.
-// The texture's size is useful when you want to calculate pixels from texels.
-func imageSrcTextureSize() vec2 {
-	return __textureSizes[0]
-}
-
-// The unit is the source texture's texel.
-var __textureDestinationRegionOrigin vec2
-
-// The unit is the source texture's texel.
-var __textureDestinationRegionSize vec2
-
-// imageDstRegionOnTexture returns the destination image's region (the origin and the size) on its texture.
-// The unit is the source texture's texel.
-//
-// As an image is a part of internal texture, the image can be located at an arbitrary position on the texture.
-func imageDstRegionOnTexture() (vec2, vec2) {
-	return __textureDestinationRegionOrigin, __textureDestinationRegionSize
-}
-
-// The unit is the source texture's texel.
-var __textureSourceOffsets [%[2]d]vec2
-
-// The unit is the source texture's texel.
-var __textureSourceRegionOrigin vec2
-
-// The unit is the source texture's texel.
-var __textureSourceRegionSize vec2
-
-// imageSrcRegionOnTexture returns the source image's region (the origin and the size) on its texture.
-// The unit is the source texture's texel.
-//
-// As an image is a part of internal texture, the image can be located at an arbitrary position on the texture.
-func imageSrcRegionOnTexture() (vec2, vec2) {
-	return __textureSourceRegionOrigin, __textureSourceRegionSize
-}
-`, graphics.ShaderImageNum, graphics.ShaderImageNum-1)
-
-	for i := 0; i < graphics.ShaderImageNum; i++ {
-		pos := "pos"
-		if i >= 1 {
-			// Convert the position in texture0's texels to the target texture texels.
-			pos = fmt.Sprintf("(pos + __textureSourceOffsets[%d]) * __textureSizes[0] / __textureSizes[%d]", i-1, i)
-		}
-		// __t%d is a special variable for a texture variable.
-		shaderSuffix += fmt.Sprintf(`
-func imageSrc%[1]dUnsafeAt(pos vec2) vec4 {
-	// pos is the position in texels of the source texture (= 0th image's texture).
-	return texture2D(__t%[1]d, %[2]s)
-}
-
-func imageSrc%[1]dAt(pos vec2) vec4 {
-	// pos is the position in texels of the source texture (= 0th image's texture).
-	return texture2D(__t%[1]d, %[2]s) *
-		step(__textureSourceRegionOrigin.x, pos.x) *
-		(1 - step(__textureSourceRegionOrigin.x + __textureSourceRegionSize.x, pos.x)) *
-		step(__textureSourceRegionOrigin.y, pos.y) *
-		(1 - step(__textureSourceRegionOrigin.y + __textureSourceRegionSize.y, pos.y))
-}
-`, i, pos)
-	}
-
-	shaderSuffix += `
-func __vertex(position vec2, texCoord vec2, color vec4) (vec4, vec2, vec4) {
-	return mat4(
-		2/__imageDstTextureSize.x, 0, 0, 0,
-		0, 2/__imageDstTextureSize.y, 0, 0,
-		0, 0, 1, 0,
-		-1, -1, 0, 1,
-	) * vec4(position, 0, 1), texCoord, color
-}
-`
-}
-
 // Shader represents a compiled shader program.
 //
 // For the details about the shader, see https://ebiten.org/documents/shader.html.
@@ -125,45 +31,143 @@ type Shader struct {
 	shader       *mipmap.Shader
 	uniformNames []string
 	uniformTypes []shaderir.Type
+	colorsCount  int
 }
 
 // NewShader compiles a shader program in the shading language Kage, and retruns the result.
 //
 // If the compilation fails, NewShader returns an error.
+// If the source is invalid, the error is a *ShaderError with the details.
+//
+// A shader can have the vertex entry point Vertex in addition to the fragment entry point Fragment:
+//
+//	func Vertex(position vec2, texCoord vec2, color vec4) (vec4, vec2, vec4)
+//
+// The parameters are a vertex's position in pixels on the destination texture, its texture coordinate in texels
+// on the source texture, and its color.
+// The first returning value is the position in the clip space, which imageDstClipPosition can calculate.
+// The other returning values are passed to Fragment's parameters after the position, so their types must match.
+// Vertex is useful to transform vertices on GPU, e.g., to deform a mesh by time, instead of rewriting the vertices
+// on CPU every frame.
+// If Vertex is not declared, the vertices are passed to Fragment as they are.
 //
 // For the details about the shader, see https://ebiten.org/documents/shader.html.
 func NewShader(src []byte) (*Shader, error) {
-	var buf bytes.Buffer
-	buf.Write(src)
-	buf.WriteString(shaderSuffix)
-
-	fs := token.NewFileSet()
-	f, err := parser.ParseFile(fs, "", buf.Bytes(), parser.AllErrors)
+	ir, err := compileShader(src)
 	if err != nil {
 		return nil, err
 	}
+	return newShader(mipmap.NewShader(ir), ir), nil
+}
 
-	const (
-		vert = "__vertex"
-		frag = "Fragment"
-	)
-	s, err := shader.Compile(fs, f, vert, frag, graphics.ShaderImageNum)
+// NewShaderFromSources compiles a shader program from multiple sources in the shading language Kage, and returns
+// the result.
+//
+// The sources are compiled together like the files of one Go package.
+// The functions, the constants and the uniform variables declared in a source are available in the other sources.
+// This is useful to share common functions like noise or color space conversions among shaders,
+// instead of copying them into each shader.
+//
+// All the sources must have the same package name, and exactly one of them must have the entry point Fragment.
+// In error messages, the sources are called source0, source1, and so on in the given order.
+//
+// If the compilation fails, NewShaderFromSources returns an error.
+// If the sources are invalid, the error is a *ShaderError with the details.
+//
+// For the details about the shader, see https://ebiten.org/documents/shader.html.
+func NewShaderFromSources(srcs ...[]byte) (*Shader, error) {
+	if len(srcs) == 0 {
+		return nil, fmt.Errorf("ebiten: no shader sources are given")
+	}
+	ir, err := compileShader(srcs...)
 	if err != nil {
 		return nil, err
 	}
+	return newShader(mipmap.NewShader(ir), ir), nil
+}
 
-	if s.VertexFunc.Block == nil {
-		return nil, fmt.Errorf("ebiten: vertex shader entry point '%s' is missing", vert)
-	}
-	if s.FragmentFunc.Block == nil {
-		return nil, fmt.Errorf("ebiten: fragment shader entry point '%s' is missing", frag)
+// NewShaderAsync compiles a shader program in the shading language Kage without blocking the game loop,
+// and calls f with the result later.
+//
+// NewShaderAsync compiles the source into the internal representation synchronously.
+// If this fails, NewShaderAsync returns an error and f is never called.
+// Then, the graphics driver compiles the shader in the background, and f is called before Update in a later frame.
+// If the graphics driver doesn't support background compilation, the shader is compiled in the frame and f is
+// called in the next frame.
+//
+// NewShaderAsync is useful to load many shaders e.g. at a loading screen without freezing the game.
+//
+// For the details about the shader, see https://ebiten.org/documents/shader.html.
+func NewShaderAsync(src []byte, f func(shader *Shader)) error {
+	ir, err := compileShader(src)
+	if err != nil {
+		return err
 	}
+	var s *Shader
+	s = newShader(mipmap.NewShaderAsync(ir, func() {
+		f(s)
+	}), ir)
+	return nil
+}
 
+func newShader(s *mipmap.Shader, ir *shaderir.Program) *Shader {
 	return &Shader{
-		shader:       mipmap.NewShader(s),
-		uniformNames: s.UniformNames,
-		uniformTypes: s.Uniforms,
-	}, nil
+		shader:       s,
+		uniformNames: ir.UniformNames,
+		uniformTypes: ir.Uniforms,
+		colorsCount:  ir.FragmentColorsCount(),
+	}
+}
+
+// compileShader compiles the Kage sources into the internal representation.
+//
+// When multiple sources are given, their declarations are merged as if they were in one source.
+func compileShader(srcs ...[]byte) (*shaderir.Program, error) {
+	var filenames []string
+	if len(srcs) > 1 {
+		for i := range srcs {
+			filenames = append(filenames, fmt.Sprintf("source%d", i))
+		}
+	}
+	s, err := shader.CompileSources(srcs, filenames)
+	if err != nil {
+		if perr, ok := err.(*shader.ParseError); ok {
+			return nil, newShaderError(perr)
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload recompiles the shader program from the given sources in the shading language Kage, and replaces the
+// program of s with the result.
+//
+// Reload is useful to iterate on shaders while the game is running.
+// The sources are compiled in the same way as NewShaderFromSources.
+//
+// If the compilation fails, Reload returns an error and s keeps the current program.
+// Otherwise, the draw calls with s after Reload use the new program, and the draw calls before Reload are not
+// affected.
+//
+// As uniform variables are given by their names at each draw call, the same uniform values work with the new
+// program as long as the names and the types of the variables are unchanged.
+//
+// Reload must not be called for a disposed shader.
+func (s *Shader) Reload(srcs ...[]byte) error {
+	if s.shader == nil {
+		panic("ebiten: the shader to reload must not be disposed")
+	}
+	if len(srcs) == 0 {
+		return fmt.Errorf("ebiten: no shader sources are given")
+	}
+	ir, err := compileShader(srcs...)
+	if err != nil {
+		return err
+	}
+	old := s.shader
+	*s = *newShader(mipmap.NewShader(ir), ir)
+	old.MarkDisposed()
+	return nil
 }
 
 // Dispose disposes the shader program.
@@ -173,6 +177,25 @@ func (s *Shader) Dispose() {
 	s.shader = nil
 }
 
+// Precompile prepares the shader for rendering onto an image in the given format with the given blends in advance.
+//
+// Some graphics drivers create an internal pipeline state for each combination of a shader, a destination format
+// and a blend lazily, which might cause a hitch at the first draw call with the combination.
+// Call Precompile e.g. at a loading screen to avoid this.
+// If blends is empty, the regular alpha blending is used.
+//
+// Precompile does nothing if the graphics driver doesn't need this preparation.
+func (s *Shader) Precompile(format ImageFormat, blends ...Blend) {
+	bs := make([]graphicsdriver.Blend, 0, len(blends))
+	for _, b := range blends {
+		bs = append(bs, b.internalBlend())
+	}
+	if len(bs) == 0 {
+		bs = append(bs, BlendSourceOver.internalBlend())
+	}
+	s.shader.Precompile(format.pixelFormat(), bs)
+}
+
 func (s *Shader) convertUniforms(uniforms map[string]interface{}) []graphicsdriver.Uniform {
 	type index struct {
 		resultIndex        int
@@ -194,6 +217,7 @@ func (s *Shader) convertUniforms(uniforms map[string]interface{}) []graphicsdriv
 
 	us := make([]graphicsdriver.Uniform, len(names))
 	for name, idx := range names {
+		t := s.uniformTypes[idx.shaderUniformIndex]
 		if v, ok := uniforms[name]; ok {
 			switch v := v.(type) {
 			case float32:
@@ -201,6 +225,9 @@ func (s *Shader) convertUniforms(uniforms map[string]interface{}) []graphicsdriv
 					Float32: v,
 				}
 			case []float32:
+				if got, want := len(v), t.FloatNum(); got != want {
+					panic(fmt.Sprintf("ebiten: the length of the uniform variable %s (%s) must be %d but %d", name, t.String(), want, got))
+				}
 				us[idx.resultIndex] = graphicsdriver.Uniform{
 					Float32s: v,
 				}
@@ -210,7 +237,6 @@ func (s *Shader) convertUniforms(uniforms map[string]interface{}) []graphicsdriv
 			continue
 		}
 
-		t := s.uniformTypes[idx.shaderUniformIndex]
 		us[idx.resultIndex] = zeroUniformValue(name, t)
 	}
 
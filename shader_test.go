@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"math"
+	"reflect"
 	"testing"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -879,6 +881,46 @@ func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
 	}
 }
 
+func TestShaderArray(t *testing.T) {
+	const w, h = 16, 16
+
+	dst := ebiten.NewImage(w, h)
+	s, err := ebiten.NewShader([]byte(`package main
+
+var Weights [4]float
+var Colors [2]vec3
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	kernel := [4]float{8, 4, 2, 1}
+	r := 0.0
+	for i := 0; i < len(kernel); i++ {
+		r += kernel[i] * Weights[i]
+	}
+	return vec4(r, Colors[1].y, Colors[1].z, 1)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op := &ebiten.DrawRectShaderOptions{}
+	op.Uniforms = map[string]interface{}{
+		"Weights": []float32{0, 0.25, 0, 0},
+		"Colors":  []float32{1, 1, 1, 0, 1, 0},
+	}
+	dst.DrawRectShader(w, h, s, op)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{0xff, 0xff, 0, 0xff}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
 func TestShaderSubImage(t *testing.T) {
 	const w, h = 16, 16
 
@@ -1399,175 +1441,684 @@ func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
 	}
 }
 
-func TestShaderOperatorAssign(t *testing.T) {
-	if _, err := ebiten.NewShader([]byte(`package main
+func TestShaderIntOperators(t *testing.T) {
+	const w, h = 16, 16
+
+	dst := ebiten.NewImage(w, h)
+	s, err := ebiten.NewShader([]byte(`package main
 
 func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
-	a := 1.0
-	a += 2
-	return vec4(a)
-}`)); err != nil {
-		t.Error(err)
+	x := 0xa5
+	y := -6
+	v := ivec2(13, -13)
+	r := (x&0x0f | 0x30) + ^y
+	g := y&0xff ^ 0x0f
+	b := x>>2 + y>>1 + 1<<4
+	v %= 5
+	b += v.x + v.y
+	return vec4(float(r)/255, float(g)/255, float(b)/255, 1)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	if _, err := ebiten.NewShader([]byte(`package main
+	dst.DrawRectShader(w, h, s, nil)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{0x35 + 5, 0xfa ^ 0x0f, 0xa5>>2 - 3 + 1<<4, 0xff}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestShaderSwitch(t *testing.T) {
+	const w, h = 16, 16
+
+	dst := ebiten.NewImage(w, h)
+	s, err := ebiten.NewShader([]byte(`package main
+
+var Mode float
+
+func Color(mode int) vec4 {
+	switch mode {
+	case 0:
+		return vec4(1, 0, 0, 1)
+	case 1, 2:
+		return vec4(0, 1, 0, 1)
+	}
+	return vec4(0, 0, 1, 1)
+}
 
 func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
-	a := 1.0
-	a += 2.0
-	return vec4(a)
-}`)); err != nil {
-		t.Error(err)
+	origin, _ := imageDstRegionOnTexture()
+	x := position.x - origin.x*imageDstTextureSize().x
+	clr := Color(int(Mode))
+	switch {
+	case x < 8:
+		clr.a = 1
+	default:
+		clr = vec4(clr.rgb, 1) * 0.5
+		break
+	}
+	return clr
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mode := range []int{0, 1, 2, 3} {
+		op := &ebiten.DrawRectShaderOptions{}
+		op.Uniforms = map[string]interface{}{
+			"Mode": float32(mode),
+		}
+		dst.Clear()
+		dst.DrawRectShader(w, h, s, op)
+
+		var clr color.RGBA
+		switch mode {
+		case 0:
+			clr = color.RGBA{0xff, 0, 0, 0xff}
+		case 1, 2:
+			clr = color.RGBA{0, 0xff, 0, 0xff}
+		default:
+			clr = color.RGBA{0, 0, 0xff, 0xff}
+		}
+		for j := 0; j < h; j++ {
+			for i := 0; i < w; i++ {
+				got := dst.At(i, j).(color.RGBA)
+				want := clr
+				if i >= 8 {
+					want = color.RGBA{clr.R / 2, clr.G / 2, clr.B / 2, 0x80}
+				}
+				if !sameColors(got, want, 1) {
+					t.Errorf("mode %d: dst.At(%d, %d): got: %v, want: %v", mode, i, j, got, want)
+				}
+			}
+		}
 	}
 
+	// break in the middle of a case clause is not supported.
 	if _, err := ebiten.NewShader([]byte(`package main
 
 func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
-	a := 1.0
-	a += 2.1
-	return vec4(a)
-}`)); err != nil {
-		t.Error(err)
+	switch {
+	case position.x < 8:
+		if position.y < 8 {
+			break
+		}
+		return vec4(1)
+	}
+	return vec4(0)
+}
+`)); err == nil {
+		t.Errorf("error must be non-nil but was nil")
 	}
 
 	if _, err := ebiten.NewShader([]byte(`package main
 
 func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
-	a := 1
-	a += 2
-	return vec4(a)
-}`)); err != nil {
-		t.Error(err)
+	switch {
+	case position.x < 8:
+		fallthrough
+	default:
+		return vec4(1)
+	}
+	return vec4(0)
+}
+`)); err == nil {
+		t.Errorf("error must be non-nil but was nil")
 	}
 
 	if _, err := ebiten.NewShader([]byte(`package main
 
 func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
-	a := 1
-	a += 2.0
-	return vec4(a)
-}`)); err != nil {
-		t.Error(err)
+	switch position.xy {
+	case vec2(0):
+		return vec4(1)
+	}
+	return vec4(0)
+}
+`)); err == nil {
+		t.Errorf("error must be non-nil but was nil")
 	}
 
 	if _, err := ebiten.NewShader([]byte(`package main
 
 func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
-	a := 1
-	a += 2.1
-	return vec4(a)
-}`)); err == nil {
+	switch int(position.x) {
+	case 1.5:
+		return vec4(1)
+	}
+	return vec4(0)
+}
+`)); err == nil {
 		t.Errorf("error must be non-nil but was nil")
 	}
 }
 
-// Issue #1963
-func TestShaderOperatorVecAndNumber(t *testing.T) {
+func TestShaderBranchOutsideLoop(t *testing.T) {
 	if _, err := ebiten.NewShader([]byte(`package main
 
 func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
-	a := vec2(1) + 2
-	return a.xxyy
-}`)); err != nil {
-		t.Error(err)
+	break
+	return vec4(0)
+}
+`)); err == nil {
+		t.Errorf("error must be non-nil but was nil")
 	}
 
 	if _, err := ebiten.NewShader([]byte(`package main
 
 func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
-	a := vec2(1) + 2.1
-	return a.xxyy
-}`)); err != nil {
-		t.Error(err)
+	switch {
+	default:
+		continue
+	}
+	return vec4(0)
+}
+`)); err == nil {
+		t.Errorf("error must be non-nil but was nil")
 	}
 
+	// continue in a switch in a for-loop is allowed.
 	if _, err := ebiten.NewShader([]byte(`package main
 
 func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
-	a := vec2(1) % 2
-	return a.xxyy
-}`)); err == nil {
-		t.Errorf("error must be non-nil but was nil")
+	sum := 0.0
+	for i := 0; i < 4; i++ {
+		switch i {
+		case 1:
+			continue
+		}
+		sum += float(i)
+	}
+	return vec4(sum)
+}
+`)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShaderDiscard(t *testing.T) {
+	const w, h = 16, 16
+
+	dst := ebiten.NewImage(w, h)
+	dst.Fill(color.RGBA{0, 0, 0xff, 0xff})
+	s, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	origin, _ := imageDstRegionOnTexture()
+	if position.x-origin.x*imageDstTextureSize().x < 8 {
+		discard()
+	}
+	return vec4(1, 0, 0, 1)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst.DrawRectShader(w, h, s, nil)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{0xff, 0, 0, 0xff}
+			if i < 8 {
+				want = color.RGBA{0, 0, 0xff, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
 	}
 
+	// discard is available only in the fragment entry point.
 	if _, err := ebiten.NewShader([]byte(`package main
 
+func Foo() {
+	discard()
+}
+
 func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
-	a := vec2(1) % 2.1
-	return a.xxyy
-}`)); err == nil {
+	Foo()
+	return vec4(0)
+}
+`)); err == nil {
 		t.Errorf("error must be non-nil but was nil")
 	}
 
+	// discard can be a terminating statement.
 	if _, err := ebiten.NewShader([]byte(`package main
 
 func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
-	a := vec2(1)
-	a += 2
-	return a.xxyy
-}`)); err != nil {
+	if position.x < 8 {
+		return vec4(1)
+	} else {
+		discard()
+	}
+}
+`)); err != nil {
 		t.Error(err)
 	}
+}
 
+func TestShaderMissingReturn(t *testing.T) {
+	// A return in only one of the branches is not enough.
 	if _, err := ebiten.NewShader([]byte(`package main
 
 func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
-	a := vec2(1)
-	a += 2.1
-	return a.xxyy
-}`)); err != nil {
-		t.Error(err)
+	if position.x < 8 {
+		return vec4(1)
+	}
+}
+`)); err == nil {
+		t.Errorf("error must be non-nil but was nil")
 	}
 
 	if _, err := ebiten.NewShader([]byte(`package main
 
+func Foo(x float) float {
+	for i := 0; i < 4; i++ {
+		return x
+	}
+}
+
 func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
-	a := vec2(1)
-	a %= 2
-	return a.xxyy
-}`)); err == nil {
+	return vec4(Foo(1))
+}
+`)); err == nil {
 		t.Errorf("error must be non-nil but was nil")
 	}
 
 	if _, err := ebiten.NewShader([]byte(`package main
 
+func Foo(x float) float {
+	if x < 0 {
+		return -x
+	} else if x > 1 {
+		return 1
+	} else {
+		return x
+	}
+}
+
 func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
-	a := vec2(1)
-	a %= 2.1
-	return a.xxyy
-}`)); err == nil {
-		t.Errorf("error must be non-nil but was nil")
+	return vec4(Foo(1))
+}
+`)); err != nil {
+		t.Error(err)
 	}
 }
 
-// Issue #1971
-func TestShaderOperatorMultiply(t *testing.T) {
-	// Note: mat + float is allowed in GLSL but not in Metal.
+func TestShaderFromSources(t *testing.T) {
+	const w, h = 16, 16
 
-	cases := []struct {
-		stmt string
-		err  bool
-	}{
-		{stmt: "a := 1 * vec2(2); _ = a", err: false},
-		{stmt: "a := int(1) * vec2(2); _ = a", err: true},
-		{stmt: "a := 1.0 * vec2(2); _ = a", err: false},
-		{stmt: "a := 1 + vec2(2); _ = a", err: false},
-		{stmt: "a := int(1) + vec2(2); _ = a", err: true},
-		{stmt: "a := 1.0 / vec2(2); _ = a", err: false},
-		{stmt: "a := 1.0 + vec2(2); _ = a", err: false},
-		{stmt: "a := 1 * vec3(2); _ = a", err: false},
-		{stmt: "a := 1.0 * vec3(2); _ = a", err: false},
-		{stmt: "a := 1 * vec4(2); _ = a", err: false},
-		{stmt: "a := 1.0 * vec4(2); _ = a", err: false},
-		{stmt: "a := 1 * mat2(2); _ = a", err: false},
-		{stmt: "a := 1.0 * mat2(2); _ = a", err: false},
-		{stmt: "a := float(1.0) / mat2(2); _ = a", err: true},
-		{stmt: "a := 1.0 / mat2(2); _ = a", err: true},
-		{stmt: "a := float(1.0) + mat2(2); _ = a", err: true},
-		{stmt: "a := 1.0 + mat2(2); _ = a", err: true},
-		{stmt: "a := 1 * mat3(2); _ = a", err: false},
-		{stmt: "a := 1.0 * mat3(2); _ = a", err: false},
-		{stmt: "a := 1 * mat4(2); _ = a", err: false},
+	lib := []byte(`package main
+
+var Scale float
+
+const half = 0.5
+
+func scale(clr vec4) vec4 {
+	return clr * Scale
+}
+`)
+
+	dst := ebiten.NewImage(w, h)
+	s, err := ebiten.NewShaderFromSources([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return scale(vec4(1, half, 0, 1))
+}
+`), lib)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op := &ebiten.DrawRectShaderOptions{}
+	op.Uniforms = map[string]interface{}{
+		"Scale": float32(1),
+	}
+	dst.DrawRectShader(w, h, s, op)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{0xff, 0x80, 0, 0xff}
+			if !sameColors(got, want, 1) {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+
+	// The same library can be used with another shader.
+	if _, err := ebiten.NewShaderFromSources(lib, []byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return scale(color)
+}
+`)); err != nil {
+		t.Error(err)
+	}
+
+	// A function redeclared in another source is an error.
+	if _, err := ebiten.NewShaderFromSources([]byte(`package main
+
+func scale(clr vec4) vec4 {
+	return clr
+}
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return scale(color)
+}
+`), lib); err == nil {
+		t.Errorf("error must be non-nil but was nil")
+	}
+
+	// The entry point must be exactly one.
+	if _, err := ebiten.NewShaderFromSources(lib, lib); err == nil {
+		t.Errorf("error must be non-nil but was nil")
+	}
+	if _, err := ebiten.NewShaderFromSources([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return color
+}
+`), []byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return color
+}
+`)); err == nil {
+		t.Errorf("error must be non-nil but was nil")
+	}
+
+	if _, err := ebiten.NewShaderFromSources([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return color
+}
+`), []byte(`package lib
+`)); err == nil {
+		t.Errorf("error must be non-nil but was nil")
+	}
+}
+
+func TestShaderBuiltinLibrary(t *testing.T) {
+	cases := []struct {
+		Expr string
+		Want color.RGBA
+	}{
+		{
+			Expr: "vec4(smootherstep(0, 1, 0.5))",
+			Want: color.RGBA{0x80, 0x80, 0x80, 0x80},
+		},
+		{
+			Expr: "vec4(smootherstep(vec2(0), vec2(1), vec2(0.25, 0.75)), 0, 1)",
+			Want: color.RGBA{26, 229, 0, 0xff},
+		},
+		{
+			Expr: "vec4(smootherstep(0, 2, vec3(0, 1, 2)), 1)",
+			Want: color.RGBA{0, 0x80, 0xff, 0xff},
+		},
+		{
+			Expr: "vec4(hsvToRGB(vec3(1.0/3.0, 1, 1)), 1)",
+			Want: color.RGBA{0, 0xff, 0, 0xff},
+		},
+		{
+			Expr: "vec4(rgbToHSV(vec3(0, 0, 1)), 1)",
+			Want: color.RGBA{0xaa, 0xff, 0xff, 0xff},
+		},
+		{
+			Expr: "vec4(rotation2D(3.14159265/2)*vec2(1, 0), 0, 1)",
+			Want: color.RGBA{0, 0xff, 0, 0xff},
+		},
+		{
+			Expr: "vec4(rotation3D(vec3(0, 0, 2), 3.14159265/2)*vec3(1, 0, 0), 1)",
+			Want: color.RGBA{0, 0xff, 0, 0xff},
+		},
+		{
+			Expr: "premultiply(vec4(1, 0.5, 0, 0.5))",
+			Want: color.RGBA{0x80, 0x40, 0, 0x80},
+		},
+		{
+			Expr: "premultiply(unpremultiply(vec4(0.25, 0.5, 0, 0.5)))",
+			Want: color.RGBA{0x40, 0x80, 0, 0x80},
+		},
+		{
+			Expr: "unpremultiply(vec4(0))",
+			Want: color.RGBA{0, 0, 0, 0},
+		},
+		{
+			// Noise values at integer points are the hash values.
+			Expr: "vec4(noise(3), noise(vec2(3, 4)), noise(vec3(3, 4, 5)), 1) - vec4(hash(3), hash(vec2(3, 4)), hash(vec3(3, 4, 5)), 0)",
+			Want: color.RGBA{0, 0, 0, 0xff},
+		},
+		{
+			Expr: "vec4(step(0, hash(1.5)) * step(hash(1.5), 0.9999), step(0, noise(vec2(1.5, 2.5))) * step(noise(vec2(1.5, 2.5)), 0.9999), 0, 1)",
+			Want: color.RGBA{0xff, 0xff, 0, 0xff},
+		},
+	}
+
+	const w, h = 16, 16
+	dst := ebiten.NewImage(w, h)
+	for _, c := range cases {
+		c := c
+		t.Run(c.Expr, func(t *testing.T) {
+			s, err := ebiten.NewShader([]byte(fmt.Sprintf(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return %s
+}
+`, c.Expr)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dst.Clear()
+			dst.DrawRectShader(w, h, s, nil)
+			if got, want := dst.At(0, 0).(color.RGBA), c.Want; !sameColors(got, want, 2) {
+				t.Errorf("got: %v, want: %v", got, want)
+			}
+		})
+	}
+
+	for _, src := range []string{
+		"smootherstep(0, 1)",
+		"smootherstep(vec2(0), vec3(1), vec3(0))",
+		"hsvToRGB(vec4(1))",
+		"hash(vec4(1))",
+		"rotation3D(1, 2)",
+		"premultiply(vec3(1))",
+	} {
+		if _, err := ebiten.NewShader([]byte(fmt.Sprintf(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	_ = %s
+	return vec4(0)
+}
+`, src))); err == nil {
+			t.Errorf("%s: error must be non-nil but was nil", src)
+		}
+	}
+}
+
+func TestShaderOperatorAssign(t *testing.T) {
+	if _, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	a := 1.0
+	a += 2
+	return vec4(a)
+}`)); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	a := 1.0
+	a += 2.0
+	return vec4(a)
+}`)); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	a := 1.0
+	a += 2.1
+	return vec4(a)
+}`)); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	a := 1
+	a += 2
+	return vec4(a)
+}`)); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	a := 1
+	a += 2.0
+	return vec4(a)
+}`)); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	a := 1
+	a += 2.1
+	return vec4(a)
+}`)); err == nil {
+		t.Errorf("error must be non-nil but was nil")
+	}
+}
+
+// Issue #1963
+func TestShaderOperatorVecAndNumber(t *testing.T) {
+	if _, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	a := vec2(1) + 2
+	return a.xxyy
+}`)); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	a := vec2(1) + 2.1
+	return a.xxyy
+}`)); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	a := vec2(1) % 2
+	return a.xxyy
+}`)); err == nil {
+		t.Errorf("error must be non-nil but was nil")
+	}
+
+	if _, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	a := vec2(1) % 2.1
+	return a.xxyy
+}`)); err == nil {
+		t.Errorf("error must be non-nil but was nil")
+	}
+
+	if _, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	a := vec2(1)
+	a += 2
+	return a.xxyy
+}`)); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	a := vec2(1)
+	a += 2.1
+	return a.xxyy
+}`)); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	a := vec2(1)
+	a %= 2
+	return a.xxyy
+}`)); err == nil {
+		t.Errorf("error must be non-nil but was nil")
+	}
+
+	if _, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	a := vec2(1)
+	a %= 2.1
+	return a.xxyy
+}`)); err == nil {
+		t.Errorf("error must be non-nil but was nil")
+	}
+}
+
+// Issue #1971
+func TestShaderOperatorMultiply(t *testing.T) {
+	// Note: mat + float is allowed in GLSL but not in Metal.
+
+	cases := []struct {
+		stmt string
+		err  bool
+	}{
+		{stmt: "a := 1 * vec2(2); _ = a", err: false},
+		{stmt: "a := int(1) * vec2(2); _ = a", err: true},
+		{stmt: "a := 1.0 * vec2(2); _ = a", err: false},
+		{stmt: "a := 1 + vec2(2); _ = a", err: false},
+		{stmt: "a := int(1) + vec2(2); _ = a", err: true},
+		{stmt: "a := 1.0 / vec2(2); _ = a", err: false},
+		{stmt: "a := 1.0 + vec2(2); _ = a", err: false},
+		{stmt: "a := 1 * vec3(2); _ = a", err: false},
+		{stmt: "a := 1.0 * vec3(2); _ = a", err: false},
+		{stmt: "a := 1 * vec4(2); _ = a", err: false},
+		{stmt: "a := 1.0 * vec4(2); _ = a", err: false},
+		{stmt: "a := 1 * mat2(2); _ = a", err: false},
+		{stmt: "a := 1.0 * mat2(2); _ = a", err: false},
+		{stmt: "a := float(1.0) / mat2(2); _ = a", err: true},
+		{stmt: "a := 1.0 / mat2(2); _ = a", err: true},
+		{stmt: "a := float(1.0) + mat2(2); _ = a", err: true},
+		{stmt: "a := 1.0 + mat2(2); _ = a", err: true},
+		{stmt: "a := 1 * mat3(2); _ = a", err: false},
+		{stmt: "a := 1.0 * mat3(2); _ = a", err: false},
+		{stmt: "a := 1 * mat4(2); _ = a", err: false},
 		{stmt: "a := 1.0 * mat4(2); _ = a", err: false},
 		{stmt: "a := vec2(1) * 2; _ = a", err: false},
 		{stmt: "a := vec2(1) * 2.0; _ = a", err: false},
@@ -1677,3 +2228,418 @@ func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
 		}
 	}
 }
+
+func TestShaderMRT(t *testing.T) {
+	if ebiten.GraphicsCapabilities().MaxRenderTargets < 2 {
+		t.Skip("multiple render targets are not available")
+	}
+
+	const w, h = 16, 16
+
+	dst0 := ebiten.NewImage(w, h)
+	dst1 := ebiten.NewImage(w, h)
+	s, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) (vec4, vec4) {
+	return vec4(1, 0, 0, 1), vec4(0, 0, 1, 1)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vs := []ebiten.Vertex{
+		{DstX: 0, DstY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: w / 2, DstY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 0, DstY: h / 2, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: w / 2, DstY: h / 2, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+	is := []uint16{0, 1, 2, 1, 2, 3}
+	ebiten.DrawTrianglesShaderMRT([]*ebiten.Image{dst0, dst1}, vs, is, s, nil)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got0 := dst0.At(i, j).(color.RGBA)
+			got1 := dst1.At(i, j).(color.RGBA)
+			var want0, want1 color.RGBA
+			if i < w/2 && j < h/2 {
+				want0 = color.RGBA{0xff, 0, 0, 0xff}
+				want1 = color.RGBA{0, 0, 0xff, 0xff}
+			}
+			if got0 != want0 {
+				t.Errorf("dst0.At(%d, %d): got: %v, want: %v", i, j, got0, want0)
+			}
+			if got1 != want1 {
+				t.Errorf("dst1.At(%d, %d): got: %v, want: %v", i, j, got1, want1)
+			}
+		}
+	}
+}
+
+func TestShaderPrecompile(t *testing.T) {
+	const w, h = 16, 16
+
+	dst := ebiten.NewImage(w, h)
+	s, err := ebiten.NewShader([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return vec4(1, 0, 0, 1)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Precompile(ebiten.ImageFormatRGBA8)
+	s.Precompile(ebiten.ImageFormatRGBA8, ebiten.BlendCopy, ebiten.BlendLighter)
+
+	op := &ebiten.DrawRectShaderOptions{}
+	op.Blend = ebiten.BlendCopy
+	dst.DrawRectShader(w, h, s, op)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{0xff, 0, 0, 0xff}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestShaderReload(t *testing.T) {
+	const w, h = 16, 16
+
+	dst := ebiten.NewImage(w, h)
+	s, err := ebiten.NewShader([]byte(`package main
+
+var Alpha float
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return vec4(Alpha, 0, 0, Alpha)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op := &ebiten.DrawRectShaderOptions{}
+	op.Uniforms = map[string]interface{}{
+		"Alpha": float32(1),
+	}
+	dst.DrawRectShader(w/2, h, s, op)
+
+	if err := s.Reload([]byte(`package main
+
+var Alpha float
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return vec4(0, Alpha, 0, Alpha)
+}
+`)); err != nil {
+		t.Fatal(err)
+	}
+
+	// An invalid source must not replace the current program.
+	if err := s.Reload([]byte(`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return foo
+}
+`)); err == nil {
+		t.Errorf("error must be non-nil but was nil")
+	}
+
+	op.GeoM.Translate(w/2, 0)
+	dst.DrawRectShader(w/2, h, s, op)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{0xff, 0, 0, 0xff}
+			if i >= w/2 {
+				want = color.RGBA{0, 0xff, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestShaderVertex(t *testing.T) {
+	const w, h = 16, 16
+
+	dst := ebiten.NewImage(w, h)
+	s, err := ebiten.NewShader([]byte(`package main
+
+var Offset vec2
+
+func Vertex(position vec2, texCoord vec2, color vec4) (vec4, vec2, vec4, float) {
+	return imageDstClipPosition(position + Offset), texCoord, color, 1
+}
+
+func Fragment(position vec4, texCoord vec2, color vec4, green float) vec4 {
+	return vec4(0, green, 0, 1)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vs := []ebiten.Vertex{
+		{DstX: 0, DstY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: w / 2, DstY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 0, DstY: h, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: w / 2, DstY: h, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+	is := []uint16{0, 1, 2, 1, 2, 3}
+	op := &ebiten.DrawTrianglesShaderOptions{}
+	op.Uniforms = map[string]interface{}{
+		"Offset": []float32{w / 2, 0},
+	}
+	dst.DrawTrianglesShader(vs, is, s, op)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			var want color.RGBA
+			if i >= w/2 {
+				want = color.RGBA{0, 0xff, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestShaderVertexError(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+	}{
+		{
+			name: "invalid parameters",
+			src: `package main
+
+func Vertex(position vec4, texCoord vec2, color vec4) (vec4, vec2, vec4) {
+	return position, texCoord, color
+}
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return color
+}
+`,
+		},
+		{
+			name: "mismatched varyings",
+			src: `package main
+
+func Vertex(position vec2, texCoord vec2, color vec4) (vec4, vec2) {
+	return imageDstClipPosition(position), texCoord
+}
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return color
+}
+`,
+		},
+		{
+			name: "no position",
+			src: `package main
+
+func Vertex(position vec2, texCoord vec2, color vec4) {
+}
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return color
+}
+`,
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := ebiten.NewShader([]byte(c.src)); err == nil {
+				t.Errorf("error must be non-nil but was nil")
+			}
+		})
+	}
+}
+
+func TestShaderError(t *testing.T) {
+	cases := []struct {
+		name string
+		srcs []string
+		want []ebiten.ShaderDiagnostic
+	}{
+		{
+			name: "syntax",
+			srcs: []string{`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	x := := 1
+	return vec4(1)
+}
+`},
+			want: []ebiten.ShaderDiagnostic{
+				{
+					Line:    4,
+					Column:  7,
+					Code:    ebiten.ShaderErrorCodeSyntax,
+					Message: "expected operand, found ':='",
+					Snippet: "\tx := := 1",
+				},
+				{
+					Line:    5,
+					Column:  2,
+					Code:    ebiten.ShaderErrorCodeSyntax,
+					Message: "expected ';', found 'return'",
+					Snippet: "\treturn vec4(1)",
+				},
+			},
+		},
+		{
+			name: "undefined",
+			srcs: []string{`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return foo
+}
+`},
+			want: []ebiten.ShaderDiagnostic{
+				{
+					Line:    4,
+					Column:  9,
+					Code:    ebiten.ShaderErrorCodeUndefined,
+					Message: "unexpected identifier: foo",
+					Snippet: "\treturn foo",
+				},
+			},
+		},
+		{
+			name: "multiple sources",
+			srcs: []string{`package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return Foo()
+}
+`, `package main
+
+func Foo() vec4 {
+	x := 1.0
+	return vec4(0)
+}
+`},
+			want: []ebiten.ShaderDiagnostic{
+				{
+					Filename: "source1",
+					Line:     4,
+					Column:   2,
+					Code:     ebiten.ShaderErrorCodeUnused,
+					Message:  "local variable x is not used",
+					Snippet:  "\tx := 1.0",
+				},
+			},
+		},
+		{
+			name: "missing entry point",
+			srcs: []string{`package main
+`},
+			want: []ebiten.ShaderDiagnostic{
+				{
+					Code:    ebiten.ShaderErrorCodeEntryPoint,
+					Message: "fragment shader entry point 'Fragment' is missing",
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			var srcs [][]byte
+			for _, src := range c.srcs {
+				srcs = append(srcs, []byte(src))
+			}
+			_, err := ebiten.NewShaderFromSources(srcs...)
+			if err == nil {
+				t.Fatal("error must be non-nil but was nil")
+			}
+			serr, ok := err.(*ebiten.ShaderError)
+			if !ok {
+				t.Fatalf("error must be *ebiten.ShaderError but %T", err)
+			}
+			if !reflect.DeepEqual(serr.Diagnostics, c.want) {
+				t.Errorf("got: %#v, want: %#v", serr.Diagnostics, c.want)
+			}
+		})
+	}
+}
+
+func TestShaderImageSrcAtLod(t *testing.T) {
+	const w, h = 8, 8
+
+	src := ebiten.NewImage(w, h)
+	pix := make([]byte, 4*w*h)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			idx := 4 * (j*w + i)
+			pix[idx] = byte(i * 0x20)
+			pix[idx+1] = byte(j * 0x20)
+			pix[idx+3] = 0xff
+		}
+	}
+	src.ReplacePixels(pix)
+
+	s, err := ebiten.NewShader([]byte(`package main
+
+var Lod float
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	return imageSrc0AtLod(texCoord, Lod)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// level returns the value of a color component at the index x at the given level.
+	level := func(x int, level int) float64 {
+		size := 1 << level
+		var sum float64
+		for i := x / size * size; i < x/size*size+size; i++ {
+			sum += float64(i * 0x20)
+		}
+		return sum / float64(size)
+	}
+
+	for _, lod := range []float64{-1, 0, 0.5, 1, 1.5, 2, 3} {
+		lod := lod
+		t.Run(fmt.Sprintf("lod%v", lod), func(t *testing.T) {
+			dst := ebiten.NewImage(w, h)
+			op := &ebiten.DrawRectShaderOptions{}
+			op.Images[0] = src
+			op.Uniforms = map[string]interface{}{
+				"Lod": float32(lod),
+			}
+			dst.DrawRectShader(w, h, s, op)
+
+			// lod is clamped to [0, 2].
+			l := int(math.Max(math.Min(lod, 2), 0))
+			f := math.Max(math.Min(lod, 2), 0) - float64(l)
+			for j := 0; j < h; j++ {
+				for i := 0; i < w; i++ {
+					got := dst.At(i, j).(color.RGBA)
+					r := level(i, l)*(1-f) + level(i, l+1)*f
+					g := level(j, l)*(1-f) + level(j, l+1)*f
+					want := color.RGBA{byte(math.Round(r)), byte(math.Round(g)), 0, 0xff}
+					if !sameColors(got, want, 1) {
+						t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+					}
+				}
+			}
+		})
+	}
+}
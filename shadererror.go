@@ -0,0 +1,149 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/shader"
+)
+
+// ShaderErrorCode is a machine-readable code to categorize a ShaderDiagnostic.
+//
+// The values are stable and can be used by tools like editors, e.g., to filter or to highlight diagnostics.
+type ShaderErrorCode string
+
+const (
+	// ShaderErrorCodeSyntax represents a syntax error.
+	ShaderErrorCodeSyntax = ShaderErrorCode(shader.ErrorCodeSyntax)
+
+	// ShaderErrorCodeRedeclared represents a redeclaration of a function, a variable or a constant.
+	ShaderErrorCodeRedeclared = ShaderErrorCode(shader.ErrorCodeRedeclared)
+
+	// ShaderErrorCodeUndefined represents an undefined identifier or type.
+	ShaderErrorCodeUndefined = ShaderErrorCode(shader.ErrorCodeUndefined)
+
+	// ShaderErrorCodeUnused represents an unused variable or value.
+	ShaderErrorCodeUnused = ShaderErrorCode(shader.ErrorCodeUnused)
+
+	// ShaderErrorCodeMismatchedTypes represents a value of an unexpected type.
+	ShaderErrorCodeMismatchedTypes = ShaderErrorCode(shader.ErrorCodeMismatchedTypes)
+
+	// ShaderErrorCodeInvalidOperation represents an operation that is not available for the operands.
+	ShaderErrorCodeInvalidOperation = ShaderErrorCode(shader.ErrorCodeInvalidOperation)
+
+	// ShaderErrorCodeInvalidConstant represents an invalid constant value or a non-constant value where a constant is required.
+	ShaderErrorCodeInvalidConstant = ShaderErrorCode(shader.ErrorCodeInvalidConstant)
+
+	// ShaderErrorCodeWrongArgumentCount represents a function call with a wrong number of arguments.
+	ShaderErrorCodeWrongArgumentCount = ShaderErrorCode(shader.ErrorCodeWrongArgumentCount)
+
+	// ShaderErrorCodeWrongValueCount represents a wrong number of values in an assignment, a return statement
+	// or an expression.
+	ShaderErrorCodeWrongValueCount = ShaderErrorCode(shader.ErrorCodeWrongValueCount)
+
+	// ShaderErrorCodeInvalidControlFlow represents an invalid break, continue or missing return.
+	ShaderErrorCodeInvalidControlFlow = ShaderErrorCode(shader.ErrorCodeInvalidControlFlow)
+
+	// ShaderErrorCodeInvalidForStatement represents a for statement in an unsupported form.
+	ShaderErrorCodeInvalidForStatement = ShaderErrorCode(shader.ErrorCodeInvalidForStatement)
+
+	// ShaderErrorCodeEntryPoint represents a missing or invalid entry point Vertex or Fragment.
+	ShaderErrorCodeEntryPoint = ShaderErrorCode(shader.ErrorCodeEntryPoint)
+
+	// ShaderErrorCodeUnsupported represents a Go feature that Kage doesn't support.
+	ShaderErrorCodeUnsupported = ShaderErrorCode(shader.ErrorCodeUnsupported)
+
+	// ShaderErrorCodeInvalid represents the other errors.
+	ShaderErrorCodeInvalid = ShaderErrorCode(shader.ErrorCodeInvalid)
+)
+
+// ShaderDiagnostic is a diagnostic of a shader compilation.
+type ShaderDiagnostic struct {
+	// Filename is the name of the source.
+	// Filename is empty when the shader is compiled from one source, and source0, source1, and so on when the
+	// shader is compiled from multiple sources. See NewShaderFromSources.
+	// Filename is <builtin> when the diagnostic is about a built-in declaration, e.g., a redeclaration of a
+	// built-in function.
+	Filename string
+
+	// Line is the line number, starting at 1.
+	// Line is 0 if the diagnostic is not for a specific position.
+	Line int
+
+	// Column is the column number in bytes, starting at 1.
+	// Column is 0 if the diagnostic is not for a specific position.
+	Column int
+
+	// Code is the machine-readable code of the diagnostic.
+	Code ShaderErrorCode
+
+	// Message is the human-readable message of the diagnostic.
+	Message string
+
+	// Snippet is the line of the source at Line without the trailing newline.
+	// Snippet is empty if Line is 0.
+	Snippet string
+}
+
+// String returns the diagnostic in the format of file:line:column: message.
+func (d *ShaderDiagnostic) String() string {
+	if d.Line == 0 {
+		return d.Message
+	}
+	pos := token.Position{
+		Filename: d.Filename,
+		Line:     d.Line,
+		Column:   d.Column,
+	}
+	return fmt.Sprintf("%s: %s", pos, d.Message)
+}
+
+// ShaderError is an error returned when a shader compilation fails.
+//
+// The functions compiling shaders like NewShader return a *ShaderError when the sources are invalid.
+type ShaderError struct {
+	// Diagnostics are the diagnostics of the compilation in the order of their appearance.
+	Diagnostics []ShaderDiagnostic
+}
+
+// Error implements error.
+func (e *ShaderError) Error() string {
+	strs := make([]string, 0, len(e.Diagnostics))
+	for i := range e.Diagnostics {
+		strs = append(strs, e.Diagnostics[i].String())
+	}
+	return strings.Join(strs, "\n")
+}
+
+func newShaderError(err *shader.ParseError) *ShaderError {
+	e := &ShaderError{}
+	for _, pe := range err.Errors {
+		d := ShaderDiagnostic{
+			Filename: pe.Pos.Filename,
+			Code:     ShaderErrorCode(pe.Code),
+			Message:  pe.Msg,
+		}
+		if pe.Pos.IsValid() {
+			d.Line = pe.Pos.Line
+			d.Column = pe.Pos.Column
+			d.Snippet = pe.Snippet
+		}
+		e.Diagnostics = append(e.Diagnostics, d)
+	}
+	return e
+}
@@ -0,0 +1,195 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"sort"
+
+	"golang.org/x/image/font"
+)
+
+// cacheSoftLimit indicates the soft limit of the number of glyphs in the cache for one face.
+// If the number of glyphs exceeds this soft limits, old glyphs are removed.
+// Even after clearning up the cache, the number of glyphs might still exceeds the soft limit, but
+// this is fine.
+const cacheSoftLimit = 512
+
+var (
+	// glyphImageCacheBytes is the total size of the cached glyph images in bytes.
+	glyphImageCacheBytes int
+
+	// glyphImageCacheMemoryLimit is the soft limit of glyphImageCacheBytes.
+	// 0 means there is no limit.
+	glyphImageCacheMemoryLimit int
+)
+
+func deleteGlyphImageCacheEntry(face font.Face, r rune) {
+	e, ok := glyphImageCache[face][r]
+	if !ok {
+		return
+	}
+	glyphImageCacheBytes -= e.bytes
+	delete(glyphImageCache[face], r)
+	if e.image != nil {
+		e.image.Dispose()
+	}
+}
+
+// cleanUpGlyphImageCache evicts old glyphs from the cache.
+//
+// cleanUpGlyphImageCache must be called whenever glyphs might be added to the cache.
+func cleanUpGlyphImageCache(face font.Face) {
+	if len(glyphImageCache[face]) > cacheSoftLimit {
+		for r, e := range glyphImageCache[face] {
+			if e.pins > 0 {
+				continue
+			}
+			// 60 is an arbitrary number.
+			if e.atime < now()-60 {
+				deleteGlyphImageCacheEntry(face, r)
+			}
+		}
+	}
+
+	if glyphImageCacheMemoryLimit <= 0 || glyphImageCacheBytes <= glyphImageCacheMemoryLimit {
+		return
+	}
+
+	// Evict the least recently used glyphs until the cache fits with the limit.
+	// Glyphs used in the current tick are not evicted.
+	type key struct {
+		face  font.Face
+		r     rune
+		atime int64
+	}
+	var keys []key
+	for f, m := range glyphImageCache {
+		for r, e := range m {
+			if e.pins > 0 || e.image == nil || e.atime >= now() {
+				continue
+			}
+			keys = append(keys, key{face: f, r: r, atime: e.atime})
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].atime < keys[j].atime
+	})
+	for _, k := range keys {
+		if glyphImageCacheBytes <= glyphImageCacheMemoryLimit {
+			break
+		}
+		deleteGlyphImageCacheEntry(k.face, k.r)
+	}
+}
+
+// PinGlyphs precaches the glyphs for the given text and the given font face, and pins them in the cache.
+//
+// Pinned glyphs are never evicted from the cache until UnpinGlyphs is called.
+// Pins are counted: if PinGlyphs is called multiple times for a glyph, the glyph is kept pinned until UnpinGlyphs is
+// called the same number of times.
+// PinGlyphs is useful to avoid a hitch at rasterizing and uploading glyphs that are used for the first time,
+// e.g., CJK characters in a dialog. Call PinGlyphs with all the possible runes at a loading phase.
+//
+// If you want to use glyphs at multiple sizes, call PinGlyphs for each font face of each size.
+//
+// PinGlyphs is concurrent-safe.
+func PinGlyphs(face font.Face, text string) {
+	textM.Lock()
+	defer textM.Unlock()
+
+	for _, r := range text {
+		getGlyphImage(face, r)
+		glyphImageCache[face][r].pins++
+	}
+
+	cleanUpGlyphImageCache(face)
+}
+
+// UnpinGlyphs unpins the glyphs for the given text and the given font face pinned by PinGlyphs.
+//
+// The unpinned glyphs are still in the cache, but might be evicted later.
+//
+// UnpinGlyphs is concurrent-safe.
+func UnpinGlyphs(face font.Face, text string) {
+	textM.Lock()
+	defer textM.Unlock()
+
+	for _, r := range text {
+		if e, ok := glyphImageCache[face][r]; ok && e.pins > 0 {
+			e.pins--
+		}
+	}
+}
+
+// GlyphCacheStats represents statistics of the glyph cache.
+type GlyphCacheStats struct {
+	// NumGlyphs is the number of the cached glyphs.
+	NumGlyphs int
+
+	// NumPinnedGlyphs is the number of the pinned glyphs.
+	NumPinnedGlyphs int
+
+	// Bytes is the approximate size of the cached glyph images in bytes.
+	Bytes int
+}
+
+// ReadGlyphCacheStats reads the current statistics of the glyph cache into stats.
+//
+// ReadGlyphCacheStats is concurrent-safe.
+func ReadGlyphCacheStats(stats *GlyphCacheStats) {
+	textM.Lock()
+	defer textM.Unlock()
+
+	*stats = GlyphCacheStats{}
+	for _, m := range glyphImageCache {
+		for _, e := range m {
+			if e.image == nil {
+				continue
+			}
+			stats.NumGlyphs++
+			if e.pins > 0 {
+				stats.NumPinnedGlyphs++
+			}
+		}
+	}
+	stats.Bytes = glyphImageCacheBytes
+}
+
+// SetGlyphCacheMemoryLimit sets the soft limit of the glyph cache size in bytes.
+//
+// When the cache exceeds the limit, the least recently used glyphs are evicted from the cache, and their images
+// are disposed.
+// Pinned glyphs and glyphs used in the current tick are never evicted, so the cache might still exceed the limit.
+// The limit is checked whenever glyphs are added to the cache, e.g., at Draw, CacheGlyphs and AppendGlyphs.
+//
+// The default value is 0, which means there is no limit in terms of bytes.
+//
+// SetGlyphCacheMemoryLimit is concurrent-safe.
+func SetGlyphCacheMemoryLimit(bytes int) {
+	textM.Lock()
+	defer textM.Unlock()
+
+	glyphImageCacheMemoryLimit = bytes
+}
+
+// GlyphCacheMemoryLimit returns the soft limit of the glyph cache size in bytes set by SetGlyphCacheMemoryLimit.
+//
+// GlyphCacheMemoryLimit is concurrent-safe.
+func GlyphCacheMemoryLimit() int {
+	textM.Lock()
+	defer textM.Unlock()
+
+	return glyphImageCacheMemoryLimit
+}
@@ -0,0 +1,653 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"sort"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// NewColorFace returns a font.Face for the given OpenType font data that renders color glyphs.
+//
+// NewColorFace supports these color font tables:
+//
+//     * sbix: PNG bitmap glyphs (e.g. Apple Color Emoji)
+//     * CBLC and CBDT: PNG bitmap glyphs (e.g. Noto Color Emoji)
+//     * COLR and CPAL version 0: layered outline glyphs (e.g. Twemoji Mozilla)
+//
+// A bitmap glyph is scaled from the strike nearest to the face size.
+// Outline layers in the foreground color and glyphs without color data are rendered in white.
+//
+// Color glyphs are cached in the glyph cache like other glyphs.
+// The glyph colors are multiplied by the color given to Draw or the ColorM given to DrawWithOptions,
+// so specify white to render color glyphs in their original colors.
+//
+// If options is nil, the default options of opentype.NewFace are used.
+//
+// As well as other font.Face implementations, the returned face is not concurrent-safe.
+// The functions in this package can use the face concurrently.
+func NewColorFace(src []byte, options *opentype.FaceOptions) (font.Face, error) {
+	f, err := opentype.Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	face, err := opentype.NewFace(f, options)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := parseFontTables(src)
+	if err != nil {
+		return nil, err
+	}
+
+	size, dpi := 12.0, 72.0
+	if options != nil {
+		size, dpi = options.Size, options.DPI
+	}
+	return &colorFace{
+		Face:   face,
+		font:   f,
+		ppem:   fixed.Int26_6(0.5 + (size * dpi * 64 / 72)),
+		tables: tables,
+		glyphs: map[sfnt.GlyphIndex]*colorGlyph{},
+	}, nil
+}
+
+// parseFontTables returns the tables of a single font by their tags.
+func parseFontTables(src []byte) (map[string][]byte, error) {
+	if len(src) < 12 {
+		return nil, fmt.Errorf("text: the font data is too short")
+	}
+	n := int(binary.BigEndian.Uint16(src[4:]))
+	if len(src) < 12+16*n {
+		return nil, fmt.Errorf("text: the font's table records are too short")
+	}
+	tables := map[string][]byte{}
+	for i := 0; i < n; i++ {
+		r := src[12+16*i:]
+		offset := uint64(binary.BigEndian.Uint32(r[8:]))
+		length := uint64(binary.BigEndian.Uint32(r[12:]))
+		if offset+length > uint64(len(src)) {
+			return nil, fmt.Errorf("text: the font's table %q is out of range", string(r[:4]))
+		}
+		tables[string(r[:4])] = src[offset : offset+length]
+	}
+	return tables, nil
+}
+
+// fontTable reads big-endian values from a font table.
+// Reading a value out of range returns 0 and marks the table as invalid.
+type fontTable struct {
+	data    []byte
+	invalid bool
+}
+
+func (t *fontTable) inRange(offset, length int) bool {
+	if offset < 0 || length < 0 || offset+length > len(t.data) {
+		t.invalid = true
+		return false
+	}
+	return true
+}
+
+func (t *fontTable) u8(offset int) int {
+	if !t.inRange(offset, 1) {
+		return 0
+	}
+	return int(t.data[offset])
+}
+
+func (t *fontTable) i8(offset int) int {
+	return int(int8(t.u8(offset)))
+}
+
+func (t *fontTable) u16(offset int) int {
+	if !t.inRange(offset, 2) {
+		return 0
+	}
+	return int(binary.BigEndian.Uint16(t.data[offset:]))
+}
+
+func (t *fontTable) i16(offset int) int {
+	return int(int16(t.u16(offset)))
+}
+
+func (t *fontTable) u32(offset int) int {
+	if !t.inRange(offset, 4) {
+		return 0
+	}
+	v := binary.BigEndian.Uint32(t.data[offset:])
+	if uint64(v) > math.MaxInt32 {
+		t.invalid = true
+		return 0
+	}
+	return int(v)
+}
+
+func (t *fontTable) bytes(offset, length int) []byte {
+	if !t.inRange(offset, length) {
+		return nil
+	}
+	return t.data[offset : offset+length]
+}
+
+type colorFace struct {
+	font.Face
+
+	font   *sfnt.Font
+	ppem   fixed.Int26_6
+	tables map[string][]byte
+	buf    sfnt.Buffer
+
+	// glyphs is a cache of color glyphs. A nil value means that the glyph has no color data.
+	glyphs map[sfnt.GlyphIndex]*colorGlyph
+}
+
+type colorGlyph struct {
+	image *image.RGBA
+
+	// bounds is the bounds of the glyph image relative to the dot.
+	bounds image.Rectangle
+}
+
+func (f *colorFace) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	g := f.colorGlyph(r)
+	if g == nil {
+		return f.Face.Glyph(dot, r)
+	}
+	advance, _ = f.Face.GlyphAdvance(r)
+	return g.bounds.Add(image.Pt(dot.X.Round(), dot.Y.Round())), g.image, image.Point{}, advance, true
+}
+
+func (f *colorFace) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	g := f.colorGlyph(r)
+	if g == nil {
+		return f.Face.GlyphBounds(r)
+	}
+	advance, _ = f.Face.GlyphAdvance(r)
+	return fixed.R(g.bounds.Min.X, g.bounds.Min.Y, g.bounds.Max.X, g.bounds.Max.Y), advance, true
+}
+
+func (f *colorFace) colorGlyph(r rune) *colorGlyph {
+	x, err := f.font.GlyphIndex(&f.buf, r)
+	if err != nil || x == 0 {
+		return nil
+	}
+	if g, ok := f.glyphs[x]; ok {
+		return g
+	}
+
+	var g *colorGlyph
+	if b, ok := f.bitmap(x); ok {
+		g = b.colorGlyph(f.ppem)
+	} else if ls, ok := f.layers(x); ok {
+		g = f.layeredColorGlyph(ls)
+	}
+	f.glyphs[x] = g
+	return g
+}
+
+// colorBitmap is a PNG bitmap glyph in a strike.
+type colorBitmap struct {
+	png []byte
+
+	// ppem is the strike's pixels per em.
+	ppem int
+
+	// left and bottom are the position of the left-bottom corner of the bitmap relative to the dot
+	// in the strike's pixels. The Y axis points upward.
+	left   int
+	bottom int
+}
+
+func (b *colorBitmap) colorGlyph(ppem fixed.Int26_6) *colorGlyph {
+	src, err := png.Decode(bytes.NewReader(b.png))
+	if err != nil {
+		return nil
+	}
+
+	scale := float64(ppem) / 64 / float64(b.ppem)
+	sb := src.Bounds()
+	w := int(math.Round(float64(sb.Dx()) * scale))
+	h := int(math.Round(float64(sb.Dy()) * scale))
+	if w == 0 || h == 0 {
+		return nil
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	if w == sb.Dx() && h == sb.Dy() {
+		draw.Draw(dst, dst.Bounds(), src, sb.Min, draw.Src)
+	} else {
+		xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, sb, xdraw.Src, nil)
+	}
+
+	left := int(math.Round(float64(b.left) * scale))
+	top := int(math.Round(float64(b.bottom+sb.Dy()) * scale))
+	return &colorGlyph{
+		image:  dst,
+		bounds: image.Rect(left, -top, left+w, -top+h),
+	}
+}
+
+// strikeOrder returns the indices of the strikes in the order of preference for the given pixels per em.
+// The smallest strike not smaller than ppem is the best, and the largest strike smaller than ppem is the next.
+func strikeOrder(ppems []int, ppem int) []int {
+	indices := make([]int, len(ppems))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		a, b := ppems[indices[i]], ppems[indices[j]]
+		if (a >= ppem) != (b >= ppem) {
+			return a >= ppem
+		}
+		if a >= ppem {
+			return a < b
+		}
+		return a > b
+	})
+	return indices
+}
+
+func (f *colorFace) bitmap(x sfnt.GlyphIndex) (colorBitmap, bool) {
+	ppem := f.ppem.Round()
+	if b, ok := f.sbixBitmap(x, ppem); ok {
+		return b, true
+	}
+	if b, ok := f.cbdtBitmap(x, ppem); ok {
+		return b, true
+	}
+	return colorBitmap{}, false
+}
+
+// sbixBitmap returns the bitmap of the glyph x in the sbix table.
+//
+// See https://docs.microsoft.com/en-us/typography/opentype/spec/sbix.
+func (f *colorFace) sbixBitmap(x sfnt.GlyphIndex, ppem int) (colorBitmap, bool) {
+	data, ok := f.tables["sbix"]
+	if !ok {
+		return colorBitmap{}, false
+	}
+	if int(x) >= f.font.NumGlyphs() {
+		return colorBitmap{}, false
+	}
+
+	t := &fontTable{data: data}
+	n := t.u32(4)
+	offsets := make([]int, n)
+	ppems := make([]int, n)
+	for i := range offsets {
+		offsets[i] = t.u32(8 + 4*i)
+		ppems[i] = t.u16(offsets[i])
+		if t.invalid {
+			return colorBitmap{}, false
+		}
+	}
+
+	for _, i := range strikeOrder(ppems, ppem) {
+		s := offsets[i]
+		// A 'dupe' glyph refers to another glyph's data. Follow the reference only once to avoid a loop.
+		for g, dupe := int(x), 0; dupe < 2; dupe++ {
+			start := t.u32(s + 4 + 4*g)
+			end := t.u32(s + 4 + 4*(g+1))
+			if t.invalid {
+				return colorBitmap{}, false
+			}
+			if end-start < 8 {
+				break
+			}
+			d := &fontTable{data: t.bytes(s+start, end-start)}
+			switch string(d.bytes(4, 4)) {
+			case "png ":
+				return colorBitmap{
+					png:    d.data[8:],
+					ppem:   ppems[i],
+					left:   d.i16(0),
+					bottom: d.i16(2),
+				}, true
+			case "dupe":
+				g = d.u16(8)
+				if d.invalid || g >= f.font.NumGlyphs() {
+					return colorBitmap{}, false
+				}
+				continue
+			}
+			break
+		}
+	}
+	return colorBitmap{}, false
+}
+
+// cbdtBitmap returns the bitmap of the glyph x in the CBLC and CBDT tables.
+//
+// See https://docs.microsoft.com/en-us/typography/opentype/spec/cblc and
+// https://docs.microsoft.com/en-us/typography/opentype/spec/cbdt.
+func (f *colorFace) cbdtBitmap(x sfnt.GlyphIndex, ppem int) (colorBitmap, bool) {
+	cblcData, ok := f.tables["CBLC"]
+	if !ok {
+		return colorBitmap{}, false
+	}
+	cbdtData, ok := f.tables["CBDT"]
+	if !ok {
+		return colorBitmap{}, false
+	}
+
+	const bitmapSizeLength = 48
+
+	cblc := &fontTable{data: cblcData}
+	n := cblc.u32(4)
+	if cblc.invalid {
+		return colorBitmap{}, false
+	}
+	ppems := make([]int, n)
+	for i := range ppems {
+		ppems[i] = cblc.u8(8 + bitmapSizeLength*i + 45)
+	}
+	if cblc.invalid {
+		return colorBitmap{}, false
+	}
+
+	for _, i := range strikeOrder(ppems, ppem) {
+		s := 8 + bitmapSizeLength*i
+		if int(x) < cblc.u16(s+40) || int(x) > cblc.u16(s+42) {
+			continue
+		}
+		arrayOffset := cblc.u32(s)
+		numSubtables := cblc.u32(s + 8)
+		if cblc.invalid {
+			return colorBitmap{}, false
+		}
+		for j := 0; j < numSubtables; j++ {
+			e := arrayOffset + 8*j
+			first, last := cblc.u16(e), cblc.u16(e+2)
+			if int(x) < first || int(x) > last {
+				continue
+			}
+			b, ok := cbdtBitmapInSubtable(cblc, cbdtData, arrayOffset+cblc.u32(e+4), int(x)-first, int(x))
+			if !ok {
+				break
+			}
+			b.ppem = ppems[i]
+			return b, true
+		}
+		if cblc.invalid {
+			return colorBitmap{}, false
+		}
+	}
+	return colorBitmap{}, false
+}
+
+// cbdtBitmapInSubtable returns the bitmap of the glyph x in the index subtable at the given offset.
+// i is the index of the glyph in the subtable's glyph range.
+func cbdtBitmapInSubtable(cblc *fontTable, cbdtData []byte, offset int, i int, x int) (colorBitmap, bool) {
+	indexFormat := cblc.u16(offset)
+	imageFormat := cblc.u16(offset + 2)
+	imageDataOffset := cblc.u32(offset + 4)
+	body := offset + 8
+
+	var glyphOffset, glyphLength int
+	// bigMetrics is the offset of the big glyph metrics shared in the subtable, or -1.
+	bigMetrics := -1
+	switch indexFormat {
+	case 1:
+		glyphOffset = cblc.u32(body + 4*i)
+		glyphLength = cblc.u32(body+4*(i+1)) - glyphOffset
+	case 2:
+		glyphLength = cblc.u32(body)
+		glyphOffset = glyphLength * i
+		bigMetrics = body + 4
+	case 3:
+		glyphOffset = cblc.u16(body + 2*i)
+		glyphLength = cblc.u16(body+2*(i+1)) - glyphOffset
+	case 4:
+		n := cblc.u32(body)
+		found := false
+		for k := 0; k < n && !cblc.invalid; k++ {
+			p := body + 4 + 4*k
+			if cblc.u16(p) == x {
+				glyphOffset = cblc.u16(p + 2)
+				glyphLength = cblc.u16(p+6) - glyphOffset
+				found = true
+				break
+			}
+		}
+		if !found {
+			return colorBitmap{}, false
+		}
+	case 5:
+		glyphLength = cblc.u32(body)
+		bigMetrics = body + 4
+		n := cblc.u32(body + 12)
+		found := false
+		for k := 0; k < n && !cblc.invalid; k++ {
+			if cblc.u16(body+16+2*k) == x {
+				glyphOffset = glyphLength * k
+				found = true
+				break
+			}
+		}
+		if !found {
+			return colorBitmap{}, false
+		}
+	default:
+		return colorBitmap{}, false
+	}
+	if cblc.invalid || glyphLength <= 0 {
+		return colorBitmap{}, false
+	}
+
+	cbdt := &fontTable{data: cbdtData}
+	d := &fontTable{data: cbdt.bytes(imageDataOffset+glyphOffset, glyphLength)}
+	if cbdt.invalid {
+		return colorBitmap{}, false
+	}
+
+	var height, bearingX, bearingY, dataOffset int
+	switch imageFormat {
+	case 17:
+		// Small glyph metrics
+		height, bearingX, bearingY = d.u8(0), d.i8(2), d.i8(3)
+		dataOffset = 5
+	case 18:
+		// Big glyph metrics
+		height, bearingX, bearingY = d.u8(0), d.i8(2), d.i8(3)
+		dataOffset = 8
+	case 19:
+		if bigMetrics < 0 {
+			return colorBitmap{}, false
+		}
+		height, bearingX, bearingY = cblc.u8(bigMetrics), cblc.i8(bigMetrics+2), cblc.i8(bigMetrics+3)
+		dataOffset = 0
+	default:
+		return colorBitmap{}, false
+	}
+	data := d.bytes(dataOffset+4, d.u32(dataOffset))
+	if d.invalid || cblc.invalid {
+		return colorBitmap{}, false
+	}
+	return colorBitmap{
+		png:    data,
+		left:   bearingX,
+		bottom: bearingY - height,
+	}, true
+}
+
+// colorLayer is a layer of a layered color glyph.
+type colorLayer struct {
+	glyph sfnt.GlyphIndex
+	color color.Color
+}
+
+// layers returns the layers of the glyph x in the COLR and CPAL tables.
+//
+// See https://docs.microsoft.com/en-us/typography/opentype/spec/colr and
+// https://docs.microsoft.com/en-us/typography/opentype/spec/cpal.
+func (f *colorFace) layers(x sfnt.GlyphIndex) ([]colorLayer, bool) {
+	colrData, ok := f.tables["COLR"]
+	if !ok {
+		return nil, false
+	}
+	cpalData, ok := f.tables["CPAL"]
+	if !ok {
+		return nil, false
+	}
+
+	const (
+		baseGlyphRecordLength = 6
+		layerRecordLength     = 4
+		colorRecordLength     = 4
+		foregroundColorIndex  = 0xffff
+	)
+
+	colr := &fontTable{data: colrData}
+	numBaseGlyphs := colr.u16(2)
+	baseGlyphsOffset := colr.u32(4)
+	layersOffset := colr.u32(8)
+	numLayers := colr.u16(12)
+	if colr.invalid {
+		return nil, false
+	}
+
+	// Base glyph records are sorted by the glyph IDs.
+	i := sort.Search(numBaseGlyphs, func(i int) bool {
+		return colr.u16(baseGlyphsOffset+baseGlyphRecordLength*i) >= int(x)
+	})
+	r := baseGlyphsOffset + baseGlyphRecordLength*i
+	if i == numBaseGlyphs || colr.u16(r) != int(x) {
+		return nil, false
+	}
+	firstLayer, n := colr.u16(r+2), colr.u16(r+4)
+	if colr.invalid || firstLayer+n > numLayers {
+		return nil, false
+	}
+
+	// Use the first palette.
+	cpal := &fontTable{data: cpalData}
+	numPaletteEntries := cpal.u16(2)
+	colorRecordsOffset := cpal.u32(8)
+	firstColorIndex := cpal.u16(12)
+	if cpal.invalid {
+		return nil, false
+	}
+
+	layers := make([]colorLayer, 0, n)
+	for i := firstLayer; i < firstLayer+n; i++ {
+		l := layersOffset + layerRecordLength*i
+		g := colr.u16(l)
+		p := colr.u16(l + 2)
+		var c color.Color = color.White
+		if p != foregroundColorIndex {
+			if p >= numPaletteEntries {
+				return nil, false
+			}
+			// Color records are in BGRA and not premultiplied.
+			c = color.NRGBA{
+				B: uint8(cpal.u8(colorRecordsOffset + colorRecordLength*(firstColorIndex+p))),
+				G: uint8(cpal.u8(colorRecordsOffset + colorRecordLength*(firstColorIndex+p) + 1)),
+				R: uint8(cpal.u8(colorRecordsOffset + colorRecordLength*(firstColorIndex+p) + 2)),
+				A: uint8(cpal.u8(colorRecordsOffset + colorRecordLength*(firstColorIndex+p) + 3)),
+			}
+		}
+		layers = append(layers, colorLayer{
+			glyph: sfnt.GlyphIndex(g),
+			color: c,
+		})
+	}
+	if colr.invalid || cpal.invalid {
+		return nil, false
+	}
+	return layers, true
+}
+
+func (f *colorFace) layeredColorGlyph(layers []colorLayer) *colorGlyph {
+	var bounds fixed.Rectangle26_6
+	for _, l := range layers {
+		lb, _, err := f.font.GlyphBounds(&f.buf, l.glyph, f.ppem, font.HintingNone)
+		if err != nil {
+			return nil
+		}
+		bounds = bounds.Union(lb)
+	}
+	b := image.Rect(bounds.Min.X.Floor(), bounds.Min.Y.Floor(), bounds.Max.X.Ceil(), bounds.Max.Y.Ceil())
+	if b.Empty() {
+		return nil
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for _, l := range layers {
+		segs, err := f.font.LoadGlyph(&f.buf, l.glyph, f.ppem, nil)
+		if err != nil {
+			return nil
+		}
+		z := vector.NewRasterizer(b.Dx(), b.Dy())
+		z.DrawOp = draw.Over
+		for _, s := range segs {
+			var ps [3]struct{ x, y float32 }
+			for i, p := range s.Args[:segmentArgCount(s.Op)] {
+				ps[i].x = float32(p.X-fixed.I(b.Min.X)) / 64
+				ps[i].y = float32(p.Y-fixed.I(b.Min.Y)) / 64
+			}
+			switch s.Op {
+			case sfnt.SegmentOpMoveTo:
+				z.MoveTo(ps[0].x, ps[0].y)
+			case sfnt.SegmentOpLineTo:
+				z.LineTo(ps[0].x, ps[0].y)
+			case sfnt.SegmentOpQuadTo:
+				z.QuadTo(ps[0].x, ps[0].y, ps[1].x, ps[1].y)
+			case sfnt.SegmentOpCubeTo:
+				z.CubeTo(ps[0].x, ps[0].y, ps[1].x, ps[1].y, ps[2].x, ps[2].y)
+			}
+		}
+		z.Draw(dst, dst.Bounds(), image.NewUniform(l.color), image.Point{})
+	}
+	return &colorGlyph{
+		image:  dst,
+		bounds: b,
+	}
+}
+
+func segmentArgCount(op sfnt.SegmentOp) int {
+	switch op {
+	case sfnt.SegmentOpQuadTo:
+		return 2
+	case sfnt.SegmentOpCubeTo:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// isColorGlyphMask reports whether the glyph mask returned by a font.Face has colors.
+// A glyph with colors is rendered as it is instead of being used as a mask.
+func isColorGlyphMask(mask image.Image) bool {
+	switch mask.ColorModel() {
+	case color.RGBAModel, color.RGBA64Model, color.NRGBAModel, color.NRGBA64Model:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,347 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"sort"
+	"testing"
+
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+func encodePNG(t *testing.T, w, h int, clr color.Color) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			img.Set(i, j, clr)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+type fontBuilder struct {
+	bytes.Buffer
+}
+
+func (b *fontBuilder) u8(v int) {
+	b.WriteByte(byte(v))
+}
+
+func (b *fontBuilder) u16(v int) {
+	_ = binary.Write(b, binary.BigEndian, uint16(v))
+}
+
+func (b *fontBuilder) u32(v int) {
+	_ = binary.Write(b, binary.BigEndian, uint32(v))
+}
+
+// colorFontUnitsPerEm is the units per em of the font by newColorFont.
+const colorFontUnitsPerEm = 8
+
+// newColorFont returns a TrueType font with these glyphs:
+//
+//     'a': a red 4x4 PNG bitmap in an 8 ppem sbix strike, at the dot.
+//     'b': a green 4x4 PNG bitmap in a 4 ppem CBDT strike, 1 pixel right to the dot.
+//     'c': a blue 8x8 square COLR layer, at the dot.
+func newColorFont(t *testing.T) []byte {
+	const numGlyphs = 5
+
+	tables := map[string][]byte{}
+
+	var head fontBuilder
+	head.u32(0x00010000)
+	head.u32(0x00010000)
+	head.u32(0)
+	head.u32(0x5f0f3cf5)
+	head.u16(0)
+	head.u16(colorFontUnitsPerEm)
+	head.Write(make([]byte, 16))
+	head.u16(0)
+	head.u16(0)
+	head.u16(colorFontUnitsPerEm)
+	head.u16(colorFontUnitsPerEm)
+	head.u16(0)
+	head.u16(0)
+	head.u16(2)
+	head.u16(1) // indexToLocFormat: long
+	head.u16(0)
+	tables["head"] = head.Bytes()
+
+	var maxp fontBuilder
+	maxp.u32(0x00010000)
+	maxp.u16(numGlyphs)
+	maxp.Write(make([]byte, 26))
+	tables["maxp"] = maxp.Bytes()
+
+	var hhea fontBuilder
+	hhea.u32(0x00010000)
+	hhea.u16(colorFontUnitsPerEm)
+	hhea.u16(-2)
+	hhea.u16(0)
+	hhea.Write(make([]byte, 8))
+	hhea.u16(1) // caretSlopeRise
+	hhea.Write(make([]byte, 14))
+	hhea.u16(numGlyphs)
+	tables["hhea"] = hhea.Bytes()
+
+	var hmtx fontBuilder
+	for i := 0; i < numGlyphs; i++ {
+		hmtx.u16(colorFontUnitsPerEm)
+		hmtx.u16(0)
+	}
+	tables["hmtx"] = hmtx.Bytes()
+
+	// Map 'a'-'c' to the glyphs 1-3 with a format 4 subtable.
+	var cmap fontBuilder
+	cmap.u16(0)
+	cmap.u16(1)
+	cmap.u16(3)
+	cmap.u16(1)
+	cmap.u32(12)
+	cmap.u16(4)
+	cmap.u16(32)
+	cmap.u16(0)
+	cmap.u16(4) // segCountX2
+	cmap.u16(4)
+	cmap.u16(1)
+	cmap.u16(0)
+	cmap.u16('c')
+	cmap.u16(0xffff)
+	cmap.u16(0)
+	cmap.u16('a')
+	cmap.u16(0xffff)
+	cmap.u16(1 - 'a')
+	cmap.u16(1)
+	cmap.u16(0)
+	cmap.u16(0)
+	tables["cmap"] = cmap.Bytes()
+
+	var post fontBuilder
+	post.u32(0x00030000)
+	post.Write(make([]byte, 28))
+	tables["post"] = post.Bytes()
+
+	// The glyph 4 is a square.
+	var glyf fontBuilder
+	glyf.u16(1)
+	glyf.u16(0)
+	glyf.u16(0)
+	glyf.u16(colorFontUnitsPerEm)
+	glyf.u16(colorFontUnitsPerEm)
+	glyf.u16(3)
+	glyf.u16(0)
+	for i := 0; i < 4; i++ {
+		glyf.u8(0x01)
+	}
+	for _, x := range []int{0, colorFontUnitsPerEm, 0, -colorFontUnitsPerEm} {
+		glyf.u16(x)
+	}
+	for _, y := range []int{0, 0, colorFontUnitsPerEm, 0} {
+		glyf.u16(y)
+	}
+	for glyf.Len()%4 != 0 {
+		glyf.u8(0)
+	}
+	tables["glyf"] = glyf.Bytes()
+
+	var loca fontBuilder
+	for i := 0; i < numGlyphs; i++ {
+		loca.u32(0)
+	}
+	loca.u32(glyf.Len())
+	tables["loca"] = loca.Bytes()
+
+	red := encodePNG(t, 4, 4, color.NRGBA{0xff, 0, 0, 0xff})
+	var sbix fontBuilder
+	sbix.u16(1)
+	sbix.u16(1)
+	sbix.u32(1)
+	sbix.u32(12)
+	sbix.u16(8)
+	sbix.u16(72)
+	const sbixGlyphDataOffset = 4 + 4*(numGlyphs+1)
+	for i := 0; i <= numGlyphs; i++ {
+		if i <= 1 {
+			sbix.u32(sbixGlyphDataOffset)
+		} else {
+			sbix.u32(sbixGlyphDataOffset + 8 + len(red))
+		}
+	}
+	sbix.u16(0)
+	sbix.u16(0)
+	sbix.WriteString("png ")
+	sbix.Write(red)
+	tables["sbix"] = sbix.Bytes()
+
+	green := encodePNG(t, 4, 4, color.NRGBA{0, 0xff, 0, 0xff})
+	var cbdt fontBuilder
+	cbdt.u16(3)
+	cbdt.u16(0)
+	cbdt.u8(4)
+	cbdt.u8(4)
+	cbdt.u8(1)
+	cbdt.u8(4)
+	cbdt.u8(4)
+	cbdt.u32(len(green))
+	cbdt.Write(green)
+	tables["CBDT"] = cbdt.Bytes()
+
+	var cblc fontBuilder
+	cblc.u16(3)
+	cblc.u16(0)
+	cblc.u32(1)
+	cblc.u32(56)
+	cblc.u32(24)
+	cblc.u32(1)
+	cblc.u32(0)
+	cblc.Write(make([]byte, 24))
+	cblc.u16(2)
+	cblc.u16(2)
+	cblc.u8(4)
+	cblc.u8(4)
+	cblc.u8(32)
+	cblc.u8(1)
+	cblc.u16(2)
+	cblc.u16(2)
+	cblc.u32(8)
+	cblc.u16(1)
+	cblc.u16(17)
+	cblc.u32(4)
+	cblc.u32(0)
+	cblc.u32(9 + len(green))
+	tables["CBLC"] = cblc.Bytes()
+
+	var colr fontBuilder
+	colr.u16(0)
+	colr.u16(1)
+	colr.u32(14)
+	colr.u32(20)
+	colr.u16(1)
+	colr.u16(3)
+	colr.u16(0)
+	colr.u16(1)
+	colr.u16(4)
+	colr.u16(0)
+	tables["COLR"] = colr.Bytes()
+
+	var cpal fontBuilder
+	cpal.u16(0)
+	cpal.u16(1)
+	cpal.u16(1)
+	cpal.u16(1)
+	cpal.u32(14)
+	cpal.u16(0)
+	cpal.Write([]byte{0xff, 0, 0, 0xff})
+	tables["CPAL"] = cpal.Bytes()
+
+	var tags []string
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	var f fontBuilder
+	f.u32(0x00010000)
+	f.u16(len(tags))
+	f.Write(make([]byte, 6))
+	offset := 12 + 16*len(tags)
+	for _, tag := range tags {
+		f.WriteString(tag)
+		f.u32(0)
+		f.u32(offset)
+		f.u32(len(tables[tag]))
+		offset += (len(tables[tag]) + 3) &^ 3
+	}
+	for _, tag := range tags {
+		f.Write(tables[tag])
+		for f.Len()%4 != 0 {
+			f.u8(0)
+		}
+	}
+	return f.Bytes()
+}
+
+func TestColorFace(t *testing.T) {
+	face, err := text.NewColorFace(newColorFont(t), &opentype.FaceOptions{
+		Size: colorFontUnitsPerEm,
+		DPI:  72,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		Rune   rune
+		Bounds image.Rectangle
+		Color  color.RGBA
+	}{
+		{
+			Rune:   'a',
+			Bounds: image.Rect(0, -4, 4, 0),
+			Color:  color.RGBA{0xff, 0, 0, 0xff},
+		},
+		{
+			Rune:   'b',
+			Bounds: image.Rect(2, -8, 10, 0),
+			Color:  color.RGBA{0, 0xff, 0, 0xff},
+		},
+		{
+			Rune:   'c',
+			Bounds: image.Rect(0, -8, 8, 0),
+			Color:  color.RGBA{0, 0, 0xff, 0xff},
+		},
+	}
+	for _, c := range cases {
+		t.Run(string(c.Rune), func(t *testing.T) {
+			b, _, ok := face.GlyphBounds(c.Rune)
+			if !ok {
+				t.Fatalf("GlyphBounds failed")
+			}
+			if got, want := b, fixed.R(c.Bounds.Min.X, c.Bounds.Min.Y, c.Bounds.Max.X, c.Bounds.Max.Y); got != want {
+				t.Errorf("bounds: got: %v, want: %v", got, want)
+			}
+
+			const size = 16
+			dst := ebiten.NewImage(size, size)
+			text.Draw(dst, string(c.Rune), face, 0, size/2, color.White)
+
+			for j := 0; j < size; j++ {
+				for i := 0; i < size; i++ {
+					got := dst.At(i, j)
+					want := color.RGBA{}
+					if image.Pt(i, j-size/2).In(c.Bounds) {
+						want = c.Color
+					}
+					if got != want {
+						t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package text
+
+import (
+	"golang.org/x/image/font"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// AdvanceTick advances the clock used for the glyph cache as if a tick passed.
+func AdvanceTick() {
+	monotonicClock++
+}
+
+func CachedGlyphImage(face font.Face, r rune) *ebiten.Image {
+	textM.Lock()
+	defer textM.Unlock()
+
+	e, ok := glyphImageCache[face][r]
+	if !ok {
+		return nil
+	}
+	return e.image
+}
@@ -0,0 +1,811 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Align represents an alignment of text in a layout box.
+type Align int
+
+const (
+	// AlignStart aligns text to the left or the top.
+	// In a vertical layout, AlignStart aligns the columns to the right, where the first column is.
+	AlignStart Align = iota
+
+	// AlignCenter aligns text to the center.
+	AlignCenter
+
+	// AlignEnd aligns text to the right or the bottom.
+	// In a vertical layout, AlignEnd aligns the columns to the left.
+	AlignEnd
+)
+
+// LayoutOptions represents options for text layout.
+//
+// In a layout, the origin point is the left-upper corner of the layout box, not a dot position.
+type LayoutOptions struct {
+	// Width is the width of the layout box in pixels.
+	// If Width is positive, lines are wrapped at word boundaries so that the lines fit with Width.
+	// A word longer than Width is broken at an arbitrary rune.
+	//
+	// The default (zero) value means that lines are never wrapped, and
+	// the longest line's width is used as the box width for alignment.
+	//
+	// In a vertical layout, Width is used only for horizontal alignment of the columns, and
+	// the default (zero) value means that the box width is the width of the columns.
+	Width float64
+
+	// Height is the height of the layout box in pixels.
+	// Height is used only for vertical alignment.
+	//
+	// The default (zero) value means that the box height is the height of the lines.
+	//
+	// In a vertical layout, Height works like Width in a horizontal layout:
+	// if Height is positive, columns are wrapped so that the columns fit with Height.
+	Height float64
+
+	// HorizontalAlign is the horizontal alignment of each line in the layout box.
+	//
+	// The default (zero) value is AlignStart.
+	HorizontalAlign Align
+
+	// VerticalAlign is the vertical alignment of the lines in the layout box.
+	//
+	// The default (zero) value is AlignStart.
+	VerticalAlign Align
+
+	// LineHeight is the distance between two baselines in pixels.
+	// In a vertical layout, LineHeight is the distance between the centers of two columns.
+	//
+	// The default (zero) value means that Metrics().Height of the face is used.
+	LineHeight float64
+
+	// TabWidth is the distance between two tab stops in pixels.
+	//
+	// The default (zero) value means that 4 times the advance of the space character is used.
+	TabWidth float64
+
+	// Shaping specifies whether the text is shaped in the same way as Shape.
+	// Lines are wrapped after Arabic letters are replaced with their contextual forms,
+	// and then the runes of each line are reordered into the visual order.
+	// See Shape for the limitations: only Arabic joining and bidirectional reordering are supported.
+	//
+	// The default (zero) value is false.
+	Shaping bool
+
+	// Vertical specifies whether the text is laid out vertically (tate-gaki).
+	//
+	// In a vertical layout, lines are columns from the top to the bottom, and the columns are from the right to
+	// the left.
+	// CJK characters are upright, and the other characters, e.g. Latin letters, are rotated by 90 degrees
+	// clockwise.
+	// Punctuations that have vertical presentation forms in Unicode, e.g. '、' and '「', are replaced with the forms.
+	// The vertical advance of an upright character is the sum of the face's ascent and descent.
+	//
+	// The default (zero) value is false.
+	Vertical bool
+
+	// Rubies are ruby annotations (e.g. furigana) of the text.
+	//
+	// A ruby text is rendered with RubyFace at the center of its base text, above the base text in a horizontal
+	// layout or right to the base text in a vertical layout.
+	// A base text is never broken into lines unless the base text is longer than a line.
+	// A ruby text is rendered outside the lines, so specify LineHeight to make space for the ruby texts.
+	// Ruby texts are not counted by MeasureLayout.
+	//
+	// The ranges of the base texts must not overlap.
+	//
+	// The default (nil) value means that there are no ruby annotations.
+	Rubies []Ruby
+
+	// RubyFace is the font face for the ruby texts, usually smaller than the face for the text.
+	//
+	// The default (nil) value means that the face for the text is used.
+	RubyFace font.Face
+}
+
+// Ruby represents a ruby annotation for a base text.
+type Ruby struct {
+	// Start is the byte offset of the base text's start in the laid out text.
+	Start int
+
+	// End is the byte offset of the base text's end (exclusive) in the laid out text.
+	End int
+
+	// Text is the ruby text.
+	Text string
+}
+
+// layoutGlyph is a glyph in a line.
+type layoutGlyph struct {
+	r rune
+
+	// x is the position of the glyph from the line's start.
+	// In a vertical layout, x is the position along the Y axis.
+	x fixed.Int26_6
+
+	// index is the byte offset of the rune in the text.
+	index int
+}
+
+type layoutLine struct {
+	glyphs []layoutGlyph
+
+	// width is the length of the line.
+	// In a vertical layout, width is the length along the Y axis.
+	width fixed.Int26_6
+}
+
+type layoutTokenKind int
+
+const (
+	layoutTokenWord layoutTokenKind = iota
+	layoutTokenSpace
+	layoutTokenTab
+)
+
+type layoutToken struct {
+	kind    layoutTokenKind
+	runes   []rune
+	indices []int
+}
+
+// isBreakableRune reports whether a line can be broken before and after r regardless of spaces.
+func isBreakableRune(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// isBreakableSpace reports whether r is a space where a line can be broken.
+// Non-breaking spaces are treated as a part of a word.
+func isBreakableSpace(r rune) bool {
+	switch r {
+	case '\u00a0', '\u2007', '\u202f':
+		return false
+	}
+	return unicode.IsSpace(r)
+}
+
+// verticalForms is a table of punctuations and their vertical presentation forms.
+var verticalForms = map[rune]rune{
+	'，': 0xfe10,
+	'、': 0xfe11,
+	'。': 0xfe12,
+	'：': 0xfe13,
+	'；': 0xfe14,
+	'！': 0xfe15,
+	'？': 0xfe16,
+	'〖': 0xfe17,
+	'〗': 0xfe18,
+	'…': 0xfe19,
+	'‥': 0xfe30,
+	'—': 0xfe31,
+	'–': 0xfe32,
+	'（': 0xfe35,
+	'）': 0xfe36,
+	'｛': 0xfe37,
+	'｝': 0xfe38,
+	'〔': 0xfe39,
+	'〕': 0xfe3a,
+	'【': 0xfe3b,
+	'】': 0xfe3c,
+	'《': 0xfe3d,
+	'》': 0xfe3e,
+	'〈': 0xfe3f,
+	'〉': 0xfe40,
+	'「': 0xfe41,
+	'」': 0xfe42,
+	'『': 0xfe43,
+	'』': 0xfe44,
+	'［': 0xfe47,
+	'］': 0xfe48,
+}
+
+// isSidewaysInVertical reports whether r is rotated by 90 degrees clockwise in a vertical layout.
+func isSidewaysInVertical(r rune) bool {
+	switch r {
+	case 'ー', '～', '〜', '－':
+		// Long lines along the writing direction.
+		return true
+	}
+	if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul, unicode.Bopomofo) {
+		return false
+	}
+	switch {
+	case 0x3000 <= r && r <= 0x303f:
+		// CJK Symbols and Punctuation
+		return false
+	case 0x3200 <= r && r <= 0x33ff:
+		// Enclosed CJK Letters and Months, CJK Compatibility
+		return false
+	case 0xfe10 <= r && r <= 0xfe1f:
+		// Vertical Forms
+		return false
+	case 0xfe30 <= r && r <= 0xfe4f:
+		// CJK Compatibility Forms
+		return false
+	case 0xff01 <= r && r <= 0xff60, 0xffe0 <= r && r <= 0xffe6:
+		// Fullwidth Forms
+		return false
+	}
+	return true
+}
+
+// splitLayoutTokens splits the runes into tokens.
+// rubyAt returns the index of the ruby whose base text includes the given byte offset, or -1.
+// A base text of a ruby is never split.
+func splitLayoutTokens(rs []rune, indices []int, rubyAt func(index int) int) []layoutToken {
+	var tokens []layoutToken
+	for i, r := range rs {
+		// Ignore '\r' so that CRLF works as a newline.
+		if r == '\r' {
+			continue
+		}
+
+		if len(tokens) > 0 {
+			last := &tokens[len(tokens)-1]
+			if ruby := rubyAt(indices[i]); ruby >= 0 && ruby == rubyAt(last.indices[len(last.indices)-1]) {
+				last.kind = layoutTokenWord
+				last.runes = append(last.runes, r)
+				last.indices = append(last.indices, indices[i])
+				continue
+			}
+		}
+
+		var kind layoutTokenKind
+		switch {
+		case r == '\t':
+			kind = layoutTokenTab
+		case isBreakableSpace(r):
+			kind = layoutTokenSpace
+		default:
+			kind = layoutTokenWord
+		}
+		if kind == layoutTokenWord && !isBreakableRune(r) && len(tokens) > 0 {
+			last := &tokens[len(tokens)-1]
+			if last.kind == layoutTokenWord && !isBreakableRune(last.runes[len(last.runes)-1]) {
+				last.runes = append(last.runes, r)
+				last.indices = append(last.indices, indices[i])
+				continue
+			}
+		}
+		if kind == layoutTokenSpace && len(tokens) > 0 && tokens[len(tokens)-1].kind == layoutTokenSpace {
+			last := &tokens[len(tokens)-1]
+			last.runes = append(last.runes, r)
+			last.indices = append(last.indices, indices[i])
+			continue
+		}
+		tokens = append(tokens, layoutToken{
+			kind:    kind,
+			runes:   []rune{r},
+			indices: []int{indices[i]},
+		})
+	}
+	return tokens
+}
+
+func float64ToFixed26_6(x float64) fixed.Int26_6 {
+	return fixed.Int26_6(math.Round(x * (1 << 6)))
+}
+
+// verticalAdvance returns the advance of r in a vertical layout.
+func verticalAdvance(face font.Face, r rune) fixed.Int26_6 {
+	if isSidewaysInVertical(r) {
+		return glyphAdvance(face, r)
+	}
+	m := face.Metrics()
+	return m.Ascent + m.Descent
+}
+
+type layouter struct {
+	face     font.Face
+	maxWidth fixed.Int26_6
+	tabWidth fixed.Int26_6
+	shaping  bool
+	vertical bool
+	rubyAt   func(index int) int
+
+	lines []layoutLine
+	line  layoutLine
+	x     fixed.Int26_6
+	prevR rune
+}
+
+func (l *layouter) advance(r rune) fixed.Int26_6 {
+	if l.vertical {
+		return verticalAdvance(l.face, r)
+	}
+	var a fixed.Int26_6
+	if l.prevR >= 0 {
+		a += l.face.Kern(l.prevR, r)
+	}
+	return a + glyphAdvance(l.face, r)
+}
+
+func (l *layouter) place(r rune, index int) {
+	if l.prevR >= 0 && !l.vertical {
+		l.x += l.face.Kern(l.prevR, r)
+	}
+	l.line.glyphs = append(l.line.glyphs, layoutGlyph{
+		r:     r,
+		x:     l.x,
+		index: index,
+	})
+	if l.vertical {
+		l.x += verticalAdvance(l.face, r)
+	} else {
+		l.x += glyphAdvance(l.face, r)
+	}
+	l.prevR = r
+}
+
+func (l *layouter) newLine() {
+	// Trailing spaces are not rendered at a wrapped line.
+	for len(l.line.glyphs) > 0 && isBreakableSpace(l.line.glyphs[len(l.line.glyphs)-1].r) {
+		l.line.glyphs = l.line.glyphs[:len(l.line.glyphs)-1]
+	}
+	l.lines = append(l.lines, l.line)
+	l.line = layoutLine{}
+	l.x = 0
+	l.prevR = -1
+}
+
+func (l *layouter) overflows(w fixed.Int26_6) bool {
+	return l.maxWidth > 0 && l.x+w > l.maxWidth && l.line.width > 0
+}
+
+// layoutParagraph lays out the runes of a paragraph. indices are the byte offsets of the runes in the text.
+func (l *layouter) layoutParagraph(rs []rune, indices []int) {
+	if l.shaping {
+		rs, indices = applyArabicForms(rs, indices)
+	}
+	if l.vertical {
+		for i, r := range rs {
+			if v, ok := verticalForms[r]; ok {
+				rs[i] = v
+			}
+		}
+	}
+	for _, t := range splitLayoutTokens(rs, indices, l.rubyAt) {
+		switch t.kind {
+		case layoutTokenSpace:
+			for i, r := range t.runes {
+				l.place(r, t.indices[i])
+			}
+		case layoutTokenTab:
+			n := l.x / l.tabWidth
+			l.x = (n + 1) * l.tabWidth
+			l.prevR = -1
+		case layoutTokenWord:
+			prevR := l.prevR
+			var w fixed.Int26_6
+			for _, r := range t.runes {
+				w += l.advance(r)
+				l.prevR = r
+			}
+			l.prevR = prevR
+
+			if l.overflows(w) {
+				l.newLine()
+			}
+			for i, r := range t.runes {
+				// Break a too long word at an arbitrary rune.
+				if l.overflows(l.advance(r)) {
+					l.newLine()
+				}
+				l.place(r, t.indices[i])
+				l.line.width = l.x
+			}
+		}
+	}
+	l.newLine()
+}
+
+// layoutText lays out the text. textM must be locked when layoutText is called.
+func layoutText(face font.Face, text string, options *LayoutOptions) []layoutLine {
+	maxWidth := options.Width
+	if options.Vertical {
+		maxWidth = options.Height
+	}
+	l := &layouter{
+		face:     face,
+		maxWidth: float64ToFixed26_6(maxWidth),
+		tabWidth: float64ToFixed26_6(options.TabWidth),
+		shaping:  options.Shaping,
+		vertical: options.Vertical,
+		rubyAt:   rubyAtFunc(text, options.Rubies),
+		prevR:    -1,
+	}
+	if l.tabWidth <= 0 {
+		l.tabWidth = 4 * glyphAdvance(face, ' ')
+	}
+	if l.tabWidth <= 0 {
+		l.tabWidth = fixed.I(1)
+	}
+
+	var offset int
+	for _, p := range strings.Split(text, "\n") {
+		rs := make([]rune, 0, len(p))
+		indices := make([]int, 0, len(p))
+		for i, r := range p {
+			rs = append(rs, r)
+			indices = append(indices, offset+i)
+		}
+		l.layoutParagraph(rs, indices)
+		offset += len(p) + 1
+	}
+	if l.shaping {
+		for _, line := range l.lines {
+			reorderLayoutLine(face, line)
+		}
+	}
+	return l.lines
+}
+
+// rubyAtFunc returns a function that returns the index of the ruby whose base text includes the given byte offset,
+// or -1.
+func rubyAtFunc(text string, rubies []Ruby) func(index int) int {
+	for _, r := range rubies {
+		if r.Start < 0 || r.End > len(text) || r.Start > r.End {
+			panic(fmt.Sprintf("text: invalid ruby range [%d, %d) for the text length %d", r.Start, r.End, len(text)))
+		}
+	}
+	return func(index int) int {
+		for i, r := range rubies {
+			if r.Start <= index && index < r.End {
+				return i
+			}
+		}
+		return -1
+	}
+}
+
+// reorderLayoutLine reorders the glyphs of the line in place into the visual order.
+// Each glyph keeps the distance to the next glyph in the logical order, so that tab gaps are kept.
+func reorderLayoutLine(face font.Face, line layoutLine) {
+	gs := line.glyphs
+	if len(gs) == 0 {
+		return
+	}
+
+	rs := make([]rune, len(gs))
+	advances := make([]fixed.Int26_6, len(gs))
+	for i, g := range gs {
+		rs[i] = g.r
+		if i < len(gs)-1 {
+			advances[i] = gs[i+1].x - g.x
+		} else {
+			advances[i] = glyphAdvance(face, g.r)
+		}
+	}
+
+	x := gs[0].x
+
+	levels := bidiLevels(rs)
+	mirrorRunes(rs, levels)
+	for i := range gs {
+		gs[i].r = rs[i]
+	}
+	reorderByLevels(len(gs), levels, func(i, j int) {
+		gs[i], gs[j] = gs[j], gs[i]
+		advances[i], advances[j] = advances[j], advances[i]
+	})
+
+	for i := range gs {
+		gs[i].x = x
+		x += advances[i]
+	}
+}
+
+// alignOffset returns the offset of a content in a box with the given space.
+// If the content overflows the box, i.e., space is negative, the content is aligned at the start.
+func alignOffset(space fixed.Int26_6, align Align) fixed.Int26_6 {
+	if space <= 0 {
+		return 0
+	}
+	switch align {
+	case AlignCenter:
+		return space / 2
+	case AlignEnd:
+		return space
+	default:
+		return 0
+	}
+}
+
+func lineHeight(face font.Face, options *LayoutOptions) fixed.Int26_6 {
+	if options.LineHeight > 0 {
+		return float64ToFixed26_6(options.LineHeight)
+	}
+	return face.Metrics().Height
+}
+
+// layoutDot returns the dot position of r at pos from the line's origin.
+//
+// In a horizontal layout, the origin is the dot position of the line's start.
+// In a vertical layout, the origin is the center-top of the column. sideways reports whether the glyph is rotated by
+// 90 degrees clockwise around the dot.
+func layoutDot(face font.Face, r rune, vertical bool, origin fixed.Point26_6, pos fixed.Int26_6) (dx, dy fixed.Int26_6, sideways bool) {
+	if !vertical {
+		return origin.X + pos, origin.Y, false
+	}
+	m := face.Metrics()
+	if isSidewaysInVertical(r) {
+		// Center the rotated em box at the column.
+		return origin.X - (m.Ascent-m.Descent)/2, origin.Y + pos, true
+	}
+	return origin.X - glyphAdvance(face, r)/2, origin.Y + pos + m.Ascent, false
+}
+
+// forEachLayoutGlyph lays out the text and calls f with each glyph's face and dot position.
+// Glyphs of the ruby texts are given with the ruby face.
+// textM must be locked when forEachLayoutGlyph is called.
+func forEachLayoutGlyph(face font.Face, text string, options *LayoutOptions, f func(face font.Face, r rune, dx, dy fixed.Int26_6, sideways bool)) {
+	if options == nil {
+		options = &LayoutOptions{}
+	}
+
+	lines := layoutText(face, text, options)
+	lh := lineHeight(face, options)
+	m := face.Metrics()
+
+	// Calculate the origins of the lines.
+	origins := make([]fixed.Point26_6, len(lines))
+	if !options.Vertical {
+		boxWidth := float64ToFixed26_6(options.Width)
+		if boxWidth <= 0 {
+			for _, l := range lines {
+				if boxWidth < l.width {
+					boxWidth = l.width
+				}
+			}
+		}
+
+		var offsetY fixed.Int26_6
+		if options.Height > 0 {
+			offsetY = alignOffset(float64ToFixed26_6(options.Height)-lh*fixed.Int26_6(len(lines)), options.VerticalAlign)
+		}
+		// The first baseline is at the ascent from the top.
+		offsetY += m.Ascent
+
+		for i, l := range lines {
+			origins[i] = fixed.Point26_6{
+				X: alignOffset(boxWidth-l.width, options.HorizontalAlign),
+				Y: offsetY + lh*fixed.Int26_6(i),
+			}
+		}
+	} else {
+		boxHeight := float64ToFixed26_6(options.Height)
+		if boxHeight <= 0 {
+			for _, l := range lines {
+				if boxHeight < l.width {
+					boxHeight = l.width
+				}
+			}
+		}
+
+		// The first column is at the right.
+		columnsWidth := lh * fixed.Int26_6(len(lines))
+		right := columnsWidth
+		if options.Width > 0 {
+			boxWidth := float64ToFixed26_6(options.Width)
+			right = boxWidth - alignOffset(boxWidth-columnsWidth, options.HorizontalAlign)
+		}
+
+		for i, l := range lines {
+			origins[i] = fixed.Point26_6{
+				X: right - lh*fixed.Int26_6(i) - lh/2,
+				Y: alignOffset(boxHeight-l.width, options.VerticalAlign),
+			}
+		}
+	}
+
+	for i, l := range lines {
+		for _, g := range l.glyphs {
+			dx, dy, sideways := layoutDot(face, g.r, options.Vertical, origins[i], g.x)
+			f(face, g.r, dx, dy, sideways)
+		}
+	}
+
+	if len(options.Rubies) == 0 {
+		return
+	}
+
+	rubyFace := options.RubyFace
+	if rubyFace == nil {
+		rubyFace = face
+	}
+	rm := rubyFace.Metrics()
+
+	for _, ruby := range options.Rubies {
+		// Find the base text in the line where the base text starts.
+		var line int
+		var start, end fixed.Int26_6
+		found := false
+		for i, l := range lines {
+			for _, g := range l.glyphs {
+				if g.index < ruby.Start || g.index >= ruby.End {
+					continue
+				}
+				var a fixed.Int26_6
+				if options.Vertical {
+					a = verticalAdvance(face, g.r)
+				} else {
+					a = glyphAdvance(face, g.r)
+				}
+				if !found {
+					line, start, end = i, g.x, g.x+a
+					found = true
+					continue
+				}
+				if start > g.x {
+					start = g.x
+				}
+				if end < g.x+a {
+					end = g.x + a
+				}
+			}
+			if found {
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+
+		var rubyWidth fixed.Int26_6
+		prevR := rune(-1)
+		for _, r := range ruby.Text {
+			if options.Vertical {
+				rubyWidth += verticalAdvance(rubyFace, r)
+				continue
+			}
+			if prevR >= 0 {
+				rubyWidth += rubyFace.Kern(prevR, r)
+			}
+			rubyWidth += glyphAdvance(rubyFace, r)
+			prevR = r
+		}
+
+		origin := origins[line]
+		if options.Vertical {
+			origin.X += (m.Ascent+m.Descent)/2 + (rm.Ascent+rm.Descent)/2
+		} else {
+			origin.Y -= m.Ascent + rm.Descent
+		}
+
+		pos := (start+end)/2 - rubyWidth/2
+		prevR = -1
+		for _, r := range ruby.Text {
+			if options.Vertical {
+				if v, ok := verticalForms[r]; ok {
+					r = v
+				}
+			} else if prevR >= 0 {
+				pos += rubyFace.Kern(prevR, r)
+			}
+			dx, dy, sideways := layoutDot(rubyFace, r, options.Vertical, origin, pos)
+			f(rubyFace, r, dx, dy, sideways)
+			if options.Vertical {
+				pos += verticalAdvance(rubyFace, r)
+			} else {
+				pos += glyphAdvance(rubyFace, r)
+			}
+			prevR = r
+		}
+	}
+}
+
+// AppendLayoutGlyphs lays out the text with the given options and appends the glyph information to glyphs.
+//
+// The glyph positions' origin is the left-upper corner of the layout box.
+// See LayoutOptions for details.
+//
+// AppendLayoutGlyphs is concurrent-safe.
+func AppendLayoutGlyphs(glyphs []Glyph, face font.Face, text string, options *LayoutOptions) []Glyph {
+	textM.Lock()
+	defer textM.Unlock()
+
+	forEachLayoutGlyph(face, text, options, func(face font.Face, r rune, dx, dy fixed.Int26_6, sideways bool) {
+		img := getGlyphImage(face, r)
+		if img == nil {
+			return
+		}
+		b := getGlyphBounds(face, r)
+		g := Glyph{
+			Rune:     r,
+			Image:    img,
+			X:        math.Floor(fixed26_6ToFloat64(dx + b.Min.X)),
+			Y:        math.Floor(fixed26_6ToFloat64(dy + b.Min.Y)),
+			Sideways: sideways,
+		}
+		if sideways {
+			g.X = math.Floor(fixed26_6ToFloat64(dx - b.Min.Y))
+			g.Y = math.Floor(fixed26_6ToFloat64(dy + b.Min.X))
+		}
+		glyphs = append(glyphs, g)
+	})
+
+	cleanUpLayoutGlyphImageCache(face, options)
+
+	return glyphs
+}
+
+// DrawLayout lays out the text with the given options and draws it on the given destination image dst.
+//
+// The origin point of drawOptions's GeoM is the left-upper corner of the layout box.
+// The default glyph color is white. drawOptions's ColorM adjusts the color.
+//
+// DrawLayout is concurrent-safe.
+func DrawLayout(dst *ebiten.Image, text string, face font.Face, options *LayoutOptions, drawOptions *ebiten.DrawImageOptions) {
+	textM.Lock()
+	defer textM.Unlock()
+
+	forEachLayoutGlyph(face, text, options, func(face font.Face, r rune, dx, dy fixed.Int26_6, sideways bool) {
+		img := getGlyphImage(face, r)
+		if sideways {
+			drawSidewaysGlyph(dst, face, r, img, dx, dy, drawOptions)
+			return
+		}
+		drawGlyph(dst, face, r, img, dx, dy, drawOptions)
+	})
+
+	cleanUpLayoutGlyphImageCache(face, options)
+}
+
+func cleanUpLayoutGlyphImageCache(face font.Face, options *LayoutOptions) {
+	cleanUpGlyphImageCache(face)
+	if options != nil && options.RubyFace != nil && options.RubyFace != face {
+		cleanUpGlyphImageCache(options.RubyFace)
+	}
+}
+
+// MeasureLayout returns the size of the block of the text laid out with the given options.
+//
+// The width is the longest line's width, and the height is the number of lines multiplied by the line height.
+// In a vertical layout, the width is the number of columns multiplied by the line height, and the height is the
+// longest column's height.
+// Trailing spaces of wrapped lines are not counted.
+//
+// MeasureLayout is concurrent-safe.
+func MeasureLayout(face font.Face, text string, options *LayoutOptions) (width, height float64) {
+	textM.Lock()
+	defer textM.Unlock()
+
+	if options == nil {
+		options = &LayoutOptions{}
+	}
+	lines := layoutText(face, text, options)
+	var w fixed.Int26_6
+	for _, l := range lines {
+		if w < l.width {
+			w = l.width
+		}
+	}
+	h := lineHeight(face, options) * fixed.Int26_6(len(lines))
+	if options.Vertical {
+		w, h = h, w
+	}
+	return fixed26_6ToFloat64(w), fixed26_6ToFloat64(h)
+}
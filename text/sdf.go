@@ -0,0 +1,274 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// sdfSpread is the distance in pixels from a glyph outline where the signed distance field is saturated.
+// A glyph image for SDF has this padding on each side.
+const sdfSpread = 8
+
+// sdfShaderSrc is a shader to render a glyph from its signed distance field.
+//
+// The distance is interpolated bilinearly regardless of the filter, and the outline is antialiased
+// by the screen-space derivative of the distance.
+var sdfShaderSrc = []byte(`package main
+
+var Color vec4
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	size := imageSrcTextureSize()
+	p := texCoord*size - 0.5
+	f := fract(p)
+	b := (floor(p) + 0.5) / size
+	d00 := imageSrc0At(b).a
+	d10 := imageSrc0At(b + vec2(1, 0)/size).a
+	d01 := imageSrc0At(b + vec2(0, 1)/size).a
+	d11 := imageSrc0At(b + vec2(1, 1)/size).a
+	d := mix(mix(d00, d10, f.x), mix(d01, d11, f.x), f.y)
+	w := max(fwidth(d), 1.0/1024.0)
+	return Color * smoothstep(0.5-w, 0.5+w, d)
+}
+`)
+
+var sdfShader *ebiten.Shader
+
+// sdfFace is a font.Face whose glyph masks are signed distance fields of the underlying face's glyphs.
+//
+// A value of a glyph mask's channels is 0.5 at the outline, larger inside and smaller outside,
+// and changes 0.5 per sdfSpread pixels.
+//
+// sdfFace is used as a key of the glyph cache so that the glyphs for SDF are cached separately from the regular
+// glyphs.
+type sdfFace struct {
+	font.Face
+}
+
+func (f sdfFace) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	dr, mask, maskp, advance, ok = f.Face.Glyph(dot, r)
+	if !ok || dr.Empty() {
+		return
+	}
+
+	w, h := dr.Dx()+2*sdfSpread, dr.Dy()+2*sdfSpread
+	inside := make([]bool, w*h)
+	for j := 0; j < dr.Dy(); j++ {
+		for i := 0; i < dr.Dx(); i++ {
+			_, _, _, a := mask.At(maskp.X+i, maskp.Y+j).RGBA()
+			inside[(j+sdfSpread)*w+i+sdfSpread] = a >= 0x8000
+		}
+	}
+
+	sdf := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i, d := range signedDistanceField(inside, w, h) {
+		v := uint8(math.Round(255 * math.Max(0, math.Min(1, 0.5+d/(2*sdfSpread)))))
+		sdf.Pix[4*i] = v
+		sdf.Pix[4*i+1] = v
+		sdf.Pix[4*i+2] = v
+		sdf.Pix[4*i+3] = v
+	}
+	return dr.Inset(-sdfSpread), sdf, image.Point{}, advance, true
+}
+
+func (f sdfFace) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	bounds, advance, ok = f.Face.GlyphBounds(r)
+	if !ok || bounds.Empty() {
+		return
+	}
+	bounds.Min = bounds.Min.Sub(fixed.P(sdfSpread, sdfSpread))
+	bounds.Max = bounds.Max.Add(fixed.P(sdfSpread, sdfSpread))
+	return
+}
+
+// signedDistanceField returns the signed distances in pixels from the outline of the given shape.
+// The distances are positive inside and negative outside.
+func signedDistanceField(inside []bool, width, height int) []float64 {
+	in := distanceTransform(inside, width, height, true)
+	out := distanceTransform(inside, width, height, false)
+	ds := make([]float64, len(inside))
+	for i := range ds {
+		// The outline is between the centers of an inside pixel and an outside pixel.
+		if inside[i] {
+			ds[i] = out[i] - 0.5
+		} else {
+			ds[i] = -(in[i] - 0.5)
+		}
+	}
+	return ds
+}
+
+// distanceTransform returns the Euclidean distances from each pixel to the nearest pixel whose inside value is
+// target, by the 8-point sequential signed Euclidean distance transform (8SSEDT).
+func distanceTransform(inside []bool, width, height int, target bool) []float64 {
+	const far = 1 << 14
+
+	type offset struct {
+		x, y int
+	}
+	offsets := make([]offset, len(inside))
+	for i, v := range inside {
+		if v != target {
+			offsets[i] = offset{far, far}
+		}
+	}
+
+	dist2 := func(o offset) int {
+		return o.x*o.x + o.y*o.y
+	}
+	compare := func(x, y, dx, dy int) {
+		if x+dx < 0 || x+dx >= width || y+dy < 0 || y+dy >= height {
+			return
+		}
+		o := offsets[(y+dy)*width+x+dx]
+		o.x += dx
+		o.y += dy
+		if dist2(o) < dist2(offsets[y*width+x]) {
+			offsets[y*width+x] = o
+		}
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			compare(x, y, -1, 0)
+			compare(x, y, 0, -1)
+			compare(x, y, -1, -1)
+			compare(x, y, 1, -1)
+		}
+		for x := width - 1; x >= 0; x-- {
+			compare(x, y, 1, 0)
+		}
+	}
+	for y := height - 1; y >= 0; y-- {
+		for x := width - 1; x >= 0; x-- {
+			compare(x, y, 1, 0)
+			compare(x, y, 0, 1)
+			compare(x, y, -1, 1)
+			compare(x, y, 1, 1)
+		}
+		for x := 0; x < width; x++ {
+			compare(x, y, -1, 0)
+		}
+	}
+
+	ds := make([]float64, len(offsets))
+	for i, o := range offsets {
+		ds[i] = math.Sqrt(float64(dist2(o)))
+	}
+	return ds
+}
+
+// DrawSDFOptions represents options for DrawSDF.
+type DrawSDFOptions struct {
+	// GeoM is a geometry matrix to draw.
+	// The origin point is the first character's dot (period) position.
+	// GeoM can scale and rotate the text smoothly.
+	//
+	// The default (zero) value is identity.
+	GeoM ebiten.GeoM
+
+	// Color is the text color.
+	//
+	// The default (nil) value is white.
+	Color color.Color
+
+	// CompositeMode is a composite mode to draw.
+	//
+	// The default (zero) value is regular alpha blending.
+	CompositeMode ebiten.CompositeMode
+}
+
+// DrawSDF draws a given text on a given destination image dst with signed distance fields (SDF) of the glyphs.
+//
+// The SDFs are generated from the glyphs of face when the glyphs are cached, and rendered by a built-in shader.
+// Unlike Draw, the text stays crisp when the text is scaled up or rotated by options's GeoM,
+// so one face can be used for multiple sizes.
+// A face with a moderate size, e.g. 32 or 48 pixels, is recommended, since thin parts of glyphs and sharp corners
+// are rounded in SDF.
+//
+// DrawSDF uses only the alpha values of the glyphs, so color glyphs are rendered with a single color.
+//
+// The SDF glyphs are cached in the same glyph cache as the glyphs for Draw separately.
+// Use PinGlyphs and CacheGlyphs for the regular glyphs; they don't affect the SDF glyphs.
+//
+// DrawSDF is concurrent-safe.
+func DrawSDF(dst *ebiten.Image, text string, face font.Face, options *DrawSDFOptions) {
+	textM.Lock()
+	defer textM.Unlock()
+
+	if options == nil {
+		options = &DrawSDFOptions{}
+	}
+	if sdfShader == nil {
+		s, err := ebiten.NewShader(sdfShaderSrc)
+		if err != nil {
+			panic(fmt.Sprintf("text: NewShader for SDF failed: %v", err))
+		}
+		sdfShader = s
+	}
+
+	clr := []float32{1, 1, 1, 1}
+	if options.Color != nil {
+		r, g, b, a := options.Color.RGBA()
+		clr = []float32{float32(r) / 0xffff, float32(g) / 0xffff, float32(b) / 0xffff, float32(a) / 0xffff}
+	}
+
+	sface := sdfFace{face}
+
+	var dx, dy fixed.Int26_6
+	prevR := rune(-1)
+
+	faceHeight := face.Metrics().Height
+
+	for _, r := range text {
+		if prevR >= 0 {
+			dx += face.Kern(prevR, r)
+		}
+		if r == '\n' {
+			dx = 0
+			dy += faceHeight
+			prevR = rune(-1)
+			continue
+		}
+
+		if img := getGlyphImage(sface, r); img != nil {
+			b := getGlyphBounds(sface, r)
+			op := &ebiten.DrawRectShaderOptions{}
+			op.GeoM.Translate(math.Floor(fixed26_6ToFloat64(dx+b.Min.X)), math.Floor(fixed26_6ToFloat64(dy+b.Min.Y)))
+			op.GeoM.Concat(options.GeoM)
+			op.CompositeMode = options.CompositeMode
+			op.Uniforms = map[string]interface{}{
+				"Color": clr,
+			}
+			op.Images[0] = img
+			w, h := img.Size()
+			dst.DrawRectShader(w, h, sdfShader, op)
+		}
+		dx += glyphAdvance(face, r)
+
+		prevR = r
+	}
+
+	cleanUpGlyphImageCache(sface)
+}
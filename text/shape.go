@@ -0,0 +1,474 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+// Shape shapes the given text so that it can be rendered rune by rune by Draw and the other functions.
+//
+// Shape does these for each line:
+//
+//     * Replaces Arabic letters with their contextual forms (isolated, initial, medial and final) and
+//       lam-alef ligatures in Unicode's Arabic Presentation Forms.
+//     * Reorders runes from the logical order into the visual order based on a simplified Unicode bidirectional
+//       algorithm, and mirrors brackets in right-to-left runs.
+//
+// The given font must have glyphs for Arabic Presentation Forms to render Arabic text correctly.
+//
+// Shape works on runes and doesn't use the glyph substitution and positioning tables (GSUB and GPOS) of the font.
+// Then, Shape is not a complete shaper like HarfBuzz: Shape doesn't shape the scripts that require these tables,
+// such as Devanagari and Thai, and doesn't apply ligatures other than lam-alef, e.g., Latin ligatures like "fi",
+// nor mark positioning.
+// Shape doesn't support explicit bidirectional formatting characters either.
+//
+// To wrap lines of shaped text, use LayoutOptions's Shaping instead of Shape,
+// since lines must be broken before being reordered.
+func Shape(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		rs, _ := applyArabicForms([]rune(l), nil)
+		reorderRunes(rs, bidiLevels(rs))
+		lines[i] = string(rs)
+	}
+	return strings.Join(lines, "\n")
+}
+
+type arabicJoiningType int
+
+const (
+	arabicNonJoining arabicJoiningType = iota
+	arabicRightJoining
+	arabicDualJoining
+	arabicJoinCausing
+	arabicTransparent
+)
+
+type arabicForms struct {
+	isolated rune
+	final    rune
+	initial  rune
+	medial   rune
+}
+
+// arabicFormsTable is a table of Arabic letters' presentation forms.
+// A letter that has no initial and medial forms is right-joining.
+var arabicFormsTable = map[rune]arabicForms{
+	0x0621: {0xFE80, 0, 0, 0},
+	0x0622: {0xFE81, 0xFE82, 0, 0},
+	0x0623: {0xFE83, 0xFE84, 0, 0},
+	0x0624: {0xFE85, 0xFE86, 0, 0},
+	0x0625: {0xFE87, 0xFE88, 0, 0},
+	0x0626: {0xFE89, 0xFE8A, 0xFE8B, 0xFE8C},
+	0x0627: {0xFE8D, 0xFE8E, 0, 0},
+	0x0628: {0xFE8F, 0xFE90, 0xFE91, 0xFE92},
+	0x0629: {0xFE93, 0xFE94, 0, 0},
+	0x062A: {0xFE95, 0xFE96, 0xFE97, 0xFE98},
+	0x062B: {0xFE99, 0xFE9A, 0xFE9B, 0xFE9C},
+	0x062C: {0xFE9D, 0xFE9E, 0xFE9F, 0xFEA0},
+	0x062D: {0xFEA1, 0xFEA2, 0xFEA3, 0xFEA4},
+	0x062E: {0xFEA5, 0xFEA6, 0xFEA7, 0xFEA8},
+	0x062F: {0xFEA9, 0xFEAA, 0, 0},
+	0x0630: {0xFEAB, 0xFEAC, 0, 0},
+	0x0631: {0xFEAD, 0xFEAE, 0, 0},
+	0x0632: {0xFEAF, 0xFEB0, 0, 0},
+	0x0633: {0xFEB1, 0xFEB2, 0xFEB3, 0xFEB4},
+	0x0634: {0xFEB5, 0xFEB6, 0xFEB7, 0xFEB8},
+	0x0635: {0xFEB9, 0xFEBA, 0xFEBB, 0xFEBC},
+	0x0636: {0xFEBD, 0xFEBE, 0xFEBF, 0xFEC0},
+	0x0637: {0xFEC1, 0xFEC2, 0xFEC3, 0xFEC4},
+	0x0638: {0xFEC5, 0xFEC6, 0xFEC7, 0xFEC8},
+	0x0639: {0xFEC9, 0xFECA, 0xFECB, 0xFECC},
+	0x063A: {0xFECD, 0xFECE, 0xFECF, 0xFED0},
+	0x0641: {0xFED1, 0xFED2, 0xFED3, 0xFED4},
+	0x0642: {0xFED5, 0xFED6, 0xFED7, 0xFED8},
+	0x0643: {0xFED9, 0xFEDA, 0xFEDB, 0xFEDC},
+	0x0644: {0xFEDD, 0xFEDE, 0xFEDF, 0xFEE0},
+	0x0645: {0xFEE1, 0xFEE2, 0xFEE3, 0xFEE4},
+	0x0646: {0xFEE5, 0xFEE6, 0xFEE7, 0xFEE8},
+	0x0647: {0xFEE9, 0xFEEA, 0xFEEB, 0xFEEC},
+	0x0648: {0xFEED, 0xFEEE, 0, 0},
+	0x0649: {0xFEEF, 0xFEF0, 0, 0},
+	0x064A: {0xFEF1, 0xFEF2, 0xFEF3, 0xFEF4},
+	0x067E: {0xFB56, 0xFB57, 0xFB58, 0xFB59},
+	0x0686: {0xFB7A, 0xFB7B, 0xFB7C, 0xFB7D},
+	0x0698: {0xFB8A, 0xFB8B, 0, 0},
+	0x06A9: {0xFB8E, 0xFB8F, 0xFB90, 0xFB91},
+	0x06AF: {0xFB92, 0xFB93, 0xFB94, 0xFB95},
+	0x06CC: {0xFBFC, 0xFBFD, 0xFBFE, 0xFBFF},
+}
+
+// lamAlefLigatures is a table of lam-alef ligatures' isolated and final forms.
+var lamAlefLigatures = map[rune][2]rune{
+	0x0622: {0xFEF5, 0xFEF6},
+	0x0623: {0xFEF7, 0xFEF8},
+	0x0625: {0xFEF9, 0xFEFA},
+	0x0627: {0xFEFB, 0xFEFC},
+}
+
+const arabicLam = 0x0644
+
+func arabicJoiningTypeOf(r rune) arabicJoiningType {
+	if r == 0x0640 || r == 0x200D {
+		// Tatweel and zero width joiner.
+		return arabicJoinCausing
+	}
+	if f, ok := arabicFormsTable[r]; ok {
+		switch {
+		case f.initial != 0:
+			return arabicDualJoining
+		case f.final != 0:
+			return arabicRightJoining
+		default:
+			return arabicNonJoining
+		}
+	}
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return arabicTransparent
+	}
+	return arabicNonJoining
+}
+
+// joinsWithFollowing reports whether a rune of the joining type t can join with the following rune.
+func (t arabicJoiningType) joinsWithFollowing() bool {
+	return t == arabicDualJoining || t == arabicJoinCausing
+}
+
+// joinsWithPreceding reports whether a rune of the joining type t can join with the preceding rune.
+func (t arabicJoiningType) joinsWithPreceding() bool {
+	return t == arabicDualJoining || t == arabicRightJoining || t == arabicJoinCausing
+}
+
+// applyArabicForms returns runes in the logical order where Arabic letters are replaced with their contextual forms.
+//
+// indices are arbitrary values associated with the runes, e.g. byte offsets. applyArabicForms returns the values
+// for the returned runes, where a ligature takes the value of its first rune. indices can be nil.
+func applyArabicForms(rs []rune, indices []int) ([]rune, []int) {
+	types := make([]arabicJoiningType, len(rs))
+	for i, r := range rs {
+		types[i] = arabicJoiningTypeOf(r)
+	}
+
+	// neighbor returns the index of the nearest non-transparent rune from i in the direction d, or -1.
+	neighbor := func(i, d int) int {
+		for j := i + d; j >= 0 && j < len(rs); j += d {
+			if types[j] != arabicTransparent {
+				return j
+			}
+		}
+		return -1
+	}
+
+	result := make([]rune, 0, len(rs))
+	var resultIndices []int
+	for i := 0; i < len(rs); i++ {
+		if indices != nil {
+			resultIndices = append(resultIndices, indices[i])
+		}
+
+		r := rs[i]
+		f, ok := arabicFormsTable[r]
+		if !ok {
+			result = append(result, r)
+			continue
+		}
+
+		prev := neighbor(i, -1)
+		joinsPrev := prev >= 0 && types[prev].joinsWithFollowing() && types[i].joinsWithPreceding()
+
+		if r == arabicLam {
+			if next := neighbor(i, 1); next == i+1 {
+				if l, ok := lamAlefLigatures[rs[next]]; ok {
+					if joinsPrev {
+						result = append(result, l[1])
+					} else {
+						result = append(result, l[0])
+					}
+					// The ligature is right-joining.
+					types[next] = arabicRightJoining
+					i++
+					continue
+				}
+			}
+		}
+
+		next := neighbor(i, 1)
+		joinsNext := next >= 0 && types[i].joinsWithFollowing() && types[next].joinsWithPreceding()
+
+		switch {
+		case joinsPrev && joinsNext && f.medial != 0:
+			result = append(result, f.medial)
+		case joinsPrev && f.final != 0:
+			result = append(result, f.final)
+		case joinsNext && f.initial != 0:
+			result = append(result, f.initial)
+		default:
+			result = append(result, f.isolated)
+		}
+	}
+	return result, resultIndices
+}
+
+func bidiClass(r rune) bidi.Class {
+	p, _ := bidi.LookupRune(r)
+	return p.Class()
+}
+
+// bidiLevels returns the embedding levels of the given runes in a paragraph, based on a simplified Unicode
+// bidirectional algorithm without explicit formatting characters.
+//
+// The paragraph level is determined by the first strong character.
+func bidiLevels(rs []rune) []int {
+	classes := make([]bidi.Class, len(rs))
+	paragraphLevel := -1
+	for i, r := range rs {
+		c := bidiClass(r)
+		classes[i] = c
+		if paragraphLevel == -1 {
+			switch c {
+			case bidi.L:
+				paragraphLevel = 0
+			case bidi.R, bidi.AL:
+				paragraphLevel = 1
+			}
+		}
+	}
+	if paragraphLevel == -1 {
+		paragraphLevel = 0
+	}
+
+	levels := make([]int, len(rs))
+	if paragraphLevel == 0 {
+		// Skip the algorithm for a pure left-to-right paragraph.
+		rtl := false
+		for _, c := range classes {
+			if c == bidi.R || c == bidi.AL || c == bidi.AN {
+				rtl = true
+				break
+			}
+		}
+		if !rtl {
+			return levels
+		}
+	}
+
+	sos := bidi.L
+	if paragraphLevel == 1 {
+		sos = bidi.R
+	}
+
+	// Resolve weak types (W1-W7).
+	prevStrong := sos
+	for i, c := range classes {
+		if c == bidi.NSM {
+			if i == 0 {
+				c = sos
+			} else {
+				c = classes[i-1]
+			}
+		}
+		switch c {
+		case bidi.L, bidi.R:
+			prevStrong = c
+		case bidi.AL:
+			prevStrong = c
+			c = bidi.R
+		case bidi.EN:
+			if prevStrong == bidi.AL {
+				c = bidi.AN
+			}
+		}
+		classes[i] = c
+	}
+	for i := 1; i < len(classes)-1; i++ {
+		if classes[i] != bidi.ES && classes[i] != bidi.CS {
+			continue
+		}
+		if classes[i-1] == bidi.EN && classes[i+1] == bidi.EN {
+			classes[i] = bidi.EN
+		} else if classes[i] == bidi.CS && classes[i-1] == bidi.AN && classes[i+1] == bidi.AN {
+			classes[i] = bidi.AN
+		}
+	}
+	for i := 0; i < len(classes); i++ {
+		if classes[i] != bidi.ET {
+			continue
+		}
+		j := i
+		for j < len(classes) && classes[j] == bidi.ET {
+			j++
+		}
+		if (i > 0 && classes[i-1] == bidi.EN) || (j < len(classes) && classes[j] == bidi.EN) {
+			for k := i; k < j; k++ {
+				classes[k] = bidi.EN
+			}
+		}
+		i = j - 1
+	}
+	prevStrong = sos
+	for i, c := range classes {
+		switch c {
+		case bidi.L, bidi.R:
+			prevStrong = c
+		case bidi.EN:
+			if prevStrong == bidi.L {
+				classes[i] = bidi.L
+			}
+		}
+	}
+
+	// Resolve neutral types (N1-N2). European and Arabic numbers work as R.
+	strong := func(c bidi.Class) (bidi.Class, bool) {
+		switch c {
+		case bidi.L:
+			return bidi.L, true
+		case bidi.R, bidi.EN, bidi.AN:
+			return bidi.R, true
+		}
+		return 0, false
+	}
+	for i := 0; i < len(classes); i++ {
+		if _, ok := strong(classes[i]); ok {
+			continue
+		}
+		j := i
+		for j < len(classes) {
+			if _, ok := strong(classes[j]); ok {
+				break
+			}
+			j++
+		}
+		before := sos
+		if i > 0 {
+			before, _ = strong(classes[i-1])
+		}
+		after := sos
+		if j < len(classes) {
+			after, _ = strong(classes[j])
+		}
+		c := bidi.L
+		if before == after {
+			c = before
+		} else if paragraphLevel == 1 {
+			c = bidi.R
+		}
+		for k := i; k < j; k++ {
+			classes[k] = c
+		}
+		i = j - 1
+	}
+
+	// Resolve implicit levels (I1-I2).
+	for i, c := range classes {
+		l := paragraphLevel
+		if l%2 == 0 {
+			switch c {
+			case bidi.R:
+				l++
+			case bidi.EN, bidi.AN:
+				l += 2
+			}
+		} else {
+			switch c {
+			case bidi.L, bidi.EN, bidi.AN:
+				l++
+			}
+		}
+		levels[i] = l
+	}
+
+	// Reset the levels of trailing whitespaces (L1).
+	for i := len(rs) - 1; i >= 0; i-- {
+		c := bidiClass(rs[i])
+		if c != bidi.WS && c != bidi.S && c != bidi.B && c != bidi.BN {
+			break
+		}
+		levels[i] = paragraphLevel
+	}
+
+	return levels
+}
+
+// mirroredRunes is a table of mirrored runes used in right-to-left runs.
+var mirroredRunes = map[rune]rune{
+	'(': ')',
+	')': '(',
+	'<': '>',
+	'>': '<',
+	'[': ']',
+	']': '[',
+	'{': '}',
+	'}': '{',
+	'«': '»',
+	'»': '«',
+}
+
+// mirrorRunes replaces the runes in right-to-left runs with their mirrored runes in place (L4).
+func mirrorRunes(rs []rune, levels []int) {
+	for i, r := range rs {
+		if levels[i]%2 == 1 {
+			if m, ok := mirroredRunes[r]; ok {
+				rs[i] = m
+			}
+		}
+	}
+}
+
+// reorderRunes reorders the runes in place from the logical order to the visual order by the given levels (L2-L4).
+func reorderRunes(rs []rune, levels []int) {
+	mirrorRunes(rs, levels)
+	reorderByLevels(len(rs), levels, func(i, j int) {
+		rs[i], rs[j] = rs[j], rs[i]
+	})
+}
+
+// reorderByLevels reverses the items by the levels so that the items are in the visual order.
+// swap is called to swap two items. levels is updated along with the items.
+func reorderByLevels(n int, levels []int, swap func(i, j int)) {
+	highest := 0
+	lowestOdd := -1
+	for _, l := range levels {
+		if highest < l {
+			highest = l
+		}
+		if l%2 == 1 && (lowestOdd == -1 || lowestOdd > l) {
+			lowestOdd = l
+		}
+	}
+	if lowestOdd == -1 {
+		return
+	}
+
+	for level := highest; level >= lowestOdd; level-- {
+		for i := 0; i < n; i++ {
+			if levels[i] < level {
+				continue
+			}
+			j := i
+			for j < n && levels[j] >= level {
+				j++
+			}
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				swap(a, b)
+				levels[a], levels[b] = levels[b], levels[a]
+			}
+			i = j
+		}
+	}
+}
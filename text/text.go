@@ -20,6 +20,7 @@ package text
 import (
 	"image"
 	"image/color"
+	"image/draw"
 	"math"
 	"sync"
 
@@ -67,6 +68,26 @@ func drawGlyph(dst *ebiten.Image, face font.Face, r rune, img *ebiten.Image, dx,
 	dst.DrawImage(img, op2)
 }
 
+// drawSidewaysGlyph draws the glyph rotated by 90 degrees clockwise around the dot.
+func drawSidewaysGlyph(dst *ebiten.Image, face font.Face, r rune, img *ebiten.Image, dx, dy fixed.Int26_6, op *ebiten.DrawImageOptions) {
+	if img == nil {
+		return
+	}
+
+	b := getGlyphBounds(face, r)
+	op2 := &ebiten.DrawImageOptions{}
+	if op != nil {
+		*op2 = *op
+		op2.GeoM.Reset()
+	}
+	op2.GeoM.Rotate(math.Pi / 2)
+	op2.GeoM.Translate(math.Floor(fixed26_6ToFloat64(dx-b.Min.Y)), math.Floor(fixed26_6ToFloat64(dy+b.Min.X)))
+	if op != nil {
+		op2.GeoM.Concat(op.GeoM)
+	}
+	dst.DrawImage(img, op2)
+}
+
 var (
 	glyphBoundsCache = map[font.Face]map[rune]fixed.Rectangle26_6{}
 )
@@ -86,6 +107,10 @@ func getGlyphBounds(face font.Face, r rune) fixed.Rectangle26_6 {
 type glyphImageCacheEntry struct {
 	image *ebiten.Image
 	atime int64
+	bytes int
+
+	// pins is the number of PinGlyphs calls without corresponding UnpinGlyphs calls.
+	pins int
 }
 
 var (
@@ -120,22 +145,24 @@ func getGlyphImage(face font.Face, r rune) *ebiten.Image {
 	}
 	rgba := image.NewRGBA(image.Rect(0, 0, w, h))
 
-	d := font.Drawer{
-		Dst:  rgba,
-		Src:  image.White,
-		Face: face,
+	dot := fixed.Point26_6{X: fixed.I((-b.Min.X).Ceil()), Y: fixed.I((-b.Min.Y).Ceil())}
+	if dr, mask, maskp, _, ok := face.Glyph(dot, r); ok {
+		if isColorGlyphMask(mask) {
+			draw.Draw(rgba, dr, mask, maskp, draw.Over)
+		} else {
+			draw.DrawMask(rgba, dr, image.White, image.Point{}, mask, maskp, draw.Over)
+		}
 	}
-	x, y := -b.Min.X, -b.Min.Y
-	x, y = fixed.I(x.Ceil()), fixed.I(y.Ceil())
-	d.Dot = fixed.Point26_6{X: x, Y: y}
-	d.DrawString(string(r))
 
 	img := ebiten.NewImageFromImage(rgba)
 	if _, ok := glyphImageCache[face][r]; !ok {
-		glyphImageCache[face][r] = &glyphImageCacheEntry{
+		e := &glyphImageCacheEntry{
 			image: img,
 			atime: now(),
+			bytes: 4 * w * h,
 		}
+		glyphImageCache[face][r] = e
+		glyphImageCacheBytes += e.bytes
 	}
 
 	return img
@@ -245,21 +272,7 @@ func DrawWithOptions(dst *ebiten.Image, text string, face font.Face, options *eb
 		prevR = r
 	}
 
-	// cacheSoftLimit indicates the soft limit of the number of glyphs in the cache.
-	// If the number of glyphs exceeds this soft limits, old glyphs are removed.
-	// Even after clearning up the cache, the number of glyphs might still exceeds the soft limit, but
-	// this is fine.
-	const cacheSoftLimit = 512
-
-	// Clean up the cache.
-	if len(glyphImageCache[face]) > cacheSoftLimit {
-		for r, e := range glyphImageCache[face] {
-			// 60 is an arbitrary number.
-			if e.atime < now()-60 {
-				delete(glyphImageCache[face], r)
-			}
-		}
-	}
+	cleanUpGlyphImageCache(face)
 }
 
 // BoundString returns the measured size of a given string using a given font.
@@ -339,6 +352,8 @@ func BoundString(face font.Face, text string) image.Rectangle {
 // merged into one draw call regardless of the size of the text.
 //
 // If a rune's glyph is already cached, CacheGlyphs does nothing for the rune.
+//
+// Glyphs cached by CacheGlyphs might be evicted before they are used. To keep glyphs in the cache, use PinGlyphs.
 func CacheGlyphs(face font.Face, text string) {
 	textM.Lock()
 	defer textM.Unlock()
@@ -346,6 +361,8 @@ func CacheGlyphs(face font.Face, text string) {
 	for _, r := range text {
 		getGlyphImage(face, r)
 	}
+
+	cleanUpGlyphImageCache(face)
 }
 
 // FaceWithLineHeight returns a font.Face with the given lineHeight in pixels.
@@ -394,7 +411,11 @@ type Glyph struct {
 	Rune rune
 
 	// Image is an image for this glyph.
-	// Image is a grayscale image i.e. RGBA values are the same.
+	// Image is a grayscale image i.e. RGBA values are the same, unless the glyph is a color glyph
+	// e.g. of a face created by NewColorFace.
+	//
+	// Image is owned by the glyph cache. Image is disposed when the glyph is evicted from the cache,
+	// so don't keep Image beyond the current tick unless the glyph is pinned by PinGlyphs.
 	Image *ebiten.Image
 
 	// X is the X position to render this glyph.
@@ -406,6 +427,12 @@ type Glyph struct {
 	// The position is determined in a sequence of characters given at AppendGlyphs.
 	// The position's origin is the first character's dot ('.') position.
 	Y float64
+
+	// Sideways reports whether the glyph is rotated by 90 degrees clockwise in a vertical layout by
+	// AppendLayoutGlyphs.
+	// If Sideways is true, render Image rotated by math.Pi/2 around its upper-left corner, and then translated by
+	// (X, Y).
+	Sideways bool
 }
 
 // AppendGlyphs appends the glyph information to glyphs.
@@ -444,5 +471,7 @@ func AppendGlyphs(glyphs []Glyph, face font.Face, text string) []Glyph {
 		prevR = r
 	}
 
+	cleanUpGlyphImageCache(face)
+
 	return glyphs
 }
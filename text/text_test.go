@@ -62,8 +62,7 @@ const testFaceSize = 6
 type testFace struct{}
 
 func (f *testFace) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
-	dr = image.Rect(0, 0, testFaceSize, testFaceSize)
-	a := image.NewAlpha(dr)
+	a := image.NewAlpha(image.Rect(0, 0, testFaceSize, testFaceSize))
 	switch r {
 	case 'a':
 		for j := 0; j < testFaceSize; j++ {
@@ -78,6 +77,7 @@ func (f *testFace) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask
 			}
 		}
 	}
+	dr = a.Bounds().Add(image.Pt(dot.X.Floor(), dot.Y.Floor()))
 	mask = a
 	advance = fixed.I(testFaceSize)
 	ok = true
@@ -146,3 +146,451 @@ func TestTextOverlap(t *testing.T) {
 		}
 	}
 }
+
+func TestPinGlyphs(t *testing.T) {
+	f := &testFace{}
+
+	var before text.GlyphCacheStats
+	text.ReadGlyphCacheStats(&before)
+
+	text.PinGlyphs(f, "ab")
+
+	var stats text.GlyphCacheStats
+	text.ReadGlyphCacheStats(&stats)
+	if got, want := stats.NumPinnedGlyphs-before.NumPinnedGlyphs, 2; got != want {
+		t.Errorf("NumPinnedGlyphs: got: %d, want: %d", got, want)
+	}
+	if got, want := stats.Bytes, 2*4*testFaceSize*testFaceSize; got < want {
+		t.Errorf("Bytes: got: %d, want: >= %d", got, want)
+	}
+
+	// Pins are counted.
+	text.PinGlyphs(f, "a")
+	text.UnpinGlyphs(f, "ab")
+	text.ReadGlyphCacheStats(&stats)
+	if got, want := stats.NumPinnedGlyphs-before.NumPinnedGlyphs, 1; got != want {
+		t.Errorf("NumPinnedGlyphs: got: %d, want: %d", got, want)
+	}
+
+	text.UnpinGlyphs(f, "a")
+	text.ReadGlyphCacheStats(&stats)
+	if got, want := stats.NumPinnedGlyphs, before.NumPinnedGlyphs; got != want {
+		t.Errorf("NumPinnedGlyphs: got: %d, want: %d", got, want)
+	}
+
+	// Unpinning a glyph that is not pinned does nothing.
+	text.UnpinGlyphs(f, "a")
+	text.ReadGlyphCacheStats(&stats)
+	if got, want := stats.NumPinnedGlyphs, before.NumPinnedGlyphs; got != want {
+		t.Errorf("NumPinnedGlyphs: got: %d, want: %d", got, want)
+	}
+}
+
+func TestGlyphCacheMemoryLimit(t *testing.T) {
+	const glyphBytes = 4 * testFaceSize * testFaceSize
+
+	f := &testFace{}
+	dst := ebiten.NewImage(testFaceSize*4, testFaceSize)
+
+	// Evict all the glyphs cached by the other tests first.
+	text.AdvanceTick()
+	text.SetGlyphCacheMemoryLimit(1)
+	text.CacheGlyphs(f, "")
+	defer text.SetGlyphCacheMemoryLimit(0)
+
+	text.SetGlyphCacheMemoryLimit(3 * glyphBytes)
+
+	text.PinGlyphs(f, "a")
+	defer text.UnpinGlyphs(f, "a")
+	text.AdvanceTick()
+
+	text.Draw(dst, "b", f, 0, testFaceSize, color.White)
+	imgB := text.CachedGlyphImage(f, 'b')
+	if imgB == nil {
+		t.Fatalf("the glyph 'b' must be cached")
+	}
+	if got, want := imgB.At(0, 0), (color.RGBA{0xff, 0xff, 0xff, 0xff}); got != want {
+		t.Errorf("At(0, 0): got: %v, want: %v", got, want)
+	}
+	text.AdvanceTick()
+
+	// The glyphs used in the current tick are never evicted even if the cache exceeds the limit.
+	glyphs := text.AppendGlyphs(nil, f, "cde")
+	if got, want := len(glyphs), 3; got != want {
+		t.Fatalf("len(glyphs): got: %d, want: %d", got, want)
+	}
+	for _, r := range "cde" {
+		if text.CachedGlyphImage(f, r) == nil {
+			t.Errorf("the glyph %q must be cached", r)
+		}
+	}
+
+	// The least recently used glyph 'b' is evicted and disposed. The pinned glyph 'a' is kept.
+	if text.CachedGlyphImage(f, 'b') != nil {
+		t.Errorf("the glyph 'b' must be evicted")
+	}
+	if got, want := imgB.At(0, 0), (color.RGBA{}); got != want {
+		t.Errorf("At(0, 0) after the eviction: got: %v, want: %v", got, want)
+	}
+	if text.CachedGlyphImage(f, 'a') == nil {
+		t.Errorf("the pinned glyph 'a' must not be evicted")
+	}
+
+	var stats text.GlyphCacheStats
+	text.ReadGlyphCacheStats(&stats)
+	if got, want := stats.Bytes, 4*glyphBytes; got != want {
+		t.Errorf("Bytes: got: %d, want: %d", got, want)
+	}
+
+	// In the next tick, the glyphs exceeding the limit are evicted.
+	text.AdvanceTick()
+	text.CacheGlyphs(f, "")
+	text.ReadGlyphCacheStats(&stats)
+	if got, want := stats.Bytes, 3*glyphBytes; got > want {
+		t.Errorf("Bytes: got: %d, want: <= %d", got, want)
+	}
+	if text.CachedGlyphImage(f, 'a') == nil {
+		t.Errorf("the pinned glyph 'a' must not be evicted")
+	}
+}
+
+func TestMeasureLayout(t *testing.T) {
+	f := &testFace{}
+
+	cases := []struct {
+		Text    string
+		Options *text.LayoutOptions
+		Width   float64
+		Height  float64
+	}{
+		{
+			Text:    "aa aa",
+			Options: nil,
+			Width:   5 * testFaceSize,
+			Height:  testFaceSize,
+		},
+		{
+			Text:    "aa aa",
+			Options: &text.LayoutOptions{Width: 4 * testFaceSize},
+			Width:   2 * testFaceSize,
+			Height:  2 * testFaceSize,
+		},
+		{
+			Text:    "aaaaa",
+			Options: &text.LayoutOptions{Width: 2 * testFaceSize},
+			Width:   2 * testFaceSize,
+			Height:  3 * testFaceSize,
+		},
+		{
+			Text:    "a\ta",
+			Options: &text.LayoutOptions{TabWidth: 4 * testFaceSize},
+			Width:   5 * testFaceSize,
+			Height:  testFaceSize,
+		},
+		{
+			Text:    "a\na",
+			Options: &text.LayoutOptions{LineHeight: 10},
+			Width:   testFaceSize,
+			Height:  20,
+		},
+		{
+			Text:    "漢字\n漢",
+			Options: &text.LayoutOptions{Vertical: true, LineHeight: 10},
+			Width:   20,
+			Height:  2 * testFaceSize,
+		},
+	}
+	for _, c := range cases {
+		w, h := text.MeasureLayout(f, c.Text, c.Options)
+		if w != c.Width || h != c.Height {
+			t.Errorf("MeasureLayout(%q, %v): got: (%f, %f), want: (%f, %f)", c.Text, c.Options, w, h, c.Width, c.Height)
+		}
+	}
+}
+
+func TestAppendLayoutGlyphs(t *testing.T) {
+	const s = testFaceSize
+
+	f := &testFace{}
+
+	type glyph struct {
+		Rune rune
+		X    float64
+		Y    float64
+	}
+
+	// testFace's glyphs are placed below the baseline, and the first baseline is at the ascent (s) from the top.
+	cases := []struct {
+		Name    string
+		Text    string
+		Options *text.LayoutOptions
+		Glyphs  []glyph
+	}{
+		{
+			Name:    "default",
+			Text:    "ac",
+			Options: nil,
+			Glyphs:  []glyph{{'a', 0, s}, {'c', s, s}},
+		},
+		{
+			Name:    "horizontal center",
+			Text:    "ac",
+			Options: &text.LayoutOptions{Width: 4 * s, HorizontalAlign: text.AlignCenter},
+			Glyphs:  []glyph{{'a', s, s}, {'c', 2 * s, s}},
+		},
+		{
+			Name:    "horizontal end",
+			Text:    "ac",
+			Options: &text.LayoutOptions{Width: 4 * s, HorizontalAlign: text.AlignEnd},
+			Glyphs:  []glyph{{'a', 2 * s, s}, {'c', 3 * s, s}},
+		},
+		{
+			Name:    "vertical center",
+			Text:    "a",
+			Options: &text.LayoutOptions{Height: 3 * s, VerticalAlign: text.AlignCenter},
+			Glyphs:  []glyph{{'a', 0, 2 * s}},
+		},
+		{
+			Name:    "vertical end",
+			Text:    "a",
+			Options: &text.LayoutOptions{Height: 3 * s, VerticalAlign: text.AlignEnd},
+			Glyphs:  []glyph{{'a', 0, 3 * s}},
+		},
+		{
+			Name:    "overflow",
+			Text:    "a",
+			Options: &text.LayoutOptions{Width: s / 2, HorizontalAlign: text.AlignEnd},
+			Glyphs:  []glyph{{'a', 0, s}},
+		},
+		{
+			Name:    "line height",
+			Text:    "a\nc",
+			Options: &text.LayoutOptions{LineHeight: 10},
+			Glyphs:  []glyph{{'a', 0, s}, {'c', 0, s + 10}},
+		},
+		{
+			Name:    "CJK",
+			Text:    "漢字かな",
+			Options: &text.LayoutOptions{Width: 2 * s},
+			Glyphs:  []glyph{{'漢', 0, s}, {'字', s, s}, {'か', 0, 2 * s}, {'な', s, 2 * s}},
+		},
+		{
+			Name:    "non-breaking space",
+			Text:    "ac a\u00a0c",
+			Options: &text.LayoutOptions{Width: 4 * s},
+			Glyphs:  []glyph{{'a', 0, s}, {'c', s, s}, {'a', 0, 2 * s}, {'\u00a0', s, 2 * s}, {'c', 2 * s, 2 * s}},
+		},
+		{
+			Name:    "CRLF",
+			Text:    "a\r\nc",
+			Options: nil,
+			Glyphs:  []glyph{{'a', 0, s}, {'c', 0, 2 * s}},
+		},
+		{
+			Name:    "shaping",
+			Text:    "ac אב",
+			Options: &text.LayoutOptions{Shaping: true},
+			Glyphs:  []glyph{{'a', 0, s}, {'c', s, s}, {' ', 2 * s, s}, {'ב', 3 * s, s}, {'א', 4 * s, s}},
+		},
+		{
+			Name:    "ruby",
+			Text:    "漢字",
+			Options: &text.LayoutOptions{Rubies: []text.Ruby{{Start: 0, End: len("漢字"), Text: "かんじ"}}},
+			Glyphs:  []glyph{{'漢', 0, s}, {'字', s, s}, {'か', -s / 2, 0}, {'ん', s / 2, 0}, {'じ', 3 * s / 2, 0}},
+		},
+		{
+			Name: "ruby base not broken",
+			Text: "ac漢字",
+			Options: &text.LayoutOptions{
+				Width:  3 * s,
+				Rubies: []text.Ruby{{Start: len("ac"), End: len("ac漢字"), Text: "じ"}},
+			},
+			Glyphs: []glyph{{'a', 0, s}, {'c', s, s}, {'漢', 0, 2 * s}, {'字', s, 2 * s}, {'じ', s / 2, s}},
+		},
+		{
+			Name:    "shaping wrapped",
+			Text:    "אב גד",
+			Options: &text.LayoutOptions{Width: 2 * s, Shaping: true},
+			Glyphs:  []glyph{{'ב', 0, s}, {'א', s, s}, {'ד', 0, 2 * s}, {'ג', s, 2 * s}},
+		},
+	}
+	for _, c := range cases {
+		var got []glyph
+		for _, g := range text.AppendLayoutGlyphs(nil, f, c.Text, c.Options) {
+			got = append(got, glyph{g.Rune, g.X, g.Y})
+		}
+		if len(got) != len(c.Glyphs) {
+			t.Errorf("%s: got: %v, want: %v", c.Name, got, c.Glyphs)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.Glyphs[i] {
+				t.Errorf("%s: got: %v, want: %v", c.Name, got, c.Glyphs)
+				break
+			}
+		}
+	}
+}
+
+func TestShape(t *testing.T) {
+	cases := []struct {
+		Name string
+		In   string
+		Out  string
+	}{
+		{
+			Name: "latin",
+			In:   "abc def",
+			Out:  "abc def",
+		},
+		{
+			Name: "hebrew in latin",
+			In:   "abc \u05d0\u05d1\u05d2 def",
+			Out:  "abc \u05d2\u05d1\u05d0 def",
+		},
+		{
+			Name: "numbers in hebrew",
+			In:   "\u05d0\u05d1\u05d2 123",
+			Out:  "123 \u05d2\u05d1\u05d0",
+		},
+		{
+			Name: "brackets in hebrew",
+			In:   "(\u05d0\u05d1)",
+			Out:  "(\u05d1\u05d0)",
+		},
+		{
+			Name: "arabic forms",
+			In:   "\u0628\u064a\u062a",
+			Out:  "\ufe96\ufef4\ufe91",
+		},
+		{
+			Name: "lam-alef",
+			In:   "\u0633\u0644\u0627\u0645",
+			Out:  "\ufee1\ufefc\ufeb3",
+		},
+		{
+			Name: "lines",
+			In:   "\u05d0\u05d1\nab",
+			Out:  "\u05d1\u05d0\nab",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if got, want := text.Shape(c.In), c.Out; got != want {
+				t.Errorf("got: %+q, want: %+q", got, want)
+			}
+		})
+	}
+}
+
+func TestDrawSDF(t *testing.T) {
+	const (
+		scale = 4
+		size  = 2 * testFaceSize * scale
+	)
+
+	f := &testFace{}
+	dst := ebiten.NewImage(size, size)
+	op := &text.DrawSDFOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.Color = color.RGBA{0, 0xff, 0, 0xff}
+	text.DrawSDF(dst, "b", f, op)
+
+	// With testFace, 'b' is a square from (0, 0) to (testFaceSize, testFaceSize).
+	const edge = testFaceSize * scale
+	for j := 0; j < size; j++ {
+		for i := 0; i < size; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			switch {
+			case 2 <= i && i < edge-2 && 2 <= j && j < edge-2:
+				if want := (color.RGBA{0, 0xff, 0, 0xff}); got != want {
+					t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+				}
+			case i >= edge+2 || j >= edge+2:
+				if want := (color.RGBA{}); got != want {
+					t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+				}
+			}
+		}
+	}
+
+	// The edge is antialiased.
+	if got := dst.At(edge, edge/2).(color.RGBA); got.A == 0 || got.A == 0xff {
+		t.Errorf("dst.At(%d, %d): got: %v, want: a partially transparent color", edge, edge/2, got)
+	}
+}
+
+func TestAppendLayoutGlyphsVertical(t *testing.T) {
+	const s = testFaceSize
+
+	f := &testFace{}
+
+	type glyph struct {
+		Rune     rune
+		X        float64
+		Y        float64
+		Sideways bool
+	}
+
+	// In a vertical layout with testFace, an upright glyph's dot is at the ascent (s) from the top of its em box,
+	// and testFace's glyphs are placed below the baseline.
+	cases := []struct {
+		Name    string
+		Text    string
+		Options *text.LayoutOptions
+		Glyphs  []glyph
+	}{
+		{
+			Name:    "upright",
+			Text:    "漢字",
+			Options: &text.LayoutOptions{Vertical: true},
+			Glyphs:  []glyph{{'漢', 0, s, false}, {'字', 0, 2 * s, false}},
+		},
+		{
+			Name:    "columns",
+			Text:    "漢字",
+			Options: &text.LayoutOptions{Vertical: true, Height: s},
+			Glyphs:  []glyph{{'漢', s, s, false}, {'字', 0, s, false}},
+		},
+		{
+			Name:    "sideways",
+			Text:    "漢ac",
+			Options: &text.LayoutOptions{Vertical: true},
+			Glyphs:  []glyph{{'漢', 0, s, false}, {'a', 0, s, true}, {'c', 0, 2 * s, true}},
+		},
+		{
+			Name:    "vertical forms",
+			Text:    "漢、",
+			Options: &text.LayoutOptions{Vertical: true},
+			Glyphs:  []glyph{{'漢', 0, s, false}, {'\ufe11', 0, 2 * s, false}},
+		},
+		{
+			Name:    "box",
+			Text:    "漢",
+			Options: &text.LayoutOptions{Vertical: true, Width: 3 * s, Height: 3 * s, HorizontalAlign: text.AlignCenter, VerticalAlign: text.AlignEnd},
+			Glyphs:  []glyph{{'漢', s, 3 * s, false}},
+		},
+		{
+			Name:    "ruby",
+			Text:    "漢",
+			Options: &text.LayoutOptions{Vertical: true, Rubies: []text.Ruby{{Start: 0, End: len("漢"), Text: "か"}}},
+			Glyphs:  []glyph{{'漢', 0, s, false}, {'か', s, s, false}},
+		},
+	}
+	for _, c := range cases {
+		var got []glyph
+		for _, g := range text.AppendLayoutGlyphs(nil, f, c.Text, c.Options) {
+			got = append(got, glyph{g.Rune, g.X, g.Y, g.Sideways})
+		}
+		if len(got) != len(c.Glyphs) {
+			t.Errorf("%s: got: %v, want: %v", c.Name, got, c.Glyphs)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.Glyphs[i] {
+				t.Errorf("%s: got: %v, want: %v", c.Name, got, c.Glyphs)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// maxColorStops is the maximum number of color stops of a gradient.
+const maxColorStops = 8
+
+// gradientShaderSrc is a shader to render a linear or radial gradient.
+//
+// texCoord is a position in the path's coordinates, as a shader without source images receives the vertices'
+// source positions as they are.
+var gradientShaderSrc = []byte(fmt.Sprintf(`package main
+
+var Start vec2
+var End vec2
+var Radius float
+var StopNum float
+var Offsets [%[1]d]float
+var Colors [%[1]d]vec4
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	t := 0.0
+	if Radius > 0 {
+		t = length(texCoord-Start) / Radius
+	} else {
+		d := End - Start
+		t = dot(texCoord-Start, d) / dot(d, d)
+	}
+
+	clr := Colors[0]
+	for i := 1; i < %[1]d; i++ {
+		if float(i) < StopNum && t > Offsets[i-1] {
+			r := clamp((t-Offsets[i-1])/max(Offsets[i]-Offsets[i-1], 1.0/65536.0), 0, 1)
+			clr = mix(Colors[i-1], Colors[i], r)
+		}
+	}
+	return clr * color
+}
+`, maxColorStops))
+
+var gradientShader *ebiten.Shader
+
+// Paint represents a way to fill triangles with colors.
+//
+// Paint is one of *LinearGradient, *RadialGradient and *Pattern.
+type Paint interface {
+	draw(dst *ebiten.Image, vertices []ebiten.Vertex, indices []uint16, options *DrawPaintOptions)
+}
+
+// ColorStop represents a color at a position in a gradient.
+type ColorStop struct {
+	// Offset is the position in the gradient, from 0 at the start to 1 at the end.
+	Offset float32
+
+	// Color is the color at Offset.
+	Color color.Color
+}
+
+// LinearGradient represents a gradient along the line from (X0, Y0) to (X1, Y1).
+//
+// The colors are constant in the direction perpendicular to the line.
+// Before the start and after the end, the colors of the first and the last stops are used respectively.
+type LinearGradient struct {
+	X0, Y0 float32
+	X1, Y1 float32
+
+	// Stops is the color stops in ascending order of their offsets.
+	// The number of Stops must be 8 or less.
+	Stops []ColorStop
+}
+
+func (g *LinearGradient) draw(dst *ebiten.Image, vertices []ebiten.Vertex, indices []uint16, options *DrawPaintOptions) {
+	drawGradient(dst, vertices, indices, options, g.Stops, map[string]interface{}{
+		"Start":  []float32{g.X0, g.Y0},
+		"End":    []float32{g.X1, g.Y1},
+		"Radius": float32(0),
+	})
+}
+
+// RadialGradient represents a gradient from the center (X, Y) to the circle with the radius Radius.
+//
+// Outside the circle, the color of the last stop is used.
+type RadialGradient struct {
+	X, Y   float32
+	Radius float32
+
+	// Stops is the color stops in ascending order of their offsets.
+	// The number of Stops must be 8 or less.
+	Stops []ColorStop
+}
+
+func (g *RadialGradient) draw(dst *ebiten.Image, vertices []ebiten.Vertex, indices []uint16, options *DrawPaintOptions) {
+	if g.Radius <= 0 {
+		return
+	}
+	drawGradient(dst, vertices, indices, options, g.Stops, map[string]interface{}{
+		"Start":  []float32{g.X, g.Y},
+		"End":    []float32{g.X, g.Y},
+		"Radius": g.Radius,
+	})
+}
+
+func drawGradient(dst *ebiten.Image, vertices []ebiten.Vertex, indices []uint16, options *DrawPaintOptions, stops []ColorStop, uniforms map[string]interface{}) {
+	if len(stops) > maxColorStops {
+		panic(fmt.Sprintf("vector: the number of color stops must be <= %d but %d", maxColorStops, len(stops)))
+	}
+	if len(stops) == 0 {
+		return
+	}
+
+	if gradientShader == nil {
+		s, err := ebiten.NewShader(gradientShaderSrc)
+		if err != nil {
+			panic(fmt.Sprintf("vector: NewShader for gradients failed: %v", err))
+		}
+		gradientShader = s
+	}
+
+	offsets := make([]float32, maxColorStops)
+	colors := make([]float32, 4*maxColorStops)
+	for i, s := range stops {
+		offsets[i] = s.Offset
+		r, g, b, a := s.Color.RGBA()
+		colors[4*i] = float32(r) / 0xffff
+		colors[4*i+1] = float32(g) / 0xffff
+		colors[4*i+2] = float32(b) / 0xffff
+		colors[4*i+3] = float32(a) / 0xffff
+	}
+	uniforms["StopNum"] = float32(len(stops))
+	uniforms["Offsets"] = offsets
+	uniforms["Colors"] = colors
+
+	vs := make([]ebiten.Vertex, len(vertices))
+	for i, v := range vertices {
+		v.SrcX = v.DstX
+		v.SrcY = v.DstY
+		vs[i] = v
+	}
+
+	op := &ebiten.DrawTrianglesShaderOptions{}
+	op.CompositeMode = options.CompositeMode
+	op.FillRule = options.FillRule
+	op.Uniforms = uniforms
+	dst.DrawTrianglesShader(vs, indices, gradientShader, op)
+}
+
+// Pattern represents an image repeated infinitely.
+type Pattern struct {
+	// Image is the image to repeat.
+	Image *ebiten.Image
+
+	// GeoM is a geometry matrix from the image to the path's coordinates.
+	// GeoM must be invertible.
+	//
+	// The default (zero) value is identity, which places the image's upper-left corner at the origin.
+	GeoM ebiten.GeoM
+
+	// Filter is a type of texture filter.
+	//
+	// The default (zero) value is FilterNearest.
+	Filter ebiten.Filter
+}
+
+func (p *Pattern) draw(dst *ebiten.Image, vertices []ebiten.Vertex, indices []uint16, options *DrawPaintOptions) {
+	g := p.GeoM
+	g.Invert()
+
+	b := p.Image.Bounds()
+	vs := make([]ebiten.Vertex, len(vertices))
+	for i, v := range vertices {
+		sx, sy := g.Apply(float64(v.DstX), float64(v.DstY))
+		v.SrcX = float32(sx) + float32(b.Min.X)
+		v.SrcY = float32(sy) + float32(b.Min.Y)
+		vs[i] = v
+	}
+
+	op := &ebiten.DrawTrianglesOptions{}
+	op.CompositeMode = options.CompositeMode
+	op.Filter = p.Filter
+	op.Address = ebiten.AddressRepeat
+	op.FillRule = options.FillRule
+	dst.DrawTriangles(vs, indices, p.Image, op)
+}
+
+// DrawPaintOptions represents options for DrawPaint.
+type DrawPaintOptions struct {
+	// CompositeMode is a composite mode to draw.
+	//
+	// The default (zero) value is regular alpha blending.
+	CompositeMode ebiten.CompositeMode
+
+	// FillRule indicates the rule how an overlapped region is rendered.
+	// Use EvenOdd or NonZero for the vertices and indices by AppendVerticesAndIndicesForFilling.
+	//
+	// The default (zero) value is FillAll.
+	FillRule ebiten.FillRule
+}
+
+// DrawPaint renders triangles on dst with paint.
+//
+// vertices and indices are typically the results of AppendVerticesAndIndicesForFilling or
+// AppendVerticesAndIndicesForStroke.
+// paint is evaluated at the vertices' DstX and DstY, so the paint's coordinates are the path's coordinates.
+// The vertices' colors are multiplied with the paint's colors, and their SrcX and SrcY are ignored.
+func DrawPaint(dst *ebiten.Image, vertices []ebiten.Vertex, indices []uint16, paint Paint, options *DrawPaintOptions) {
+	if options == nil {
+		options = &DrawPaintOptions{}
+	}
+	paint.draw(dst, vertices, indices, options)
+}
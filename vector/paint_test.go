@@ -0,0 +1,145 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	t "github.com/hajimehoshi/ebiten/v2/internal/testing"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+func TestMain(m *testing.M) {
+	t.MainWithRunLoop(m)
+}
+
+func fillRect(dst *ebiten.Image, x0, y0, x1, y1 float32, paint vector.Paint) {
+	var path vector.Path
+	path.MoveTo(x0, y0)
+	path.LineTo(x1, y0)
+	path.LineTo(x1, y1)
+	path.LineTo(x0, y1)
+	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	vector.DrawPaint(dst, vs, is, paint, &vector.DrawPaintOptions{
+		FillRule: ebiten.EvenOdd,
+	})
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func colorsNear(c0, c1 color.RGBA, delta int) bool {
+	return abs(int(c0.R)-int(c1.R)) <= delta && abs(int(c0.G)-int(c1.G)) <= delta &&
+		abs(int(c0.B)-int(c1.B)) <= delta && abs(int(c0.A)-int(c1.A)) <= delta
+}
+
+func TestDrawPaintLinearGradient(t *testing.T) {
+	const w, h = 16, 4
+	dst := ebiten.NewImage(w, h)
+	fillRect(dst, 0, 0, w, h, &vector.LinearGradient{
+		X0: 4,
+		X1: 12,
+		Stops: []vector.ColorStop{
+			{Offset: 0, Color: color.RGBA{0xff, 0, 0, 0xff}},
+			{Offset: 1, Color: color.RGBA{0, 0, 0xff, 0xff}},
+		},
+	})
+
+	for i := 0; i < w; i++ {
+		// The pixel center is at i + 0.5.
+		r := (float64(i) + 0.5 - 4) / 8
+		if r < 0 {
+			r = 0
+		}
+		if r > 1 {
+			r = 1
+		}
+		want := color.RGBA{uint8(0xff * (1 - r)), 0, uint8(0xff * r), 0xff}
+		for j := 0; j < h; j++ {
+			got := dst.At(i, j).(color.RGBA)
+			if !colorsNear(got, want, 2) {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestDrawPaintRadialGradient(t *testing.T) {
+	const size = 16
+	dst := ebiten.NewImage(size, size)
+	fillRect(dst, 0, 0, size, size, &vector.RadialGradient{
+		X:      8,
+		Y:      8,
+		Radius: 4,
+		Stops: []vector.ColorStop{
+			{Offset: 0, Color: color.White},
+			{Offset: 0.5, Color: color.RGBA{0xff, 0, 0, 0xff}},
+			{Offset: 1, Color: color.Transparent},
+		},
+	})
+
+	// The centers of the pixels (8, 8) and (10, 8) are about 0.71 and 2.55 pixels away from the gradient's center.
+	cases := []struct {
+		X, Y int
+		Want color.RGBA
+	}{
+		{8, 8, color.RGBA{0xff, 0xa5, 0xa5, 0xff}},
+		{10, 8, color.RGBA{0xb9, 0, 0, 0xb9}},
+		{8, 1, color.RGBA{}},
+		{0, 0, color.RGBA{}},
+	}
+	for _, c := range cases {
+		got := dst.At(c.X, c.Y).(color.RGBA)
+		if !colorsNear(got, c.Want, 4) {
+			t.Errorf("dst.At(%d, %d): got: %v, want: %v", c.X, c.Y, got, c.Want)
+		}
+	}
+}
+
+func TestDrawPaintPattern(t *testing.T) {
+	src := ebiten.NewImage(2, 2)
+	src.Set(0, 0, color.RGBA{0xff, 0, 0, 0xff})
+	src.Set(1, 1, color.RGBA{0, 0xff, 0, 0xff})
+
+	const size = 8
+	dst := ebiten.NewImage(size, size)
+	p := &vector.Pattern{
+		Image: src,
+	}
+	p.GeoM.Translate(1, 0)
+	fillRect(dst, 0, 0, size, size, p)
+
+	for j := 0; j < size; j++ {
+		for i := 0; i < size; i++ {
+			got := dst.At(i, j)
+			var want color.RGBA
+			switch {
+			case (i-1+2)%2 == 0 && j%2 == 0:
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			case (i-1+2)%2 == 1 && j%2 == 1:
+				want = color.RGBA{0, 0xff, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
@@ -36,34 +36,56 @@ type point struct {
 	y float32
 }
 
+type subpath struct {
+	points []point
+	closed bool
+}
+
 // Path represents a collection of path segments.
 type Path struct {
-	segs [][]point
-	cur  point
+	subpaths []subpath
+	cur      point
 }
 
 // MoveTo skips the current position of the path to the given position (x, y) without adding any strokes.
 func (p *Path) MoveTo(x, y float32) {
 	p.cur = point{x: x, y: y}
-	p.segs = append(p.segs, []point{p.cur})
+	p.subpaths = append(p.subpaths, subpath{points: []point{p.cur}})
 }
 
 // LineTo adds a line segument to the path, which starts from the current position and ends to the given position (x, y).
 //
 // LineTo updates the current position to (x, y).
 func (p *Path) LineTo(x, y float32) {
-	if len(p.segs) == 0 {
-		p.segs = append(p.segs, []point{{x: x, y: y}})
+	if len(p.subpaths) == 0 {
+		p.subpaths = append(p.subpaths, subpath{points: []point{{x: x, y: y}}})
 		p.cur = point{x: x, y: y}
 		return
 	}
-	seg := p.segs[len(p.segs)-1]
-	if seg[len(seg)-1].x != x || seg[len(seg)-1].y != y {
-		p.segs[len(p.segs)-1] = append(seg, point{x: x, y: y})
+	if p.subpaths[len(p.subpaths)-1].closed {
+		p.subpaths = append(p.subpaths, subpath{points: []point{p.cur}})
+	}
+	sp := &p.subpaths[len(p.subpaths)-1]
+	if last := sp.points[len(sp.points)-1]; last.x != x || last.y != y {
+		sp.points = append(sp.points, point{x: x, y: y})
 	}
 	p.cur = point{x: x, y: y}
 }
 
+// Close adds a line segment from the current position to the start of the current subpath, and closes the subpath.
+// A closed subpath is stroked with a join at its start instead of caps.
+//
+// Close updates the current position to the start of the subpath.
+// A following segment starts a new subpath from there.
+func (p *Path) Close() {
+	if len(p.subpaths) == 0 {
+		return
+	}
+	sp := &p.subpaths[len(p.subpaths)-1]
+	sp.closed = true
+	p.cur = sp.points[0]
+}
+
 // QuadTo adds a quadratic Bézier curve to the path.
 // (x1, y1) is the control point, and (x2, y2) is the destination.
 //
@@ -282,13 +304,17 @@ func (p *Path) Arc(x, y, radius, startAngle, endAngle float32, dir Direction) {
 //
 // The returned vertice's SrcX and SrcY are 0, and ColorR, ColorG, ColorB, and ColorA are 1.
 //
-// The returned values are intended to be passed to DrawTriangles or DrawTrianglesShader with EvenOdd fill mode
+// The returned values are intended to be passed to DrawTriangles or DrawTrianglesShader with EvenOdd or NonZero fill mode
 // in order to render a complex polygon like a concave polygon, a polygon with holes, or a self-intersecting polygon.
+//
+// The path is not triangulated on CPU. Each subpath is converted into a triangle fan, and the fill mode resolves
+// the overlaps with a stencil buffer on GPU, so the cost on CPU is proportional to the number of the points.
 func (p *Path) AppendVerticesAndIndicesForFilling(vertices []ebiten.Vertex, indices []uint16) ([]ebiten.Vertex, []uint16) {
 	// TODO: Add tests.
 
 	var base uint16
-	for _, seg := range p.segs {
+	for _, sp := range p.subpaths {
+		seg := sp.points
 		if len(seg) < 3 {
 			continue
 		}
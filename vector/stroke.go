@@ -0,0 +1,383 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// LineJoin represents the way to join two line segments of a stroke.
+type LineJoin int
+
+const (
+	LineJoinMiter LineJoin = iota
+	LineJoinBevel
+	LineJoinRound
+)
+
+// LineCap represents the way to render the ends of a stroke.
+type LineCap int
+
+const (
+	LineCapButt LineCap = iota
+	LineCapRound
+	LineCapSquare
+)
+
+// StrokeOptions represents options to render a stroke of a path.
+type StrokeOptions struct {
+	// Width is the width of the stroke.
+	//
+	// The default (zero) value is 1.
+	Width float32
+
+	// LineJoin is the way to join two line segments.
+	//
+	// The default (zero) value is LineJoinMiter.
+	LineJoin LineJoin
+
+	// MiterLimit is the limit of the ratio of a miter join's length to the stroke width.
+	// A miter join exceeding the limit is rendered as a bevel join instead.
+	//
+	// The default (zero) value is 4.
+	MiterLimit float32
+
+	// LineCap is the way to render the ends of open subpaths and dashes.
+	//
+	// The default (zero) value is LineCapButt.
+	LineCap LineCap
+
+	// DashPattern is the lengths of dashes and gaps in turn.
+	// If DashPattern has an odd number of lengths, the lengths are repeated to make it even.
+	//
+	// The default (nil) value means a solid line.
+	DashPattern []float32
+
+	// DashOffset is the distance into the dash pattern at the start of each subpath.
+	//
+	// The default (zero) value is 0.
+	DashOffset float32
+}
+
+// AppendVerticesAndIndicesForStroke appends vertices and indices to render a stroke of this path and returns them.
+// AppendVerticesAndIndicesForStroke works in a similar way to the built-in append function.
+// If the arguments are nils, AppendVerticesAndIndicesForStroke returns new slices.
+//
+// The returned vertice's SrcX and SrcY are 0, and ColorR, ColorG, ColorB, and ColorA are 1.
+//
+// The returned values are intended to be passed to DrawTriangles or DrawTrianglesShader without EvenOdd fill mode.
+// The triangles might overlap at joins, so render them with an opaque color, or render them to an offscreen image
+// and then draw the image with a translucent color.
+func (p *Path) AppendVerticesAndIndicesForStroke(vertices []ebiten.Vertex, indices []uint16, op *StrokeOptions) ([]ebiten.Vertex, []uint16) {
+	if op == nil {
+		op = &StrokeOptions{}
+	}
+
+	s := stroker{
+		vertices:   vertices,
+		indices:    indices,
+		halfWidth:  op.Width / 2,
+		lineJoin:   op.LineJoin,
+		miterLimit: op.MiterLimit,
+		lineCap:    op.LineCap,
+	}
+	if s.halfWidth == 0 {
+		s.halfWidth = 0.5
+	}
+	if s.miterLimit == 0 {
+		s.miterLimit = 4
+	}
+
+	pattern := dashPattern(op.DashPattern)
+	for _, sp := range p.subpaths {
+		if pattern == nil {
+			s.strokePolyline(sp.points, sp.closed)
+			continue
+		}
+		for _, pts := range dashPolyline(sp.points, sp.closed, pattern, op.DashOffset) {
+			s.strokePolyline(pts, false)
+		}
+	}
+	return s.vertices, s.indices
+}
+
+// dashPattern returns a dash pattern with an even number of lengths, or nil if the pattern means a solid line.
+func dashPattern(pattern []float32) []float32 {
+	var total float32
+	for _, l := range pattern {
+		if l < 0 {
+			return nil
+		}
+		total += l
+	}
+	if total <= 0 {
+		return nil
+	}
+	if len(pattern)%2 == 1 {
+		return append(append([]float32{}, pattern...), pattern...)
+	}
+	return pattern
+}
+
+// dashPolyline splits a polyline into dashes by the given pattern.
+func dashPolyline(pts []point, closed bool, pattern []float32, offset float32) [][]point {
+	if closed {
+		pts = append(pts[:len(pts):len(pts)], pts[0])
+	}
+
+	var total float32
+	for _, l := range pattern {
+		total += l
+	}
+	offset = float32(math.Mod(float64(offset), float64(total)))
+	if offset < 0 {
+		offset += total
+	}
+
+	idx := 0
+	for offset > 0 && offset >= pattern[idx] {
+		offset -= pattern[idx]
+		idx = (idx + 1) % len(pattern)
+	}
+	remain := pattern[idx] - offset
+	on := idx%2 == 0
+
+	var dashes [][]point
+	var dash []point
+	if on {
+		dash = []point{pts[0]}
+	}
+	for i := 0; i < len(pts)-1; i++ {
+		p0, p1 := pts[i], pts[i+1]
+		l := float32(math.Hypot(float64(p1.x-p0.x), float64(p1.y-p0.y)))
+		var t float32
+		for l-t > remain {
+			t += remain
+			q := point{
+				x: p0.x + (p1.x-p0.x)*t/l,
+				y: p0.y + (p1.y-p0.y)*t/l,
+			}
+			if on {
+				dashes = append(dashes, append(dash, q))
+				dash = nil
+			} else {
+				dash = []point{q}
+			}
+			on = !on
+			idx = (idx + 1) % len(pattern)
+			remain = pattern[idx]
+		}
+		remain -= l - t
+		if on {
+			dash = append(dash, p1)
+		}
+	}
+	if on && len(dash) > 0 {
+		dashes = append(dashes, dash)
+	}
+	return dashes
+}
+
+type stroker struct {
+	vertices   []ebiten.Vertex
+	indices    []uint16
+	halfWidth  float32
+	lineJoin   LineJoin
+	miterLimit float32
+	lineCap    LineCap
+}
+
+func (s *stroker) appendVertex(p point) uint16 {
+	s.vertices = append(s.vertices, ebiten.Vertex{
+		DstX:   p.x,
+		DstY:   p.y,
+		SrcX:   0,
+		SrcY:   0,
+		ColorR: 1,
+		ColorG: 1,
+		ColorB: 1,
+		ColorA: 1,
+	})
+	return uint16(len(s.vertices) - 1)
+}
+
+// appendQuad appends a quadrilateral whose edges are p0-p1, p1-p3, p3-p2 and p2-p0.
+func (s *stroker) appendQuad(p0, p1, p2, p3 point) {
+	i0 := s.appendVertex(p0)
+	i1 := s.appendVertex(p1)
+	i2 := s.appendVertex(p2)
+	i3 := s.appendVertex(p3)
+	s.indices = append(s.indices, i0, i1, i2, i1, i2, i3)
+}
+
+// appendFan appends a triangle fan around center.
+func (s *stroker) appendFan(center point, pts []point) {
+	c := s.appendVertex(center)
+	base := uint16(len(s.vertices))
+	for i, p := range pts {
+		s.appendVertex(p)
+		if i == 0 {
+			continue
+		}
+		s.indices = append(s.indices, c, base+uint16(i-1), base+uint16(i))
+	}
+}
+
+// appendArc appends a sector with the stroke's half width as the radius.
+// The sector starts at the direction (dx, dy) and sweeps the angle delta in radians.
+func (s *stroker) appendArc(center point, dx, dy float32, delta float64) {
+	r := float64(s.halfWidth)
+
+	// Choose the step so that the error between the arc and the chords is at most 0.25 pixels.
+	step := math.Pi / 2
+	if r > 0.25 {
+		step = math.Min(step, 2*math.Acos(1-0.25/r))
+	}
+	n := int(math.Ceil(math.Abs(delta) / step))
+	if n < 1 {
+		n = 1
+	}
+
+	a0 := math.Atan2(float64(dy), float64(dx))
+	pts := make([]point, 0, n+1)
+	for i := 0; i <= n; i++ {
+		sin, cos := math.Sincos(a0 + delta*float64(i)/float64(n))
+		pts = append(pts, point{
+			x: center.x + float32(r*cos),
+			y: center.y + float32(r*sin),
+		})
+	}
+	s.appendFan(center, pts)
+}
+
+func (s *stroker) strokePolyline(pts []point, closed bool) {
+	// Remove duplicated points, which don't have directions.
+	ps := make([]point, 0, len(pts))
+	for _, p := range pts {
+		if len(ps) > 0 && ps[len(ps)-1] == p {
+			continue
+		}
+		ps = append(ps, p)
+	}
+	if closed && len(ps) > 1 && ps[0] == ps[len(ps)-1] {
+		ps = ps[:len(ps)-1]
+	}
+
+	if len(ps) == 1 {
+		// A zero-length subpath is rendered only with its caps, pointing to the right.
+		if closed {
+			return
+		}
+		s.appendCap(ps[0], 1, 0)
+		s.appendCap(ps[0], -1, 0)
+		return
+	}
+	if len(ps) < 2 {
+		return
+	}
+
+	n := len(ps) - 1
+	if closed {
+		n = len(ps)
+	}
+	dirs := make([]point, n)
+	for i := range dirs {
+		p0, p1 := ps[i], ps[(i+1)%len(ps)]
+		dx, dy := normalize(p1.x-p0.x, p1.y-p0.y)
+		dirs[i] = point{x: dx, y: dy}
+
+		nx, ny := -dy*s.halfWidth, dx*s.halfWidth
+		s.appendQuad(
+			point{x: p0.x + nx, y: p0.y + ny},
+			point{x: p0.x - nx, y: p0.y - ny},
+			point{x: p1.x + nx, y: p1.y + ny},
+			point{x: p1.x - nx, y: p1.y - ny})
+	}
+
+	for i := 1; i < n; i++ {
+		s.appendJoin(ps[i], dirs[i-1], dirs[i])
+	}
+	if closed {
+		s.appendJoin(ps[0], dirs[n-1], dirs[0])
+		return
+	}
+
+	s.appendCap(ps[0], -dirs[0].x, -dirs[0].y)
+	s.appendCap(ps[len(ps)-1], dirs[n-1].x, dirs[n-1].y)
+}
+
+// appendJoin appends a join at p between a segment in the direction d0 and a following segment in the direction d1.
+func (s *stroker) appendJoin(p point, d0, d1 point) {
+	c := cross(d0.x, d0.y, d1.x, d1.y)
+	dot := d0.x*d1.x + d0.y*d1.y
+	if c == 0 && dot > 0 {
+		return
+	}
+
+	// The join is on the outer side of the corner.
+	sign := float32(1)
+	if c > 0 {
+		sign = -1
+	}
+	n0 := point{x: -d0.y * sign, y: d0.x * sign}
+	n1 := point{x: -d1.y * sign, y: d1.x * sign}
+	hw := s.halfWidth
+	a := point{x: p.x + n0.x*hw, y: p.y + n0.y*hw}
+	b := point{x: p.x + n1.x*hw, y: p.y + n1.y*hw}
+
+	switch s.lineJoin {
+	case LineJoinMiter:
+		if c == 0 {
+			break
+		}
+		mx, my := normalize(n0.x+n1.x, n0.y+n1.y)
+		// cos is the cosine of the half angle between the normals.
+		cos := mx*n0.x + my*n0.y
+		if cos <= 0 || 1/cos > s.miterLimit {
+			break
+		}
+		m := point{x: p.x + mx*hw/cos, y: p.y + my*hw/cos}
+		s.appendFan(p, []point{a, m, b})
+		return
+	case LineJoinRound:
+		delta := math.Atan2(float64(cross(n0.x, n0.y, n1.x, n1.y)), float64(n0.x*n1.x+n0.y*n1.y))
+		if c == 0 {
+			// The path turns back. Go around the end in the direction d0.
+			delta = -math.Pi
+		}
+		s.appendArc(p, n0.x, n0.y, delta)
+		return
+	}
+
+	s.appendFan(p, []point{a, b})
+}
+
+// appendCap appends a cap at p, the end of a stroke in the direction (dx, dy).
+func (s *stroker) appendCap(p point, dx, dy float32) {
+	hw := s.halfWidth
+	nx, ny := -dy*hw, dx*hw
+	switch s.lineCap {
+	case LineCapRound:
+		s.appendArc(p, nx, ny, -math.Pi)
+	case LineCapSquare:
+		s.appendQuad(
+			point{x: p.x + nx, y: p.y + ny},
+			point{x: p.x - nx, y: p.y - ny},
+			point{x: p.x + nx + dx*hw, y: p.y + ny + dy*hw},
+			point{x: p.x - nx + dx*hw, y: p.y - ny + dy*hw})
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+type rect struct {
+	minX, minY, maxX, maxY float32
+}
+
+func bounds(vs []ebiten.Vertex) rect {
+	r := rect{
+		minX: float32(math.Inf(1)),
+		minY: float32(math.Inf(1)),
+		maxX: float32(math.Inf(-1)),
+		maxY: float32(math.Inf(-1)),
+	}
+	for _, v := range vs {
+		r.minX = float32(math.Min(float64(r.minX), float64(v.DstX)))
+		r.minY = float32(math.Min(float64(r.minY), float64(v.DstY)))
+		r.maxX = float32(math.Max(float64(r.maxX), float64(v.DstX)))
+		r.maxY = float32(math.Max(float64(r.maxY), float64(v.DstY)))
+	}
+	return r
+}
+
+// area returns the total area of the triangles.
+func area(vs []ebiten.Vertex, is []uint16) float32 {
+	var a float32
+	for i := 0; i < len(is); i += 3 {
+		v0, v1, v2 := vs[is[i]], vs[is[i+1]], vs[is[i+2]]
+		a += float32(math.Abs(float64((v1.DstX-v0.DstX)*(v2.DstY-v0.DstY)-(v2.DstX-v0.DstX)*(v1.DstY-v0.DstY)))) / 2
+	}
+	return a
+}
+
+func near(a, b float32) bool {
+	return nearWithin(a, b, 1e-3)
+}
+
+func nearWithin(a, b float32, tolerance float32) bool {
+	return math.Abs(float64(a-b)) <= float64(tolerance)
+}
+
+func TestStrokeCaps(t *testing.T) {
+	cases := []struct {
+		Cap       vector.LineCap
+		Want      rect
+		Tolerance float32
+	}{
+		{
+			Cap:       vector.LineCapButt,
+			Want:      rect{0, -1, 10, 1},
+			Tolerance: 1e-3,
+		},
+		{
+			Cap:       vector.LineCapSquare,
+			Want:      rect{-1, -1, 11, 1},
+			Tolerance: 1e-3,
+		},
+		{
+			// A round cap is approximated with chords within 0.25 pixels.
+			Cap:       vector.LineCapRound,
+			Want:      rect{-1, -1, 11, 1},
+			Tolerance: 0.25,
+		},
+	}
+	for _, c := range cases {
+		var path vector.Path
+		path.MoveTo(0, 0)
+		path.LineTo(10, 0)
+		vs, is := path.AppendVerticesAndIndicesForStroke(nil, nil, &vector.StrokeOptions{
+			Width:   2,
+			LineCap: c.Cap,
+		})
+		got := bounds(vs)
+		if !nearWithin(got.minX, c.Want.minX, c.Tolerance) || !nearWithin(got.minY, c.Want.minY, c.Tolerance) ||
+			!nearWithin(got.maxX, c.Want.maxX, c.Tolerance) || !nearWithin(got.maxY, c.Want.maxY, c.Tolerance) {
+			t.Errorf("cap %d: bounds: got: %v, want: %v", c.Cap, got, c.Want)
+		}
+		if c.Cap == vector.LineCapButt {
+			if got, want := area(vs, is), float32(20); !near(got, want) {
+				t.Errorf("cap %d: area: got: %v, want: %v", c.Cap, got, want)
+			}
+		}
+	}
+}
+
+func TestStrokeJoins(t *testing.T) {
+	cases := []struct {
+		Join       vector.LineJoin
+		MiterLimit float32
+		WantMiter  bool
+	}{
+		{
+			Join:      vector.LineJoinMiter,
+			WantMiter: true,
+		},
+		{
+			// The miter ratio of a right angle is √2.
+			Join:       vector.LineJoinMiter,
+			MiterLimit: 1.4,
+			WantMiter:  false,
+		},
+		{
+			Join:      vector.LineJoinBevel,
+			WantMiter: false,
+		},
+		{
+			Join:      vector.LineJoinRound,
+			WantMiter: false,
+		},
+	}
+	for _, c := range cases {
+		var path vector.Path
+		path.MoveTo(0, 0)
+		path.LineTo(10, 0)
+		path.LineTo(10, 10)
+		vs, _ := path.AppendVerticesAndIndicesForStroke(nil, nil, &vector.StrokeOptions{
+			Width:      2,
+			LineJoin:   c.Join,
+			MiterLimit: c.MiterLimit,
+		})
+		var miter bool
+		for _, v := range vs {
+			if near(v.DstX, 11) && near(v.DstY, -1) {
+				miter = true
+			}
+		}
+		if miter != c.WantMiter {
+			t.Errorf("join %d, miter limit %v: miter: got: %v, want: %v", c.Join, c.MiterLimit, miter, c.WantMiter)
+		}
+		if got, want := bounds(vs), (rect{0, -1, 11, 10}); !near(got.minX, want.minX) || !near(got.minY, want.minY) || !near(got.maxX, want.maxX) || !near(got.maxY, want.maxY) {
+			t.Errorf("join %d, miter limit %v: bounds: got: %v, want: %v", c.Join, c.MiterLimit, got, want)
+		}
+	}
+}
+
+func TestStrokeClose(t *testing.T) {
+	var path vector.Path
+	path.MoveTo(0, 0)
+	path.LineTo(10, 0)
+	path.LineTo(10, 10)
+	path.LineTo(0, 10)
+	path.Close()
+	vs, is := path.AppendVerticesAndIndicesForStroke(nil, nil, &vector.StrokeOptions{
+		Width:    2,
+		LineJoin: vector.LineJoinBevel,
+	})
+	// Four sides and four bevel joins.
+	if got, want := len(is), 4*6+4*3; got != want {
+		t.Errorf("len(indices): got: %d, want: %d", got, want)
+	}
+	if got, want := area(vs, is), float32(4*20+4*0.5); !near(got, want) {
+		t.Errorf("area: got: %v, want: %v", got, want)
+	}
+}
+
+func TestStrokeDash(t *testing.T) {
+	var path vector.Path
+	path.MoveTo(0, 0)
+	path.LineTo(10, 0)
+	vs, is := path.AppendVerticesAndIndicesForStroke(nil, nil, &vector.StrokeOptions{
+		Width:       2,
+		DashPattern: []float32{2, 3},
+		DashOffset:  1,
+	})
+	// The dashes are [0, 1], [4, 6] and [9, 10].
+	if got, want := len(is), 3*6; got != want {
+		t.Errorf("len(indices): got: %d, want: %d", got, want)
+	}
+	if got, want := area(vs, is), float32(2*(1+2+1)); !near(got, want) {
+		t.Errorf("area: got: %v, want: %v", got, want)
+	}
+}
@@ -0,0 +1,340 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ParseSVGPathData parses SVG path data, which is the value of the d attribute of an SVG path element,
+// and returns the path.
+//
+// All the commands in SVG 1.1 are supported: M, L, H, V, C, S, Q, T, A, Z and their relative versions.
+// Elliptical arcs are approximated with cubic Bézier curves.
+//
+// See https://www.w3.org/TR/SVG11/paths.html#PathData for the syntax.
+func ParseSVGPathData(d string) (*Path, error) {
+	s := svgPathScanner{src: d}
+	var p Path
+
+	var cmd byte
+	// (cx, cy) is the last control point for S and T commands.
+	var cx, cy float32
+	var prev byte
+
+	for {
+		s.skipSpaces()
+		if s.eof() {
+			break
+		}
+
+		if c := s.peek(); isSVGPathCommand(c) {
+			cmd = c
+			s.pos++
+		} else if cmd == 0 || cmd == 'Z' || cmd == 'z' {
+			return nil, fmt.Errorf("vector: a command is expected at %d in the path data", s.pos)
+		}
+
+		x, y := p.cur.x, p.cur.y
+		rel := cmd >= 'a'
+		var ox, oy float32
+		if rel {
+			ox, oy = x, y
+		}
+
+		switch cmd {
+		case 'M', 'm':
+			v, err := s.numbers(2)
+			if err != nil {
+				return nil, err
+			}
+			p.MoveTo(ox+v[0], oy+v[1])
+			// The following coordinate pairs are treated as implicit lineto commands.
+			if rel {
+				cmd = 'l'
+			} else {
+				cmd = 'L'
+			}
+		case 'L', 'l':
+			v, err := s.numbers(2)
+			if err != nil {
+				return nil, err
+			}
+			p.LineTo(ox+v[0], oy+v[1])
+		case 'H', 'h':
+			v, err := s.numbers(1)
+			if err != nil {
+				return nil, err
+			}
+			p.LineTo(ox+v[0], y)
+		case 'V', 'v':
+			v, err := s.numbers(1)
+			if err != nil {
+				return nil, err
+			}
+			p.LineTo(x, oy+v[0])
+		case 'C', 'c':
+			v, err := s.numbers(6)
+			if err != nil {
+				return nil, err
+			}
+			p.CubicTo(ox+v[0], oy+v[1], ox+v[2], oy+v[3], ox+v[4], oy+v[5])
+			cx, cy = ox+v[2], oy+v[3]
+		case 'S', 's':
+			v, err := s.numbers(4)
+			if err != nil {
+				return nil, err
+			}
+			// The first control point is the reflection of the previous command's second control point.
+			x1, y1 := x, y
+			if prev == 'C' || prev == 'c' || prev == 'S' || prev == 's' {
+				x1, y1 = 2*x-cx, 2*y-cy
+			}
+			p.CubicTo(x1, y1, ox+v[0], oy+v[1], ox+v[2], oy+v[3])
+			cx, cy = ox+v[0], oy+v[1]
+		case 'Q', 'q':
+			v, err := s.numbers(4)
+			if err != nil {
+				return nil, err
+			}
+			p.QuadTo(ox+v[0], oy+v[1], ox+v[2], oy+v[3])
+			cx, cy = ox+v[0], oy+v[1]
+		case 'T', 't':
+			v, err := s.numbers(2)
+			if err != nil {
+				return nil, err
+			}
+			// The control point is the reflection of the previous command's control point.
+			x1, y1 := x, y
+			if prev == 'Q' || prev == 'q' || prev == 'T' || prev == 't' {
+				x1, y1 = 2*x-cx, 2*y-cy
+			}
+			p.QuadTo(x1, y1, ox+v[0], oy+v[1])
+			cx, cy = x1, y1
+		case 'A', 'a':
+			v, err := s.numbers(3)
+			if err != nil {
+				return nil, err
+			}
+			largeArc, err := s.flag()
+			if err != nil {
+				return nil, err
+			}
+			sweep, err := s.flag()
+			if err != nil {
+				return nil, err
+			}
+			v2, err := s.numbers(2)
+			if err != nil {
+				return nil, err
+			}
+			p.svgArc(float64(v[0]), float64(v[1]), float64(v[2])*math.Pi/180, largeArc, sweep, float64(ox+v2[0]), float64(oy+v2[1]))
+		case 'Z', 'z':
+			p.Close()
+		}
+		prev = cmd
+	}
+	return &p, nil
+}
+
+// svgArc adds an elliptical arc from the current position to (x2, y2) in the way of the SVG's A command.
+// phi is the rotation of the ellipse in radians.
+//
+// See https://www.w3.org/TR/SVG11/implnote.html#ArcImplementationNotes.
+func (p *Path) svgArc(rx, ry, phi float64, largeArc, sweep bool, x2, y2 float64) {
+	x1, y1 := float64(p.cur.x), float64(p.cur.y)
+	if x1 == x2 && y1 == y2 {
+		return
+	}
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		p.LineTo(float32(x2), float32(y2))
+		return
+	}
+
+	sinPhi, cosPhi := math.Sincos(phi)
+	dx, dy := (x1-x2)/2, (y1-y2)/2
+	x1p := cosPhi*dx + sinPhi*dy
+	y1p := -sinPhi*dx + cosPhi*dy
+
+	// Scale up the radii if there is no ellipse passing through the both points.
+	if l := x1p*x1p/(rx*rx) + y1p*y1p/(ry*ry); l > 1 {
+		rx *= math.Sqrt(l)
+		ry *= math.Sqrt(l)
+	}
+
+	num := rx*rx*ry*ry - rx*rx*y1p*y1p - ry*ry*x1p*x1p
+	den := rx*rx*y1p*y1p + ry*ry*x1p*x1p
+	coef := math.Sqrt(math.Max(0, num/den))
+	if largeArc == sweep {
+		coef = -coef
+	}
+	cxp := coef * rx * y1p / ry
+	cyp := -coef * ry * x1p / rx
+	cx := cosPhi*cxp - sinPhi*cyp + (x1+x2)/2
+	cy := sinPhi*cxp + cosPhi*cyp + (y1+y2)/2
+
+	angle := func(ux, uy, vx, vy float64) float64 {
+		return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+	}
+	theta := angle(1, 0, (x1p-cxp)/rx, (y1p-cyp)/ry)
+	dtheta := angle((x1p-cxp)/rx, (y1p-cyp)/ry, (-x1p-cxp)/rx, (-y1p-cyp)/ry)
+	if !sweep && dtheta > 0 {
+		dtheta -= 2 * math.Pi
+	}
+	if sweep && dtheta < 0 {
+		dtheta += 2 * math.Pi
+	}
+
+	// Approximate the arc with cubic Bézier curves, each of which is at most a quarter of the ellipse.
+	n := int(math.Ceil(math.Abs(dtheta) / (math.Pi / 2)))
+	da := dtheta / float64(n)
+	t := 4.0 / 3.0 * math.Tan(da/4)
+	ellipse := func(a float64) (x, y, dx, dy float64) {
+		sin, cos := math.Sincos(a)
+		x = cx + rx*cos*cosPhi - ry*sin*sinPhi
+		y = cy + rx*cos*sinPhi + ry*sin*cosPhi
+		dx = -rx*sin*cosPhi - ry*cos*sinPhi
+		dy = -rx*sin*sinPhi + ry*cos*cosPhi
+		return
+	}
+	for i := 0; i < n; i++ {
+		a0 := theta + da*float64(i)
+		a1 := a0 + da
+		ex0, ey0, edx0, edy0 := ellipse(a0)
+		ex1, ey1, edx1, edy1 := ellipse(a1)
+		if i == n-1 {
+			ex1, ey1 = x2, y2
+		}
+		p.CubicTo(
+			float32(ex0+t*edx0), float32(ey0+t*edy0),
+			float32(ex1-t*edx1), float32(ey1-t*edy1),
+			float32(ex1), float32(ey1))
+	}
+}
+
+func isSVGPathCommand(c byte) bool {
+	switch c {
+	case 'M', 'm', 'L', 'l', 'H', 'h', 'V', 'v', 'C', 'c', 'S', 's', 'Q', 'q', 'T', 't', 'A', 'a', 'Z', 'z':
+		return true
+	}
+	return false
+}
+
+type svgPathScanner struct {
+	src string
+	pos int
+}
+
+func (s *svgPathScanner) eof() bool {
+	return s.pos >= len(s.src)
+}
+
+func (s *svgPathScanner) peek() byte {
+	return s.src[s.pos]
+}
+
+func (s *svgPathScanner) skipSpaces() {
+	for !s.eof() {
+		switch s.peek() {
+		case ' ', '\t', '\n', '\r', '\f':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+// skipSeparator skips spaces and at most one comma.
+func (s *svgPathScanner) skipSeparator() {
+	s.skipSpaces()
+	if !s.eof() && s.peek() == ',' {
+		s.pos++
+		s.skipSpaces()
+	}
+}
+
+// number scans a number like 1, -2.5, .5 or 1e-3.
+// A number ends where a character cannot continue it, so "1.5.5" is 1.5 and .5, and "1-2" is 1 and -2.
+func (s *svgPathScanner) number() (float32, error) {
+	s.skipSeparator()
+	start := s.pos
+	if !s.eof() && (s.peek() == '+' || s.peek() == '-') {
+		s.pos++
+	}
+	digits := s.digits()
+	if !s.eof() && s.peek() == '.' {
+		s.pos++
+		digits += s.digits()
+	}
+	if digits == 0 {
+		return 0, fmt.Errorf("vector: a number is expected at %d in the path data", start)
+	}
+	if !s.eof() && (s.peek() == 'e' || s.peek() == 'E') {
+		// An exponent must have digits. Otherwise, e is not a part of the number.
+		pos := s.pos
+		s.pos++
+		if !s.eof() && (s.peek() == '+' || s.peek() == '-') {
+			s.pos++
+		}
+		if s.digits() == 0 {
+			s.pos = pos
+		}
+	}
+	v, err := strconv.ParseFloat(s.src[start:s.pos], 32)
+	if err != nil {
+		return 0, fmt.Errorf("vector: invalid number %q at %d in the path data", s.src[start:s.pos], start)
+	}
+	return float32(v), nil
+}
+
+func (s *svgPathScanner) digits() int {
+	n := 0
+	for !s.eof() && '0' <= s.peek() && s.peek() <= '9' {
+		s.pos++
+		n++
+	}
+	return n
+}
+
+func (s *svgPathScanner) numbers(n int) ([]float32, error) {
+	vs := make([]float32, n)
+	for i := range vs {
+		v, err := s.number()
+		if err != nil {
+			return nil, err
+		}
+		vs[i] = v
+	}
+	return vs, nil
+}
+
+// flag scans a flag of an arc, which is a single character 0 or 1 and doesn't need a following separator.
+func (s *svgPathScanner) flag() (bool, error) {
+	s.skipSeparator()
+	if !s.eof() {
+		switch s.peek() {
+		case '0':
+			s.pos++
+			return false, nil
+		case '1':
+			s.pos++
+			return true, nil
+		}
+	}
+	return false, fmt.Errorf("vector: a flag is expected at %d in the path data", s.pos)
+}
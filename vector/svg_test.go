@@ -0,0 +1,127 @@
+// Copyright 2022 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+func TestParseSVGPathData(t *testing.T) {
+	cases := []struct {
+		Name      string
+		Data      string
+		Want      rect
+		Tolerance float32
+	}{
+		{
+			Name:      "absolute",
+			Data:      "M1 2 L11 2 L11 12 L1 12 Z",
+			Want:      rect{1, 2, 11, 12},
+			Tolerance: 1e-3,
+		},
+		{
+			Name:      "relative",
+			Data:      "m1,2 h10 v10 h-10 z",
+			Want:      rect{1, 2, 11, 12},
+			Tolerance: 1e-3,
+		},
+		{
+			Name:      "implicit lineto",
+			Data:      "M1 2 11 2 11 12",
+			Want:      rect{1, 2, 11, 12},
+			Tolerance: 1e-3,
+		},
+		{
+			Name:      "compact numbers",
+			Data:      "M-1-2L.5.5 1e1,0",
+			Want:      rect{-1, -2, 10, 0.5},
+			Tolerance: 1e-3,
+		},
+		{
+			Name:      "after close",
+			Data:      "M0 0 L4 0 L4 4 Z L0 -4 L-4 -4",
+			Want:      rect{-4, -4, 4, 4},
+			Tolerance: 1e-3,
+		},
+		{
+			Name:      "cubic",
+			Data:      "M0 0 C0 -8 8 -8 8 0 S16 8 16 0",
+			Want:      rect{0, -6, 16, 6},
+			Tolerance: 0.5,
+		},
+		{
+			Name:      "quadratic",
+			Data:      "M0 0 Q4 -8 8 0 T16 0",
+			Want:      rect{0, -4, 16, 4},
+			Tolerance: 0.5,
+		},
+		{
+			Name:      "arc",
+			Data:      "M0 0 A5 5 0 0 1 10 0",
+			Want:      rect{0, -5, 10, 0},
+			Tolerance: 0.5,
+		},
+		{
+			Name:      "large arc",
+			Data:      "M0 0 a5,5 0 1,0 8,0",
+			Want:      rect{-1, 0, 9, 8},
+			Tolerance: 0.5,
+		},
+		{
+			Name:      "rotated arc",
+			Data:      "M0 0 A10 5 90 0 1 0 20",
+			Want:      rect{0, 0, 5, 20},
+			Tolerance: 0.5,
+		},
+		{
+			Name:      "compact flags",
+			Data:      "M0 0a5 5 0 0110 0",
+			Want:      rect{0, -5, 10, 0},
+			Tolerance: 0.5,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			path, err := vector.ParseSVGPathData(c.Data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vs, _ := path.AppendVerticesAndIndicesForFilling(nil, nil)
+			got := bounds(vs)
+			if !nearWithin(got.minX, c.Want.minX, c.Tolerance) || !nearWithin(got.minY, c.Want.minY, c.Tolerance) ||
+				!nearWithin(got.maxX, c.Want.maxX, c.Tolerance) || !nearWithin(got.maxY, c.Want.maxY, c.Tolerance) {
+				t.Errorf("bounds: got: %v, want: %v", got, c.Want)
+			}
+		})
+	}
+}
+
+func TestParseSVGPathDataError(t *testing.T) {
+	for _, d := range []string{
+		"1 2",
+		"M1",
+		"M1 2 L",
+		"M1 2 Z 3 4",
+		"M1 2 A5 5 0 2 0 3 4",
+		"M1 2 X3 4",
+		"M1e 2",
+	} {
+		if _, err := vector.ParseSVGPathData(d); err == nil {
+			t.Errorf("ParseSVGPathData(%q) must return an error", d)
+		}
+	}
+}
@@ -125,15 +125,32 @@ func SetWindowTitle(title string) {
 //     The selected images will be rescaled as needed.
 //     Good sizes include 16x16, 32x32 and 48x48.
 //
-// As macOS windows don't have icons, SetWindowIcon doesn't work on macOS.
+// On Windows and Linux, the taskbar icon is also updated as the window icon.
+// As macOS windows don't have icons, SetWindowIcon sets the Dock icon with the largest image instead.
+//
+// iconImages can include *ebiten.Image. The pixels of *ebiten.Image are read from GPU asynchronously, and the icon
+// is updated a few frames later. The icon reflects the images at the time SetWindowIcon is called.
+//
+// SetWindowIcon can be called anytime, before or after RunGame.
 //
 // SetWindowIcon doesn't work on browsers or mobiles.
 //
 // SetWindowIcon is concurrent-safe.
 func SetWindowIcon(iconImages []image.Image) {
-	ui.Get().Window().SetIcon(iconImages)
+	gen := atomic.AddUint64(&windowIconGeneration, 1)
+
+	readImagesAsync(iconImages, func(imgs []image.Image) {
+		// Skip this if SetWindowIcon is called again in the meantime.
+		if atomic.LoadUint64(&windowIconGeneration) != gen {
+			return
+		}
+		ui.Get().Window().SetIcon(imgs)
+	})
 }
 
+// windowIconGeneration is incremented every time SetWindowIcon is called.
+var windowIconGeneration uint64
+
 // WindowPosition returns the window position.
 // The origin position is the left-upper corner of the current monitor.
 // The unit is device-independent pixels.
@@ -176,6 +193,35 @@ func initializeWindowPositionIfNeeded(width, height int) {
 	}
 }
 
+// WindowMonitor returns the monitor the window is on.
+//
+// WindowMonitor returns nil on browsers and mobiles.
+//
+// WindowMonitor must be called on the main thread before RunGame, and is concurrent-safe after RunGame.
+func WindowMonitor() *Monitor {
+	return (*Monitor)(ui.Get().Window().Monitor())
+}
+
+// SetWindowMonitor moves the window to the given monitor.
+// The window is put at the same relative position as the initial window position.
+//
+// In fullscreen mode, SetWindowMonitor makes the window fullscreen on the given monitor.
+//
+// Before RunGame, SetWindowMonitor specifies the monitor where the window is created.
+// SetWindowMonitor overrides the initial monitor that is selected by the cursor position.
+//
+// SetWindowMonitor panics if monitor is nil.
+//
+// SetWindowMonitor does nothing on browsers and mobiles.
+//
+// SetWindowMonitor is concurrent-safe.
+func SetWindowMonitor(monitor *Monitor) {
+	if monitor == nil {
+		panic("ebiten: monitor must not be nil at SetWindowMonitor")
+	}
+	ui.Get().Window().SetMonitor((*ui.Monitor)(monitor))
+}
+
 // WindowSize returns the window size on desktops.
 // WindowSize returns (0, 0) on other environments.
 //
@@ -217,6 +263,29 @@ func SetWindowSizeLimits(minw, minh, maxw, maxh int) {
 	ui.Get().Window().SetSizeLimits(minw, minh, maxw, maxh)
 }
 
+// WindowAspectRatioLocked returns the locked aspect ratio of the window on desktops.
+// WindowAspectRatioLocked returns (0, 0) when the aspect ratio is not locked.
+//
+// WindowAspectRatioLocked is concurrent-safe.
+func WindowAspectRatioLocked() (width, height int) {
+	return ui.Get().Window().AspectRatioLocked()
+}
+
+// SetWindowAspectRatioLocked locks the aspect ratio of the window to width:height on desktops.
+// While the aspect ratio is locked, the window keeps the aspect ratio when the user resizes the window.
+// If width or height is 0 or negative, the aspect ratio is unlocked.
+//
+// The aspect ratio is applied only when the window is resized by the user.
+// SetWindowSize, maximizing the window, and the fullscreen mode are not affected.
+// If the aspect ratio conflicts with the window size limits, the result is undefined.
+//
+// SetWindowAspectRatioLocked does nothing on browsers or mobiles.
+//
+// SetWindowAspectRatioLocked is concurrent-safe.
+func SetWindowAspectRatioLocked(width, height int) {
+	ui.Get().Window().SetAspectRatioLocked(width, height)
+}
+
 // IsWindowFloating reports whether the window is always shown above all the other windows.
 //
 // IsWindowFloating returns false on browsers and mobiles.
@@ -291,6 +360,85 @@ func RestoreWindow() {
 	ui.Get().Window().Restore()
 }
 
+// TaskbarProgressStateType represents a state of the progress shown on the taskbar button or the Dock icon.
+type TaskbarProgressStateType = ui.TaskbarProgressState
+
+// TaskbarProgressStateTypes
+const (
+	// TaskbarProgressStateNone indicates that no progress is shown.
+	TaskbarProgressStateNone TaskbarProgressStateType = TaskbarProgressStateType(ui.TaskbarProgressStateNone)
+
+	// TaskbarProgressStateIndeterminate indicates that the progress is being made but its amount is unknown.
+	TaskbarProgressStateIndeterminate TaskbarProgressStateType = TaskbarProgressStateType(ui.TaskbarProgressStateIndeterminate)
+
+	// TaskbarProgressStateNormal indicates that the progress is being made normally.
+	TaskbarProgressStateNormal TaskbarProgressStateType = TaskbarProgressStateType(ui.TaskbarProgressStateNormal)
+
+	// TaskbarProgressStateError indicates that an error happened.
+	TaskbarProgressStateError TaskbarProgressStateType = TaskbarProgressStateType(ui.TaskbarProgressStateError)
+
+	// TaskbarProgressStatePaused indicates that the progress is paused.
+	TaskbarProgressStatePaused TaskbarProgressStateType = TaskbarProgressStateType(ui.TaskbarProgressStatePaused)
+)
+
+// SetWindowTaskbarProgress shows the progress on the taskbar button of the window.
+// progress is in [0, 1], and is used only for TaskbarProgressStateNormal, TaskbarProgressStateError and
+// TaskbarProgressStatePaused.
+//
+// On Windows, the progress is shown on the taskbar button with ITaskbarList3.
+// On macOS, the progress is shown as a badge of the Dock icon, like "42%".
+// SetWindowTaskbarProgress does nothing on the other desktops, browsers or mobiles.
+//
+// If the main loop does not start yet, SetWindowTaskbarProgress does nothing.
+//
+// SetWindowTaskbarProgress is concurrent-safe.
+func SetWindowTaskbarProgress(state TaskbarProgressStateType, progress float64) {
+	ui.Get().Window().SetTaskbarProgress(state, progress)
+}
+
+// RequestWindowAttention requests the user's attention to the window, e.g. when it's the user's turn.
+//
+// On Windows, the taskbar button flashes. On macOS, the Dock icon bounces. On Linux and BSDs, the urgency hint is
+// set to the window. If the window is already focused, the behavior depends on the platform.
+//
+// If the main loop does not start yet, RequestWindowAttention does nothing.
+//
+// RequestWindowAttention does nothing on browsers or mobiles.
+//
+// RequestWindowAttention is concurrent-safe.
+func RequestWindowAttention() {
+	ui.Get().Window().RequestAttention()
+}
+
+// WindowResizeHandler is an optional interface for Game to be notified when the window size is changed.
+//
+// OnWindowResized is called with the new window size in device-independent pixels, which is the same as WindowSize.
+// OnWindowResized is called whether the window is resized by the user, by maximizing or restoring the window, or
+// by SetWindowSize. OnWindowResized is not called for the initial window size.
+//
+// OnWindowResized is never called on browsers or mobiles.
+//
+// OnWindowResized is never called concurrently with Game's Update and Draw.
+type WindowResizeHandler interface {
+	// OnWindowResized is called when the window size is changed.
+	OnWindowResized(width, height int)
+}
+
+// WindowMaximizeHandler is an optional interface for Game to be notified when the window is maximized or
+// restored from being maximized.
+//
+// The maximized state is the same as IsWindowMaximized.
+// When the window is maximized or restored, OnWindowMaximizedChanged is called before
+// WindowResizeHandler's OnWindowResized.
+//
+// OnWindowMaximizedChanged is never called on browsers or mobiles.
+//
+// OnWindowMaximizedChanged is never called concurrently with Game's Update and Draw.
+type WindowMaximizeHandler interface {
+	// OnWindowMaximizedChanged is called when the window is maximized or restored.
+	OnWindowMaximizedChanged(maximized bool)
+}
+
 // IsWindowBeingClosed returns true when the user is trying to close the window on desktops.
 // As the window is closed immediately by default,
 // you might want to call SetWindowClosingHandled(true) to prevent the window is automatically closed.